@@ -2,9 +2,15 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
+	"fmt"
+	"io"
+	"math/rand"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/audiobookshelf"
@@ -12,6 +18,8 @@ import (
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/sync"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/sync/state"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/util"
 )
 
 // boolFlag is a custom flag type that tracks if a boolean flag was explicitly set
@@ -49,8 +57,14 @@ type configFlags struct {
 	hardcoverToken      string        // Hardcover API token
 	syncInterval        time.Duration // Sync interval duration
 	dryRun              *boolFlag     // Enable dry-run mode
+	ownershipOnly       *boolFlag     // Only mark matched books as owned
 	testBookFilter      string        // Filter books by title/author (case-insensitive)
 	testBookLimit       int           // Limit number of books to process
+	limitLibrary        string        // Restrict a one-time sync to a single library (name or ID)
+	benchmark           int           // Benchmark matching against a random sample of this many items
+	syncItem            string        // Sync only this library item and exit
+	dumpState           bool          // Print the sync state file and exit
+	listBlocked         bool          // Print the blocked items and exit
 	help                *boolFlag     // Show help
 	version             *boolFlag     // Show version
 	oneTimeSync         *boolFlag     // Run sync once and exit
@@ -60,28 +74,35 @@ type configFlags struct {
 // parseFlags parses command-line flags and returns the configuration
 func parseFlags() *configFlags {
 	cfg := configFlags{
-		dryRun:      &boolFlag{value: false, set: false},
-		help:        &boolFlag{value: false, set: false},
-		version:     &boolFlag{value: false, set: false},
-		oneTimeSync: &boolFlag{value: false, set: false},
-		serverOnly:  &boolFlag{value: false, set: false},
+		dryRun:        &boolFlag{value: false, set: false},
+		ownershipOnly: &boolFlag{value: false, set: false},
+		help:          &boolFlag{value: false, set: false},
+		version:       &boolFlag{value: false, set: false},
+		oneTimeSync:   &boolFlag{value: false, set: false},
+		serverOnly:    &boolFlag{value: false, set: false},
 	}
 
 	// Define flags with our custom boolFlag type
 	flag.Var(cfg.dryRun, "dry-run", "Run in dry-run mode (no changes will be made)")
+	flag.Var(cfg.ownershipOnly, "ownership-only", "Only mark matched books as owned, without syncing reads or statuses")
 	flag.Var(cfg.help, "help", "Show help")
 	flag.Var(cfg.version, "version", "Show version")
 	flag.Var(cfg.oneTimeSync, "once", "Run sync once and exit")
 	flag.Var(cfg.serverOnly, "server-only", "Only run the HTTP server, don't start sync service")
 
 	// String flags need to be pointers to detect if they were set
-	configFile := flag.String("config", "", "Path to config file (YAML/JSON)")
+	configFile := flag.String("config", "", "Path to config file (YAML/JSON), or comma-separated files merged in order")
 	audiobookshelfURL := flag.String("audiobookshelf-url", "", "Audiobookshelf server URL")
 	audiobookshelfToken := flag.String("audiobookshelf-token", "", "Audiobookshelf API token")
 	hardcoverToken := flag.String("hardcover-token", "", "Hardcover API token")
 	syncInterval := flag.Duration("sync-interval", -1, "Sync interval (e.g., 10m, 1h). Defaults to config value if not set")
 	testBookFilter := flag.String("test-book-filter", "", "Filter books by title/author (case-insensitive)")
 	testBookLimit := flag.Int("test-book-limit", -1, "Limit number of books to process (-1 for no limit)")
+	limitLibrary := flag.String("limit-library", "", "Restrict a one-time sync (--once) or benchmark to a single library by name or ID")
+	benchmark := flag.Int("benchmark", 0, "Match a random sample of N library items against Hardcover (read-only), report match rates and exit")
+	syncItem := flag.String("sync-item", "", "Sync only the Audiobookshelf library item with this ID, report the outcome and exit")
+	dumpState := flag.Bool("dump-state", false, "Print the sync state file as JSON and exit")
+	listBlocked := flag.Bool("list-blocked", false, "Print the Audiobookshelf items in the blocklist and exit")
 
 	// Parse flags
 	flag.Parse()
@@ -91,6 +112,9 @@ func parseFlags() *configFlags {
 		// Use the environment variable name that matches the config struct tag (DRY_RUN)
 		os.Setenv("DRY_RUN", strconv.FormatBool(cfg.dryRun.value))
 	}
+	if cfg.ownershipOnly.set {
+		os.Setenv("SYNC_OWNERSHIP_ONLY", strconv.FormatBool(cfg.ownershipOnly.value))
+	}
 	if cfg.help.set {
 		os.Setenv("HELP", strconv.FormatBool(cfg.help.value))
 	}
@@ -150,6 +174,13 @@ func parseFlags() *configFlags {
 		os.Setenv("TEST_BOOK_LIMIT", strconv.Itoa(*testBookLimit))
 	}
 
+	// The library limit only applies to one-time syncs and benchmarks, so it is not exported to the environment
+	cfg.limitLibrary = strings.TrimSpace(*limitLibrary)
+	cfg.benchmark = *benchmark
+	cfg.syncItem = strings.TrimSpace(*syncItem)
+	cfg.dumpState = *dumpState
+	cfg.listBlocked = *listBlocked
+
 	return &cfg
 }
 
@@ -195,10 +226,14 @@ func RunOneTimeSync(flags *configFlags) {
 
 	// Re-initialize logger with config from file
 	logger.Setup(logger.Config{
-		Level:      "debug",
-		Format:     logger.ParseLogFormat(cfg.Logging.Format),
-		Output:     os.Stdout,
-		TimeFormat: time.RFC3339,
+		Level:          "debug",
+		Format:         logger.ParseLogFormat(cfg.Logging.Format),
+		Output:         os.Stdout,
+		TimeFormat:     time.RFC3339,
+		File:           cfg.Logging.File,
+		FileMaxSize:    int64(cfg.Logging.FileMaxSizeMB) * 1024 * 1024,
+		FileMaxBackups: cfg.Logging.FileMaxBackups,
+		DisableOutput:  cfg.Logging.DisableStdout,
 	})
 	log = logger.Get() // Get the reconfigured logger
 
@@ -224,6 +259,7 @@ func RunOneTimeSync(flags *configFlags) {
 		"sync_want_to_read":          cfg.Sync.SyncWantToRead,
 		"sync_owned":                 cfg.Sync.SyncOwned,
 		"dry_run":                    cfg.Sync.DryRun,
+		"ownership_only":             cfg.Sync.OwnershipOnly,
 		"test_book_filter":           cfg.Sync.TestBookFilter,
 		"test_book_limit":            cfg.Sync.TestBookLimit,
 	})
@@ -252,9 +288,36 @@ func RunOneTimeSync(flags *configFlags) {
 	})
 
 	audiobookshelfClient := audiobookshelf.NewClient(cfg.Audiobookshelf.URL, cfg.Audiobookshelf.Token)
-	// Get the global logger instance and pass it to the Hardcover client
+	audiobookshelfClient.SetFullItems(cfg.Audiobookshelf.FullItems)
+	audiobookshelfClient.SetAuthScheme(cfg.Audiobookshelf.AuthScheme)
+
+	// Restrict the sync to a single library if requested
+	if flags.limitLibrary != "" {
+		libCtx, libCancel := context.WithTimeout(context.Background(), 30*time.Second)
+		err := applyLibraryLimit(libCtx, audiobookshelfClient, cfg, flags.limitLibrary)
+		libCancel()
+		if err != nil {
+			log.Error("Invalid --limit-library value", map[string]interface{}{
+				"error":   err.Error(),
+				"library": flags.limitLibrary,
+			})
+			os.Exit(1)
+		}
+		log.Info("Limiting one-time sync to a single library", map[string]interface{}{
+			"library": flags.limitLibrary,
+		})
+	}
+
+	// Sync every mapped Audiobookshelf user to their own Hardcover account instead of the token owner
+	if len(cfg.Audiobookshelf.Users) > 0 {
+		runAllUsersSync(audiobookshelfClient, cfg)
+		return
+	}
+
+	// Get the global logger instance and pass it to the Hardcover client, which honors the global
+	// API settings such as disabled operations
 	logInstance := logger.Get()
-	hardcoverClient := hardcover.NewClient(cfg.Hardcover.Token, logInstance)
+	hardcoverClient := newHardcoverClientFactory(cfg, logInstance)(cfg.Hardcover.Token)
 
 	log.Debug("Created Audiobookshelf client", map[string]interface{}{
 		"client_type": "audiobookshelf",
@@ -333,6 +396,320 @@ func RunOneTimeSync(flags *configFlags) {
 	log.Info("========================================")
 }
 
+// runAllUsersSync syncs every Audiobookshelf user mapped in audiobookshelf.users once and exits with
+// an error status if any of them failed
+func runAllUsersSync(admin *audiobookshelf.Client, cfg *config.Config) {
+	log := logger.Get()
+	log.Info("Syncing all mapped Audiobookshelf users", map[string]interface{}{
+		"users": len(cfg.Audiobookshelf.Users),
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	startTime := time.Now()
+	results, err := sync.NewAllUsersSync(admin, cfg, newHardcoverClientFactory(cfg, log)).Sync(ctx)
+	if err != nil {
+		log.Error("Sync operation failed", map[string]interface{}{
+			"error":    err.Error(),
+			"duration": time.Since(startTime).String(),
+		})
+		os.Exit(1)
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		log.Error("Sync failed for some Audiobookshelf users", map[string]interface{}{
+			"failed":   failed,
+			"users":    len(results),
+			"duration": time.Since(startTime).String(),
+		})
+		os.Exit(1)
+	}
+
+	log.Info("Sync completed successfully for all users", map[string]interface{}{
+		"users":    len(results),
+		"duration": time.Since(startTime).String(),
+	})
+}
+
+// newHardcoverClientFactory returns a factory for Hardcover clients that share the global API settings.
+// With RateLimit.Shared set, the clients also share a single rate limiter.
+func newHardcoverClientFactory(cfg *config.Config, log *logger.Logger) sync.HardcoverClientFactory {
+	newClientConfig := func() *hardcover.ClientConfig {
+		hcCfg := hardcover.DefaultClientConfig()
+		if cfg.Hardcover.BaseURL != "" {
+			hcCfg.BaseURL = cfg.Hardcover.BaseURL
+		}
+		hcCfg.AllowedOperations = cfg.Hardcover.AllowedOperations
+		hcCfg.DisabledOperations = cfg.Hardcover.DisabledOperations
+		if cfg.RateLimit.Rate > 0 {
+			hcCfg.RateLimit = cfg.RateLimit.Rate
+		}
+		if cfg.RateLimit.Burst > 0 {
+			hcCfg.Burst = cfg.RateLimit.Burst
+		}
+		if cfg.RateLimit.MaxConcurrent > 0 {
+			hcCfg.MaxConcurrent = cfg.RateLimit.MaxConcurrent
+		}
+		return hcCfg
+	}
+
+	var sharedLimiter *util.RateLimiter
+	if cfg.RateLimit.Shared {
+		hcCfg := newClientConfig()
+		sharedLimiter = util.NewRateLimiter(hcCfg.RateLimit, hcCfg.Burst, hcCfg.MaxConcurrent, log)
+	}
+
+	return func(token string) hardcover.HardcoverClientInterface {
+		hcCfg := newClientConfig()
+		hcCfg.RateLimiter = sharedLimiter
+		return hardcover.NewClientWithConfig(hcCfg, token, log)
+	}
+}
+
+// RunBenchmark matches a random sample of library items against Hardcover without making any
+// changes and prints the match rates per method
+func RunBenchmark(flags *configFlags) {
+	log := logger.Get()
+
+	cfg, err := config.Load(flags.configFile)
+	if err != nil {
+		log.Error("Failed to load configuration", map[string]interface{}{
+			"error": err.Error(),
+		})
+		os.Exit(1)
+	}
+
+	audiobookshelfClient := audiobookshelf.NewClient(cfg.Audiobookshelf.URL, cfg.Audiobookshelf.Token)
+	audiobookshelfClient.SetFullItems(cfg.Audiobookshelf.FullItems)
+	audiobookshelfClient.SetAuthScheme(cfg.Audiobookshelf.AuthScheme)
+
+	if flags.limitLibrary != "" {
+		libCtx, libCancel := context.WithTimeout(context.Background(), 30*time.Second)
+		err := applyLibraryLimit(libCtx, audiobookshelfClient, cfg, flags.limitLibrary)
+		libCancel()
+		if err != nil {
+			log.Error("Invalid --limit-library value", map[string]interface{}{
+				"error":   err.Error(),
+				"library": flags.limitLibrary,
+			})
+			os.Exit(1)
+		}
+	}
+
+	hardcoverClient := hardcover.NewClient(cfg.Hardcover.Token, logger.Get())
+	syncService, err := sync.NewService(audiobookshelfClient, hardcoverClient, cfg)
+	if err != nil {
+		log.Error("Failed to initialize sync service", map[string]interface{}{
+			"error": err.Error(),
+		})
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	benchmark, err := syncService.BenchmarkMatching(ctx, flags.benchmark, rand.New(rand.NewSource(time.Now().UnixNano())))
+	if err != nil {
+		log.Error("Benchmark failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+		os.Exit(1)
+	}
+
+	printBenchmarkReport(os.Stdout, benchmark)
+}
+
+// printBenchmarkReport writes a human-readable summary of a matching benchmark
+func printBenchmarkReport(w io.Writer, benchmark *sync.MatchBenchmark) {
+	labels := map[string]string{
+		sync.MatchMethodASIN:        "ASIN",
+		sync.MatchMethodISBN:        "ISBN",
+		sync.MatchMethodTitleAuthor: "Title/author",
+		sync.MatchMethodUnmatched:   "Unmatched",
+	}
+
+	fmt.Fprintf(w, "Matching benchmark: %d of %d library items\n", benchmark.SampleSize(), benchmark.LibrarySize)
+	for _, method := range sync.MatchMethods {
+		fmt.Fprintf(w, "  %-14s %5d  %6.1f%%\n", labels[method], benchmark.Counts[method], benchmark.Percentage(method))
+	}
+	fmt.Fprintf(w, "Total time: %s (average %s per item)\n",
+		benchmark.Duration.Round(time.Millisecond), benchmark.AverageDuration().Round(time.Millisecond))
+}
+
+// RunSyncItem syncs a single library item, e.g. to debug a problem book without processing the
+// whole library, and prints the outcome
+func RunSyncItem(flags *configFlags) {
+	log := logger.Get()
+
+	cfg, err := config.Load(flags.configFile)
+	if err != nil {
+		log.Error("Failed to load configuration", map[string]interface{}{
+			"error": err.Error(),
+		})
+		os.Exit(1)
+	}
+
+	audiobookshelfClient := audiobookshelf.NewClient(cfg.Audiobookshelf.URL, cfg.Audiobookshelf.Token)
+	audiobookshelfClient.SetFullItems(cfg.Audiobookshelf.FullItems)
+	audiobookshelfClient.SetAuthScheme(cfg.Audiobookshelf.AuthScheme)
+
+	hardcoverClient := newHardcoverClientFactory(cfg, log)(cfg.Hardcover.Token)
+	syncService, err := sync.NewService(audiobookshelfClient, hardcoverClient, cfg)
+	if err != nil {
+		log.Error("Failed to initialize sync service", map[string]interface{}{
+			"error": err.Error(),
+		})
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	syncErr := syncService.SyncItem(ctx, flags.syncItem)
+	printItemSyncReport(os.Stdout, flags.syncItem, syncService.GetSummary(), syncErr)
+	if syncErr != nil {
+		os.Exit(1)
+	}
+}
+
+// printItemSyncReport writes a human-readable outcome of a single item sync
+func printItemSyncReport(w io.Writer, itemID string, summary *sync.SyncSummary, syncErr error) {
+	switch {
+	case syncErr != nil:
+		fmt.Fprintf(w, "Item %s: failed: %s\n", itemID, syncErr)
+	case summary.TotalBooksProcessed == 0:
+		fmt.Fprintf(w, "Item %s: not synced, its library is excluded\n", itemID)
+	case summary.BooksSynced > 0:
+		fmt.Fprintf(w, "Item %s: synced\n", itemID)
+	default:
+		fmt.Fprintf(w, "Item %s: skipped\n", itemID)
+	}
+
+	reasons := make([]string, 0, len(summary.SkipReasons))
+	for reason, count := range summary.SkipReasons {
+		if count > 0 {
+			reasons = append(reasons, reason)
+		}
+	}
+	sort.Strings(reasons)
+	for _, reason := range reasons {
+		fmt.Fprintf(w, "  Skipped: %s\n", reason)
+	}
+	for _, book := range summary.BooksNotFound {
+		fmt.Fprintf(w, "  Not found in Hardcover: %s (%s)\n", book.Title, book.Error)
+	}
+	for _, m := range summary.Mismatches {
+		fmt.Fprintf(w, "  Mismatch: %s (%s)\n", m.Title, m.Reason)
+	}
+}
+
+// RunDumpState prints the sync state file, migrated to the current version, as JSON
+func RunDumpState(flags *configFlags) {
+	log := logger.Get()
+
+	cfg, err := config.Load(flags.configFile)
+	if err != nil {
+		log.Error("Failed to load configuration", map[string]interface{}{
+			"error": err.Error(),
+		})
+		os.Exit(1)
+	}
+
+	if err := dumpState(os.Stdout, cfg.Sync.StateFile); err != nil {
+		log.Error("Failed to dump sync state", map[string]interface{}{
+			"error":      err.Error(),
+			"state_file": cfg.Sync.StateFile,
+		})
+		os.Exit(1)
+	}
+}
+
+// dumpState writes the sync state at path as indented JSON. Unlike a sync, it doesn't create a
+// missing state file.
+func dumpState(w io.Writer, path string) error {
+	if path == "" {
+		path = state.DefaultStateFile
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	syncState, err := state.LoadState(path)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(syncState)
+}
+
+// RunListBlocked prints the items in sync.blocklist and paths.blocklist_file
+func RunListBlocked(flags *configFlags) {
+	log := logger.Get()
+
+	cfg, err := config.Load(flags.configFile)
+	if err != nil {
+		log.Error("Failed to load configuration", map[string]interface{}{
+			"error": err.Error(),
+		})
+		os.Exit(1)
+	}
+
+	if err := listBlocked(os.Stdout, cfg); err != nil {
+		log.Error("Failed to list blocked items", map[string]interface{}{
+			"error":          err.Error(),
+			"blocklist_file": cfg.Paths.BlocklistFile,
+		})
+		os.Exit(1)
+	}
+}
+
+// listBlocked writes the blocked items, one per line with where they're blocked
+func listBlocked(w io.Writer, cfg *config.Config) error {
+	items, err := sync.BlockedItems(cfg)
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		fmt.Fprintln(w, "No items are blocked")
+		return nil
+	}
+	for _, item := range items {
+		fmt.Fprintf(w, "%s\t%s\n", item.ItemID, item.Source)
+	}
+	return nil
+}
+
+// applyLibraryLimit validates that the named library exists in Audiobookshelf and replaces the
+// configured library filters with a temporary include filter for just that library.
+// The library can be given by name (case-insensitive) or ID.
+func applyLibraryLimit(ctx context.Context, client audiobookshelf.AudiobookshelfClientInterface, cfg *config.Config, name string) error {
+	libraries, err := client.GetLibraries(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch libraries: %w", err)
+	}
+
+	available := make([]string, 0, len(libraries))
+	for _, library := range libraries {
+		if strings.EqualFold(library.Name, name) || library.ID == name {
+			cfg.Sync.Libraries.Include = []string{library.ID}
+			cfg.Sync.Libraries.Exclude = nil
+			return nil
+		}
+		available = append(available, library.Name)
+	}
+
+	return fmt.Errorf("library %q not found (available: %s)", name, strings.Join(available, ", "))
+}
+
 // startPeriodicSync starts the periodic sync service
 // StartPeriodicSync starts a periodic sync service with the specified interval
 // Note: The interval is assumed to be valid (positive) as it should have been validated by config
@@ -369,3 +746,49 @@ func StartPeriodicSync(ctx context.Context, syncService *sync.Service, abortCh <
 		}
 	}()
 }
+
+// StartAllUsersSync periodically syncs every Audiobookshelf user mapped in audiobookshelf.users to
+// their own Hardcover account, starting with an initial sync
+func StartAllUsersSync(ctx context.Context, allUsers *sync.AllUsersSync, abortCh <-chan struct{}, interval time.Duration) {
+	log := logger.Get()
+
+	log.Info("Starting periodic sync for all mapped Audiobookshelf users", map[string]interface{}{
+		"interval": interval.String(),
+	})
+
+	runSync := func() {
+		results, err := allUsers.Sync(ctx)
+		if err != nil {
+			log.Error("Sync for all mapped Audiobookshelf users failed", map[string]interface{}{
+				"error": err.Error(),
+			})
+			return
+		}
+		for _, result := range results {
+			if result.Err != nil {
+				log.Error("Sync failed for Audiobookshelf user", map[string]interface{}{
+					"user":  result.User,
+					"error": result.Err.Error(),
+				})
+			}
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		runSync()
+		for {
+			select {
+			case <-ticker.C:
+				runSync()
+			case <-abortCh:
+				log.Info("Received shutdown signal, stopping periodic sync for all users", nil)
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/audiobookshelf"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/sync"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/sync/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLibraryClient returns a fixed set of libraries
+type fakeLibraryClient struct {
+	libraries []audiobookshelf.AudiobookshelfLibrary
+	err       error
+}
+
+func (f *fakeLibraryClient) GetLibraries(ctx context.Context) ([]audiobookshelf.AudiobookshelfLibrary, error) {
+	return f.libraries, f.err
+}
+
+func (f *fakeLibraryClient) GetLibraryItems(ctx context.Context, libraryID string) ([]models.AudiobookshelfBook, error) {
+	return nil, nil
+}
+
+func (f *fakeLibraryClient) GetLibraryItemsUpdatedSince(ctx context.Context, libraryID string, since time.Time) ([]models.AudiobookshelfBook, error) {
+	return nil, nil
+}
+
+func (f *fakeLibraryClient) GetLibraryItemsPage(ctx context.Context, libraryID string, since time.Time, page, limit int) ([]models.AudiobookshelfBook, bool, error) {
+	return nil, false, nil
+}
+
+func (f *fakeLibraryClient) GetLibraryItem(ctx context.Context, itemID string) (*models.AudiobookshelfBook, error) {
+	return nil, nil
+}
+
+func (f *fakeLibraryClient) GetUserProgress(ctx context.Context) (*models.AudiobookshelfUserProgress, error) {
+	return nil, nil
+}
+
+func (f *fakeLibraryClient) GetListeningSessions(ctx context.Context, since time.Time) ([]models.AudiobookshelfBook, error) {
+	return nil, nil
+}
+
+func (f *fakeLibraryClient) GetCollections(ctx context.Context) ([]audiobookshelf.AudiobookshelfCollection, error) {
+	return nil, nil
+}
+
+func (f *fakeLibraryClient) UpdateMediaProgress(ctx context.Context, itemID string, currentTime float64, isFinished bool) error {
+	return nil
+}
+
+func TestApplyLibraryLimit(t *testing.T) {
+	client := &fakeLibraryClient{
+		libraries: []audiobookshelf.AudiobookshelfLibrary{
+			{ID: "lib1", Name: "Audiobooks"},
+			{ID: "lib2", Name: "Podcasts"},
+		},
+	}
+
+	t.Run("matches library by name case-insensitively", func(t *testing.T) {
+		cfg := config.DefaultConfig()
+		cfg.Sync.Libraries.Include = []string{"Audiobooks"}
+		cfg.Sync.Libraries.Exclude = []string{"Podcasts"}
+
+		require.NoError(t, applyLibraryLimit(context.Background(), client, cfg, "podcasts"))
+		assert.Equal(t, []string{"lib2"}, cfg.Sync.Libraries.Include)
+		assert.Empty(t, cfg.Sync.Libraries.Exclude)
+	})
+
+	t.Run("matches library by ID", func(t *testing.T) {
+		cfg := config.DefaultConfig()
+
+		require.NoError(t, applyLibraryLimit(context.Background(), client, cfg, "lib1"))
+		assert.Equal(t, []string{"lib1"}, cfg.Sync.Libraries.Include)
+	})
+
+	t.Run("unknown library returns an error", func(t *testing.T) {
+		cfg := config.DefaultConfig()
+
+		err := applyLibraryLimit(context.Background(), client, cfg, "Ebooks")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Audiobooks, Podcasts")
+		assert.Empty(t, cfg.Sync.Libraries.Include)
+	})
+
+	t.Run("fetch failure returns an error", func(t *testing.T) {
+		cfg := config.DefaultConfig()
+		failing := &fakeLibraryClient{err: errors.New("connection refused")}
+
+		assert.Error(t, applyLibraryLimit(context.Background(), failing, cfg, "Audiobooks"))
+	})
+}
+
+func TestPrintBenchmarkReport(t *testing.T) {
+	benchmark := &sync.MatchBenchmark{
+		LibrarySize: 40,
+		Results:     make([]sync.BenchmarkResult, 4),
+		Counts: map[string]int{
+			sync.MatchMethodASIN:      3,
+			sync.MatchMethodUnmatched: 1,
+		},
+		Duration: 2 * time.Second,
+	}
+
+	var out bytes.Buffer
+	printBenchmarkReport(&out, benchmark)
+
+	assert.Contains(t, out.String(), "4 of 40 library items")
+	assert.Contains(t, out.String(), "75.0%")
+	assert.Contains(t, out.String(), "25.0%")
+	assert.Contains(t, out.String(), "average 500ms per item")
+}
+
+func TestDumpState(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "state.json")
+
+	var out bytes.Buffer
+	assert.Error(t, dumpState(&out, statePath), "a missing state file isn't created")
+	assert.NoFileExists(t, statePath)
+
+	syncState := state.NewState()
+	syncState.UpdateBook("abs-1:100", 0.5, "IN_PROGRESS")
+	syncState.RecordMatch("abs-1:100", state.MatchSourceISBN, 1, "100")
+	require.NoError(t, syncState.Save(statePath))
+
+	require.NoError(t, dumpState(&out, statePath))
+	assert.Contains(t, out.String(), `"matchSource": "isbn"`)
+	assert.Contains(t, out.String(), `"editionId": "100"`)
+}
+
+func TestListBlocked(t *testing.T) {
+	cfg := &config.Config{}
+
+	var out bytes.Buffer
+	require.NoError(t, listBlocked(&out, cfg))
+	assert.Equal(t, "No items are blocked\n", out.String())
+
+	cfg.Sync.Blocklist = []string{"li_2"}
+	cfg.Paths.BlocklistFile = filepath.Join(t.TempDir(), "blocklist.txt")
+	require.NoError(t, sync.AppendBlocklistFile(cfg.Paths.BlocklistFile, "li_1"))
+
+	out.Reset()
+	require.NoError(t, listBlocked(&out, cfg))
+	assert.Equal(t, "li_1\t"+cfg.Paths.BlocklistFile+"\nli_2\tconfig\n", out.String())
+}
+
+func TestPrintItemSyncReport(t *testing.T) {
+	var out bytes.Buffer
+	printItemSyncReport(&out, "li_1", &sync.SyncSummary{TotalBooksProcessed: 1, BooksSynced: 1}, nil)
+	assert.Equal(t, "Item li_1: synced\n", out.String())
+
+	out.Reset()
+	printItemSyncReport(&out, "li_2", &sync.SyncSummary{
+		TotalBooksProcessed: 1,
+		BooksNotFound:       []sync.BookNotFoundInfo{{Title: "Lost Book", Error: "no ASIN or ISBN"}},
+		SkipReasons:         map[string]int{sync.SkipReasonNotFound: 1},
+	}, nil)
+	assert.Contains(t, out.String(), "Item li_2: skipped")
+	assert.Contains(t, out.String(), "Skipped: not_found")
+	assert.Contains(t, out.String(), "Not found in Hardcover: Lost Book (no ASIN or ISBN)")
+
+	out.Reset()
+	printItemSyncReport(&out, "li_3", &sync.SyncSummary{}, nil)
+	assert.Contains(t, out.String(), "its library is excluded")
+
+	out.Reset()
+	printItemSyncReport(&out, "li_4", &sync.SyncSummary{}, errors.New("item not found"))
+	assert.Contains(t, out.String(), "Item li_4: failed: item not found")
+}
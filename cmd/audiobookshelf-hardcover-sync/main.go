@@ -23,6 +23,7 @@ import (
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/multiuser"
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/server"
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/sync"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/tracing"
 )
 
 // Package main is the entry point for the Audiobookshelf to Hardcover sync service.
@@ -77,6 +78,18 @@ func main() {
 		return
 	}
 
+	// Print the sync state if requested, before any startup logging so the output stays valid JSON
+	if flags.dumpState {
+		RunDumpState(flags)
+		return
+	}
+
+	// List the blocked items if requested
+	if flags.listBlocked {
+		RunListBlocked(flags)
+		return
+	}
+
 	// Load configuration first (without initializing logger)
 	// We'll use environment variables and command line flags to determine initial log level
 	cfg, err := config.Load(flags.configFile)
@@ -90,10 +103,14 @@ func main() {
 	// Use ForceSetup to ensure the logger is re-initialized with the correct format
 	// even if it was previously initialized during config loading
 	logger.ForceSetup(logger.Config{
-		Level:      cfg.Logging.Level,
-		Format:     logger.ParseLogFormat(cfg.Logging.Format),
-		Output:     os.Stdout,
-		TimeFormat: time.RFC3339,
+		Level:          cfg.Logging.Level,
+		Format:         logger.ParseLogFormat(cfg.Logging.Format),
+		Output:         os.Stdout,
+		TimeFormat:     time.RFC3339,
+		File:           cfg.Logging.File,
+		FileMaxSize:    int64(cfg.Logging.FileMaxSizeMB) * 1024 * 1024,
+		FileMaxBackups: cfg.Logging.FileMaxBackups,
+		DisableOutput:  cfg.Logging.DisableStdout,
 	})
 
 	// Get the logger instance
@@ -112,6 +129,29 @@ func main() {
 		"dry_run":   cfg.Sync.DryRun,
 	})
 
+	// Export traces of sync runs if enabled
+	if cfg.Observability.TracingEnabled {
+		shutdownTracing, err := tracing.Setup(context.Background(), cfg.Observability.OTLPEndpoint, version)
+		if err != nil {
+			log.Error("Failed to set up tracing, continuing without it", map[string]interface{}{
+				"error": err.Error(),
+			})
+		} else {
+			log.Info("OpenTelemetry tracing enabled", map[string]interface{}{
+				"otlp_endpoint": cfg.Observability.OTLPEndpoint,
+			})
+			defer func() {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				if err := shutdownTracing(ctx); err != nil {
+					log.Warn("Failed to flush traces", map[string]interface{}{
+						"error": err.Error(),
+					})
+				}
+			}()
+		}
+	}
+
 	// Set environment variables from flags if provided
 	setEnvFromFlag(flags.audiobookshelfURL, "AUDIOBOOKSHELF_URL")
 	setEnvFromFlag(flags.audiobookshelfToken, "AUDIOBOOKSHELF_TOKEN")
@@ -130,12 +170,30 @@ func main() {
 		os.Setenv("DRY_RUN", "true")
 	}
 
+	// Run a matching benchmark if requested
+	if flags.benchmark > 0 {
+		RunBenchmark(flags)
+		return
+	}
+
+	// Sync a single library item if requested
+	if flags.syncItem != "" {
+		RunSyncItem(flags)
+		return
+	}
+
 	// Run one-time sync if requested
 	if flags.oneTimeSync.value {
 		RunOneTimeSync(flags)
 		return
 	}
 
+	if flags.limitLibrary != "" {
+		log.Warn("--limit-library only applies to one-time syncs (--once) and benchmarks (--benchmark), ignoring", map[string]interface{}{
+			"library": flags.limitLibrary,
+		})
+	}
+
 	// Set up signal handling
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
@@ -214,7 +272,8 @@ func main() {
 	repo := database.NewRepository(db, encryptor, log)
 
 	// Perform automatic migration from single-user config if needed
-	// Use the actual config path that was loaded, not default search paths
+	// Use the actual config path that was loaded, not default search paths; it may list several
+	// comma-separated files
 	configPath := flags.configFile
 
 	// Log the migration attempt with the actual database path being used
@@ -312,12 +371,16 @@ func main() {
 	if !cfg.Server.EnableWebUI {
 		// Simple mode: Create clients from config
 		audiobookshelfClient := audiobookshelf.NewClient(cfg.Audiobookshelf.URL, cfg.Audiobookshelf.Token)
+		audiobookshelfClient.SetFullItems(cfg.Audiobookshelf.FullItems)
+		audiobookshelfClient.SetAuthScheme(cfg.Audiobookshelf.AuthScheme)
 
 		// Build Hardcover client config from global settings
 		hcCfg := hardcover.DefaultClientConfig()
 		if cfg.Hardcover.BaseURL != "" {
 			hcCfg.BaseURL = cfg.Hardcover.BaseURL
 		}
+		hcCfg.AllowedOperations = cfg.Hardcover.AllowedOperations
+		hcCfg.DisabledOperations = cfg.Hardcover.DisabledOperations
 		if cfg.RateLimit.Rate > 0 {
 			hcCfg.RateLimit = cfg.RateLimit.Rate
 		}
@@ -356,6 +419,7 @@ func main() {
 	if cfg.Server.EnableWebUI {
 		// Create HTTP server with multi-user and authentication support
 		srv = server.New(fmt.Sprintf(":%s", cfg.Server.Port), multiUserService, authService, syncService, log)
+		srv.SetWebhookSecret(cfg.Webhooks.Secret)
 
 		// Start the HTTP server
 		go func() {
@@ -387,6 +451,14 @@ func main() {
 			"interval": syncInterval.String(),
 		})
 
+		// Mapped Audiobookshelf users are synced to their own Hardcover accounts using the admin token
+		if len(cfg.Audiobookshelf.Users) > 0 {
+			adminClient := audiobookshelf.NewClient(cfg.Audiobookshelf.URL, cfg.Audiobookshelf.Token)
+			adminClient.SetFullItems(cfg.Audiobookshelf.FullItems)
+			adminClient.SetAuthScheme(cfg.Audiobookshelf.AuthScheme)
+			StartAllUsersSync(ctx, sync.NewAllUsersSync(adminClient, cfg, newHardcoverClientFactory(cfg, log)), abortCh, syncInterval)
+		}
+
 		// Start a ticker for periodic sync
 		ticker := time.NewTicker(syncInterval)
 		defer ticker.Stop()
@@ -530,18 +602,42 @@ func showHelp() {
 	fmt.Println("  \tEnvironment: HARDCOVER_TOKEN")
 
 	fmt.Println("\nOptional Configuration:")
-	fmt.Println("  --config FILE")
-	fmt.Println("  \tPath to config file (YAML/JSON)")
+	fmt.Println("  --config FILE[,FILE...]")
+	fmt.Println("  \tPath to config file (YAML/JSON); multiple comma-separated files are merged in order, later files win")
 	fmt.Println("  \tEnvironment: CONFIG_PATH")
 
 	fmt.Println("  --sync-interval DURATION")
 	fmt.Println("  \tInterval between syncs (e.g., 1h, 30m, 0 to disable)")
 	fmt.Println("  \tEnvironment: SYNC_INTERVAL (duration string, e.g., 1h30m)")
 
+	fmt.Println("  --limit-library NAME")
+	fmt.Println("  \tRestrict a one-time sync (--once) or benchmark to a single library by name or ID")
+
+	fmt.Println("  --benchmark N")
+	fmt.Println("  \tMatch a random sample of N library items against Hardcover without making changes,")
+	fmt.Println("  \treport the share matched by ASIN, ISBN, title/author or unmatched, and exit")
+
+	fmt.Println("  --sync-item ID")
+	fmt.Println("  \tSync only the Audiobookshelf library item with this ID, e.g. to debug a problem book,")
+	fmt.Println("  \treport the outcome and exit")
+
+	fmt.Println("  --dump-state")
+	fmt.Println("  \tPrint the sync state file as JSON, including recorded matches")
+	fmt.Println("  \t(see sync.record_match_info), and exit")
+
+	fmt.Println("  --list-blocked")
+	fmt.Println("  \tPrint the Audiobookshelf items in sync.blocklist and paths.blocklist_file,")
+	fmt.Println("  \twhich are never synced or recorded as mismatches, and exit")
+
 	fmt.Println("  --dry-run")
 	fmt.Println("  \tRun in dry-run mode (no changes will be made)")
 	fmt.Println("  \tEnvironment: DRY_RUN (true/false)")
 
+	fmt.Println("  --ownership-only")
+	fmt.Println("  \tOnly mark matched books as owned, without syncing reads or statuses,")
+	fmt.Println("  \te.g. for a fast first pass over a new library")
+	fmt.Println("  \tEnvironment: SYNC_OWNERSHIP_ONLY (true/false)")
+
 	fmt.Println("\nOther Options:")
 	fmt.Println("  -h, --help")
 	fmt.Println("  \tShow this help message")
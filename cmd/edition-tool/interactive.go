@@ -0,0 +1,273 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/edition"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// errAborted is returned when the user doesn't confirm the assembled edition
+var errAborted = errors.New("edition creation aborted")
+
+// peopleSearcher looks up authors and narrators in Hardcover
+type peopleSearcher interface {
+	SearchPeople(ctx context.Context, name, personType string, limit int) ([]models.Author, error)
+}
+
+// prompter asks for the fields of an edition on in and writes its questions to out
+type prompter struct {
+	in     *bufio.Reader
+	out    io.Writer
+	people peopleSearcher
+}
+
+func newPrompter(in io.Reader, out io.Writer, people peopleSearcher) *prompter {
+	return &prompter{in: bufio.NewReader(in), out: out, people: people}
+}
+
+// ask prints the question and returns the trimmed answer, io.EOF once the input ends
+func (p *prompter) ask(question string) (string, error) {
+	fmt.Fprintf(p.out, "%s: ", question)
+	line, err := p.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// askUntilValid asks the question until parse accepts the answer
+func (p *prompter) askUntilValid(question string, parse func(string) error) (string, error) {
+	for {
+		answer, err := p.ask(question)
+		if err != nil {
+			return "", err
+		}
+		if err := parse(answer); err != nil {
+			fmt.Fprintf(p.out, "  %s\n", err)
+			continue
+		}
+		return answer, nil
+	}
+}
+
+// runInteractive prompts for an edition on in, echoes it as JSON for confirmation and creates it,
+// only previewing the creation in dry-run mode
+func runInteractive(ctx context.Context, configPath string, dryRun bool, in io.Reader, out io.Writer) error {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	log := logger.Get()
+
+	hc := hardcover.NewClient(cfg.Hardcover.Token, log)
+	creator := edition.NewCreator(hc, log, dryRun, cfg.Audiobookshelf.Token)
+	creator.SetDefaults(cfg.Edition.Defaults)
+	creator.SetResolveConcurrency(cfg.Edition.ResolveConcurrency)
+	creator.SetCreateMissingPublishers(cfg.Edition.CreateMissingPublishers)
+	creator.SetFormats(cfg.Edition.Formats)
+
+	p := newPrompter(in, out, hc)
+	input, err := p.promptEdition(ctx)
+	if err != nil {
+		return err
+	}
+	if err := p.confirm(input, dryRun); err != nil {
+		return err
+	}
+
+	result, err := creator.CreateEdition(ctx, input)
+	if err != nil {
+		return fmt.Errorf("failed to create edition: %w", err)
+	}
+	output, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Fprintln(out, string(output))
+	return nil
+}
+
+// promptEdition asks for the fields of an edition and resolves the author and narrator names to
+// Hardcover IDs
+func (p *prompter) promptEdition(ctx context.Context) (*edition.EditionInput, error) {
+	input := &edition.EditionInput{EditionFormat: edition.DefaultFormatKey}
+
+	bookID, err := p.askUntilValid("Hardcover book ID", func(s string) error {
+		if id, err := strconv.Atoi(s); err != nil || id <= 0 {
+			return errors.New("enter the numeric ID of the book in Hardcover")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	input.BookID, _ = strconv.Atoi(bookID)
+
+	if input.Title, err = p.askUntilValid("Title", func(s string) error {
+		if s == "" {
+			return errors.New("the title is required")
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if input.Subtitle, err = p.ask("Subtitle (optional)"); err != nil {
+		return nil, err
+	}
+	if input.ASIN, err = p.ask("ASIN (optional)"); err != nil {
+		return nil, err
+	}
+
+	isbn, err := p.askUntilValid("ISBN-10 or ISBN-13 (optional)", func(s string) error {
+		if s = strings.ReplaceAll(s, "-", ""); s != "" && len(s) != 10 && len(s) != 13 {
+			return errors.New("an ISBN has 10 or 13 characters")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if isbn = strings.ReplaceAll(isbn, "-", ""); len(isbn) == 10 {
+		input.ISBN10 = isbn
+	} else if len(isbn) == 13 {
+		input.ISBN13 = isbn
+	}
+
+	if input.AuthorIDs, err = p.promptPeople(ctx, "author", "Author names (comma-separated)"); err != nil {
+		return nil, err
+	}
+	if input.NarratorIDs, err = p.promptPeople(ctx, "narrator", "Narrator names (comma-separated)"); err != nil {
+		return nil, err
+	}
+	if input.PublisherName, err = p.ask("Publisher (optional)"); err != nil {
+		return nil, err
+	}
+
+	if input.ReleaseDate, err = p.askUntilValid("Release date YYYY-MM-DD (optional)", func(s string) error {
+		if s == "" {
+			return nil
+		}
+		_, err := time.Parse("2006-01-02", s)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	length, err := p.askUntilValid("Audio length in seconds or HH:MM:SS (optional)", func(s string) error {
+		_, err := parseAudioLength(s)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	input.AudioLength, _ = parseAudioLength(length)
+
+	return input, nil
+}
+
+// promptPeople asks for comma-separated names and resolves each to a Hardcover person of the
+// kind, offering the top search result and taking an ID typed instead
+func (p *prompter) promptPeople(ctx context.Context, kind, question string) ([]int, error) {
+	names, err := p.ask(question)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		suggestion := ""
+		people, err := p.people.SearchPeople(ctx, name, kind, 5)
+		if err != nil {
+			fmt.Fprintf(p.out, "  Failed to search for %s %q: %s\n", kind, name, err)
+		} else if len(people) > 0 {
+			suggestion = people[0].ID
+			fmt.Fprintf(p.out, "  Found %s %q with ID %s\n", kind, people[0].Name, suggestion)
+		} else {
+			fmt.Fprintf(p.out, "  No %s found for %q\n", kind, name)
+		}
+
+		question := fmt.Sprintf("  ID of %s %q (empty to skip)", kind, name)
+		if suggestion != "" {
+			question = fmt.Sprintf("  ID of %s %q [%s]", kind, name, suggestion)
+		}
+		answer, err := p.askUntilValid(question, func(s string) error {
+			if s == "" {
+				return nil
+			}
+			if _, err := strconv.Atoi(s); err != nil {
+				return fmt.Errorf("enter the numeric ID of the %s", kind)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if answer == "" {
+			answer = suggestion
+		}
+		if id, err := strconv.Atoi(answer); err == nil && id > 0 {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// confirm echoes the assembled edition as JSON and asks whether to create it
+func (p *prompter) confirm(input *edition.EditionInput, dryRun bool) error {
+	data, err := json.MarshalIndent(input, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal edition: %w", err)
+	}
+	fmt.Fprintf(p.out, "\n%s\n\n", data)
+
+	question := "Create this edition? [y/N]"
+	if dryRun {
+		question = "Preview the creation of this edition (dry run)? [y/N]"
+	}
+	answer, err := p.ask(question)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(answer, "y") && !strings.EqualFold(answer, "yes") {
+		return errAborted
+	}
+	return nil
+}
+
+// parseAudioLength parses an audio length given in seconds or as HH:MM:SS, empty being zero
+func parseAudioLength(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if seconds, err := strconv.Atoi(s); err == nil && seconds >= 0 {
+		return seconds, nil
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid audio length %q, expected seconds or HH:MM:SS", s)
+	}
+	total := 0
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || (i > 0 && n > 59) {
+			return 0, fmt.Errorf("invalid audio length %q, expected seconds or HH:MM:SS", s)
+		}
+		total = total*60 + n
+	}
+	return total, nil
+}
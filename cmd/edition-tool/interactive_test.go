@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/edition"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePeopleSearcher returns fixed Hardcover people by name
+type fakePeopleSearcher struct {
+	people map[string][]models.Author
+}
+
+func (f *fakePeopleSearcher) SearchPeople(ctx context.Context, name, personType string, limit int) ([]models.Author, error) {
+	return f.people[name], nil
+}
+
+func TestPromptEdition(t *testing.T) {
+	searcher := &fakePeopleSearcher{people: map[string][]models.Author{
+		"Frank Herbert": {{ID: "7", Name: "Frank Herbert"}},
+		"Scott Brick":   {{ID: "11", Name: "Scott Brick"}},
+	}}
+	answers := strings.Join([]string{
+		"abc", "42", // an invalid book ID is asked again
+		"Dune",
+		"",
+		"B002V1OF70",
+		"978-0-441-17271-9",
+		"Frank Herbert",
+		"", // accept the suggested author
+		"Scott Brick, Unknown Narrator",
+		"12", // override the suggested narrator
+		"",   // skip the unknown narrator
+		"Macmillan Audio",
+		"2007",
+		"2007-02-01",
+		"21:00:00",
+	}, "\n") + "\n"
+
+	var out bytes.Buffer
+	p := newPrompter(strings.NewReader(answers), &out, searcher)
+	input, err := p.promptEdition(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, &edition.EditionInput{
+		BookID:        42,
+		Title:         "Dune",
+		ASIN:          "B002V1OF70",
+		ISBN13:        "9780441172719",
+		AuthorIDs:     []int{7},
+		NarratorIDs:   []int{12},
+		PublisherName: "Macmillan Audio",
+		ReleaseDate:   "2007-02-01",
+		AudioLength:   75600,
+		EditionFormat: edition.DefaultFormatKey,
+	}, input)
+	assert.Contains(t, out.String(), `Found narrator "Scott Brick" with ID 11`)
+	assert.Contains(t, out.String(), `No narrator found for "Unknown Narrator"`)
+}
+
+func TestConfirm(t *testing.T) {
+	input := &edition.EditionInput{BookID: 42, Title: "Dune"}
+
+	var out bytes.Buffer
+	require.NoError(t, newPrompter(strings.NewReader("y\n"), &out, nil).confirm(input, false))
+	assert.Contains(t, out.String(), `"title": "Dune"`)
+
+	err := newPrompter(strings.NewReader("\n"), &out, nil).confirm(input, true)
+	assert.ErrorIs(t, err, errAborted)
+}
+
+func TestParseAudioLength(t *testing.T) {
+	for s, want := range map[string]int{"": 0, "3600": 3600, "01:02:03": 3723, "21:00:00": 75600} {
+		got, err := parseAudioLength(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, want, got, s)
+	}
+	for _, s := range []string{"1:02", "01:60:00", "-5", "abc"} {
+		_, err := parseAudioLength(s)
+		assert.Error(t, err, s)
+	}
+}
@@ -3,6 +3,8 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
@@ -19,6 +21,8 @@ func main() {
 	createInteractive := createCmd.Bool("interactive", false, "Run in interactive mode")
 	createPrepopulated := createCmd.Bool("prepopulated", false, "Prepopulate with Hardcover data")
 	createFile := createCmd.String("file", "", "Create from JSON file")
+	createConfig := createCmd.String("config", "config.yaml", "Path to the configuration file")
+	createDryRun := createCmd.Bool("dry-run", false, "Preview the edition without creating it")
 
 	// Check if a subcommand is provided
 	if len(os.Args) < 2 {
@@ -32,8 +36,12 @@ func main() {
 			logger.Fatal().Err(err).Msg("Error parsing command line arguments")
 		}
 		if *createInteractive {
-			// TODO: Implement interactive mode
-			logger.Info().Msg("Interactive edition creation")
+			err := runInteractive(context.Background(), *createConfig, *createDryRun, os.Stdin, os.Stdout)
+			if errors.Is(err, errAborted) {
+				logger.Info().Msg("Edition creation aborted")
+			} else if err != nil {
+				logger.Fatal().Err(err).Msg("Interactive edition creation failed")
+			}
 		} else if *createPrepopulated {
 			// TODO: Implement prepopulated mode
 			logger.Info().Msg("Prepopulated edition creation")
@@ -66,9 +74,12 @@ Flags for create:
   --interactive    Run in interactive mode
   --prepopulated   Prepopulate with Hardcover data
   --file string    Create from JSON file
+  --config string  Path to the configuration file, environment variables override it (default "config.yaml")
+  --dry-run        Preview the edition without creating it
 
 Examples:
   edition-tool create --interactive
+  edition-tool create --interactive --dry-run
   edition-tool create --prepopulated
   edition-tool create --file edition.json`)
 }
@@ -156,6 +156,10 @@ func createEdition(c *cli.Context) error {
 		log.Debug("Using Audiobookshelf token from config")
 	}
 	creator := edition.NewCreator(hc, log, c.Bool("dry-run"), audiobookshelfToken)
+	creator.SetDefaults(cfg.Edition.Defaults)
+	creator.SetResolveConcurrency(cfg.Edition.ResolveConcurrency)
+	creator.SetCreateMissingPublishers(cfg.Edition.CreateMissingPublishers)
+	creator.SetFormats(cfg.Edition.Formats)
 
 	// Create edition
 	result, err := creator.CreateEdition(context.Background(), &input)
@@ -189,6 +193,7 @@ func prepopulateEdition(c *cli.Context) error {
 		log.Debug("Using Audiobookshelf token from config")
 	}
 	creator := edition.NewCreator(hc, log, c.Bool("dry-run"), audiobookshelfToken)
+	creator.SetDefaults(cfg.Edition.Defaults)
 
 	// Generate prepopulated data
 	prepopulated, err := creator.PrepopulateFromBook(context.Background(), c.Int("book-id"))
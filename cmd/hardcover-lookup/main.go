@@ -9,6 +9,7 @@
 //	author     Look up or verify author information
 //	narrator   Look up or verify narrator information
 //	publisher  Look up or verify publisher information
+//	shelf-diff List Hardcover books in a status that have no Audiobookshelf item
 //	help       Show help for commands
 //
 // Global Flags:
@@ -29,10 +30,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/audiobookshelf"
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
-	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
 )
 
 func main() {
@@ -116,6 +117,10 @@ func main() {
 	publisherLimit := publisherCmd.Int("limit", *limit, "Maximum number of results to return")
 	publisherJSON := publisherCmd.Bool("json", *jsonOutput, "Output results in JSON format")
 
+	shelfDiffCmd := flag.NewFlagSet("shelf-diff", flag.ExitOnError)
+	shelfDiffStatus := shelfDiffCmd.String("status", "reading", "Hardcover status to list (want-to-read, reading, read)")
+	shelfDiffJSON := shelfDiffCmd.Bool("json", *jsonOutput, "Output results in JSON format")
+
 	switch subcommand {
 	case "author":
 		if err := authorCmd.Parse(subArgs); err != nil {
@@ -180,6 +185,25 @@ func main() {
 			os.Exit(1)
 		}
 
+	case "shelf-diff":
+		if err := shelfDiffCmd.Parse(subArgs); err != nil {
+			log.Error(fmt.Sprintf("Error parsing shelf-diff command flags: %v", err))
+			shelfDiffCmd.Usage()
+			os.Exit(1)
+		}
+		if _, ok := hardcover.StatusID(*shelfDiffStatus); !ok {
+			log.Error(fmt.Sprintf("Unknown status: %s", *shelfDiffStatus))
+			shelfDiffCmd.Usage()
+			os.Exit(1)
+		}
+		if cfg.Audiobookshelf.URL == "" || cfg.Audiobookshelf.Token == "" {
+			log.Error("shelf-diff needs the Audiobookshelf URL and token to be configured")
+			os.Exit(1)
+		}
+		abs := audiobookshelf.NewClient(cfg.Audiobookshelf.URL, cfg.Audiobookshelf.Token)
+		abs.SetAuthScheme(cfg.Audiobookshelf.AuthScheme)
+		runShelfDiff(ctx, hc, abs, *shelfDiffStatus, *shelfDiffJSON)
+
 	case "help":
 		printUsage()
 
@@ -343,32 +367,16 @@ func lookupPublisherByName(ctx context.Context, hc *hardcover.Client, name strin
 // verifyPublisherID verifies a publisher by ID
 func verifyPublisherID(ctx context.Context, hc *hardcover.Client, id string, jsonOutput bool) {
 	log := logger.Get()
-	// Since we don't have a direct GetPublisherByID method, we'll use SearchPublishers
-	// with a filter on the ID field
-	publishers, err := hc.SearchPublishers(ctx, "", 10) // Empty name to get all publishers
+	publisher, err := hc.GetPublisherByID(ctx, id)
 	if err != nil {
-		log.Error(fmt.Sprintf("Failed to fetch publishers: %v", err))
-		os.Exit(1)
-	}
-
-	// Find the publisher with the matching ID
-	var foundPublisher *models.Publisher
-	for i := range publishers {
-		if publishers[i].ID == id {
-			foundPublisher = &publishers[i]
-			break
-		}
-	}
-
-	if foundPublisher == nil {
-		log.Error(fmt.Sprintf("Publisher not found with ID: %s", id))
+		log.Error(fmt.Sprintf("Failed to verify publisher ID: %v (id: %s)", err, id))
 		os.Exit(1)
 	}
 
 	if jsonOutput {
-		printJSON(foundPublisher)
+		printJSON(publisher)
 	} else {
-		fmt.Printf("Publisher found:\nID: %s\nName: %s\n", foundPublisher.ID, foundPublisher.Name)
+		fmt.Printf("Publisher found:\nID: %s\nName: %s\n", publisher.ID, publisher.Name)
 	}
 }
 
@@ -446,6 +454,9 @@ Commands:
     -bulk      Bulk look up multiple publishers (comma-separated)
     -limit     Maximum results to return (default 5)
 
+  shelf-diff   List Hardcover books in a status that have no Audiobookshelf item (read-only)
+    -status    Hardcover status: want-to-read, reading or read (default reading)
+
 Examples:
   # Look up an author by name
   hardcover-lookup author -name "J.K. Rowling"
@@ -456,6 +467,9 @@ Examples:
   # Bulk look up multiple narrators with a custom limit
   hardcover-lookup narrator -bulk "Jim Dale,Stephen Fry" -limit 10
   
+  # Find books marked as currently reading in Hardcover that aren't in Audiobookshelf
+  hardcover-lookup shelf-diff -status reading
+  
   # Look up a publisher with a custom config file
   hardcover-lookup -config ./my-config.yaml publisher -name "Penguin"
 
@@ -468,5 +482,6 @@ Configuration:
     - CONFIG_FILE: Path to config file (default: ./config.yaml)
     - LOG_LEVEL: Log level (debug, info, warn, error, fatal)
     - LOG_FORMAT: Log format (json, text)
+    - AUDIOBOOKSHELF_URL, AUDIOBOOKSHELF_TOKEN: Your Audiobookshelf server (needed by shelf-diff)
 `)
 }
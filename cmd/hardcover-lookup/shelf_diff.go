@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/audiobookshelf"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// userBookLister lists the current user's Hardcover books with a status
+type userBookLister interface {
+	ListUserBooks(ctx context.Context, status string) ([]models.HardcoverBook, error)
+}
+
+// libraryItemLister lists the items of all Audiobookshelf libraries
+type libraryItemLister interface {
+	GetLibraries(ctx context.Context) ([]audiobookshelf.AudiobookshelfLibrary, error)
+	GetLibraryItems(ctx context.Context, libraryID string) ([]models.AudiobookshelfBook, error)
+}
+
+// Reasons a Hardcover book is reported by shelf-diff
+const (
+	shelfDiffNotInAudiobookshelf = "not in Audiobookshelf"
+	shelfDiffNoIdentifiers       = "edition has no ASIN or ISBN to match"
+)
+
+// shelfDiffEntry is a Hardcover book on the shelf without a corresponding Audiobookshelf item
+type shelfDiffEntry struct {
+	BookID     string `json:"book_id"`
+	UserBookID string `json:"user_book_id"`
+	Title      string `json:"title"`
+	Slug       string `json:"slug,omitempty"`
+	EditionID  string `json:"edition_id,omitempty"`
+	ASIN       string `json:"asin,omitempty"`
+	ISBN13     string `json:"isbn_13,omitempty"`
+	ISBN10     string `json:"isbn_10,omitempty"`
+	Reason     string `json:"reason"`
+}
+
+// shelfDiffResult is the outcome of comparing a Hardcover shelf with Audiobookshelf
+type shelfDiffResult struct {
+	Status  string           `json:"status"`
+	Total   int              `json:"total"`
+	Matched int              `json:"matched"`
+	Orphans []shelfDiffEntry `json:"orphans"`
+}
+
+// diffShelf lists the Hardcover books with the given status and reports those whose edition
+// identifiers don't match any Audiobookshelf item. Nothing is changed in either service.
+func diffShelf(ctx context.Context, hc userBookLister, abs libraryItemLister, status string) (*shelfDiffResult, error) {
+	hcBooks, err := hc.ListUserBooks(ctx, status)
+	if err != nil {
+		return nil, err
+	}
+
+	libraries, err := abs.GetLibraries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Audiobookshelf libraries: %w", err)
+	}
+	absIdentifiers := make(map[string]bool)
+	for _, library := range libraries {
+		items, err := abs.GetLibraryItems(ctx, library.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get items of Audiobookshelf library %s: %w", library.Name, err)
+		}
+		for _, item := range items {
+			for _, id := range []string{item.Media.Metadata.ASIN, item.Media.Metadata.ISBN} {
+				if key := normalizeIdentifier(id); key != "" {
+					absIdentifiers[key] = true
+				}
+			}
+		}
+	}
+
+	result := &shelfDiffResult{Status: status, Total: len(hcBooks), Orphans: []shelfDiffEntry{}}
+	for _, book := range hcBooks {
+		reason := shelfDiffNoIdentifiers
+		for _, id := range []string{book.EditionASIN, book.EditionISBN13, book.EditionISBN10} {
+			key := normalizeIdentifier(id)
+			if key == "" {
+				continue
+			}
+			if absIdentifiers[key] {
+				reason = ""
+				break
+			}
+			reason = shelfDiffNotInAudiobookshelf
+		}
+		if reason == "" {
+			result.Matched++
+			continue
+		}
+
+		result.Orphans = append(result.Orphans, shelfDiffEntry{
+			BookID:     book.ID,
+			UserBookID: book.UserBookID,
+			Title:      book.Title,
+			Slug:       book.Slug,
+			EditionID:  book.EditionID,
+			ASIN:       book.EditionASIN,
+			ISBN13:     book.EditionISBN13,
+			ISBN10:     book.EditionISBN10,
+			Reason:     reason,
+		})
+	}
+
+	return result, nil
+}
+
+// normalizeIdentifier returns an ASIN or ISBN in a form that can be compared across services
+func normalizeIdentifier(id string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(id)))
+}
+
+// runShelfDiff prints the Hardcover books with the given status that have no Audiobookshelf item
+func runShelfDiff(ctx context.Context, hc userBookLister, abs libraryItemLister, status string, jsonOutput bool) {
+	log := logger.Get()
+	result, err := diffShelf(ctx, hc, abs, status)
+	if err != nil {
+		log.Error(fmt.Sprintf("Failed to diff shelf: %v (status: %s)", err, status))
+		os.Exit(1)
+	}
+
+	if jsonOutput {
+		printJSON(result)
+		return
+	}
+
+	fmt.Printf("%d of %d Hardcover books with status '%s' have no matching Audiobookshelf item:\n",
+		len(result.Orphans), result.Total, status)
+	for i, orphan := range result.Orphans {
+		fmt.Printf("%d. %s (book ID: %s, user book ID: %s) - %s\n", i+1, orphan.Title, orphan.BookID, orphan.UserBookID, orphan.Reason)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/audiobookshelf"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeUserBookLister returns fixed Hardcover books for a status
+type fakeUserBookLister struct {
+	books map[string][]models.HardcoverBook
+}
+
+func (f *fakeUserBookLister) ListUserBooks(ctx context.Context, status string) ([]models.HardcoverBook, error) {
+	return f.books[status], nil
+}
+
+// fakeLibraryItemLister returns fixed Audiobookshelf items per library
+type fakeLibraryItemLister struct {
+	items map[string][]models.AudiobookshelfBook
+	err   error
+}
+
+func (f *fakeLibraryItemLister) GetLibraries(ctx context.Context) ([]audiobookshelf.AudiobookshelfLibrary, error) {
+	var libraries []audiobookshelf.AudiobookshelfLibrary
+	for id := range f.items {
+		libraries = append(libraries, audiobookshelf.AudiobookshelfLibrary{ID: id, Name: id})
+	}
+	return libraries, nil
+}
+
+func (f *fakeLibraryItemLister) GetLibraryItems(ctx context.Context, libraryID string) ([]models.AudiobookshelfBook, error) {
+	return f.items[libraryID], f.err
+}
+
+func newLibraryItem(id, asin, isbn string) models.AudiobookshelfBook {
+	item := models.AudiobookshelfBook{ID: id}
+	item.Media.Metadata.ASIN = asin
+	item.Media.Metadata.ISBN = isbn
+	return item
+}
+
+func TestDiffShelf(t *testing.T) {
+	hc := &fakeUserBookLister{books: map[string][]models.HardcoverBook{
+		"reading": {
+			{ID: "1", UserBookID: "11", Title: "Matched by ASIN", EditionASIN: "b00asin001"},
+			{ID: "2", UserBookID: "12", Title: "Matched by ISBN", EditionISBN13: "9781234567897"},
+			{ID: "3", UserBookID: "13", Title: "Orphaned", EditionASIN: "B00GONE001", EditionISBN13: "9780000000002"},
+			{ID: "4", UserBookID: "14", Title: "No Identifiers"},
+		},
+	}}
+	abs := &fakeLibraryItemLister{items: map[string][]models.AudiobookshelfBook{
+		"audiobooks": {newLibraryItem("abs-1", "B00ASIN001", "")},
+		"ebooks":     {newLibraryItem("abs-2", "", "978-1-234567-89-7"), newLibraryItem("abs-3", "", "")},
+	}}
+
+	result, err := diffShelf(context.Background(), hc, abs, "reading")
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, result.Total)
+	assert.Equal(t, 2, result.Matched)
+	require.Len(t, result.Orphans, 2)
+	assert.Equal(t, "3", result.Orphans[0].BookID)
+	assert.Equal(t, "13", result.Orphans[0].UserBookID)
+	assert.Equal(t, shelfDiffNotInAudiobookshelf, result.Orphans[0].Reason)
+	assert.Equal(t, "4", result.Orphans[1].BookID)
+	assert.Equal(t, shelfDiffNoIdentifiers, result.Orphans[1].Reason)
+
+	// An empty shelf has no orphans
+	result, err = diffShelf(context.Background(), hc, abs, "read")
+	require.NoError(t, err)
+	assert.Zero(t, result.Total)
+	assert.Empty(t, result.Orphans)
+}
+
+func TestDiffShelf_LibraryError(t *testing.T) {
+	hc := &fakeUserBookLister{}
+	abs := &fakeLibraryItemLister{
+		items: map[string][]models.AudiobookshelfBook{"audiobooks": nil},
+		err:   errors.New("connection refused"),
+	}
+
+	_, err := diffShelf(context.Background(), hc, abs, "reading")
+	assert.ErrorContains(t, err, "connection refused")
+}
@@ -0,0 +1,85 @@
+package audiobookshelf
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_AuthScheme(t *testing.T) {
+	tests := []struct {
+		name        string
+		scheme      string
+		checkAuthed func(t *testing.T, r *http.Request)
+	}{
+		{
+			name:   "default",
+			scheme: "",
+			checkAuthed: func(t *testing.T, r *http.Request) {
+				assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+				assert.Empty(t, r.Header.Get("x-api-key"))
+				assert.Empty(t, r.URL.Query().Get("token"))
+			},
+		},
+		{
+			name:   "bearer",
+			scheme: AuthSchemeBearer,
+			checkAuthed: func(t *testing.T, r *http.Request) {
+				assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+			},
+		},
+		{
+			name:   "query",
+			scheme: AuthSchemeQuery,
+			checkAuthed: func(t *testing.T, r *http.Request) {
+				assert.Equal(t, "test-token", r.URL.Query().Get("token"))
+				assert.Empty(t, r.Header.Get("Authorization"))
+			},
+		},
+		{
+			name:   "x-api-key",
+			scheme: AuthSchemeAPIKey,
+			checkAuthed: func(t *testing.T, r *http.Request) {
+				assert.Equal(t, "test-token", r.Header.Get("x-api-key"))
+				assert.Empty(t, r.Header.Get("Authorization"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []*http.Request
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r)
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/api/libraries":
+					_, _ = w.Write([]byte(`{"libraries":[]}`))
+				default:
+					_, _ = w.Write([]byte(`[]`))
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, "test-token")
+			client.SetAuthScheme(tt.scheme)
+
+			_, err := client.GetLibraries(context.Background())
+			require.NoError(t, err)
+			_, err = client.GetListeningSessions(context.Background(), time.Unix(1700000000, 0))
+			require.NoError(t, err)
+
+			require.Len(t, requests, 2)
+			for _, r := range requests {
+				tt.checkAuthed(t, r)
+			}
+			// Existing query parameters are kept when the token is added to the query
+			assert.Equal(t, "1700000000000", requests[1].URL.Query().Get("since"))
+		})
+	}
+}
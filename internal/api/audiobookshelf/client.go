@@ -4,14 +4,18 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"time"
 
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/tracing"
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
 )
 
@@ -19,10 +23,38 @@ const (
 	apiPath = "/api"
 )
 
+// ErrUnauthorized is returned when Audiobookshelf rejects the API token
+var ErrUnauthorized = errors.New("audiobookshelf authentication failed")
+
+// statusError returns the error for an unexpected HTTP status code
+func statusError(statusCode int) error {
+	if statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden {
+		return fmt.Errorf("%w: unexpected status code: %d", ErrUnauthorized, statusCode)
+	}
+	return fmt.Errorf("unexpected status code: %d", statusCode)
+}
+
 // AudiobookshelfLibrary represents a library in Audiobookshelf
 type AudiobookshelfLibrary struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	MediaType string `json:"mediaType"`
+}
+
+// AudiobookshelfCollection represents a collection of books in Audiobookshelf
+type AudiobookshelfCollection struct {
+	ID        string                      `json:"id"`
+	LibraryID string                      `json:"libraryId"`
+	Name      string                      `json:"name"`
+	Books     []models.AudiobookshelfBook `json:"books"`
+}
+
+// AudiobookshelfUser represents a user account in Audiobookshelf
+type AudiobookshelfUser struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Type     string `json:"type"`
+	IsActive bool   `json:"isActive"`
 }
 
 // Client is a client for the Audiobookshelf API
@@ -31,8 +63,24 @@ type Client struct {
 	token   string
 	client  *http.Client
 	logger  *logger.Logger
+	// fullItems requests full library item payloads instead of minified ones
+	fullItems bool
+	// userID is the user whose progress is read instead of the token owner's (see ForUser)
+	userID string
+	// authScheme is how the token is sent with each request (see SetAuthScheme)
+	authScheme string
 }
 
+// Ways of sending the API token to Audiobookshelf, see SetAuthScheme
+const (
+	// AuthSchemeBearer sends the token in an "Authorization: Bearer" header
+	AuthSchemeBearer = "bearer"
+	// AuthSchemeQuery sends the token as the "token" query parameter, for older servers
+	AuthSchemeQuery = "query"
+	// AuthSchemeAPIKey sends the token in an "x-api-key" header
+	AuthSchemeAPIKey = "x-api-key"
+)
+
 // NewClient creates a new Audiobookshelf client
 func NewClient(baseURL, token string) *Client {
 	log := logger.Get()
@@ -44,12 +92,112 @@ func NewClient(baseURL, token string) *Client {
 		baseURL: baseURL,
 		token:   token,
 		client: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: tracing.Transport("audiobookshelf", nil),
 		},
 		logger: log,
 	}
 }
 
+// SetFullItems controls whether library items are fetched with their full payload (audio files,
+// chapters, tracks, ...) instead of the minified payload, which holds every field the sync uses
+func (c *Client) SetFullItems(full bool) {
+	c.fullItems = full
+}
+
+// SetAuthScheme sets how the API token is sent with each request, one of the AuthScheme*
+// constants. Different Audiobookshelf versions accept different schemes; the default is
+// AuthSchemeBearer.
+func (c *Client) SetAuthScheme(scheme string) {
+	c.authScheme = scheme
+}
+
+// authorize adds the API token to the request using the configured auth scheme
+func (c *Client) authorize(req *http.Request) {
+	switch c.authScheme {
+	case AuthSchemeQuery:
+		query := req.URL.Query()
+		query.Set("token", c.token)
+		req.URL.RawQuery = query.Encode()
+	case AuthSchemeAPIKey:
+		req.Header.Set("x-api-key", c.token)
+	default:
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+}
+
+// ForUser returns a copy of the client that reads the progress of the given user instead of the
+// token owner's, using the /users/{id} endpoints that require an admin token. Library items are
+// fetched without the token owner's progress so it can't be mistaken for the user's.
+func (c *Client) ForUser(userID string) *Client {
+	userClient := *c
+	userClient.userID = userID
+	userClient.logger = c.logger.With(map[string]interface{}{
+		"abs_user_id": userID,
+	})
+	return &userClient
+}
+
+// GetUsers fetches all user accounts from Audiobookshelf. This requires an admin token.
+func (c *Client) GetUsers(ctx context.Context) ([]AudiobookshelfUser, error) {
+	const endpoint = "/users"
+	log := c.logger.With(map[string]interface{}{
+		"endpoint": endpoint,
+	})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+apiPath+endpoint, nil)
+	if err != nil {
+		log.Error("Failed to create request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.authorize(req)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		log.Error("Request failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Error("Failed to read response body", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error("Unexpected status code", map[string]interface{}{
+			"status": resp.StatusCode,
+			"body":   string(body),
+		})
+		return nil, statusError(resp.StatusCode)
+	}
+
+	var result struct {
+		Users []AudiobookshelfUser `json:"users"`
+	}
+
+	if err := json.Unmarshal(body, &result); err != nil {
+		log.Error("Failed to decode response", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	log.Info("Successfully fetched users", map[string]interface{}{
+		"count": len(result.Users),
+	})
+	return result.Users, nil
+}
+
 // GetLibraries fetches all libraries from Audiobookshelf
 func (c *Client) GetLibraries(ctx context.Context) ([]AudiobookshelfLibrary, error) {
 	const endpoint = "/libraries"
@@ -65,7 +213,7 @@ func (c *Client) GetLibraries(ctx context.Context) ([]AudiobookshelfLibrary, err
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.token)
+	c.authorize(req)
 	req.Header.Set("Accept", "application/json")
 
 	resp, err := c.client.Do(req)
@@ -90,7 +238,7 @@ func (c *Client) GetLibraries(ctx context.Context) ([]AudiobookshelfLibrary, err
 			"status": resp.StatusCode,
 			"body":   string(body),
 		})
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, statusError(resp.StatusCode)
 	}
 
 	var result struct {
@@ -112,10 +260,172 @@ func (c *Client) GetLibraries(ctx context.Context) ([]AudiobookshelfLibrary, err
 
 // GetLibraryItems returns all library items from a specific Audiobookshelf library
 func (c *Client) GetLibraryItems(ctx context.Context, libraryID string) ([]models.AudiobookshelfBook, error) {
+	items, _, err := c.fetchLibraryItems(ctx, libraryID, nil)
+	return items, err
+}
+
+// GetLibraryItemsUpdatedSince returns the library items that were updated, or whose progress
+// changed, since the given time. The updatedSince parameter lets servers that support it filter
+// server-side; the result is always filtered client-side as well for servers that ignore it.
+func (c *Client) GetLibraryItemsUpdatedSince(ctx context.Context, libraryID string, since time.Time) ([]models.AudiobookshelfBook, error) {
+	query := url.Values{}
+	query.Set("updatedSince", strconv.FormatInt(since.UnixMilli(), 10))
+
+	items, _, err := c.fetchLibraryItems(ctx, libraryID, query)
+	if err != nil {
+		return nil, err
+	}
+	return c.filterUpdatedSince(ctx, libraryID, items, since)
+}
+
+// GetLibraryItemsPage returns a page of up to limit items of a library, counting pages from 0, and
+// whether the library has more pages. With a non-zero since, only items updated since then are
+// returned, like GetLibraryItemsUpdatedSince. Servers that ignore paging return all items at once.
+func (c *Client) GetLibraryItemsPage(ctx context.Context, libraryID string, since time.Time, page, limit int) ([]models.AudiobookshelfBook, bool, error) {
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("page", strconv.Itoa(page))
+	if !since.IsZero() {
+		query.Set("updatedSince", strconv.FormatInt(since.UnixMilli(), 10))
+	}
+
+	items, total, err := c.fetchLibraryItems(ctx, libraryID, query)
+	if err != nil {
+		return nil, false, err
+	}
+	// A server that ignores the limit returns more items than asked for
+	more := len(items) == limit && (page+1)*limit < total
+
+	if !since.IsZero() {
+		items, err = c.filterUpdatedSince(ctx, libraryID, items, since)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	return items, more, nil
+}
+
+// filterUpdatedSince returns the items that were updated, or whose progress changed, since the
+// given time
+func (c *Client) filterUpdatedSince(ctx context.Context, libraryID string, items []models.AudiobookshelfBook, since time.Time) ([]models.AudiobookshelfBook, error) {
+	sinceMs := since.UnixMilli()
+
+	// Items fetched for another user don't carry their progress, so take its update times from the
+	// user's own progress instead
+	var userProgressUpdates map[string]int64
+	if c.userID != "" {
+		progress, err := c.GetUserProgress(ctx)
+		if err != nil {
+			return nil, err
+		}
+		userProgressUpdates = make(map[string]int64, len(progress.MediaProgress))
+		for _, p := range progress.MediaProgress {
+			userProgressUpdates[p.LibraryItemID] = p.LastUpdate
+		}
+	}
+
+	filtered := make([]models.AudiobookshelfBook, 0, len(items))
+	for _, item := range items {
+		progressUpdate := item.Progress.LastUpdate
+		if userProgressUpdates[item.ID] > progressUpdate {
+			progressUpdate = userProgressUpdates[item.ID]
+		}
+
+		// Keep items without timestamps, since we can't tell whether they changed
+		if item.UpdatedAt == 0 && progressUpdate == 0 {
+			filtered = append(filtered, item)
+			continue
+		}
+		if item.UpdatedAt >= sinceMs || progressUpdate >= sinceMs {
+			filtered = append(filtered, item)
+		}
+	}
+
+	c.logger.Debug("Filtered library items by update time", map[string]interface{}{
+		"library_id":     libraryID,
+		"since":          since.Format(time.RFC3339),
+		"returned_count": len(items),
+		"filtered_count": len(filtered),
+	})
+
+	return filtered, nil
+}
+
+// GetLibraryItem fetches a single library item with the token owner's progress, which the item
+// endpoint returns as userMediaProgress. Items fetched for another user with ForUser don't carry
+// any progress, like library items.
+func (c *Client) GetLibraryItem(ctx context.Context, itemID string) (*models.AudiobookshelfBook, error) {
+	if itemID == "" {
+		return nil, fmt.Errorf("library item ID is required")
+	}
+	endpoint := "/items/" + url.PathEscape(itemID)
+	if c.userID == "" {
+		endpoint += "?include=progress"
+	}
+	log := c.logger.With(map[string]interface{}{
+		"endpoint": endpoint,
+	})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+apiPath+endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	c.authorize(req)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		log.Error("Failed to fetch library item", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, fmt.Errorf("failed to fetch library item: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		log.Error("Unexpected status code", map[string]interface{}{
+			"status":   resp.StatusCode,
+			"response": string(body),
+		})
+		return nil, statusError(resp.StatusCode)
+	}
+
+	var item struct {
+		models.AudiobookshelfBook
+		UserMediaProgress *models.AudiobookshelfProgress `json:"userMediaProgress"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&item); err != nil {
+		return nil, fmt.Errorf("failed to decode library item: %w", err)
+	}
+
+	book := item.AudiobookshelfBook
+	if item.UserMediaProgress != nil {
+		book.Progress = *item.UserMediaProgress
+	}
+	return &book, nil
+}
+
+// fetchLibraryItems fetches the items of a library, adding the given query parameters to the
+// request, and returns them with the total number of items the server reports
+func (c *Client) fetchLibraryItems(ctx context.Context, libraryID string, extraQuery url.Values) ([]models.AudiobookshelfBook, int, error) {
 	if libraryID == "" {
-		return nil, fmt.Errorf("library ID is required")
+		return nil, 0, fmt.Errorf("library ID is required")
+	}
+	// The minified payload contains the ID, media metadata, duration and progress the sync needs
+	// while leaving out audio files, chapters and tracks, which dominate the size of large libraries
+	minified := "1"
+	if c.fullItems {
+		minified = "0"
+	}
+	endpoint := fmt.Sprintf("/libraries/%s/items?include=progress&minified=%s", libraryID, minified)
+	if c.userID != "" {
+		// The included progress is always the token owner's
+		endpoint = fmt.Sprintf("/libraries/%s/items?minified=%s", libraryID, minified)
+	}
+	if len(extraQuery) > 0 {
+		endpoint += "&" + extraQuery.Encode()
 	}
-	endpoint := fmt.Sprintf("/libraries/%s/items?include=progress&minified=0", libraryID)
 	log := c.logger.With(map[string]interface{}{
 		"endpoint": endpoint,
 	})
@@ -126,11 +436,11 @@ func (c *Client) GetLibraryItems(ctx context.Context, libraryID string) ([]model
 		log.Error("Failed to create request", map[string]interface{}{
 			"error": err.Error(),
 		})
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, 0, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Set headers
-	req.Header.Set("Authorization", "Bearer "+c.token)
+	c.authorize(req)
 	req.Header.Set("Accept", "application/json")
 
 	// Execute request
@@ -140,7 +450,7 @@ func (c *Client) GetLibraryItems(ctx context.Context, libraryID string) ([]model
 		log.Error("Failed to fetch library items", map[string]interface{}{
 			"error": err.Error(),
 		})
-		return nil, fmt.Errorf("failed to fetch library items: %w", err)
+		return nil, 0, fmt.Errorf("failed to fetch library items: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -151,7 +461,7 @@ func (c *Client) GetLibraryItems(ctx context.Context, libraryID string) ([]model
 			"status":   resp.StatusCode,
 			"response": string(body),
 		})
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, 0, statusError(resp.StatusCode)
 	}
 
 	// Read the response body
@@ -160,7 +470,7 @@ func (c *Client) GetLibraryItems(ctx context.Context, libraryID string) ([]model
 		log.Error("Failed to read response body", map[string]interface{}{
 			"error": err.Error(),
 		})
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, 0, fmt.Errorf("failed to read response body: %w", err)
 	}
 
 	// Save the raw response to a file for inspection
@@ -269,7 +579,7 @@ func (c *Client) GetLibraryItems(ctx context.Context, libraryID string) ([]model
 			"error":           err.Error(),
 			"response_sample": string(body[:sampleSize]),
 		})
-		return nil, fmt.Errorf("failed to decode response into raw map: %w", err)
+		return nil, 0, fmt.Errorf("failed to decode response into raw map: %w", err)
 	}
 
 	// Log the top-level keys in the response
@@ -287,7 +597,7 @@ func (c *Client) GetLibraryItems(ctx context.Context, libraryID string) ([]model
 		log.Error("No 'results' key in API response", map[string]interface{}{
 			"response": rawResponse,
 		})
-		return nil, fmt.Errorf("no 'results' key in API response")
+		return nil, 0, fmt.Errorf("no 'results' key in API response")
 	}
 
 	// Log the type of the results value
@@ -301,7 +611,7 @@ func (c *Client) GetLibraryItems(ctx context.Context, libraryID string) ([]model
 		log.Error("Failed to marshal results", map[string]interface{}{
 			"error": err.Error(),
 		})
-		return nil, fmt.Errorf("failed to marshal results: %w", err)
+		return nil, 0, fmt.Errorf("failed to marshal results: %w", err)
 	}
 
 	var books []models.AudiobookshelfBook
@@ -309,7 +619,7 @@ func (c *Client) GetLibraryItems(ctx context.Context, libraryID string) ([]model
 		log.Error("Failed to process library item", map[string]interface{}{
 			"error": err.Error(),
 		})
-		return nil, fmt.Errorf("failed to unmarshal results into books: %w", err)
+		return nil, 0, fmt.Errorf("failed to unmarshal results into books: %w", err)
 	}
 
 	// Log the first book's raw data for debugging
@@ -351,12 +661,22 @@ func (c *Client) GetLibraryItems(ctx context.Context, libraryID string) ([]model
 		})
 	}
 
-	return result.Results, nil
+	// Servers that don't report a total returned all items
+	total := len(result.Results)
+	if t, ok := rawResponse["total"].(float64); ok {
+		total = int(t)
+	}
+
+	return result.Results, total, nil
 }
 
-// GetUserProgress fetches the current user's progress data from Audiobookshelf
+// GetUserProgress fetches the current user's progress data from Audiobookshelf, or that of the
+// user selected with ForUser
 func (c *Client) GetUserProgress(ctx context.Context) (*models.AudiobookshelfUserProgress, error) {
-	const endpoint = "/me"
+	endpoint := "/me"
+	if c.userID != "" {
+		endpoint = "/users/" + url.PathEscape(c.userID)
+	}
 	log := c.logger.With(map[string]interface{}{
 		"endpoint": endpoint,
 	})
@@ -367,7 +687,7 @@ func (c *Client) GetUserProgress(ctx context.Context) (*models.AudiobookshelfUse
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.token)
+	c.authorize(req)
 	req.Header.Set("Accept", "application/json")
 
 	resp, err := c.client.Do(req)
@@ -388,7 +708,7 @@ func (c *Client) GetUserProgress(ctx context.Context) (*models.AudiobookshelfUse
 			"status":   resp.StatusCode,
 			"response": string(body),
 		})
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, statusError(resp.StatusCode)
 	}
 
 	var progress models.AudiobookshelfUserProgress
@@ -407,9 +727,113 @@ func (c *Client) GetUserProgress(ctx context.Context) (*models.AudiobookshelfUse
 	return &progress, nil
 }
 
-// GetListeningSessions fetches recent listening sessions from Audiobookshelf
+// GetCollections fetches all collections from Audiobookshelf with the library items in them.
+// Collections belong to libraries rather than users, so the same ones are returned for every user.
+func (c *Client) GetCollections(ctx context.Context) ([]AudiobookshelfCollection, error) {
+	endpoint := "/collections"
+	log := c.logger.With(map[string]interface{}{
+		"endpoint": endpoint,
+	})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+apiPath+endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	c.authorize(req)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		log.Error("Failed to fetch collections", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, fmt.Errorf("failed to fetch collections: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		log.Error("Unexpected status code", map[string]interface{}{
+			"status":   resp.StatusCode,
+			"response": string(body),
+		})
+		return nil, statusError(resp.StatusCode)
+	}
+
+	var result struct {
+		Collections []AudiobookshelfCollection `json:"collections"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode collections: %w", err)
+	}
+
+	log.Debug("Fetched collections", map[string]interface{}{
+		"count": len(result.Collections),
+	})
+	return result.Collections, nil
+}
+
+// UpdateMediaProgress sets the token owner's progress of a library item to currentTime seconds,
+// marking it finished or not. Audiobookshelf only lets users update their own progress, so a client
+// reading another user's progress with ForUser can't write it.
+func (c *Client) UpdateMediaProgress(ctx context.Context, itemID string, currentTime float64, isFinished bool) error {
+	if itemID == "" {
+		return fmt.Errorf("library item ID is required")
+	}
+	if c.userID != "" {
+		return fmt.Errorf("progress of user %s can't be updated, only that of the token owner", c.userID)
+	}
+	endpoint := "/me/progress/" + url.PathEscape(itemID)
+	log := c.logger.With(map[string]interface{}{
+		"endpoint":     endpoint,
+		"current_time": currentTime,
+		"is_finished":  isFinished,
+	})
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"currentTime": currentTime,
+		"isFinished":  isFinished,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode progress: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, c.baseURL+apiPath+endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	c.authorize(req)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		log.Error("Failed to update media progress", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return fmt.Errorf("failed to update media progress: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		log.Error("Unexpected status code", map[string]interface{}{
+			"status":   resp.StatusCode,
+			"response": string(body),
+		})
+		return statusError(resp.StatusCode)
+	}
+
+	log.Debug("Updated media progress", nil)
+	return nil
+}
+
+// GetListeningSessions fetches recent listening sessions from Audiobookshelf, or those of the
+// user selected with ForUser
 func (c *Client) GetListeningSessions(ctx context.Context, since time.Time) ([]models.AudiobookshelfBook, error) {
-	const endpoint = "/me/listening-sessions"
+	endpoint := "/me/listening-sessions"
+	if c.userID != "" {
+		endpoint = "/users/" + url.PathEscape(c.userID) + "/listening-sessions"
+	}
 	log := c.logger.With(map[string]interface{}{
 		"endpoint": endpoint,
 		"since":    since,
@@ -428,7 +852,7 @@ func (c *Client) GetListeningSessions(ctx context.Context, since time.Time) ([]m
 	}
 
 	// Set headers
-	req.Header.Set("Authorization", "Bearer "+c.token)
+	c.authorize(req)
 	req.Header.Set("Accept", "application/json")
 
 	// Execute request
@@ -449,7 +873,7 @@ func (c *Client) GetListeningSessions(ctx context.Context, since time.Time) ([]m
 			"status":   resp.StatusCode,
 			"response": string(body),
 		})
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, statusError(resp.StatusCode)
 	}
 
 	// Parse response
@@ -12,8 +12,13 @@ import (
 type AudiobookshelfClientInterface interface {
 	GetLibraries(ctx context.Context) ([]AudiobookshelfLibrary, error)
 	GetLibraryItems(ctx context.Context, libraryID string) ([]models.AudiobookshelfBook, error)
+	GetLibraryItemsUpdatedSince(ctx context.Context, libraryID string, since time.Time) ([]models.AudiobookshelfBook, error)
+	GetLibraryItemsPage(ctx context.Context, libraryID string, since time.Time, page, limit int) ([]models.AudiobookshelfBook, bool, error)
+	GetLibraryItem(ctx context.Context, itemID string) (*models.AudiobookshelfBook, error)
 	GetUserProgress(ctx context.Context) (*models.AudiobookshelfUserProgress, error)
 	GetListeningSessions(ctx context.Context, since time.Time) ([]models.AudiobookshelfBook, error)
+	GetCollections(ctx context.Context) ([]AudiobookshelfCollection, error)
+	UpdateMediaProgress(ctx context.Context, itemID string, currentTime float64, isFinished bool) error
 }
 
 // Ensure that the Client implements AudiobookshelfClientInterface
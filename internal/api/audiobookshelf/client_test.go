@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 
@@ -150,6 +151,191 @@ func TestGetLibraryItems(t *testing.T) {
 	}
 }
 
+func TestGetLibraryItemsFieldSelection(t *testing.T) {
+	newServer := func(t *testing.T, expectedMinified string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, expectedMinified, r.URL.Query().Get("minified"))
+			assert.Equal(t, "progress", r.URL.Query().Get("include"))
+
+			// A minified item as returned by Audiobookshelf
+			response := map[string]interface{}{
+				"results": []map[string]interface{}{
+					{
+						"id":        "li_1",
+						"libraryId": "lib_1",
+						"mediaType": "book",
+						"media": map[string]interface{}{
+							"id": "media_1",
+							"metadata": map[string]interface{}{
+								"title":        "Project Hail Mary",
+								"authorName":   "Andy Weir",
+								"narratorName": "Ray Porter",
+								"asin":         "B08G9PRS1K",
+							},
+							"coverPath":     "/covers/li_1.jpg",
+							"duration":      58000.5,
+							"numTracks":     12,
+							"numAudioFiles": 12,
+						},
+						"progress": map[string]interface{}{
+							"currentTime": 1200.0,
+							"isFinished":  false,
+						},
+					},
+				},
+			}
+			w.Header().Set("Content-Type", "application/json")
+			require.NoError(t, json.NewEncoder(w).Encode(response))
+		}))
+	}
+
+	t.Run("requests minified items by default", func(t *testing.T) {
+		server := newServer(t, "1")
+		defer server.Close()
+
+		items, err := NewClient(server.URL, "test-token").GetLibraryItems(context.Background(), "lib_1")
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+
+		item := items[0]
+		assert.Equal(t, "li_1", item.ID)
+		assert.Equal(t, "media_1", item.Media.ID)
+		assert.Equal(t, "Project Hail Mary", item.Media.Metadata.Title)
+		assert.Equal(t, "Andy Weir", item.Media.Metadata.AuthorName)
+		assert.Equal(t, "Ray Porter", item.Media.Metadata.NarratorName)
+		assert.Equal(t, "B08G9PRS1K", item.Media.Metadata.ASIN)
+		assert.Equal(t, 58000.5, item.Media.Duration)
+		assert.Equal(t, 1200.0, item.Progress.CurrentTime)
+	})
+
+	t.Run("full items can be requested", func(t *testing.T) {
+		server := newServer(t, "0")
+		defer server.Close()
+
+		client := NewClient(server.URL, "test-token")
+		client.SetFullItems(true)
+		items, err := client.GetLibraryItems(context.Background(), "lib_1")
+		require.NoError(t, err)
+		assert.Len(t, items, 1)
+	})
+}
+
+func TestGetLibraryItemsUpdatedSince(t *testing.T) {
+	since := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	sinceMs := since.UnixMilli()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/libraries/1/items", r.URL.Path)
+		assert.Equal(t, "progress", r.URL.Query().Get("include"))
+		assert.Equal(t, strconv.FormatInt(sinceMs, 10), r.URL.Query().Get("updatedSince"))
+
+		// Simulate a server that ignores updatedSince and returns everything
+		response := map[string]interface{}{
+			"results": []map[string]interface{}{
+				{"id": "updated", "updatedAt": sinceMs + 1000},
+				{"id": "progressed", "updatedAt": sinceMs - 1000, "progress": map[string]interface{}{"lastUpdate": sinceMs + 5000}},
+				{"id": "unchanged", "updatedAt": sinceMs - 1000, "progress": map[string]interface{}{"lastUpdate": sinceMs - 5000}},
+				{"id": "no-timestamps"},
+			},
+			"total": 4,
+		}
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(response))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	items, err := client.GetLibraryItemsUpdatedSince(context.Background(), "1", since)
+	require.NoError(t, err)
+
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	assert.Equal(t, []string{"updated", "progressed", "no-timestamps"}, ids)
+}
+
+func TestGetLibraryItemsPage(t *testing.T) {
+	ids := []string{"li_1", "li_2", "li_3", "li_4", "li_5"}
+	ignorePaging := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/libraries/1/items", r.URL.Path)
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		require.NoError(t, err)
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		require.NoError(t, err)
+
+		var results []map[string]interface{}
+		for i, id := range ids {
+			if ignorePaging || (i >= page*limit && i < (page+1)*limit) {
+				results = append(results, map[string]interface{}{"id": id})
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{
+			"results": results,
+			"total":   len(ids),
+		}))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	var fetched []string
+	for page := 0; ; page++ {
+		items, more, err := client.GetLibraryItemsPage(context.Background(), "1", time.Time{}, page, 2)
+		require.NoError(t, err)
+		for _, item := range items {
+			fetched = append(fetched, item.ID)
+		}
+		if !more {
+			break
+		}
+	}
+	assert.Equal(t, ids, fetched)
+
+	// All items of a server ignoring the limit are on the first page
+	ignorePaging = true
+	items, more, err := client.GetLibraryItemsPage(context.Background(), "1", time.Time{}, 0, 2)
+	require.NoError(t, err)
+	assert.Len(t, items, 5)
+	assert.False(t, more)
+}
+
+func TestGetLibraryItem(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/items/li_1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		assert.Equal(t, "progress", r.URL.Query().Get("include"))
+
+		response := map[string]interface{}{
+			"id":        "li_1",
+			"libraryId": "lib1",
+			"mediaType": "book",
+			"media": map[string]interface{}{
+				"duration": 3600,
+				"metadata": map[string]interface{}{"title": "Test Book", "asin": "B000TEST01"},
+			},
+			"userMediaProgress": map[string]interface{}{"currentTime": 1800, "lastUpdate": 1700000000000},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(response))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	item, err := client.GetLibraryItem(context.Background(), "li_1")
+	require.NoError(t, err)
+	assert.Equal(t, "lib1", item.LibraryID)
+	assert.Equal(t, "Test Book", item.Media.Metadata.Title)
+	assert.Equal(t, 1800.0, item.Progress.CurrentTime, "the user's progress is taken from userMediaProgress")
+	assert.Equal(t, int64(1700000000000), item.Progress.LastUpdate)
+
+	_, err = client.GetLibraryItem(context.Background(), "missing")
+	assert.Error(t, err)
+}
+
 func TestGetUserProgress(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -169,17 +355,19 @@ func TestGetUserProgress(t *testing.T) {
 						ID:       "user1",
 						Username: "testuser",
 						MediaProgress: []struct {
-							ID            string  `json:"id"`
-							LibraryItemID string  `json:"libraryItemId"`
-							UserID        string  `json:"userId"`
-							IsFinished    bool    `json:"isFinished"`
-							Progress      float64 `json:"progress"`
-							CurrentTime   float64 `json:"currentTime"`
-							Duration      float64 `json:"duration"`
-							StartedAt     int64   `json:"startedAt"`
-							FinishedAt    int64   `json:"finishedAt"`
-							LastUpdate    int64   `json:"lastUpdate"`
-							TimeListening float64 `json:"timeListening"`
+							ID                        string  `json:"id"`
+							LibraryItemID             string  `json:"libraryItemId"`
+							UserID                    string  `json:"userId"`
+							IsFinished                bool    `json:"isFinished"`
+							Progress                  float64 `json:"progress"`
+							CurrentTime               float64 `json:"currentTime"`
+							Duration                  float64 `json:"duration"`
+							StartedAt                 int64   `json:"startedAt"`
+							FinishedAt                int64   `json:"finishedAt"`
+							LastUpdate                int64   `json:"lastUpdate"`
+							TimeListening             float64 `json:"timeListening"`
+							EbookProgress             float64 `json:"ebookProgress"`
+							HideFromContinueListening bool    `json:"hideFromContinueListening"`
 						}{
 							{
 								ID:            "progress1",
@@ -288,6 +476,7 @@ func TestGetListeningSessions(t *testing.T) {
 								Metadata models.AudiobookshelfMetadataStruct `json:"metadata"`
 								CoverPath string                       `json:"coverPath"`
 								Duration  float64                      `json:"duration"`
+								Tags      []string                     `json:"tags,omitempty"`
 							}{
 								Metadata: models.AudiobookshelfMetadataStruct{
 									Title: "Test Book",
@@ -326,3 +515,151 @@ func TestGetListeningSessions(t *testing.T) {
 		})
 	}
 }
+
+func TestGetUsers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/users", r.URL.Path)
+		assert.Equal(t, "Bearer admin-token", r.Header.Get("Authorization"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{"users":[
+			{"id":"root","username":"admin","type":"root","isActive":true},
+			{"id":"usr_1","username":"alice","type":"user","isActive":false}
+		]}`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "admin-token")
+	users, err := client.GetUsers(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []AudiobookshelfUser{
+		{ID: "root", Username: "admin", Type: "root", IsActive: true},
+		{ID: "usr_1", Username: "alice", Type: "user", IsActive: false},
+	}, users)
+
+	t.Run("not an admin", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer server.Close()
+
+		_, err := NewClient(server.URL, "user-token").GetUsers(context.Background())
+		assert.ErrorIs(t, err, ErrUnauthorized)
+	})
+}
+
+func TestClientForUser(t *testing.T) {
+	since := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	sinceMs := since.UnixMilli()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer admin-token", r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/api/users/usr_1":
+			require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{
+				"id":       "usr_1",
+				"username": "alice",
+				"mediaProgress": []map[string]interface{}{
+					{"libraryItemId": "progressed", "currentTime": 60, "lastUpdate": sinceMs + 5000},
+				},
+			}))
+		case "/api/users/usr_1/listening-sessions":
+			require.NoError(t, json.NewEncoder(w).Encode([]map[string]interface{}{{"id": "session"}}))
+		case "/api/libraries/1/items":
+			assert.Empty(t, r.URL.Query().Get("include"), "the admin's progress must not be included")
+			require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{
+				"results": []map[string]interface{}{
+					{"id": "updated", "updatedAt": sinceMs + 1000},
+					{"id": "progressed", "updatedAt": sinceMs - 1000},
+					{"id": "unchanged", "updatedAt": sinceMs - 1000},
+				},
+			}))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	admin := NewClient(server.URL, "admin-token")
+	client := admin.ForUser("usr_1")
+	assert.Empty(t, admin.userID, "the admin client is left unchanged")
+
+	progress, err := client.GetUserProgress(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "alice", progress.Username)
+	require.Len(t, progress.MediaProgress, 1)
+
+	sessions, err := client.GetListeningSessions(context.Background(), since)
+	require.NoError(t, err)
+	assert.Len(t, sessions, 1)
+
+	// Progress changes are taken from the user's progress rather than the library items
+	items, err := client.GetLibraryItemsUpdatedSince(context.Background(), "1", since)
+	require.NoError(t, err)
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	assert.Equal(t, []string{"updated", "progressed"}, ids)
+}
+
+func TestUpdateMediaProgress(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/me/progress/li_1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		assert.Equal(t, http.MethodPatch, r.Method)
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	require.NoError(t, client.UpdateMediaProgress(context.Background(), "li_1", 1234.5, true))
+	assert.Equal(t, 1234.5, body["currentTime"])
+	assert.Equal(t, true, body["isFinished"])
+
+	assert.Error(t, client.UpdateMediaProgress(context.Background(), "missing", 10, false))
+	assert.Error(t, client.UpdateMediaProgress(context.Background(), "", 10, false))
+
+	// Audiobookshelf has no endpoint to write another user's progress
+	assert.Error(t, client.ForUser("usr_1").UpdateMediaProgress(context.Background(), "li_1", 10, false))
+}
+
+func TestGetCollections(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/collections" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{
+			"collections": []map[string]interface{}{{
+				"id":        "col_1",
+				"libraryId": "lib1",
+				"name":      "Favorites",
+				"books": []map[string]interface{}{{
+					"id":    "li_1",
+					"media": map[string]interface{}{"metadata": map[string]interface{}{"title": "Test Book", "asin": "B000TEST01"}},
+				}},
+			}},
+		}))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	collections, err := client.GetCollections(context.Background())
+	require.NoError(t, err)
+	require.Len(t, collections, 1)
+	assert.Equal(t, "Favorites", collections[0].Name)
+	assert.Equal(t, "lib1", collections[0].LibraryID)
+	require.Len(t, collections[0].Books, 1)
+	assert.Equal(t, "B000TEST01", collections[0].Books[0].Media.Metadata.ASIN)
+}
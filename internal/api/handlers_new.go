@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -57,6 +58,8 @@ type CreateProfileRequest struct {
 // UpdateProfileRequest represents the request body for updating a sync profile
 type UpdateProfileRequest struct {
 	Name string `json:"name"`
+	// DryRun enables or disables the profile's read-only preview mode when set
+	DryRun *bool `json:"dry_run,omitempty"`
 }
 
 // UpdateProfileConfigRequest represents the request body for updating sync profile config
@@ -129,6 +132,7 @@ func (h *Handler) buildProfileResponse(p *database.ProfileWithTokens) map[string
 			"created_at": prof.CreatedAt,
 			"updated_at": prof.UpdatedAt,
 			"active":     prof.Active,
+			"dry_run":    prof.DryRun,
 		},
 		"audiobookshelf_url":   p.AudiobookshelfURL,
 		"audiobookshelf_token": p.AudiobookshelfToken,
@@ -155,6 +159,7 @@ func (h *Handler) GetProfiles(w http.ResponseWriter, r *http.Request) {
             "id":         p.ID,
             "name":       p.Name,
             "active":     p.Active,
+            "dry_run":    p.DryRun,
             "created_at": p.CreatedAt,
             "updated_at": p.UpdatedAt,
         }
@@ -261,6 +266,15 @@ func (h *Handler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Toggle the read-only preview mode if requested
+	if req.DryRun != nil {
+		if err := h.multiUserService.SetProfileDryRun(profileID, *req.DryRun); err != nil {
+			h.log.Error("Failed to update sync profile dry run: " + err.Error())
+			h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to update sync profile")
+			return
+		}
+	}
+
 	// Get updated profile
 	profile, err := h.multiUserService.GetProfile(profileID)
 	if err != nil {
@@ -405,6 +419,43 @@ func (h *Handler) StartSync(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// SyncItem handles POST /api/profiles/{id}/sync/item/{itemId}, syncing a single library item
+// and returning the outcome once it's done
+func (h *Handler) SyncItem(w http.ResponseWriter, r *http.Request) {
+	profileID, itemID := h.extractIDsFromSyncItemPath(r.URL.Path)
+	if profileID == "" || itemID == "" {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Profile ID and item ID are required")
+		return
+	}
+
+	profile, err := h.multiUserService.GetProfile(profileID)
+	if err != nil {
+		h.log.Error("Failed to get sync profile: " + err.Error())
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve sync profile")
+		return
+	}
+	if profile == nil {
+		h.writeErrorResponse(w, http.StatusNotFound, "Sync profile not found")
+		return
+	}
+
+	summary, err := h.multiUserService.SyncItem(r.Context(), profileID, itemID)
+	if err != nil {
+		h.log.Error(fmt.Sprintf("Failed to sync item %s for profile %s: %s", itemID, profileID, err.Error()))
+		status := http.StatusInternalServerError
+		if errors.Is(err, multiuser.ErrSyncInProgress) {
+			status = http.StatusConflict
+		}
+		h.writeErrorResponse(w, status, err.Error())
+		return
+	}
+
+	h.writeSuccessResponse(w, map[string]interface{}{
+		"item_id": itemID,
+		"summary": summary,
+	})
+}
+
 // CancelSync handles DELETE /api/profiles/{id}/sync
 func (h *Handler) CancelSync(w http.ResponseWriter, r *http.Request) {
 	profileID := h.extractProfileIDFromSyncPath(r.URL.Path)
@@ -424,6 +475,38 @@ func (h *Handler) CancelSync(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// CreateMismatchEdition handles POST /api/mismatches/{id}/create-edition, creating the missing
+// audiobook edition of a mismatch from its metadata and clearing the mismatch
+func (h *Handler) CreateMismatchEdition(w http.ResponseWriter, r *http.Request) {
+	mismatchID := h.extractMismatchID(r.URL.Path)
+	if mismatchID == "" {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Mismatch ID is required")
+		return
+	}
+
+	result, err := h.multiUserService.CreateMismatchEdition(r.Context(), mismatchID)
+	if err != nil {
+		h.log.Error(fmt.Sprintf("Failed to create edition for mismatch %s: %s", mismatchID, err.Error()))
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, sync.ErrMismatchNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, sync.ErrMismatchWithoutBook), errors.Is(err, sync.ErrMismatchNotMissingEdition):
+			status = http.StatusUnprocessableEntity
+		case errors.Is(err, multiuser.ErrSyncInProgress):
+			status = http.StatusConflict
+		}
+		h.writeErrorResponse(w, status, err.Error())
+		return
+	}
+
+	h.writeSuccessResponse(w, map[string]interface{}{
+		"mismatch_id": mismatchID,
+		"edition_id":  result.EditionID,
+		"result":      result,
+	})
+}
+
 // Helper functions to extract profile ID from URL paths
 
 func (h *Handler) extractProfileID(path string) string {
@@ -436,6 +519,16 @@ func (h *Handler) extractProfileID(path string) string {
 	return ""
 }
 
+func (h *Handler) extractMismatchID(path string) string {
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if part == "mismatches" && i+2 < len(parts) && parts[i+2] == "create-edition" {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
+
 func (h *Handler) extractProfileIDFromConfigPath(path string) string {
 	parts := strings.Split(path, "/")
 	for i, part := range parts {
@@ -466,6 +559,16 @@ func (h *Handler) extractProfileIDFromSyncPath(path string) string {
 	return ""
 }
 
+func (h *Handler) extractIDsFromSyncItemPath(path string) (string, string) {
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if part == "profiles" && i+4 < len(parts) && parts[i+2] == "sync" && parts[i+3] == "item" {
+			return parts[i+1], parts[i+4]
+		}
+	}
+	return "", ""
+}
+
 // HandleCurrentUser returns information about the current sync profile
 // This is a placeholder for future authentication integration
 func (h *Handler) HandleCurrentUser(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,64 @@
+package hardcover
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_AddTagToBook(t *testing.T) {
+	t.Run("sends upsert_tags mutation", func(t *testing.T) {
+		client, server := CreateTestClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+			var req struct {
+				Query     string                 `json:"query"`
+				Variables map[string]interface{} `json:"variables"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+			assert.Contains(t, req.Query, "upsert_tags")
+			assert.Equal(t, float64(123), req.Variables["id"])
+			tags, ok := req.Variables["tags"].([]interface{})
+			require.True(t, ok)
+			require.Len(t, tags, 1)
+			assert.Equal(t, map[string]interface{}{"tag": "abs-sync", "category": "Tag", "spoiler": false}, tags[0])
+
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"upsert_tags": map[string]interface{}{
+						"tags": []map[string]interface{}{{"tag": "abs-sync", "category": "Tag"}},
+					},
+				},
+			})
+		})
+		defer server.Close()
+
+		assert.NoError(t, client.AddTagToBook(context.Background(), 123, "abs-sync"))
+	})
+
+	t.Run("graphql error is returned", func(t *testing.T) {
+		client, server := CreateTestClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"errors": []map[string]interface{}{{"message": "not allowed"}},
+			})
+		})
+		defer server.Close()
+
+		assert.Error(t, client.AddTagToBook(context.Background(), 123, "abs-sync"))
+	})
+
+	t.Run("invalid input is rejected", func(t *testing.T) {
+		client, server := CreateTestClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("no request expected")
+		})
+		defer server.Close()
+
+		assert.Error(t, client.AddTagToBook(context.Background(), 0, "abs-sync"))
+		assert.Error(t, client.AddTagToBook(context.Background(), 123, " "))
+	})
+}
@@ -70,6 +70,80 @@ func TestClient_CheckExistingUserBookRead(t *testing.T) {
 			expectResult:   false,
 			expectedReadID: 0,
 		},
+		{
+			name: "reads created on the date",
+			input: CheckExistingUserBookReadInput{
+				UserBookID: 123,
+				Date:       "2023-01-31",
+			},
+			mockHandler: func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+				var body struct {
+					Query     string                 `json:"query"`
+					Variables map[string]interface{} `json:"variables"`
+				}
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				assert.Contains(t, body.Query, "$dayStart: timestamptz!, $nextDay: timestamptz!")
+				assert.Contains(t, body.Query, "{created_at: {_gte: $dayStart, _lt: $nextDay}}")
+				assert.NotContains(t, body.Query, "interval", "the query must be plain GraphQL")
+				assert.Equal(t, "2023-01-31", body.Variables["date"])
+				assert.Equal(t, "2023-01-31T00:00:00Z", body.Variables["dayStart"])
+				assert.Equal(t, "2023-02-01T00:00:00Z", body.Variables["nextDay"])
+
+				response := map[string]interface{}{
+					"data": map[string]interface{}{
+						"user_book_reads": []map[string]interface{}{},
+					},
+				}
+				w.Header().Set("Content-Type", "application/json")
+				if err := json.NewEncoder(w).Encode(response); err != nil {
+					t.Fatalf("Failed to encode response: %v", err)
+				}
+			},
+			expectError:    false,
+			expectResult:   false,
+			expectedReadID: 0,
+		},
+		{
+			name: "invalid date",
+			input: CheckExistingUserBookReadInput{
+				UserBookID: 123,
+				Date:       "01/31/2023",
+			},
+			mockHandler: func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+				t.Error("no query is sent for an invalid date")
+			},
+			expectError:    true,
+			expectResult:   false,
+			expectedReadID: 0,
+		},
+		{
+			name: "only unfinished reads",
+			input: CheckExistingUserBookReadInput{
+				UserBookID: 123,
+				Date:       "2023-01-01",
+				Unfinished: true,
+			},
+			mockHandler: func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+				var body struct {
+					Query string `json:"query"`
+				}
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				assert.Contains(t, body.Query, "finished_at: {_is_null: true}")
+
+				response := map[string]interface{}{
+					"data": map[string]interface{}{
+						"user_book_reads": []map[string]interface{}{},
+					},
+				}
+				w.Header().Set("Content-Type", "application/json")
+				if err := json.NewEncoder(w).Encode(response); err != nil {
+					t.Fatalf("Failed to encode response: %v", err)
+				}
+			},
+			expectError:    false,
+			expectResult:   false,
+			expectedReadID: 0,
+		},
 		{
 			name: "graphql error",
 			input: CheckExistingUserBookReadInput{
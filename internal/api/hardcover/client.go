@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
 	"sort"
 	"strconv"
@@ -15,10 +16,13 @@ import (
 	"time"
 
 	"github.com/hasura/go-graphql-client"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/cache"
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/metrics"
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/tracing"
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/util"
 )
 
@@ -28,7 +32,7 @@ type ctxKey string
 const ctxKeyReadingFormat ctxKey = "hardcover_reading_format"
 
 // WithReadingFormat returns a context that carries the desired reading format string.
-// Accepted values typically include "audiobook" and "ebook". Case-insensitive.
+// Accepted values are "audiobook", "ebook", "physical" and "both". Case-insensitive.
 // When absent, client defaults to audiobook-only behavior for compatibility.
 func WithReadingFormat(ctx context.Context, format string) context.Context {
 	return context.WithValue(ctx, ctxKeyReadingFormat, strings.ToLower(strings.TrimSpace(format)))
@@ -43,6 +47,22 @@ func getReadingFormatFromCtx(ctx context.Context) (string, bool) {
 	return "", false
 }
 
+// readingFormatIDFromCtx returns the Hardcover reading format ID of the reading format carried by
+// the context, ReadingFormatAudiobook when there's none or it's unknown
+func readingFormatIDFromCtx(ctx context.Context) int {
+	formatStr, _ := getReadingFormatFromCtx(ctx)
+	switch formatStr {
+	case "ebook":
+		return ReadingFormatEbook
+	case "physical":
+		return ReadingFormatPhysical
+	case "both":
+		return ReadingFormatBoth
+	default:
+		return ReadingFormatAudiobook
+	}
+}
+
 // getMapKeys returns a sorted list of keys from a map
 func getMapKeys(m map[string]interface{}) []string {
 	keys := make([]string, 0, len(m))
@@ -83,6 +103,9 @@ var (
 	ErrBookNotFound     = errors.New("book not found")
 	ErrUserBookNotFound = errors.New("user book not found")
 	ErrInvalidInput     = errors.New("invalid input")
+	// ErrEditionNotFound is returned for an edition ID that doesn't exist (anymore), e.g. because
+	// its book was merged into another one
+	ErrEditionNotFound = errors.New("edition not found")
 )
 
 const (
@@ -122,6 +145,15 @@ type ClientConfig struct {
 	Burst int
 	// MaxConcurrent specifies the maximum number of concurrent requests (default: from config or 3)
 	MaxConcurrent int
+	// RateLimiter is shared with other clients when set, in which case RateLimit, Burst and
+	// MaxConcurrent are ignored (default: nil, the client creates its own)
+	RateLimiter *util.RateLimiter
+	// AllowedOperations restricts mutations to the listed GraphQL operation names when set
+	// (default: empty, all mutations are allowed)
+	AllowedOperations []string
+	// DisabledOperations lists GraphQL mutation operation names the client refuses to send
+	// (default: empty)
+	DisabledOperations []string
 }
 
 // headerAddingTransport is an http.RoundTripper that adds the required headers
@@ -165,6 +197,7 @@ type Client struct {
 	userBookIDCache  cache.Cache[int, int]             // editionID -> userBookID
 	userCache        cache.Cache[string, any]          // Generic cache for user-specific data
 	editionCache     cache.Cache[int, *models.Edition] // editionID -> Edition
+	operations       operationPolicy
 }
 
 // GetAuthHeader returns the properly formatted Authorization header value
@@ -236,8 +269,11 @@ func NewClientWithConfig(cfg *ClientConfig, token string, log *logger.Logger) *C
 		Timeout: cfg.Timeout,
 	}
 
-	// Create rate limiter with max concurrent requests from config
-	rateLimiter := util.NewRateLimiter(cfg.RateLimit, cfg.Burst, cfg.MaxConcurrent, log)
+	// Create rate limiter with max concurrent requests from config, unless one is shared
+	rateLimiter := cfg.RateLimiter
+	if rateLimiter == nil {
+		rateLimiter = util.NewRateLimiter(cfg.RateLimit, cfg.Burst, cfg.MaxConcurrent, log)
+	}
 
 	// Create logger if not provided
 	if log == nil {
@@ -301,6 +337,7 @@ func NewClientWithConfig(cfg *ClientConfig, token string, log *logger.Logger) *C
 		userBookIDCache: userBookIDCache,
 		userCache:       userCache,
 		editionCache:    editionCache,
+		operations:      newOperationPolicy(cfg.AllowedOperations, cfg.DisabledOperations),
 	}
 
 	// Log client creation
@@ -385,6 +422,64 @@ func (e *HTTPError) Error() string {
 	return fmt.Sprintf("HTTP error %d: %s", e.StatusCode, string(e.Body))
 }
 
+// IsAuthError reports whether err was caused by Hardcover rejecting the API token
+func IsAuthError(err error) bool {
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) {
+		return false
+	}
+	return httpErr.StatusCode == http.StatusUnauthorized || httpErr.StatusCode == http.StatusForbidden
+}
+
+// maxRateLimitBackoff caps the exponential backoff after a 429 response without Retry-After
+const maxRateLimitBackoff = time.Minute
+
+// onTooManyRequests handles a 429 response to attempt (0-based) of an operation, returning how
+// long to wait before retrying: the Retry-After of the response if it has one, and otherwise an
+// exponential backoff with jitter. The rate limiter lowers its rate, so the requests after the
+// retry are spaced out more, too.
+func (c *Client) onTooManyRequests(resp *http.Response, operation string, attempt int) time.Duration {
+	metrics.HardcoverRateLimited(operation)
+
+	retryAfter, err := util.ParseRetryAfter(resp.Header.Get("Retry-After"))
+	if err != nil {
+		c.logger.Debug("Ignoring invalid Retry-After header", map[string]interface{}{
+			"error": err.Error(),
+		})
+		retryAfter = 0
+	}
+	rate := c.rateLimiter.OnTooManyRequests(retryAfter)
+
+	wait := retryAfter
+	if wait <= 0 {
+		wait = rateLimitBackoff(c.retryDelay, attempt)
+	}
+	c.logger.Warn("Hardcover rate limit hit, backing off", map[string]interface{}{
+		"operation":   operation,
+		"attempt":     attempt + 1,
+		"retry_after": retryAfter.String(),
+		"wait":        wait.String(),
+		"new_rate":    rate.String(),
+	})
+	return wait
+}
+
+// rateLimitBackoff returns the exponential backoff after attempt (0-based) was rate limited,
+// doubling base for every attempt up to maxRateLimitBackoff. Half of it is jitter, so clients
+// rate limited together don't all retry at the same time.
+func rateLimitBackoff(base time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		base = DefaultRetryDelay
+	}
+	backoff := maxRateLimitBackoff
+	if attempt < 30 {
+		if exp := base << uint(attempt); exp > 0 && exp < maxRateLimitBackoff {
+			backoff = exp
+		}
+	}
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
 // GraphQLQuery executes a GraphQL query and unmarshals the response into the result parameter
 func (c *Client) GraphQLQuery(ctx context.Context, query string, variables map[string]interface{}, result interface{}) error {
 	if variables == nil {
@@ -408,12 +503,28 @@ func (c *Client) GraphQLMutation(ctx context.Context, mutation string, variables
 }
 
 // executeGraphQLOperation is a helper function that handles the common logic for executing GraphQL operations
-func (c *Client) executeGraphQLOperation(ctx context.Context, op graphqlOperation, query string, variables map[string]interface{}, result interface{}) error {
+func (c *Client) executeGraphQLOperation(ctx context.Context, op graphqlOperation, query string, variables map[string]interface{}, result interface{}) (err error) {
+	// Trace the operation, including its retries and rate limiting
+	opType, opName := parseOperation(query)
+	ctx, span := tracing.Start(ctx, "hardcover "+operationSpanName(opType, opName),
+		attribute.String("graphql.operation.type", opType),
+		attribute.String("graphql.operation.name", opName),
+	)
+	defer func() {
+		tracing.End(span, err)
+		metrics.HardcoverRequest(operationSpanName(opType, opName), err)
+	}()
+
+	// Refuse mutations disabled by configuration before sending anything
+	if err := c.checkOperationAllowed(query); err != nil {
+		return err
+	}
+
 	// Create a new GraphQL client with logging transport
 	httpClient := &http.Client{
 		Transport: loggingRoundTripper{
 			logger: c.logger,
-			rt:     http.DefaultTransport,
+			rt:     tracing.Transport("hardcover", nil),
 		},
 	}
 
@@ -426,13 +537,18 @@ func (c *Client) executeGraphQLOperation(ctx context.Context, op graphqlOperatio
 
 	// Execute the operation using the GraphQL client with retry logic
 	var lastErr error
+	var retryWait time.Duration // Set when Hardcover answered 429, replacing the linear delay
 	for attempt := 0; attempt <= c.maxRetries; attempt++ {
 		if attempt > 0 {
+			delay := c.retryDelay * time.Duration(attempt)
+			if retryWait > 0 {
+				delay, retryWait = retryWait, 0
+			}
 			// Context-aware backoff delay
 			select {
 			case <-ctx.Done():
 				return fmt.Errorf("retry canceled: %w", ctx.Err())
-			case <-time.After(c.retryDelay * time.Duration(attempt)):
+			case <-time.After(delay):
 			}
 		}
 
@@ -516,6 +632,13 @@ func (c *Client) executeGraphQLOperation(ctx context.Context, op graphqlOperatio
 				"error":   lastErr.Error(),
 				"attempt": attempt + 1,
 			})
+			// Retrying with rejected credentials won't help and risks tripping account protections
+			if IsAuthError(lastErr) {
+				return fmt.Errorf("authentication failed: %w", lastErr)
+			}
+			if resp.StatusCode == http.StatusTooManyRequests {
+				retryWait = c.onTooManyRequests(resp, operationSpanName(opType, opName), attempt)
+			}
 			continue
 		}
 
@@ -938,7 +1061,7 @@ func (c *Client) GetBookByID(ctx context.Context, bookID string) (*models.Hardco
 		}
 	}
 
-	// Editions - prefer audiobook if available
+	// Editions - prefer the reading format of the context, audiobook by default
 	var editions []interface{}
 	if v, ok := bookObj["editions"].([]interface{}); ok {
 		editions = v
@@ -946,7 +1069,7 @@ func (c *Client) GetBookByID(ctx context.Context, bookID string) (*models.Hardco
 	var chosen map[string]interface{}
 	for _, e := range editions {
 		if em, ok := e.(map[string]interface{}); ok {
-			if rf, ok := em["reading_format_id"].(float64); ok && int(rf) == 2 {
+			if rf, ok := em["reading_format_id"].(float64); ok && int(rf) == readingFormatIDFromCtx(ctx) {
 				chosen = em
 				break
 			}
@@ -1026,6 +1149,7 @@ func (c *Client) GetUserBook(ctx context.Context, userBookID string) (*models.Ha
 			id
 			book_id
 			status_id
+			updated_at
 			book {
 				id
 				title
@@ -1048,15 +1172,16 @@ func (c *Client) GetUserBook(ctx context.Context, userBookID string) (*models.Ha
 	// Define a response structure that uses the array format
 	var result struct {
 		UserBooks []struct {
-			ID       int `json:"id"`
-			BookID   int `json:"book_id"`
-			StatusID int `json:"status_id"`
-			Book     struct {
+			ID        int     `json:"id"`
+			BookID    int     `json:"book_id"`
+			StatusID  int     `json:"status_id"`
+			UpdatedAt *string `json:"updated_at"`
+			Book      struct {
 				ID    int    `json:"id"`
 				Title string `json:"title"`
 			} `json:"book"`
-			EditionID int `json:"edition_id"`
-			Edition   struct {
+			EditionID *int `json:"edition_id"` // Null for books tracked without an edition
+			Edition   *struct {
 				ID     int     `json:"id"`
 				ASIN   *string `json:"asin"`
 				ISBN13 *string `json:"isbn_13"`
@@ -1077,30 +1202,41 @@ func (c *Client) GetUserBook(ctx context.Context, userBookID string) (*models.Ha
 	// Check if we got results
 	if len(result.UserBooks) == 0 {
 		log.Warn("User book not found", nil)
-		return nil, fmt.Errorf("user book not found with ID: %s", userBookID)
+		return nil, fmt.Errorf("%w with ID: %s", ErrUserBookNotFound, userBookID)
 	}
 
 	// Get the first (and only) user book
 	userBook := result.UserBooks[0]
+	bookID := userBook.Book.ID
+	if bookID == 0 {
+		bookID = userBook.BookID
+	}
 
 	// Create and populate the HardcoverBook model
 	book := &models.HardcoverBook{
-		ID:           strconv.Itoa(userBook.Book.ID),
+		ID:           strconv.Itoa(bookID),
 		Title:        userBook.Book.Title,
 		UserBookID:   userBookID,
 		BookStatusID: userBook.StatusID,
-		EditionID:    strconv.Itoa(userBook.EditionID),
 	}
 
-	// Set optional fields if they exist
-	if userBook.Edition.ASIN != nil {
-		book.EditionASIN = *userBook.Edition.ASIN
+	// Set optional fields if they exist; books tracked without an edition have none
+	if userBook.UpdatedAt != nil {
+		book.UpdatedAt = *userBook.UpdatedAt
 	}
-	if userBook.Edition.ISBN13 != nil {
-		book.EditionISBN13 = *userBook.Edition.ISBN13
+	if userBook.EditionID != nil && *userBook.EditionID > 0 {
+		book.EditionID = strconv.Itoa(*userBook.EditionID)
 	}
-	if userBook.Edition.ISBN10 != nil {
-		book.EditionISBN10 = *userBook.Edition.ISBN10
+	if edition := userBook.Edition; edition != nil {
+		if edition.ASIN != nil {
+			book.EditionASIN = *edition.ASIN
+		}
+		if edition.ISBN13 != nil {
+			book.EditionISBN13 = *edition.ISBN13
+		}
+		if edition.ISBN10 != nil {
+			book.EditionISBN10 = *edition.ISBN10
+		}
 	}
 
 	log.Debug("Successfully retrieved user book", map[string]interface{}{
@@ -1129,7 +1265,8 @@ func (c *Client) AddWithMetadata(key string, value interface{}, metadata map[str
 	return nil
 }
 
-// SearchBookByASIN searches for a book in the Hardcover database by ASIN
+// SearchBookByASIN searches for a book in the Hardcover database by ASIN among the editions in the
+// reading format of the context, audiobook by default
 func (c *Client) SearchBookByASIN(ctx context.Context, asin string) (*models.HardcoverBook, error) {
 	if asin == "" {
 		return nil, fmt.Errorf("ASIN cannot be empty")
@@ -1141,28 +1278,63 @@ func (c *Client) SearchBookByASIN(ctx context.Context, asin string) (*models.Har
 		"method": "SearchBookByASIN",
 	})
 
-	// Define the GraphQL query: always format-aware via numeric format_id, default to audiobook (2)
-	formatStr, hasFormat := getReadingFormatFromCtx(ctx)
-	formatID := 2
-	if hasFormat {
-		switch formatStr {
-		case "ebook":
-			formatID = 4
-		case "audiobook":
-			formatID = 2
-		}
+	// Always format-aware via numeric format_id, default to audiobook (2)
+	return c.searchBookByASIN(ctx, asin, readingFormatIDFromCtx(ctx), log)
+}
+
+// SearchBookByASINAnyFormat searches for a book by ASIN among the editions of all reading formats.
+// Many audiobooks have their ASIN attached to another edition in Hardcover, usually the ebook, so
+// this is a last resort once the format-aware lookups failed. A book found this way is returned
+// without an EditionID: its status can still be synced, but not its edition.
+func (c *Client) SearchBookByASINAnyFormat(ctx context.Context, asin string) (*models.HardcoverBook, error) {
+	if asin == "" {
+		return nil, fmt.Errorf("ASIN cannot be empty")
 	}
-	query := `
-query BookByASIN($asin: String!, $format_id: Int!) {
-  books(
-    where: { 
-      editions: { 
-        _and: [
-          { asin: { _eq: $asin } }, 
+
+	log := c.logger.With(map[string]interface{}{
+		"asin":   asin,
+		"method": "SearchBookByASINAnyFormat",
+	})
+
+	hcBook, err := c.searchBookByASIN(ctx, asin, 0, log)
+	if err != nil || hcBook == nil {
+		return hcBook, err
+	}
+
+	message := "ASIN matched a non-audio edition, syncing the book without an edition, so its audio length isn't set"
+	if readingFormatIDFromCtx(ctx) != 2 {
+		message = "ASIN matched an edition in another reading format, syncing the book without an edition"
+	}
+	log.Warn(message, map[string]interface{}{
+		"book_id":            hcBook.ID,
+		"title":              hcBook.Title,
+		"matched_edition_id": hcBook.EditionID,
+	})
+	hcBook.EditionID = ""
+	return hcBook, nil
+}
+
+// searchBookByASIN looks up the book with an edition carrying the ASIN in the reading format, or in
+// any reading format when formatID is 0
+func (c *Client) searchBookByASIN(ctx context.Context, asin string, formatID int, log *logger.Logger) (*models.HardcoverBook, error) {
+	params := "$asin: String!"
+	editionFilter := "{ asin: { _eq: $asin } }"
+	vars := map[string]interface{}{
+		"asin": asin,
+	}
+	if formatID > 0 {
+		params += ", $format_id: Int!"
+		editionFilter = `{ _and: [
+          { asin: { _eq: $asin } },
           { reading_format: { id: { _eq: $format_id } } }
-        ]
-      } 
-    },
+        ] }`
+		vars["format_id"] = formatID
+	}
+
+	query := fmt.Sprintf(`
+query BookByASIN(%s) {
+  books(
+    where: { editions: %s },
     limit: 1
   ) {
     id
@@ -1170,12 +1342,7 @@ query BookByASIN($asin: String!, $format_id: Int!) {
     book_status_id
     canonical_id
     editions(
-      where: { 
-        _and: [
-          { asin: { _eq: $asin }},
-          { reading_format: { id: { _eq: $format_id } } }
-        ]
-      },
+      where: %s,
       limit: 1
     ) {
       id
@@ -1184,19 +1351,16 @@ query BookByASIN($asin: String!, $format_id: Int!) {
       isbn_10
       reading_format_id
       audio_seconds
+      contributions(limit: 20) { contribution author { id name } }
     }
   }
-}`
+}`, params, editionFilter, editionFilter)
 
 	// Define the response structure to match the actual API response
 
 	// Use a flexible raw map to be resilient to schema variations
 	var rawResponse map[string]interface{}
 
-	vars := map[string]interface{}{
-		"asin":      asin,
-		"format_id": formatID,
-	}
 	err := c.GraphQLQuery(ctx, query, vars, &rawResponse)
 
 	if err != nil {
@@ -1313,7 +1477,7 @@ query BookByASIN($asin: String!, $format_id: Int!) {
 		log.Warn("No editions found for book", map[string]interface{}{
 			"book_id": hcBook.ID,
 		})
-		return nil, nil
+		return nil, WithBookID(ErrBookHasNoEditions, hcBook.ID)
 	}
 
 	// Process the first edition
@@ -1359,6 +1523,38 @@ query BookByASIN($asin: String!, $format_id: Int!) {
 		hcBook.EditionISBN10 = isbn10
 	}
 
+	// Edition-level narrators (used for narrator verification of ASIN matches)
+	if rel, ok := edition["contributions"].([]interface{}); ok {
+		for _, item := range rel {
+			m, _ := item.(map[string]interface{})
+			if m == nil {
+				continue
+			}
+			role, _ := m["contribution"].(string)
+			if !strings.Contains(strings.ToLower(role), "narrator") {
+				continue
+			}
+			a, _ := m["author"].(map[string]interface{})
+			if a == nil {
+				continue
+			}
+			name, _ := a["name"].(string)
+			if name == "" {
+				continue
+			}
+			idStr := ""
+			switch idv := a["id"].(type) {
+			case json.Number:
+				idStr = idv.String()
+			case float64:
+				idStr = strconv.FormatFloat(idv, 'f', 0, 64)
+			case string:
+				idStr = idv
+			}
+			hcBook.Narrators = append(hcBook.Narrators, models.Author{ID: idStr, Name: name})
+		}
+	}
+
 	log.Debug("Successfully found book by ASIN", map[string]interface{}{
 		"book_id":    hcBook.ID,
 		"title":      bookData["title"].(string),
@@ -1386,16 +1582,7 @@ func (c *Client) searchBookByISBN(ctx context.Context, isbnField, isbn string) (
 	normalizedISBN = strings.ReplaceAll(normalizedISBN, " ", "")
 
 	// Define the GraphQL query (always format-aware via numeric format_id, default to audiobook id=2)
-	formatStr, hasFormat := getReadingFormatFromCtx(ctx)
-	formatID := 2
-	if hasFormat {
-		switch formatStr {
-		case "ebook":
-			formatID = 4
-		case "audiobook":
-			formatID = 2
-		}
-	}
+	formatID := readingFormatIDFromCtx(ctx)
 	query := fmt.Sprintf(`
     query BookByISBN($isbn: String!, $format_id: Int!) {
       books(
@@ -1515,7 +1702,7 @@ func (c *Client) searchBookByISBN(ctx context.Context, isbnField, isbn string) (
 		log.Warn("No editions found for book", map[string]interface{}{
 			"book_id": bookData.ID.String(),
 		})
-		return nil, nil
+		return nil, WithBookID(ErrBookHasNoEditions, bookData.ID.String())
 	}
 
 	edition := bookData.Editions[0]
@@ -1688,6 +1875,14 @@ func (c *Client) searchBooksWithLimit(ctx context.Context, query string, limit i
 	return searchResults, nil
 }
 
+// Hardcover reading format IDs, as used by reading_format_id on editions and user book reads
+const (
+	ReadingFormatPhysical  = 1
+	ReadingFormatAudiobook = 2
+	ReadingFormatBoth      = 3
+	ReadingFormatEbook     = 4
+)
+
 // DatesReadInput represents the input for date-related fields when creating or updating a user book read entry
 type DatesReadInput struct {
 	Action          *string `json:"action,omitempty"`
@@ -1696,6 +1891,7 @@ type DatesReadInput struct {
 	ID              *int64  `json:"id,omitempty"`
 	StartedAt       *string `json:"started_at,omitempty"`
 	ProgressSeconds *int    `json:"progress_seconds,omitempty"`
+	ReadingFormatID *int    `json:"reading_format_id,omitempty"`
 }
 
 // InsertUserBookReadInput represents the input for creating a new user book read entry
@@ -1738,6 +1934,12 @@ func (c *Client) InsertUserBookRead(ctx context.Context, input InsertUserBookRea
 	if input.DatesRead.StartedAt != nil {
 		userBookRead["started_at"] = input.DatesRead.StartedAt
 	}
+	if input.DatesRead.ProgressSeconds != nil {
+		userBookRead["progress_seconds"] = input.DatesRead.ProgressSeconds
+	}
+	if input.DatesRead.ReadingFormatID != nil {
+		userBookRead["reading_format_id"] = input.DatesRead.ReadingFormatID
+	}
 
 	variables := map[string]interface{}{
 		"user_book_id":   input.UserBookID,
@@ -1766,6 +1968,7 @@ func (c *Client) InsertUserBookRead(ctx context.Context, input InsertUserBookRea
 		return 0, fmt.Errorf("failed to insert user book read: %s", *result.InsertUserBookRead.Error)
 	}
 
+	metrics.ProgressUpdateSent("insert")
 	return result.InsertUserBookRead.ID, nil
 }
 
@@ -1803,6 +2006,8 @@ func (c *Client) UpdateUserBookStatus(ctx context.Context, input UpdateUserBookS
 			input.StatusID = 2
 		case "READ", "FINISHED":
 			input.StatusID = 3
+		case "DID_NOT_FINISH", "DNF":
+			input.StatusID = 5
 		default:
 			return fmt.Errorf("%w: invalid status: %s", ErrInvalidInput, input.Status)
 		}
@@ -1979,6 +2184,8 @@ type CheckExistingFinishedReadResult struct {
 type CheckExistingUserBookReadInput struct {
 	UserBookID int    `json:"user_book_id"`
 	Date       string `json:"date"` // Format: YYYY-MM-DD
+	// Unfinished only matches reads that aren't finished yet
+	Unfinished bool `json:"unfinished,omitempty"`
 }
 
 // ExistingUserBookRead represents an existing user book read entry
@@ -2015,21 +2222,40 @@ type UpdateUserBookReadResponse struct {
 	} `json:"update_user_book_read"`
 }
 
-// CheckExistingUserBookRead checks if there's an existing user book read entry for the given date
+// CheckExistingUserBookRead checks if there's an existing user book read entry for the given date.
+// A read matches if it was started, finished or created on that date, and with input.Unfinished
+// only if it isn't finished.
 func (c *Client) CheckExistingUserBookRead(ctx context.Context, input CheckExistingUserBookReadInput) (*CheckExistingUserBookReadResult, error) {
 	log := logger.WithContext(map[string]interface{}{
 		"method":       "CheckExistingUserBookRead",
 		"user_book_id": input.UserBookID,
 		"date":         input.Date,
+		"unfinished":   input.Unfinished,
 	})
 
+	// created_at is a timestamp, so it's matched against the day's start and the next day's start
+	day, err := time.Parse("2006-01-02", input.Date)
+	if err != nil {
+		return nil, fmt.Errorf("invalid read date %q: %w", input.Date, err)
+	}
+
+	unfinishedFilter := ""
+	if input.Unfinished {
+		unfinishedFilter = "finished_at: {_is_null: true},"
+	}
+
 	// Define the GraphQL query
-	const query = `
-		query CheckExistingUserBookRead($userBookId: Int!, $date: date!) {
+	query := `
+		query CheckExistingUserBookRead($userBookId: Int!, $date: date!, $dayStart: timestamptz!, $nextDay: timestamptz!) {
 			user_book_reads(
 				where: {
 					user_book_id: {_eq: $userBookId},
-					created_at: {_gte: $date, _lt: $date::date + interval '1 day'}
+					` + unfinishedFilter + `
+					_or: [
+						{started_at: {_eq: $date}},
+						{finished_at: {_eq: $date}},
+						{created_at: {_gte: $dayStart, _lt: $nextDay}}
+					]
 				},
 				order_by: {created_at: desc},
 				limit: 1
@@ -2056,10 +2282,12 @@ func (c *Client) CheckExistingUserBookRead(ctx context.Context, input CheckExist
 	vars := map[string]interface{}{
 		"userBookId": input.UserBookID,
 		"date":       input.Date,
+		"dayStart":   day.Format(time.RFC3339),
+		"nextDay":    day.AddDate(0, 0, 1).Format(time.RFC3339),
 	}
 
 	// Execute the query
-	err := c.executeGraphQLOperation(ctx, queryOperation, query, vars, &response)
+	err = c.executeGraphQLOperation(ctx, queryOperation, query, vars, &response)
 	if err != nil {
 		log.Error("Failed to execute GraphQL query", map[string]interface{}{
 			"error": err.Error(),
@@ -2079,7 +2307,7 @@ func (c *Client) CheckExistingUserBookRead(ctx context.Context, input CheckExist
 		}, nil
 	}
 
-	log.Debug("No existing read entry found for today", nil)
+	log.Debug("No existing read entry found for date", nil)
 	return nil, nil
 }
 
@@ -2143,6 +2371,9 @@ func (c *Client) UpdateUserBookRead(ctx context.Context, input UpdateUserBookRea
 	if datesReadInput.ProgressSeconds != nil {
 		updateObjMap["progress_seconds"] = datesReadInput.ProgressSeconds
 	}
+	if datesReadInput.ReadingFormatID != nil {
+		updateObjMap["reading_format_id"] = datesReadInput.ReadingFormatID
+	}
 
 	// Define the result type
 	var result struct {
@@ -2179,6 +2410,7 @@ func (c *Client) UpdateUserBookRead(ctx context.Context, input UpdateUserBookRea
 		})
 		return false, fmt.Errorf("update error: %s", errMsg)
 	}
+	metrics.ProgressUpdateSent("update")
 
 	// The API sometimes returns success with user_book_read: null
 	// In this case, we'll assume the update was successful
@@ -2197,6 +2429,54 @@ func (c *Client) UpdateUserBookRead(ctx context.Context, input UpdateUserBookRea
 	return true, nil
 }
 
+// DeleteUserBookRead deletes a reading progress entry
+func (c *Client) DeleteUserBookRead(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return fmt.Errorf("%w: invalid user book read ID: %d", ErrInvalidInput, id)
+	}
+
+	log := c.logger.With(map[string]interface{}{
+		"id":     id,
+		"method": "DeleteUserBookRead",
+	})
+
+	mutation := `
+		mutation DeleteUserBookRead($id: Int!) {
+		  delete_user_book_read(id: $id) {
+			id
+			error
+		  }
+		}`
+
+	var result struct {
+		DeleteUserBookRead *struct {
+			ID    *int    `json:"id"`
+			Error *string `json:"error"`
+		} `json:"delete_user_book_read"`
+	}
+
+	if err := c.executeGraphQLMutation(ctx, mutation, map[string]interface{}{"id": id}, &result); err != nil {
+		log.Error("Failed to execute delete mutation", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return fmt.Errorf("failed to execute delete mutation: %w", err)
+	}
+
+	if result.DeleteUserBookRead == nil {
+		return errors.New("failed to delete user book read: empty response")
+	}
+	if result.DeleteUserBookRead.Error != nil {
+		errMsg := *result.DeleteUserBookRead.Error
+		log.Error("Error in delete_user_book_read response", map[string]interface{}{
+			"error": errMsg,
+		})
+		return fmt.Errorf("delete error: %s", errMsg)
+	}
+
+	log.Info("Successfully deleted user book read entry", nil)
+	return nil
+}
+
 // GetEditionByISBN13 retrieves an edition by its ISBN-13
 func (c *Client) GetEditionByISBN13(ctx context.Context, isbn13 string) (*models.Edition, error) {
 	// First try to find the book by ISBN-13
@@ -2355,7 +2635,7 @@ func (c *Client) GetEdition(ctx context.Context, editionID string) (*models.Edit
 		log.Debug("Edition not found in response", map[string]interface{}{
 			"edition_id": editionID,
 		})
-		return nil, fmt.Errorf("edition not found: %s", editionID)
+		return nil, fmt.Errorf("%w: %s", ErrEditionNotFound, editionID)
 	}
 
 	// Get the first edition
@@ -2634,6 +2914,69 @@ func (c *Client) SearchPublishers(ctx context.Context, name string, limit int) (
 	return publishers, nil
 }
 
+// GetPublisherByID retrieves a publisher by ID
+func (c *Client) GetPublisherByID(ctx context.Context, id string) (*models.Publisher, error) {
+	if c.logger == nil {
+		c.logger = logger.Get()
+	}
+	log := c.logger.With(map[string]interface{}{
+		"method": "GetPublisherByID",
+		"id":     id,
+	})
+
+	// Validate input
+	if id == "" {
+		log.Error("Empty publisher ID provided", nil)
+		return nil, errors.New("empty publisher ID provided")
+	}
+
+	// Parse ID to ensure it's a valid integer
+	publisherID, err := strconv.Atoi(id)
+	if err != nil {
+		log.Error("Invalid publisher ID format", map[string]interface{}{"error": err.Error()})
+		return nil, fmt.Errorf("invalid publisher ID format: %w", err)
+	}
+
+	query := `
+	query GetPublisher($id: Int!) {
+		publishers(where: {id: {_eq: $id}}, limit: 1) {
+			id
+			name
+		}
+	}`
+
+	variables := map[string]interface{}{
+		"id": publisherID,
+	}
+
+	var response struct {
+		Publishers []struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+		} `json:"publishers"`
+	}
+
+	if err := c.GraphQLQuery(ctx, query, variables, &response); err != nil {
+		log.Error("Failed to fetch publisher details", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, fmt.Errorf("failed to fetch publisher details: %w", err)
+	}
+
+	if len(response.Publishers) == 0 {
+		log.Warn("Publisher not found", map[string]interface{}{
+			"id": id,
+		})
+		return nil, fmt.Errorf("publisher not found with ID: %s", id)
+	}
+
+	publisher := response.Publishers[0]
+	return &models.Publisher{
+		ID:   strconv.Itoa(publisher.ID),
+		Name: publisher.Name,
+	}, nil
+}
+
 // GetPersonByID retrieves a person (author or narrator) by ID
 func (c *Client) GetPersonByID(ctx context.Context, id string) (*models.Author, error) {
 	if c.logger == nil {
@@ -2943,6 +3286,9 @@ var statusNameToID = map[string]int{
 	"READING":     2,
 	"READ":        3,
 	"FINISHED":    3, // FINISHED is an alias for READ in the API
+	// Did Not Finish, for books the user abandoned
+	"DID_NOT_FINISH": 5,
+	"DNF":            5,
 }
 
 // CreateUserBook creates a new user book entry for the given edition ID and status
@@ -2958,7 +3304,7 @@ func (c *Client) CreateUserBook(ctx context.Context, editionID, status string) (
 			"error":     err.Error(),
 			"editionID": editionID,
 		})
-		return "", errors.New("failed to get edition details")
+		return "", fmt.Errorf("failed to get edition details: %w", err)
 	}
 
 	c.logger.Debug("Retrieved edition details", map[string]interface{}{
@@ -3065,6 +3411,82 @@ func (c *Client) CreateUserBook(ctx context.Context, editionID, status string) (
 	return userBookID, nil
 }
 
+// CreateBookLevelUserBook adds a book to the user's library without an edition, for books that have
+// no matching edition. If the book is already in the library, the existing user book ID is returned
+// and its status is left unchanged.
+func (c *Client) CreateBookLevelUserBook(ctx context.Context, bookID, status string) (string, error) {
+	statusID, ok := statusNameToID[status]
+	if !ok {
+		return "", errors.New("invalid status: " + status)
+	}
+
+	bookIDInt, err := strconv.Atoi(bookID)
+	if err != nil {
+		return "", errors.New("invalid book ID format")
+	}
+
+	userID, err := c.GetCurrentUserID(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get current user ID: %w", err)
+	}
+
+	existingID, err := c.lookupUserBookByBookID(ctx, bookIDInt, userID)
+	if err != nil {
+		return "", err
+	}
+	if existingID > 0 {
+		c.logger.Debug("Book is already in the user's library", map[string]interface{}{
+			"userBookID": existingID,
+			"bookID":     bookIDInt,
+		})
+		return strconv.Itoa(existingID), nil
+	}
+
+	mutation := `
+	mutation InsertUserBook($object: UserBookCreateInput!) {
+	  insert_user_book(object: $object) {
+		id
+		user_book {
+		  id
+		  status_id
+		}
+		error
+	  }
+	}`
+
+	input := map[string]interface{}{
+		"object": map[string]interface{}{
+			"book_id":   bookIDInt,
+			"status_id": statusID,
+		},
+	}
+
+	var result struct {
+		InsertUserBook struct {
+			UserBook struct {
+				ID       int `json:"id"`
+				StatusID int `json:"status_id"`
+			} `json:"user_book"`
+			Error *string `json:"error,omitempty"`
+		} `json:"insert_user_book"`
+	}
+
+	if err := c.GraphQLMutation(ctx, mutation, input, &result); err != nil {
+		return "", fmt.Errorf("failed to create book-level user book: %w", err)
+	}
+	if result.InsertUserBook.Error != nil {
+		return "", fmt.Errorf("failed to create book-level user book: %s", *result.InsertUserBook.Error)
+	}
+
+	c.logger.Info("Successfully created book-level user book", map[string]interface{}{
+		"userBookID": result.InsertUserBook.UserBook.ID,
+		"bookID":     bookIDInt,
+		"status":     status,
+	})
+
+	return strconv.Itoa(result.InsertUserBook.UserBook.ID), nil
+}
+
 // SearchByISBNResponse represents the response when searching for a book by ISBN
 type SearchByISBNResponse struct {
 	Books []struct {
@@ -3172,6 +3594,124 @@ func (c *Client) CheckBookOwnership(ctx context.Context, bookID int) (bool, erro
 	return owned, nil
 }
 
+// finishedUserBooksPageSize is the number of finished user books fetched per request
+const finishedUserBooksPageSize = 500
+
+// GetFinishedUserBooks returns the Hardcover book IDs of the current user's books with the Read
+// status, mapped to the date they were last read. The date is zero when Hardcover doesn't know it.
+func (c *Client) GetFinishedUserBooks(ctx context.Context) (map[string]time.Time, error) {
+	userID, err := c.GetCurrentUserID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current user ID: %w", err)
+	}
+
+	const query = `
+	query GetFinishedUserBooks($userId: Int!, $statusId: Int!, $limit: Int!, $offset: Int!) {
+	  user_books(
+		where: {
+		  user_id: {_eq: $userId},
+		  status_id: {_eq: $statusId}
+		},
+		order_by: {id: asc},
+		limit: $limit,
+		offset: $offset
+	  ) {
+		book_id
+		last_read_date
+	  }
+	}`
+
+	finished := make(map[string]time.Time)
+	for offset := 0; ; offset += finishedUserBooksPageSize {
+		var response struct {
+			UserBooks []struct {
+				BookID       int    `json:"book_id"`
+				LastReadDate string `json:"last_read_date"`
+			} `json:"user_books"`
+		}
+
+		err := c.GraphQLQuery(ctx, query, map[string]interface{}{
+			"userId":   userID,
+			"statusId": statusNameToID["READ"],
+			"limit":    finishedUserBooksPageSize,
+			"offset":   offset,
+		}, &response)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get finished user books: %w", err)
+		}
+
+		for _, userBook := range response.UserBooks {
+			lastRead, _ := time.Parse("2006-01-02", userBook.LastReadDate)
+			finished[strconv.Itoa(userBook.BookID)] = lastRead
+		}
+		if len(response.UserBooks) < finishedUserBooksPageSize {
+			break
+		}
+	}
+
+	c.logger.Debug("Fetched finished user books", map[string]interface{}{
+		"userID": userID,
+		"count":  len(finished),
+	})
+
+	return finished, nil
+}
+
+// GetBookAuthors returns the authors of a Hardcover book. Contributors with another role, such as
+// narrators and translators, are left out.
+func (c *Client) GetBookAuthors(ctx context.Context, bookID string) ([]models.Author, error) {
+	id, err := strconv.Atoi(strings.TrimSpace(bookID))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid book ID: %s", ErrInvalidInput, bookID)
+	}
+
+	const query = `
+	query GetBookAuthors($id: Int!) {
+	  books(where: { id: { _eq: $id } }, limit: 1) {
+	    contributions(limit: 50) { contribution author { id name } }
+	  }
+	}`
+
+	var response struct {
+		Books []struct {
+			Contributions []struct {
+				Contribution *string `json:"contribution"`
+				Author       *struct {
+					ID   int    `json:"id"`
+					Name string `json:"name"`
+				} `json:"author"`
+			} `json:"contributions"`
+		} `json:"books"`
+	}
+
+	if err := c.GraphQLQuery(ctx, query, map[string]interface{}{"id": id}, &response); err != nil {
+		return nil, fmt.Errorf("failed to get book authors: %w", err)
+	}
+	if len(response.Books) == 0 {
+		return nil, fmt.Errorf("book not found: %s", bookID)
+	}
+
+	var authors []models.Author
+	for _, contribution := range response.Books[0].Contributions {
+		// Authors have no contribution role, or "Author"
+		if contribution.Author == nil || (contribution.Contribution != nil &&
+			*contribution.Contribution != "" && !strings.EqualFold(*contribution.Contribution, "Author")) {
+			continue
+		}
+		authors = append(authors, models.Author{
+			ID:   strconv.Itoa(contribution.Author.ID),
+			Name: contribution.Author.Name,
+		})
+	}
+
+	c.logger.Debug("Fetched book authors", map[string]interface{}{
+		"book_id": bookID,
+		"count":   len(authors),
+	})
+
+	return authors, nil
+}
+
 // SearchBookByISBN searches for a book by its ISBN
 func (c *Client) SearchBookByISBN(ctx context.Context, isbn string) (*models.HardcoverBook, error) {
 	log := c.logger.With(map[string]interface{}{
@@ -3186,13 +3726,13 @@ func (c *Client) SearchBookByISBN(ctx context.Context, isbn string) (*models.Har
 	}
 
 	query := fmt.Sprintf(`
-	query BookByISBN($identifier: String!) {
+	query BookByISBN($identifier: String!, $format_id: Int!) {
 	  books(
 	    where: { 
 	      editions: { 
 	        _and: [
 	          { %s: { _eq: $identifier } },
-	          { reading_format: { id: { _eq: 2 } } }
+	          { reading_format: { id: { _eq: $format_id } } }
 	        ]
 	      } 
 	    },
@@ -3206,7 +3746,7 @@ func (c *Client) SearchBookByISBN(ctx context.Context, isbn string) (*models.Har
 	      where: { 
 	        _and: [
 	          { %s: { _eq: $identifier } },
-	          { reading_format: { id: { _eq: 2 } } }
+	          { reading_format: { id: { _eq: $format_id } } }
 	        ]
 	      },
 	      limit: 1
@@ -3224,6 +3764,7 @@ func (c *Client) SearchBookByISBN(ctx context.Context, isbn string) (*models.Har
 	var result SearchByISBNResponse
 	err := c.GraphQLQuery(ctx, query, map[string]interface{}{
 		"identifier": isbn,
+		"format_id":  readingFormatIDFromCtx(ctx),
 	}, &result)
 
 	if err != nil {
@@ -3268,6 +3809,131 @@ func (c *Client) SearchBookByISBN(ctx context.Context, isbn string) (*models.Har
 	return hcBook, nil
 }
 
+// BookSlug returns the Hardcover book slug of a slug or a book URL such as
+// https://hardcover.app/books/project-hail-mary, or "" if the URL isn't a book URL
+func BookSlug(slugOrURL string) string {
+	slug := strings.TrimSpace(slugOrURL)
+	if !strings.Contains(slug, "/") {
+		return slug
+	}
+
+	// Drop the query and fragment, then take the path segment after "books"
+	if i := strings.IndexAny(slug, "?#"); i >= 0 {
+		slug = slug[:i]
+	}
+	parts := strings.Split(slug, "/")
+	for i, part := range parts {
+		if part == "books" && i+1 < len(parts) {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
+
+// SearchBookBySlug looks a book up by its Hardcover slug or book URL and returns it with its most
+// popular edition of the reading format in the context (audiobook by default). The book is returned
+// without an edition if it has none of that format, and nil if there's no book with the slug.
+func (c *Client) SearchBookBySlug(ctx context.Context, slug string) (*models.HardcoverBook, error) {
+	bookSlug := BookSlug(slug)
+	if bookSlug == "" {
+		return nil, fmt.Errorf("not a Hardcover book slug or URL: %q", slug)
+	}
+
+	log := c.logger.With(map[string]interface{}{
+		"slug":   bookSlug,
+		"method": "SearchBookBySlug",
+	})
+
+	formatID := ReadingFormatAudiobook
+	if formatStr, ok := getReadingFormatFromCtx(ctx); ok && formatStr == "ebook" {
+		formatID = ReadingFormatEbook
+	}
+
+	const query = `
+	query BookBySlug($slug: String!, $format_id: Int!) {
+	  books(where: {slug: {_eq: $slug}}, limit: 1) {
+		id
+		title
+		slug
+		book_status_id
+		canonical_id
+		editions(
+		  where: {reading_format: {id: {_eq: $format_id}}},
+		  order_by: {users_count: desc_nulls_last},
+		  limit: 1
+		) {
+		  id
+		  asin
+		  isbn_13
+		  isbn_10
+		}
+	  }
+	}`
+
+	var result struct {
+		Books []struct {
+			ID           json.Number `json:"id"`
+			Title        string      `json:"title"`
+			Slug         string      `json:"slug"`
+			BookStatusID int         `json:"book_status_id"`
+			CanonicalID  *int        `json:"canonical_id"`
+			Editions     []struct {
+				ID     json.Number `json:"id"`
+				ASIN   *string     `json:"asin"`
+				ISBN13 *string     `json:"isbn_13"`
+				ISBN10 *string     `json:"isbn_10"`
+			} `json:"editions"`
+		} `json:"books"`
+	}
+
+	err := c.GraphQLQuery(ctx, query, map[string]interface{}{
+		"slug":      bookSlug,
+		"format_id": formatID,
+	}, &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search book by slug: %w", err)
+	}
+
+	if len(result.Books) == 0 {
+		log.Debug("No book found with the given slug", nil)
+		return nil, nil
+	}
+
+	bookData := result.Books[0]
+	hcBook := &models.HardcoverBook{
+		ID:           bookData.ID.String(),
+		Title:        bookData.Title,
+		Slug:         bookData.Slug,
+		BookStatusID: bookData.BookStatusID,
+		CanonicalID:  bookData.CanonicalID,
+	}
+	if len(bookData.Editions) == 0 {
+		log.Warn("Book has no edition of the reading format", map[string]interface{}{
+			"book_id":   hcBook.ID,
+			"format_id": formatID,
+		})
+		return hcBook, nil
+	}
+
+	edition := bookData.Editions[0]
+	hcBook.EditionID = edition.ID.String()
+	if edition.ASIN != nil {
+		hcBook.EditionASIN = *edition.ASIN
+	}
+	if edition.ISBN13 != nil {
+		hcBook.EditionISBN13 = *edition.ISBN13
+	}
+	if edition.ISBN10 != nil {
+		hcBook.EditionISBN10 = *edition.ISBN10
+	}
+
+	log.Debug("Found book by slug", map[string]interface{}{
+		"book_id":    hcBook.ID,
+		"edition_id": hcBook.EditionID,
+	})
+	return hcBook, nil
+}
+
 // SearchBookByTitleAuthor searches for a book by its title and author
 func (c *Client) SearchBookByTitleAuthor(ctx context.Context, title, author string) (*models.HardcoverBook, error) {
 	log := c.logger.With(map[string]interface{}{
@@ -3290,7 +3956,7 @@ func (c *Client) SearchBookByTitleAuthor(ctx context.Context, title, author stri
 
 	// Build the query dynamically based on available search terms
 	query := `
-	query BookByTitleAuthor($title: String!` + func() string {
+	query BookByTitleAuthor($title: String!, $format_id: Int!` + func() string {
 		if cleanAuthor != "" {
 			return `, $author: String!`
 		}
@@ -3306,14 +3972,14 @@ func (c *Client) SearchBookByTitleAuthor(ctx context.Context, title, author stri
 			}
 			return ""
 		}() + `,
-	      { editions: { reading_format: { id: { _eq: 2 } } } }
+	      { editions: { reading_format: { id: { _eq: $format_id } } } }
 	    ]
 	  }, limit: 5) {
 	    id
 	    title
 	    book_status_id
 	    canonical_id
-	    editions(where: { reading_format: { id: { _eq: 2 } } }, limit: 1) {
+	    editions(where: { reading_format: { id: { _eq: $format_id } } }, limit: 1) {
 	      id
 	      asin
 	      isbn_13
@@ -3327,7 +3993,8 @@ func (c *Client) SearchBookByTitleAuthor(ctx context.Context, title, author stri
 
 	// Prepare variables
 	variables := map[string]interface{}{
-		"title": cleanTitle,
+		"title":     cleanTitle,
+		"format_id": readingFormatIDFromCtx(ctx),
 	}
 
 	// Only add author to variables if it's not empty
@@ -3485,6 +4152,67 @@ func (c *Client) MarkEditionAsOwned(ctx context.Context, editionID int) error {
 	return nil
 }
 
+// AddTagToBook applies a user tag to a book in Hardcover.
+// Tagging is an upsert, so applying a tag that is already present is a no-op.
+func (c *Client) AddTagToBook(ctx context.Context, bookID int, tag string) error {
+	if bookID <= 0 {
+		return fmt.Errorf("invalid book ID: %d", bookID)
+	}
+	if strings.TrimSpace(tag) == "" {
+		return errors.New("tag is required")
+	}
+
+	log := c.logger.With(map[string]interface{}{
+		"book_id": bookID,
+		"tag":     tag,
+		"method":  "AddTagToBook",
+	})
+
+	mutation := `
+	mutation AddTagToBook($id: bigint!, $tags: [BasicTag]!) {
+	  upsert_tags(id: $id, type: "Book", tags: $tags) {
+		tags {
+		  tag
+		  category
+		}
+	  }
+	}`
+
+	variables := map[string]interface{}{
+		"id": bookID,
+		"tags": []map[string]interface{}{
+			{
+				"tag":      tag,
+				"category": "Tag",
+				"spoiler":  false,
+			},
+		},
+	}
+
+	var result struct {
+		UpsertTags *struct {
+			Tags []struct {
+				Tag      string `json:"tag"`
+				Category string `json:"category"`
+			} `json:"tags"`
+		} `json:"upsert_tags"`
+	}
+
+	if err := c.GraphQLMutation(ctx, mutation, variables, &result); err != nil {
+		log.Error("Failed to add tag to book", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return fmt.Errorf("failed to add tag to book: %w", err)
+	}
+
+	if result.UpsertTags == nil {
+		return errors.New("failed to add tag to book: empty response")
+	}
+
+	log.Debug("Added tag to book in Hardcover", nil)
+	return nil
+}
+
 // UpdateUserBook updates a user book
 func (c *Client) UpdateUserBook(ctx context.Context, input UpdateUserBookInput) error {
 	log := c.logger.With(map[string]interface{}{
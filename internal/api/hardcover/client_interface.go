@@ -2,6 +2,7 @@ package hardcover
 
 import (
 	"context"
+	"time"
 
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
 )
@@ -29,6 +30,9 @@ type HardcoverClientInterface interface {
 	// MarkEditionAsOwned adds a book to the user's "Owned" list
 	MarkEditionAsOwned(ctx context.Context, editionID int) error
 
+	// AddTagToBook applies a user tag to a book
+	AddTagToBook(ctx context.Context, bookID int, tag string) error
+
 	// GetUserBookID retrieves the user book ID for a given edition ID
 	GetUserBookID(ctx context.Context, editionID int) (int, error)
 
@@ -38,12 +42,18 @@ type HardcoverClientInterface interface {
     // SearchBookByASIN searches for a book by ASIN
     SearchBookByASIN(ctx context.Context, asin string) (*models.HardcoverBook, error)
 
+    // SearchBookByASINAnyFormat searches for a book by ASIN across all reading formats
+    SearchBookByASINAnyFormat(ctx context.Context, asin string) (*models.HardcoverBook, error)
+
     // SearchBookByISBN10 searches for a book by ISBN-10
     SearchBookByISBN10(ctx context.Context, isbn10 string) (*models.HardcoverBook, error)
 
     // SearchBooks searches for books by title and author
     SearchBooks(ctx context.Context, title, author string) ([]models.HardcoverBook, error)
 
+	// SearchBookBySlug looks a book up by its Hardcover slug or book URL
+	SearchBookBySlug(ctx context.Context, slug string) (*models.HardcoverBook, error)
+
 
 	// GetEditionByASIN gets an edition by its ASIN
 	GetEditionByASIN(ctx context.Context, asin string) (*models.Edition, error)
@@ -72,6 +82,9 @@ type HardcoverClientInterface interface {
 	// UpdateUserBookRead updates an existing reading progress entry
 	UpdateUserBookRead(ctx context.Context, input UpdateUserBookReadInput) (bool, error)
 
+	// DeleteUserBookRead deletes a reading progress entry
+	DeleteUserBookRead(ctx context.Context, id int64) error
+
 	// CheckExistingUserBookRead checks if a reading progress entry exists
 	CheckExistingUserBookRead(ctx context.Context, input CheckExistingUserBookReadInput) (*CheckExistingUserBookReadResult, error)
 
@@ -81,6 +94,33 @@ type HardcoverClientInterface interface {
 	// CreateUserBook creates a new user book entry
 	CreateUserBook(ctx context.Context, editionID, status string) (string, error)
 
+	// CreateBookLevelUserBook adds a book to the library without an edition
+	CreateBookLevelUserBook(ctx context.Context, bookID, status string) (string, error)
+
+	// GetFinishedUserBooks returns the IDs of the user's finished books and when they were last read
+	GetFinishedUserBooks(ctx context.Context) (map[string]time.Time, error)
+
+	// GetUserBookRefs returns all of the user's books with their book and edition IDs
+	GetUserBookRefs(ctx context.Context) ([]UserBookRef, error)
+
+	// GetUserBooksByEditionIDs returns the user's books of the given editions
+	GetUserBooksByEditionIDs(ctx context.Context, editionIDs []int) ([]UserBookRef, error)
+
     // GetBookByID retrieves a book and basic related details by its Hardcover book ID
     GetBookByID(ctx context.Context, bookID string) (*models.HardcoverBook, error)
+
+	// GetBookAuthors returns the authors of a book by its Hardcover book ID
+	GetBookAuthors(ctx context.Context, bookID string) ([]models.Author, error)
+
+	// GetAudioEditionNarrators returns the audio editions of a book with their narrators
+	GetAudioEditionNarrators(ctx context.Context, bookID string) ([]EditionNarrators, error)
+
+	// GetUserLists returns the user's lists with the books on them
+	GetUserLists(ctx context.Context) ([]List, error)
+
+	// CreateList creates a list of the user and returns its ID
+	CreateList(ctx context.Context, name string) (int, error)
+
+	// AddBookToList adds a book to one of the user's lists
+	AddBookToList(ctx context.Context, listID, bookID int) error
 }
@@ -0,0 +1,67 @@
+package hardcover
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_DeleteUserBookRead(t *testing.T) {
+	tests := []struct {
+		name        string
+		response    map[string]interface{}
+		expectError string
+	}{
+		{
+			name:     "deleted",
+			response: map[string]interface{}{"id": 55, "error": nil},
+		},
+		{
+			name:        "error in response",
+			response:    map[string]interface{}{"id": nil, "error": "not allowed"},
+			expectError: "delete error: not allowed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if HandleGetCurrentUserIDQuery(t, w, r) {
+					return
+				}
+
+				var req struct {
+					Query     string                 `json:"query"`
+					Variables map[string]interface{} `json:"variables"`
+				}
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+				require.Contains(t, req.Query, "delete_user_book_read")
+				assert.Equal(t, float64(55), req.Variables["id"])
+
+				w.Header().Set("Content-Type", "application/json")
+				require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{
+					"data": map[string]interface{}{"delete_user_book_read": tt.response},
+				}))
+			}))
+			defer server.Close()
+
+			err := CreateTestClient(server).DeleteUserBookRead(context.Background(), 55)
+			if tt.expectError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectError)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("invalid ID", func(t *testing.T) {
+		client := NewClient("test-token", nil)
+		assert.ErrorIs(t, client.DeleteUserBookRead(context.Background(), 0), ErrInvalidInput)
+	})
+}
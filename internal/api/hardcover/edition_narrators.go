@@ -0,0 +1,99 @@
+package hardcover
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// audioEditionsLimit caps the number of audio editions of a book fetched by GetAudioEditionNarrators
+const audioEditionsLimit = 25
+
+// EditionNarrators is an audio edition of a book with the narrators credited on it
+type EditionNarrators struct {
+	EditionID int
+	ASIN      string
+	ISBN13    string
+	ISBN10    string
+	Narrators []models.Author
+}
+
+// GetAudioEditionNarrators returns the audio editions of a book with their narrators, so that the
+// edition narrated by a given narrator can be picked among several
+func (c *Client) GetAudioEditionNarrators(ctx context.Context, bookID string) ([]EditionNarrators, error) {
+	id, err := strconv.Atoi(strings.TrimSpace(bookID))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid book ID: %s", ErrInvalidInput, bookID)
+	}
+
+	const query = `
+	query GetAudioEditionNarrators($bookId: Int!, $formatId: Int!, $limit: Int!) {
+	  editions(
+		where: { book_id: { _eq: $bookId }, reading_format_id: { _eq: $formatId } },
+		order_by: { users_count: desc_nulls_last },
+		limit: $limit
+	  ) {
+		id
+		asin
+		isbn_13
+		isbn_10
+		contributions(limit: 20) { contribution author { id name } }
+	  }
+	}`
+
+	var response struct {
+		Editions []struct {
+			ID            int     `json:"id"`
+			ASIN          *string `json:"asin"`
+			ISBN13        *string `json:"isbn_13"`
+			ISBN10        *string `json:"isbn_10"`
+			Contributions []struct {
+				Contribution *string `json:"contribution"`
+				Author       *struct {
+					ID   int    `json:"id"`
+					Name string `json:"name"`
+				} `json:"author"`
+			} `json:"contributions"`
+		} `json:"editions"`
+	}
+
+	err = c.GraphQLQuery(ctx, query, map[string]interface{}{
+		"bookId":   id,
+		"formatId": ReadingFormatAudiobook,
+		"limit":    audioEditionsLimit,
+	}, &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audio editions: %w", err)
+	}
+
+	editions := make([]EditionNarrators, 0, len(response.Editions))
+	for _, e := range response.Editions {
+		edition := EditionNarrators{
+			EditionID: e.ID,
+			ASIN:      stringValue(e.ASIN),
+			ISBN13:    stringValue(e.ISBN13),
+			ISBN10:    stringValue(e.ISBN10),
+		}
+		for _, contribution := range e.Contributions {
+			if contribution.Author == nil || contribution.Contribution == nil ||
+				!strings.Contains(strings.ToLower(*contribution.Contribution), "narrator") {
+				continue
+			}
+			edition.Narrators = append(edition.Narrators, models.Author{
+				ID:   strconv.Itoa(contribution.Author.ID),
+				Name: contribution.Author.Name,
+			})
+		}
+		editions = append(editions, edition)
+	}
+
+	c.logger.Debug("Fetched audio edition narrators", map[string]interface{}{
+		"book_id":  bookID,
+		"editions": len(editions),
+	})
+
+	return editions, nil
+}
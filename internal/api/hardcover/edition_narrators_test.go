@@ -0,0 +1,46 @@
+package hardcover
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_GetAudioEditionNarrators(t *testing.T) {
+	client, server := CreateTestClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.EqualValues(t, 123, req.Variables["bookId"])
+		assert.EqualValues(t, ReadingFormatAudiobook, req.Variables["formatId"])
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"editions":[
+			{"id":456,"asin":"B0099SNFYY","isbn_13":null,"isbn_10":null,"contributions":[
+				{"contribution":null,"author":{"id":1,"name":"J.R.R. Tolkien"}},
+				{"contribution":"Narrator","author":{"id":2,"name":"Rob Inglis"}}
+			]},
+			{"id":789,"asin":null,"isbn_13":"9780008376086","isbn_10":null,"contributions":[
+				{"contribution":"Narrator","author":{"id":3,"name":"Andy Serkis"}}
+			]}
+		]}}`))
+	})
+	defer server.Close()
+
+	editions, err := client.GetAudioEditionNarrators(context.Background(), "123")
+	require.NoError(t, err)
+	assert.Equal(t, []EditionNarrators{
+		{EditionID: 456, ASIN: "B0099SNFYY", Narrators: []models.Author{{ID: "2", Name: "Rob Inglis"}}},
+		{EditionID: 789, ISBN13: "9780008376086", Narrators: []models.Author{{ID: "3", Name: "Andy Serkis"}}},
+	}, editions)
+
+	_, err = client.GetAudioEditionNarrators(context.Background(), "abc")
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
@@ -46,3 +46,32 @@ func GetBookID(err error) (string, bool) {
 	}
 	return "", false
 }
+
+// ErrBookHasNoEditions is returned, wrapped in a BookError with the book's ID, when a lookup finds
+// a book but none of its editions
+var ErrBookHasNoEditions = errors.New("book exists but has no editions")
+
+// ErrOperationDisabled is matched by errors for GraphQL operations disabled by configuration
+var ErrOperationDisabled = errors.New("operation disabled")
+
+// OperationDisabledError is returned instead of sending a GraphQL mutation that the client's
+// configuration doesn't allow
+type OperationDisabledError struct {
+	// Operation is the name of the refused GraphQL operation
+	Operation string
+}
+
+// Error implements the error interface
+func (e *OperationDisabledError) Error() string {
+	return fmt.Sprintf("GraphQL operation %q is disabled by configuration", e.Operation)
+}
+
+// Is makes the error match ErrOperationDisabled
+func (e *OperationDisabledError) Is(target error) bool {
+	return target == ErrOperationDisabled
+}
+
+// IsOperationDisabled reports whether err was caused by a GraphQL operation disabled by configuration
+func IsOperationDisabled(err error) bool {
+	return errors.Is(err, ErrOperationDisabled)
+}
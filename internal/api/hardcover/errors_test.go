@@ -1,10 +1,15 @@
 package hardcover
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBookError(t *testing.T) {
@@ -138,3 +143,28 @@ func TestGetBookID(t *testing.T) {
 		})
 	}
 }
+
+func TestIsAuthError(t *testing.T) {
+	assert.True(t, IsAuthError(fmt.Errorf("wrapped: %w", &HTTPError{StatusCode: http.StatusUnauthorized})))
+	assert.True(t, IsAuthError(&HTTPError{StatusCode: http.StatusForbidden}))
+	assert.False(t, IsAuthError(&HTTPError{StatusCode: http.StatusInternalServerError}))
+	assert.False(t, IsAuthError(errors.New("connection refused")))
+	assert.False(t, IsAuthError(nil))
+}
+
+func TestAuthErrorsAreNotRetried(t *testing.T) {
+	var requests int32
+	client, server := CreateTestClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":"Unable to verify token"}`))
+	})
+	defer server.Close()
+	client.maxRetries = 3
+
+	var result map[string]interface{}
+	err := client.GraphQLQuery(context.Background(), "query { me { id } }", nil, &result)
+	require.Error(t, err)
+	assert.True(t, IsAuthError(err))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
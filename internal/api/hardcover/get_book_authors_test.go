@@ -0,0 +1,48 @@
+package hardcover
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_GetBookAuthors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if HandleGetCurrentUserIDQuery(t, w, r) {
+			return
+		}
+
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Contains(t, req.Query, "GetBookAuthors")
+		assert.Equal(t, float64(42), req.Variables["id"])
+
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{"books": []map[string]interface{}{{
+				"contributions": []map[string]interface{}{
+					{"contribution": nil, "author": map[string]interface{}{"id": 1, "name": "Cormac McCarthy"}},
+					{"contribution": "Narrator", "author": map[string]interface{}{"id": 2, "name": "Tom Stechschulte"}},
+					{"contribution": "Author", "author": map[string]interface{}{"id": 3, "name": "Co Author"}},
+				},
+			}}},
+		}))
+	}))
+	defer server.Close()
+
+	authors, err := CreateTestClient(server).GetBookAuthors(context.Background(), "42")
+	require.NoError(t, err)
+	assert.Equal(t, []models.Author{{ID: "1", Name: "Cormac McCarthy"}, {ID: "3", Name: "Co Author"}}, authors)
+
+	_, err = CreateTestClient(server).GetBookAuthors(context.Background(), "not-a-number")
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
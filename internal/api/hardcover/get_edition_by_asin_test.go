@@ -166,7 +166,7 @@ func TestClient_GetEditionByASIN(t *testing.T) {
 					"editions": []map[string]interface{}{},
 				},
 			},
-			expectedError: "no book found with ASIN",
+			expectedError: "book exists but has no editions",
 		},
 	}
 
@@ -185,6 +185,10 @@ func TestClient_GetEdition(t *testing.T) {
 				if tt.errorContains != "" {
 					assert.Contains(t, err.Error(), tt.errorContains)
 				}
+				if tt.name == "edition not found" {
+					// Callers detect merged books by this error
+					assert.ErrorIs(t, err, ErrEditionNotFound)
+				}
 				return
 			}
 
@@ -0,0 +1,61 @@
+package hardcover
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_GetFinishedUserBooks(t *testing.T) {
+	var offsets []float64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if HandleGetCurrentUserIDQuery(t, w, r) {
+			return
+		}
+
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Contains(t, req.Query, "GetFinishedUserBooks")
+		assert.Equal(t, float64(1001), req.Variables["userId"])
+		assert.Equal(t, float64(3), req.Variables["statusId"])
+
+		offset := req.Variables["offset"].(float64)
+		offsets = append(offsets, offset)
+
+		// A full first page, then the rest
+		userBooks := []map[string]interface{}{}
+		if offset == 0 {
+			for i := 0; i < finishedUserBooksPageSize; i++ {
+				userBooks = append(userBooks, map[string]interface{}{"book_id": 1000 + i, "last_read_date": nil})
+			}
+			userBooks[0]["last_read_date"] = "2025-03-10"
+		} else {
+			userBooks = append(userBooks, map[string]interface{}{"book_id": 42, "last_read_date": "2024-12-31"})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{"user_books": userBooks},
+		}))
+	}))
+	defer server.Close()
+
+	client := CreateTestClient(server)
+	finished, err := client.GetFinishedUserBooks(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []float64{0, finishedUserBooksPageSize}, offsets)
+	assert.Len(t, finished, finishedUserBooksPageSize+1)
+	assert.Equal(t, time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), finished["1000"])
+	assert.True(t, finished["1001"].IsZero())
+	assert.Equal(t, time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), finished["42"])
+}
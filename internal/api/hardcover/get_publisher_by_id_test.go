@@ -0,0 +1,45 @@
+package hardcover
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_GetPublisherByID(t *testing.T) {
+	client, server := CreateTestClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.True(t, strings.Contains(req.Query, "publishers(where: {id: {_eq: $id}}"), req.Query)
+
+		w.Header().Set("Content-Type", "application/json")
+		if req.Variables["id"] == float64(42) {
+			_, _ = w.Write([]byte(`{"data":{"publishers":[{"id":42,"name":"Macmillan Audio"}]}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"publishers":[]}}`))
+	})
+	defer server.Close()
+
+	publisher, err := client.GetPublisherByID(context.Background(), "42")
+	require.NoError(t, err)
+	assert.Equal(t, "42", publisher.ID)
+	assert.Equal(t, "Macmillan Audio", publisher.Name)
+
+	_, err = client.GetPublisherByID(context.Background(), "7")
+	assert.ErrorContains(t, err, "publisher not found with ID: 7")
+
+	_, err = client.GetPublisherByID(context.Background(), "abc")
+	assert.ErrorContains(t, err, "invalid publisher ID format")
+
+	_, err = client.GetPublisherByID(context.Background(), "")
+	assert.ErrorContains(t, err, "empty publisher ID provided")
+}
@@ -26,6 +26,7 @@ func TestClient_GetUserBook(t *testing.T) {
 		mockStatusCode int
 		expected       *models.HardcoverBook
 		expectError    bool
+		expectErrorIs  error
 	}{
 		{
 			name:       "successful retrieval - READING status",
@@ -103,6 +104,35 @@ func TestClient_GetUserBook(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name:       "book tracked without an edition",
+			userBookID: "321",
+			mockResponse: map[string]interface{}{
+				"data": map[string]interface{}{
+					"user_books": []map[string]interface{}{
+						{
+							"id":        321,
+							"book_id":   654,
+							"status_id": 1, // WANT_TO_READ status
+							"book": map[string]interface{}{
+								"id":    654,
+								"title": "Book Without Edition",
+							},
+							"edition_id": nil,
+							"edition":    nil,
+						},
+					},
+				},
+			},
+			mockStatusCode: http.StatusOK,
+			expected: &models.HardcoverBook{
+				ID:           "654",
+				Title:        "Book Without Edition",
+				UserBookID:   "321",
+				BookStatusID: 1,
+			},
+			expectError: false,
+		},
 		{
 			name:       "user book not found",
 			userBookID: "999",
@@ -114,6 +144,7 @@ func TestClient_GetUserBook(t *testing.T) {
 			mockStatusCode: http.StatusOK,
 			expected:       nil,
 			expectError:    true,
+			expectErrorIs:  ErrUserBookNotFound,
 		},
 		{
 			name:       "graphql error",
@@ -186,6 +217,9 @@ func TestClient_GetUserBook(t *testing.T) {
 			// Check for expected errors
 			if tt.expectError {
 				assert.Error(t, err)
+				if tt.expectErrorIs != nil {
+					assert.ErrorIs(t, err, tt.expectErrorIs)
+				}
 				assert.Nil(t, got)
 				return
 			}
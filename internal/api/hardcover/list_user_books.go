@@ -0,0 +1,128 @@
+package hardcover
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// listUserBooksPageSize is the number of user books fetched per request by ListUserBooks
+const listUserBooksPageSize = 100
+
+// StatusID returns the Hardcover status ID for a status name such as "reading", "want-to-read" or
+// "FINISHED", or false if the name is unknown
+func StatusID(status string) (int, bool) {
+	id, ok := statusNameToID[strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(status), "-", "_"))]
+	return id, ok
+}
+
+// ListUserBooks returns all of the current user's books with the given status, including the
+// identifiers of their editions
+func (c *Client) ListUserBooks(ctx context.Context, status string) ([]models.HardcoverBook, error) {
+	statusID, ok := StatusID(status)
+	if !ok {
+		return nil, fmt.Errorf("unknown status: %s", status)
+	}
+
+	userID, err := c.GetCurrentUserID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current user ID: %w", err)
+	}
+
+	const query = `
+	query ListUserBooks($userId: Int!, $statusId: Int!, $limit: Int!, $offset: Int!) {
+	  user_books(
+		where: {
+		  user_id: {_eq: $userId},
+		  status_id: {_eq: $statusId}
+		},
+		order_by: {id: asc},
+		limit: $limit,
+		offset: $offset
+	  ) {
+		id
+		status_id
+		book {
+		  id
+		  title
+		  slug
+		}
+		edition_id
+		edition {
+		  asin
+		  isbn_13
+		  isbn_10
+		}
+	  }
+	}`
+
+	var books []models.HardcoverBook
+	for offset := 0; ; offset += listUserBooksPageSize {
+		var response struct {
+			UserBooks []struct {
+				ID       int `json:"id"`
+				StatusID int `json:"status_id"`
+				Book     struct {
+					ID    int    `json:"id"`
+					Title string `json:"title"`
+					Slug  string `json:"slug"`
+				} `json:"book"`
+				EditionID *int `json:"edition_id"`
+				Edition   *struct {
+					ASIN   *string `json:"asin"`
+					ISBN13 *string `json:"isbn_13"`
+					ISBN10 *string `json:"isbn_10"`
+				} `json:"edition"`
+			} `json:"user_books"`
+		}
+
+		err := c.GraphQLQuery(ctx, query, map[string]interface{}{
+			"userId":   userID,
+			"statusId": statusID,
+			"limit":    listUserBooksPageSize,
+			"offset":   offset,
+		}, &response)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list user books: %w", err)
+		}
+
+		for _, userBook := range response.UserBooks {
+			book := models.HardcoverBook{
+				ID:           strconv.Itoa(userBook.Book.ID),
+				UserBookID:   strconv.Itoa(userBook.ID),
+				Title:        userBook.Book.Title,
+				Slug:         userBook.Book.Slug,
+				BookStatusID: userBook.StatusID,
+			}
+			if userBook.EditionID != nil {
+				book.EditionID = strconv.Itoa(*userBook.EditionID)
+			}
+			if userBook.Edition != nil {
+				if userBook.Edition.ASIN != nil {
+					book.EditionASIN = *userBook.Edition.ASIN
+				}
+				if userBook.Edition.ISBN13 != nil {
+					book.EditionISBN13 = *userBook.Edition.ISBN13
+				}
+				if userBook.Edition.ISBN10 != nil {
+					book.EditionISBN10 = *userBook.Edition.ISBN10
+				}
+			}
+			books = append(books, book)
+		}
+
+		if len(response.UserBooks) < listUserBooksPageSize {
+			break
+		}
+	}
+
+	c.logger.Debug("Listed user books", map[string]interface{}{
+		"status": status,
+		"count":  len(books),
+	})
+
+	return books, nil
+}
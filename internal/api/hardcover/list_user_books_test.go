@@ -0,0 +1,81 @@
+package hardcover
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_ListUserBooks(t *testing.T) {
+	client, server := CreateTestClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		if HandleGetCurrentUserIDQuery(t, w, r) {
+			return
+		}
+
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Contains(t, req.Query, "ListUserBooks")
+		assert.Equal(t, float64(1001), req.Variables["userId"])
+		assert.Equal(t, float64(2), req.Variables["statusId"])
+		assert.Equal(t, float64(0), req.Variables["offset"])
+
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"user_books": []map[string]interface{}{
+					{
+						"id":         11,
+						"status_id":  2,
+						"book":       map[string]interface{}{"id": 101, "title": "Audiobook", "slug": "audiobook"},
+						"edition_id": 201,
+						"edition":    map[string]interface{}{"asin": "B00TEST123", "isbn_13": "9781234567890", "isbn_10": nil},
+					},
+					{
+						"id":         12,
+						"status_id":  2,
+						"book":       map[string]interface{}{"id": 102, "title": "No Edition"},
+						"edition_id": nil,
+						"edition":    nil,
+					},
+				},
+			},
+		}))
+	})
+	defer server.Close()
+
+	books, err := client.ListUserBooks(context.Background(), "reading")
+	require.NoError(t, err)
+	assert.Equal(t, []models.HardcoverBook{
+		{ID: "101", UserBookID: "11", Title: "Audiobook", Slug: "audiobook", BookStatusID: 2, EditionID: "201", EditionASIN: "B00TEST123", EditionISBN13: "9781234567890"},
+		{ID: "102", UserBookID: "12", Title: "No Edition", BookStatusID: 2},
+	}, books)
+
+	_, err = client.ListUserBooks(context.Background(), "shelved")
+	assert.Error(t, err)
+}
+
+func TestStatusID(t *testing.T) {
+	for status, expected := range map[string]int{
+		"reading":           2,
+		"want-to-read":      1,
+		"WANT_TO_READ":      1,
+		"currently_reading": 2,
+		"finished":          3,
+		"read":              3,
+	} {
+		id, ok := StatusID(status)
+		assert.True(t, ok, status)
+		assert.Equal(t, expected, id, status)
+	}
+
+	_, ok := StatusID("shelved")
+	assert.False(t, ok)
+}
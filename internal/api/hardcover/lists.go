@@ -0,0 +1,164 @@
+package hardcover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// List is one of the current user's Hardcover lists with the IDs of the books on it
+type List struct {
+	ID      int
+	Name    string
+	BookIDs []int
+}
+
+// GetUserLists returns the current user's lists with the books on them
+func (c *Client) GetUserLists(ctx context.Context) ([]List, error) {
+	userID, err := c.GetCurrentUserID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current user ID: %w", err)
+	}
+
+	const query = `
+	query GetUserLists($userId: Int!) {
+	  lists(
+		where: {
+		  user_id: {_eq: $userId}
+		},
+		order_by: {id: asc}
+	  ) {
+		id
+		name
+		list_books {
+		  book_id
+		}
+	  }
+	}`
+
+	var response struct {
+		Lists []struct {
+			ID        int    `json:"id"`
+			Name      string `json:"name"`
+			ListBooks []struct {
+				BookID int `json:"book_id"`
+			} `json:"list_books"`
+		} `json:"lists"`
+	}
+	if err := c.GraphQLQuery(ctx, query, map[string]interface{}{"userId": userID}, &response); err != nil {
+		return nil, fmt.Errorf("failed to get lists: %w", err)
+	}
+
+	lists := make([]List, 0, len(response.Lists))
+	for _, l := range response.Lists {
+		list := List{ID: l.ID, Name: l.Name, BookIDs: make([]int, 0, len(l.ListBooks))}
+		for _, listBook := range l.ListBooks {
+			list.BookIDs = append(list.BookIDs, listBook.BookID)
+		}
+		lists = append(lists, list)
+	}
+
+	c.logger.Debug("Fetched user lists", map[string]interface{}{
+		"userID": userID,
+		"count":  len(lists),
+	})
+
+	return lists, nil
+}
+
+// CreateList creates a list of the current user with the given name and returns its ID
+func (c *Client) CreateList(ctx context.Context, name string) (int, error) {
+	if strings.TrimSpace(name) == "" {
+		return 0, errors.New("list name is required")
+	}
+
+	log := c.logger.With(map[string]interface{}{
+		"name":   name,
+		"method": "CreateList",
+	})
+
+	const mutation = `
+	mutation CreateList($object: ListInput!) {
+	  insert_list(object: $object) {
+		id
+		errors
+	  }
+	}`
+
+	var result struct {
+		InsertList *struct {
+			ID     *int     `json:"id"`
+			Errors []string `json:"errors"`
+		} `json:"insert_list"`
+	}
+	variables := map[string]interface{}{
+		"object": map[string]interface{}{"name": name},
+	}
+	if err := c.GraphQLMutation(ctx, mutation, variables, &result); err != nil {
+		log.Error("Failed to create list", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return 0, fmt.Errorf("failed to create list: %w", err)
+	}
+
+	if result.InsertList == nil {
+		return 0, errors.New("failed to create list: empty response")
+	}
+	if len(result.InsertList.Errors) > 0 {
+		return 0, fmt.Errorf("failed to create list: %s", strings.Join(result.InsertList.Errors, "; "))
+	}
+	if result.InsertList.ID == nil || *result.InsertList.ID == 0 {
+		return 0, errors.New("failed to create list: no list ID returned")
+	}
+
+	log.Debug("Created list in Hardcover", map[string]interface{}{
+		"list_id": *result.InsertList.ID,
+	})
+	return *result.InsertList.ID, nil
+}
+
+// AddBookToList adds a book to one of the current user's lists
+func (c *Client) AddBookToList(ctx context.Context, listID, bookID int) error {
+	if listID <= 0 {
+		return fmt.Errorf("invalid list ID: %d", listID)
+	}
+	if bookID <= 0 {
+		return fmt.Errorf("invalid book ID: %d", bookID)
+	}
+
+	log := c.logger.With(map[string]interface{}{
+		"list_id": listID,
+		"book_id": bookID,
+		"method":  "AddBookToList",
+	})
+
+	const mutation = `
+	mutation AddBookToList($object: ListBookInput!) {
+	  insert_list_book(object: $object) {
+		id
+	  }
+	}`
+
+	var result struct {
+		InsertListBook *struct {
+			ID *int `json:"id"`
+		} `json:"insert_list_book"`
+	}
+	variables := map[string]interface{}{
+		"object": map[string]interface{}{"list_id": listID, "book_id": bookID},
+	}
+	if err := c.GraphQLMutation(ctx, mutation, variables, &result); err != nil {
+		log.Error("Failed to add book to list", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return fmt.Errorf("failed to add book to list: %w", err)
+	}
+
+	if result.InsertListBook == nil || result.InsertListBook.ID == nil {
+		return errors.New("failed to add book to list: empty response")
+	}
+
+	log.Debug("Added book to list in Hardcover", nil)
+	return nil
+}
@@ -0,0 +1,72 @@
+package hardcover
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Lists(t *testing.T) {
+	var requests []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if HandleGetCurrentUserIDQuery(t, w, r) {
+			return
+		}
+
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req.Variables)
+
+		var data map[string]interface{}
+		switch {
+		case strings.Contains(req.Query, "GetUserLists"):
+			assert.Equal(t, float64(1001), req.Variables["userId"])
+			data = map[string]interface{}{"lists": []map[string]interface{}{
+				{"id": 7, "name": "Favorites", "list_books": []map[string]interface{}{{"book_id": 101}, {"book_id": 102}}},
+				{"id": 8, "name": "Empty", "list_books": []map[string]interface{}{}},
+			}}
+		case strings.Contains(req.Query, "CreateList"):
+			data = map[string]interface{}{"insert_list": map[string]interface{}{"id": 9, "errors": nil}}
+		case strings.Contains(req.Query, "AddBookToList"):
+			data = map[string]interface{}{"insert_list_book": map[string]interface{}{"id": 55}}
+		default:
+			t.Errorf("unexpected query: %s", req.Query)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{"data": data}))
+	}))
+	defer server.Close()
+
+	client := CreateTestClient(server)
+	ctx := context.Background()
+
+	lists, err := client.GetUserLists(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []List{
+		{ID: 7, Name: "Favorites", BookIDs: []int{101, 102}},
+		{ID: 8, Name: "Empty", BookIDs: []int{}},
+	}, lists)
+
+	listID, err := client.CreateList(ctx, "New Collection")
+	require.NoError(t, err)
+	assert.Equal(t, 9, listID)
+	assert.Equal(t, map[string]interface{}{"name": "New Collection"}, requests[len(requests)-1]["object"])
+
+	require.NoError(t, client.AddBookToList(ctx, 9, 104))
+	assert.Equal(t, map[string]interface{}{"list_id": float64(9), "book_id": float64(104)}, requests[len(requests)-1]["object"])
+
+	// Invalid input isn't sent
+	_, err = client.CreateList(ctx, " ")
+	assert.Error(t, err)
+	assert.Error(t, client.AddBookToList(ctx, 0, 104))
+}
@@ -0,0 +1,84 @@
+package hardcover
+
+import (
+	"regexp"
+	"strings"
+)
+
+// operationHeader matches the operation type and optional name at the start of a GraphQL document
+var operationHeader = regexp.MustCompile(`^\s*(query|mutation|subscription)\b\s*([_A-Za-z][_0-9A-Za-z]*)?`)
+
+// operationPolicy decides which GraphQL mutations the client may send. Queries are always allowed,
+// as they can't change anything in Hardcover.
+type operationPolicy struct {
+	allowed  map[string]bool // lowercased operation names, nil allows all
+	disabled map[string]bool // lowercased operation names
+}
+
+// newOperationPolicy builds a policy from allowed and disabled operation names, compared
+// case-insensitively
+func newOperationPolicy(allowed, disabled []string) operationPolicy {
+	return operationPolicy{
+		allowed:  operationSet(allowed),
+		disabled: operationSet(disabled),
+	}
+}
+
+// operationSet returns the lowercased names as a set, or nil when there are none
+func operationSet(names []string) map[string]bool {
+	var set map[string]bool
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if set == nil {
+			set = make(map[string]bool)
+		}
+		set[name] = true
+	}
+	return set
+}
+
+// allows reports whether a mutation with the given operation name may be sent. Anonymous mutations
+// are only refused by an allowlist.
+func (p operationPolicy) allows(name string) bool {
+	key := strings.ToLower(name)
+	if p.allowed != nil && !p.allowed[key] {
+		return false
+	}
+	return !p.disabled[key]
+}
+
+// parseOperation returns the type and name of the first operation in a GraphQL document. Documents
+// in shorthand form ("{ ... }") are queries.
+func parseOperation(document string) (opType, name string) {
+	m := operationHeader.FindStringSubmatch(document)
+	if m == nil {
+		return string(queryOperation), ""
+	}
+	return m[1], m[2]
+}
+
+// checkOperationAllowed returns an OperationDisabledError for mutations the client's configuration
+// doesn't allow. The document itself is inspected, since some mutations are sent as queries.
+func (c *Client) checkOperationAllowed(document string) error {
+	opType, name := parseOperation(document)
+	if opType != string(mutationOperation) || c.operations.allows(name) {
+		return nil
+	}
+
+	c.logger.Warn("Refusing GraphQL mutation disabled by configuration", map[string]interface{}{
+		"operation": name,
+	})
+	return &OperationDisabledError{Operation: name}
+}
+
+// operationSpanName names the trace span of an operation after its name, or its type when it's
+// anonymous
+func operationSpanName(opType, name string) string {
+	if name == "" {
+		return opType
+	}
+	return name
+}
@@ -0,0 +1,92 @@
+package hardcover
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOperation(t *testing.T) {
+	tests := []struct {
+		document string
+		opType   string
+		name     string
+	}{
+		{"\n\tmutation EditionOwned($id: Int!) { edition_owned(id: $id) { id } }", "mutation", "EditionOwned"},
+		{"mutation($id: Int!) { edition_owned(id: $id) { id } }", "mutation", ""},
+		{"query GetCurrentUserID { me { id } }", "query", "GetCurrentUserID"},
+		{"{ me { id } }", "query", ""},
+	}
+	for _, tt := range tests {
+		opType, name := parseOperation(tt.document)
+		assert.Equal(t, tt.opType, opType, tt.document)
+		assert.Equal(t, tt.name, name, tt.document)
+	}
+}
+
+func TestOperationPolicy_Allows(t *testing.T) {
+	assert.True(t, newOperationPolicy(nil, nil).allows("EditionOwned"))
+
+	denied := newOperationPolicy(nil, []string{" editionowned "})
+	assert.False(t, denied.allows("EditionOwned"))
+	assert.True(t, denied.allows("InsertUserBook"))
+	assert.True(t, denied.allows(""))
+
+	allowed := newOperationPolicy([]string{"UpdateUserBookRead", "EditionOwned"}, []string{"EditionOwned"})
+	assert.True(t, allowed.allows("UpdateUserBookRead"))
+	assert.False(t, allowed.allows("EditionOwned"))
+	assert.False(t, allowed.allows("InsertUserBook"))
+	assert.False(t, allowed.allows(""))
+}
+
+func TestClient_DisabledOperation(t *testing.T) {
+	var buf bytes.Buffer
+	logger.ResetForTesting()
+	logger.Setup(logger.Config{Level: "debug", Format: "json", Output: &buf})
+	t.Cleanup(logger.ResetForTesting)
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"me":[{"id":1}]}}`))
+	}))
+	defer server.Close()
+
+	cfg := DefaultClientConfig()
+	cfg.BaseURL = server.URL
+	cfg.DisabledOperations = []string{"EditionOwned", "UpdateUserBookStatus"}
+	client := NewClientWithConfig(cfg, "test-token", logger.Get())
+
+	t.Run("denied mutation is blocked and logged", func(t *testing.T) {
+		buf.Reset()
+		err := client.MarkEditionAsOwned(context.Background(), 123)
+		require.Error(t, err)
+		assert.True(t, IsOperationDisabled(err))
+		var disabledErr *OperationDisabledError
+		require.ErrorAs(t, err, &disabledErr)
+		assert.Equal(t, "EditionOwned", disabledErr.Operation)
+		assert.Contains(t, buf.String(), "Refusing GraphQL mutation disabled by configuration")
+		assert.Equal(t, int32(0), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("mutation sent as a query is blocked", func(t *testing.T) {
+		err := client.UpdateUserBookStatus(context.Background(), UpdateUserBookStatusInput{ID: 1, StatusID: 3})
+		assert.True(t, IsOperationDisabled(err))
+		assert.Equal(t, int32(0), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("queries are unaffected", func(t *testing.T) {
+		userID, err := client.GetCurrentUserID(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, userID)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	})
+}
@@ -0,0 +1,119 @@
+package hardcover
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_InsertUserBookRead_ReadingFormat(t *testing.T) {
+	client, server := CreateTestClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		read, ok := req.Variables["user_book_read"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, float64(ReadingFormatEbook), read["reading_format_id"])
+		assert.Equal(t, float64(300), read["progress_seconds"])
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"insert_user_book_read": map[string]interface{}{"id": 456},
+			},
+		})
+	})
+	defer server.Close()
+
+	progressSeconds := 300
+	readingFormatID := ReadingFormatEbook
+	id, err := client.InsertUserBookRead(context.Background(), InsertUserBookReadInput{
+		UserBookID: 123,
+		DatesRead: DatesReadInput{
+			ProgressSeconds: &progressSeconds,
+			ReadingFormatID: &readingFormatID,
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 456, id)
+}
+
+func TestClient_UpdateUserBookRead_ReadingFormat(t *testing.T) {
+	client, server := CreateTestClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		object, ok := req.Variables["object"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, float64(ReadingFormatAudiobook), object["reading_format_id"])
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"update_user_book_read": map[string]interface{}{
+					"id":    789,
+					"error": nil,
+				},
+			},
+		})
+	})
+	defer server.Close()
+
+	_, err := client.UpdateUserBookRead(context.Background(), UpdateUserBookReadInput{
+		ID: 789,
+		Object: map[string]interface{}{
+			"progress_seconds":  120,
+			"reading_format_id": ReadingFormatAudiobook,
+		},
+	})
+	require.NoError(t, err)
+}
+
+func TestClient_Search_ReadingFormatFromContext(t *testing.T) {
+	var formatIDs []interface{}
+	client, server := CreateTestClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		formatIDs = append(formatIDs, req.Variables["format_id"])
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{"books": []interface{}{}},
+		})
+	})
+	defer server.Close()
+
+	tests := []struct {
+		format string
+		want   int
+	}{
+		{format: "", want: ReadingFormatAudiobook},
+		{format: "ebook", want: ReadingFormatEbook},
+		{format: "Physical", want: ReadingFormatPhysical},
+		{format: "both", want: ReadingFormatBoth},
+	}
+	for _, tt := range tests {
+		ctx := context.Background()
+		if tt.format != "" {
+			ctx = WithReadingFormat(ctx, tt.format)
+		}
+
+		formatIDs = nil
+		_, _ = client.SearchBookByISBN(ctx, "9780000000001")
+		_, _ = client.SearchBookByTitleAuthor(ctx, "Title", "Author")
+		assert.Equal(t, []interface{}{float64(tt.want), float64(tt.want)}, formatIDs, "format %q", tt.format)
+	}
+}
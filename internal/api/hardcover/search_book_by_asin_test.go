@@ -0,0 +1,141 @@
+package hardcover
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// asinSearchServer answers ASIN searches filtered by reading format with audioBooks and the ones
+// across all formats with anyBooks, counting both
+func asinSearchServer(t *testing.T, audioBooks, anyBooks []map[string]interface{}, filtered, unfiltered *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if HandleGetCurrentUserIDQuery(t, w, r) {
+			return
+		}
+
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Contains(t, req.Query, "BookByASIN")
+		assert.Equal(t, "B0EBOOK001", req.Variables["asin"])
+
+		books := anyBooks
+		if formatID, ok := req.Variables["format_id"]; ok {
+			assert.Equal(t, float64(2), formatID)
+			assert.Contains(t, req.Query, "reading_format")
+			books = audioBooks
+			*filtered++
+		} else {
+			assert.NotContains(t, req.Query, "reading_format:")
+			*unfiltered++
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{"books": books},
+		}))
+	}))
+}
+
+func asinSearchBook(editionID int, readingFormatID int) map[string]interface{} {
+	return map[string]interface{}{
+		"id":             123,
+		"title":          "The Road",
+		"book_status_id": 1,
+		"editions": []map[string]interface{}{{
+			"id":                editionID,
+			"asin":              "B0EBOOK001",
+			"reading_format_id": readingFormatID,
+		}},
+	}
+}
+
+func TestClient_SearchBookByASIN_ReadingFormats(t *testing.T) {
+	t.Run("audiobook edition", func(t *testing.T) {
+		var filtered, unfiltered int
+		server := asinSearchServer(t, []map[string]interface{}{asinSearchBook(456, 2)}, nil, &filtered, &unfiltered)
+		defer server.Close()
+
+		book, err := CreateTestClient(server).SearchBookByASIN(context.Background(), "B0EBOOK001")
+		require.NoError(t, err)
+		require.NotNil(t, book)
+		assert.Equal(t, "456", book.EditionID)
+		assert.Equal(t, 1, filtered)
+		assert.Zero(t, unfiltered)
+	})
+
+	t.Run("ASIN only on the ebook edition", func(t *testing.T) {
+		var filtered, unfiltered int
+		server := asinSearchServer(t, []map[string]interface{}{}, []map[string]interface{}{asinSearchBook(789, 4)}, &filtered, &unfiltered)
+		defer server.Close()
+		client := CreateTestClient(server)
+
+		// The format-aware search doesn't fall back to other formats by itself
+		book, err := client.SearchBookByASIN(context.Background(), "B0EBOOK001")
+		require.NoError(t, err)
+		assert.Nil(t, book)
+		assert.Equal(t, 1, filtered)
+		assert.Zero(t, unfiltered)
+
+		book, err = client.SearchBookByASINAnyFormat(context.Background(), "B0EBOOK001")
+		require.NoError(t, err)
+		require.NotNil(t, book)
+		assert.Equal(t, "123", book.ID)
+		assert.Equal(t, "The Road", book.Title)
+		assert.Empty(t, book.EditionID)
+		assert.Equal(t, "B0EBOOK001", book.EditionASIN)
+		assert.Equal(t, 1, filtered)
+		assert.Equal(t, 1, unfiltered)
+	})
+
+	t.Run("ASIN on no edition", func(t *testing.T) {
+		var filtered, unfiltered int
+		server := asinSearchServer(t, []map[string]interface{}{}, []map[string]interface{}{}, &filtered, &unfiltered)
+		defer server.Close()
+
+		book, err := CreateTestClient(server).SearchBookByASINAnyFormat(context.Background(), "B0EBOOK001")
+		require.NoError(t, err)
+		assert.Nil(t, book)
+		assert.Equal(t, 1, unfiltered)
+	})
+	t.Run("book without editions", func(t *testing.T) {
+		var filtered, unfiltered int
+		noEditions := map[string]interface{}{"id": 123, "title": "The Road", "editions": []map[string]interface{}{}}
+		server := asinSearchServer(t, []map[string]interface{}{noEditions}, []map[string]interface{}{}, &filtered, &unfiltered)
+		defer server.Close()
+
+		book, err := CreateTestClient(server).SearchBookByASIN(context.Background(), "B0EBOOK001")
+		assert.Nil(t, book)
+		require.ErrorIs(t, err, ErrBookHasNoEditions)
+		bookID, ok := GetBookID(err)
+		assert.True(t, ok)
+		assert.Equal(t, "123", bookID)
+		assert.Zero(t, unfiltered)
+	})
+}
+
+func TestClient_SearchBookByISBN13_NoEditions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{"books": []map[string]interface{}{
+				{"id": 321, "title": "Blood Meridian", "editions": []map[string]interface{}{}},
+			}},
+		}))
+	}))
+	defer server.Close()
+
+	book, err := CreateTestClient(server).SearchBookByISBN13(context.Background(), "9780679728757")
+	assert.Nil(t, book)
+	require.ErrorIs(t, err, ErrBookHasNoEditions)
+	bookID, _ := GetBookID(err)
+	assert.Equal(t, "321", bookID)
+}
@@ -0,0 +1,108 @@
+package hardcover
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookSlug(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"project-hail-mary", "project-hail-mary"},
+		{"  project-hail-mary ", "project-hail-mary"},
+		{"https://hardcover.app/books/project-hail-mary", "project-hail-mary"},
+		{"https://hardcover.app/books/project-hail-mary/editions/123?ref=x", "project-hail-mary"},
+		{"hardcover.app/books/project-hail-mary#reviews", "project-hail-mary"},
+		{"https://hardcover.app/authors/andy-weir", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, BookSlug(tt.input))
+		})
+	}
+}
+
+func TestClient_SearchBookBySlug(t *testing.T) {
+	books := map[string]interface{}{
+		"project-hail-mary": map[string]interface{}{
+			"id":             42,
+			"title":          "Project Hail Mary",
+			"slug":           "project-hail-mary",
+			"book_status_id": 1,
+			"canonical_id":   nil,
+			"editions": []map[string]interface{}{
+				{"id": 420, "asin": "B08G9PRS1K", "isbn_13": nil, "isbn_10": nil},
+			},
+		},
+		"print-only": map[string]interface{}{
+			"id":       7,
+			"title":    "Print Only",
+			"slug":     "print-only",
+			"editions": []map[string]interface{}{},
+		},
+	}
+
+	var formatIDs []float64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Contains(t, req.Query, "BookBySlug")
+		formatIDs = append(formatIDs, req.Variables["format_id"].(float64))
+
+		result := []interface{}{}
+		if book, ok := books[req.Variables["slug"].(string)]; ok {
+			result = append(result, book)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{"books": result},
+		}))
+	}))
+	defer server.Close()
+
+	client := CreateTestClient(server)
+
+	t.Run("found with edition", func(t *testing.T) {
+		hcBook, err := client.SearchBookBySlug(context.Background(), "https://hardcover.app/books/project-hail-mary")
+		require.NoError(t, err)
+		require.NotNil(t, hcBook)
+		assert.Equal(t, "42", hcBook.ID)
+		assert.Equal(t, "project-hail-mary", hcBook.Slug)
+		assert.Equal(t, "420", hcBook.EditionID)
+		assert.Equal(t, "B08G9PRS1K", hcBook.EditionASIN)
+	})
+
+	t.Run("found without edition of the format", func(t *testing.T) {
+		hcBook, err := client.SearchBookBySlug(WithReadingFormat(context.Background(), "ebook"), "print-only")
+		require.NoError(t, err)
+		require.NotNil(t, hcBook)
+		assert.Equal(t, "7", hcBook.ID)
+		assert.Empty(t, hcBook.EditionID)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		hcBook, err := client.SearchBookBySlug(context.Background(), "no-such-book")
+		require.NoError(t, err)
+		assert.Nil(t, hcBook)
+	})
+
+	t.Run("not a book URL", func(t *testing.T) {
+		_, err := client.SearchBookBySlug(context.Background(), "https://hardcover.app/authors/andy-weir")
+		assert.Error(t, err)
+	})
+
+	assert.Equal(t, []float64{ReadingFormatAudiobook, ReadingFormatEbook, ReadingFormatAudiobook}, formatIDs)
+}
@@ -0,0 +1,66 @@
+package hardcover
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClientWithConfig_SharedRateLimiter(t *testing.T) {
+	logger.Setup(logger.Config{Level: "debug", Format: "json"})
+	log := logger.Get()
+
+	var mu sync.Mutex
+	var requestTimes []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requestTimes = append(requestTimes, time.Now())
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	}))
+	defer server.Close()
+
+	const rate = 100 * time.Millisecond
+	shared := util.NewRateLimiter(rate, 1, 2, log)
+	newClient := func(token string) *Client {
+		cfg := DefaultClientConfig()
+		cfg.BaseURL = server.URL
+		cfg.RateLimiter = shared
+		return NewClientWithConfig(cfg, token, log)
+	}
+	clients := []*Client{newClient("token-1"), newClient("token-2")}
+	assert.Same(t, clients[0].rateLimiter, clients[1].rateLimiter)
+
+	// Both clients send their requests at the same time
+	const requestsPerClient = 3
+	var wg sync.WaitGroup
+	for _, client := range clients {
+		wg.Add(1)
+		go func(client *Client) {
+			defer wg.Done()
+			for i := 0; i < requestsPerClient; i++ {
+				var result map[string]interface{}
+				require.NoError(t, client.GraphQLQuery(context.Background(), "query { me { id } }", nil, &result))
+			}
+		}(client)
+	}
+	wg.Wait()
+
+	// The shared limiter spaces out the requests of both clients combined
+	require.Len(t, requestTimes, 2*requestsPerClient)
+	sort.Slice(requestTimes, func(i, j int) bool { return requestTimes[i].Before(requestTimes[j]) })
+	for i := 1; i < len(requestTimes); i++ {
+		gap := requestTimes[i].Sub(requestTimes[i-1])
+		assert.GreaterOrEqual(t, gap, rate-20*time.Millisecond, "gap before request %d", i)
+	}
+}
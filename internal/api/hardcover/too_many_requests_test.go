@@ -0,0 +1,72 @@
+package hardcover
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteGraphQLOperation_TooManyRequests(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= 2 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":"Throttled"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"me":[{"id":1001}]}}`))
+	}))
+	defer server.Close()
+
+	client := CreateTestClient(server)
+	client.maxRetries = 3
+	rate := client.rateLimiter.GetRate()
+
+	var result map[string]interface{}
+	require.NoError(t, client.GraphQLQuery(context.Background(), "query { me { id } }", nil, &result))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+
+	// Both 429s are counted and slow down the following requests
+	assert.Equal(t, uint64(2), client.rateLimiter.GetMetrics().RateLimited)
+	assert.Greater(t, client.rateLimiter.GetRate(), rate)
+}
+
+func TestOnTooManyRequests_RetryAfter(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	client := CreateTestClient(server)
+
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	resp.Header.Set("Retry-After", "20")
+	wait := client.onTooManyRequests(resp, "GetBook", 0)
+	assert.GreaterOrEqual(t, wait, 20*time.Second)
+	assert.Equal(t, uint64(1), client.rateLimiter.GetMetrics().RetryAfter)
+
+	// Without a valid Retry-After, the backoff is used
+	resp.Header.Set("Retry-After", "soon")
+	assert.LessOrEqual(t, client.onTooManyRequests(resp, "GetBook", 0), client.retryDelay)
+}
+
+func TestRateLimitBackoff(t *testing.T) {
+	base := 100 * time.Millisecond
+	for attempt := 0; attempt < 5; attempt++ {
+		exp := base << uint(attempt)
+		for i := 0; i < 20; i++ {
+			backoff := rateLimitBackoff(base, attempt)
+			assert.GreaterOrEqual(t, backoff, exp/2)
+			assert.LessOrEqual(t, backoff, exp)
+		}
+	}
+
+	// The backoff is capped, however many attempts were rate limited
+	assert.LessOrEqual(t, rateLimitBackoff(base, 20), maxRateLimitBackoff)
+	assert.LessOrEqual(t, rateLimitBackoff(base, 100), maxRateLimitBackoff)
+	assert.GreaterOrEqual(t, rateLimitBackoff(base, 100), maxRateLimitBackoff/2)
+}
@@ -0,0 +1,42 @@
+package hardcover
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestClient_TracesOperations(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	defer otel.SetTracerProvider(previous)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{"books": []interface{}{}},
+		}))
+	}))
+	defer server.Close()
+
+	client := CreateTestClient(server)
+	_, err := client.SearchBookBySlug(context.Background(), "project-hail-mary")
+	require.NoError(t, err)
+
+	// The HTTP request is a child of the GraphQL operation that sent it
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	request, operation := spans[0], spans[1]
+	assert.Equal(t, "hardcover BookBySlug", operation.Name)
+	assert.Equal(t, "hardcover POST", request.Name)
+	assert.Equal(t, operation.SpanContext.SpanID(), request.Parent.SpanID())
+}
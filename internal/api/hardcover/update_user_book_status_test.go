@@ -13,6 +13,7 @@ import (
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/util"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestClient_UpdateUserBookStatus(t *testing.T) {
@@ -196,3 +197,29 @@ func TestClient_UpdateUserBookStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_UpdateUserBookStatus_DidNotFinish(t *testing.T) {
+	var statusIDs []float64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		statusIDs = append(statusIDs, req.Variables["status_id"].(float64))
+
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"update_user_book": map[string]interface{}{"id": 123},
+			},
+		}))
+	}))
+	defer server.Close()
+
+	client := CreateTestClient(server)
+	for _, status := range []string{"DID_NOT_FINISH", "dnf"} {
+		require.NoError(t, client.UpdateUserBookStatus(context.Background(), UpdateUserBookStatusInput{ID: 123, Status: status}))
+	}
+	assert.Equal(t, []float64{5, 5}, statusIDs)
+	assert.Equal(t, 5, statusNameToID["DID_NOT_FINISH"])
+}
@@ -0,0 +1,151 @@
+package hardcover
+
+import (
+	"context"
+	"fmt"
+)
+
+// userBookRefsPageSize is the number of user books fetched per request by GetUserBookRefs
+const userBookRefsPageSize = 500
+
+// userBooksByEditionBatchSize is the number of editions looked up per request by
+// GetUserBooksByEditionIDs
+const userBooksByEditionBatchSize = 100
+
+// UserBookRef identifies one of the current user's books by its Hardcover book and edition
+type UserBookRef struct {
+	ID     int
+	BookID int
+	// EditionID is 0 for books added at the book level, without an edition
+	EditionID int
+}
+
+// GetUserBookRefs returns all of the current user's books with their book and edition IDs, so
+// that user books can be looked up without a request per book
+func (c *Client) GetUserBookRefs(ctx context.Context) ([]UserBookRef, error) {
+	userID, err := c.GetCurrentUserID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current user ID: %w", err)
+	}
+
+	const query = `
+	query GetUserBookRefs($userId: Int!, $limit: Int!, $offset: Int!) {
+	  user_books(
+		where: {
+		  user_id: {_eq: $userId}
+		},
+		order_by: {id: asc},
+		limit: $limit,
+		offset: $offset
+	  ) {
+		id
+		book_id
+		edition_id
+	  }
+	}`
+
+	var refs []UserBookRef
+	for offset := 0; ; offset += userBookRefsPageSize {
+		var response struct {
+			UserBooks []struct {
+				ID        int  `json:"id"`
+				BookID    int  `json:"book_id"`
+				EditionID *int `json:"edition_id"`
+			} `json:"user_books"`
+		}
+
+		err := c.GraphQLQuery(ctx, query, map[string]interface{}{
+			"userId": userID,
+			"limit":  userBookRefsPageSize,
+			"offset": offset,
+		}, &response)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user books: %w", err)
+		}
+
+		for _, userBook := range response.UserBooks {
+			ref := UserBookRef{ID: userBook.ID, BookID: userBook.BookID}
+			if userBook.EditionID != nil {
+				ref.EditionID = *userBook.EditionID
+			}
+			refs = append(refs, ref)
+		}
+		if len(response.UserBooks) < userBookRefsPageSize {
+			break
+		}
+	}
+
+	c.logger.Debug("Fetched user book IDs", map[string]interface{}{
+		"userID": userID,
+		"count":  len(refs),
+	})
+
+	return refs, nil
+}
+
+// GetUserBooksByEditionIDs returns the current user's books of the given editions, looking them up
+// userBooksByEditionBatchSize editions per request. Editions the user has no book of are left out.
+func (c *Client) GetUserBooksByEditionIDs(ctx context.Context, editionIDs []int) ([]UserBookRef, error) {
+	if len(editionIDs) == 0 {
+		return nil, nil
+	}
+
+	userID, err := c.GetCurrentUserID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current user ID: %w", err)
+	}
+
+	const query = `
+	query GetUserBooksByEditionIDs($userId: Int!, $ids: [Int!]!) {
+	  user_books(
+		where: {
+		  user_id: {_eq: $userId},
+		  edition_id: {_in: $ids}
+		}
+	  ) {
+		id
+		book_id
+		edition_id
+	  }
+	}`
+
+	var refs []UserBookRef
+	for start := 0; start < len(editionIDs); start += userBooksByEditionBatchSize {
+		end := start + userBooksByEditionBatchSize
+		if end > len(editionIDs) {
+			end = len(editionIDs)
+		}
+
+		var response struct {
+			UserBooks []struct {
+				ID        int  `json:"id"`
+				BookID    int  `json:"book_id"`
+				EditionID *int `json:"edition_id"`
+			} `json:"user_books"`
+		}
+
+		err := c.GraphQLQuery(ctx, query, map[string]interface{}{
+			"userId": userID,
+			"ids":    editionIDs[start:end],
+		}, &response)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user books by edition: %w", err)
+		}
+
+		for _, userBook := range response.UserBooks {
+			ref := UserBookRef{ID: userBook.ID, BookID: userBook.BookID}
+			if userBook.EditionID != nil {
+				ref.EditionID = *userBook.EditionID
+			}
+			refs = append(refs, ref)
+		}
+	}
+
+	c.logger.Debug("Fetched user books by edition", map[string]interface{}{
+		"userID":   userID,
+		"editions": len(editionIDs),
+		"count":    len(refs),
+	})
+
+	return refs, nil
+}
@@ -0,0 +1,108 @@
+package hardcover
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_GetUserBookRefs(t *testing.T) {
+	var offsets []float64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if HandleGetCurrentUserIDQuery(t, w, r) {
+			return
+		}
+
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Contains(t, req.Query, "GetUserBookRefs")
+		assert.Equal(t, float64(1001), req.Variables["userId"])
+
+		offset := req.Variables["offset"].(float64)
+		offsets = append(offsets, offset)
+
+		// A full first page, then a book added without an edition
+		userBooks := []map[string]interface{}{}
+		if offset == 0 {
+			for i := 0; i < userBookRefsPageSize; i++ {
+				userBooks = append(userBooks, map[string]interface{}{"id": i + 1, "book_id": 1000 + i, "edition_id": 5000 + i})
+			}
+		} else {
+			userBooks = append(userBooks, map[string]interface{}{"id": 9999, "book_id": 42, "edition_id": nil})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{"user_books": userBooks},
+		}))
+	}))
+	defer server.Close()
+
+	client := CreateTestClient(server)
+	refs, err := client.GetUserBookRefs(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []float64{0, userBookRefsPageSize}, offsets)
+	require.Len(t, refs, userBookRefsPageSize+1)
+	assert.Equal(t, UserBookRef{ID: 1, BookID: 1000, EditionID: 5000}, refs[0])
+	assert.Equal(t, UserBookRef{ID: 9999, BookID: 42}, refs[userBookRefsPageSize])
+}
+
+func TestClient_GetUserBooksByEditionIDs(t *testing.T) {
+	var batches [][]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if HandleGetCurrentUserIDQuery(t, w, r) {
+			return
+		}
+
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Contains(t, req.Query, "GetUserBooksByEditionIDs")
+		assert.Equal(t, float64(1001), req.Variables["userId"])
+		ids := req.Variables["ids"].([]interface{})
+		batches = append(batches, ids)
+
+		// The user only has a book of the first edition of each batch
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{"user_books": []map[string]interface{}{
+				{"id": int(ids[0].(float64)) + 10000, "book_id": 1, "edition_id": ids[0]},
+			}},
+		}))
+	}))
+	defer server.Close()
+
+	editionIDs := make([]int, userBooksByEditionBatchSize+1)
+	for i := range editionIDs {
+		editionIDs[i] = i + 1
+	}
+
+	client := CreateTestClient(server)
+	refs, err := client.GetUserBooksByEditionIDs(context.Background(), editionIDs)
+	require.NoError(t, err)
+
+	require.Len(t, batches, 2)
+	assert.Len(t, batches[0], userBooksByEditionBatchSize)
+	assert.Equal(t, []interface{}{float64(userBooksByEditionBatchSize + 1)}, batches[1])
+	assert.Equal(t, []UserBookRef{
+		{ID: 10001, BookID: 1, EditionID: 1},
+		{ID: 10000 + userBooksByEditionBatchSize + 1, BookID: 1, EditionID: userBooksByEditionBatchSize + 1},
+	}, refs)
+
+	// No editions, no requests
+	refs, err = client.GetUserBooksByEditionIDs(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, refs)
+	assert.Len(t, batches, 2)
+}
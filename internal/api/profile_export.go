@@ -0,0 +1,210 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/database"
+)
+
+// profileExportVersion is the current version of the portable profile format
+const profileExportVersion = 1
+
+// ProfileExport is a portable representation of a sync profile's configuration that can be
+// moved between instances. Tokens are only included when explicitly requested.
+type ProfileExport struct {
+	Version             int                     `json:"version"`
+	ExportedAt          time.Time               `json:"exported_at"`
+	Name                string                  `json:"name"`
+	AudiobookshelfURL   string                  `json:"audiobookshelf_url"`
+	AudiobookshelfToken string                  `json:"audiobookshelf_token,omitempty"`
+	HardcoverToken      string                  `json:"hardcover_token,omitempty"`
+	SecretsIncluded     bool                    `json:"secrets_included"`
+	SyncConfig          database.SyncConfigData `json:"sync_config"`
+}
+
+// newProfileExport builds a portable export of the given profile
+func newProfileExport(p *database.ProfileWithTokens, includeSecrets bool) ProfileExport {
+	export := ProfileExport{
+		Version:           profileExportVersion,
+		ExportedAt:        time.Now().UTC(),
+		Name:              p.Profile.Name,
+		AudiobookshelfURL: p.AudiobookshelfURL,
+		SecretsIncluded:   includeSecrets,
+		SyncConfig:        p.SyncConfig,
+	}
+
+	// The state file location is specific to this instance
+	export.SyncConfig.StateFile = ""
+
+	if includeSecrets {
+		export.AudiobookshelfToken = p.AudiobookshelfToken
+		export.HardcoverToken = p.HardcoverToken
+	}
+
+	return export
+}
+
+// Validate checks that the export can be applied to a profile
+func (e ProfileExport) Validate() error {
+	if e.Version != profileExportVersion {
+		return fmt.Errorf("unsupported profile export version %d (expected %d)", e.Version, profileExportVersion)
+	}
+
+	if e.AudiobookshelfURL == "" {
+		return fmt.Errorf("audiobookshelf_url is required")
+	}
+	u, err := url.Parse(e.AudiobookshelfURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("audiobookshelf_url must be an absolute http(s) URL")
+	}
+
+	if e.SyncConfig.SyncInterval != "" {
+		if _, err := time.ParseDuration(e.SyncConfig.SyncInterval); err != nil {
+			return fmt.Errorf("invalid sync_config.sync_interval: %w", err)
+		}
+	}
+	if e.SyncConfig.MinimumProgress < 0 || e.SyncConfig.MinimumProgress > 1 {
+		return fmt.Errorf("sync_config.minimum_progress must be between 0 and 1")
+	}
+	if e.SyncConfig.MinChangeThreshold < 0 {
+		return fmt.Errorf("sync_config.min_change_threshold must not be negative")
+	}
+	if e.SyncConfig.TestBookLimit < 0 {
+		return fmt.Errorf("sync_config.test_book_limit must not be negative")
+	}
+
+	return nil
+}
+
+// ExportProfile handles GET /api/profiles/{id}/export
+// Tokens are redacted unless the request sets include_secrets=true.
+func (h *Handler) ExportProfile(w http.ResponseWriter, r *http.Request) {
+	profileID := h.extractProfileID(r.URL.Path)
+	if profileID == "" {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Profile ID is required")
+		return
+	}
+
+	includeSecrets := false
+	if v := r.URL.Query().Get("include_secrets"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, "Invalid include_secrets value")
+			return
+		}
+		includeSecrets = parsed
+	}
+
+	profile, err := h.multiUserService.GetProfile(profileID)
+	if err != nil {
+		h.log.Error("Failed to get sync profile for export: " + err.Error())
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve sync profile")
+		return
+	}
+	if profile == nil {
+		h.writeErrorResponse(w, http.StatusNotFound, "Sync profile not found")
+		return
+	}
+
+	if includeSecrets {
+		h.log.Warn("Exporting sync profile including secrets", map[string]interface{}{
+			"profile_id": profileID,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "profile-"+profileID+".json"))
+	w.WriteHeader(http.StatusOK)
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(newProfileExport(profile, includeSecrets)); err != nil {
+		h.log.Error("Failed to write profile export: " + err.Error())
+	}
+}
+
+// ImportProfile handles POST /api/profiles/{id}/import
+// The export is applied to an existing profile, preserving its tokens when the export is
+// redacted. If the profile does not exist it is created, which requires both tokens.
+func (h *Handler) ImportProfile(w http.ResponseWriter, r *http.Request) {
+	profileID := h.extractProfileID(r.URL.Path)
+	if profileID == "" {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Profile ID is required")
+		return
+	}
+
+	var export ProfileExport
+	if err := json.NewDecoder(r.Body).Decode(&export); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := export.Validate(); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid profile export: "+err.Error())
+		return
+	}
+
+	existing, err := h.multiUserService.GetProfile(profileID)
+	if err != nil {
+		h.log.Error("Failed to get sync profile for import: " + err.Error())
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve sync profile")
+		return
+	}
+
+	if existing == nil {
+		if export.AudiobookshelfToken == "" || export.HardcoverToken == "" {
+			h.writeErrorResponse(w, http.StatusBadRequest, "Tokens are required to create a new profile from an export")
+			return
+		}
+
+		name := strings.TrimSpace(export.Name)
+		if name == "" {
+			name = profileID
+		}
+
+		if err := h.multiUserService.CreateProfile(profileID, name, export.AudiobookshelfURL, export.AudiobookshelfToken, export.HardcoverToken, export.SyncConfig); err != nil {
+			h.log.Error("Failed to create sync profile from import: " + err.Error())
+			h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to create sync profile")
+			return
+		}
+	} else {
+		// Keep this instance's state file location
+		export.SyncConfig.StateFile = existing.SyncConfig.StateFile
+
+		// Empty tokens preserve the existing ones
+		if err := h.multiUserService.UpdateProfileConfig(profileID, export.AudiobookshelfURL, export.AudiobookshelfToken, export.HardcoverToken, export.SyncConfig); err != nil {
+			h.log.Error("Failed to apply imported sync profile config: " + err.Error())
+			h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to update sync profile configuration")
+			return
+		}
+
+		if name := strings.TrimSpace(export.Name); name != "" && name != existing.Profile.Name {
+			if err := h.multiUserService.UpdateProfile(profileID, name); err != nil {
+				h.log.Error("Failed to update imported sync profile name: " + err.Error())
+				h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to update sync profile")
+				return
+			}
+		}
+	}
+
+	h.log.Info("Imported sync profile", map[string]interface{}{
+		"profile_id":       profileID,
+		"created":          existing == nil,
+		"secrets_included": export.AudiobookshelfToken != "" || export.HardcoverToken != "",
+	})
+
+	profile, err := h.multiUserService.GetProfile(profileID)
+	if err != nil {
+		h.log.Error("Failed to get imported sync profile: " + err.Error())
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve imported sync profile")
+		return
+	}
+
+	h.writeSuccessResponse(w, h.buildProfileResponse(profile))
+}
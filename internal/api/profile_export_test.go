@@ -0,0 +1,139 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/crypto"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/database"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/multiuser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestProfileHandler(t *testing.T) (*Handler, *multiuser.MultiUserService) {
+	t.Helper()
+	log := logger.Get()
+
+	db, err := database.NewDatabase(&database.DatabaseConfig{
+		Type: database.DatabaseTypeSQLite,
+		Path: filepath.Join(t.TempDir(), "test.db"),
+	}, log)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	encryptor, err := crypto.NewEncryptionManagerWithKey(bytes.Repeat([]byte("k"), 32), log)
+	require.NoError(t, err)
+
+	svc := multiuser.NewMultiUserService(database.NewRepository(db, encryptor, log), config.DefaultConfig(), log)
+	return NewHandler(svc, nil, log), svc
+}
+
+func exportProfile(t *testing.T, h *Handler, path string) ProfileExport {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ExportProfile(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var export ProfileExport
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &export))
+	return export
+}
+
+func importProfile(h *Handler, profileID string, export ProfileExport) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(export)
+	rec := httptest.NewRecorder()
+	h.ImportProfile(rec, httptest.NewRequest(http.MethodPost, "/profiles/"+profileID+"/import", bytes.NewReader(body)))
+	return rec
+}
+
+func TestProfileExportImportRoundTrip(t *testing.T) {
+	h, svc := newTestProfileHandler(t)
+
+	syncConfig := database.SyncConfigData{
+		Incremental:     true,
+		StateFile:       "/data/alice_state.json",
+		SyncInterval:    "1h",
+		MinimumProgress: 0.05,
+		SyncWantToRead:  true,
+		SyncOwned:       true,
+	}
+	syncConfig.Libraries.Include = []string{"Audiobooks"}
+	syncConfig.Libraries.Exclude = []string{"Podcasts"}
+	require.NoError(t, svc.CreateProfile("alice", "Alice", "https://abs.example.com", "abs-token", "hc-token", syncConfig))
+
+	t.Run("secrets are redacted by default", func(t *testing.T) {
+		export := exportProfile(t, h, "/profiles/alice/export")
+		assert.False(t, export.SecretsIncluded)
+		assert.Empty(t, export.AudiobookshelfToken)
+		assert.Empty(t, export.HardcoverToken)
+		assert.Empty(t, export.SyncConfig.StateFile)
+		assert.Equal(t, "https://abs.example.com", export.AudiobookshelfURL)
+	})
+
+	t.Run("export with secrets imports into a new profile", func(t *testing.T) {
+		export := exportProfile(t, h, "/profiles/alice/export?include_secrets=true")
+		assert.True(t, export.SecretsIncluded)
+
+		rec := importProfile(h, "alice-copy", export)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		imported, err := svc.GetProfile("alice-copy")
+		require.NoError(t, err)
+		require.NotNil(t, imported)
+		assert.Equal(t, "Alice", imported.Profile.Name)
+		assert.Equal(t, "https://abs.example.com", imported.AudiobookshelfURL)
+		assert.Equal(t, "abs-token", imported.AudiobookshelfToken)
+		assert.Equal(t, "hc-token", imported.HardcoverToken)
+		assert.Equal(t, []string{"Audiobooks"}, imported.SyncConfig.Libraries.Include)
+		assert.Equal(t, []string{"Podcasts"}, imported.SyncConfig.Libraries.Exclude)
+		assert.Equal(t, "1h", imported.SyncConfig.SyncInterval)
+		assert.True(t, imported.SyncConfig.Incremental)
+	})
+
+	t.Run("redacted export applied to existing profile keeps its tokens", func(t *testing.T) {
+		require.NoError(t, svc.CreateProfile("bob", "Bob", "https://old.example.com", "bob-abs", "bob-hc", database.SyncConfigData{StateFile: "/data/bob_state.json"}))
+
+		export := exportProfile(t, h, "/profiles/alice/export")
+		rec := importProfile(h, "bob", export)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		bob, err := svc.GetProfile("bob")
+		require.NoError(t, err)
+		assert.Equal(t, "https://abs.example.com", bob.AudiobookshelfURL)
+		assert.Equal(t, "bob-abs", bob.AudiobookshelfToken)
+		assert.Equal(t, "bob-hc", bob.HardcoverToken)
+		assert.Equal(t, "/data/bob_state.json", bob.SyncConfig.StateFile)
+		assert.Equal(t, []string{"Audiobooks"}, bob.SyncConfig.Libraries.Include)
+	})
+
+	t.Run("redacted export cannot create a new profile", func(t *testing.T) {
+		export := exportProfile(t, h, "/profiles/alice/export")
+		rec := importProfile(h, "carol", export)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("invalid export is rejected", func(t *testing.T) {
+		export := exportProfile(t, h, "/profiles/alice/export")
+		export.SyncConfig.SyncInterval = "soon"
+		rec := importProfile(h, "alice", export)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		export = exportProfile(t, h, "/profiles/alice/export")
+		export.Version = 99
+		rec = importProfile(h, "alice", export)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("unknown profile export returns not found", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.ExportProfile(rec, httptest.NewRequest(http.MethodGet, "/profiles/nobody/export", nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
@@ -2,8 +2,10 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -57,9 +59,196 @@ type Config struct {
 			Include []string `yaml:"include" env:"SYNC_LIBRARIES_INCLUDE"`
 			// Exclude these libraries (empty = none)
 			Exclude []string `yaml:"exclude" env:"SYNC_LIBRARIES_EXCLUDE"`
+			// Overrides holds settings for single libraries, keyed by library name (case-insensitive)
+			// or ID
+			Overrides map[string]LibraryOverride `yaml:"overrides"`
 		} `yaml:"libraries"`
 		// IncludeEbooks controls whether items with mediaType "ebook" are included in sync (default: false)
 		IncludeEbooks bool `yaml:"include_ebooks" env:"SYNC_INCLUDE_EBOOKS"`
+		// SkipPodcasts skips podcast libraries and items, which have no Hardcover editions (default: false)
+		SkipPodcasts bool `yaml:"skip_podcasts" env:"SYNC_SKIP_PODCASTS"`
+		// VerifyNarrator records a mismatch when an ASIN-matched edition lists different narrators than ABS (default: false)
+		VerifyNarrator bool `yaml:"verify_narrator" env:"SYNC_VERIFY_NARRATOR"`
+		// MatchNarrator prefers, among the audio editions of a book matched by ASIN or ISBN, the one
+		// narrated by the Audiobookshelf narrator, so progress lands on the narration actually listened
+		// to (default: false). Not applied with strict_identifier_match, which pins the matched edition.
+		MatchNarrator bool `yaml:"match_narrator" env:"SYNC_MATCH_NARRATOR"`
+		// ExcludeTitlePatterns are regular expressions; title/author search results matching any of them
+		// are skipped unless the Audiobookshelf title matches the same pattern (empty list = no filtering)
+		ExcludeTitlePatterns []string `yaml:"exclude_title_patterns" env:"SYNC_EXCLUDE_TITLE_PATTERNS"`
+		// MaxProgressJumpSeconds refuses progress updates that differ from Hardcover by more than this
+		// many seconds and records a mismatch instead (0 = no cap)
+		MaxProgressJumpSeconds int `yaml:"max_progress_jump_seconds" env:"SYNC_MAX_PROGRESS_JUMP_SECONDS"`
+		// ProgressSource selects which Audiobookshelf progress entry is used when both media progress and
+		// listening sessions exist for a book: "media", "sessions" or "most_recent" (default: "media")
+		ProgressSource string `yaml:"progress_source" env:"SYNC_PROGRESS_SOURCE"`
+		// FinishedSource decides whether a book is finished when the library item's progress and the
+		// /api/me progress disagree: "media_progress", "item", "either" or "both" (default: "media_progress")
+		FinishedSource string `yaml:"finished_source" env:"SYNC_FINISHED_SOURCE"`
+		// Direction is which way the progress of books being read is synced: "abs_to_hc",
+		// "hc_to_abs" or "both" (default: "abs_to_hc"). With "hc_to_abs" neither progress nor
+		// statuses are written to Hardcover, though matched books are still added to the library.
+		Direction string `yaml:"direction" env:"SYNC_DIRECTION"`
+		// ConflictResolution decides whose progress is synced when Direction is "both" and the
+		// progress differs: "newest", "abs" or "hardcover" (default: "newest")
+		ConflictResolution string `yaml:"conflict_resolution" env:"SYNC_CONFLICT_RESOLUTION"`
+		// SourceTag is a Hardcover tag (e.g. "abs-sync") applied to synced books so they can be
+		// identified as coming from this tool (empty = no tagging)
+		SourceTag string `yaml:"source_tag" env:"SYNC_SOURCE_TAG"`
+		// StateFlushInterval is how often sync state changes are written to disk while a sync is running
+		// (default: 30s, 0 = only save at the end of a sync)
+		StateFlushInterval time.Duration `yaml:"state_flush_interval" env:"SYNC_STATE_FLUSH_INTERVAL"`
+		// RepairStateKeys validates and repairs the book keys of the sync state on every load; state
+		// files written by older versions are always repaired (default: false)
+		RepairStateKeys bool `yaml:"repair_state_keys" env:"SYNC_REPAIR_STATE_KEYS"`
+		// ReadingFormat is the Hardcover reading format set on created and updated reads: "auto" (audiobook,
+		// or ebook for ebook items), "audiobook", "ebook", "physical" or "both" (default: "auto")
+		ReadingFormat string `yaml:"reading_format" env:"SYNC_READING_FORMAT"`
+		// AuthFailureThreshold aborts a sync after this many consecutive authentication failures from
+		// Hardcover or Audiobookshelf (default: 3, 0 = never abort)
+		AuthFailureThreshold int `yaml:"auth_failure_threshold" env:"SYNC_AUTH_FAILURE_THRESHOLD"`
+		// AuthFailureBackoff is how long syncs are paused after being aborted for authentication failures (default: 1h)
+		AuthFailureBackoff time.Duration `yaml:"auth_failure_backoff" env:"SYNC_AUTH_FAILURE_BACKOFF"`
+		// ReloadTokenOnAuthFailure reloads a profile's tokens from the database when its sync fails with
+		// an authentication error in multi-user mode, and runs the sync once more if they were changed
+		// since it started (default: false)
+		ReloadTokenOnAuthFailure bool `yaml:"reload_token_on_auth_failure" env:"SYNC_RELOAD_TOKEN_ON_AUTH_FAILURE"`
+		// NotFoundGracePeriod defers recording books that can't be found in Hardcover until this long after
+		// they were first seen, since newly published books may not be on Hardcover yet (0 = record immediately)
+		NotFoundGracePeriod time.Duration `yaml:"not_found_grace_period" env:"SYNC_NOT_FOUND_GRACE_PERIOD"`
+		// SkipUnchangedBooks skips books whose Audiobookshelf item and progress haven't changed since the
+		// last successful sync before any other work is done (only in incremental mode)
+		SkipUnchangedBooks bool `yaml:"skip_unchanged_books" env:"SYNC_SKIP_UNCHANGED_BOOKS"`
+		// MissingProgressPolicy decides what happens to library items without any media progress or
+		// listening session for the user: "want_to_read" or "skip" (default: "want_to_read")
+		MissingProgressPolicy string `yaml:"missing_progress_policy" env:"SYNC_MISSING_PROGRESS_POLICY"`
+		// PerBookTimeout limits how long a single book may take to process; books that time out are
+		// retried on the next run while the current run continues (0 = no limit)
+		PerBookTimeout time.Duration `yaml:"per_book_timeout" env:"SYNC_PER_BOOK_TIMEOUT"`
+		// MaxRunDuration limits how long a single sync run processes books; when it runs out, the
+		// state is saved and the next run continues with the remaining books (0 = no limit)
+		MaxRunDuration time.Duration `yaml:"max_run_duration" env:"SYNC_MAX_RUN_DURATION"`
+		// PrefetchLibraries is how many pages of library items are fetched ahead while earlier items
+		// are still being processed, overlapping fetching and processing within and across libraries
+		// (0 = fetch each library in full when it's processed)
+		PrefetchLibraries int `yaml:"prefetch_libraries" env:"SYNC_PREFETCH_LIBRARIES"`
+		// AlwaysReverify lists book statuses ("WANT_TO_READ", "IN_PROGRESS", "FINISHED") that are
+		// processed on every run even when incremental sync would skip them as unchanged. Library
+		// items are then always fetched in full (default: none)
+		AlwaysReverify []string `yaml:"always_reverify" env:"SYNC_ALWAYS_REVERIFY"`
+		// IdentifierConflicts decides how items whose metadata carries several differing ASINs or ISBNs
+		// are looked up: "try_all" tries each in priority order, "first" only tries the first of each and
+		// "skip" doesn't sync them (default: "try_all")
+		IdentifierConflicts string `yaml:"identifier_conflicts" env:"SYNC_IDENTIFIER_CONFLICTS"`
+		// FinishedProgressHandling decides the progress written for books finished in Audiobookshelf
+		// whose listening position is short of the end, e.g. when marked finished by hand: "full" writes
+		// the whole duration and "actual" the listening position (default: "full")
+		FinishedProgressHandling string `yaml:"finished_progress_handling" env:"SYNC_FINISHED_PROGRESS_HANDLING"`
+		// CrossFormatProgress maps the ebook progress of an item that also has audio to a listening
+		// position for the audio edition when the audio itself hasn't been started, for hybrid items
+		// read as ebooks (default: false, ebook progress is ignored)
+		CrossFormatProgress bool `yaml:"cross_format_progress" env:"SYNC_CROSS_FORMAT_PROGRESS"`
+		// WantToReadOwnedOnly only adds unstarted books to Want to Read when they're owned in Hardcover,
+		// instead of the whole library (default: false). Has no effect with sync_owned, which marks
+		// every matched book as owned.
+		WantToReadOwnedOnly bool `yaml:"want_to_read_owned_only" env:"SYNC_WANT_TO_READ_OWNED_ONLY"`
+		// ProgressCacheTTL is how long the last progress written for a book suppresses writing nearly the
+		// same progress again (default: 5m)
+		ProgressCacheTTL time.Duration `yaml:"progress_cache_ttl" env:"SYNC_PROGRESS_CACHE_TTL"`
+		// PersistProgressCache keeps the last progress written per book in the cache directory, so
+		// restarts don't re-push unchanged progress (default: false)
+		PersistProgressCache bool `yaml:"persist_progress_cache" env:"SYNC_PERSIST_PROGRESS_CACHE"`
+		// ProgressCacheRetention is how long the persisted progress of a book is kept. Until then, a
+		// later run skips writing the same progress again, however long ago it was written (default: 168h)
+		ProgressCacheRetention time.Duration `yaml:"progress_cache_retention" env:"SYNC_PROGRESS_CACHE_RETENTION"`
+		// StrictIdentifierMatch only syncs a book matched by ASIN or ISBN when the matched Hardcover
+		// edition carries that same identifier, recording a mismatch otherwise (default: false)
+		StrictIdentifierMatch bool `yaml:"strict_identifier_match" env:"SYNC_STRICT_IDENTIFIER_MATCH"`
+		// IdentifierTrust marks the Audiobookshelf identifier types ("asin", "isbn") as trusted or not.
+		// An untrusted identifier is only looked up when the item has no trusted one, e.g. ISBNs
+		// scraped from unreliable sources next to authoritative ASINs (default: both trusted)
+		IdentifierTrust map[string]bool `yaml:"identifier_trust" env:"SYNC_IDENTIFIER_TRUST"`
+		// StreamMismatches appends each mismatch to mismatches.jsonl in the mismatch output directory
+		// as soon as it's recorded, instead of only saving mismatches at the end of a sync (default: false)
+		StreamMismatches bool `yaml:"stream_mismatches" env:"SYNC_STREAM_MISMATCHES"`
+		// MismatchFixSuggestions adds a reason code and a short suggestion on how to fix it to every
+		// mismatch record, e.g. adding the ASIN for a book that wasn't found (default: false)
+		MismatchFixSuggestions bool `yaml:"mismatch_fix_suggestions" env:"SYNC_MISMATCH_FIX_SUGGESTIONS"`
+		// ProgressOnly only pushes reading progress and never changes the status of a book that's
+		// already on a Hardcover shelf, for users who manage statuses manually (default: false)
+		ProgressOnly bool `yaml:"progress_only" env:"SYNC_PROGRESS_ONLY"`
+		// OwnershipOnly only marks matched books as owned and leaves reads and statuses alone, for a
+		// fast first pass over a library before enabling the full sync (default: false)
+		OwnershipOnly bool `yaml:"ownership_only" env:"SYNC_OWNERSHIP_ONLY"`
+		// RecordMatchInfo stores how each book was matched (source, score and edition ID) in the
+		// sync state, for debugging matches with --dump-state (default: false)
+		RecordMatchInfo bool `yaml:"record_match_info" env:"SYNC_RECORD_MATCH_INFO"`
+		// SuggestTitleAuthorMatches adds the Hardcover book and edition found by title/author to the
+		// mismatch record as a suggested mapping to confirm (default: false)
+		SuggestTitleAuthorMatches bool `yaml:"suggest_title_author_matches" env:"SYNC_SUGGEST_TITLE_AUTHOR_MATCHES"`
+		// SummaryWebhookURL receives a JSON summary of every run, with the books per match source and
+		// per reason they weren't synced, when set (default: empty, disabled)
+		SummaryWebhookURL string `yaml:"summary_webhook_url" env:"SYNC_SUMMARY_WEBHOOK_URL"`
+		// AllowBookLevelTracking adds a book found in Hardcover without a matching edition to the
+		// library at the book level with its status only, instead of skipping it (default: false)
+		AllowBookLevelTracking bool `yaml:"allow_book_level_tracking" env:"SYNC_ALLOW_BOOK_LEVEL_TRACKING"`
+		// UserProgressRetries is how often fetching the user's progress from Audiobookshelf is retried
+		// before falling back to the progress cached by a previous run (default: 2)
+		UserProgressRetries int `yaml:"user_progress_retries" env:"SYNC_USER_PROGRESS_RETRIES"`
+		// UserProgressCacheMaxAge is how old the cached user progress may be to still be used when
+		// fetching it fails (default: twice the sync interval, so only the previous run's progress is used)
+		UserProgressCacheMaxAge time.Duration `yaml:"user_progress_cache_max_age" env:"SYNC_USER_PROGRESS_CACHE_MAX_AGE"`
+		// SkipHardcoverFinished leaves books alone that are already Read in Hardcover unless
+		// Audiobookshelf shows activity after they were last read there, saving the per-book read
+		// lookups for settled books (default: false)
+		SkipHardcoverFinished bool `yaml:"skip_hardcover_finished" env:"SYNC_SKIP_HARDCOVER_FINISHED"`
+		// PrefetchUserBooks fetches all of the user's Hardcover books once at the start of a run
+		// instead of looking up the user book of every matched edition separately (default: false)
+		PrefetchUserBooks bool `yaml:"prefetch_user_books" env:"SYNC_PREFETCH_USER_BOOKS"`
+		// SyncCollections keeps a Hardcover list with the matched books of every Audiobookshelf
+		// collection, named like the collection. Books removed from a collection stay on its list
+		// (default: false)
+		SyncCollections bool `yaml:"sync_collections" env:"SYNC_COLLECTIONS"`
+		// AbandonedTag is an Audiobookshelf tag (e.g. "dnf") marking books the user stopped listening
+		// to, which are synced to Hardcover as Did Not Finish (default: empty, disabled)
+		AbandonedTag string `yaml:"abandoned_tag" env:"SYNC_ABANDONED_TAG"`
+		// HeartbeatFile is rewritten as a sync makes progress and removed when it ends, so a
+		// watchdog can detect a stuck sync (default: empty, disabled)
+		HeartbeatFile string `yaml:"heartbeat_file" env:"SYNC_HEARTBEAT_FILE"`
+		// HeartbeatInterval is how often at most the heartbeat file is rewritten (default: 30s)
+		HeartbeatInterval time.Duration `yaml:"heartbeat_interval" env:"SYNC_HEARTBEAT_INTERVAL"`
+		// BookOverrides pins Audiobookshelf items, by item ID, to a Hardcover book given by its slug
+		// or URL, which is used instead of the ASIN, ISBN and title/author lookup (default: none)
+		BookOverrides map[string]string `yaml:"book_overrides" env:"SYNC_BOOK_OVERRIDES"`
+		// OverProgressTolerance is how far past the duration, as a fraction of it, a listening
+		// position is taken for rounding and clamped to the duration. Books further past it are
+		// recorded as mismatches and not synced (default: 0.02)
+		OverProgressTolerance float64 `yaml:"over_progress_tolerance" env:"SYNC_OVER_PROGRESS_TOLERANCE"`
+		// FinishedThreshold is the progress, from 0 to 1, at which a book is taken as finished even
+		// if Audiobookshelf doesn't mark it finished, e.g. because the end credits weren't listened
+		// to (default: 0.99, 1 = only books Audiobookshelf marks finished or listened to completely)
+		FinishedThreshold float64 `yaml:"finished_threshold" env:"SYNC_FINISHED_THRESHOLD"`
+		// TitleMatchThreshold is the title similarity, from 0 to 1, the best title/author search
+		// result needs to be suggested for a book. Books without such a result are recorded as
+		// mismatches without a suggestion, 0 accepts any result (default: 0.75)
+		TitleMatchThreshold float64 `yaml:"title_match_threshold" env:"SYNC_TITLE_MATCH_THRESHOLD"`
+		// MergeOverlappingReads deletes Hardcover reads of a book whose dates overlap most of a more
+		// complete read of it, left behind by transient states of earlier runs (default: false)
+		MergeOverlappingReads bool `yaml:"merge_overlapping_reads" env:"SYNC_MERGE_OVERLAPPING_READS"`
+		// Blocklist lists Audiobookshelf item IDs that are never synced and never recorded as
+		// mismatches, together with the items in Paths.BlocklistFile (default: none)
+		Blocklist []string `yaml:"blocklist" env:"SYNC_BLOCKLIST"`
+		// StateRetentionRuns is how many full syncs in a row an item can be missing from
+		// Audiobookshelf, e.g. after it was deleted, before its sync state is dropped
+		// (default: 3, 0 = never)
+		StateRetentionRuns int `yaml:"state_retention_runs" env:"SYNC_STATE_RETENTION_RUNS"`
+		// FullSyncInterval is how long incremental syncs go before fetching every item again, which
+		// drops the state of deleted items per StateRetentionRuns (default: 24h, 0 = never)
+		FullSyncInterval time.Duration `yaml:"full_sync_interval" env:"SYNC_FULL_SYNC_INTERVAL"`
+		// SkipArchived skips items the user hid or archived in Audiobookshelf ("Remove from Continue
+		// Listening"), so their progress isn't synced (default: true). A pointer so that config files
+		// not setting it keep the default, use SkipArchivedItems to read it.
+		SkipArchived *bool `yaml:"skip_archived" env:"SYNC_SKIP_ARCHIVED"`
 	} `yaml:"sync"`
 
 	// Rate limiting configuration
@@ -70,6 +259,9 @@ type Config struct {
 		Burst int `yaml:"burst" env:"RATE_LIMIT_BURST"`
 		// MaxConcurrent is the maximum number of concurrent requests
 		MaxConcurrent int `yaml:"max_concurrent" env:"RATE_LIMIT_MAX_CONCURRENT"`
+		// Shared makes all users' Hardcover clients draw from a single rate limiter in multi-user
+		// mode, so the limits above apply to their combined requests (default: false)
+		Shared bool `yaml:"shared" env:"RATE_LIMIT_SHARED"`
 	} `yaml:"rate_limit"`
 
 	// Logging configuration
@@ -78,14 +270,59 @@ type Config struct {
 		Level string `yaml:"level" env:"LOG_LEVEL"`
 		// Format is the log format (json, console)
 		Format string `yaml:"format" env:"LOG_FORMAT"`
+		// File is the path of a file logs are also written to as NDJSON (empty = no log file)
+		File string `yaml:"file" env:"LOG_FILE"`
+		// FileMaxSizeMB is the size in megabytes at which the log file is rotated (default: 10, 0 = never rotate)
+		FileMaxSizeMB int `yaml:"file_max_size_mb" env:"LOG_FILE_MAX_SIZE_MB"`
+		// FileMaxBackups is the number of rotated log files kept (default: 3)
+		FileMaxBackups int `yaml:"file_max_backups" env:"LOG_FILE_MAX_BACKUPS"`
+		// DisableStdout stops logging to stdout when a log file is configured
+		DisableStdout bool `yaml:"disable_stdout" env:"LOG_DISABLE_STDOUT"`
+		// MismatchLogLimit is the number of mismatches per reason code logged in detail each run;
+		// further ones are only counted in a periodic summary, while the mismatch file still has
+		// them all (default: 0 = log every mismatch)
+		MismatchLogLimit int `yaml:"mismatch_log_limit" env:"LOG_MISMATCH_LIMIT"`
 	} `yaml:"logging"`
 
+	// Observability configuration
+	Observability struct {
+		// TracingEnabled exports OpenTelemetry traces of sync runs, with spans for every library, book
+		// and Audiobookshelf or Hardcover request (default: false)
+		TracingEnabled bool `yaml:"tracing_enabled" env:"TRACING_ENABLED"`
+		// OTLPEndpoint is the URL traces are exported to over OTLP/HTTP, e.g. "http://localhost:4318"
+		// (default: the standard OTEL_EXPORTER_OTLP_* environment variables, or localhost:4318)
+		OTLPEndpoint string `yaml:"otlp_endpoint" env:"TRACING_OTLP_ENDPOINT"`
+	} `yaml:"observability"`
+
+	// Webhooks configuration
+	Webhooks struct {
+		// Secret verifies the HMAC-SHA256 signature of the webhooks Audiobookshelf posts to
+		// /webhooks/audiobookshelf, which is disabled while it's empty (default: "")
+		Secret string `yaml:"secret" env:"WEBHOOKS_SECRET"`
+	} `yaml:"webhooks"`
+
+	// External metadata configuration
+	Metadata struct {
+		// ISBNToASINURL is a URL returning {"asin": "..."} for an ISBN, looked up for books that
+		// have an ISBN but no ASIN when their ISBN isn't found in Hardcover. "{isbn}" in the URL is
+		// replaced with the ISBN, otherwise it's added as the isbn query parameter (default: none)
+		ISBNToASINURL string `yaml:"isbn_to_asin_url" env:"METADATA_ISBN_TO_ASIN_URL"`
+	} `yaml:"metadata"`
+
 	// Audiobookshelf configuration
 	Audiobookshelf struct {
 		// URL is the base URL of the Audiobookshelf server
 		URL string `yaml:"url" env:"AUDIOBOOKSHELF_URL"`
 		// Token is the API token for Audiobookshelf
 		Token string `yaml:"token" env:"AUDIOBOOKSHELF_TOKEN"`
+		// AuthScheme is how the token is sent: "bearer" (Authorization header, default), "query"
+		// (token query parameter) or "x-api-key" (x-api-key header), for older or newer servers
+		AuthScheme string `yaml:"auth_scheme" env:"AUDIOBOOKSHELF_AUTH_SCHEME"`
+		// FullItems fetches full library item payloads instead of minified ones (default: false)
+		FullItems bool `yaml:"full_items" env:"AUDIOBOOKSHELF_FULL_ITEMS"`
+		// Users maps Audiobookshelf users to their own Hardcover accounts. When set, the token must be
+		// an admin token and every mapped user's progress is synced to their Hardcover account
+		Users []AudiobookshelfUser `yaml:"users" env:"AUDIOBOOKSHELF_USERS"`
 	} `yaml:"audiobookshelf"`
 
 	// Hardcover configuration
@@ -94,6 +331,18 @@ type Config struct {
 		Token string `yaml:"token" env:"HARDCOVER_TOKEN"`
 		// BaseURL is the base URL for the Hardcover GraphQL API
 		BaseURL string `yaml:"base_url" env:"HARDCOVER_BASE_URL"`
+		// AllowedOperations restricts the GraphQL mutations sent to Hardcover to the listed operation
+		// names, e.g. "InsertUserBook" (default: none, all mutations are allowed)
+		AllowedOperations []string `yaml:"allowed_operations" env:"HARDCOVER_ALLOWED_OPERATIONS"`
+		// DisabledOperations lists GraphQL mutation operation names that are never sent to Hardcover,
+		// e.g. "EditionOwned" to never mark editions as owned (default: none)
+		DisabledOperations []string `yaml:"disabled_operations" env:"HARDCOVER_DISABLED_OPERATIONS"`
+		// NegativeCacheTTL is how long an ASIN that wasn't found on Hardcover is cached before it's looked
+		// up again, so newly added books are found sooner. Capped at the 24h of found ASINs (default: 6h)
+		NegativeCacheTTL time.Duration `yaml:"negative_cache_ttl" env:"HARDCOVER_NEGATIVE_CACHE_TTL"`
+		// TitleSearchCacheTTL is how long the outcome of a title/author search is cached across runs,
+		// whether it matched a book or found nothing (default: 168h, 0 = don't cache)
+		TitleSearchCacheTTL time.Duration `yaml:"title_search_cache_ttl" env:"HARDCOVER_TITLE_SEARCH_CACHE_TTL"`
 	} `yaml:"hardcover"`
 
 	// Application settings
@@ -142,6 +391,10 @@ type Config struct {
 			// ConnMaxLifetime is the connection lifetime in minutes
 			ConnMaxLifetime int `yaml:"conn_max_lifetime" env:"DATABASE_CONN_MAX_LIFETIME"`
 		} `yaml:"connection_pool"`
+		// MigrateLegacyState imports the sync state file into the sync state of the default profile
+		// when a single-user config is migrated to the database (default: true). A pointer so that
+		// config files not setting it keep the default, use MigrateLegacyStateEnabled to read it.
+		MigrateLegacyState *bool `yaml:"migrate_legacy_state" env:"DATABASE_MIGRATE_LEGACY_STATE"`
 	} `yaml:"database"`
 
 	// Authentication configuration
@@ -199,7 +452,191 @@ type Config struct {
 		CacheDir string `yaml:"cache_dir" env:"CACHE_DIR"`
 		// MismatchOutputDir is the directory where mismatch JSON files will be saved
 		MismatchOutputDir string `yaml:"mismatch_output_dir" env:"MISMATCH_OUTPUT_DIR"`
+		// MismatchFormats lists the formats mismatches are saved in: "json" saves a file per
+		// mismatch for the edition import tool and "csv" saves them all to mismatches.csv
+		// (default: ["json"])
+		MismatchFormats []string `yaml:"mismatch_formats" env:"MISMATCH_FORMATS"`
+		// OverridesFile is a YAML or JSON file mapping Audiobookshelf item IDs to the Hardcover
+		// edition IDs they're synced to, without looking them up (default: empty, none)
+		OverridesFile string `yaml:"overrides_file" env:"OVERRIDES_FILE"`
+		// DryRunReport is the JSON file a dry run writes the changes it would have made to
+		// (default: dry_run_report.json in MismatchOutputDir)
+		DryRunReport string `yaml:"dry_run_report" env:"DRY_RUN_REPORT"`
+		// BlocklistFile lists further Audiobookshelf item IDs that are never synced, one per line.
+		// Unlike Sync.Blocklist it can be appended to while the service runs (default: empty, none)
+		BlocklistFile string `yaml:"blocklist_file" env:"BLOCKLIST_FILE"`
+		// UnmatchedExport is a JSON file every run writes the books not found in Hardcover to, with
+		// all their metadata in the format of the edition import tool, for adding them to Hardcover
+		// manually (default: empty, disabled)
+		UnmatchedExport string `yaml:"unmatched_export" env:"UNMATCHED_EXPORT"`
 	} `yaml:"paths"`
+
+	// Edition creation configuration
+	Edition struct {
+		// Defaults for fields of created editions that the Audiobookshelf metadata doesn't provide
+		Defaults EditionDefaults `yaml:"defaults"`
+		// ResolveConcurrency is how many author, narrator and publisher names are looked up at once
+		// when creating an edition (default: 4)
+		ResolveConcurrency int `yaml:"resolve_concurrency" env:"EDITION_RESOLVE_CONCURRENCY"`
+		// PublisherSource is the metadata field the publisher name of a mismatch is taken from:
+		// "publisher" (the Audiobookshelf publisher, default), "audnex" (the Audnex publisher,
+		// falling back to the Audiobookshelf one) or "none" (always use the default publisher)
+		PublisherSource string `yaml:"publisher_source" env:"EDITION_PUBLISHER_SOURCE"`
+		// CreateMissingPublishers creates a publisher in Hardcover when its name isn't found while
+		// creating an edition, instead of falling back to the default publisher
+		CreateMissingPublishers bool `yaml:"create_missing_publishers" env:"EDITION_CREATE_MISSING_PUBLISHERS"`
+		// Formats adds edition formats that edition_format in the edition input can refer to by
+		// key, in addition to the built-in ones such as "audiobook", "audible" and "ebook"
+		Formats map[string]EditionFormat `yaml:"formats"`
+	} `yaml:"edition"`
+}
+
+// LibraryOverride holds the settings of a library in Sync.Libraries.Overrides
+type LibraryOverride struct {
+	// DryRun only plans the changes for the library's books, as in dry-run mode, while other
+	// libraries sync normally
+	DryRun bool `yaml:"dry_run"`
+	// ReadingFormat is the reading format of the library's books, used for Hardcover lookups and
+	// reads instead of the one derived from the item's media type and Sync.ReadingFormat:
+	// "audiobook", "ebook", "physical" or "both" (default: empty)
+	ReadingFormat string `yaml:"reading_format"`
+}
+
+// EditionFormat is an edition format added through Edition.Formats
+type EditionFormat struct {
+	// Name is the edition_format string stored in Hardcover, e.g. "Audible Audio"
+	Name string `yaml:"name"`
+	// ReadingFormatID is the Hardcover reading format: 1 (physical), 2 (audiobook), 3 (both) or
+	// 4 (ebook) (default: 2)
+	ReadingFormatID int `yaml:"reading_format_id"`
+}
+
+// EditionDefaults holds the Hardcover IDs used when creating an edition whose Audiobookshelf
+// metadata lacks a language, country or publisher. Zero keeps the built-in fallback.
+type EditionDefaults struct {
+	// LanguageID is the Hardcover language ID (fallback: 1, English)
+	LanguageID int `yaml:"language_id" env:"EDITION_DEFAULT_LANGUAGE_ID"`
+	// CountryID is the Hardcover country ID (fallback: 1, United States)
+	CountryID int `yaml:"country_id" env:"EDITION_DEFAULT_COUNTRY_ID"`
+	// PublisherID is the Hardcover publisher ID
+	PublisherID int `yaml:"publisher_id" env:"EDITION_DEFAULT_PUBLISHER_ID"`
+}
+
+// AudiobookshelfUser maps an Audiobookshelf user to the Hardcover account their progress is synced to
+type AudiobookshelfUser struct {
+	// User is the Audiobookshelf username or user ID
+	User string `yaml:"user"`
+	// HardcoverToken is the API token of the user's Hardcover account
+	HardcoverToken string `yaml:"hardcover_token"`
+}
+
+// Progress sources for Sync.ProgressSource
+const (
+	// ProgressSourceMedia prefers the item's media progress, falling back to listening sessions
+	ProgressSourceMedia = "media"
+	// ProgressSourceSessions prefers the most recent listening session, falling back to media progress
+	ProgressSourceSessions = "sessions"
+	// ProgressSourceMostRecent uses whichever of the two was updated most recently
+	ProgressSourceMostRecent = "most_recent"
+)
+
+// Finished sources for Sync.FinishedSource
+const (
+	// FinishedSourceMediaProgress uses the isFinished flag of the /api/me progress entry
+	FinishedSourceMediaProgress = "media_progress"
+	// FinishedSourceItem uses the isFinished flag of the library item's own progress
+	FinishedSourceItem = "item"
+	// FinishedSourceEither treats a book as finished when either of the two is finished
+	FinishedSourceEither = "either"
+	// FinishedSourceBoth only treats a book as finished when both are finished
+	FinishedSourceBoth = "both"
+)
+
+// Sync directions for Sync.Direction
+const (
+	// SyncDirectionABSToHC syncs Audiobookshelf progress to Hardcover
+	SyncDirectionABSToHC = "abs_to_hc"
+	// SyncDirectionHCToABS syncs Hardcover progress to Audiobookshelf
+	SyncDirectionHCToABS = "hc_to_abs"
+	// SyncDirectionBoth syncs the progress either way, as decided by Sync.ConflictResolution
+	SyncDirectionBoth = "both"
+)
+
+// Conflict resolutions for Sync.ConflictResolution
+const (
+	// ConflictResolutionNewest syncs the progress that was updated most recently
+	ConflictResolutionNewest = "newest"
+	// ConflictResolutionABS always syncs the Audiobookshelf progress
+	ConflictResolutionABS = "abs"
+	// ConflictResolutionHardcover always syncs the Hardcover progress
+	ConflictResolutionHardcover = "hardcover"
+)
+
+// Formats for Paths.MismatchFormats
+const (
+	// MismatchFormatJSON saves every mismatch to a JSON file for the edition import tool
+	MismatchFormatJSON = "json"
+	// MismatchFormatCSV saves all mismatches to a CSV file for reviewing them in a spreadsheet
+	MismatchFormatCSV = "csv"
+)
+
+// Publisher sources for Edition.PublisherSource
+const (
+	// PublisherSourcePublisher uses the publisher field of the Audiobookshelf metadata
+	PublisherSourcePublisher = "publisher"
+	// PublisherSourceAudnex uses the publisher returned by Audnex, falling back to Audiobookshelf
+	PublisherSourceAudnex = "audnex"
+	// PublisherSourceNone ignores the publisher name and uses the default publisher
+	PublisherSourceNone = "none"
+)
+
+// Policies for Sync.MissingProgressPolicy
+const (
+	// MissingProgressWantToRead processes items without progress data as unstarted books, adding them to
+	// Want to Read when sync_want_to_read and process_unread_books allow it
+	MissingProgressWantToRead = "want_to_read"
+	// MissingProgressSkip skips items without progress data
+	MissingProgressSkip = "skip"
+)
+
+// Behaviors for Sync.IdentifierConflicts
+const (
+	// IdentifierConflictsTryAll tries each of the conflicting identifiers in priority order
+	IdentifierConflictsTryAll = "try_all"
+	// IdentifierConflictsFirst only tries the first ASIN and the first ISBN
+	IdentifierConflictsFirst = "first"
+	// IdentifierConflictsSkip doesn't sync items with conflicting identifiers
+	IdentifierConflictsSkip = "skip"
+)
+
+// Identifier types for Sync.IdentifierTrust
+const (
+	IdentifierASIN = "asin"
+	IdentifierISBN = "isbn"
+)
+
+// Handling of the progress of finished books for Sync.FinishedProgressHandling
+const (
+	// FinishedProgressFull writes the book's whole duration as the progress of finished books
+	FinishedProgressFull = "full"
+	// FinishedProgressActual writes the Audiobookshelf listening position as the progress of finished books
+	FinishedProgressActual = "actual"
+)
+
+// Reading formats for Sync.ReadingFormat
+const (
+	ReadingFormatAuto      = "auto"
+	ReadingFormatAudiobook = "audiobook"
+	ReadingFormatEbook     = "ebook"
+	ReadingFormatPhysical  = "physical"
+	ReadingFormatBoth      = "both"
+)
+
+// DefaultExcludeTitlePatterns skip "summary" books that often show up in title/author searches
+var DefaultExcludeTitlePatterns = []string{
+	`(?i)summary of`,
+	`(?i)summary:`,
+	`(?i)^summary\s`,
 }
 
 // DefaultConfig returns the default configuration
@@ -211,6 +648,9 @@ func DefaultConfig() *Config {
 	cfg.Server.ShutdownTimeout = 30 * time.Second
 	cfg.Server.EnableWebUI = false // Web UI is disabled by default for backward compatibility
 
+	// Default Audiobookshelf configuration
+	cfg.Audiobookshelf.AuthScheme = "bearer"
+
 	// Default sync configuration
 	cfg.Sync.Incremental = true
 	cfg.Sync.StateFile = "./data/sync_state.json"
@@ -225,6 +665,63 @@ func DefaultConfig() *Config {
 	cfg.Sync.TestBookFilter = ""
 	cfg.Sync.TestBookLimit = 0
 	cfg.Sync.IncludeEbooks = false
+	cfg.Sync.VerifyNarrator = false
+	cfg.Sync.MatchNarrator = false
+	cfg.Sync.ExcludeTitlePatterns = append([]string(nil), DefaultExcludeTitlePatterns...)
+	cfg.Sync.MaxProgressJumpSeconds = 0
+	cfg.Sync.ProgressSource = ProgressSourceMedia
+	cfg.Sync.FinishedSource = FinishedSourceMediaProgress
+	cfg.Sync.Direction = SyncDirectionABSToHC
+	cfg.Sync.ConflictResolution = ConflictResolutionNewest
+	cfg.Sync.SourceTag = ""
+	cfg.Sync.StateFlushInterval = 30 * time.Second
+	cfg.Sync.ReadingFormat = ReadingFormatAuto
+	cfg.Sync.AuthFailureThreshold = 3
+	cfg.Sync.AuthFailureBackoff = time.Hour
+	cfg.Sync.ReloadTokenOnAuthFailure = false
+	cfg.Sync.NotFoundGracePeriod = 0
+	cfg.Sync.MissingProgressPolicy = MissingProgressWantToRead
+	cfg.Sync.PerBookTimeout = 0
+	cfg.Sync.MaxRunDuration = 0
+	cfg.Sync.PrefetchLibraries = 0
+	cfg.Sync.IdentifierConflicts = IdentifierConflictsTryAll
+	cfg.Sync.FinishedProgressHandling = FinishedProgressFull
+	cfg.Sync.CrossFormatProgress = false
+	cfg.Sync.WantToReadOwnedOnly = false
+	cfg.Sync.ProgressCacheTTL = 5 * time.Minute
+	cfg.Sync.PersistProgressCache = false
+	cfg.Sync.ProgressCacheRetention = 7 * 24 * time.Hour
+	cfg.Sync.StrictIdentifierMatch = false
+	cfg.Sync.StreamMismatches = false
+	cfg.Sync.MismatchFixSuggestions = false
+	cfg.Sync.ProgressOnly = false
+	cfg.Sync.OwnershipOnly = false
+	cfg.Sync.RecordMatchInfo = false
+	cfg.Sync.SuggestTitleAuthorMatches = false
+	cfg.Sync.AllowBookLevelTracking = false
+	cfg.Sync.UserProgressRetries = 2
+	cfg.Sync.UserProgressCacheMaxAge = 0
+	cfg.Sync.SkipHardcoverFinished = false
+	cfg.Sync.SyncCollections = false
+	cfg.Sync.PrefetchUserBooks = false
+	cfg.Sync.AbandonedTag = ""
+	cfg.Sync.OverProgressTolerance = 0.02
+	cfg.Sync.FinishedThreshold = 0.99
+	cfg.Sync.TitleMatchThreshold = 0.75
+	cfg.Sync.MergeOverlappingReads = false
+	cfg.Sync.StateRetentionRuns = 3
+	cfg.Sync.FullSyncInterval = 24 * time.Hour
+	cfg.Sync.SkipArchived = boolPtr(true)
+	cfg.Sync.HeartbeatInterval = 30 * time.Second
+
+	// Edition creation defaults
+	cfg.Edition.ResolveConcurrency = 4
+	cfg.Edition.PublisherSource = PublisherSourcePublisher
+	cfg.Edition.CreateMissingPublishers = false
+
+	// Log file rotation defaults (the log file itself is disabled unless a path is set)
+	cfg.Logging.FileMaxSizeMB = 10
+	cfg.Logging.FileMaxBackups = 3
 
 	// Database defaults
 	cfg.Database.Type = "sqlite"
@@ -238,6 +735,7 @@ func DefaultConfig() *Config {
 	cfg.Database.ConnectionPool.MaxOpenConns = 10
 	cfg.Database.ConnectionPool.MaxIdleConns = 5
 	cfg.Database.ConnectionPool.ConnMaxLifetime = 30 // minutes
+	cfg.Database.MigrateLegacyState = boolPtr(true)
 
 	// Authentication defaults
 	cfg.Authentication.Enabled = false
@@ -262,14 +760,20 @@ func DefaultConfig() *Config {
 	cfg.Paths.DataDir = "./data"
 	cfg.Paths.CacheDir = "./cache"
 	cfg.Paths.MismatchOutputDir = "./mismatches"
+	cfg.Paths.MismatchFormats = []string{MismatchFormatJSON}
 
 	// Default Hardcover settings
 	// Official GraphQL endpoint, can be overridden via HARDCOVER_BASE_URL or config
 	cfg.Hardcover.BaseURL = "https://api.hardcover.app/v1/graphql"
+	cfg.Hardcover.NegativeCacheTTL = 6 * time.Hour
+	cfg.Hardcover.TitleSearchCacheTTL = 7 * 24 * time.Hour
 
     return cfg
 }
 
+// Load builds the configuration from defaults, the given config file(s) and environment variables.
+// configPath may contain multiple comma-separated files, which are merged in order so that values
+// in later files override earlier ones while values only set in earlier files are kept.
 func Load(configPath string) (*Config, error) {
 	// Start with default configuration
 	cfg := DefaultConfig()
@@ -279,27 +783,38 @@ func Load(configPath string) (*Config, error) {
 	
 	// Note: Debug logging removed to prevent early logger initialization
 
-	// Load from file if path is provided
-	if configPath != "" {
-		// Check if file exists
-		if _, err := os.Stat(configPath); os.IsNotExist(err) {
-			// Config file does not exist, using defaults
-		} else {
+	// Load from file(s) if a path is provided
+	if paths := SplitConfigPaths(configPath); len(paths) > 0 {
+		// Decode every file into the same temporary config so later files only
+		// override the keys they actually set
+		fileCfg := &Config{}
+		loaded := false
+
+		for _, path := range paths {
+			// Check if file exists. A single missing file leaves the defaults and environment, but
+			// every file of a list was asked for, so a typo doesn't quietly drop an overlay.
+			if _, err := os.Stat(path); os.IsNotExist(err) {
+				if len(paths) > 1 {
+					return nil, fmt.Errorf("config file %s does not exist", path)
+				}
+				continue
+			}
+
 			// Read the config file
-			data, err := os.ReadFile(configPath)
+			data, err := os.ReadFile(path)
 			if err != nil {
-				return nil, fmt.Errorf("failed to read config file: %w", err)
+				return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
 			}
 
-			// Create a temporary config to load the file into
-			fileCfg := &Config{}
-
 			// Unmarshal the config file
 			if err := yaml.Unmarshal(data, fileCfg); err != nil {
-				return nil, fmt.Errorf("failed to parse config file: %w", err)
+				return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
 			}
+			loaded = true
+		}
 
-			// Merge the config from file into our config
+		// Merge the config from file(s) into our config
+		if loaded {
 			mergeConfigs(cfg, fileCfg)
 		}
 	}
@@ -325,8 +840,8 @@ func Load(configPath string) (*Config, error) {
 		cfg.Sync.ProcessUnreadBooks, cfg.Sync.SyncOwned, cfg.Sync.DryRun,
 		cfg.Sync.SingleUserMode, cfg.Sync.SingleUserUsername, cfg.Sync.TestBookFilter,
 		cfg.Sync.TestBookLimit, cfg.Sync.IncludeEbooks)
-	fmt.Printf("Rate Limiting:\n  rate: %s\n  burst: %d\n  max_concurrent: %d\n",
-		cfg.RateLimit.Rate, cfg.RateLimit.Burst, cfg.RateLimit.MaxConcurrent)
+	fmt.Printf("Rate Limiting:\n  rate: %s\n  burst: %d\n  max_concurrent: %d\n  shared: %v\n",
+		cfg.RateLimit.Rate, cfg.RateLimit.Burst, cfg.RateLimit.MaxConcurrent, cfg.RateLimit.Shared)
 	fmt.Printf("Logging:\n  level: %s\n  format: %s\n", 
 		cfg.Logging.Level, cfg.Logging.Format)
 	fmt.Printf("Database:\n  type: %s\n  path: %s\n", 
@@ -351,6 +866,23 @@ func Load(configPath string) (*Config, error) {
 	return cfg, nil
 }
 
+// SkipArchivedItems reports whether items hidden or archived in Audiobookshelf are skipped, which
+// they are unless Sync.SkipArchived is set to false
+func (c *Config) SkipArchivedItems() bool {
+	return c.Sync.SkipArchived == nil || *c.Sync.SkipArchived
+}
+
+// MigrateLegacyStateEnabled reports whether the sync state file is imported on migration to the
+// database, which it is unless Database.MigrateLegacyState is set to false
+func (c *Config) MigrateLegacyStateEnabled() bool {
+	return c.Database.MigrateLegacyState == nil || *c.Database.MigrateLegacyState
+}
+
+// boolPtr returns a pointer to b
+func boolPtr(b bool) *bool {
+	return &b
+}
+
 // Validate checks that all required configuration is present and valid
 func (c *Config) Validate() error {
 	var missing []string
@@ -420,6 +952,328 @@ func (c *Config) Validate() error {
 		fmt.Printf("Warning: Invalid minimum progress, using default: %.2f\n", c.Sync.MinimumProgress)
 	}
 
+	// Validate over progress tolerance
+	if c.Sync.OverProgressTolerance < 0 {
+		c.Sync.OverProgressTolerance = 0
+		fmt.Printf("Warning: Invalid over progress tolerance, clamping all progress past the duration is disabled\n")
+	}
+
+	// Validate finished threshold
+	if c.Sync.FinishedThreshold <= 0 || c.Sync.FinishedThreshold > 1 {
+		fmt.Printf("Warning: Invalid finished threshold %.2f, using default of 0.99\n", c.Sync.FinishedThreshold)
+		c.Sync.FinishedThreshold = 0.99
+	}
+
+	// Validate title match threshold
+	if c.Sync.TitleMatchThreshold < 0 || c.Sync.TitleMatchThreshold > 1 {
+		fmt.Printf("Warning: Invalid title match threshold %.2f, using default of 0.75\n", c.Sync.TitleMatchThreshold)
+		c.Sync.TitleMatchThreshold = 0.75
+	}
+
+	// Validate progress jump cap
+	if c.Sync.MaxProgressJumpSeconds < 0 {
+		c.Sync.MaxProgressJumpSeconds = 0
+		fmt.Printf("Warning: Invalid max progress jump, disabling the cap\n")
+	}
+
+	// Validate state flush interval
+	if c.Sync.StateFlushInterval < 0 {
+		c.Sync.StateFlushInterval = 0
+		fmt.Printf("Warning: Invalid state flush interval, only saving state at the end of a sync\n")
+	}
+
+	// Validate authentication failure handling
+	if c.Sync.AuthFailureThreshold < 0 {
+		c.Sync.AuthFailureThreshold = 0
+		fmt.Printf("Warning: Invalid auth failure threshold, never aborting on authentication failures\n")
+	}
+	if c.Sync.AuthFailureBackoff < 0 {
+		c.Sync.AuthFailureBackoff = time.Hour
+		fmt.Printf("Warning: Invalid auth failure backoff, using default of 1h\n")
+	}
+
+	// Validate log file rotation
+	if c.Logging.FileMaxSizeMB < 0 {
+		c.Logging.FileMaxSizeMB = 0
+		fmt.Printf("Warning: Invalid log file max size, never rotating the log file\n")
+	}
+	if c.Logging.FileMaxBackups < 0 {
+		c.Logging.FileMaxBackups = 0
+		fmt.Printf("Warning: Invalid log file max backups, not keeping rotated log files\n")
+	}
+
+	// Validate full sync interval
+	if c.Sync.FullSyncInterval < 0 {
+		c.Sync.FullSyncInterval = 0
+		fmt.Printf("Warning: Invalid full sync interval, not scheduling full syncs\n")
+	}
+
+	// Validate not found grace period
+	if c.Sync.NotFoundGracePeriod < 0 {
+		c.Sync.NotFoundGracePeriod = 0
+		fmt.Printf("Warning: Invalid not found grace period, recording books not found immediately\n")
+	}
+
+	// Validate per-book timeout
+	if c.Sync.PerBookTimeout < 0 {
+		c.Sync.PerBookTimeout = 0
+		fmt.Printf("Warning: Invalid per-book timeout, processing books without a time limit\n")
+	}
+
+	// Validate maximum run duration
+	if c.Sync.MaxRunDuration < 0 {
+		c.Sync.MaxRunDuration = 0
+		fmt.Printf("Warning: Invalid maximum run duration, running syncs without a time limit\n")
+	}
+
+	// Validate and normalize the statuses that are always re-verified
+	for i, status := range c.Sync.AlwaysReverify {
+		normalized := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(status), "-", "_"))
+		switch normalized {
+		case "WANT_TO_READ", "IN_PROGRESS", "FINISHED":
+			c.Sync.AlwaysReverify[i] = normalized
+		default:
+			return &ConfigError{
+				Field: "sync.always_reverify",
+				Msg:   fmt.Sprintf("must only contain WANT_TO_READ, IN_PROGRESS or FINISHED, got %q", status),
+			}
+		}
+	}
+
+	// Validate and normalize the mismatch formats
+	if len(c.Paths.MismatchFormats) == 0 {
+		c.Paths.MismatchFormats = []string{MismatchFormatJSON}
+	}
+	for i, format := range c.Paths.MismatchFormats {
+		normalized := strings.ToLower(strings.TrimSpace(format))
+		switch normalized {
+		case MismatchFormatJSON, MismatchFormatCSV:
+			c.Paths.MismatchFormats[i] = normalized
+		default:
+			return &ConfigError{
+				Field: "paths.mismatch_formats",
+				Msg:   fmt.Sprintf("must only contain %q or %q, got %q", MismatchFormatJSON, MismatchFormatCSV, format),
+			}
+		}
+	}
+
+	// Validate the behavior for conflicting identifiers
+	switch c.Sync.IdentifierConflicts {
+	case IdentifierConflictsTryAll, IdentifierConflictsFirst, IdentifierConflictsSkip:
+	default:
+		return &ConfigError{
+			Field: "sync.identifier_conflicts",
+			Msg: fmt.Sprintf("must be %q, %q or %q, got %q",
+				IdentifierConflictsTryAll, IdentifierConflictsFirst, IdentifierConflictsSkip, c.Sync.IdentifierConflicts),
+		}
+	}
+
+	// Validate the identifier types given a trust level
+	for identifier := range c.Sync.IdentifierTrust {
+		if identifier != IdentifierASIN && identifier != IdentifierISBN {
+			return &ConfigError{
+				Field: "sync.identifier_trust",
+				Msg:   fmt.Sprintf("must only contain %q or %q, got %q", IdentifierASIN, IdentifierISBN, identifier),
+			}
+		}
+	}
+
+	// Validate the ISBN to ASIN lookup URL
+	if c.Metadata.ISBNToASINURL != "" {
+		u, err := url.Parse(c.Metadata.ISBNToASINURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return &ConfigError{
+				Field: "metadata.isbn_to_asin_url",
+				Msg:   fmt.Sprintf("must be an http or https URL, got %q", c.Metadata.ISBNToASINURL),
+			}
+		}
+	}
+
+	// Validate the progress handling of finished books
+	switch c.Sync.FinishedProgressHandling {
+	case FinishedProgressFull, FinishedProgressActual:
+	default:
+		return &ConfigError{
+			Field: "sync.finished_progress_handling",
+			Msg:   fmt.Sprintf("must be %q or %q, got %q", FinishedProgressFull, FinishedProgressActual, c.Sync.FinishedProgressHandling),
+		}
+	}
+
+	// Validate library prefetching
+	if c.Sync.PrefetchLibraries < 0 {
+		c.Sync.PrefetchLibraries = 0
+		fmt.Printf("Warning: Invalid number of library pages to prefetch, fetching each library when it's processed\n")
+	}
+
+	// Validate progress cache TTL
+	if c.Sync.ProgressCacheTTL <= 0 {
+		c.Sync.ProgressCacheTTL = 5 * time.Minute
+		fmt.Printf("Warning: Invalid progress cache TTL, using default: 5m\n")
+	}
+
+	// Validate progress source
+	switch c.Sync.ProgressSource {
+	case ProgressSourceMedia, ProgressSourceSessions, ProgressSourceMostRecent:
+	default:
+		return &ConfigError{
+			Field: "sync.progress_source",
+			Msg:   fmt.Sprintf("must be one of %q, %q or %q, got %q", ProgressSourceMedia, ProgressSourceSessions, ProgressSourceMostRecent, c.Sync.ProgressSource),
+		}
+	}
+
+	// Validate finished source
+	switch c.Sync.FinishedSource {
+	case FinishedSourceMediaProgress, FinishedSourceItem, FinishedSourceEither, FinishedSourceBoth:
+	default:
+		return &ConfigError{
+			Field: "sync.finished_source",
+			Msg: fmt.Sprintf("must be one of %q, %q, %q or %q, got %q", FinishedSourceMediaProgress, FinishedSourceItem,
+				FinishedSourceEither, FinishedSourceBoth, c.Sync.FinishedSource),
+		}
+	}
+
+	// Validate sync direction
+	switch c.Sync.Direction {
+	case SyncDirectionABSToHC, SyncDirectionHCToABS, SyncDirectionBoth:
+	default:
+		return &ConfigError{
+			Field: "sync.direction",
+			Msg:   fmt.Sprintf("must be one of %q, %q or %q, got %q", SyncDirectionABSToHC, SyncDirectionHCToABS, SyncDirectionBoth, c.Sync.Direction),
+		}
+	}
+
+	// Validate conflict resolution
+	switch c.Sync.ConflictResolution {
+	case ConflictResolutionNewest, ConflictResolutionABS, ConflictResolutionHardcover:
+	default:
+		return &ConfigError{
+			Field: "sync.conflict_resolution",
+			Msg: fmt.Sprintf("must be one of %q, %q or %q, got %q", ConflictResolutionNewest, ConflictResolutionABS,
+				ConflictResolutionHardcover, c.Sync.ConflictResolution),
+		}
+	}
+
+	// Validate missing progress policy
+	switch c.Sync.MissingProgressPolicy {
+	case MissingProgressWantToRead, MissingProgressSkip:
+	default:
+		return &ConfigError{
+			Field: "sync.missing_progress_policy",
+			Msg:   fmt.Sprintf("must be %q or %q, got %q", MissingProgressWantToRead, MissingProgressSkip, c.Sync.MissingProgressPolicy),
+		}
+	}
+
+	// Validate reading format
+	switch c.Sync.ReadingFormat {
+	case ReadingFormatAuto, ReadingFormatAudiobook, ReadingFormatEbook, ReadingFormatPhysical, ReadingFormatBoth:
+	default:
+		return &ConfigError{
+			Field: "sync.reading_format",
+			Msg:   fmt.Sprintf("must be one of auto, audiobook, ebook, physical or both, got %q", c.Sync.ReadingFormat),
+		}
+	}
+
+	// Validate the reading formats of library overrides
+	for name, override := range c.Sync.Libraries.Overrides {
+		switch override.ReadingFormat {
+		case "", ReadingFormatAudiobook, ReadingFormatEbook, ReadingFormatPhysical, ReadingFormatBoth:
+		default:
+			return &ConfigError{
+				Field: "sync.libraries.overrides." + name + ".reading_format",
+				Msg:   fmt.Sprintf("must be one of audiobook, ebook, physical or both, got %q", override.ReadingFormat),
+			}
+		}
+	}
+
+	// Validate edition defaults
+	for _, d := range []struct {
+		field string
+		id    int
+	}{
+		{"edition.defaults.language_id", c.Edition.Defaults.LanguageID},
+		{"edition.defaults.country_id", c.Edition.Defaults.CountryID},
+		{"edition.defaults.publisher_id", c.Edition.Defaults.PublisherID},
+	} {
+		if d.id < 0 {
+			return &ConfigError{
+				Field: d.field,
+				Msg:   fmt.Sprintf("must be a Hardcover ID or 0 for the built-in fallback, got %d", d.id),
+			}
+		}
+	}
+
+	if c.Edition.ResolveConcurrency < 1 {
+		c.Edition.ResolveConcurrency = 4
+		fmt.Printf("Warning: Invalid edition resolve concurrency, using default: 4\n")
+	}
+
+	// Validate publisher source
+	switch c.Edition.PublisherSource {
+	case PublisherSourcePublisher, PublisherSourceAudnex, PublisherSourceNone:
+	default:
+		return &ConfigError{
+			Field: "edition.publisher_source",
+			Msg: fmt.Sprintf("must be one of %q, %q or %q, got %q", PublisherSourcePublisher, PublisherSourceAudnex,
+				PublisherSourceNone, c.Edition.PublisherSource),
+		}
+	}
+
+	// Validate edition formats
+	for key, format := range c.Edition.Formats {
+		if strings.TrimSpace(format.Name) == "" {
+			return &ConfigError{
+				Field: "edition.formats." + key + ".name",
+				Msg:   "must be the edition format string stored in Hardcover, e.g. \"Audible Audio\"",
+			}
+		}
+		if format.ReadingFormatID < 0 || format.ReadingFormatID > 4 {
+			return &ConfigError{
+				Field: "edition.formats." + key + ".reading_format_id",
+				Msg:   fmt.Sprintf("must be 1 (physical), 2 (audiobook), 3 (both) or 4 (ebook), got %d", format.ReadingFormatID),
+			}
+		}
+	}
+
+	// Validate Audiobookshelf auth scheme (see audiobookshelf.AuthScheme*)
+	switch c.Audiobookshelf.AuthScheme {
+	case "bearer", "query", "x-api-key":
+	default:
+		return &ConfigError{
+			Field: "audiobookshelf.auth_scheme",
+			Msg:   fmt.Sprintf("must be one of bearer, query or x-api-key, got %q", c.Audiobookshelf.AuthScheme),
+		}
+	}
+
+	// Validate Audiobookshelf user mappings
+	seenUsers := make(map[string]bool, len(c.Audiobookshelf.Users))
+	for i, user := range c.Audiobookshelf.Users {
+		field := fmt.Sprintf("audiobookshelf.users[%d]", i)
+		if user.User == "" || user.HardcoverToken == "" {
+			return &ConfigError{
+				Field: field,
+				Msg:   "must set both user and hardcover_token",
+			}
+		}
+		key := strings.ToLower(user.User)
+		if seenUsers[key] {
+			return &ConfigError{
+				Field: field,
+				Msg:   fmt.Sprintf("maps user %q more than once", user.User),
+			}
+		}
+		seenUsers[key] = true
+	}
+
+	// Validate title exclusion patterns
+	for _, pattern := range c.Sync.ExcludeTitlePatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return &ConfigError{
+				Field: "sync.exclude_title_patterns",
+				Msg:   fmt.Sprintf("contains an invalid regular expression %q: %v", pattern, err),
+			}
+		}
+	}
+
 	// Note: Logger initialization deferred to prevent early initialization with JSON format
 	// Check for deprecated app-level settings, migrate them to sync section, and log warnings
 	var deprecatedFields []string
@@ -574,6 +1428,25 @@ func loadFromEnv(cfg *Config) {
 	if token := os.Getenv("AUDIOBOOKSHELF_TOKEN"); token != "" {
 		cfg.Audiobookshelf.Token = token
 	}
+	if authScheme := os.Getenv("AUDIOBOOKSHELF_AUTH_SCHEME"); authScheme != "" {
+		cfg.Audiobookshelf.AuthScheme = strings.ToLower(authScheme)
+	}
+	if fullItems := os.Getenv("AUDIOBOOKSHELF_FULL_ITEMS"); fullItems != "" {
+		if b, err := strconv.ParseBool(fullItems); err == nil {
+			cfg.Audiobookshelf.FullItems = b
+		}
+	}
+	// Users are given as comma-separated user=hardcover_token pairs
+	if users := os.Getenv("AUDIOBOOKSHELF_USERS"); users != "" {
+		cfg.Audiobookshelf.Users = nil
+		for _, pair := range parseCommaSeparatedList(users) {
+			user, token, _ := strings.Cut(pair, "=")
+			cfg.Audiobookshelf.Users = append(cfg.Audiobookshelf.Users, AudiobookshelfUser{
+				User:           strings.TrimSpace(user),
+				HardcoverToken: strings.TrimSpace(token),
+			})
+		}
+	}
 
 	// Hardcover configuration
 	if token := os.Getenv("HARDCOVER_TOKEN"); token != "" {
@@ -582,6 +1455,36 @@ func loadFromEnv(cfg *Config) {
 	if baseURL := os.Getenv("HARDCOVER_BASE_URL"); baseURL != "" {
 		cfg.Hardcover.BaseURL = strings.TrimSuffix(baseURL, "/")
 	}
+	if allowed := os.Getenv("HARDCOVER_ALLOWED_OPERATIONS"); allowed != "" {
+		cfg.Hardcover.AllowedOperations = parseCommaSeparatedList(allowed)
+	}
+	if disabled := os.Getenv("HARDCOVER_DISABLED_OPERATIONS"); disabled != "" {
+		cfg.Hardcover.DisabledOperations = parseCommaSeparatedList(disabled)
+	}
+	if negativeCacheTTL := os.Getenv("HARDCOVER_NEGATIVE_CACHE_TTL"); negativeCacheTTL != "" {
+		if d, err := time.ParseDuration(negativeCacheTTL); err == nil {
+			cfg.Hardcover.NegativeCacheTTL = d
+		}
+	}
+	if titleSearchCacheTTL := os.Getenv("HARDCOVER_TITLE_SEARCH_CACHE_TTL"); titleSearchCacheTTL != "" {
+		if d, err := time.ParseDuration(titleSearchCacheTTL); err == nil {
+			cfg.Hardcover.TitleSearchCacheTTL = d
+		}
+	}
+
+	// Database configuration (connection settings are read by the database package)
+	if migrateLegacyState := os.Getenv("DATABASE_MIGRATE_LEGACY_STATE"); migrateLegacyState != "" {
+		if b, err := strconv.ParseBool(migrateLegacyState); err == nil {
+			cfg.Database.MigrateLegacyState = boolPtr(b)
+		}
+	}
+
+	// Rate limiting configuration
+	if shared := os.Getenv("RATE_LIMIT_SHARED"); shared != "" {
+		if b, err := strconv.ParseBool(shared); err == nil {
+			cfg.RateLimit.Shared = b
+		}
+	}
 
 	// Server configuration
 	if port := os.Getenv("PORT"); port != "" {
@@ -604,6 +1507,38 @@ func loadFromEnv(cfg *Config) {
 	if logFormat := os.Getenv("LOG_FORMAT"); logFormat != "" {
 		cfg.Logging.Format = logFormat
 	}
+	// NDJSON log file sink
+	cfg.Logging.File = getEnv("LOG_FILE", cfg.Logging.File)
+	if maxSize := os.Getenv("LOG_FILE_MAX_SIZE_MB"); maxSize != "" {
+		if i, err := strconv.Atoi(maxSize); err == nil {
+			cfg.Logging.FileMaxSizeMB = i
+		}
+	}
+	if maxBackups := os.Getenv("LOG_FILE_MAX_BACKUPS"); maxBackups != "" {
+		if i, err := strconv.Atoi(maxBackups); err == nil {
+			cfg.Logging.FileMaxBackups = i
+		}
+	}
+	if disableStdout := os.Getenv("LOG_DISABLE_STDOUT"); disableStdout != "" {
+		if b, err := strconv.ParseBool(disableStdout); err == nil {
+			cfg.Logging.DisableStdout = b
+		}
+	}
+	if mismatchLogLimit := os.Getenv("LOG_MISMATCH_LIMIT"); mismatchLogLimit != "" {
+		if i, err := strconv.Atoi(mismatchLogLimit); err == nil {
+			cfg.Logging.MismatchLogLimit = i
+		}
+	}
+	// OpenTelemetry tracing
+	if tracingEnabled := os.Getenv("TRACING_ENABLED"); tracingEnabled != "" {
+		if b, err := strconv.ParseBool(tracingEnabled); err == nil {
+			cfg.Observability.TracingEnabled = b
+		}
+	}
+	cfg.Observability.OTLPEndpoint = getEnv("TRACING_OTLP_ENDPOINT", cfg.Observability.OTLPEndpoint)
+	cfg.Webhooks.Secret = getEnv("WEBHOOKS_SECRET", cfg.Webhooks.Secret)
+	// ISBN to ASIN lookups
+	cfg.Metadata.ISBNToASINURL = strings.TrimSpace(getEnv("METADATA_ISBN_TO_ASIN_URL", cfg.Metadata.ISBNToASINURL))
 	if syncInterval := os.Getenv("SYNC_INTERVAL"); syncInterval != "" {
 		if d, err := time.ParseDuration(syncInterval); err == nil {
 			cfg.Sync.SyncInterval = d
@@ -666,6 +1601,319 @@ func loadFromEnv(cfg *Config) {
 			cfg.Sync.IncludeEbooks = b
 		}
 	}
+	// Narrator verification option
+	if verifyNarrator := os.Getenv("SYNC_VERIFY_NARRATOR"); verifyNarrator != "" {
+		if b, err := strconv.ParseBool(verifyNarrator); err == nil {
+			cfg.Sync.VerifyNarrator = b
+		}
+	}
+	// Narrator-aware edition matching
+	if matchNarrator := os.Getenv("SYNC_MATCH_NARRATOR"); matchNarrator != "" {
+		if b, err := strconv.ParseBool(matchNarrator); err == nil {
+			cfg.Sync.MatchNarrator = b
+		}
+	}
+	// Title exclusion patterns (set to an empty value to disable filtering)
+	if excludeTitlePatterns, ok := os.LookupEnv("SYNC_EXCLUDE_TITLE_PATTERNS"); ok {
+		cfg.Sync.ExcludeTitlePatterns = parseCommaSeparatedList(excludeTitlePatterns)
+	}
+	// Progress jump sanity cap
+	if maxProgressJump := os.Getenv("SYNC_MAX_PROGRESS_JUMP_SECONDS"); maxProgressJump != "" {
+		if i, err := strconv.Atoi(maxProgressJump); err == nil {
+			cfg.Sync.MaxProgressJumpSeconds = i
+		}
+	}
+	// Progress source preference
+	if progressSource := os.Getenv("SYNC_PROGRESS_SOURCE"); progressSource != "" {
+		cfg.Sync.ProgressSource = progressSource
+	}
+	// Finished flag precedence
+	if finishedSource := os.Getenv("SYNC_FINISHED_SOURCE"); finishedSource != "" {
+		cfg.Sync.FinishedSource = finishedSource
+	}
+	// Direction of the progress sync and whose progress wins when it goes both ways
+	if direction := os.Getenv("SYNC_DIRECTION"); direction != "" {
+		cfg.Sync.Direction = direction
+	}
+	if conflictResolution := os.Getenv("SYNC_CONFLICT_RESOLUTION"); conflictResolution != "" {
+		cfg.Sync.ConflictResolution = conflictResolution
+	}
+	// Source tag for synced books
+	cfg.Sync.SourceTag = getEnv("SYNC_SOURCE_TAG", cfg.Sync.SourceTag)
+	// State write-behind flush interval
+	if stateFlushInterval := os.Getenv("SYNC_STATE_FLUSH_INTERVAL"); stateFlushInterval != "" {
+		if d, err := time.ParseDuration(stateFlushInterval); err == nil {
+			cfg.Sync.StateFlushInterval = d
+		}
+	}
+	if repairStateKeys := os.Getenv("SYNC_REPAIR_STATE_KEYS"); repairStateKeys != "" {
+		if b, err := strconv.ParseBool(repairStateKeys); err == nil {
+			cfg.Sync.RepairStateKeys = b
+		}
+	}
+	// Reading format for created and updated reads
+	if readingFormat := os.Getenv("SYNC_READING_FORMAT"); readingFormat != "" {
+		cfg.Sync.ReadingFormat = strings.ToLower(strings.TrimSpace(readingFormat))
+	}
+	// Pausing on repeated authentication failures
+	if authFailureThreshold := os.Getenv("SYNC_AUTH_FAILURE_THRESHOLD"); authFailureThreshold != "" {
+		if i, err := strconv.Atoi(authFailureThreshold); err == nil {
+			cfg.Sync.AuthFailureThreshold = i
+		}
+	}
+	if authFailureBackoff := os.Getenv("SYNC_AUTH_FAILURE_BACKOFF"); authFailureBackoff != "" {
+		if d, err := time.ParseDuration(authFailureBackoff); err == nil {
+			cfg.Sync.AuthFailureBackoff = d
+		}
+	}
+	if reloadToken := os.Getenv("SYNC_RELOAD_TOKEN_ON_AUTH_FAILURE"); reloadToken != "" {
+		if b, err := strconv.ParseBool(reloadToken); err == nil {
+			cfg.Sync.ReloadTokenOnAuthFailure = b
+		}
+	}
+	// Skip books unchanged since the last successful sync
+	if skipUnchanged := os.Getenv("SYNC_SKIP_UNCHANGED_BOOKS"); skipUnchanged != "" {
+		if b, err := strconv.ParseBool(skipUnchanged); err == nil {
+			cfg.Sync.SkipUnchangedBooks = b
+		}
+	}
+	// Handling of library items without progress data
+	if missingProgressPolicy := os.Getenv("SYNC_MISSING_PROGRESS_POLICY"); missingProgressPolicy != "" {
+		cfg.Sync.MissingProgressPolicy = strings.ToLower(strings.TrimSpace(missingProgressPolicy))
+	}
+	// Time limit for processing a single book
+	if perBookTimeout := os.Getenv("SYNC_PER_BOOK_TIMEOUT"); perBookTimeout != "" {
+		if d, err := time.ParseDuration(perBookTimeout); err == nil {
+			cfg.Sync.PerBookTimeout = d
+		}
+	}
+	// Time limit for a whole sync run
+	if maxRunDuration := os.Getenv("SYNC_MAX_RUN_DURATION"); maxRunDuration != "" {
+		if d, err := time.ParseDuration(maxRunDuration); err == nil {
+			cfg.Sync.MaxRunDuration = d
+		}
+	}
+	// Statuses that bypass incremental skipping
+	if alwaysReverify := os.Getenv("SYNC_ALWAYS_REVERIFY"); alwaysReverify != "" {
+		cfg.Sync.AlwaysReverify = parseCommaSeparatedList(alwaysReverify)
+	}
+	// Lookup of items with conflicting identifiers
+	if identifierConflicts := os.Getenv("SYNC_IDENTIFIER_CONFLICTS"); identifierConflicts != "" {
+		cfg.Sync.IdentifierConflicts = strings.ToLower(strings.TrimSpace(identifierConflicts))
+	}
+	// Progress written for finished books
+	if finishedProgressHandling := os.Getenv("SYNC_FINISHED_PROGRESS_HANDLING"); finishedProgressHandling != "" {
+		cfg.Sync.FinishedProgressHandling = strings.ToLower(strings.TrimSpace(finishedProgressHandling))
+	}
+	// Ebook progress of hybrid items mapped to the audio edition
+	if crossFormatProgress := os.Getenv("SYNC_CROSS_FORMAT_PROGRESS"); crossFormatProgress != "" {
+		if b, err := strconv.ParseBool(crossFormatProgress); err == nil {
+			cfg.Sync.CrossFormatProgress = b
+		}
+	}
+	// Fetching library items ahead of processing
+	if prefetchLibraries := os.Getenv("SYNC_PREFETCH_LIBRARIES"); prefetchLibraries != "" {
+		if i, err := strconv.Atoi(prefetchLibraries); err == nil {
+			cfg.Sync.PrefetchLibraries = i
+		}
+	}
+	// Only add owned books to Want to Read
+	if wantToReadOwnedOnly := os.Getenv("SYNC_WANT_TO_READ_OWNED_ONLY"); wantToReadOwnedOnly != "" {
+		if b, err := strconv.ParseBool(wantToReadOwnedOnly); err == nil {
+			cfg.Sync.WantToReadOwnedOnly = b
+		}
+	}
+	// Suppression of repeated progress writes
+	if progressCacheTTL := os.Getenv("SYNC_PROGRESS_CACHE_TTL"); progressCacheTTL != "" {
+		if d, err := time.ParseDuration(progressCacheTTL); err == nil {
+			cfg.Sync.ProgressCacheTTL = d
+		}
+	}
+	if persistProgressCache := os.Getenv("SYNC_PERSIST_PROGRESS_CACHE"); persistProgressCache != "" {
+		if b, err := strconv.ParseBool(persistProgressCache); err == nil {
+			cfg.Sync.PersistProgressCache = b
+		}
+	}
+	if progressCacheRetention := os.Getenv("SYNC_PROGRESS_CACHE_RETENTION"); progressCacheRetention != "" {
+		if d, err := time.ParseDuration(progressCacheRetention); err == nil {
+			cfg.Sync.ProgressCacheRetention = d
+		}
+	}
+	// Verification of the matched edition's identifiers
+	if strictIdentifierMatch := os.Getenv("SYNC_STRICT_IDENTIFIER_MATCH"); strictIdentifierMatch != "" {
+		if b, err := strconv.ParseBool(strictIdentifierMatch); err == nil {
+			cfg.Sync.StrictIdentifierMatch = b
+		}
+	}
+	// Trust levels of identifier types
+	if identifierTrust := os.Getenv("SYNC_IDENTIFIER_TRUST"); identifierTrust != "" {
+		cfg.Sync.IdentifierTrust = make(map[string]bool)
+		for _, pair := range parseCommaSeparatedList(identifierTrust) {
+			identifier, trusted, ok := strings.Cut(pair, "=")
+			if !ok {
+				continue
+			}
+			if b, err := strconv.ParseBool(strings.TrimSpace(trusted)); err == nil {
+				cfg.Sync.IdentifierTrust[strings.ToLower(strings.TrimSpace(identifier))] = b
+			}
+		}
+	}
+	// Streaming of mismatches as they're recorded
+	if streamMismatches := os.Getenv("SYNC_STREAM_MISMATCHES"); streamMismatches != "" {
+		if b, err := strconv.ParseBool(streamMismatches); err == nil {
+			cfg.Sync.StreamMismatches = b
+		}
+	}
+	// Fix suggestions in mismatch records
+	if mismatchFixSuggestions := os.Getenv("SYNC_MISMATCH_FIX_SUGGESTIONS"); mismatchFixSuggestions != "" {
+		if b, err := strconv.ParseBool(mismatchFixSuggestions); err == nil {
+			cfg.Sync.MismatchFixSuggestions = b
+		}
+	}
+	// Progress-only mode that leaves book statuses alone
+	if progressOnly := os.Getenv("SYNC_PROGRESS_ONLY"); progressOnly != "" {
+		if b, err := strconv.ParseBool(progressOnly); err == nil {
+			cfg.Sync.ProgressOnly = b
+		}
+	}
+	// Ownership-only mode that leaves reads and statuses alone
+	if ownershipOnly := os.Getenv("SYNC_OWNERSHIP_ONLY"); ownershipOnly != "" {
+		if b, err := strconv.ParseBool(ownershipOnly); err == nil {
+			cfg.Sync.OwnershipOnly = b
+		}
+	}
+	// Recording of match details in the sync state
+	if recordMatchInfo := os.Getenv("SYNC_RECORD_MATCH_INFO"); recordMatchInfo != "" {
+		if b, err := strconv.ParseBool(recordMatchInfo); err == nil {
+			cfg.Sync.RecordMatchInfo = b
+		}
+	}
+	// Mapping suggestions from title/author matches
+	if suggestTitleAuthorMatches := os.Getenv("SYNC_SUGGEST_TITLE_AUTHOR_MATCHES"); suggestTitleAuthorMatches != "" {
+		if b, err := strconv.ParseBool(suggestTitleAuthorMatches); err == nil {
+			cfg.Sync.SuggestTitleAuthorMatches = b
+		}
+	}
+	// Per-run summary webhook
+	if summaryWebhookURL := os.Getenv("SYNC_SUMMARY_WEBHOOK_URL"); summaryWebhookURL != "" {
+		cfg.Sync.SummaryWebhookURL = strings.TrimSpace(summaryWebhookURL)
+	}
+	// Book-level tracking of books without a matching edition
+	if allowBookLevelTracking := os.Getenv("SYNC_ALLOW_BOOK_LEVEL_TRACKING"); allowBookLevelTracking != "" {
+		if b, err := strconv.ParseBool(allowBookLevelTracking); err == nil {
+			cfg.Sync.AllowBookLevelTracking = b
+		}
+	}
+	// Retries and cached fallback of the user progress fetch
+	if userProgressRetries := os.Getenv("SYNC_USER_PROGRESS_RETRIES"); userProgressRetries != "" {
+		if n, err := strconv.Atoi(userProgressRetries); err == nil && n >= 0 {
+			cfg.Sync.UserProgressRetries = n
+		}
+	}
+	if userProgressCacheMaxAge := os.Getenv("SYNC_USER_PROGRESS_CACHE_MAX_AGE"); userProgressCacheMaxAge != "" {
+		if d, err := time.ParseDuration(userProgressCacheMaxAge); err == nil {
+			cfg.Sync.UserProgressCacheMaxAge = d
+		}
+	}
+	// Skipping of books already finished in Hardcover
+	if skipHardcoverFinished := os.Getenv("SYNC_SKIP_HARDCOVER_FINISHED"); skipHardcoverFinished != "" {
+		if b, err := strconv.ParseBool(skipHardcoverFinished); err == nil {
+			cfg.Sync.SkipHardcoverFinished = b
+		}
+	}
+	// Skipping of podcasts
+	if skipPodcasts := os.Getenv("SYNC_SKIP_PODCASTS"); skipPodcasts != "" {
+		if b, err := strconv.ParseBool(skipPodcasts); err == nil {
+			cfg.Sync.SkipPodcasts = b
+		}
+	}
+	// Syncing of collections as Hardcover lists
+	if syncCollections := os.Getenv("SYNC_COLLECTIONS"); syncCollections != "" {
+		if b, err := strconv.ParseBool(syncCollections); err == nil {
+			cfg.Sync.SyncCollections = b
+		}
+	}
+	// Prefetching of the user's Hardcover books
+	if prefetchUserBooks := os.Getenv("SYNC_PREFETCH_USER_BOOKS"); prefetchUserBooks != "" {
+		if b, err := strconv.ParseBool(prefetchUserBooks); err == nil {
+			cfg.Sync.PrefetchUserBooks = b
+		}
+	}
+	// Audiobookshelf tag of abandoned books
+	if abandonedTag := os.Getenv("SYNC_ABANDONED_TAG"); abandonedTag != "" {
+		cfg.Sync.AbandonedTag = strings.TrimSpace(abandonedTag)
+	}
+	// Heartbeat file written during syncs
+	if heartbeatFile := os.Getenv("SYNC_HEARTBEAT_FILE"); heartbeatFile != "" {
+		cfg.Sync.HeartbeatFile = strings.TrimSpace(heartbeatFile)
+	}
+	if heartbeatInterval := os.Getenv("SYNC_HEARTBEAT_INTERVAL"); heartbeatInterval != "" {
+		if d, err := time.ParseDuration(heartbeatInterval); err == nil {
+			cfg.Sync.HeartbeatInterval = d
+		}
+	}
+	// Hardcover books pinned per item, as "itemID=slug" pairs
+	if bookOverrides := os.Getenv("SYNC_BOOK_OVERRIDES"); bookOverrides != "" {
+		cfg.Sync.BookOverrides = make(map[string]string)
+		for _, pair := range parseCommaSeparatedList(bookOverrides) {
+			itemID, slug, ok := strings.Cut(pair, "=")
+			if ok && strings.TrimSpace(itemID) != "" && strings.TrimSpace(slug) != "" {
+				cfg.Sync.BookOverrides[strings.TrimSpace(itemID)] = strings.TrimSpace(slug)
+			}
+		}
+	}
+	// Tolerance for progress past the duration
+	if overProgressTolerance := os.Getenv("SYNC_OVER_PROGRESS_TOLERANCE"); overProgressTolerance != "" {
+		if f, err := strconv.ParseFloat(overProgressTolerance, 64); err == nil {
+			cfg.Sync.OverProgressTolerance = f
+		}
+	}
+	// Progress at which books are taken as finished
+	if finishedThreshold := os.Getenv("SYNC_FINISHED_THRESHOLD"); finishedThreshold != "" {
+		if f, err := strconv.ParseFloat(finishedThreshold, 64); err == nil {
+			cfg.Sync.FinishedThreshold = f
+		}
+	}
+	// Title similarity needed for title/author matches
+	if titleMatchThreshold := os.Getenv("SYNC_TITLE_MATCH_THRESHOLD"); titleMatchThreshold != "" {
+		if f, err := strconv.ParseFloat(titleMatchThreshold, 64); err == nil {
+			cfg.Sync.TitleMatchThreshold = f
+		}
+	}
+	// Merging of reads of the same listening session
+	if mergeOverlappingReads := os.Getenv("SYNC_MERGE_OVERLAPPING_READS"); mergeOverlappingReads != "" {
+		if b, err := strconv.ParseBool(mergeOverlappingReads); err == nil {
+			cfg.Sync.MergeOverlappingReads = b
+		}
+	}
+	// Audiobookshelf items that are never synced
+	if blocklist := os.Getenv("SYNC_BLOCKLIST"); blocklist != "" {
+		cfg.Sync.Blocklist = parseCommaSeparatedList(blocklist)
+	}
+	// Full syncs an item can be missing from before its state is dropped
+	if stateRetentionRuns := os.Getenv("SYNC_STATE_RETENTION_RUNS"); stateRetentionRuns != "" {
+		if i, err := strconv.Atoi(stateRetentionRuns); err == nil {
+			cfg.Sync.StateRetentionRuns = i
+		}
+	}
+	// Interval between full syncs in incremental mode
+	if fullSyncInterval := os.Getenv("SYNC_FULL_SYNC_INTERVAL"); fullSyncInterval != "" {
+		if d, err := time.ParseDuration(fullSyncInterval); err == nil {
+			cfg.Sync.FullSyncInterval = d
+		}
+	}
+	// Skipping of hidden or archived items
+	if skipArchived := os.Getenv("SYNC_SKIP_ARCHIVED"); skipArchived != "" {
+		if b, err := strconv.ParseBool(skipArchived); err == nil {
+			cfg.Sync.SkipArchived = boolPtr(b)
+		}
+	}
+	// Grace period before recording books not found in Hardcover
+	if notFoundGracePeriod := os.Getenv("SYNC_NOT_FOUND_GRACE_PERIOD"); notFoundGracePeriod != "" {
+		if d, err := time.ParseDuration(notFoundGracePeriod); err == nil {
+			cfg.Sync.NotFoundGracePeriod = d
+		}
+	}
 	// Library filtering from environment variables
 	if librariesInclude := os.Getenv("SYNC_LIBRARIES_INCLUDE"); librariesInclude != "" {
 		cfg.Sync.Libraries.Include = parseCommaSeparatedList(librariesInclude)
@@ -677,6 +1925,43 @@ func loadFromEnv(cfg *Config) {
 	// File paths
 	cfg.Paths.CacheDir = getEnv("CACHE_DIR", cfg.Paths.CacheDir)
 	cfg.Paths.MismatchOutputDir = getEnv("MISMATCH_OUTPUT_DIR", cfg.Paths.MismatchOutputDir)
+	if mismatchFormats := os.Getenv("MISMATCH_FORMATS"); mismatchFormats != "" {
+		cfg.Paths.MismatchFormats = parseCommaSeparatedList(mismatchFormats)
+	}
+	cfg.Paths.OverridesFile = getEnv("OVERRIDES_FILE", cfg.Paths.OverridesFile)
+	cfg.Paths.DryRunReport = getEnv("DRY_RUN_REPORT", cfg.Paths.DryRunReport)
+	cfg.Paths.BlocklistFile = getEnv("BLOCKLIST_FILE", cfg.Paths.BlocklistFile)
+	cfg.Paths.UnmatchedExport = getEnv("UNMATCHED_EXPORT", cfg.Paths.UnmatchedExport)
+
+	// Edition creation defaults
+	if languageID := os.Getenv("EDITION_DEFAULT_LANGUAGE_ID"); languageID != "" {
+		if i, err := strconv.Atoi(languageID); err == nil {
+			cfg.Edition.Defaults.LanguageID = i
+		}
+	}
+	if countryID := os.Getenv("EDITION_DEFAULT_COUNTRY_ID"); countryID != "" {
+		if i, err := strconv.Atoi(countryID); err == nil {
+			cfg.Edition.Defaults.CountryID = i
+		}
+	}
+	if publisherID := os.Getenv("EDITION_DEFAULT_PUBLISHER_ID"); publisherID != "" {
+		if i, err := strconv.Atoi(publisherID); err == nil {
+			cfg.Edition.Defaults.PublisherID = i
+		}
+	}
+	if resolveConcurrency := os.Getenv("EDITION_RESOLVE_CONCURRENCY"); resolveConcurrency != "" {
+		if i, err := strconv.Atoi(resolveConcurrency); err == nil {
+			cfg.Edition.ResolveConcurrency = i
+		}
+	}
+	if publisherSource := os.Getenv("EDITION_PUBLISHER_SOURCE"); publisherSource != "" {
+		cfg.Edition.PublisherSource = publisherSource
+	}
+	if createMissingPublishers := os.Getenv("EDITION_CREATE_MISSING_PUBLISHERS"); createMissingPublishers != "" {
+		if b, err := strconv.ParseBool(createMissingPublishers); err == nil {
+			cfg.Edition.CreateMissingPublishers = b
+		}
+	}
 }
 
 // mergeConfigs merges non-zero values from src into dst
@@ -712,6 +1997,9 @@ func mergeConfigs(dst, src *Config) {
 // mergeValues recursively merges src into dst following these rules:
 // - Strings/Floats/Ints: copy only when src is non-zero
 // - Bools: always copy (false is a valid explicit value in config)
+// - Slices: copy when src is non-nil (an explicit empty list clears the default)
+// - Maps: copy when src is non-nil, like slices
+// - Pointers: copy when src is non-nil, for bools whose default is true
 // - Structs: recurse into fields
 func mergeValues(dst, src reflect.Value) {
     if !dst.CanSet() {
@@ -747,9 +2035,24 @@ func mergeValues(dst, src reflect.Value) {
     case reflect.Bool:
         // Always set boolean values from config (explicit false is valid)
         dst.SetBool(src.Bool())
+    case reflect.Slice, reflect.Map, reflect.Ptr:
+        if !src.IsNil() {
+            dst.Set(src)
+        }
     }
 }
 
+// SplitConfigPaths splits a comma-separated list of config file paths, dropping empty entries
+func SplitConfigPaths(configPath string) []string {
+	var paths []string
+	for _, path := range strings.Split(configPath, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
 // getEnv returns the value of an environment variable or a default value
 func getEnv(key, fallback string) string {
 	if value, exists := os.LookupEnv(key); exists {
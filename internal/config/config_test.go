@@ -2,7 +2,9 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -173,3 +175,385 @@ http:
 	assert.Equal(t, "test-audiobookshelf-token", cfg.Audiobookshelf.Token)
 	assert.Equal(t, "test-hardcover-token", cfg.Hardcover.Token)
 }
+
+func TestExcludeTitlePatterns(t *testing.T) {
+	t.Setenv("AUDIOBOOKSHELF_URL", "https://example.com/audiobookshelf")
+	t.Setenv("AUDIOBOOKSHELF_TOKEN", "test-audiobookshelf-token")
+	t.Setenv("HARDCOVER_TOKEN", "test-hardcover-token")
+
+	writeConfig := func(t *testing.T, content string) string {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+		return path
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		cfg, err := Load("")
+		require.NoError(t, err)
+		assert.Equal(t, DefaultExcludeTitlePatterns, cfg.Sync.ExcludeTitlePatterns)
+	})
+
+	t.Run("custom patterns from file", func(t *testing.T) {
+		cfg, err := Load(writeConfig(t, "sync:\n  exclude_title_patterns:\n    - \"(?i)zusammenfassung\"\n"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"(?i)zusammenfassung"}, cfg.Sync.ExcludeTitlePatterns)
+	})
+
+	t.Run("empty list in file disables filtering", func(t *testing.T) {
+		cfg, err := Load(writeConfig(t, "sync:\n  exclude_title_patterns: []\n"))
+		require.NoError(t, err)
+		assert.Empty(t, cfg.Sync.ExcludeTitlePatterns)
+	})
+
+	t.Run("empty environment variable disables filtering", func(t *testing.T) {
+		t.Setenv("SYNC_EXCLUDE_TITLE_PATTERNS", "")
+		cfg, err := Load("")
+		require.NoError(t, err)
+		assert.Empty(t, cfg.Sync.ExcludeTitlePatterns)
+	})
+
+	t.Run("invalid pattern is rejected", func(t *testing.T) {
+		t.Setenv("SYNC_EXCLUDE_TITLE_PATTERNS", "(unclosed")
+		_, err := Load("")
+		assert.Error(t, err)
+	})
+}
+
+func TestLoadMultipleConfigFiles(t *testing.T) {
+	t.Setenv("AUDIOBOOKSHELF_URL", "https://example.com/audiobookshelf")
+	t.Setenv("AUDIOBOOKSHELF_TOKEN", "test-audiobookshelf-token")
+	t.Setenv("HARDCOVER_TOKEN", "test-hardcover-token")
+
+	dir := t.TempDir()
+	writeConfig := func(t *testing.T, name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+		return path
+	}
+
+	base := writeConfig(t, "base.yaml", `
+sync:
+  dry_run: true
+  sync_interval: 2h
+  minimum_progress: 0.1
+  libraries:
+    include: ["Audiobooks"]
+logging:
+  level: debug
+  format: json
+`)
+	overlay := writeConfig(t, "overlay.yaml", `
+sync:
+  sync_interval: 30m
+logging:
+  level: warn
+`)
+
+	t.Run("overlay values win and base-only values persist", func(t *testing.T) {
+		cfg, err := Load(base + ", " + overlay)
+		require.NoError(t, err)
+
+		// Overridden by the overlay
+		assert.Equal(t, 30*time.Minute, cfg.Sync.SyncInterval)
+		assert.Equal(t, "warn", cfg.Logging.Level)
+
+		// Only set in the base
+		assert.True(t, cfg.Sync.DryRun)
+		assert.Equal(t, 0.1, cfg.Sync.MinimumProgress)
+		assert.Equal(t, []string{"Audiobooks"}, cfg.Sync.Libraries.Include)
+		assert.Equal(t, "json", cfg.Logging.Format)
+	})
+
+	t.Run("order determines precedence", func(t *testing.T) {
+		cfg, err := Load(overlay + "," + base)
+		require.NoError(t, err)
+		assert.Equal(t, 2*time.Hour, cfg.Sync.SyncInterval)
+		assert.Equal(t, "debug", cfg.Logging.Level)
+	})
+
+	t.Run("missing overlay is an error", func(t *testing.T) {
+		_, err := Load(base + "," + filepath.Join(dir, "missing.yaml"))
+		assert.ErrorContains(t, err, "missing.yaml does not exist")
+	})
+
+	t.Run("merged result is validated", func(t *testing.T) {
+		invalid := writeConfig(t, "invalid.yaml", "sync:\n  exclude_title_patterns:\n    - \"(unclosed\"\n")
+		_, err := Load(base + "," + invalid)
+		assert.Error(t, err)
+	})
+}
+
+func TestReadingFormat(t *testing.T) {
+	t.Setenv("AUDIOBOOKSHELF_URL", "https://example.com/audiobookshelf")
+	t.Setenv("AUDIOBOOKSHELF_TOKEN", "test-audiobookshelf-token")
+	t.Setenv("HARDCOVER_TOKEN", "test-hardcover-token")
+
+	t.Run("defaults to auto", func(t *testing.T) {
+		cfg, err := Load("")
+		require.NoError(t, err)
+		assert.Equal(t, ReadingFormatAuto, cfg.Sync.ReadingFormat)
+	})
+
+	t.Run("environment variable", func(t *testing.T) {
+		t.Setenv("SYNC_READING_FORMAT", "Ebook")
+		cfg, err := Load("")
+		require.NoError(t, err)
+		assert.Equal(t, ReadingFormatEbook, cfg.Sync.ReadingFormat)
+	})
+
+	t.Run("unknown format is rejected", func(t *testing.T) {
+		t.Setenv("SYNC_READING_FORMAT", "vinyl")
+		_, err := Load("")
+		assert.Error(t, err)
+	})
+}
+
+func TestMismatchFormats(t *testing.T) {
+	t.Setenv("AUDIOBOOKSHELF_URL", "https://example.com/audiobookshelf")
+	t.Setenv("AUDIOBOOKSHELF_TOKEN", "test-audiobookshelf-token")
+	t.Setenv("HARDCOVER_TOKEN", "test-hardcover-token")
+
+	cfg, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{MismatchFormatJSON}, cfg.Paths.MismatchFormats)
+
+	t.Setenv("MISMATCH_FORMATS", "JSON, csv")
+	cfg, err = Load("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{MismatchFormatJSON, MismatchFormatCSV}, cfg.Paths.MismatchFormats)
+
+	t.Setenv("MISMATCH_FORMATS", "xlsx")
+	_, err = Load("")
+	assert.Error(t, err)
+}
+
+func TestMissingProgressPolicy(t *testing.T) {
+	t.Setenv("AUDIOBOOKSHELF_URL", "https://example.com/audiobookshelf")
+	t.Setenv("AUDIOBOOKSHELF_TOKEN", "test-audiobookshelf-token")
+	t.Setenv("HARDCOVER_TOKEN", "test-hardcover-token")
+
+	cfg, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, MissingProgressWantToRead, cfg.Sync.MissingProgressPolicy)
+
+	t.Setenv("SYNC_MISSING_PROGRESS_POLICY", "skip")
+	cfg, err = Load("")
+	require.NoError(t, err)
+	assert.Equal(t, MissingProgressSkip, cfg.Sync.MissingProgressPolicy)
+
+	t.Setenv("SYNC_MISSING_PROGRESS_POLICY", "ignore")
+	_, err = Load("")
+	assert.Error(t, err)
+}
+
+func TestAlwaysReverify(t *testing.T) {
+	t.Setenv("AUDIOBOOKSHELF_URL", "https://example.com/audiobookshelf")
+	t.Setenv("AUDIOBOOKSHELF_TOKEN", "test-audiobookshelf-token")
+	t.Setenv("HARDCOVER_TOKEN", "test-hardcover-token")
+
+	t.Setenv("SYNC_ALWAYS_REVERIFY", "finished, want-to-read")
+	cfg, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"FINISHED", "WANT_TO_READ"}, cfg.Sync.AlwaysReverify)
+
+	t.Setenv("SYNC_ALWAYS_REVERIFY", "FINISHED,DNF")
+	_, err = Load("")
+	assert.Error(t, err)
+}
+
+func TestBookOverrides(t *testing.T) {
+	t.Setenv("AUDIOBOOKSHELF_URL", "https://example.com/audiobookshelf")
+	t.Setenv("AUDIOBOOKSHELF_TOKEN", "test-audiobookshelf-token")
+	t.Setenv("HARDCOVER_TOKEN", "test-hardcover-token")
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("sync:\n  book_overrides:\n    li_1: project-hail-mary\n"), 0600))
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"li_1": "project-hail-mary"}, cfg.Sync.BookOverrides)
+
+	t.Setenv("SYNC_BOOK_OVERRIDES", "li_2 = dune, li_3=https://hardcover.app/books/the-martian?ref=x, invalid")
+	cfg, err = Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"li_2": "dune",
+		"li_3": "https://hardcover.app/books/the-martian?ref=x",
+	}, cfg.Sync.BookOverrides)
+}
+
+func TestLibraryOverrides(t *testing.T) {
+	t.Setenv("AUDIOBOOKSHELF_URL", "https://example.com/audiobookshelf")
+	t.Setenv("AUDIOBOOKSHELF_TOKEN", "test-audiobookshelf-token")
+	t.Setenv("HARDCOVER_TOKEN", "test-hardcover-token")
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("sync:\n  libraries:\n    overrides:\n      Test Library:\n        dry_run: true\n"), 0600))
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]LibraryOverride{"Test Library": {DryRun: true}}, cfg.Sync.Libraries.Overrides)
+	assert.False(t, cfg.Sync.DryRun)
+
+	require.NoError(t, os.WriteFile(path, []byte("sync:\n  libraries:\n    overrides:\n      Ebooks:\n        reading_format: ebook\n"), 0600))
+	cfg, err = Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, ReadingFormatEbook, cfg.Sync.Libraries.Overrides["Ebooks"].ReadingFormat)
+
+	require.NoError(t, os.WriteFile(path, []byte("sync:\n  libraries:\n    overrides:\n      Ebooks:\n        reading_format: vinyl\n"), 0600))
+	_, err = Load(path)
+	assert.Error(t, err)
+}
+
+func TestIdentifierTrust(t *testing.T) {
+	t.Setenv("AUDIOBOOKSHELF_URL", "https://example.com/audiobookshelf")
+	t.Setenv("AUDIOBOOKSHELF_TOKEN", "test-audiobookshelf-token")
+	t.Setenv("HARDCOVER_TOKEN", "test-hardcover-token")
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("sync:\n  identifier_trust:\n    isbn: false\n"), 0600))
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{IdentifierISBN: false}, cfg.Sync.IdentifierTrust)
+
+	t.Setenv("SYNC_IDENTIFIER_TRUST", "ASIN=false, isbn=true")
+	cfg, err = Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{IdentifierASIN: false, IdentifierISBN: true}, cfg.Sync.IdentifierTrust)
+
+	t.Setenv("SYNC_IDENTIFIER_TRUST", "upc=false")
+	_, err = Load(path)
+	assert.ErrorContains(t, err, "sync.identifier_trust")
+}
+
+func TestMetadataISBNToASINURL(t *testing.T) {
+	t.Setenv("AUDIOBOOKSHELF_URL", "https://example.com/audiobookshelf")
+	t.Setenv("AUDIOBOOKSHELF_TOKEN", "test-audiobookshelf-token")
+	t.Setenv("HARDCOVER_TOKEN", "test-hardcover-token")
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("metadata:\n  isbn_to_asin_url: https://example.com/isbn/{isbn}\n"), 0600))
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/isbn/{isbn}", cfg.Metadata.ISBNToASINURL)
+
+	t.Setenv("METADATA_ISBN_TO_ASIN_URL", " http://localhost:8080/lookup ")
+	cfg, err = Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080/lookup", cfg.Metadata.ISBNToASINURL)
+
+	t.Setenv("METADATA_ISBN_TO_ASIN_URL", "localhost:8080/lookup")
+	_, err = Load(path)
+	assert.ErrorContains(t, err, "metadata.isbn_to_asin_url")
+}
+
+func TestObservability(t *testing.T) {
+	t.Setenv("AUDIOBOOKSHELF_URL", "https://example.com/audiobookshelf")
+	t.Setenv("AUDIOBOOKSHELF_TOKEN", "test-audiobookshelf-token")
+	t.Setenv("HARDCOVER_TOKEN", "test-hardcover-token")
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("observability:\n  otlp_endpoint: http://collector:4318\n"), 0600))
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.False(t, cfg.Observability.TracingEnabled)
+	assert.Equal(t, "http://collector:4318", cfg.Observability.OTLPEndpoint)
+
+	t.Setenv("TRACING_ENABLED", "true")
+	t.Setenv("TRACING_OTLP_ENDPOINT", "http://localhost:4318")
+	cfg, err = Load(path)
+	require.NoError(t, err)
+	assert.True(t, cfg.Observability.TracingEnabled)
+	assert.Equal(t, "http://localhost:4318", cfg.Observability.OTLPEndpoint)
+}
+
+func TestEditionDefaults(t *testing.T) {
+	t.Setenv("AUDIOBOOKSHELF_URL", "https://example.com/audiobookshelf")
+	t.Setenv("AUDIOBOOKSHELF_TOKEN", "test-audiobookshelf-token")
+	t.Setenv("HARDCOVER_TOKEN", "test-hardcover-token")
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("edition:\n  defaults:\n    language_id: 4\n    country_id: 9\n"), 0600))
+	t.Setenv("EDITION_DEFAULT_PUBLISHER_ID", "42")
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, EditionDefaults{LanguageID: 4, CountryID: 9, PublisherID: 42}, cfg.Edition.Defaults)
+
+	t.Setenv("EDITION_DEFAULT_PUBLISHER_ID", "-1")
+	_, err = Load(path)
+	assert.Error(t, err)
+}
+
+func TestAudiobookshelfUsers(t *testing.T) {
+	t.Setenv("AUDIOBOOKSHELF_URL", "https://example.com/audiobookshelf")
+	t.Setenv("AUDIOBOOKSHELF_TOKEN", "test-admin-token")
+	t.Setenv("HARDCOVER_TOKEN", "test-hardcover-token")
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("audiobookshelf:\n  users:\n    - user: alice\n      hardcover_token: alice-token\n"), 0600))
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, []AudiobookshelfUser{{User: "alice", HardcoverToken: "alice-token"}}, cfg.Audiobookshelf.Users)
+
+	t.Setenv("AUDIOBOOKSHELF_USERS", "alice=alice-token, usr_2=bob-token")
+	cfg, err = Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, []AudiobookshelfUser{
+		{User: "alice", HardcoverToken: "alice-token"},
+		{User: "usr_2", HardcoverToken: "bob-token"},
+	}, cfg.Audiobookshelf.Users)
+
+	t.Setenv("AUDIOBOOKSHELF_USERS", "alice=alice-token,bob")
+	_, err = Load(path)
+	assert.Error(t, err, "a user without a Hardcover token is rejected")
+
+	t.Setenv("AUDIOBOOKSHELF_USERS", "alice=alice-token,Alice=other-token")
+	_, err = Load(path)
+	assert.Error(t, err, "a user mapped twice is rejected")
+}
+
+func TestSkipArchived(t *testing.T) {
+	t.Setenv("AUDIOBOOKSHELF_URL", "https://example.com/audiobookshelf")
+	t.Setenv("AUDIOBOOKSHELF_TOKEN", "test-audiobookshelf-token")
+	t.Setenv("HARDCOVER_TOKEN", "test-hardcover-token")
+
+	// A config file that doesn't set it keeps the default
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("sync:\n  incremental: true\n"), 0600))
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.True(t, cfg.SkipArchivedItems())
+
+	require.NoError(t, os.WriteFile(path, []byte("sync:\n  skip_archived: false\n"), 0600))
+	cfg, err = Load(path)
+	require.NoError(t, err)
+	assert.False(t, cfg.SkipArchivedItems())
+
+	t.Setenv("SYNC_SKIP_ARCHIVED", "true")
+	cfg, err = Load(path)
+	require.NoError(t, err)
+	assert.True(t, cfg.SkipArchivedItems())
+}
+
+func TestMigrateLegacyState(t *testing.T) {
+	t.Setenv("AUDIOBOOKSHELF_URL", "https://example.com/audiobookshelf")
+	t.Setenv("AUDIOBOOKSHELF_TOKEN", "test-audiobookshelf-token")
+	t.Setenv("HARDCOVER_TOKEN", "test-hardcover-token")
+
+	// A config file that doesn't set it keeps the default
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("database:\n  type: sqlite\n"), 0600))
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.True(t, cfg.MigrateLegacyStateEnabled())
+
+	require.NoError(t, os.WriteFile(path, []byte("database:\n  migrate_legacy_state: false\n"), 0600))
+	cfg, err = Load(path)
+	require.NoError(t, err)
+	assert.False(t, cfg.MigrateLegacyStateEnabled())
+
+	t.Setenv("DATABASE_MIGRATE_LEGACY_STATE", "true")
+	cfg, err = Load(path)
+	require.NoError(t, err)
+	assert.True(t, cfg.MigrateLegacyStateEnabled())
+}
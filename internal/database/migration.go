@@ -1,6 +1,7 @@
 package database
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,6 +10,7 @@ import (
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/crypto"
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/sync/state"
 )
 
 // MigrationManager handles migration from single-user config to multi-user database
@@ -25,10 +27,12 @@ func NewMigrationManager(repo *Repository, log *logger.Logger) *MigrationManager
 	}
 }
 
-// MigrateFromSingleUserConfig migrates from single-user config file to multi-user database
+// MigrateFromSingleUserConfig migrates from single-user config file to multi-user database.
+// configPath may list multiple comma-separated files, as accepted by config.Load.
 func (m *MigrationManager) MigrateFromSingleUserConfig(configPath string) error {
-	// Check if config file exists
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+	// Check if any config file exists
+	configFiles := existingConfigFiles(configPath)
+	if len(configFiles) == 0 {
 		m.logger.Info("No existing config file found, skipping migration", map[string]interface{}{
 			"config_path": configPath,
 		})
@@ -118,35 +122,91 @@ func (m *MigrationManager) MigrateFromSingleUserConfig(configPath string) error
 		return fmt.Errorf("failed to create default user: %w", err)
 	}
 
-	// Backup original config file
-	backupPath := configPath + ".backup." + time.Now().Format("20060102-150405")
-	if err := copyFile(configPath, backupPath); err != nil {
-		m.logger.Warn("Failed to backup original config file", map[string]interface{}{
-			"original_path": configPath,
-			"backup_path":   backupPath,
-			"error":         err.Error(),
-		})
-	} else {
-		m.logger.Info("Backed up original config file", map[string]interface{}{
-			"original_path": configPath,
-			"backup_path":   backupPath,
-		})
+	// Attach the single-user sync state to the default profile
+	if cfg.MigrateLegacyStateEnabled() {
+		if err := m.MigrateLegacyState(profileID, cfg.Sync.StateFile); err != nil {
+			m.logger.Warn("Failed to migrate sync state file, the default profile starts without it", map[string]interface{}{
+				"profile_id": profileID,
+				"state_file": cfg.Sync.StateFile,
+				"error":      err.Error(),
+			})
+		}
+	}
+
+	// Backup original config files
+	backupSuffix := ".backup." + time.Now().Format("20060102-150405")
+	for _, configFile := range configFiles {
+		backupPath := configFile + backupSuffix
+		if err := copyFile(configFile, backupPath); err != nil {
+			m.logger.Warn("Failed to backup original config file", map[string]interface{}{
+				"original_path": configFile,
+				"backup_path":   backupPath,
+				"error":         err.Error(),
+			})
+		} else {
+			m.logger.Info("Backed up original config file", map[string]interface{}{
+				"original_path": configFile,
+				"backup_path":   backupPath,
+			})
+		}
 	}
 
 	m.logger.Info("Successfully migrated single-user config to multi-profile database", map[string]interface{}{
-		"profile_id":   profileID,
-		"profile_name": profileName,
-		"config_path":  configPath,
-		"backup_path":  backupPath,
+		"profile_id":    profileID,
+		"profile_name":  profileName,
+		"config_path":   configPath,
+		"backup_suffix": backupSuffix,
 	})
 
 	return nil
 }
 
+// MigrateLegacyState stores the sync state file of a single-user setup as the sync state of the
+// profile, along with the time of its last sync. A missing state file is skipped.
+func (m *MigrationManager) MigrateLegacyState(profileID, statePath string) error {
+	if statePath == "" {
+		statePath = state.DefaultStateFile
+	}
+	if _, err := os.Stat(statePath); os.IsNotExist(err) {
+		m.logger.Debug("No sync state file found, skipping state migration", map[string]interface{}{
+			"state_file": statePath,
+		})
+		return nil
+	}
+
+	legacyState, err := state.LoadState(statePath)
+	if err != nil {
+		return fmt.Errorf("failed to load sync state file: %w", err)
+	}
+	stateData, err := json.Marshal(legacyState)
+	if err != nil {
+		return fmt.Errorf("failed to encode sync state: %w", err)
+	}
+
+	profileState := &ProfileSyncState{
+		ProfileID: profileID,
+		StateData: string(stateData),
+	}
+	if legacyState.LastSync > 0 {
+		lastSync := time.Unix(legacyState.LastSync, 0)
+		profileState.LastSync = &lastSync
+	}
+	if err := m.repository.UpdateSyncState(profileState); err != nil {
+		return fmt.Errorf("failed to store sync state: %w", err)
+	}
+
+	m.logger.Info("Migrated sync state file to profile", map[string]interface{}{
+		"profile_id": profileID,
+		"state_file": statePath,
+		"books":      len(legacyState.Books),
+	})
+	return nil
+}
+
 // CheckMigrationNeeded checks if migration from single-user config is needed
 func (m *MigrationManager) CheckMigrationNeeded(configPath string) (bool, error) {
-	// Check if config file exists
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+	// Check if any config file exists
+	if len(existingConfigFiles(configPath)) == 0 {
 		return false, nil
 	}
 
@@ -218,6 +278,17 @@ func AutoMigrate(dbConfig *DatabaseConfig, configPath string, dataDir string, lo
 	return nil
 }
 
+// existingConfigFiles returns the files of the comma-separated configPath that exist
+func existingConfigFiles(configPath string) []string {
+	var files []string
+	for _, path := range config.SplitConfigPaths(configPath) {
+		if _, err := os.Stat(path); err == nil {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
 // copyFile copies a file from src to dst
 func copyFile(src, dst string) error {
 	// Ensure destination directory exists
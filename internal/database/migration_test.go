@@ -0,0 +1,127 @@
+package database
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/crypto"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/sync/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestMigrationManager returns a migration manager on a fresh SQLite database
+func newTestMigrationManager(t *testing.T) (*MigrationManager, *Repository) {
+	t.Helper()
+	log := logger.Get()
+
+	db, err := NewDatabase(&DatabaseConfig{Type: DatabaseTypeSQLite, Path: filepath.Join(t.TempDir(), "test.db")}, log)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	encryptor, err := crypto.NewEncryptionManagerWithKey(bytes.Repeat([]byte("k"), 32), log)
+	require.NoError(t, err)
+
+	repo := NewRepository(db, encryptor, log)
+	return NewMigrationManager(repo, log), repo
+}
+
+// writeSingleUserConfig writes a single-user config using the state file and returns its path
+func writeSingleUserConfig(t *testing.T, dir, statePath string, migrateState bool) string {
+	t.Helper()
+	configPath := filepath.Join(dir, "config.yaml")
+	content := fmt.Sprintf(`audiobookshelf:
+  url: "https://abs.example.com"
+  token: "abs-token"
+hardcover:
+  token: "hc-token"
+sync:
+  state_file: %q
+database:
+  migrate_legacy_state: %v
+`, statePath, migrateState)
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+	return configPath
+}
+
+func TestMigrateFromSingleUserConfig_LegacyState(t *testing.T) {
+	dir := t.TempDir()
+	statePath := filepath.Join(dir, "sync_state.json")
+
+	legacyState := state.NewState()
+	legacyState.UpdateBook("book-1", 0.5, "IN_PROGRESS")
+	require.NoError(t, legacyState.Save(statePath))
+
+	t.Run("state attached to the default profile", func(t *testing.T) {
+		manager, repo := newTestMigrationManager(t)
+		require.NoError(t, manager.MigrateFromSingleUserConfig(writeSingleUserConfig(t, t.TempDir(), statePath, true)))
+
+		profileState, err := repo.GetSyncState("default")
+		require.NoError(t, err)
+		require.NotNil(t, profileState.LastSync)
+		assert.Equal(t, legacyState.LastSync, profileState.LastSync.Unix())
+
+		var migrated state.State
+		require.NoError(t, json.Unmarshal([]byte(profileState.StateData), &migrated))
+		require.Contains(t, migrated.Books, "book-1")
+		assert.Equal(t, 0.5, migrated.Books["book-1"].LastProgress)
+		assert.Equal(t, "IN_PROGRESS", migrated.Books["book-1"].Status)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		manager, repo := newTestMigrationManager(t)
+		require.NoError(t, manager.MigrateFromSingleUserConfig(writeSingleUserConfig(t, t.TempDir(), statePath, false)))
+
+		profileState, err := repo.GetSyncState("default")
+		require.NoError(t, err)
+		assert.Equal(t, "{}", profileState.StateData)
+		assert.Nil(t, profileState.LastSync)
+	})
+
+	t.Run("missing state file", func(t *testing.T) {
+		manager, repo := newTestMigrationManager(t)
+		missingPath := filepath.Join(t.TempDir(), "missing.json")
+		require.NoError(t, manager.MigrateFromSingleUserConfig(writeSingleUserConfig(t, t.TempDir(), missingPath, true)))
+
+		profileState, err := repo.GetSyncState("default")
+		require.NoError(t, err)
+		assert.Equal(t, "{}", profileState.StateData)
+		assert.NoFileExists(t, missingPath)
+	})
+}
+
+func TestMigrateFromSingleUserConfig_MultipleConfigFiles(t *testing.T) {
+	dir := t.TempDir()
+	statePath := filepath.Join(dir, "sync_state.json")
+	legacyState := state.NewState()
+	legacyState.UpdateBook("book-1", 0.5, "IN_PROGRESS")
+	require.NoError(t, legacyState.Save(statePath))
+
+	base := writeSingleUserConfig(t, dir, filepath.Join(dir, "missing.json"), true)
+	overlay := filepath.Join(dir, "overlay.yaml")
+	require.NoError(t, os.WriteFile(overlay, []byte(fmt.Sprintf("sync:\n  state_file: %q\n", statePath)), 0644))
+	configPath := base + "," + overlay
+
+	manager, repo := newTestMigrationManager(t)
+	needed, err := manager.CheckMigrationNeeded(configPath)
+	require.NoError(t, err)
+	require.True(t, needed)
+	require.NoError(t, manager.MigrateFromSingleUserConfig(configPath))
+
+	// The merged config is migrated, including the state file of the overlay
+	profileState, err := repo.GetSyncState("default")
+	require.NoError(t, err)
+	assert.Contains(t, profileState.StateData, "book-1")
+
+	// Every file is backed up
+	for _, configFile := range []string{base, overlay} {
+		backups, err := filepath.Glob(configFile + ".backup.*")
+		require.NoError(t, err)
+		assert.Len(t, backups, 1, configFile)
+	}
+}
@@ -13,6 +13,9 @@ type SyncProfile struct {
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 	Active    bool      `gorm:"default:true" json:"active"`
+	// DryRun runs the profile's syncs as a read-only preview, without changes in Hardcover,
+	// regardless of the global and sync config
+	DryRun bool `gorm:"default:false" json:"dry_run"`
 
 	// Relationships
 	Config    *SyncProfileConfig `gorm:"foreignKey:ProfileID" json:"config,omitempty"`
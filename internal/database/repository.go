@@ -201,6 +201,22 @@ func (r *Repository) UpdateProfile(profileID, name string) error {
 	return nil
 }
 
+// SetProfileDryRun enables or disables the read-only preview mode of a sync profile
+func (r *Repository) SetProfileDryRun(profileID string, dryRun bool) error {
+	result := r.db.GetDB().Model(&SyncProfile{}).
+		Where("id = ? AND active = ?", profileID, true).
+		Updates(map[string]interface{}{
+			"dry_run":    dryRun,
+			"updated_at": time.Now(),
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update sync profile dry run: %w", result.Error)
+	}
+
+	return nil
+}
+
 // UpdateUserConfig updates user configuration with encrypted tokens
 // If audiobookshelfToken or hardcoverToken are empty, the existing tokens will be preserved
 func (r *Repository) UpdateUserConfig(profileID, audiobookshelfURL, audiobookshelfToken, hardcoverToken string, syncConfig SyncConfigData) error {
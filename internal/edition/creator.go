@@ -14,6 +14,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
 )
@@ -37,6 +38,10 @@ type EditionInput struct {
 	ReleaseDate   string `json:"release_date,omitempty"`
 	EditionInfo   string `json:"edition_information,omitempty"`
 	EditionFormat string `json:"edition_format,omitempty"`
+	// Names resolved to IDs when the edition is created, in addition to the IDs above
+	AuthorNames   []string `json:"author_names,omitempty"`
+	NarratorNames []string `json:"narrator_names,omitempty"`
+	PublisherName string   `json:"publisher_name,omitempty"`
 }
 
 // EditionResult represents the result of an edition creation or update
@@ -44,6 +49,9 @@ type EditionResult struct {
 	Success   bool `json:"success"`
 	EditionID int  `json:"edition_id"`
 	ImageID   int  `json:"image_id"`
+	// UnresolvedPublisher is the publisher name that wasn't found in Hardcover, in which case the
+	// default publisher was used
+	UnresolvedPublisher string `json:"unresolved_publisher,omitempty"`
 }
 
 // GoogleUploadInfo contains the signed upload credentials for Google Cloud Storage
@@ -72,6 +80,10 @@ type HardcoverClient interface {
 	GetGoogleUploadCredentials(ctx context.Context, filename string, editionID int) (*GoogleUploadInfo, error)
 	// GetAuthHeader gets the authentication header for the client
 	GetAuthHeader() string
+	// SearchPeople searches for authors or narrators by name
+	SearchPeople(ctx context.Context, name, personType string, limit int) ([]models.Author, error)
+	// SearchPublishers searches for publishers by name
+	SearchPublishers(ctx context.Context, name string, limit int) ([]models.Publisher, error)
 }
 
 // Creator handles the creation of audiobook editions in Hardcover
@@ -79,8 +91,13 @@ type Creator struct {
 	client              HardcoverClient
 	log                 *logger.Logger
 	dryRun              bool
-	audiobookshelfToken string       // Token for authenticating with Audiobookshelf
-	httpClient          *http.Client // Custom HTTP client for testing
+	audiobookshelfToken string                 // Token for authenticating with Audiobookshelf
+	httpClient          *http.Client           // Custom HTTP client for testing
+	defaults            config.EditionDefaults // IDs used for fields the input doesn't provide
+	resolveConcurrency  int                    // Concurrent name lookups, see SetResolveConcurrency
+	// createMissingPublishers creates publishers that aren't found, see SetCreateMissingPublishers
+	createMissingPublishers bool
+	formats                 map[string]Format // Edition formats by key, see SetFormats
 }
 
 // NewCreator creates a new instance of the edition creator
@@ -136,12 +153,44 @@ func NewCreatorWithHTTPClient(client HardcoverClient, log *logger.Logger, dryRun
 	}
 }
 
+// SetDefaults sets the language, country and publisher IDs used for editions whose input
+// doesn't provide them
+func (c *Creator) SetDefaults(defaults config.EditionDefaults) {
+	c.defaults = defaults
+}
+
+// applyDefaults fills the language, country and publisher IDs missing from the input with the
+// configured defaults
+func (c *Creator) applyDefaults(input *EditionInput) {
+	if input.LanguageID == 0 {
+		input.LanguageID = c.defaults.LanguageID
+	}
+	if input.CountryID == 0 {
+		input.CountryID = c.defaults.CountryID
+	}
+	if input.PublisherID == 0 {
+		input.PublisherID = c.defaults.PublisherID
+	}
+}
+
 // CreateEdition creates a new audiobook edition in Hardcover
 func (c *Creator) CreateEdition(ctx context.Context, input *EditionInput) (*EditionResult, error) {
+	if err := c.resolveNames(ctx, input); err != nil {
+		return nil, fmt.Errorf("failed to resolve names: %w", err)
+	}
+	unresolvedPublisher := ""
+	if input.PublisherID == 0 {
+		unresolvedPublisher = strings.TrimSpace(input.PublisherName)
+	}
+	c.applyDefaults(input)
+
 	// Validate input
 	if err := input.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid input: %w", err)
 	}
+	if _, err := c.ResolveFormat(input.EditionFormat); err != nil {
+		return nil, fmt.Errorf("invalid input: %w", err)
+	}
 
 	c.log.Info("Creating new audiobook edition", map[string]interface{}{
 		"book_id": input.BookID,
@@ -152,9 +201,10 @@ func (c *Creator) CreateEdition(ctx context.Context, input *EditionInput) (*Edit
 	if c.dryRun {
 		c.log.Info("Dry run enabled - no changes will be made", nil)
 		return &EditionResult{
-			Success:   true,
-			EditionID: 0,
-			ImageID:   0,
+			Success:             true,
+			EditionID:           0,
+			ImageID:             0,
+			UnresolvedPublisher: unresolvedPublisher,
 		}, nil
 	}
 
@@ -190,9 +240,10 @@ func (c *Creator) CreateEdition(ctx context.Context, input *EditionInput) (*Edit
 	}
 
 	return &EditionResult{
-		Success:   true,
-		EditionID: editionID,
-		ImageID:   imageID,
+		Success:             true,
+		EditionID:           editionID,
+		ImageID:             imageID,
+		UnresolvedPublisher: unresolvedPublisher,
 	}, nil
 }
 
@@ -621,12 +672,17 @@ func (c *Creator) createEdition(ctx context.Context, input *EditionInput, imageI
 	  }
 	}`
 
+	format, err := c.ResolveFormat(input.EditionFormat)
+	if err != nil {
+		return 0, err
+	}
+
 	// Initialize edition data with required fields
 	editionData := map[string]interface{}{
 		"dto": map[string]interface{}{
 			"title":             input.Title,
-			"edition_format":    "Audiobook",
-			"reading_format_id": 2, // 2 is the ID for Audiobook format
+			"edition_format":    format.Name,
+			"reading_format_id": format.ReadingFormatID,
 		},
 	}
 
@@ -947,22 +1003,23 @@ func (c *Creator) PrepopulateFromBook(ctx context.Context, bookID int) (*Edition
 		input.NarratorIDs = append(input.NarratorIDs, narrator.ID)
 	}
 
-	// Add publisher if available
+	// Add publisher, language and country if available
 	if book.Publisher != nil {
 		input.PublisherID = book.Publisher.ID
 	}
-
-	// Add language if available (default to English)
 	if book.Language != nil {
 		input.LanguageID = book.Language.ID
-	} else {
-		input.LanguageID = 1 // Default to English
 	}
-
-	// Add country if available (default to USA)
 	if book.Country != nil {
 		input.CountryID = book.Country.ID
-	} else {
+	}
+
+	// Fall back to the configured defaults, then to English and the USA
+	c.applyDefaults(input)
+	if input.LanguageID == 0 {
+		input.LanguageID = 1 // Default to English
+	}
+	if input.CountryID == 0 {
 		input.CountryID = 1 // Default to USA
 	}
 
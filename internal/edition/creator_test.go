@@ -68,6 +68,12 @@ func (m *MockHardcoverClient) GetEditionByASIN(ctx context.Context, asin string)
 	return args.Get(0).(*models.Edition), args.Error(1)
 }
 
+// AddTagToBook mocks the AddTagToBook method
+func (m *MockHardcoverClient) AddTagToBook(ctx context.Context, bookID int, tag string) error {
+	args := m.Called(ctx, bookID, tag)
+	return args.Error(0)
+}
+
 // GetBookByID mocks the GetBookByID method
 func (m *MockHardcoverClient) GetBookByID(ctx context.Context, bookID string) (*models.HardcoverBook, error) {
 	args := m.Called(ctx, bookID)
@@ -221,6 +227,24 @@ func (m *MockHardcoverClient) GetGoogleUploadCredentials(ctx context.Context, fi
 	return args.Get(0).(*edition.GoogleUploadInfo), args.Error(1)
 }
 
+// SearchPeople mocks the SearchPeople method
+func (m *MockHardcoverClient) SearchPeople(ctx context.Context, name, personType string, limit int) ([]models.Author, error) {
+	args := m.Called(ctx, name, personType, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Author), args.Error(1)
+}
+
+// SearchPublishers mocks the SearchPublishers method
+func (m *MockHardcoverClient) SearchPublishers(ctx context.Context, name string, limit int) ([]models.Publisher, error) {
+	args := m.Called(ctx, name, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Publisher), args.Error(1)
+}
+
 func newTestCreator(t *testing.T, client edition.HardcoverClient) *edition.Creator {
 	t.Helper()
 
@@ -0,0 +1,84 @@
+package edition_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/edition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditionCreator_CreateEditionDefaults(t *testing.T) {
+	// createWithDefaults creates an edition from input and returns the dto sent to Hardcover
+	createWithDefaults := func(t *testing.T, defaults config.EditionDefaults, input *edition.EditionInput) map[string]interface{} {
+		mockClient := &MockHardcoverClient{}
+		creator := newTestCreator(t, mockClient)
+		creator.SetDefaults(defaults)
+
+		var dto map[string]interface{}
+		mockClient.On("GraphQLMutation", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				variables := args.Get(2).(map[string]interface{})
+				dto = variables["edition"].(map[string]interface{})["dto"].(map[string]interface{})
+
+				resp := args.Get(3).(*struct {
+					InsertEdition struct {
+						ID     interface{} `json:"id"`
+						Errors []string    `json:"errors"`
+					} `json:"insert_edition"`
+				})
+				resp.InsertEdition.ID = 789
+			}).
+			Return(nil).
+			Once()
+
+		result, err := creator.CreateEdition(context.Background(), input)
+		require.NoError(t, err)
+		assert.Equal(t, 789, result.EditionID)
+		mockClient.AssertExpectations(t)
+		return dto
+	}
+
+	defaults := config.EditionDefaults{LanguageID: 4, CountryID: 9, PublisherID: 42}
+
+	t.Run("defaults fill missing fields", func(t *testing.T) {
+		dto := createWithDefaults(t, defaults, &edition.EditionInput{
+			BookID:    123,
+			Title:     "Test Book",
+			AuthorIDs: []int{1},
+		})
+
+		assert.Equal(t, 4, dto["language_id"])
+		assert.Equal(t, 9, dto["country_id"])
+		assert.Equal(t, 42, dto["publisher_id"])
+	})
+
+	t.Run("input values take precedence", func(t *testing.T) {
+		dto := createWithDefaults(t, defaults, &edition.EditionInput{
+			BookID:      123,
+			Title:       "Test Book",
+			AuthorIDs:   []int{1},
+			LanguageID:  2,
+			PublisherID: 5,
+		})
+
+		assert.Equal(t, 2, dto["language_id"])
+		assert.Equal(t, 9, dto["country_id"])
+		assert.Equal(t, 5, dto["publisher_id"])
+	})
+
+	t.Run("no defaults configured", func(t *testing.T) {
+		dto := createWithDefaults(t, config.EditionDefaults{}, &edition.EditionInput{
+			BookID:    123,
+			Title:     "Test Book",
+			AuthorIDs: []int{1},
+		})
+
+		assert.NotContains(t, dto, "language_id")
+		assert.NotContains(t, dto, "country_id")
+		assert.NotContains(t, dto, "publisher_id")
+	})
+}
@@ -0,0 +1,92 @@
+package edition
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+)
+
+// Hardcover reading format IDs, which decide how an edition is read (and shown on the profile)
+const (
+	ReadingFormatPhysical  = 1
+	ReadingFormatAudiobook = 2
+	ReadingFormatBoth      = 3
+	ReadingFormatEbook     = 4
+)
+
+// DefaultFormatKey is the format of editions whose input doesn't set edition_format
+const DefaultFormatKey = "audiobook"
+
+// Format is an edition format as Hardcover stores it: the free-text edition_format string and the
+// reading format it belongs to
+type Format struct {
+	Name            string
+	ReadingFormatID int
+}
+
+// builtinFormats maps canonical format keys to the edition_format strings Hardcover uses. Hardcover
+// doesn't have a reference table for edition_format, so these are the strings its own editions use.
+var builtinFormats = map[string]Format{
+	"audiobook":      {Name: "Audiobook", ReadingFormatID: ReadingFormatAudiobook},
+	"audible":        {Name: "Audible Audio", ReadingFormatID: ReadingFormatAudiobook},
+	"audio_cd":       {Name: "Audio CD", ReadingFormatID: ReadingFormatAudiobook},
+	"mp3_cd":         {Name: "MP3 CD", ReadingFormatID: ReadingFormatAudiobook},
+	"ebook":          {Name: "Ebook", ReadingFormatID: ReadingFormatEbook},
+	"kindle":         {Name: "Kindle Edition", ReadingFormatID: ReadingFormatEbook},
+	"hardcover":      {Name: "Hardcover", ReadingFormatID: ReadingFormatPhysical},
+	"paperback":      {Name: "Paperback", ReadingFormatID: ReadingFormatPhysical},
+	"mass_paperback": {Name: "Mass Market Paperback", ReadingFormatID: ReadingFormatPhysical},
+}
+
+// SetFormats adds the configured edition formats to the built-in ones, replacing built-in formats
+// with the same key. A format without a reading format is an audiobook.
+func (c *Creator) SetFormats(formats map[string]config.EditionFormat) {
+	c.formats = make(map[string]Format, len(builtinFormats)+len(formats))
+	for key, format := range builtinFormats {
+		c.formats[key] = format
+	}
+	for key, format := range formats {
+		readingFormatID := format.ReadingFormatID
+		if readingFormatID == 0 {
+			readingFormatID = ReadingFormatAudiobook
+		}
+		c.formats[formatKey(key)] = Format{Name: strings.TrimSpace(format.Name), ReadingFormatID: readingFormatID}
+	}
+}
+
+// ResolveFormat returns the format for an edition_format value, which is either a canonical key
+// such as "audible" or the Hardcover string such as "Audible Audio", both ignoring case. An empty
+// value is the default format.
+func (c *Creator) ResolveFormat(value string) (Format, error) {
+	formats := c.formats
+	if formats == nil {
+		formats = builtinFormats
+	}
+
+	value = strings.TrimSpace(value)
+	if value == "" {
+		value = DefaultFormatKey
+	}
+	if format, ok := formats[formatKey(value)]; ok {
+		return format, nil
+	}
+	for _, format := range formats {
+		if strings.EqualFold(format.Name, value) {
+			return format, nil
+		}
+	}
+
+	options := make([]string, 0, len(formats))
+	for key, format := range formats {
+		options = append(options, fmt.Sprintf("%s (%q)", key, format.Name))
+	}
+	sort.Strings(options)
+	return Format{}, fmt.Errorf("unknown edition_format %q, valid formats are: %s", value, strings.Join(options, ", "))
+}
+
+// formatKey normalizes a format key, so "MP3 CD" and "mp3-cd" both become "mp3_cd"
+func formatKey(value string) string {
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(strings.ToLower(strings.TrimSpace(value)))
+}
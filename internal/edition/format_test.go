@@ -0,0 +1,91 @@
+package edition_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/edition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditionCreator_ResolveFormat(t *testing.T) {
+	creator := newTestCreator(t, &MockHardcoverClient{})
+	creator.SetFormats(map[string]config.EditionFormat{
+		"Playaway": {Name: "Playaway"},
+	})
+
+	tests := []struct {
+		value    string
+		expected edition.Format
+	}{
+		{"", edition.Format{Name: "Audiobook", ReadingFormatID: edition.ReadingFormatAudiobook}},
+		{"audible", edition.Format{Name: "Audible Audio", ReadingFormatID: edition.ReadingFormatAudiobook}},
+		{"Audible Audio", edition.Format{Name: "Audible Audio", ReadingFormatID: edition.ReadingFormatAudiobook}},
+		{"MP3-CD", edition.Format{Name: "MP3 CD", ReadingFormatID: edition.ReadingFormatAudiobook}},
+		{"kindle", edition.Format{Name: "Kindle Edition", ReadingFormatID: edition.ReadingFormatEbook}},
+		{"playaway", edition.Format{Name: "Playaway", ReadingFormatID: edition.ReadingFormatAudiobook}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			format, err := creator.ResolveFormat(tt.value)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, format)
+		})
+	}
+}
+
+func TestEditionCreator_CreateEditionInvalidFormat(t *testing.T) {
+	mockClient := &MockHardcoverClient{}
+	creator := newTestCreator(t, mockClient)
+
+	_, err := creator.CreateEdition(context.Background(), &edition.EditionInput{
+		BookID:        123,
+		Title:         "Test Book",
+		AuthorIDs:     []int{1},
+		EditionFormat: "Audible Audiobook",
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown edition_format "Audible Audiobook"`)
+	assert.Contains(t, err.Error(), `audible ("Audible Audio")`)
+	assert.Contains(t, err.Error(), `audiobook ("Audiobook")`)
+	mockClient.AssertNotCalled(t, "GraphQLMutation", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestEditionCreator_CreateEditionFormat(t *testing.T) {
+	mockClient := &MockHardcoverClient{}
+	creator := newTestCreator(t, mockClient)
+
+	var dto map[string]interface{}
+	mockClient.On("GraphQLMutation", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			variables := args.Get(2).(map[string]interface{})
+			dto = variables["edition"].(map[string]interface{})["dto"].(map[string]interface{})
+
+			resp := args.Get(3).(*struct {
+				InsertEdition struct {
+					ID     interface{} `json:"id"`
+					Errors []string    `json:"errors"`
+				} `json:"insert_edition"`
+			})
+			resp.InsertEdition.ID = 789
+		}).
+		Return(nil).
+		Once()
+
+	_, err := creator.CreateEdition(context.Background(), &edition.EditionInput{
+		BookID:        123,
+		Title:         "Test Book",
+		AuthorIDs:     []int{1},
+		EditionFormat: "audible",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Audible Audio", dto["edition_format"])
+	assert.Equal(t, edition.ReadingFormatAudiobook, dto["reading_format_id"])
+	mockClient.AssertExpectations(t)
+}
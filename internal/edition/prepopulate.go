@@ -0,0 +1,97 @@
+package edition
+
+import (
+	"context"
+	"strings"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// UnresolvedName is an author or narrator name no Hardcover person ID was found for
+type UnresolvedName struct {
+	Kind  string `json:"kind"` // "author" or "narrator"
+	Name  string `json:"name"`
+	Error string `json:"error"`
+}
+
+// PrepopulatedEdition is edition input prepopulated from an Audiobookshelf item
+type PrepopulatedEdition struct {
+	Input *EditionInput `json:"input"`
+	// NeedsManualID lists the author and narrator names whose IDs have to be added by hand
+	NeedsManualID []UnresolvedName `json:"needs_manual_id,omitempty"`
+}
+
+// PrepopulateFromAudiobookshelf prepopulates the input for the audio edition of an Audiobookshelf
+// item from its metadata, looking up its authors and narrators in Hardcover. Names that aren't
+// found are listed in NeedsManualID instead of being dropped. The book ID isn't known from the
+// item and has to be set before the edition is created.
+func (c *Creator) PrepopulateFromAudiobookshelf(ctx context.Context, item models.AudiobookshelfBook) (*PrepopulatedEdition, error) {
+	metadata := item.Media.Metadata
+	c.log.Debug("Prepopulating edition data from Audiobookshelf item", map[string]interface{}{
+		"item_id": item.ID,
+		"title":   metadata.Title,
+	})
+
+	input := &EditionInput{
+		Title:         metadata.Title,
+		Subtitle:      metadata.Subtitle,
+		ASIN:          strings.TrimSpace(metadata.ASIN),
+		AudioLength:   int(item.Media.Duration + 0.5),
+		PublisherName: strings.TrimSpace(metadata.Publisher),
+		EditionFormat: DefaultFormatKey,
+	}
+	isbn := strings.ReplaceAll(strings.TrimSpace(metadata.ISBN), "-", "")
+	switch len(isbn) {
+	case 10:
+		input.ISBN10 = isbn
+	case 13:
+		input.ISBN13 = isbn
+	}
+	if year := strings.TrimSpace(metadata.PublishedYear); len(year) == 4 {
+		input.ReleaseDate = year + "-01-01"
+	}
+
+	var lookups []*nameLookup
+	seen := make(map[string]bool)
+	add := func(kind, names string) {
+		for _, name := range strings.Split(names, ",") {
+			name = strings.TrimSpace(name)
+			key := kind + ":" + strings.ToLower(name)
+			if name == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			lookups = append(lookups, &nameLookup{kind: kind, name: name})
+		}
+	}
+	add("author", metadata.AuthorName)
+	add("narrator", metadata.NarratorName)
+	c.runLookups(ctx, lookups)
+
+	result := &PrepopulatedEdition{Input: input}
+	for _, lookup := range lookups {
+		if lookup.err != nil {
+			result.NeedsManualID = append(result.NeedsManualID, UnresolvedName{
+				Kind:  lookup.kind,
+				Name:  lookup.name,
+				Error: lookup.err.Error(),
+			})
+			continue
+		}
+		switch lookup.kind {
+		case "author":
+			input.AuthorIDs = appendUniqueID(input.AuthorIDs, lookup.id)
+		case "narrator":
+			input.NarratorIDs = appendUniqueID(input.NarratorIDs, lookup.id)
+		}
+	}
+
+	c.applyDefaults(input)
+	if len(result.NeedsManualID) > 0 {
+		c.log.Warn("Some names need a Hardcover ID added by hand", map[string]interface{}{
+			"item_id":    item.ID,
+			"unresolved": len(result.NeedsManualID),
+		})
+	}
+	return result, nil
+}
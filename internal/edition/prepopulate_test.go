@@ -0,0 +1,58 @@
+package edition_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/edition"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditionCreator_PrepopulateFromAudiobookshelf(t *testing.T) {
+	mockClient := &MockHardcoverClient{}
+	creator := edition.NewCreator(mockClient, logger.Get(), true, "")
+
+	mockClient.On("SearchPeople", mock.Anything, "Frank Herbert", "author", 5).
+		Return([]models.Author{{ID: "101", Name: "Frank Herbert"}}, nil).Once()
+	mockClient.On("SearchPeople", mock.Anything, "Scott Brick", "narrator", 5).
+		Return([]models.Author{{ID: "201", Name: "Scott Brick"}}, nil).Once()
+	mockClient.On("SearchPeople", mock.Anything, "Unknown Narrator", "narrator", 5).
+		Return([]models.Author{}, nil).Once()
+
+	var item models.AudiobookshelfBook
+	item.ID = "li_1"
+	item.Media.Duration = 75599.6
+	item.Media.Metadata = models.AudiobookshelfMetadataStruct{
+		Title:         "Dune",
+		AuthorName:    "Frank Herbert",
+		NarratorName:  "Scott Brick, Unknown Narrator",
+		Publisher:     "Macmillan Audio",
+		PublishedYear: "2007",
+		ISBN:          "978-1-4272-0137-5",
+		ASIN:          "B002V1OF70",
+	}
+
+	result, err := creator.PrepopulateFromAudiobookshelf(context.Background(), item)
+	require.NoError(t, err)
+	mockClient.AssertExpectations(t)
+
+	input := result.Input
+	assert.Equal(t, "Dune", input.Title)
+	assert.Equal(t, []int{101}, input.AuthorIDs)
+	assert.Equal(t, []int{201}, input.NarratorIDs)
+	assert.Equal(t, 75600, input.AudioLength)
+	assert.Equal(t, "B002V1OF70", input.ASIN)
+	assert.Equal(t, "9781427201375", input.ISBN13)
+	assert.Equal(t, "2007-01-01", input.ReleaseDate)
+	assert.Equal(t, "Macmillan Audio", input.PublisherName)
+
+	// The narrator that wasn't found is reported rather than dropped
+	require.Len(t, result.NeedsManualID, 1)
+	assert.Equal(t, "narrator", result.NeedsManualID[0].Kind)
+	assert.Equal(t, "Unknown Narrator", result.NeedsManualID[0].Name)
+	assert.Contains(t, result.NeedsManualID[0].Error, "no narrator found")
+}
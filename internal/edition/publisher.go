@@ -0,0 +1,97 @@
+package edition
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// errPublisherNotFound is returned by resolvePublisher when no Hardcover publisher has the name
+// and missing publishers aren't created
+var errPublisherNotFound = errors.New("publisher not found")
+
+// SetCreateMissingPublishers sets whether a publisher name that isn't found in Hardcover is
+// created there. When disabled, the default publisher is used and the name is reported in the
+// edition result.
+func (c *Creator) SetCreateMissingPublishers(create bool) {
+	c.createMissingPublishers = create
+}
+
+// resolvePublisher returns the Hardcover ID of the publisher with the given name. A result whose
+// name matches exactly (ignoring case) is preferred over the top search result. When nothing is
+// found, the publisher is created if enabled, otherwise errPublisherNotFound is returned.
+func (c *Creator) resolvePublisher(ctx context.Context, name string) (int, error) {
+	publishers, err := c.client.SearchPublishers(ctx, name, 5)
+	if err != nil {
+		return 0, fmt.Errorf("failed to search for publisher %q: %w", name, err)
+	}
+
+	if len(publishers) > 0 {
+		id := publishers[0].ID
+		for _, publisher := range publishers {
+			if strings.EqualFold(strings.TrimSpace(publisher.Name), name) {
+				id = publisher.ID
+				break
+			}
+		}
+		parsed, err := strconv.Atoi(id)
+		if err != nil {
+			return 0, fmt.Errorf("invalid publisher ID %q for %q: %w", id, name, err)
+		}
+		return parsed, nil
+	}
+
+	if !c.createMissingPublishers {
+		return 0, fmt.Errorf("%w: %q", errPublisherNotFound, name)
+	}
+	return c.createPublisher(ctx, name)
+}
+
+// createPublisher creates a publisher in Hardcover and returns its ID. In dry run mode nothing is
+// created and errPublisherNotFound is returned, so the default publisher is used.
+func (c *Creator) createPublisher(ctx context.Context, name string) (int, error) {
+	if c.dryRun {
+		c.log.Info("Dry run enabled - not creating publisher", map[string]interface{}{
+			"publisher": name,
+		})
+		return 0, fmt.Errorf("%w: %q", errPublisherNotFound, name)
+	}
+
+	mutation := `
+	mutation CreatePublisher($publisher: PublisherInputType!) {
+	  insert_publisher(publisher: $publisher) {
+	    id
+	    errors
+	  }
+	}`
+
+	var response struct {
+		InsertPublisher struct {
+			ID     int      `json:"id"`
+			Errors []string `json:"errors"`
+		} `json:"insert_publisher"`
+	}
+
+	variables := map[string]interface{}{
+		"publisher": map[string]interface{}{
+			"name": name,
+		},
+	}
+	if err := c.client.GraphQLMutation(ctx, mutation, variables, &response); err != nil {
+		return 0, fmt.Errorf("failed to create publisher %q: %w", name, err)
+	}
+	if len(response.InsertPublisher.Errors) > 0 {
+		return 0, fmt.Errorf("failed to create publisher %q: %s", name, strings.Join(response.InsertPublisher.Errors, "; "))
+	}
+	if response.InsertPublisher.ID == 0 {
+		return 0, fmt.Errorf("failed to create publisher %q: no ID returned", name)
+	}
+
+	c.log.Info("Created publisher", map[string]interface{}{
+		"publisher":    name,
+		"publisher_id": response.InsertPublisher.ID,
+	})
+	return response.InsertPublisher.ID, nil
+}
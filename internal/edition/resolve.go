@@ -0,0 +1,141 @@
+package edition
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// defaultResolveConcurrency is the number of concurrent people and publisher lookups used when
+// none is configured
+const defaultResolveConcurrency = 4
+
+// SetResolveConcurrency sets how many author, narrator and publisher names are looked up at once.
+// The lookups still go through the client's rate limiter. Zero or less uses the default.
+func (c *Creator) SetResolveConcurrency(n int) {
+	c.resolveConcurrency = n
+}
+
+// nameLookup is a single author, narrator or publisher name to resolve to a Hardcover ID
+type nameLookup struct {
+	kind string // "author", "narrator" or "publisher"
+	name string
+	id   int
+	err  error
+}
+
+// resolveNames resolves the author, narrator and publisher names of the input to Hardcover IDs,
+// looking all of them up at once with bounded concurrency. Resolved IDs are added to the IDs
+// already set on the input; a publisher name is only looked up when no publisher ID is set.
+func (c *Creator) resolveNames(ctx context.Context, input *EditionInput) error {
+	var lookups []*nameLookup
+	seen := make(map[string]bool)
+	add := func(kind string, names ...string) {
+		for _, name := range names {
+			name = strings.TrimSpace(name)
+			key := kind + ":" + strings.ToLower(name)
+			if name == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			lookups = append(lookups, &nameLookup{kind: kind, name: name})
+		}
+	}
+	add("author", input.AuthorNames...)
+	add("narrator", input.NarratorNames...)
+	if input.PublisherID == 0 {
+		add("publisher", input.PublisherName)
+	}
+	if len(lookups) == 0 {
+		return nil
+	}
+	c.runLookups(ctx, lookups)
+
+	// Apply the results in input order so the IDs keep the order of the names
+	var errs []error
+	for _, lookup := range lookups {
+		if errors.Is(lookup.err, errPublisherNotFound) {
+			// Not fatal: the default publisher is used and the name is reported in the result
+			c.log.Warn("Publisher not found in Hardcover, using default publisher", map[string]interface{}{
+				"publisher": lookup.name,
+			})
+			continue
+		}
+		if lookup.err != nil {
+			errs = append(errs, lookup.err)
+			continue
+		}
+		switch lookup.kind {
+		case "author":
+			input.AuthorIDs = appendUniqueID(input.AuthorIDs, lookup.id)
+		case "narrator":
+			input.NarratorIDs = appendUniqueID(input.NarratorIDs, lookup.id)
+		case "publisher":
+			input.PublisherID = lookup.id
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runLookups looks up all names at once with bounded concurrency, setting their IDs or errors
+func (c *Creator) runLookups(ctx context.Context, lookups []*nameLookup) {
+	concurrency := c.resolveConcurrency
+	if concurrency <= 0 {
+		concurrency = defaultResolveConcurrency
+	}
+
+	c.log.Debug("Resolving people and publisher names", map[string]interface{}{
+		"lookups":     len(lookups),
+		"concurrency": concurrency,
+	})
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, lookup := range lookups {
+		wg.Add(1)
+		go func(lookup *nameLookup) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			lookup.id, lookup.err = c.lookupName(ctx, lookup.kind, lookup.name)
+		}(lookup)
+	}
+	wg.Wait()
+}
+
+// lookupName returns the Hardcover ID for an author, narrator or publisher name. For people the
+// top search result is taken; publishers are resolved by resolvePublisher.
+func (c *Creator) lookupName(ctx context.Context, kind, name string) (int, error) {
+	if kind == "publisher" {
+		return c.resolvePublisher(ctx, name)
+	}
+
+	// Like the mismatch people lookup, the top result is taken
+	people, err := c.client.SearchPeople(ctx, name, kind, 5)
+	if err != nil {
+		return 0, fmt.Errorf("failed to search for %s %q: %w", kind, name, err)
+	}
+	if len(people) == 0 {
+		return 0, fmt.Errorf("no %s found for %q", kind, name)
+	}
+	id := people[0].ID
+
+	parsed, err := strconv.Atoi(id)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s ID %q for %q: %w", kind, id, name, err)
+	}
+	return parsed, nil
+}
+
+// appendUniqueID appends id to ids unless it's already there
+func appendUniqueID(ids []int, id int) []int {
+	for _, existing := range ids {
+		if existing == id {
+			return ids
+		}
+	}
+	return append(ids, id)
+}
@@ -0,0 +1,158 @@
+package edition_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/edition"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditionCreator_ResolveNamesConcurrently(t *testing.T) {
+	mockClient := &MockHardcoverClient{}
+	creator := edition.NewCreator(mockClient, logger.Get(), true, "")
+	creator.SetResolveConcurrency(3)
+
+	// Track how many lookups run at once
+	var running, maxRunning int32
+	track := func(mock.Arguments) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			max := atomic.LoadInt32(&maxRunning)
+			if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+	}
+
+	input := &edition.EditionInput{BookID: 1, Title: "Anthology", AuthorIDs: []int{100}}
+	for i := 1; i <= 6; i++ {
+		name := fmt.Sprintf("Author %d", i)
+		input.AuthorNames = append(input.AuthorNames, name)
+		mockClient.On("SearchPeople", mock.Anything, name, "author", 5).Run(track).
+			Return([]models.Author{{ID: strconv.Itoa(100 + i), Name: name}}, nil).Once()
+	}
+	for i := 1; i <= 4; i++ {
+		name := fmt.Sprintf("Narrator %d", i)
+		input.NarratorNames = append(input.NarratorNames, name)
+		mockClient.On("SearchPeople", mock.Anything, name, "narrator", 5).Run(track).
+			Return([]models.Author{{ID: strconv.Itoa(200 + i), Name: name}}, nil).Once()
+	}
+	// Duplicate names are only looked up once
+	input.NarratorNames = append(input.NarratorNames, "narrator 1")
+	input.PublisherName = "Audible Studios"
+	mockClient.On("SearchPublishers", mock.Anything, "Audible Studios", 5).Run(track).
+		Return([]models.Publisher{{ID: "300", Name: "Audible Studios"}}, nil).Once()
+
+	_, err := creator.CreateEdition(context.Background(), input)
+	require.NoError(t, err)
+	mockClient.AssertExpectations(t)
+
+	// Every ID resolves, in the order of the names, after the IDs already given
+	assert.Equal(t, []int{100, 101, 102, 103, 104, 105, 106}, input.AuthorIDs)
+	assert.Equal(t, []int{201, 202, 203, 204}, input.NarratorIDs)
+	assert.Equal(t, 300, input.PublisherID)
+
+	assert.Greater(t, atomic.LoadInt32(&maxRunning), int32(1), "lookups should run concurrently")
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxRunning), int32(3), "lookups should respect the concurrency limit")
+}
+
+func TestEditionCreator_ResolveNamesErrors(t *testing.T) {
+	mockClient := &MockHardcoverClient{}
+	creator := edition.NewCreator(mockClient, logger.Get(), true, "")
+
+	mockClient.On("SearchPeople", mock.Anything, "Known Author", "author", 5).
+		Return([]models.Author{{ID: "101", Name: "Known Author"}}, nil).Once()
+	mockClient.On("SearchPeople", mock.Anything, "Unknown Narrator", "narrator", 5).
+		Return([]models.Author{}, nil).Once()
+
+	input := &edition.EditionInput{
+		BookID:        1,
+		Title:         "Anthology",
+		AuthorNames:   []string{"Known Author"},
+		NarratorNames: []string{"Unknown Narrator"},
+		PublisherID:   42, // A publisher ID takes precedence over the name, which isn't looked up
+		PublisherName: "Ignored Publisher",
+	}
+
+	_, err := creator.CreateEdition(context.Background(), input)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `no narrator found for "Unknown Narrator"`)
+	mockClient.AssertExpectations(t)
+	mockClient.AssertNotCalled(t, "SearchPublishers", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestEditionCreator_ResolvePublisher(t *testing.T) {
+	t.Run("known publisher resolves to its ID", func(t *testing.T) {
+		mockClient := &MockHardcoverClient{}
+		creator := edition.NewCreator(mockClient, logger.Get(), true, "")
+
+		// The exact name match is preferred over the top search result
+		mockClient.On("SearchPublishers", mock.Anything, "Tantor Audio", 5).
+			Return([]models.Publisher{{ID: "10", Name: "Tantor Media"}, {ID: "11", Name: "tantor audio"}}, nil).Once()
+
+		input := &edition.EditionInput{BookID: 1, Title: "Book", AuthorIDs: []int{100}, PublisherName: "Tantor Audio"}
+		result, err := creator.CreateEdition(context.Background(), input)
+		require.NoError(t, err)
+		assert.Equal(t, 11, input.PublisherID)
+		assert.Empty(t, result.UnresolvedPublisher)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("unknown publisher falls back to the default", func(t *testing.T) {
+		mockClient := &MockHardcoverClient{}
+		creator := edition.NewCreator(mockClient, logger.Get(), true, "")
+		creator.SetDefaults(config.EditionDefaults{PublisherID: 7})
+
+		mockClient.On("SearchPublishers", mock.Anything, "Tiny Press", 5).Return([]models.Publisher{}, nil).Once()
+
+		input := &edition.EditionInput{BookID: 1, Title: "Book", AuthorIDs: []int{100}, PublisherName: "Tiny Press"}
+		result, err := creator.CreateEdition(context.Background(), input)
+		require.NoError(t, err)
+		assert.Equal(t, 7, input.PublisherID)
+		assert.Equal(t, "Tiny Press", result.UnresolvedPublisher)
+		mockClient.AssertNotCalled(t, "GraphQLMutation", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown publisher is created when enabled", func(t *testing.T) {
+		mockClient := &MockHardcoverClient{}
+		creator := edition.NewCreator(mockClient, logger.Get(), false, "")
+		creator.SetCreateMissingPublishers(true)
+
+		mockClient.On("SearchPublishers", mock.Anything, "Tiny Press", 5).Return([]models.Publisher{}, nil).Once()
+		mockClient.On("GraphQLMutation", mock.Anything, mock.MatchedBy(func(m string) bool {
+			return strings.Contains(m, "insert_publisher")
+		}), map[string]interface{}{"publisher": map[string]interface{}{"name": "Tiny Press"}}, mock.Anything).
+			Run(func(args mock.Arguments) {
+				require.NoError(t, json.Unmarshal([]byte(`{"insert_publisher":{"id":77}}`), args.Get(3)))
+			}).Return(nil).Once()
+
+		// Stop after the edition mutation, checking it uses the created publisher
+		mockClient.On("GraphQLMutation", mock.Anything, mock.MatchedBy(func(m string) bool {
+			return strings.Contains(m, "insert_edition")
+		}), mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				dto := args.Get(2).(map[string]interface{})["edition"].(map[string]interface{})["dto"].(map[string]interface{})
+				assert.Equal(t, 77, dto["publisher_id"])
+			}).Return(assert.AnError).Once()
+
+		input := &edition.EditionInput{BookID: 1, Title: "Book", AuthorIDs: []int{100}, PublisherName: "Tiny Press"}
+		_, err := creator.CreateEdition(context.Background(), input)
+		require.Error(t, err)
+		assert.Equal(t, 77, input.PublisherID)
+		mockClient.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,127 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readLines(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines
+}
+
+func TestSetup_FileSink(t *testing.T) {
+	ResetForTesting()
+	t.Cleanup(ResetForTesting)
+
+	path := filepath.Join(t.TempDir(), "logs", "sync.log")
+	var stdout bytes.Buffer
+	ForceSetup(Config{
+		Level:      "info",
+		Format:     FormatConsole,
+		Output:     &stdout,
+		TimeFormat: time.RFC3339,
+		File:       path,
+	})
+	t.Cleanup(func() { _ = fileSink.Close() })
+
+	Get().Info("Synced book", map[string]interface{}{"book_id": "abc"})
+
+	// The file holds one JSON object per line, even though stdout uses the console format
+	lines := readLines(t, path)
+	require.NotEmpty(t, lines)
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[len(lines)-1]), &entry))
+	assert.Equal(t, "Synced book", entry["message"])
+	assert.Equal(t, "abc", entry["book_id"])
+	assert.Contains(t, stdout.String(), "Synced book")
+}
+
+func TestSetup_FileSinkWithoutStdout(t *testing.T) {
+	ResetForTesting()
+	t.Cleanup(ResetForTesting)
+
+	path := filepath.Join(t.TempDir(), "sync.log")
+	var stdout bytes.Buffer
+	ForceSetup(Config{
+		Level:         "info",
+		Format:        FormatJSON,
+		Output:        &stdout,
+		File:          path,
+		DisableOutput: true,
+	})
+	t.Cleanup(func() { _ = fileSink.Close() })
+
+	Get().Info("Only in the file", nil)
+
+	assert.Empty(t, stdout.String())
+	assert.Contains(t, strings.Join(readLines(t, path), "\n"), "Only in the file")
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sync.log")
+	file, err := NewRotatingFile(path, 100, 2)
+	require.NoError(t, err)
+	defer file.Close()
+
+	line := []byte(strings.Repeat("x", 39) + "\n") // 40 bytes
+
+	// Two lines fit, the third triggers a rotation
+	for i := 0; i < 2; i++ {
+		_, err := file.Write(line)
+		require.NoError(t, err)
+	}
+	assert.NoFileExists(t, path+".1")
+
+	_, err = file.Write(line)
+	require.NoError(t, err)
+	assert.FileExists(t, path+".1")
+	assert.Len(t, readLines(t, path+".1"), 2)
+	assert.Len(t, readLines(t, path), 1)
+
+	// Further rotations shift backups and drop the oldest beyond the limit
+	for i := 0; i < 6; i++ {
+		_, err := file.Write(line)
+		require.NoError(t, err)
+	}
+	assert.FileExists(t, path+".2")
+	assert.NoFileExists(t, path+".3")
+
+	for _, p := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(p)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, info.Size(), int64(100))
+	}
+}
+
+func TestRotatingFile_AppendsToExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sync.log")
+	require.NoError(t, os.WriteFile(path, []byte(strings.Repeat("x", 90)+"\n"), 0644))
+
+	file, err := NewRotatingFile(path, 100, 1)
+	require.NoError(t, err)
+	defer file.Close()
+
+	// The existing size counts towards the limit
+	_, err = file.Write([]byte("next line\n"))
+	require.NoError(t, err)
+	assert.FileExists(t, path+".1")
+	assert.Equal(t, []string{"next line"}, readLines(t, path))
+}
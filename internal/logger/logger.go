@@ -2,6 +2,7 @@ package logger
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"net/http"
 	"os"
@@ -26,6 +27,9 @@ var (
 	// once ensures the global logger is only initialized once
 	once sync.Once
 
+	// fileSink is the log file the global logger writes to, if any
+	fileSink *RotatingFile
+
 	// defaultConfig is the default logger configuration
 	// Use console format as default to match user expectations
 	defaultConfig = Config{
@@ -93,6 +97,14 @@ type Config struct {
 	Output io.Writer
 	// TimeFormat is the time format (default: time.RFC3339)
 	TimeFormat string
+	// File is the path of a file logs are also written to as NDJSON (empty = no file)
+	File string
+	// FileMaxSize is the size in bytes at which the log file is rotated (0 = never rotate)
+	FileMaxSize int64
+	// FileMaxBackups is the number of rotated log files kept
+	FileMaxBackups int
+	// DisableOutput stops logging to Output, leaving only the log file (ignored without a File)
+	DisableOutput bool
 }
 
 // HTTPMiddleware is a middleware that logs HTTP requests
@@ -213,20 +225,37 @@ func setupLogger(cfg Config) {
 		output = os.Stdout
 	}
 
-	// Create the base logger with the specified level
-	var logger zerolog.Logger
-
-	// Configure the logger based on the format
-	switch cfg.Format {
-	case FormatConsole:
-		logger = zerolog.New(zerolog.ConsoleWriter{
+	// Configure the output based on the format
+	var writer io.Writer = output
+	if cfg.Format == FormatConsole {
+		writer = zerolog.ConsoleWriter{
 			Out:        output,
 			TimeFormat: cfg.TimeFormat,
-		})
-	default: // Default to JSON
-		logger = zerolog.New(output)
+		}
+	}
+
+	// Also write NDJSON to the log file, if configured
+	if fileSink != nil {
+		_ = fileSink.Close()
+		fileSink = nil
+	}
+	if cfg.File != "" {
+		file, err := NewRotatingFile(cfg.File, cfg.FileMaxSize, cfg.FileMaxBackups)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to open log file %s, logging to stdout only: %v\n", cfg.File, err)
+		} else {
+			fileSink = file
+			if cfg.DisableOutput {
+				writer = file
+			} else {
+				writer = zerolog.MultiLevelWriter(writer, file)
+			}
+		}
 	}
 
+	// Create the base logger with the specified level
+	logger := zerolog.New(writer)
+
 	// Configure the logger with the specified level and timestamp
 	logger = logger.Level(level).With().Timestamp().Logger()
 
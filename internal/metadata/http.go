@@ -0,0 +1,108 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+)
+
+// isbnPlaceholder is replaced with the ISBN in the URL of an HTTPProvider
+const isbnPlaceholder = "{isbn}"
+
+// maxResponseSize caps the response body read from the provider
+const maxResponseSize = 1 << 20
+
+// HTTPProvider looks ASINs up at a user-provided URL returning {"asin": "..."} for an ISBN
+type HTTPProvider struct {
+	url        string
+	httpClient *http.Client
+	logger     *logger.Logger
+}
+
+// NewHTTPProvider creates a provider for the URL, in which "{isbn}" is replaced with the ISBN. The
+// ISBN is added as the isbn query parameter to URLs without it.
+func NewHTTPProvider(providerURL string, log *logger.Logger) *HTTPProvider {
+	if log == nil {
+		log = logger.Get()
+	}
+	return &HTTPProvider{
+		url: providerURL,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		logger: log,
+	}
+}
+
+// ASINForISBN returns the ASIN the provider returns for the ISBN. Unknown ISBNs, answered with a
+// 404 or an empty ASIN, return "".
+func (p *HTTPProvider) ASINForISBN(ctx context.Context, isbn string) (string, error) {
+	isbn = strings.TrimSpace(isbn)
+	if isbn == "" {
+		return "", fmt.Errorf("ISBN is required")
+	}
+
+	lookupURL, err := p.lookupURL(isbn)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, lookupURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create ISBN to ASIN request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("ISBN to ASIN request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		p.logger.Debug("ISBN unknown to the ISBN to ASIN provider", map[string]interface{}{
+			"isbn": isbn,
+		})
+		return "", nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("ISBN to ASIN request returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		ASIN string `json:"asin"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode ISBN to ASIN response: %w", err)
+	}
+
+	asin := strings.ToUpper(strings.TrimSpace(result.ASIN))
+	p.logger.Debug("Looked up ASIN for ISBN", map[string]interface{}{
+		"isbn": isbn,
+		"asin": asin,
+	})
+	return asin, nil
+}
+
+// lookupURL returns the provider URL for the ISBN
+func (p *HTTPProvider) lookupURL(isbn string) (string, error) {
+	if strings.Contains(p.url, isbnPlaceholder) {
+		return strings.ReplaceAll(p.url, isbnPlaceholder, url.PathEscape(isbn)), nil
+	}
+
+	u, err := url.Parse(p.url)
+	if err != nil {
+		return "", fmt.Errorf("invalid ISBN to ASIN URL: %w", err)
+	}
+	query := u.Query()
+	query.Set("isbn", isbn)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
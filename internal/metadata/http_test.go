@@ -0,0 +1,70 @@
+package metadata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPProvider_ASINForISBN(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		isbn := r.URL.Query().Get("isbn")
+		if isbn == "" {
+			isbn = r.URL.Path[len("/isbn/"):]
+		}
+
+		switch isbn {
+		case "9781234567897":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"asin": " b0known001 "}`))
+		case "9780000000002":
+			_, _ = w.Write([]byte(`{"asin": ""}`))
+		case "9780000000019":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		url     string
+		isbn    string
+		want    string
+		wantErr bool
+	}{
+		{name: "ISBN placeholder", url: server.URL + "/isbn/{isbn}", isbn: "9781234567897", want: "B0KNOWN001"},
+		{name: "ISBN query parameter", url: server.URL + "/lookup?format=json", isbn: "9781234567897", want: "B0KNOWN001"},
+		{name: "unknown ISBN", url: server.URL + "/lookup", isbn: "9789999999991"},
+		{name: "empty ASIN", url: server.URL + "/lookup", isbn: "9780000000002"},
+		{name: "server error", url: server.URL + "/lookup", isbn: "9780000000019", wantErr: true},
+		{name: "empty ISBN", url: server.URL + "/lookup", isbn: " ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			asin, err := NewHTTPProvider(tt.url, nil).ASINForISBN(context.Background(), tt.isbn)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, asin)
+		})
+	}
+}
+
+func TestHTTPProvider_LookupURL(t *testing.T) {
+	url, err := NewHTTPProvider("https://example.com/lookup?format=json", nil).lookupURL("9781234567897")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/lookup?format=json&isbn=9781234567897", url)
+
+	url, err = NewHTTPProvider("https://example.com/isbn/{isbn}.json", nil).lookupURL("9781234567897")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/isbn/9781234567897.json", url)
+}
@@ -0,0 +1,12 @@
+// Package metadata looks up book metadata Hardcover and Audiobookshelf don't share in external
+// providers, such as the ASIN of a book only known by its ISBN.
+package metadata
+
+import "context"
+
+// Provider looks up the ASIN of a book by its ISBN
+type Provider interface {
+	// ASINForISBN returns the ASIN of the book with the ISBN, or "" when the provider doesn't know
+	// the book
+	ASINForISBN(ctx context.Context, isbn string) (string, error)
+}
@@ -0,0 +1,132 @@
+// Package metrics exposes Prometheus metrics of sync runs and Hardcover API calls. The metrics are
+// kept in a registry of their own and served by Handler, so they're available as soon as the HTTP
+// server runs without any setup.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes the name of every metric
+const namespace = "abs_hardcover_sync"
+
+// Result label values of Hardcover requests and sync runs
+const (
+	ResultSuccess = "success"
+	ResultError   = "error"
+)
+
+var (
+	registry = prometheus.NewRegistry()
+
+	booksProcessed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "books_processed_total",
+		Help:      "Books processed by sync runs, whether they were synced or skipped.",
+	}, []string{"user"})
+
+	booksMatched = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "books_matched_total",
+		Help:      "Books matched to a Hardcover book, by how they were matched (asin, isbn, title, ...).",
+	}, []string{"user", "source"})
+
+	mismatchesRecorded = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "mismatches_recorded_total",
+		Help:      "Mismatches recorded by sync runs.",
+	}, []string{"user"})
+
+	progressUpdates = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "progress_updates_total",
+		Help:      "Reading progress updates sent to Hardcover, by the kind of update (insert or update).",
+	}, []string{"kind"})
+
+	hardcoverRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "hardcover_requests_total",
+		Help:      "GraphQL operations sent to the Hardcover API, by whether they succeeded after any retries.",
+	}, []string{"operation", "result"})
+
+	hardcoverRateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "hardcover_rate_limited_total",
+		Help:      "HTTP 429 responses of the Hardcover API, by GraphQL operation.",
+	}, []string{"operation"})
+
+	syncDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "sync_duration_seconds",
+		Help:      "Duration of sync runs.",
+		Buckets:   []float64{1, 5, 15, 30, 60, 120, 300, 600, 1200, 1800, 3600},
+	}, []string{"user", "result"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		booksProcessed,
+		booksMatched,
+		mismatchesRecorded,
+		progressUpdates,
+		hardcoverRequests,
+		hardcoverRateLimited,
+		syncDuration,
+	)
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// BookProcessed counts a book processed by a sync run of user, which is empty in single-user mode
+func BookProcessed(user string) {
+	booksProcessed.WithLabelValues(user).Inc()
+}
+
+// BookMatched counts a book of user matched by source, one of the state.MatchSource constants
+func BookMatched(user, source string) {
+	booksMatched.WithLabelValues(user, source).Inc()
+}
+
+// MismatchesRecorded counts the mismatches recorded by a sync run of user
+func MismatchesRecorded(user string, count int) {
+	mismatchesRecorded.WithLabelValues(user).Add(float64(count))
+}
+
+// ProgressUpdateSent counts a reading progress update sent to Hardcover, where kind tells whether
+// a read was inserted or updated
+func ProgressUpdateSent(kind string) {
+	progressUpdates.WithLabelValues(kind).Inc()
+}
+
+// HardcoverRequest counts a GraphQL operation sent to Hardcover, failed when err is not nil
+func HardcoverRequest(operation string, err error) {
+	hardcoverRequests.WithLabelValues(operation, result(err)).Inc()
+}
+
+// HardcoverRateLimited counts an HTTP 429 response of Hardcover to a GraphQL operation
+func HardcoverRateLimited(operation string) {
+	hardcoverRateLimited.WithLabelValues(operation).Inc()
+}
+
+// ObserveSync records the duration of a sync run of user that started at startedAt and ended
+// with err
+func ObserveSync(user string, startedAt time.Time, err error) {
+	syncDuration.WithLabelValues(user, result(err)).Observe(time.Since(startedAt).Seconds())
+}
+
+func result(err error) string {
+	if err != nil {
+		return ResultError
+	}
+	return ResultSuccess
+}
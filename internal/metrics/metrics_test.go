@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCounters(t *testing.T) {
+	BookProcessed("user-a")
+	BookProcessed("user-a")
+	BookProcessed("user-b")
+	assert.Equal(t, 2.0, testutil.ToFloat64(booksProcessed.WithLabelValues("user-a")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(booksProcessed.WithLabelValues("user-b")))
+
+	BookMatched("user-a", "asin")
+	assert.Equal(t, 1.0, testutil.ToFloat64(booksMatched.WithLabelValues("user-a", "asin")))
+	assert.Zero(t, testutil.ToFloat64(booksMatched.WithLabelValues("user-a", "isbn")))
+
+	MismatchesRecorded("user-a", 3)
+	assert.Equal(t, 3.0, testutil.ToFloat64(mismatchesRecorded.WithLabelValues("user-a")))
+
+	HardcoverRequest("GetBook", nil)
+	HardcoverRequest("GetBook", errors.New("boom"))
+	assert.Equal(t, 1.0, testutil.ToFloat64(hardcoverRequests.WithLabelValues("GetBook", ResultSuccess)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(hardcoverRequests.WithLabelValues("GetBook", ResultError)))
+
+	HardcoverRateLimited("GetBook")
+	assert.Equal(t, 1.0, testutil.ToFloat64(hardcoverRateLimited.WithLabelValues("GetBook")))
+}
+
+func TestHandler(t *testing.T) {
+	ProgressUpdateSent("insert")
+	ObserveSync("", time.Now().Add(-2*time.Second), nil)
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, 200, rec.Code)
+	assert.Contains(t, string(body), `abs_hardcover_sync_progress_updates_total{kind="insert"} 1`)
+	assert.Contains(t, string(body), `abs_hardcover_sync_sync_duration_seconds_count{result="success",user=""} 1`)
+	assert.Contains(t, string(body), "go_goroutines")
+}
@@ -0,0 +1,70 @@
+package mismatch
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// CSVFileName is the name of the file in the mismatch output directory that all mismatches are
+// saved to when Paths.MismatchFormats contains "csv"
+const CSVFileName = "mismatches.csv"
+
+// csvHeader lists the columns of the CSV file
+var csvHeader = []string{
+	"title", "author", "isbn", "asin", "reason", "book_id", "edition_id", "abs_item_id", "duration",
+}
+
+// SaveCSV writes the mismatches to path as CSV with a header row, replacing the file of the
+// previous run. Fields containing commas, quotes or line breaks are quoted.
+func SaveCSV(mismatches []BookMismatch, path string) error {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(csvHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	for _, m := range mismatches {
+		if err := w.Write(m.csvRecord()); err != nil {
+			return fmt.Errorf("failed to write CSV row for '%s': %w", m.Title, err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for CSV file: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write CSV file '%s': %w", path, err)
+	}
+	return nil
+}
+
+// csvRecord returns the CSV row of the mismatch in the order of csvHeader
+func (b *BookMismatch) csvRecord() []string {
+	isbn := b.ISBN13
+	if isbn == "" {
+		isbn = b.ISBN10
+	}
+	if isbn == "" {
+		isbn = b.ISBN
+	}
+
+	// The book ID falls back to the item ID when no Hardcover book is known, which isn't repeated
+	bookID := b.BookID
+	if bookID == b.ItemID {
+		bookID = ""
+	}
+
+	duration := ""
+	if b.DurationSeconds > 0 {
+		duration = strconv.Itoa(b.DurationSeconds)
+	}
+
+	return []string{b.Title, b.Author, isbn, b.ASIN, b.Reason, bookID, b.EditionID, b.ItemID, duration}
+}
@@ -0,0 +1,62 @@
+package mismatch
+
+import (
+	"context"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveToFile_CSV(t *testing.T) {
+	Clear()
+	t.Cleanup(Clear)
+
+	Add(BookMismatch{
+		BookID:          "123",
+		EditionID:       "456",
+		Title:           "Dune, Part One",
+		Author:          "Frank Herbert",
+		ISBN10:          "0441172717",
+		ISBN13:          "9780441172719",
+		ASIN:            "B002V1OF70",
+		Reason:          "book exists but has no \"audio\" edition",
+		ItemID:          "li_1",
+		DurationSeconds: 75600,
+	})
+	Add(BookMismatch{BookID: "li_2", Title: "Missing Book", Reason: "could not find book in Hardcover", ItemID: "li_2"})
+
+	dir := t.TempDir()
+	cfg := newTestConfig(dir)
+	cfg.Paths.MismatchFormats = []string{config.MismatchFormatCSV}
+	require.NoError(t, SaveToFile(context.Background(), nil, dir, cfg))
+
+	// Only the CSV file is written
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, CSVFileName, files[0].Name())
+
+	f, err := os.Open(filepath.Join(dir, CSVFileName))
+	require.NoError(t, err)
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	require.NoError(t, err)
+
+	require.Len(t, records, 3)
+	assert.Equal(t, []string{"title", "author", "isbn", "asin", "reason", "book_id", "edition_id", "abs_item_id", "duration"}, records[0])
+	assert.Equal(t, []string{
+		"Dune, Part One", "Frank Herbert", "9780441172719", "B002V1OF70",
+		"book exists but has no \"audio\" edition", "123", "456", "li_1", "75600",
+	}, records[1])
+	assert.Equal(t, []string{"Missing Book", "", "", "", "could not find book in Hardcover", "", "", "li_2", ""}, records[2])
+
+	// The CSV file of the previous run is removed with the JSON files
+	Clear()
+	require.NoError(t, SaveToFile(context.Background(), nil, dir, cfg))
+	assert.NoFileExists(t, filepath.Join(dir, CSVFileName))
+}
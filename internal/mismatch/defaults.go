@@ -0,0 +1,63 @@
+package mismatch
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+)
+
+var (
+	editionDefaults     config.EditionDefaults
+	publisherSource     = config.PublisherSourcePublisher
+	editionDefaultsLock sync.RWMutex
+)
+
+// SetEditionDefaults sets the language, country and publisher IDs used for mismatches whose
+// Audiobookshelf metadata doesn't provide them. Zero values keep the built-in fallbacks.
+func SetEditionDefaults(defaults config.EditionDefaults) {
+	editionDefaultsLock.Lock()
+	defer editionDefaultsLock.Unlock()
+	editionDefaults = defaults
+}
+
+// getEditionDefaults returns the configured edition defaults
+func getEditionDefaults() config.EditionDefaults {
+	editionDefaultsLock.RLock()
+	defer editionDefaultsLock.RUnlock()
+	return editionDefaults
+}
+
+// defaultID returns the configured ID, or fallback when none is configured
+func defaultID(configured, fallback int) int {
+	if configured > 0 {
+		return configured
+	}
+	return fallback
+}
+
+// SetPublisherSource sets which metadata field the publisher name of new mismatches is taken from,
+// one of the config.PublisherSource* values
+func SetPublisherSource(source string) {
+	editionDefaultsLock.Lock()
+	defer editionDefaultsLock.Unlock()
+	publisherSource = source
+}
+
+// selectPublisherName returns the publisher name to use for a mismatch according to the
+// configured publisher source
+func selectPublisherName(absPublisher, audnexPublisher string) string {
+	editionDefaultsLock.RLock()
+	source := publisherSource
+	editionDefaultsLock.RUnlock()
+
+	switch source {
+	case config.PublisherSourceNone:
+		return ""
+	case config.PublisherSourceAudnex:
+		if name := strings.TrimSpace(audnexPublisher); name != "" {
+			return name
+		}
+	}
+	return strings.TrimSpace(absPublisher)
+}
@@ -0,0 +1,84 @@
+package mismatch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToEditionExport_EditionDefaults(t *testing.T) {
+	SetEditionDefaults(config.EditionDefaults{LanguageID: 4, CountryID: 9, PublisherID: 42})
+	t.Cleanup(func() { SetEditionDefaults(config.EditionDefaults{}) })
+
+	t.Run("metadata without language, country or publisher", func(t *testing.T) {
+		book := BookMismatch{Title: "Test Book", HardcoverBookID: "123"}
+		export := book.ToEditionExport(context.Background(), nil)
+
+		assert.Equal(t, 4, export.LanguageID)
+		assert.Equal(t, 9, export.CountryID)
+		assert.Equal(t, 42, export.PublisherID)
+	})
+
+	t.Run("metadata values take precedence", func(t *testing.T) {
+		book := BookMismatch{Title: "Test Book", HardcoverBookID: "123", LanguageID: 2, CountryID: 3, PublisherID: 5}
+		export := book.ToEditionExport(context.Background(), nil)
+
+		assert.Equal(t, 2, export.LanguageID)
+		assert.Equal(t, 3, export.CountryID)
+		assert.Equal(t, 5, export.PublisherID)
+	})
+
+	t.Run("publisher named in metadata is not replaced", func(t *testing.T) {
+		book := BookMismatch{Title: "Test Book", HardcoverBookID: "123", Publisher: "Unknown Press"}
+		export := book.ToEditionExport(context.Background(), nil)
+
+		assert.Zero(t, export.PublisherID)
+	})
+
+	t.Run("built-in fallbacks without configured defaults", func(t *testing.T) {
+		SetEditionDefaults(config.EditionDefaults{})
+		book := BookMismatch{Title: "Test Book", HardcoverBookID: "123"}
+		export := book.ToEditionExport(context.Background(), nil)
+
+		assert.Equal(t, 1, export.LanguageID)
+		assert.Equal(t, 1, export.CountryID)
+		assert.Zero(t, export.PublisherID)
+	})
+}
+
+func TestToEditionExport_UnresolvedPublisherName(t *testing.T) {
+	// The mock client doesn't find any publisher, so the name is exported for the edition tool
+	book := BookMismatch{Title: "Test Book", HardcoverBookID: "123", Publisher: "Unknown Press"}
+	export := book.ToEditionExport(context.Background(), &MockHardcoverClient{})
+
+	assert.Zero(t, export.PublisherID)
+	assert.Equal(t, "Unknown Press", export.PublisherName)
+
+	resolved := BookMismatch{Title: "Test Book", HardcoverBookID: "123", Publisher: "Known Press", PublisherID: 5}
+	export = resolved.ToEditionExport(context.Background(), nil)
+
+	assert.Equal(t, 5, export.PublisherID)
+	assert.Empty(t, export.PublisherName)
+}
+
+func TestSelectPublisherName(t *testing.T) {
+	t.Cleanup(func() { SetPublisherSource(config.PublisherSourcePublisher) })
+
+	tests := []struct {
+		source   string
+		abs      string
+		audnex   string
+		expected string
+	}{
+		{config.PublisherSourcePublisher, "ABS Press", "Audnex Press", "ABS Press"},
+		{config.PublisherSourceAudnex, "ABS Press", "Audnex Press", "Audnex Press"},
+		{config.PublisherSourceAudnex, "ABS Press", "", "ABS Press"},
+		{config.PublisherSourceNone, "ABS Press", "Audnex Press", ""},
+	}
+	for _, tt := range tests {
+		SetPublisherSource(tt.source)
+		assert.Equal(t, tt.expected, selectPublisherName(tt.abs, tt.audnex), tt.source)
+	}
+}
@@ -0,0 +1,105 @@
+package mismatch
+
+import (
+	"strings"
+	"sync"
+)
+
+// Reason codes classifying why a book was recorded as a mismatch
+const (
+	ReasonNotFound           = "NOT_FOUND"
+	ReasonMissingMetadata    = "MISSING_METADATA"
+	ReasonNoEdition          = "NO_EDITION"
+	ReasonNoEditions         = "NO_EDITIONS"
+	ReasonTitleAuthorOnly    = "TITLE_AUTHOR_ONLY"
+	ReasonWeakTitleMatch     = "WEAK_TITLE_MATCH"
+	ReasonAuthorMismatch     = "AUTHOR_MISMATCH"
+	ReasonIdentifierMismatch = "IDENTIFIER_MISMATCH"
+	ReasonNarratorMismatch   = "NARRATOR_MISMATCH"
+	ReasonDurationMismatch   = "DURATION_MISMATCH"
+	ReasonProgressJump       = "PROGRESS_JUMP"
+)
+
+// reasonPatterns maps the lowercase text of mismatch reasons to their codes. The more specific
+// reasons come first, as they can be wrapped in a generic "error finding book" one.
+var reasonPatterns = []struct {
+	pattern string
+	code    string
+}{
+	{"identifier mismatch", ReasonIdentifierMismatch},
+	{"narrator mismatch", ReasonNarratorMismatch},
+	{"progress exceeds duration", ReasonDurationMismatch},
+	{"suspicious progress jump", ReasonProgressJump},
+	{"above the similarity threshold", ReasonWeakTitleMatch},
+	{"match by the book's author", ReasonAuthorMismatch},
+	{"found by title/author only", ReasonTitleAuthorOnly},
+	{"has no editions", ReasonNoEditions},
+	{"no edition id", ReasonNoEdition},
+	{"in another reading format", ReasonNoEdition},
+	{"title is empty", ReasonMissingMetadata},
+	{"could not find book", ReasonNotFound},
+	{"error finding book", ReasonNotFound},
+	{"not found", ReasonNotFound},
+}
+
+// fixSuggestions says how to fix the mismatches of each reason code
+var fixSuggestions = map[string]string{
+	ReasonNotFound:           "add the ASIN or ISBN to the book in Audiobookshelf, or create the edition in Hardcover",
+	ReasonMissingMetadata:    "add the title and author to the book in Audiobookshelf",
+	ReasonNoEdition:          "create the audiobook edition in Hardcover, or enable sync.allow_book_level_tracking",
+	ReasonNoEditions:         "add an edition to the book in Hardcover, e.g. with the edition tool, or enable sync.allow_book_level_tracking",
+	ReasonTitleAuthorOnly:    "add the ASIN or ISBN to the book in Audiobookshelf, or pin it in sync.book_overrides",
+	ReasonWeakTitleMatch:     "check the title in Audiobookshelf, or pin the book in sync.book_overrides",
+	ReasonAuthorMismatch:     "check the author in Audiobookshelf, or pin the book in sync.book_overrides",
+	ReasonIdentifierMismatch: "fix the ASIN or ISBN in Audiobookshelf or on the Hardcover edition",
+	ReasonNarratorMismatch:   "verify the correct edition, the ASIN in Audiobookshelf may belong to another narration",
+	ReasonDurationMismatch:   "verify the correct edition, the Audiobookshelf duration doesn't match the listening progress",
+	ReasonProgressJump:       "check the listening progress in Audiobookshelf before syncing it again",
+}
+
+var (
+	fixSuggestionsEnabled bool
+	fixSuggestionsLock    sync.RWMutex
+)
+
+// SetFixSuggestions sets whether new mismatches get a reason code and a suggestion on how to fix
+// them
+func SetFixSuggestions(enabled bool) {
+	fixSuggestionsLock.Lock()
+	defer fixSuggestionsLock.Unlock()
+	fixSuggestionsEnabled = enabled
+}
+
+// ReasonCodeFor classifies a mismatch reason, returning "" for reasons it doesn't know
+func ReasonCodeFor(reason string) string {
+	reason = strings.ToLower(reason)
+	for _, p := range reasonPatterns {
+		if strings.Contains(reason, p.pattern) {
+			return p.code
+		}
+	}
+	return ""
+}
+
+// FixSuggestion returns how to fix the mismatches of a reason code, or "" for unknown codes
+func FixSuggestion(code string) string {
+	return fixSuggestions[code]
+}
+
+// applyFixSuggestion sets the reason code and fix suggestion of a mismatch when fix suggestions
+// are enabled, keeping any already set
+func applyFixSuggestion(book *BookMismatch) {
+	fixSuggestionsLock.RLock()
+	enabled := fixSuggestionsEnabled
+	fixSuggestionsLock.RUnlock()
+	if !enabled {
+		return
+	}
+
+	if book.ReasonCode == "" {
+		book.ReasonCode = ReasonCodeFor(book.Reason)
+	}
+	if book.FixSuggestion == "" {
+		book.FixSuggestion = FixSuggestion(book.ReasonCode)
+	}
+}
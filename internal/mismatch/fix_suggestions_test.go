@@ -0,0 +1,93 @@
+package mismatch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReasonCodeFor(t *testing.T) {
+	tests := []struct {
+		reason string
+		code   string
+	}{
+		{"could not find book in Hardcover", ReasonNotFound},
+		{"error finding book in Hardcover: no edition found", ReasonNotFound},
+		{"book title is empty, cannot search by title/author", ReasonMissingMetadata},
+		{"book found by title/author search but no edition ID available", ReasonNoEdition},
+		{"no edition ID or book ID available", ReasonNoEdition},
+		{"ASIN only matches an edition in another reading format", ReasonNoEdition},
+		{"book exists but has no editions", ReasonNoEditions},
+		{"Found by title/author only - manual verification required", ReasonTitleAuthorOnly},
+		{`No title/author match above the similarity threshold of 0.75: best result "Habits" (ID: 1) scored 0.33`, ReasonWeakTitleMatch},
+		{`No title/author match by the book's author "Cormac McCarthy": rejected "The Road" (ID: 2) by Jack London`, ReasonAuthorMismatch},
+		{"Identifier mismatch on matched edition: edition 100 has ASIN B0OTHER, expected B0BOOK", ReasonIdentifierMismatch},
+		{"error finding book in Hardcover: identifier mismatch on matched edition: edition 100 has no ASIN", ReasonIdentifierMismatch},
+		{`Narrator mismatch: Audiobookshelf narrator "A", Hardcover edition 100 narrator "B"`, ReasonNarratorMismatch},
+		{"Progress exceeds duration: Audiobookshelf progress 12h0m0s is 120% of the duration 10h0m0s", ReasonDurationMismatch},
+		{"Suspicious progress jump: Audiobookshelf progress 9h0m0s, Hardcover progress 1h0m0s (difference 8h0m0s exceeds cap of 3600s)", ReasonProgressJump},
+		{"test reason", ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.code, ReasonCodeFor(tt.reason), tt.reason)
+	}
+}
+
+func TestFixSuggestion(t *testing.T) {
+	tests := map[string]string{
+		ReasonNotFound:           "add the ASIN or ISBN to the book in Audiobookshelf, or create the edition in Hardcover",
+		ReasonMissingMetadata:    "add the title and author to the book in Audiobookshelf",
+		ReasonNoEdition:          "create the audiobook edition in Hardcover, or enable sync.allow_book_level_tracking",
+		ReasonNoEditions:         "add an edition to the book in Hardcover, e.g. with the edition tool, or enable sync.allow_book_level_tracking",
+		ReasonTitleAuthorOnly:    "add the ASIN or ISBN to the book in Audiobookshelf, or pin it in sync.book_overrides",
+		ReasonWeakTitleMatch:     "check the title in Audiobookshelf, or pin the book in sync.book_overrides",
+		ReasonAuthorMismatch:     "check the author in Audiobookshelf, or pin the book in sync.book_overrides",
+		ReasonIdentifierMismatch: "fix the ASIN or ISBN in Audiobookshelf or on the Hardcover edition",
+		ReasonNarratorMismatch:   "verify the correct edition, the ASIN in Audiobookshelf may belong to another narration",
+		ReasonDurationMismatch:   "verify the correct edition, the Audiobookshelf duration doesn't match the listening progress",
+		ReasonProgressJump:       "check the listening progress in Audiobookshelf before syncing it again",
+		"":                       "",
+		"UNKNOWN":                "",
+	}
+
+	for code, suggestion := range tests {
+		assert.Equal(t, suggestion, FixSuggestion(code), code)
+	}
+	// Every reason code has a suggestion
+	for _, p := range reasonPatterns {
+		assert.NotEmpty(t, FixSuggestion(p.code), p.code)
+	}
+}
+
+func TestAddWithMetadata_FixSuggestions(t *testing.T) {
+	defer SetFixSuggestions(false)
+	defer Clear()
+
+	t.Run("enabled", func(t *testing.T) {
+		Clear()
+		SetFixSuggestions(true)
+
+		AddWithMetadata(MediaMetadata{Title: "Missing Book"}, "", "", "could not find book in Hardcover", 3600, "abs-1", nil)
+
+		mismatches := GetAll()
+		require.Len(t, mismatches, 1)
+		assert.Equal(t, ReasonNotFound, mismatches[0].ReasonCode)
+		assert.Equal(t, FixSuggestion(ReasonNotFound), mismatches[0].FixSuggestion)
+		assert.Equal(t, FixSuggestion(ReasonNotFound), mismatches[0].ToEditionExport(context.Background(), nil).Info.FixSuggestion)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		Clear()
+		SetFixSuggestions(false)
+
+		AddWithMetadata(MediaMetadata{Title: "Missing Book"}, "", "", "could not find book in Hardcover", 3600, "abs-1", nil)
+
+		mismatches := GetAll()
+		require.Len(t, mismatches, 1)
+		assert.Empty(t, mismatches[0].ReasonCode)
+		assert.Empty(t, mismatches[0].FixSuggestion)
+	})
+}
@@ -0,0 +1,109 @@
+package mismatch
+
+import (
+	"sync"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+)
+
+const (
+	// mismatchSummaryInterval is how often the mismatches not logged in detail are summarized
+	mismatchSummaryInterval = time.Minute
+	// otherReasonKey counts the mismatches of reasons without a reason code
+	otherReasonKey = "OTHER"
+)
+
+var (
+	logLimit     int
+	loggedByCode = make(map[string]int)
+	suppressedBy = make(map[string]int)
+	lastSummary  time.Time
+	logLimitLock sync.Mutex
+)
+
+// SetLogLimit sets how many mismatches per reason code are logged in detail, 0 logging all of
+// them. It resets the counts of the current run.
+func SetLogLimit(limit int) {
+	logLimitLock.Lock()
+	defer logLimitLock.Unlock()
+	logLimit = limit
+	resetLogCountsLocked()
+}
+
+// ShouldLogDetail reports whether a mismatch with this reason is still logged in detail, so
+// callers logging it themselves before it's recorded stay within the limit
+func ShouldLogDetail(reason string) bool {
+	logLimitLock.Lock()
+	defer logLimitLock.Unlock()
+	return logLimit <= 0 || loggedByCode[logReasonKey("", reason)] < logLimit
+}
+
+// LogSuppressedSummary logs how many mismatches per reason code weren't logged in detail since
+// the last summary, if any
+func LogSuppressedSummary() {
+	logLimitLock.Lock()
+	defer logLimitLock.Unlock()
+	logSuppressedSummaryLocked()
+}
+
+// countLogged counts a recorded mismatch, reporting whether it's logged in detail. Mismatches
+// over the limit are summarized once mismatchSummaryInterval has passed since the last summary.
+func countLogged(book BookMismatch) bool {
+	logLimitLock.Lock()
+	defer logLimitLock.Unlock()
+
+	key := logReasonKey(book.ReasonCode, book.Reason)
+	if logLimit <= 0 || loggedByCode[key] < logLimit {
+		loggedByCode[key]++
+		return true
+	}
+
+	suppressedBy[key]++
+	if lastSummary.IsZero() {
+		lastSummary = time.Now()
+	} else if time.Since(lastSummary) >= mismatchSummaryInterval {
+		logSuppressedSummaryLocked()
+	}
+	return false
+}
+
+// logSuppressedSummaryLocked logs and resets the suppressed counts; logLimitLock must be held
+func logSuppressedSummaryLocked() {
+	if len(suppressedBy) == 0 {
+		return
+	}
+
+	total := 0
+	for _, count := range suppressedBy {
+		total += count
+	}
+	if log := logger.Get(); log != nil {
+		log.Warn("More mismatches recorded than logged, see the mismatch file for details", map[string]interface{}{
+			"suppressed": suppressedBy,
+			"total":      total,
+			"limit":      logLimit,
+		})
+	}
+
+	suppressedBy = make(map[string]int)
+	lastSummary = time.Now()
+}
+
+// resetLogCountsLocked starts counting from scratch; logLimitLock must be held
+func resetLogCountsLocked() {
+	loggedByCode = make(map[string]int)
+	suppressedBy = make(map[string]int)
+	lastSummary = time.Time{}
+}
+
+// logReasonKey is the reason code mismatches are counted under
+func logReasonKey(code, reason string) string {
+	if code == "" {
+		code = ReasonCodeFor(reason)
+	}
+	if code == "" {
+		return otherReasonKey
+	}
+	return code
+}
@@ -0,0 +1,80 @@
+package mismatch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureLogs sends the global logger's output to a buffer for the rest of the test
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	logger.ResetForTesting()
+	logger.Setup(logger.Config{Level: "info", Format: logger.FormatJSON, Output: &buf, TimeFormat: time.RFC3339})
+	t.Cleanup(logger.ResetForTesting)
+	return &buf
+}
+
+func TestAdd_LogLimit(t *testing.T) {
+	logs := captureLogs(t)
+	SetLogLimit(2)
+	t.Cleanup(func() { SetLogLimit(0) })
+	Clear()
+	t.Cleanup(Clear)
+
+	for i := 0; i < 5; i++ {
+		Add(BookMismatch{BookID: fmt.Sprintf("nf-%d", i), Title: fmt.Sprintf("Not Found %d", i), Reason: "could not find book in Hardcover"})
+	}
+	Add(BookMismatch{BookID: "ne-1", Title: "No Edition", Reason: "book exists but has no editions"})
+	Add(BookMismatch{BookID: "other-1", Title: "Other", Reason: "something unexpected"})
+
+	// Only the first two per reason code are logged in detail, but all are recorded
+	assert.Equal(t, 4, strings.Count(logs.String(), "Mismatch recorded"))
+	assert.Len(t, GetAll(), 7)
+	assert.False(t, ShouldLogDetail("could not find book in Hardcover"))
+	assert.True(t, ShouldLogDetail("book exists but has no editions"))
+
+	// The rest are summarized, once
+	LogSuppressedSummary()
+	assert.Contains(t, logs.String(), `"suppressed":{"NOT_FOUND":3}`)
+	assert.Contains(t, logs.String(), `"total":3`)
+	LogSuppressedSummary()
+	assert.Equal(t, 1, strings.Count(logs.String(), "More mismatches recorded than logged"))
+
+	// Every mismatch still gets its file
+	dir := t.TempDir()
+	require.NoError(t, SaveToFile(context.Background(), hardcover.NewClient("test-token", logger.Get()), dir, newTestConfig(dir)))
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 7)
+
+	// A new run logs in detail again
+	Clear()
+	assert.True(t, ShouldLogDetail("could not find book in Hardcover"))
+}
+
+func TestAdd_NoLogLimit(t *testing.T) {
+	logs := captureLogs(t)
+	SetLogLimit(0)
+	Clear()
+	t.Cleanup(Clear)
+
+	for i := 0; i < 5; i++ {
+		Add(BookMismatch{BookID: fmt.Sprintf("nf-%d", i), Title: fmt.Sprintf("Not Found %d", i), Reason: "could not find book in Hardcover"})
+	}
+	LogSuppressedSummary()
+
+	assert.Equal(t, 5, strings.Count(logs.String(), "Mismatch recorded"))
+	assert.NotContains(t, logs.String(), "More mismatches recorded than logged")
+	assert.True(t, ShouldLogDetail("could not find book in Hardcover"))
+}
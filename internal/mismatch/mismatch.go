@@ -23,7 +23,9 @@ var (
 	mismatchLock sync.Mutex
 )
 
-// Add adds a new book mismatch to the collection
+// Add adds a new book mismatch to the collection. A mismatch of an Audiobookshelf item that
+// already has one replaces it, unless its reason is less informative, so every item is reported
+// once however often it's processed.
 func Add(book BookMismatch) {
 	mismatchLock.Lock()
 	defer mismatchLock.Unlock()
@@ -38,12 +40,30 @@ func Add(book BookMismatch) {
 	if book.CreatedAt.IsZero() {
 		book.CreatedAt = time.Now()
 	}
+	applyFixSuggestion(&book)
 
-	mismatches = append(mismatches, book)
+	if i := indexOf(&book); i >= 0 {
+		existing := mismatches[i]
+		if reasonRank(book) < reasonRank(existing) {
+			existing.Attempts++
+			existing.Timestamp = book.Timestamp
+			mismatches[i] = existing
+			return
+		}
+		book.Attempts = existing.Attempts + 1
+		book.CreatedAt = existing.CreatedAt
+		mismatches[i] = book
+		if book.Reason == existing.Reason {
+			return
+		}
+	} else {
+		mismatches = append(mismatches, book)
+	}
+	streamMismatch(book)
 
-	// Log the mismatch
+	// Log the mismatch, unless more of its reason code than Logging.MismatchLogLimit were logged
 	log := logger.Get()
-	if log != nil {
+	if countLogged(book) && log != nil {
 		log.Info("Mismatch recorded", map[string]interface{}{
 			"title":  book.Title,
 			"reason": book.Reason,
@@ -51,6 +71,54 @@ func Add(book BookMismatch) {
 	}
 }
 
+// Restore adds mismatches of a previous run without logging or streaming them, skipping those of
+// items with a mismatch recorded this run. It returns the number of mismatches added.
+func Restore(books []BookMismatch) int {
+	mismatchLock.Lock()
+	defer mismatchLock.Unlock()
+
+	added := 0
+	for i := range books {
+		if indexOf(&books[i]) >= 0 {
+			continue
+		}
+		mismatches = append(mismatches, books[i])
+		added++
+	}
+	return added
+}
+
+// indexOf returns the index of the mismatch with the same ID as book, i.e. of the same
+// Audiobookshelf item, or -1 if there is none; mismatchLock must be held
+func indexOf(book *BookMismatch) int {
+	id := book.ID()
+	if id == "" {
+		return -1
+	}
+	for i := range mismatches {
+		if mismatches[i].ID() == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// reasonRank ranks how informative the reason of a mismatch is: a specific reason, like a book
+// without an audio edition, ranks above a book that wasn't found, which ranks above an unknown one
+func reasonRank(book BookMismatch) int {
+	code := book.ReasonCode
+	if code == "" {
+		code = ReasonCodeFor(book.Reason)
+	}
+	switch code {
+	case "":
+		return 0
+	case ReasonNotFound:
+		return 1
+	}
+	return 2
+}
+
 // RecordMismatch records a new book mismatch
 func RecordMismatch(book *BookMismatch) error {
 	mismatchLock.Lock()
@@ -63,6 +131,8 @@ func RecordMismatch(book *BookMismatch) error {
 			existing.Attempts++
 			existing.Timestamp = time.Now().Unix()
 			existing.Reason = book.Reason
+			existing.ReasonCode, existing.FixSuggestion = book.ReasonCode, book.FixSuggestion
+			applyFixSuggestion(&existing)
 			mismatches[i] = existing
 			return nil
 		}
@@ -72,13 +142,16 @@ func RecordMismatch(book *BookMismatch) error {
 	book.Timestamp = time.Now().Unix()
 	book.CreatedAt = time.Now()
 	book.Attempts = 1
+	applyFixSuggestion(book)
 
 	mismatches = append(mismatches, *book)
+	streamMismatch(*book)
 	return nil
 }
 
 // AddWithMetadata creates and adds a new book mismatch with enhanced metadata
 // If hc is provided, it will be used to look up publisher and other metadata
+// With fix suggestions enabled, the reason code and fix suggestion are derived from reason
 func AddWithMetadata(metadata MediaMetadata, bookID, editionID, reason string, duration float64, audiobookShelfID string, hc hardcover.HardcoverClientInterface) {
 	// Create a logger
 	log := logger.Get()
@@ -118,6 +191,7 @@ func AddWithMetadata(metadata MediaMetadata, bookID, editionID, reason string, d
 	// Enhanced release date lookup - try to get accurate date from Audnex API for ASIN
 	releaseDate := ""
 	audnexReleaseDate := ""
+	audnexPublisher := ""
 
 	// If we have an ASIN, try to look up the book details from Audnex API
 	if metadata.ASIN != "" {
@@ -172,6 +246,8 @@ func AddWithMetadata(metadata MediaMetadata, bookID, editionID, reason string, d
 				"narrators_as_string": strings.Join(narrators, ", "),
 			})
 
+			audnexPublisher = book.PublisherName
+
 			if book.ReleaseDate != "" {
 				audnexReleaseDate = book.ReleaseDate
 				log.Info("Using release date from Audnex API for mismatch enrichment", map[string]interface{}{
@@ -263,9 +339,10 @@ func AddWithMetadata(metadata MediaMetadata, bookID, editionID, reason string, d
 		}
 	}
 
-	// Default publisher values
-	publisherID := 1 // Default publisher ID
-	publisherName := metadata.Publisher
+	// Default edition values, used when the metadata doesn't provide them
+	defaults := getEditionDefaults()
+	publisherID := defaultID(defaults.PublisherID, 1)
+	publisherName := selectPublisherName(metadata.Publisher, audnexPublisher)
 
 	// If we have a Hardcover client and a publisher name, try to look up the publisher ID
 	if hc != nil && publisherName != "" {
@@ -286,7 +363,10 @@ func AddWithMetadata(metadata MediaMetadata, bookID, editionID, reason string, d
 				"error": err.Error(),
 			})
 		} else {
-			logger.Get().Debug("Publisher not found, using default ID", map[string]interface{}{
+			// Leave the ID unset so the name is exported and the edition tool can resolve or
+			// create the publisher, rather than silently using the default one
+			publisherID = 0
+			logger.Get().Warn("Publisher not found in Hardcover, exporting name for the edition tool", map[string]interface{}{
 				"name": publisherName,
 			})
 		}
@@ -307,6 +387,7 @@ func AddWithMetadata(metadata MediaMetadata, bookID, editionID, reason string, d
 	mismatch := BookMismatch{
 		// Core book information
 		BookID:          bookID,
+		EditionID:       editionID,
 		Title:           metadata.Title,
 		Subtitle:        metadata.Subtitle,
 		Author:          metadata.AuthorName,
@@ -327,9 +408,9 @@ func AddWithMetadata(metadata MediaMetadata, bookID, editionID, reason string, d
 
 		// Edition information
 		EditionFormat: "Audiobook",
-		EditionInfo:   "Audiobookshelf", // Only include platform info, no debug/error details
-		LanguageID:    1,                // Default to English
-		CountryID:     1,                // Default to US
+		EditionInfo:   "Audiobookshelf",                  // Only include platform info, no debug/error details
+		LanguageID:    defaultID(defaults.LanguageID, 1), // Default to English
+		CountryID:     defaultID(defaults.CountryID, 1),  // Default to US
 
 		// Publisher information
 		PublisherID: publisherID, // Use looked up or default publisher ID
@@ -338,6 +419,11 @@ func AddWithMetadata(metadata MediaMetadata, bookID, editionID, reason string, d
 		// Audiobookshelf-specific context
 		LibraryID: metadata.LibraryID,
 		FolderID:  metadata.FolderID,
+		ItemID:    audiobookShelfID,
+
+		// Suggested mapping and pin, if any
+		Suggestion:   metadata.Suggestion,
+		BookOverride: metadata.BookOverride,
 
 		// Tracking information
 		Reason:    reason,
@@ -631,11 +717,29 @@ func GetAll() []BookMismatch {
 	return result
 }
 
-// Clear removes all collected mismatches
+// Remove removes the collected mismatch with the given ID, reporting whether there was one
+func Remove(id string) bool {
+	mismatchLock.Lock()
+	defer mismatchLock.Unlock()
+
+	for i := range mismatches {
+		if mismatches[i].ID() == id {
+			mismatches = append(mismatches[:i], mismatches[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Clear removes all collected mismatches and restarts the counts of mismatches logged in detail
 func Clear() {
 	mismatchLock.Lock()
 	defer mismatchLock.Unlock()
 	mismatches = []BookMismatch{}
+
+	logLimitLock.Lock()
+	resetLogCountsLocked()
+	logLimitLock.Unlock()
 }
 
 // ExportJSON returns all mismatches as a JSON string
@@ -666,7 +770,8 @@ func ExportJSON() (string, error) {
 
 // SaveToFile saves all mismatches as individual JSON files in the specified directory
 // in a format compatible with the edition import tool. If outputDir is empty, it will
-// use the directory from the provided config.
+// use the directory from the provided config. The config's Paths.MismatchFormats can
+// also or instead save them all to CSVFileName.
 // Note: This function should be called with a context that has a Hardcover client available
 // for proper author/narrator lookups.
 func SaveToFile(ctx context.Context, hc hardcover.HardcoverClientInterface, outputDir string, cfg *config.Config) error {
@@ -711,6 +816,37 @@ func SaveToFile(ctx context.Context, hc hardcover.HardcoverClientInterface, outp
 		return nil
 	}
 
+	saveJSON, saveCSV := true, false
+	if cfg != nil && len(cfg.Paths.MismatchFormats) > 0 {
+		saveJSON = false
+		for _, format := range cfg.Paths.MismatchFormats {
+			switch format {
+			case config.MismatchFormatJSON:
+				saveJSON = true
+			case config.MismatchFormatCSV:
+				saveCSV = true
+			}
+		}
+	}
+
+	if saveCSV {
+		csvPath := filepath.Join(outputDir, CSVFileName)
+		if err := SaveCSV(mismatches, csvPath); err != nil {
+			log.Error("Failed to save mismatches as CSV in mismatch.SaveToFile", map[string]interface{}{
+				"error":    err.Error(),
+				"filePath": csvPath,
+			})
+			return err
+		}
+		log.Info("Saved mismatches as CSV", map[string]interface{}{
+			"count":    len(mismatches),
+			"filePath": csvPath,
+		})
+	}
+	if !saveJSON {
+		return nil
+	}
+
 	// Track errors
 	var saveErrors []error
 	successCount := 0
@@ -780,7 +916,7 @@ func SaveToFile(ctx context.Context, hc hardcover.HardcoverClientInterface, outp
 	return nil
 }
 
-// cleanupOldFiles removes old JSON files from the output directory
+// cleanupOldFiles removes old JSON files and the CSV file from the output directory
 func cleanupOldFiles(dirPath string) error {
 	log := logger.Get()
 
@@ -796,9 +932,9 @@ func cleanupOldFiles(dirPath string) error {
 		return fmt.Errorf("failed to read directory: %w", err)
 	}
 
-	// Delete all .json files
+	// Delete all .json files and the CSV file
 	for _, file := range files {
-		if !file.IsDir() && strings.HasSuffix(file.Name(), ".json") {
+		if !file.IsDir() && (strings.HasSuffix(file.Name(), ".json") || file.Name() == CSVFileName) {
 			filePath := filepath.Join(dirPath, file.Name())
 			if err := os.Remove(filePath); err != nil {
 				if log != nil {
@@ -858,4 +994,8 @@ type MediaMetadata struct {
 	Duration      float64 `json:"duration,omitempty"`
 	LibraryID     string  // Audiobookshelf library ID
 	FolderID      string  // Source folder ID (if available)
+	// Suggestion is an optional mapping suggestion recorded with the mismatch
+	Suggestion *Suggestion
+	// BookOverride is the Hardcover slug or URL the book is pinned to, if any
+	BookOverride string
 }
@@ -151,6 +151,14 @@ func (m *MockHardcoverClient) SearchBookByASIN(ctx context.Context, asin string)
 	return args.Get(0).(*models.HardcoverBook), args.Error(1)
 }
 
+func (m *MockHardcoverClient) SearchBookByASINAnyFormat(ctx context.Context, asin string) (*models.HardcoverBook, error) {
+	args := m.Called(ctx, asin)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.HardcoverBook), args.Error(1)
+}
+
 func (m *MockHardcoverClient) SearchBookByISBN10(ctx context.Context, isbn10 string) (*models.HardcoverBook, error) {
 	args := m.Called(ctx, isbn10)
 	if args.Get(0) == nil {
@@ -167,6 +175,12 @@ func (m *MockHardcoverClient) SearchBookByISBN13(ctx context.Context, isbn13 str
 	return args.Get(0).(*models.HardcoverBook), args.Error(1)
 }
 
+// AddTagToBook mocks applying a tag to a book
+func (m *MockHardcoverClient) AddTagToBook(ctx context.Context, bookID int, tag string) error {
+	args := m.Called(ctx, bookID, tag)
+	return args.Error(0)
+}
+
 // GetBookByID mocks fetching a Hardcover book by its book ID
 func (m *MockHardcoverClient) GetBookByID(ctx context.Context, bookID string) (*models.HardcoverBook, error) {
 	args := m.Called(ctx, bookID)
@@ -176,6 +190,15 @@ func (m *MockHardcoverClient) GetBookByID(ctx context.Context, bookID string) (*
 	return args.Get(0).(*models.HardcoverBook), args.Error(1)
 }
 
+// GetBookAuthors mocks fetching the authors of a Hardcover book
+func (m *MockHardcoverClient) GetBookAuthors(ctx context.Context, bookID string) ([]models.Author, error) {
+	args := m.Called(ctx, bookID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Author), args.Error(1)
+}
+
 func (m *MockHardcoverClient) AddWithMetadata(metadata string, bookID interface{}, extraData map[string]interface{}) error {
 	args := m.Called(metadata, bookID, extraData)
 	return args.Error(0)
@@ -213,6 +236,78 @@ func (m *MockHardcoverClient) CreateUserBook(ctx context.Context, editionID, sta
 	return args.String(0), args.Error(1)
 }
 
+// SearchBookBySlug is a mock implementation for the HardcoverClientInterface
+func (m *MockHardcoverClient) SearchBookBySlug(ctx context.Context, slug string) (*models.HardcoverBook, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.HardcoverBook), args.Error(1)
+}
+
+// CreateBookLevelUserBook is a mock implementation for the HardcoverClientInterface
+func (m *MockHardcoverClient) CreateBookLevelUserBook(ctx context.Context, bookID, status string) (string, error) {
+	args := m.Called(ctx, bookID, status)
+	return args.String(0), args.Error(1)
+}
+
+// GetFinishedUserBooks is a mock implementation for the HardcoverClientInterface
+func (m *MockHardcoverClient) GetFinishedUserBooks(ctx context.Context) (map[string]time.Time, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]time.Time), args.Error(1)
+}
+
+// GetUserBookRefs is a mock implementation for the HardcoverClientInterface
+func (m *MockHardcoverClient) GetUserBookRefs(ctx context.Context) ([]hardcover.UserBookRef, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]hardcover.UserBookRef), args.Error(1)
+}
+
+// GetUserBooksByEditionIDs is a mock implementation for the HardcoverClientInterface
+func (m *MockHardcoverClient) GetUserBooksByEditionIDs(ctx context.Context, editionIDs []int) ([]hardcover.UserBookRef, error) {
+	args := m.Called(ctx, editionIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]hardcover.UserBookRef), args.Error(1)
+}
+
+// GetAudioEditionNarrators is a mock implementation for the HardcoverClientInterface
+func (m *MockHardcoverClient) GetAudioEditionNarrators(ctx context.Context, bookID string) ([]hardcover.EditionNarrators, error) {
+	args := m.Called(ctx, bookID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]hardcover.EditionNarrators), args.Error(1)
+}
+
+// GetUserLists mocks the GetUserLists method
+func (m *MockHardcoverClient) GetUserLists(ctx context.Context) ([]hardcover.List, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]hardcover.List), args.Error(1)
+}
+
+// CreateList mocks the CreateList method
+func (m *MockHardcoverClient) CreateList(ctx context.Context, name string) (int, error) {
+	args := m.Called(ctx, name)
+	return args.Int(0), args.Error(1)
+}
+
+// AddBookToList mocks the AddBookToList method
+func (m *MockHardcoverClient) AddBookToList(ctx context.Context, listID, bookID int) error {
+	args := m.Called(ctx, listID, bookID)
+	return args.Error(0)
+}
+
 // GetUserBookReads gets the reading progress for a user book
 func (m *MockHardcoverClient) GetUserBookReads(ctx context.Context, input hardcover.GetUserBookReadsInput) ([]hardcover.UserBookRead, error) {
 	args := m.Called(ctx, input)
@@ -228,6 +323,12 @@ func (m *MockHardcoverClient) InsertUserBookRead(ctx context.Context, input hard
 	return args.Int(0), args.Error(1)
 }
 
+// DeleteUserBookRead deletes a reading progress entry
+func (m *MockHardcoverClient) DeleteUserBookRead(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 // MarkEditionAsOwned adds a book to the user's "Owned" list
 func (m *MockHardcoverClient) MarkEditionAsOwned(ctx context.Context, editionID int) error {
 	args := m.Called(ctx, editionID)
@@ -292,11 +393,11 @@ func TestBookMismatchToEditionExport(t *testing.T) {
 				Title:         "Test Book",
 				AuthorIDs:     []int{}, // Empty slice when no authors found
 				AudioSeconds:  19800,
-				EditionFormat: "Audible Audio", // Updated to match new default
-				EditionInfo:   "Unabridged",    // Updated to match new default
-				LanguageID:    1,               // Default values
-				CountryID:     1,               // Default values
-				PublisherID:   0,               // Default values
+				EditionFormat: "audible",    // Updated to match new default
+				EditionInfo:   "Unabridged", // Updated to match new default
+				LanguageID:    1,            // Default values
+				CountryID:     1,            // Default values
+				PublisherID:   0,            // Default values
 			},
 		},
 		{
@@ -356,7 +457,7 @@ func TestBookMismatchToEditionExport(t *testing.T) {
 				PublisherID:   2,
 				ReleaseDate:   "2020-01-01",
 				AudioSeconds:  37800,
-				EditionFormat: "Audible Audio",   // Updated to match new default
+				EditionFormat: "audible",         // Updated to match new default
 				EditionInfo:   "Special Edition", // Updated to remove the period at the end
 				LanguageID:    1,
 				CountryID:     1,
@@ -424,6 +525,36 @@ func TestBookMismatchToEditionExport(t *testing.T) {
 }
 
 // TestAddWithMetadata verifies that AddWithMetadata populates all required fields
+func TestBookMismatchToEditionExport_FormatRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	creator := edition.NewCreatorWithHTTPClient(nil, logger.Get(), true, "", nil)
+
+	tests := map[string]string{
+		"":                      "Audible Audio",
+		"Audible Studios":       "Audible Audio",
+		"libro.fm":              "Audiobook",
+		"Tantor Audio":          "Audible Audio",
+		"Blackstone Audiobooks": "Audiobook",
+	}
+
+	for publisher, expected := range tests {
+		t.Run(publisher, func(t *testing.T) {
+			book := BookMismatch{HardcoverBookID: "123", Title: "Test Book", Publisher: publisher}
+			export := book.ToEditionExport(ctx, &MockHardcoverClient{})
+
+			// The edition tool reads exports as edition inputs
+			data, err := json.Marshal(export)
+			require.NoError(t, err)
+			var input edition.EditionInput
+			require.NoError(t, json.Unmarshal(data, &input))
+
+			format, err := creator.ResolveFormat(input.EditionFormat)
+			require.NoError(t, err)
+			assert.Equal(t, expected, format.Name)
+		})
+	}
+}
+
 func TestAddWithMetadata(t *testing.T) {
 	// Setup
 	metadata := MediaMetadata{
@@ -467,6 +598,65 @@ func TestAddWithMetadata(t *testing.T) {
 	assert.Equal(t, 1, mismatch.PublisherID)                // Default publisher
 }
 
+// TestAddWithMetadata_Suggestion verifies that a mapping suggestion is recorded with the mismatch
+// and included in its edition export
+func TestAddWithMetadata_Suggestion(t *testing.T) {
+	Clear()
+	defer Clear()
+
+	// Without an ASIN or Hardcover client no lookups are made
+	metadata := MediaMetadata{
+		Title:      "Suggested Book",
+		AuthorName: "Test Author",
+		Suggestion: &Suggestion{BookID: "42", EditionID: "420", Title: "Suggested Book", Source: "title_author"},
+	}
+	AddWithMetadata(metadata, "abs-1", "420", "Found by title/author only - manual verification required", 3600, "abs-1", nil)
+
+	mismatches := GetAll()
+	require.Len(t, mismatches, 1)
+	require.NotNil(t, mismatches[0].Suggestion)
+	assert.Equal(t, "42", mismatches[0].Suggestion.BookID)
+	assert.Equal(t, "420", mismatches[0].Suggestion.EditionID)
+
+	export := mismatches[0].ToEditionExport(context.Background(), nil)
+	require.NotNil(t, export.Info)
+	assert.Equal(t, metadata.Suggestion, export.Info.Suggestion)
+}
+
+func TestAddWithMetadata_DeduplicatesByItem(t *testing.T) {
+	Clear()
+	defer Clear()
+
+	metadata := MediaMetadata{Title: "Repeated Book", AuthorName: "Test Author"}
+	AddWithMetadata(metadata, "", "", "could not find book in Hardcover", 3600, "li_1", nil)
+	AddWithMetadata(metadata, "42", "", "book exists but has no edition ID for book 42", 3600, "li_1", nil)
+	// The less informative reason doesn't replace the better one
+	AddWithMetadata(metadata, "", "", "could not find book in Hardcover", 3600, "li_1", nil)
+	AddWithMetadata(MediaMetadata{Title: "Other Book"}, "", "", "could not find book in Hardcover", 3600, "li_2", nil)
+
+	mismatches := GetAll()
+	require.Len(t, mismatches, 2)
+	assert.Equal(t, "li_1", mismatches[0].ItemID)
+	assert.Equal(t, "42", mismatches[0].BookID)
+	assert.Equal(t, "book exists but has no edition ID for book 42", mismatches[0].Reason)
+	assert.Equal(t, 3, mismatches[0].Attempts)
+	assert.Equal(t, "li_2", mismatches[1].ItemID)
+}
+
+func TestAdd_DeduplicatesWithoutItemID(t *testing.T) {
+	Clear()
+	defer Clear()
+
+	// Without an item ID the mismatch is identified by its book ID
+	Add(BookMismatch{BookID: "li_1", Title: "Repeated Book", Reason: "Narrator mismatch: Audiobookshelf narrator \"A\", Hardcover edition 1 narrator \"B\""})
+	Add(BookMismatch{BookID: "li_1", Title: "Repeated Book", Reason: "Narrator mismatch: Audiobookshelf narrator \"A\", Hardcover edition 1 narrator \"B\""})
+	Add(BookMismatch{BookID: "li_1", ItemID: "li_1", Title: "Repeated Book", Reason: "could not find book in Hardcover"})
+
+	mismatches := GetAll()
+	require.Len(t, mismatches, 1)
+	assert.Equal(t, 3, mismatches[0].Attempts)
+}
+
 func TestBookMismatchToEditionInput(t *testing.T) {
 	// Create a test context
 	ctx := context.Background()
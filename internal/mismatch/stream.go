@@ -0,0 +1,106 @@
+package mismatch
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+)
+
+// StreamFileName is the name of the file mismatches are streamed to in the mismatch output directory
+const StreamFileName = "mismatches.jsonl"
+
+var (
+	stream     *Stream
+	streamLock sync.Mutex
+)
+
+// Stream appends each mismatch to a JSON Lines file as soon as it's recorded, so mismatches can be
+// followed while a sync runs and survive a crash, and optionally emits it on a channel
+type Stream struct {
+	mu   sync.Mutex
+	file *os.File
+	ch   chan<- BookMismatch
+}
+
+// OpenStream creates (or truncates) the stream file at path. Mismatches are also sent to ch if it
+// isn't nil; they're dropped rather than blocking when ch is full.
+func OpenStream(path string, ch chan<- BookMismatch) (*Stream, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create mismatch stream directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open mismatch stream file: %w", err)
+	}
+	return &Stream{file: file, ch: ch}, nil
+}
+
+// write appends the mismatch to the stream file and sends it to the channel
+func (s *Stream) write(book BookMismatch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ch != nil {
+		select {
+		case s.ch <- book:
+		default:
+			logger.Get().Debug("Mismatch channel is full, not emitting mismatch", map[string]interface{}{
+				"title": book.Title,
+			})
+		}
+	}
+
+	if s.file == nil {
+		return fmt.Errorf("mismatch stream is closed")
+	}
+	data, err := json.Marshal(book)
+	if err != nil {
+		return fmt.Errorf("failed to marshal mismatch: %w", err)
+	}
+	// Each mismatch is written with a single call, so a crash leaves at most a partial last line
+	if _, err := s.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to append mismatch: %w", err)
+	}
+	return nil
+}
+
+// Close closes the stream file. It doesn't close the channel, which belongs to the caller.
+func (s *Stream) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// SetStream sets the stream every recorded mismatch is written to, or stops streaming when nil
+func SetStream(s *Stream) {
+	streamLock.Lock()
+	defer streamLock.Unlock()
+	stream = s
+}
+
+// streamMismatch writes the mismatch to the current stream, if any
+func streamMismatch(book BookMismatch) {
+	streamLock.Lock()
+	s := stream
+	streamLock.Unlock()
+	if s == nil {
+		return
+	}
+
+	if err := s.write(book); err != nil {
+		logger.Get().Warn("Failed to stream mismatch", map[string]interface{}{
+			"title": book.Title,
+			"error": err.Error(),
+		})
+	}
+}
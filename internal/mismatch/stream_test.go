@@ -0,0 +1,72 @@
+package mismatch
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readStreamFile returns the mismatches in a stream file
+func readStreamFile(t *testing.T, path string) []BookMismatch {
+	t.Helper()
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var result []BookMismatch
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var book BookMismatch
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &book))
+		result = append(result, book)
+	}
+	require.NoError(t, scanner.Err())
+	return result
+}
+
+func TestStream(t *testing.T) {
+	Clear()
+	defer Clear()
+
+	path := filepath.Join(t.TempDir(), "mismatches", StreamFileName)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte("left over from the last run\n"), 0644))
+
+	ch := make(chan BookMismatch, 1)
+	stream, err := OpenStream(path, ch)
+	require.NoError(t, err)
+	SetStream(stream)
+	defer SetStream(nil)
+
+	// Each mismatch is on disk as soon as it's recorded
+	Add(BookMismatch{BookID: "book-1", Title: "First", Reason: "not found"})
+	streamed := readStreamFile(t, path)
+	require.Len(t, streamed, 1)
+	assert.Equal(t, "book-1", streamed[0].BookID)
+	assert.Equal(t, "not found", streamed[0].Reason)
+
+	require.NoError(t, RecordMismatch(&BookMismatch{BookID: "book-2", Title: "Second", Reason: "no edition"}))
+	streamed = readStreamFile(t, path)
+	require.Len(t, streamed, 2)
+	assert.Equal(t, "book-2", streamed[1].BookID)
+
+	// The first mismatch was emitted; the second is dropped rather than blocking on the full channel
+	require.Len(t, ch, 1)
+	assert.Equal(t, "book-1", (<-ch).BookID)
+
+	// Nothing is streamed once the stream is unset
+	SetStream(nil)
+	require.NoError(t, stream.Close())
+	Add(BookMismatch{BookID: "book-3", Title: "Third"})
+	assert.Len(t, readStreamFile(t, path), 2)
+	assert.Empty(t, ch)
+
+	// Streaming doesn't change what's collected for the end of the sync
+	assert.Len(t, GetAll(), 3)
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/edition"
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
 )
 
@@ -68,7 +69,8 @@ func (b *BookMismatch) ToEditionExport(ctx context.Context, hc hardcover.Hardcov
 		"title":             b.Title,
 	})
 
-	// Set edition format based on publisher if possible
+	// Set edition format based on publisher if possible. The format is exported as a canonical key,
+	// which is what the edition tool resolves when it creates the edition.
 	editionFormat := b.EditionFormat
 	if editionFormat == "" || editionFormat == "Audiobook" {
 		// Try to determine a more specific format based on publisher
@@ -79,35 +81,33 @@ func (b *BookMismatch) ToEditionExport(ctx context.Context, hc hardcover.Hardcov
 			case strings.Contains(publisher, "audible") ||
 				strings.Contains(publisher, "brilliance") ||
 				strings.Contains(publisher, "amazon"):
-				editionFormat = "Audible Audio"
-			case strings.Contains(publisher, "libro"):
-				editionFormat = "libro.fm"
-			case strings.Contains(publisher, "audiobook"):
-				editionFormat = "Audiobook"
+				editionFormat = "audible"
+			case strings.Contains(publisher, "libro") ||
+				strings.Contains(publisher, "audiobook"):
+				// libro.fm sells plain audiobooks, Hardcover has no format of its own for them
+				editionFormat = edition.DefaultFormatKey
 			default:
-				// If we can't determine, use "Audible Audio" as default for audiobooks
-				editionFormat = "Audible Audio"
+				// If we can't determine, use "audible" as default for audiobooks
+				editionFormat = "audible"
 			}
 		} else {
 			// Default to Audible Audio if no publisher info
-			editionFormat = "Audible Audio"
+			editionFormat = "audible"
 		}
 	}
 
 	// Set default language and country if not specified
+	defaults := getEditionDefaults()
 	languageID := b.LanguageID
 	if languageID == 0 {
-		languageID = 1 // Default to English
+		languageID = defaultID(defaults.LanguageID, 1) // Default to English
 	}
 
 	countryID := b.CountryID
 	if countryID == 0 {
-		countryID = 1 // Default to US
+		countryID = defaultID(defaults.CountryID, 1) // Default to US
 	}
 
-	// Use the provided publisher ID or default to 0
-	publisherID := b.PublisherID
-
 	// Prefer Audiobookshelf cover (ImageURL/CoverURL) for image_url so the
 	// edition tool can fetch from ABS, and only fall back to Hardcover cover
 	// if no ABS image is available.
@@ -193,6 +193,16 @@ func (b *BookMismatch) ToEditionExport(ctx context.Context, hc hardcover.Hardcov
 		}
 	}
 
+	// Use the provided or looked up publisher ID, or the configured default if the metadata has no
+	// publisher. A named publisher that couldn't be resolved is exported by name instead.
+	publisherID := b.PublisherID
+	publisherName := ""
+	if publisherID == 0 && b.Publisher == "" {
+		publisherID = defaults.PublisherID
+	} else if publisherID == 0 {
+		publisherName = b.Publisher
+	}
+
 	logger.Debug(fmt.Sprintf("Final AuthorIDs: %v, NarratorIDs: %v", authorIDs, narratorIDs))
 
 	// Initialize all fields with zero values to ensure they appear in the JSON output
@@ -208,6 +218,7 @@ func (b *BookMismatch) ToEditionExport(ctx context.Context, hc hardcover.Hardcov
 		AuthorIDs:     authorIDs,
 		NarratorIDs:   narratorIDs,
 		PublisherID:   publisherID,
+		PublisherName: publisherName,
 		ReleaseDate:   b.ReleaseDate,
 		AudioSeconds:  b.DurationSeconds,
 		EditionFormat: editionFormat,
@@ -223,9 +234,14 @@ func (b *BookMismatch) ToEditionExport(ctx context.Context, hc hardcover.Hardcov
 			PublishedYear:     b.PublishedYear,
 			CoverURL:          b.CoverURL,
 			HardcoverCoverURL: b.HardcoverCoverURL,
+			Suggestion:        b.Suggestion,
+			ItemID:            b.ItemID,
+			BookOverride:      b.BookOverride,
 			Timestamp:         b.Timestamp,
 			CreatedAt:         b.CreatedAt.Format(time.RFC3339),
 			Reason:            b.Reason,
+			ReasonCode:        b.ReasonCode,
+			FixSuggestion:     b.FixSuggestion,
 			Attempts:          b.Attempts,
 		},
 	}
@@ -322,7 +338,8 @@ func (b *BookMismatch) ToEditionInput(ctx context.Context, hc hardcover.Hardcove
 		userNotes += fmt.Sprintf("Folder ID: %s\n", b.FolderID)
 	}
 
-	// Create the edition input
+	// Create the edition input, using the configured defaults for unknown fields
+	defaults := getEditionDefaults()
 	edition := EditionCreatorInput{
 		// Core book information
 		BookID:   bookID,
@@ -337,13 +354,13 @@ func (b *BookMismatch) ToEditionInput(ctx context.Context, hc hardcover.Hardcove
 		// Media information
 		ImageURL:    b.ImageURL, // Prefer ImageURL over CoverURL
 		AudioLength: b.DurationSeconds,
-		LanguageID:  1, // Default to English (would need to be looked up)
+		LanguageID:  defaultID(defaults.LanguageID, 1), // Default to English (would need to be looked up)
 
 		// Relationships
 		AuthorIDs:   authorIDs,
 		NarratorIDs: narratorIDs,
-		PublisherID: 0, // Would need to be looked up
-		CountryID:   1, // Default to US (would need to be looked up)
+		PublisherID: defaults.PublisherID,             // Would need to be looked up
+		CountryID:   defaultID(defaults.CountryID, 1), // Default to US (would need to be looked up)
 
 		// Edition information
 		ReleaseDate:   releaseDate,
@@ -364,6 +381,7 @@ func (b *BookMismatch) ToEditionInput(ctx context.Context, hc hardcover.Hardcove
 type BookMismatch struct {
 	// Core book information
 	BookID      string `json:"book_id"`
+	EditionID   string `json:"edition_id,omitempty"`
 	Title       string `json:"title"`
 	Subtitle    string `json:"subtitle,omitempty"`
 	Author      string `json:"author"`
@@ -378,6 +396,9 @@ type BookMismatch struct {
 	ISBN13    string `json:"isbn_13,omitempty"`
 	LibraryID string `json:"library_id,omitempty"`
 	FolderID  string `json:"folder_id,omitempty"`
+	// ItemID is the Audiobookshelf item ID, which pins the book to a Hardcover book in
+	// sync.book_overrides
+	ItemID string `json:"item_id,omitempty"`
 
 	// Metadata
 	ReleaseDate     string `json:"release_date,omitempty"`
@@ -405,11 +426,41 @@ type BookMismatch struct {
 	HardcoverISBN          string `json:"hardcover_isbn,omitempty"`
 	HardcoverSlug          string `json:"hardcover_slug,omitempty"`
 
+	// Suggested mapping for the user to confirm
+	Suggestion *Suggestion `json:"suggestion,omitempty"`
+	// BookOverride is the Hardcover slug or URL the book is pinned to, if any, which didn't get
+	// it synced
+	BookOverride string `json:"book_override,omitempty"`
+
 	// Tracking
 	Reason    string    `json:"reason"`
 	Timestamp int64     `json:"timestamp"`
 	Attempts  int       `json:"attempts,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
+
+	// ReasonCode classifies Reason, e.g. NOT_FOUND, and FixSuggestion says how to fix it; both are
+	// only set when fix suggestions are enabled
+	ReasonCode    string `json:"reason_code,omitempty"`
+	FixSuggestion string `json:"fix_suggestion,omitempty"`
+}
+
+// ID identifies the mismatch: the Audiobookshelf item ID, or the book ID of mismatches recorded
+// without one
+func (b *BookMismatch) ID() string {
+	if b.ItemID != "" {
+		return b.ItemID
+	}
+	return b.BookID
+}
+
+// Suggestion is a Hardcover book and edition a mismatched book probably maps to, found by a lookup
+// that isn't trusted enough to sync it, so the mapping can be confirmed quickly
+type Suggestion struct {
+	BookID    string `json:"book_id,omitempty"`
+	EditionID string `json:"edition_id,omitempty"`
+	Title     string `json:"title,omitempty"`
+	// Source is how the suggestion was found, e.g. "title_author"
+	Source string `json:"source,omitempty"`
 }
 
 // EditionExportInfo contains additional informational fields that are not used during import
@@ -425,11 +476,20 @@ type EditionExportInfo struct {
 	CoverURL          string `json:"cover_url,omitempty"`
 	HardcoverCoverURL string `json:"hardcover_cover_url,omitempty"`
 
+	// Suggested mapping for the user to confirm
+	Suggestion *Suggestion `json:"suggestion,omitempty"`
+
+	// Pinning the book in sync.book_overrides
+	ItemID       string `json:"item_id,omitempty"`
+	BookOverride string `json:"book_override,omitempty"`
+
 	// Export process metadata
-	Timestamp int64  `json:"timestamp,omitempty"`
-	CreatedAt string `json:"created_at,omitempty"`
-	Reason    string `json:"reason,omitempty"`
-	Attempts  int    `json:"attempts,omitempty"`
+	Timestamp     int64  `json:"timestamp,omitempty"`
+	CreatedAt     string `json:"created_at,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+	ReasonCode    string `json:"reason_code,omitempty"`
+	FixSuggestion string `json:"fix_suggestion,omitempty"`
+	Attempts      int    `json:"attempts,omitempty"`
 }
 
 // EditionExport represents the format expected by the Hardcover edition import tool
@@ -445,6 +505,7 @@ type EditionExport struct {
 	AuthorIDs     []int  `json:"author_ids"`
 	NarratorIDs   []int  `json:"narrator_ids"`
 	PublisherID   int    `json:"publisher_id"`
+	PublisherName string `json:"publisher_name,omitempty"` // Set when the publisher has no Hardcover ID yet
 	ReleaseDate   string `json:"release_date"`
 	AudioSeconds  int    `json:"audio_seconds"`
 	EditionFormat string `json:"edition_format"`
@@ -0,0 +1,63 @@
+package mismatch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+)
+
+// UnmatchedExport is the file of books not found in Hardcover, with everything known about them
+// in the format of the edition import tool, for adding them to Hardcover manually
+type UnmatchedExport struct {
+	GeneratedAt time.Time        `json:"generated_at"`
+	Count       int              `json:"count"`
+	Books       []*EditionExport `json:"books"`
+}
+
+// Unmatched returns the mismatches of books that weren't found in Hardcover at all, as opposed to
+// books found with a wrong or missing edition
+func Unmatched(mismatches []BookMismatch) []BookMismatch {
+	var unmatched []BookMismatch
+	for _, book := range mismatches {
+		code := book.ReasonCode
+		if code == "" {
+			code = ReasonCodeFor(book.Reason)
+		}
+		if code == ReasonNotFound {
+			unmatched = append(unmatched, book)
+		}
+	}
+	return unmatched
+}
+
+// ExportUnmatched writes the collected mismatches of books not found in Hardcover to path,
+// replacing the export of the previous run, and returns how many were written. hc is used to look
+// up the author, narrator and publisher IDs like SaveToFile does, and may be nil.
+func ExportUnmatched(ctx context.Context, hc hardcover.HardcoverClientInterface, path string) (int, error) {
+	unmatched := Unmatched(GetAll())
+	export := UnmatchedExport{
+		GeneratedAt: time.Now().UTC(),
+		Count:       len(unmatched),
+		Books:       make([]*EditionExport, 0, len(unmatched)),
+	}
+	for i := range unmatched {
+		export.Books = append(export.Books, unmatched[i].ToEditionExport(ctx, hc))
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal unmatched books: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return 0, fmt.Errorf("failed to create directory for unmatched books: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return 0, fmt.Errorf("failed to write unmatched books: %w", err)
+	}
+	return export.Count, nil
+}
@@ -0,0 +1,74 @@
+package mismatch
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportUnmatched(t *testing.T) {
+	ctx := newTestContext(t)
+	Clear()
+	defer Clear()
+
+	Add(BookMismatch{
+		BookID:          "li_missing",
+		ItemID:          "li_missing",
+		Title:           "Missing Book",
+		Subtitle:        "A Novel",
+		Author:          "Jane Author",
+		Narrator:        "Nick Narrator",
+		Publisher:       "Indie Audio",
+		ASIN:            "B0MISSING1",
+		ISBN13:          "9781234567897",
+		ReleaseDate:     "2024-05-01",
+		DurationSeconds: 36000,
+		ImageURL:        "https://abs.example.com/api/items/li_missing/cover",
+		Reason:          "Could not find book in Hardcover by ASIN, ISBN or title/author",
+	})
+	Add(BookMismatch{BookID: "li_coded", Title: "Coded Book", Reason: "lookup failed", ReasonCode: ReasonNotFound})
+	Add(BookMismatch{BookID: "li_narrator", Title: "Other Narration", HardcoverBookID: "42", Reason: "narrator mismatch on matched edition"})
+	Add(BookMismatch{BookID: "li_edition", Title: "No Edition", HardcoverBookID: "43", Reason: "No edition ID found for book"})
+
+	path := filepath.Join(t.TempDir(), "exports", "unmatched.json")
+	count, err := ExportUnmatched(ctx, nil, path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count, "only books not found in Hardcover are exported")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var export UnmatchedExport
+	require.NoError(t, json.Unmarshal(data, &export))
+	require.Len(t, export.Books, 2)
+	assert.Equal(t, 2, export.Count)
+
+	book := export.Books[0]
+	assert.Zero(t, book.BookID, "the book isn't in Hardcover yet")
+	assert.Equal(t, "Missing Book", book.Title)
+	assert.Equal(t, "A Novel", book.Subtitle)
+	assert.Equal(t, "B0MISSING1", book.ASIN)
+	assert.Equal(t, "9781234567897", book.ISBN13)
+	assert.Equal(t, "2024-05-01", book.ReleaseDate)
+	assert.Equal(t, 36000, book.AudioSeconds)
+	assert.Equal(t, "https://abs.example.com/api/items/li_missing/cover", book.ImageURL)
+	assert.Equal(t, "Indie Audio", book.PublisherName, "unresolved publishers are exported by name")
+	require.NotNil(t, book.Info)
+	assert.Equal(t, "Jane Author", book.Info.AuthorName)
+	assert.Equal(t, "Nick Narrator", book.Info.NarratorName)
+	assert.Equal(t, "li_missing", book.Info.ItemID)
+
+	assert.Equal(t, "Coded Book", export.Books[1].Title)
+
+	// The next run replaces the export
+	Clear()
+	count, err = ExportUnmatched(ctx, nil, path)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"books": []`)
+}
@@ -44,11 +44,14 @@ type AudiobookshelfBook struct {
 	LibraryID string `json:"libraryId"`
 	Path      string `json:"path"`
 	MediaType string `json:"mediaType"`
+	// UpdatedAt is when the library item was last updated (Unix milliseconds)
+	UpdatedAt int64 `json:"updatedAt,omitempty"`
 	Media     struct {
 		ID       string                      `json:"id"`
 		Metadata AudiobookshelfMetadataStruct `json:"metadata"`
 		CoverPath string                     `json:"coverPath"`
 		Duration  float64                    `json:"duration"`
+		Tags      []string                   `json:"tags,omitempty"`
 	} `json:"media"`
 	// Progress tracks the user's progress through the book
 	Progress struct {
@@ -56,6 +59,10 @@ type AudiobookshelfBook struct {
 		IsFinished  bool    `json:"isFinished"`
 		StartedAt   int64   `json:"startedAt"`
 		FinishedAt  int64   `json:"finishedAt"`
+		LastUpdate  int64   `json:"lastUpdate,omitempty"`
+		// HideFromContinueListening is set when the user hid or archived the item in
+		// Audiobookshelf ("Remove from Continue Listening")
+		HideFromContinueListening bool `json:"hideFromContinueListening,omitempty"`
 	} `json:"progress,omitempty"`
 }
 
@@ -96,6 +103,8 @@ func (b *AudiobookshelfBook) GetProgress() *AudiobookshelfProgress {
 		IsFinished:  b.Progress.IsFinished,
 		StartedAt:   b.Progress.StartedAt,
 		FinishedAt:  b.Progress.FinishedAt,
+
+		HideFromContinueListening: b.Progress.HideFromContinueListening,
 	}
 }
 
@@ -105,6 +114,9 @@ type AudiobookshelfProgress struct {
 	IsFinished  bool    `json:"isFinished"`
 	StartedAt   int64   `json:"startedAt"`
 	FinishedAt  int64   `json:"finishedAt"`
+	LastUpdate  int64   `json:"lastUpdate,omitempty"`
+	// HideFromContinueListening is set when the user hid or archived the item
+	HideFromContinueListening bool `json:"hideFromContinueListening,omitempty"`
 }
 
 // AudiobookshelfLibraryResponse represents the response from the Audiobookshelf API
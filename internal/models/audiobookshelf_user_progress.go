@@ -16,6 +16,10 @@ type AudiobookshelfUserProgress struct {
 		FinishedAt    int64   `json:"finishedAt"`
 		LastUpdate    int64   `json:"lastUpdate"`
 		TimeListening float64 `json:"timeListening"`
+		// EbookProgress is the share of the item's ebook read (0-1), tracked apart from the audio
+		EbookProgress float64 `json:"ebookProgress"`
+		// HideFromContinueListening is set when the user hid or archived the item
+		HideFromContinueListening bool `json:"hideFromContinueListening"`
 	} `json:"mediaProgress"`
 	ListeningSessions []struct {
 		ID            string `json:"id"`
@@ -24,6 +24,8 @@ type HardcoverBook struct {
 	EditionASIN   string `json:"edition_asin,omitempty"`
 	EditionISBN13 string `json:"edition_isbn_13,omitempty"`
 	EditionISBN10 string `json:"edition_isbn_10,omitempty"`
+	// UpdatedAt is when the user book was last updated (RFC 3339), only set by GetUserBook
+	UpdatedAt string `json:"updated_at,omitempty"`
 }
 
 // Author represents an author or narrator in the Hardcover API
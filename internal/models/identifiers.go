@@ -0,0 +1,89 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+)
+
+// asinPattern matches Amazon ASINs of audiobooks and Kindle books, which start with "B0"
+var asinPattern = regexp.MustCompile(`^B0[A-Z0-9]{8}$`)
+
+// identifierSeparators splits metadata fields holding several identifiers
+var identifierSeparators = regexp.MustCompile(`[,;|/]+`)
+
+// Identifiers holds the ASINs and ISBNs found in a book's metadata, each in priority order
+type Identifiers struct {
+	ASINs []string
+	ISBNs []string
+}
+
+// Conflicting reports whether the metadata carries more than one distinct ASIN or ISBN
+func (ids Identifiers) Conflicting() bool {
+	return len(ids.ASINs) > 1 || len(ids.ISBNs) > 1
+}
+
+// ExtractIdentifiers returns the distinct ASINs and ISBNs in the metadata. Fields may hold several
+// identifiers separated by commas, semicolons, slashes or pipes, and an ASIN in the ISBN field is
+// treated as an ASIN. The ASIN field comes first, followed by the ISBN field, each in the order the
+// identifiers appear. ISBN-10s and ISBN-13s of the same book count as one ISBN.
+func ExtractIdentifiers(metadata AudiobookshelfMetadataStruct) Identifiers {
+	var ids Identifiers
+	seenASINs := make(map[string]bool)
+	seenISBNs := make(map[string]bool)
+
+	addASIN := func(asin string) {
+		if !seenASINs[asin] {
+			seenASINs[asin] = true
+			ids.ASINs = append(ids.ASINs, asin)
+		}
+	}
+	addISBN := func(isbn string) {
+		key := isbn
+		if len(isbn) == 10 {
+			key = isbn10To13(isbn)
+		}
+		if !seenISBNs[key] {
+			seenISBNs[key] = true
+			ids.ISBNs = append(ids.ISBNs, isbn)
+		}
+	}
+
+	for _, value := range strings.Fields(identifierSeparators.ReplaceAllString(metadata.ASIN, " ")) {
+		addASIN(strings.ToUpper(value))
+	}
+
+	for _, value := range identifierSeparators.Split(metadata.ISBN, -1) {
+		value = strings.ToUpper(strings.TrimSpace(value))
+		if value == "" {
+			continue
+		}
+		if asinPattern.MatchString(value) {
+			addASIN(value)
+			continue
+		}
+		addISBN(strings.NewReplacer("-", "", " ", "").Replace(value))
+	}
+
+	return ids
+}
+
+// isbn10To13 converts an ISBN-10 to its ISBN-13, returning the input unchanged when it isn't a
+// well-formed ISBN-10
+func isbn10To13(isbn10 string) string {
+	if len(isbn10) != 10 {
+		return isbn10
+	}
+	isbn13 := "978" + isbn10[:9]
+	sum := 0
+	for i, r := range isbn13 {
+		if r < '0' || r > '9' {
+			return isbn10
+		}
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += int(r-'0') * weight
+	}
+	return isbn13 + string(rune('0'+(10-sum%10)%10))
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractIdentifiers(t *testing.T) {
+	tests := []struct {
+		name            string
+		asin            string
+		isbn            string
+		wantASINs       []string
+		wantISBNs       []string
+		wantConflicting bool
+	}{
+		{name: "single identifiers", asin: "B00TEST123", isbn: "978-1-234-56789-7", wantASINs: []string{"B00TEST123"}, wantISBNs: []string{"9781234567897"}},
+		{name: "no identifiers"},
+		{name: "two ASINs in the ASIN field", asin: "B00TEST123, b00other45", wantASINs: []string{"B00TEST123", "B00OTHER45"}, wantConflicting: true},
+		{name: "duplicate ASIN", asin: "B00TEST123; B00TEST123", wantASINs: []string{"B00TEST123"}},
+		{name: "different ASIN in the ISBN field", asin: "B00TEST123", isbn: "B00OTHER45", wantASINs: []string{"B00TEST123", "B00OTHER45"}, wantConflicting: true},
+		{name: "same ASIN in the ISBN field", asin: "B00TEST123", isbn: "B00TEST123", wantASINs: []string{"B00TEST123"}},
+		{name: "two ISBNs", isbn: "9781234567897 / 9780987654321", wantISBNs: []string{"9781234567897", "9780987654321"}, wantConflicting: true},
+		{name: "ISBN-10 and ISBN-13 of the same book", isbn: "0306406152, 978-0-306-40615-7", wantISBNs: []string{"0306406152"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids := ExtractIdentifiers(AudiobookshelfMetadataStruct{ASIN: tt.asin, ISBN: tt.isbn})
+			assert.Equal(t, tt.wantASINs, ids.ASINs)
+			assert.Equal(t, tt.wantISBNs, ids.ISBNs)
+			assert.Equal(t, tt.wantConflicting, ids.Conflicting())
+		})
+	}
+}
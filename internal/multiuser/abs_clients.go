@@ -0,0 +1,43 @@
+package multiuser
+
+import (
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/audiobookshelf"
+)
+
+// cachedABSClient is a profile's Audiobookshelf client along with the credentials it was created
+// with
+type cachedABSClient struct {
+	url    string
+	token  string
+	client *audiobookshelf.Client
+}
+
+// audiobookshelfClient returns the Audiobookshelf client of a profile. A client is created once
+// per profile and reused by all of its sync runs, periodic or targeted, as long as the profile's
+// URL and token stay the same; a sync service uses the client for all libraries of its run.
+func (s *MultiUserService) audiobookshelfClient(profileID, url, token string) *audiobookshelf.Client {
+	s.absMutex.Lock()
+	defer s.absMutex.Unlock()
+
+	if cached, ok := s.absClients[profileID]; ok && cached.url == url && cached.token == token {
+		return cached.client
+	}
+
+	client := audiobookshelf.NewClient(url, token)
+	if s.globalConfig != nil {
+		client.SetFullItems(s.globalConfig.Audiobookshelf.FullItems)
+		client.SetAuthScheme(s.globalConfig.Audiobookshelf.AuthScheme)
+	}
+	s.absClients[profileID] = &cachedABSClient{url: url, token: token, client: client}
+	s.logger.Debug("Created Audiobookshelf client for profile", map[string]interface{}{
+		"profile_id": profileID,
+	})
+	return client
+}
+
+// forgetAudiobookshelfClient drops the cached Audiobookshelf client of a profile
+func (s *MultiUserService) forgetAudiobookshelfClient(profileID string) {
+	s.absMutex.Lock()
+	defer s.absMutex.Unlock()
+	delete(s.absClients, profileID)
+}
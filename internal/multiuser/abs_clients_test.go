@@ -0,0 +1,52 @@
+package multiuser
+
+import (
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/audiobookshelf"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPerformSync_ReusesAudiobookshelfClient(t *testing.T) {
+	absServer := newTestAudiobookshelfServer(t)
+	hcServer, _ := newTestHardcoverServer(t)
+	svc := newTestMultiUserService(t, hcServer.URL)
+
+	syncConfig := database.SyncConfigData{SyncInterval: "1h", SyncWantToRead: true}
+	require.NoError(t, svc.CreateProfile("alice", "Alice", absServer.URL, "abs-token", "hc-token", syncConfig))
+
+	sync := func() *audiobookshelf.Client {
+		t.Helper()
+		require.NoError(t, svc.StartSync("alice"))
+		status := waitForSync(t, svc, "alice")
+		require.Equal(t, "completed", status.Status, status.Error)
+		svc.absMutex.Lock()
+		defer svc.absMutex.Unlock()
+		require.Contains(t, svc.absClients, "alice")
+		return svc.absClients["alice"].client
+	}
+
+	// Consecutive syncs of a profile share its client
+	first := sync()
+	assert.Same(t, first, sync())
+
+	// A new token gets a new client, which is reused in turn
+	require.NoError(t, svc.UpdateProfileConfig("alice", absServer.URL, "new-abs-token", "hc-token", syncConfig))
+	rotated := sync()
+	assert.NotSame(t, first, rotated)
+	assert.Same(t, rotated, sync())
+
+	require.NoError(t, svc.DeleteProfile("alice"))
+	assert.NotContains(t, svc.absClients, "alice")
+}
+
+func TestAudiobookshelfClient_PerProfile(t *testing.T) {
+	svc := newTestMultiUserService(t, "http://hardcover.invalid")
+
+	alice := svc.audiobookshelfClient("alice", "http://abs.invalid", "alice-token")
+	assert.Same(t, alice, svc.audiobookshelfClient("alice", "http://abs.invalid", "alice-token"))
+	assert.NotSame(t, alice, svc.audiobookshelfClient("bob", "http://abs.invalid", "bob-token"))
+	assert.NotSame(t, alice, svc.audiobookshelfClient("alice", "http://abs2.invalid", "alice-token"))
+}
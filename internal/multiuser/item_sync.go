@@ -0,0 +1,188 @@
+package multiuser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/sync"
+)
+
+// ErrSyncInProgress is returned by SyncItem while the profile is already syncing
+var ErrSyncInProgress = errors.New("sync already in progress")
+
+// itemSyncWait is how often a queued item sync checks whether the profile's running sync finished
+var itemSyncWait = time.Second
+
+// EnqueueItemSync queues a targeted sync of a single library item for a profile, e.g. after
+// Audiobookshelf reported progress on it. Items queued while the profile is syncing are synced
+// once that sync finishes, each item at most once however often it was queued meanwhile.
+func (s *MultiUserService) EnqueueItemSync(profileID, itemID string) error {
+	profile, err := s.GetProfile(profileID)
+	if err != nil {
+		return fmt.Errorf("failed to get profile: %w", err)
+	}
+	if profile == nil {
+		return fmt.Errorf("profile %s not found", profileID)
+	}
+	if until, paused := s.authBackoffUntil(profileID); paused {
+		return fmt.Errorf("syncs for profile %s are paused until %s after repeated authentication failures", profileID, until.Format(time.RFC3339))
+	}
+
+	s.itemsMutex.Lock()
+	defer s.itemsMutex.Unlock()
+
+	pending, running := s.pendingItems[profileID]
+	if !running {
+		pending = make(map[string]struct{})
+		s.pendingItems[profileID] = pending
+		go s.runItemSyncs(profileID)
+	}
+	pending[itemID] = struct{}{}
+	return nil
+}
+
+// SyncItem syncs a single library item of a profile right away and returns the outcome, e.g. to
+// debug a problem book without processing the whole library. Unlike EnqueueItemSync it doesn't
+// wait for a running sync of the profile but fails with ErrSyncInProgress.
+func (s *MultiUserService) SyncItem(ctx context.Context, profileID, itemID string) (*sync.SyncSummary, error) {
+	profileConfig, err := s.GetProfile(profileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+	if profileConfig == nil {
+		return nil, fmt.Errorf("profile %s not found", profileID)
+	}
+	if until, paused := s.authBackoffUntil(profileID); paused {
+		return nil, fmt.Errorf("%w until %s", sync.ErrAuthBackoff, until.Format(time.RFC3339))
+	}
+
+	s.syncMutex.Lock()
+	if _, exists := s.activeSyncs[profileID]; exists {
+		s.syncMutex.Unlock()
+		return nil, fmt.Errorf("%w for profile %s", ErrSyncInProgress, profileID)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	s.activeSyncs[profileID] = cancel
+	s.syncMutex.Unlock()
+	defer func() {
+		cancel()
+		s.syncMutex.Lock()
+		delete(s.activeSyncs, profileID)
+		s.syncMutex.Unlock()
+	}()
+
+	syncService, _, err := s.newSyncService(profileID, profileConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sync service: %w", err)
+	}
+
+	err = syncService.SyncItem(ctx, itemID)
+	if errors.Is(err, sync.ErrAuthBackoff) {
+		s.authMutex.Lock()
+		s.authBackoffs[profileID] = syncService.AuthBackoffUntil()
+		s.authMutex.Unlock()
+	}
+	return syncService.GetSummary(), err
+}
+
+// runItemSyncs syncs the items queued for a profile until none are left
+func (s *MultiUserService) runItemSyncs(profileID string) {
+	for {
+		ctx, release := s.claimSync(profileID)
+		itemIDs := s.takePendingItems(profileID)
+		if len(itemIDs) == 0 {
+			release()
+			return
+		}
+		s.syncItems(ctx, profileID, itemIDs)
+		release()
+	}
+}
+
+// claimSync waits until the profile isn't syncing and marks it as syncing, so that full syncs
+// and item syncs of a profile never write its sync state at the same time. The returned function
+// must be called once the item syncs are done.
+func (s *MultiUserService) claimSync(profileID string) (context.Context, func()) {
+	for {
+		s.syncMutex.Lock()
+		if _, exists := s.activeSyncs[profileID]; !exists {
+			ctx, cancel := context.WithCancel(context.Background())
+			s.activeSyncs[profileID] = cancel
+			s.syncMutex.Unlock()
+			return ctx, func() {
+				cancel()
+				s.syncMutex.Lock()
+				delete(s.activeSyncs, profileID)
+				s.syncMutex.Unlock()
+			}
+		}
+		s.syncMutex.Unlock()
+		time.Sleep(itemSyncWait)
+	}
+}
+
+// takePendingItems returns the items queued for a profile in a stable order and clears the queue.
+// Once the queue is empty the profile's worker is done, so the next item queued starts a new one.
+func (s *MultiUserService) takePendingItems(profileID string) []string {
+	s.itemsMutex.Lock()
+	defer s.itemsMutex.Unlock()
+
+	pending := s.pendingItems[profileID]
+	if len(pending) == 0 {
+		delete(s.pendingItems, profileID)
+		return nil
+	}
+
+	itemIDs := make([]string, 0, len(pending))
+	for itemID := range pending {
+		itemIDs = append(itemIDs, itemID)
+	}
+	sort.Strings(itemIDs)
+	s.pendingItems[profileID] = make(map[string]struct{})
+	return itemIDs
+}
+
+// syncItems syncs the given library items of a profile with a sync service of their own
+func (s *MultiUserService) syncItems(ctx context.Context, profileID string, itemIDs []string) {
+	profileConfig, err := s.GetProfile(profileID)
+	if err == nil && profileConfig == nil {
+		err = fmt.Errorf("profile %s not found", profileID)
+	}
+	if err != nil {
+		s.logger.Error("Failed to get profile for item sync", map[string]interface{}{
+			"profileID": profileID,
+			"error":     err.Error(),
+		})
+		return
+	}
+
+	syncService, _, err := s.newSyncService(profileID, profileConfig)
+	if err != nil {
+		s.logger.Error("Failed to create sync service for item sync", map[string]interface{}{
+			"profileID": profileID,
+			"error":     err.Error(),
+		})
+		return
+	}
+
+	for _, itemID := range itemIDs {
+		if err := syncService.SyncItem(ctx, itemID); err != nil {
+			s.logger.Error("Item sync failed", map[string]interface{}{
+				"profileID": profileID,
+				"item_id":   itemID,
+				"error":     err.Error(),
+			})
+			if errors.Is(err, sync.ErrAuthBackoff) {
+				s.authMutex.Lock()
+				s.authBackoffs[profileID] = syncService.AuthBackoffUntil()
+				s.authMutex.Unlock()
+			}
+			if authFailed(err) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package multiuser
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnqueueItemSync(t *testing.T) {
+	absServer := newTestAudiobookshelfServer(t)
+	hcServer, mutations := newTestHardcoverServer(t)
+	svc := newTestMultiUserService(t, hcServer.URL)
+	itemSyncWait = 10 * time.Millisecond
+
+	syncConfig := database.SyncConfigData{SyncInterval: "1h", SyncWantToRead: true}
+	require.NoError(t, svc.CreateProfile("alice", "Alice", absServer.URL, "abs-token", "hc-token", syncConfig))
+	assert.Error(t, svc.EnqueueItemSync("bob", "item1"), "unknown profiles can't be synced")
+
+	// Items queued while the profile syncs wait for the sync to finish
+	ctx, release := svc.claimSync("alice")
+	require.NoError(t, svc.EnqueueItemSync("alice", "item1"))
+	require.NoError(t, svc.EnqueueItemSync("alice", "item1"))
+	time.Sleep(5 * itemSyncWait)
+	assert.Empty(t, mutations())
+	assert.NoError(t, ctx.Err())
+	release()
+
+	require.Eventually(t, func() bool {
+		svc.itemsMutex.Lock()
+		defer svc.itemsMutex.Unlock()
+		_, running := svc.pendingItems["alice"]
+		return !running && !svc.IsProfileSyncing("alice")
+	}, 30*time.Second, 10*time.Millisecond)
+	assert.NotEmpty(t, mutations(), "the item's progress is synced to Hardcover")
+}
+
+func TestSyncItem(t *testing.T) {
+	absServer := newTestAudiobookshelfServer(t)
+	hcServer, mutations := newTestHardcoverServer(t)
+	svc := newTestMultiUserService(t, hcServer.URL)
+
+	syncConfig := database.SyncConfigData{SyncInterval: "1h", SyncWantToRead: true}
+	require.NoError(t, svc.CreateProfile("alice", "Alice", absServer.URL, "abs-token", "hc-token", syncConfig))
+
+	_, err := svc.SyncItem(context.Background(), "bob", "item1")
+	assert.Error(t, err, "unknown profiles can't be synced")
+
+	_, release := svc.claimSync("alice")
+	_, err = svc.SyncItem(context.Background(), "alice", "item1")
+	assert.ErrorIs(t, err, ErrSyncInProgress)
+	release()
+
+	// The test server doesn't return the user book it's asked to create, so updating the item's
+	// status fails once the book was added; the outcome is returned along with the error
+	summary, err := svc.SyncItem(context.Background(), "alice", "item1")
+	assert.Error(t, err)
+	require.NotNil(t, summary)
+	assert.Equal(t, int32(1), summary.TotalBooksProcessed)
+	assert.NotEmpty(t, mutations(), "the item is synced to Hardcover")
+	assert.False(t, svc.IsProfileSyncing("alice"))
+}
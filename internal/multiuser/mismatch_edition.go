@@ -0,0 +1,38 @@
+package multiuser
+
+import (
+	"context"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/edition"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/sync"
+)
+
+// CreateMismatchEdition creates the missing audiobook edition of a mismatch with the Hardcover
+// account of the profile whose last sync recorded it, and clears the mismatch. It fails with
+// sync.ErrMismatchNotFound if no profile's last sync recorded it, and with ErrSyncInProgress while
+// that profile is syncing.
+func (s *MultiUserService) CreateMismatchEdition(ctx context.Context, mismatchID string) (*edition.EditionResult, error) {
+	s.servicesMutex.RLock()
+	var profileID string
+	var service *sync.Service
+	for id, svc := range s.syncServices {
+		if svc != nil && svc.HasMismatch(mismatchID) {
+			profileID, service = id, svc
+			break
+		}
+	}
+	s.servicesMutex.RUnlock()
+
+	if service == nil {
+		return nil, sync.ErrMismatchNotFound
+	}
+	if s.IsProfileSyncing(profileID) {
+		return nil, ErrSyncInProgress
+	}
+
+	s.logger.Info("Creating edition for mismatch", map[string]interface{}{
+		"profile_id":  profileID,
+		"mismatch_id": mismatchID,
+	})
+	return service.CreateMismatchEdition(ctx, mismatchID)
+}
@@ -2,6 +2,7 @@ package multiuser
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	stdSync "sync"
 	"time"
@@ -13,6 +14,7 @@ import (
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/mismatch"
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/sync"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/util"
 )
 
 // SyncProfileStatus represents the sync status for a profile
@@ -28,6 +30,8 @@ type SyncProfileStatus struct {
 	BooksNotFound      []sync.BookNotFoundInfo `json:"books_not_found,omitempty"`
 	Mismatches         []mismatch.BookMismatch `json:"mismatches,omitempty"`
 	LastSyncSummary    *sync.SyncSummary       `json:"last_sync_summary,omitempty"`
+	AuthBackoffUntil   *time.Time              `json:"auth_backoff_until,omitempty"` // Syncs are paused until then after repeated auth failures
+	DryRun             bool                    `json:"dry_run,omitempty"`            // The sync runs as a read-only preview without changes in Hardcover
 }
 
 // MultiUserService manages sync operations for multiple users
@@ -41,6 +45,14 @@ type MultiUserService struct {
 	syncMutex       stdSync.RWMutex
 	syncServices    map[string]*sync.Service // Maps profile ID to its sync service
 	servicesMutex   stdSync.RWMutex
+	authBackoffs    map[string]time.Time // Maps profile ID to the time its syncs are paused until after auth failures
+	authMutex       stdSync.Mutex
+	sharedLimiter   *util.RateLimiter // Rate limiter shared by all profiles' Hardcover clients when RateLimit.Shared is set
+	limiterOnce     stdSync.Once
+	pendingItems    map[string]map[string]struct{} // Maps profile ID to the library items waiting for a targeted sync
+	itemsMutex      stdSync.Mutex
+	absClients      map[string]*cachedABSClient // Maps profile ID to its Audiobookshelf client, reused across sync runs
+	absMutex        stdSync.Mutex
 }
 
 // NewMultiUserService creates a new multi-user service
@@ -52,6 +64,9 @@ func NewMultiUserService(repo *database.Repository, globalConfig *config.Config,
 		profileStatuses: make(map[string]*SyncProfileStatus),
 		activeSyncs:     make(map[string]context.CancelFunc),
 		syncServices:    make(map[string]*sync.Service),
+		authBackoffs:    make(map[string]time.Time),
+		pendingItems:    make(map[string]map[string]struct{}),
+		absClients:      make(map[string]*cachedABSClient),
 	}
 }
 
@@ -75,9 +90,23 @@ func (s *MultiUserService) UpdateProfile(profileID, name string) error {
 	return s.repository.UpdateProfile(profileID, name)
 }
 
+// SetProfileDryRun enables or disables the read-only preview mode of a profile, in which its syncs
+// run in dry-run mode whatever the global and profile sync config say
+func (s *MultiUserService) SetProfileDryRun(profileID string, dryRun bool) error {
+	return s.repository.SetProfileDryRun(profileID, dryRun)
+}
+
 // UpdateProfileConfig updates profile configuration
 func (s *MultiUserService) UpdateProfileConfig(profileID, audiobookshelfURL, audiobookshelfToken, hardcoverToken string, syncConfig database.SyncConfigData) error {
-	return s.repository.UpdateUserConfig(profileID, audiobookshelfURL, audiobookshelfToken, hardcoverToken, syncConfig)
+	if err := s.repository.UpdateUserConfig(profileID, audiobookshelfURL, audiobookshelfToken, hardcoverToken, syncConfig); err != nil {
+		return err
+	}
+
+	// New credentials may fix the authentication failures, so don't keep the profile paused
+	s.authMutex.Lock()
+	delete(s.authBackoffs, profileID)
+	s.authMutex.Unlock()
+	return nil
 }
 
 // DeleteProfile deletes a sync profile
@@ -94,6 +123,7 @@ func (s *MultiUserService) DeleteProfile(profileID string) error {
 	s.statusMutex.Lock()
 	delete(s.profileStatuses, profileID)
 	s.statusMutex.Unlock()
+	s.forgetAudiobookshelfClient(profileID)
 	
 	return s.repository.DeleteProfile(profileID)
 }
@@ -123,14 +153,15 @@ func (s *MultiUserService) GetAllProfileStatuses() ([]*SyncProfileStatus, error)
 		} else {
 			// Ensure we return a copy to avoid race conditions
 			status = &SyncProfileStatus{
-				ProfileID:   status.ProfileID,
-				ProfileName: status.ProfileName,
-				Status:      status.Status,
-				LastSync:    status.LastSync,
-				Error:       status.Error,
-				Progress:    status.Progress,
-				BooksTotal:  status.BooksTotal,
-				BooksSynced: status.BooksSynced,
+				ProfileID:        status.ProfileID,
+				ProfileName:      status.ProfileName,
+				Status:           status.Status,
+				LastSync:         status.LastSync,
+				Error:            status.Error,
+				Progress:         status.Progress,
+				BooksTotal:       status.BooksTotal,
+				BooksSynced:      status.BooksSynced,
+				AuthBackoffUntil: status.AuthBackoffUntil,
 			}
 		}
 
@@ -243,6 +274,19 @@ func (s *MultiUserService) StartSync(profileID string) error {
         return fmt.Errorf("failed to get profile config: %w", err)
     }
 
+    // Don't start syncs while paused after repeated authentication failures
+    if until, paused := s.authBackoffUntil(profileID); paused {
+        err := fmt.Errorf("%w until %s", sync.ErrAuthBackoff, until.Format(time.RFC3339))
+        s.updateProfileStatus(profileID, &SyncProfileStatus{
+            ProfileID:        profileID,
+            ProfileName:      profileConfig.Profile.Name,
+            Status:           "error",
+            Error:            err.Error(),
+            AuthBackoffUntil: timePtr(until),
+        })
+        return err
+    }
+
     // Create cancellable context and store cancel
     ctx, cancel := context.WithCancel(context.Background())
     s.activeSyncs[profileID] = cancel
@@ -254,6 +298,7 @@ func (s *MultiUserService) StartSync(profileID string) error {
         Status:      "syncing",
         LastSync:    nil,
         Progress:    "Starting sync...",
+        DryRun:      profileConfig.Profile.DryRun,
     })
 
     // Start the sync in background
@@ -292,6 +337,15 @@ func (s *MultiUserService) CancelSync(profileID string) error {
     return nil
 }
 
+// sharedRateLimiter returns the rate limiter shared by all profiles' Hardcover clients, creating
+// it from the client config on first use
+func (s *MultiUserService) sharedRateLimiter(hcCfg *hardcover.ClientConfig) *util.RateLimiter {
+    s.limiterOnce.Do(func() {
+        s.sharedLimiter = util.NewRateLimiter(hcCfg.RateLimit, hcCfg.Burst, hcCfg.MaxConcurrent, s.logger)
+    })
+    return s.sharedLimiter
+}
+
 // performSync performs the actual sync operation for a profile
 func (s *MultiUserService) performSync(ctx context.Context, profileID string, profileConfig *database.ProfileWithTokens) {
     // Ensure the active sync marker is cleared when this sync finishes
@@ -300,41 +354,8 @@ func (s *MultiUserService) performSync(ctx context.Context, profileID string, pr
         delete(s.activeSyncs, profileID)
         s.syncMutex.Unlock()
     }()
-    // Create profile-specific config
-    config := s.createProfileSpecificConfig(profileConfig)
-
-    // Create clients
-    absClient := audiobookshelf.NewClient(profileConfig.AudiobookshelfURL, profileConfig.AudiobookshelfToken)
 
-    // Build Hardcover client config using global settings (rate limits/base URL)
-    hcCfg := hardcover.DefaultClientConfig()
-    if s.globalConfig != nil {
-        if s.globalConfig.Hardcover.BaseURL != "" {
-            hcCfg.BaseURL = s.globalConfig.Hardcover.BaseURL
-        }
-        if s.globalConfig.RateLimit.Rate > 0 {
-            hcCfg.RateLimit = s.globalConfig.RateLimit.Rate
-        }
-        if s.globalConfig.RateLimit.Burst > 0 {
-            hcCfg.Burst = s.globalConfig.RateLimit.Burst
-        }
-        if s.globalConfig.RateLimit.MaxConcurrent > 0 {
-            hcCfg.MaxConcurrent = s.globalConfig.RateLimit.MaxConcurrent
-        }
-    }
-
-    s.logger.Debug("Initializing Hardcover client (multi-user)", map[string]interface{}{
-        "profile_id":     profileID,
-        "base_url":       hcCfg.BaseURL,
-        "rate_limit":     hcCfg.RateLimit.String(),
-        "burst":          hcCfg.Burst,
-        "max_concurrent": hcCfg.MaxConcurrent,
-    })
-
-    hcClient := hardcover.NewClientWithConfig(hcCfg, profileConfig.HardcoverToken, s.logger)
-
-    // Create sync service
-    syncService, err := sync.NewService(absClient, hcClient, config)
+    syncService, config, err := s.newSyncService(profileID, profileConfig)
     if err != nil {
         s.updateProfileStatus(profileID, &SyncProfileStatus{
             ProfileID:   profileID,
@@ -346,9 +367,7 @@ func (s *MultiUserService) performSync(ctx context.Context, profileID string, pr
     }
 
     // Store the sync service for status access
-    s.servicesMutex.Lock()
-    s.syncServices[profileID] = syncService
-    s.servicesMutex.Unlock()
+    s.setSyncService(profileID, syncService)
     defer func() {
         s.servicesMutex.Lock()
         delete(s.syncServices, profileID)
@@ -358,6 +377,15 @@ func (s *MultiUserService) performSync(ctx context.Context, profileID string, pr
     // Run the sync
     err = syncService.Sync(ctx)
 
+    // The tokens may have been rotated while the sync was running, so try once more with the
+    // ones currently stored before giving up on authentication
+    if err != nil && config.Sync.ReloadTokenOnAuthFailure && authFailed(err) && ctx.Err() == nil {
+        if retryService, retryConfig, ok := s.reloadSyncService(profileID, profileConfig, err); ok {
+            syncService, config = retryService, retryConfig
+            err = syncService.Sync(ctx)
+        }
+    }
+
     // Obtain summary
     summary := syncService.GetSummary()
 
@@ -366,6 +394,7 @@ func (s *MultiUserService) performSync(ctx context.Context, profileID string, pr
         ProfileID:   profileID,
         ProfileName: profileConfig.Profile.Name,
         LastSync:    timePtr(time.Now()),
+        DryRun:      config.Sync.DryRun,
     }
 
     if err != nil {
@@ -375,6 +404,15 @@ func (s *MultiUserService) performSync(ctx context.Context, profileID string, pr
             "profileID": profileID,
             "error":     err,
         })
+
+        // Sync services are created per run, so remember the pause for the next periodic attempt
+        if errors.Is(err, sync.ErrAuthBackoff) {
+            until := syncService.AuthBackoffUntil()
+            s.authMutex.Lock()
+            s.authBackoffs[profileID] = until
+            s.authMutex.Unlock()
+            status.AuthBackoffUntil = timePtr(until)
+        }
     } else {
         status.Status = "completed"
         status.Progress = "Sync completed successfully"
@@ -419,6 +457,108 @@ func (s *MultiUserService) performSync(ctx context.Context, profileID string, pr
     s.statusMutex.Unlock()
 }
 
+// newSyncService creates the clients and sync service of a profile's sync run, returning the
+// profile-specific config along with it
+func (s *MultiUserService) newSyncService(profileID string, profileConfig *database.ProfileWithTokens) (*sync.Service, *config.Config, error) {
+    // Create profile-specific config
+    config := s.createProfileSpecificConfig(profileConfig)
+
+    // Create clients, reusing the profile's Audiobookshelf client of earlier runs
+    absClient := s.audiobookshelfClient(profileID, profileConfig.AudiobookshelfURL, profileConfig.AudiobookshelfToken)
+
+    // Build Hardcover client config using global settings (rate limits/base URL)
+    hcCfg := hardcover.DefaultClientConfig()
+    if s.globalConfig != nil {
+        if s.globalConfig.Hardcover.BaseURL != "" {
+            hcCfg.BaseURL = s.globalConfig.Hardcover.BaseURL
+        }
+        hcCfg.AllowedOperations = s.globalConfig.Hardcover.AllowedOperations
+        hcCfg.DisabledOperations = s.globalConfig.Hardcover.DisabledOperations
+        if s.globalConfig.RateLimit.Rate > 0 {
+            hcCfg.RateLimit = s.globalConfig.RateLimit.Rate
+        }
+        if s.globalConfig.RateLimit.Burst > 0 {
+            hcCfg.Burst = s.globalConfig.RateLimit.Burst
+        }
+        if s.globalConfig.RateLimit.MaxConcurrent > 0 {
+            hcCfg.MaxConcurrent = s.globalConfig.RateLimit.MaxConcurrent
+        }
+        if s.globalConfig.RateLimit.Shared {
+            hcCfg.RateLimiter = s.sharedRateLimiter(hcCfg)
+        }
+    }
+
+    s.logger.Debug("Initializing Hardcover client (multi-user)", map[string]interface{}{
+        "profile_id":     profileID,
+        "base_url":       hcCfg.BaseURL,
+        "rate_limit":     hcCfg.RateLimit.String(),
+        "burst":          hcCfg.Burst,
+        "max_concurrent": hcCfg.MaxConcurrent,
+        "shared_limiter": hcCfg.RateLimiter != nil,
+    })
+
+    hcClient := hardcover.NewClientWithConfig(hcCfg, profileConfig.HardcoverToken, s.logger)
+
+    // Create sync service
+    syncService, err := sync.NewService(absClient, hcClient, config)
+    if err != nil {
+        return nil, nil, err
+    }
+    syncService.SetUserID(profileID)
+    return syncService, config, nil
+}
+
+// reloadSyncService reloads a profile's tokens after its sync failed with the authentication
+// error syncErr, and returns a new sync service using them if they differ from the ones in
+// profileConfig the sync ran with
+func (s *MultiUserService) reloadSyncService(profileID string, profileConfig *database.ProfileWithTokens, syncErr error) (*sync.Service, *config.Config, bool) {
+    reloaded, err := s.GetProfile(profileID)
+    if err != nil {
+        s.logger.Warn("Failed to reload profile tokens after authentication failure", map[string]interface{}{
+            "profileID": profileID,
+            "error":     err.Error(),
+        })
+        return nil, nil, false
+    }
+    if reloaded.HardcoverToken == profileConfig.HardcoverToken &&
+        reloaded.AudiobookshelfToken == profileConfig.AudiobookshelfToken &&
+        reloaded.AudiobookshelfURL == profileConfig.AudiobookshelfURL {
+        s.logger.Debug("Profile tokens unchanged after authentication failure, not retrying sync", map[string]interface{}{
+            "profileID": profileID,
+        })
+        return nil, nil, false
+    }
+
+    syncService, config, err := s.newSyncService(profileID, reloaded)
+    if err != nil {
+        s.logger.Warn("Failed to create sync service with reloaded profile tokens", map[string]interface{}{
+            "profileID": profileID,
+            "error":     err.Error(),
+        })
+        return nil, nil, false
+    }
+
+    s.logger.Info("Profile tokens changed during sync, retrying after authentication failure", map[string]interface{}{
+        "profileID": profileID,
+        "error":     syncErr.Error(),
+    })
+    s.setSyncService(profileID, syncService)
+    return syncService, config, true
+}
+
+// setSyncService stores the sync service of a profile's running sync for status access
+func (s *MultiUserService) setSyncService(profileID string, syncService *sync.Service) {
+    s.servicesMutex.Lock()
+    defer s.servicesMutex.Unlock()
+    s.syncServices[profileID] = syncService
+}
+
+// authFailed reports whether a sync failed because Hardcover or Audiobookshelf rejected the
+// profile's tokens
+func authFailed(err error) bool {
+    return errors.Is(err, sync.ErrAuthBackoff) || hardcover.IsAuthError(err) || errors.Is(err, audiobookshelf.ErrUnauthorized)
+}
+
 // createProfileSpecificConfig creates a config.Config instance for a specific profile
 func (s *MultiUserService) createProfileSpecificConfig(profileConfig *database.ProfileWithTokens) *config.Config {
 	// Create a copy of the global config
@@ -453,10 +593,32 @@ func (s *MultiUserService) createProfileSpecificConfig(profileConfig *database.P
 		config.Sync.SyncOwned = syncConfig.SyncOwned
 		config.Sync.DryRun = syncConfig.DryRun
 	}
+
+	// The profile's read-only preview mode overrides the global and profile sync config
+	if profileConfig.Profile.DryRun {
+		config.Sync.DryRun = true
+	}
 	
 	return &config
 }
 
+// authBackoffUntil returns the time until which syncs for a profile are paused after repeated
+// authentication failures, and whether that time is still in the future
+func (s *MultiUserService) authBackoffUntil(profileID string) (time.Time, bool) {
+	s.authMutex.Lock()
+	defer s.authMutex.Unlock()
+
+	until, exists := s.authBackoffs[profileID]
+	if !exists {
+		return time.Time{}, false
+	}
+	if !time.Now().Before(until) {
+		delete(s.authBackoffs, profileID)
+		return time.Time{}, false
+	}
+	return until, true
+}
+
 // updateProfileStatus updates the status for a profile
 func (s *MultiUserService) updateProfileStatus(profileID string, status *SyncProfileStatus) {
 	s.statusMutex.Lock()
@@ -0,0 +1,226 @@
+package multiuser
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"path/filepath"
+	"strings"
+	stdSync "sync"
+	"testing"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/crypto"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/database"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestAudiobookshelfServer serves a single library with one audiobook that is half listened to
+func newTestAudiobookshelfServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/libraries":
+			_, _ = w.Write([]byte(`{"libraries":[{"id":"lib1","name":"Audiobooks","mediaType":"book"}]}`))
+		case r.URL.Path == "/api/libraries/lib1/items":
+			_, _ = w.Write([]byte(`{"results":[{"id":"item1","libraryId":"lib1","mediaType":"book",` +
+				`"media":{"duration":3600,"metadata":{"title":"Preview Audiobook","authorName":"Test Author","asin":"B0PREVIEW1"}},` +
+				`"progress":{"currentTime":1800,"startedAt":1700000000000,"lastUpdate":1700000000000}}]}`))
+		case r.URL.Path == "/api/items/item1":
+			_, _ = w.Write([]byte(`{"id":"item1","libraryId":"lib1","mediaType":"book",` +
+				`"media":{"duration":3600,"metadata":{"title":"Preview Audiobook","authorName":"Test Author","asin":"B0PREVIEW1"}},` +
+				`"userMediaProgress":{"currentTime":1800,"startedAt":1700000000000,"lastUpdate":1700000000000}}`))
+		case r.URL.Path == "/api/me":
+			_, _ = w.Write([]byte(`{"id":"user1","mediaProgress":[{"libraryItemId":"item1","progress":0.5,` +
+				`"currentTime":1800,"duration":3600,"startedAt":1700000000000,"lastUpdate":1700000000000}]}`))
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newTestHardcoverServer resolves the test audiobook's ASIN and records the mutations it receives
+func newTestHardcoverServer(t *testing.T) (*httptest.Server, func() []string) {
+	t.Helper()
+	var mu stdSync.Mutex
+	var mutations []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hardcover.HandleGetCurrentUserIDQuery(t, w, r) {
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var req struct {
+			Query string `json:"query"`
+		}
+		_ = json.Unmarshal(body, &req)
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasPrefix(strings.TrimSpace(req.Query), "mutation"):
+			mu.Lock()
+			mutations = append(mutations, req.Query)
+			mu.Unlock()
+			_, _ = w.Write([]byte(`{"data":{}}`))
+		case strings.Contains(req.Query, "BookByASIN"):
+			_, _ = w.Write([]byte(`{"data":{"books":[{"id":10,"title":"Preview Audiobook","editions":[` +
+				`{"id":100,"asin":"B0PREVIEW1","reading_format_id":2,"audio_seconds":3600}]}]}}`))
+		case strings.Contains(req.Query, "GetEdition"):
+			_, _ = w.Write([]byte(`{"data":{"editions":[{"id":100,"book_id":10,"title":"Preview Audiobook","asin":"B0PREVIEW1"}]}}`))
+		default:
+			_, _ = w.Write([]byte(`{"data":{}}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), mutations...)
+	}
+}
+
+// newTestMultiUserService returns a service backed by a fresh database whose Hardcover clients use
+// the given server
+func newTestMultiUserService(t *testing.T, hardcoverURL string) *MultiUserService {
+	t.Helper()
+	// Sync state, caches and Audiobookshelf response dumps are written relative to the working directory
+	t.Chdir(t.TempDir())
+	log := logger.Get()
+
+	db, err := database.NewDatabase(&database.DatabaseConfig{
+		Type: database.DatabaseTypeSQLite,
+		Path: filepath.Join(t.TempDir(), "test.db"),
+	}, log)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	encryptor, err := crypto.NewEncryptionManagerWithKey(bytes.Repeat([]byte("k"), 32), log)
+	require.NoError(t, err)
+
+	cfg := config.DefaultConfig()
+	cfg.Hardcover.BaseURL = hardcoverURL
+	cfg.RateLimit.Rate = time.Millisecond
+	return NewMultiUserService(database.NewRepository(db, encryptor, log), cfg, log)
+}
+
+// waitForSync waits until the profile's sync has finished and returns its final status
+func waitForSync(t *testing.T, svc *MultiUserService, profileID string) *SyncProfileStatus {
+	t.Helper()
+	require.Eventually(t, func() bool { return !svc.IsProfileSyncing(profileID) }, 30*time.Second, 10*time.Millisecond)
+	return svc.GetProfileStatus(profileID)
+}
+
+func TestStartSync_ProfileDryRun(t *testing.T) {
+	tests := []struct {
+		name          string
+		dryRun        bool
+		wantMutations bool
+	}{
+		{name: "regular sync updates Hardcover", dryRun: false, wantMutations: true},
+		{name: "read-only preview makes no changes", dryRun: true, wantMutations: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			absServer := newTestAudiobookshelfServer(t)
+			hcServer, mutations := newTestHardcoverServer(t)
+			svc := newTestMultiUserService(t, hcServer.URL)
+
+			// The profile's own sync config doesn't ask for a dry run
+			syncConfig := database.SyncConfigData{SyncInterval: "1h", SyncWantToRead: true}
+			require.NoError(t, svc.CreateProfile("alice", "Alice", absServer.URL, "abs-token", "hc-token", syncConfig))
+			require.NoError(t, svc.SetProfileDryRun("alice", tt.dryRun))
+
+			profile, err := svc.GetProfile("alice")
+			require.NoError(t, err)
+			assert.Equal(t, tt.dryRun, profile.Profile.DryRun)
+
+			require.NoError(t, svc.StartSync("alice"))
+			status := waitForSync(t, svc, "alice")
+			require.Equal(t, "completed", status.Status, status.Error)
+			assert.Equal(t, tt.dryRun, status.DryRun)
+
+			if tt.wantMutations {
+				assert.NotEmpty(t, mutations())
+			} else {
+				assert.Empty(t, mutations())
+			}
+		})
+	}
+}
+
+// newRejectingHardcoverServer forwards requests to target unless they use the rejected token, which
+// it answers with 401 after calling onReject
+func newRejectingHardcoverServer(t *testing.T, target, rejectedToken string, onReject func()) *httptest.Server {
+	t.Helper()
+	targetURL, err := url.Parse(target)
+	require.NoError(t, err)
+	proxy := httputil.NewSingleHostReverseProxy(targetURL)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer "+rejectedToken {
+			onReject()
+			http.Error(w, `{"error":"Unable to verify token"}`, http.StatusUnauthorized)
+			return
+		}
+		proxy.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestPerformSync_ReloadTokenOnAuthFailure(t *testing.T) {
+	tests := []struct {
+		name         string
+		rotateToken  bool
+		reloadToken  bool
+		wantStatus   string
+		wantMutation bool
+	}{
+		{name: "token rotated mid-run is picked up", rotateToken: true, reloadToken: true, wantStatus: "completed", wantMutation: true},
+		{name: "unchanged token isn't retried", rotateToken: false, reloadToken: true, wantStatus: "error"},
+		{name: "disabled by default", rotateToken: true, reloadToken: false, wantStatus: "error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			absServer := newTestAudiobookshelfServer(t)
+			hcServer, mutations := newTestHardcoverServer(t)
+
+			var svc *MultiUserService
+			syncConfig := database.SyncConfigData{SyncInterval: "1h", SyncWantToRead: true}
+			var rotateOnce stdSync.Once
+			rejecting := newRejectingHardcoverServer(t, hcServer.URL, "old-token", func() {
+				if tt.rotateToken {
+					// Rotate the token out-of-band while the sync is running
+					rotateOnce.Do(func() {
+						require.NoError(t, svc.UpdateProfileConfig("alice", absServer.URL, "abs-token", "new-token", syncConfig))
+					})
+				}
+			})
+
+			svc = newTestMultiUserService(t, rejecting.URL)
+			svc.globalConfig.Sync.AuthFailureThreshold = 1
+			svc.globalConfig.Sync.ReloadTokenOnAuthFailure = tt.reloadToken
+			require.NoError(t, svc.CreateProfile("alice", "Alice", absServer.URL, "abs-token", "old-token", syncConfig))
+
+			require.NoError(t, svc.StartSync("alice"))
+			status := waitForSync(t, svc, "alice")
+			require.Equal(t, tt.wantStatus, status.Status, status.Error)
+			if tt.wantMutation {
+				assert.NotEmpty(t, mutations())
+			} else {
+				assert.Empty(t, mutations())
+			}
+		})
+	}
+}
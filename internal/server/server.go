@@ -12,6 +12,7 @@ import (
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api"
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/auth"
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/metrics"
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/multiuser"
 )
 
@@ -25,6 +26,7 @@ type Server struct {
 	authMiddleware   *auth.AuthMiddleware
 	syncService      api.SyncService
 	logger           *logger.Logger
+	webhookSecret    string
 }
 
 // New creates a new HTTP server with multi-user and authentication support
@@ -53,6 +55,9 @@ func New(addr string, multiUserService *multiuser.MultiUserService, authService
 	
 	// Health check (no auth required)
 	handler.HandleFunc("GET /health", s.handleHealthCheck)
+
+	// Prometheus metrics of sync runs (no auth required, for scrapers)
+	handler.Handle("GET /metrics", metrics.Handler())
 	
 	// Authentication endpoints (no auth required for login)
 	handler.HandleFunc("GET /login", s.authHandlers.HandleLogin)  // Serve login page
@@ -66,6 +71,9 @@ func New(addr string, multiUserService *multiuser.MultiUserService, authService
 	handler.HandleFunc("GET /api/status", s.handleAPIStatus)  // General status check
 	handler.HandleFunc("POST /api/sync", s.handleSync)  // Legacy sync endpoint
 
+	// Audiobookshelf webhooks (verified by their signature instead of auth)
+	handler.HandleFunc("POST /webhooks/audiobookshelf", s.handleAudiobookshelfWebhook)
+
 	// API v1 routes with authentication
 	apiMux := http.NewServeMux()
 	apiMux.HandleFunc("GET /profiles", s.handleAPIProfiles)
@@ -77,7 +85,11 @@ func New(addr string, multiUserService *multiuser.MultiUserService, authService
 	apiMux.HandleFunc("GET /profiles/{id}/status", s.handleAPIProfilesWithID)
 	apiMux.HandleFunc("POST /profiles/{id}/sync", s.handleAPIProfilesWithID)
 	apiMux.HandleFunc("DELETE /profiles/{id}/sync", s.handleAPIProfilesWithID)
+	apiMux.HandleFunc("POST /profiles/{id}/sync/item/{itemId}", s.handleAPIProfilesWithID)
 	apiMux.HandleFunc("GET /profiles/{id}/summary", s.handleAPISummary)  // Add summary endpoint
+	apiMux.HandleFunc("GET /profiles/{id}/export", s.handleAPIProfilesWithID)
+	apiMux.HandleFunc("POST /profiles/{id}/import", s.handleAPIProfilesWithID)
+	apiMux.HandleFunc("POST /mismatches/{id}/create-edition", s.handleAPICreateMismatchEdition)
 
 	// Mount API routes under /api with auth middleware
 	handler.Handle("/api/", s.authMiddleware.RequireAuth(http.StripPrefix("/api", apiMux)))
@@ -220,6 +232,15 @@ func (s *Server) handleAPIProfilesWithID(w http.ResponseWriter, r *http.Request)
 				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 			}
 		case "sync":
+			if len(pathParts) > 3 {
+				// Single item syncs under /profiles/{id}/sync/item/{itemId}
+				if len(pathParts) == 5 && pathParts[3] == "item" && r.Method == http.MethodPost {
+					s.apiHandler.SyncItem(w, r)
+				} else {
+					http.Error(w, "Not found", http.StatusNotFound)
+				}
+				return
+			}
 			switch r.Method {
 			case http.MethodPost:
 				s.apiHandler.StartSync(w, r)
@@ -228,6 +249,18 @@ func (s *Server) handleAPIProfilesWithID(w http.ResponseWriter, r *http.Request)
 			default:
 				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 			}
+		case "export":
+			if r.Method == http.MethodGet {
+				s.apiHandler.ExportProfile(w, r)
+			} else {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+		case "import":
+			if r.Method == http.MethodPost {
+				s.apiHandler.ImportProfile(w, r)
+			} else {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
 		default:
 			http.Error(w, "Not found", http.StatusNotFound)
 		}
@@ -353,6 +386,11 @@ func (s *Server) handleAPISummary(w http.ResponseWriter, r *http.Request) {
 	s.apiHandler.GetSyncSummary(w, r)
 }
 
+// handleAPICreateMismatchEdition handles POST /api/mismatches/{id}/create-edition
+func (s *Server) handleAPICreateMismatchEdition(w http.ResponseWriter, r *http.Request) {
+	s.apiHandler.CreateMismatchEdition(w, r)
+}
+
 // handleStaticFiles serves static web UI files
 func (s *Server) handleStaticFiles(w http.ResponseWriter, r *http.Request) {
 	// Skip if this is an API route
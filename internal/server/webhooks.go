@@ -0,0 +1,178 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/database"
+)
+
+// webhookSignatureHeader carries the hex HMAC-SHA256 of the webhook body, optionally prefixed with
+// "sha256="
+const webhookSignatureHeader = "X-Signature-256"
+
+// maxWebhookBodySize limits the size of webhook payloads read into memory
+const maxWebhookBodySize = 1 << 20
+
+// audiobookshelfEvent holds the fields of an Audiobookshelf webhook payload the sync needs. The
+// fields are read from the top level or from a nested "data" object.
+type audiobookshelfEvent struct {
+	Event         string
+	LibraryItemID string
+	UserID        string
+}
+
+// webhookFields are the keys an Audiobookshelf webhook payload may carry its fields under
+type webhookFields struct {
+	Event         string `json:"event"`
+	LibraryItemID string `json:"libraryItemId"`
+	SnakeItemID   string `json:"library_item_id"`
+	ItemID        string `json:"itemId"`
+	UserID        string `json:"userId"`
+	SnakeUserID   string `json:"user_id"`
+	Username      string `json:"username"`
+}
+
+// SetWebhookSecret sets the secret webhook signatures are verified with. The webhook endpoint
+// rejects all requests while no secret is set.
+func (s *Server) SetWebhookSecret(secret string) {
+	s.webhookSecret = secret
+}
+
+// parseAudiobookshelfEvent extracts the event name, library item and user from a webhook payload
+func parseAudiobookshelfEvent(body []byte) (audiobookshelfEvent, error) {
+	var payload struct {
+		webhookFields
+		Data *webhookFields `json:"data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return audiobookshelfEvent{}, fmt.Errorf("invalid webhook payload: %w", err)
+	}
+
+	fields := []webhookFields{payload.webhookFields}
+	if payload.Data != nil {
+		fields = append(fields, *payload.Data)
+	}
+
+	var event audiobookshelfEvent
+	for _, f := range fields {
+		event.Event = firstNonEmpty(event.Event, f.Event)
+		event.LibraryItemID = firstNonEmpty(event.LibraryItemID, f.LibraryItemID, f.SnakeItemID, f.ItemID)
+		event.UserID = firstNonEmpty(event.UserID, f.UserID, f.SnakeUserID, f.Username)
+	}
+	if event.LibraryItemID == "" {
+		return audiobookshelfEvent{}, errors.New("webhook payload has no library item ID")
+	}
+	return event, nil
+}
+
+// firstNonEmpty returns the first of values that isn't empty
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// validWebhookSignature reports whether signature is the HMAC-SHA256 of body with secret
+func validWebhookSignature(secret string, body []byte, signature string) bool {
+	signature = strings.TrimPrefix(strings.TrimSpace(signature), "sha256=")
+	expected, err := hex.DecodeString(signature)
+	if err != nil || len(expected) == 0 {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// webhookProfile returns the ID of the profile a webhook is for: the "profile" query parameter,
+// else the profile whose ID or name matches the event's user, else the only profile there is
+func webhookProfile(profiles []database.SyncProfile, requested, user string) (string, error) {
+	if requested != "" {
+		return requested, nil
+	}
+	if user != "" {
+		for _, profile := range profiles {
+			if profile.ID == user || strings.EqualFold(profile.Name, user) {
+				return profile.ID, nil
+			}
+		}
+	}
+	if len(profiles) == 1 {
+		return profiles[0].ID, nil
+	}
+	return "", errors.New("can't tell which profile the webhook is for, set the profile query parameter")
+}
+
+// handleAudiobookshelfWebhook queues a sync of the library item an Audiobookshelf event reports
+// progress on, so the progress reaches Hardcover without waiting for the next full sync
+func (s *Server) handleAudiobookshelfWebhook(w http.ResponseWriter, r *http.Request) {
+	if s.webhookSecret == "" {
+		http.Error(w, "Webhooks are disabled, set webhooks.secret to enable them", http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBodySize))
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+	if !validWebhookSignature(s.webhookSecret, body, r.Header.Get(webhookSignatureHeader)) {
+		s.logger.Warn("Rejected Audiobookshelf webhook with an invalid signature", map[string]interface{}{
+			"remote_addr": r.RemoteAddr,
+		})
+		http.Error(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	event, err := parseAudiobookshelfEvent(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	profiles, err := s.multiUserService.ListProfiles()
+	if err != nil {
+		http.Error(w, "Failed to list profiles", http.StatusInternalServerError)
+		return
+	}
+	profileID, err := webhookProfile(profiles, r.URL.Query().Get("profile"), event.UserID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.multiUserService.EnqueueItemSync(profileID, event.LibraryItemID); err != nil {
+		s.logger.Warn("Failed to queue item sync from Audiobookshelf webhook", map[string]interface{}{
+			"profile_id": profileID,
+			"item_id":    event.LibraryItemID,
+			"error":      err.Error(),
+		})
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	s.logger.Info("Queued item sync from Audiobookshelf webhook", map[string]interface{}{
+		"event":      event.Event,
+		"profile_id": profileID,
+		"item_id":    event.LibraryItemID,
+		"user":       event.UserID,
+	})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"status":     "queued",
+		"profile_id": profileID,
+		"item_id":    event.LibraryItemID,
+	})
+}
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidWebhookSignature(t *testing.T) {
+	body := []byte(`{"libraryItemId":"li_1"}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	assert.True(t, validWebhookSignature("secret", body, signature))
+	assert.True(t, validWebhookSignature("secret", body, "sha256="+signature))
+	assert.False(t, validWebhookSignature("other", body, signature))
+	assert.False(t, validWebhookSignature("secret", []byte(`{"libraryItemId":"li_2"}`), signature))
+	assert.False(t, validWebhookSignature("secret", body, ""))
+	assert.False(t, validWebhookSignature("secret", body, "not-hex"))
+}
+
+func TestParseAudiobookshelfEvent(t *testing.T) {
+	event, err := parseAudiobookshelfEvent([]byte(`{"event":"onPlaybackProgress","libraryItemId":"li_1","userId":"u_1"}`))
+	require.NoError(t, err)
+	assert.Equal(t, audiobookshelfEvent{Event: "onPlaybackProgress", LibraryItemID: "li_1", UserID: "u_1"}, event)
+
+	event, err = parseAudiobookshelfEvent([]byte(`{"event":"onPlaybackProgress","data":{"library_item_id":"li_2","username":"alice"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, audiobookshelfEvent{Event: "onPlaybackProgress", LibraryItemID: "li_2", UserID: "alice"}, event)
+
+	_, err = parseAudiobookshelfEvent([]byte(`{"event":"onTest"}`))
+	assert.Error(t, err, "events without a library item can't be synced")
+	_, err = parseAudiobookshelfEvent([]byte(`not json`))
+	assert.Error(t, err)
+}
+
+func TestWebhookProfile(t *testing.T) {
+	profiles := []database.SyncProfile{{ID: "p1", Name: "Alice"}, {ID: "p2", Name: "Bob"}}
+
+	profileID, err := webhookProfile(profiles, "p2", "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "p2", profileID, "the profile query parameter wins")
+
+	profileID, err = webhookProfile(profiles, "", "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "p1", profileID)
+
+	_, err = webhookProfile(profiles, "", "carol")
+	assert.Error(t, err)
+
+	profileID, err = webhookProfile(profiles[:1], "", "carol")
+	require.NoError(t, err)
+	assert.Equal(t, "p1", profileID, "a single profile takes all webhooks")
+}
@@ -0,0 +1,128 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// statusDidNotFinishID is the Hardcover status ID of Did Not Finish
+const statusDidNotFinishID = 5
+
+// bookStatus returns the status a book is synced with. Books carrying Sync.AbandonedTag are Did Not
+// Finish whatever their progress; otherwise determineBookStatus decides.
+func (s *Service) bookStatus(book models.AudiobookshelfBook, progress float64) string {
+	if s.isAbandoned(book) {
+		return "DID_NOT_FINISH"
+	}
+	return s.determineBookStatus(progress, book.Progress.IsFinished, book.Progress.FinishedAt)
+}
+
+// isAbandoned reports whether the book carries Sync.AbandonedTag, ignoring case
+func (s *Service) isAbandoned(book models.AudiobookshelfBook) bool {
+	tag := strings.TrimSpace(s.config.Sync.AbandonedTag)
+	if tag == "" {
+		return false
+	}
+	for _, bookTag := range book.Media.Tags {
+		if strings.EqualFold(strings.TrimSpace(bookTag), tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// handleAbandonedBook marks a user book as Did Not Finish and syncs the progress of its unfinished
+// read. The read is never finished and its progress stays below the duration, so Hardcover doesn't
+// count the book as read.
+func (s *Service) handleAbandonedBook(ctx context.Context, userBookID int64, book models.AudiobookshelfBook, stateKey string) error {
+	log := s.log.With(map[string]interface{}{
+		"function":     "handleAbandonedBook",
+		"user_book_id": userBookID,
+		"book_id":      book.ID,
+		"title":        book.Media.Metadata.Title,
+	})
+
+	progressSeconds := int(math.Round(book.Progress.CurrentTime))
+	if duration := int(book.Media.Duration); duration > 0 && progressSeconds >= duration {
+		progressSeconds = duration - 1
+	}
+
+	if s.dryRun(ctx) {
+		log.Info("[DRY-RUN] Would mark book as Did Not Finish", map[string]interface{}{
+			"progress_seconds": progressSeconds,
+		})
+		s.planAction(ctx, PlannedAction{
+			Action:                PlannedActionMarkDidNotFinish,
+			EditionID:             editionIDFromStateKey(stateKey),
+			UserBookID:            plannedUserBookID(userBookID),
+			Status:                "DID_NOT_FINISH",
+			TargetProgressSeconds: progressSeconds,
+		})
+		return nil
+	}
+
+	if s.config.Sync.ProgressOnly {
+		log.Debug("Progress only mode, not marking book as Did Not Finish", nil)
+	} else if err := s.hardcover.UpdateUserBookStatus(ctx, hardcover.UpdateUserBookStatusInput{
+		ID:       userBookID,
+		StatusID: statusDidNotFinishID,
+	}); err != nil {
+		return fmt.Errorf("failed to mark book as did not finish: %w", err)
+	}
+
+	if progressSeconds <= 0 {
+		log.Info("Marked book as Did Not Finish", nil)
+		return nil
+	}
+
+	reads, err := s.hardcover.GetUserBookReads(ctx, hardcover.GetUserBookReadsInput{
+		UserBookID: userBookID,
+		Status:     "unfinished",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get unfinished reads: %w", err)
+	}
+
+	if len(reads) > 0 {
+		read := reads[0]
+		if read.ProgressSeconds != nil && *read.ProgressSeconds == progressSeconds {
+			log.Info("Marked book as Did Not Finish, progress is up to date", nil)
+			return nil
+		}
+		if _, err := s.hardcover.UpdateUserBookRead(ctx, hardcover.UpdateUserBookReadInput{
+			ID: read.ID,
+			Object: map[string]interface{}{
+				"progress_seconds": progressSeconds,
+			},
+		}); err != nil {
+			return fmt.Errorf("failed to update progress: %w", err)
+		}
+	} else {
+		readingFormatID := s.readingFormatID(ctx, book)
+		datesRead := hardcover.DatesReadInput{
+			ProgressSeconds: &progressSeconds,
+			ReadingFormatID: &readingFormatID,
+		}
+		if book.Progress.StartedAt > 0 {
+			startedAt := time.Unix(book.Progress.StartedAt/1000, 0).Format("2006-01-02")
+			datesRead.StartedAt = &startedAt
+		}
+		if _, err := s.hardcover.InsertUserBookRead(ctx, hardcover.InsertUserBookReadInput{
+			UserBookID: userBookID,
+			DatesRead:  datesRead,
+		}); err != nil {
+			return fmt.Errorf("failed to create read: %w", err)
+		}
+	}
+
+	log.Info("Marked book as Did Not Finish", map[string]interface{}{
+		"progress_seconds": progressSeconds,
+	})
+	return nil
+}
@@ -0,0 +1,87 @@
+package sync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookStatus_AbandonedTag(t *testing.T) {
+	svc, _ := createTestService()
+	book := newTestBook("item-1", "Abandoned Audiobook")
+
+	// Without Sync.AbandonedTag, tags don't matter
+	book.Media.Tags = []string{"DNF"}
+	assert.Equal(t, "IN_PROGRESS", svc.bookStatus(book, 0.5))
+
+	svc.config.Sync.AbandonedTag = "dnf"
+	book.Media.Tags = []string{"Fantasy", "DNF"}
+	assert.Equal(t, "DID_NOT_FINISH", svc.bookStatus(book, 0.5))
+	book.Media.Tags = []string{"Fantasy"}
+	assert.Equal(t, "IN_PROGRESS", svc.bookStatus(book, 0.5))
+
+	// Abandoned books are never finished, whatever their progress
+	finished := newTestBook("item-1", "Abandoned Audiobook")
+	finished.Media.Tags = []string{"dnf"}
+	finished.Progress.CurrentTime = 3600
+	finished.Progress.IsFinished = true
+	finished.Progress.FinishedAt = 1700000000000
+	assert.Equal(t, "DID_NOT_FINISH", svc.bookStatus(finished, 1.0))
+}
+
+func TestHandleAbandonedBook(t *testing.T) {
+	abandoned := newTestBook("item-1", "Abandoned Audiobook")
+	abandoned.Media.Tags = []string{"dnf"}
+
+	t.Run("updates the unfinished read", func(t *testing.T) {
+		svc, mockClient := createTestService()
+		svc.config.Sync.DryRun = false
+
+		progress := 600
+		mockClient.On("UpdateUserBookStatus", mock.Anything, hardcover.UpdateUserBookStatusInput{ID: 42, StatusID: 5}).Return(nil).Once()
+		mockClient.On("GetUserBookReads", mock.Anything, hardcover.GetUserBookReadsInput{UserBookID: 42, Status: "unfinished"}).
+			Return([]hardcover.UserBookRead{{ID: 7, UserBookID: 42, ProgressSeconds: &progress}}, nil).Once()
+		mockClient.On("UpdateUserBookRead", mock.Anything, hardcover.UpdateUserBookReadInput{
+			ID:     7,
+			Object: map[string]interface{}{"progress_seconds": 1800},
+		}).Return(true, nil).Once()
+
+		require.NoError(t, svc.handleAbandonedBook(context.Background(), 42, abandoned, "item-1:100"))
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("creates an unfinished read below the duration", func(t *testing.T) {
+		svc, mockClient := createTestService()
+		svc.config.Sync.DryRun = false
+
+		mockClient.On("UpdateUserBookStatus", mock.Anything, hardcover.UpdateUserBookStatusInput{ID: 42, StatusID: 5}).Return(nil).Once()
+		mockClient.On("GetUserBookReads", mock.Anything, mock.Anything).Return([]hardcover.UserBookRead{}, nil).Once()
+		mockClient.On("InsertUserBookRead", mock.Anything, mock.MatchedBy(func(input hardcover.InsertUserBookReadInput) bool {
+			return input.UserBookID == 42 && input.DatesRead.FinishedAt == nil &&
+				input.DatesRead.ProgressSeconds != nil && *input.DatesRead.ProgressSeconds == 3599
+		})).Return(8, nil).Once()
+
+		book := abandoned
+		book.Progress.CurrentTime = 3600
+		book.Progress.IsFinished = true
+		book.Progress.FinishedAt = 1700000000000
+		require.NoError(t, svc.handleAbandonedBook(context.Background(), 42, book, "item-1:100"))
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("dry run plans the status change", func(t *testing.T) {
+		svc, mockClient := createTestService()
+		svc.config.Sync.DryRun = true
+
+		require.NoError(t, svc.handleAbandonedBook(context.Background(), 42, abandoned, "item-1:100"))
+		require.Len(t, svc.plannedActions, 1)
+		assert.Equal(t, PlannedActionMarkDidNotFinish, svc.plannedActions[0].Action)
+		assert.Equal(t, "100", svc.plannedActions[0].EditionID)
+		assert.Equal(t, 1800, svc.plannedActions[0].TargetProgressSeconds)
+		mockClient.AssertNotCalled(t, "UpdateUserBookStatus", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,153 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/audiobookshelf"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+)
+
+// HardcoverClientFactory creates the Hardcover client for the account the token belongs to
+type HardcoverClientFactory func(token string) hardcover.HardcoverClientInterface
+
+// UserSyncResult is the outcome of syncing a single mapped Audiobookshelf user
+type UserSyncResult struct {
+	// User is the mapping from Audiobookshelf.Users, as configured
+	User string
+	// UserID is the Audiobookshelf user ID, empty if the user wasn't found
+	UserID string
+	// Summary is the user's sync summary, nil if the sync didn't run
+	Summary *SyncSummary
+	// Err is the error the user's sync failed with
+	Err error
+}
+
+// AllUsersSync syncs the progress of every Audiobookshelf user mapped in Audiobookshelf.Users to
+// their own Hardcover account. It needs an Audiobookshelf admin token to enumerate the users and
+// read their progress. Each user gets their own sync service, with their own state file and
+// caches, which is kept across runs.
+type AllUsersSync struct {
+	admin        *audiobookshelf.Client
+	config       *Config
+	newHardcover HardcoverClientFactory
+	services     map[string]*Service
+	log          *logger.Logger
+}
+
+// NewAllUsersSync creates a sync for all mapped Audiobookshelf users
+func NewAllUsersSync(admin *audiobookshelf.Client, cfg *Config, newHardcover HardcoverClientFactory) *AllUsersSync {
+	return &AllUsersSync{
+		admin:        admin,
+		config:       cfg,
+		newHardcover: newHardcover,
+		services:     make(map[string]*Service),
+		log:          logger.Get(),
+	}
+}
+
+// Sync syncs every mapped user in turn and returns one result per mapping. A failing user doesn't
+// stop the others; an error is only returned if the users can't be listed or ctx is canceled.
+// Users are synced one at a time since mismatches are collected globally during a sync.
+func (a *AllUsersSync) Sync(ctx context.Context) ([]UserSyncResult, error) {
+	users, err := a.admin.GetUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Audiobookshelf users: %w", err)
+	}
+
+	results := make([]UserSyncResult, 0, len(a.config.Audiobookshelf.Users))
+	for _, mapping := range a.config.Audiobookshelf.Users {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+
+		result := UserSyncResult{User: mapping.User}
+		user, ok := findAudiobookshelfUser(users, mapping.User)
+		if !ok {
+			a.log.Warn("Mapped Audiobookshelf user not found, skipping", map[string]interface{}{
+				"user": mapping.User,
+			})
+			result.Err = fmt.Errorf("audiobookshelf user %q not found", mapping.User)
+			results = append(results, result)
+			continue
+		}
+		result.UserID = user.ID
+
+		if !user.IsActive {
+			a.log.Info("Skipping inactive Audiobookshelf user", map[string]interface{}{
+				"user":    mapping.User,
+				"user_id": user.ID,
+			})
+			continue
+		}
+
+		svc, err := a.userService(user.ID, mapping.HardcoverToken)
+		if err != nil {
+			result.Err = err
+			results = append(results, result)
+			continue
+		}
+
+		a.log.Info("Syncing Audiobookshelf user to their Hardcover account", map[string]interface{}{
+			"user":    mapping.User,
+			"user_id": user.ID,
+		})
+		result.Err = svc.Sync(ctx)
+		result.Summary = svc.GetSummary()
+		if result.Err != nil {
+			a.log.Error("Sync failed for Audiobookshelf user", map[string]interface{}{
+				"user":    mapping.User,
+				"user_id": user.ID,
+				"error":   result.Err.Error(),
+			})
+		}
+		results = append(results, result)
+	}
+
+	return results, ctx.Err()
+}
+
+// userService returns the sync service of the user, creating it on first use
+func (a *AllUsersSync) userService(userID, hardcoverToken string) (*Service, error) {
+	if svc, ok := a.services[userID]; ok {
+		return svc, nil
+	}
+
+	svc, err := NewService(a.admin.ForUser(userID), a.newHardcover(hardcoverToken), userConfig(a.config, userID, hardcoverToken))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sync service for user %s: %w", userID, err)
+	}
+	svc.summary.UserID = userID
+	a.services[userID] = svc
+	return svc, nil
+}
+
+// findAudiobookshelfUser finds the user by ID or, case-insensitively, by username
+func findAudiobookshelfUser(users []audiobookshelf.AudiobookshelfUser, name string) (audiobookshelf.AudiobookshelfUser, bool) {
+	for _, user := range users {
+		if user.ID == name || strings.EqualFold(user.Username, name) {
+			return user, true
+		}
+	}
+	return audiobookshelf.AudiobookshelfUser{}, false
+}
+
+// userConfig returns a copy of cfg for syncing the user to the Hardcover account of the token.
+// State, caches and mismatch output are kept apart per user, since user books and sync progress
+// belong to a single Hardcover account.
+func userConfig(cfg *Config, userID, hardcoverToken string) *Config {
+	userCfg := *cfg
+	userCfg.Hardcover.Token = hardcoverToken
+	userCfg.Audiobookshelf.Users = nil
+
+	ext := filepath.Ext(cfg.Sync.StateFile)
+	userCfg.Sync.StateFile = strings.TrimSuffix(cfg.Sync.StateFile, ext) + "_" + userID + ext
+	userCfg.Paths.CacheDir = filepath.Join(cfg.Paths.CacheDir, "users", userID)
+	if cfg.Paths.MismatchOutputDir != "" {
+		userCfg.Paths.MismatchOutputDir = filepath.Join(cfg.Paths.MismatchOutputDir, "users", userID)
+	}
+	return &userCfg
+}
@@ -0,0 +1,198 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/audiobookshelf"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllUsersSync(t *testing.T) {
+	logger.Setup(logger.Config{Level: "debug"})
+	// The Audiobookshelf client saves raw library responses to the working directory
+	t.Chdir(t.TempDir())
+
+	// The items have no identifiers or author, see newTestBook. With missing progress
+	// skipped, only the books a user's sync saw progress for are looked up.
+	mediaProgress := func(itemID string) map[string]interface{} {
+		return map[string]interface{}{
+			"mediaProgress": []map[string]interface{}{{"libraryItemId": itemID, "currentTime": 600, "duration": 3600}},
+		}
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer admin-token", r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+
+		var response interface{}
+		switch r.URL.Path {
+		case "/api/users":
+			response = map[string]interface{}{"users": []map[string]interface{}{
+				{"id": "root", "username": "admin", "type": "root", "isActive": true},
+				{"id": "usr_alice", "username": "alice", "type": "user", "isActive": true},
+				{"id": "usr_bob", "username": "Bob", "type": "user", "isActive": true},
+				{"id": "usr_carol", "username": "carol", "type": "user", "isActive": true},
+				{"id": "usr_dave", "username": "dave", "type": "user", "isActive": false},
+			}}
+		case "/api/users/usr_alice":
+			response = mediaProgress("alice-book")
+		case "/api/users/usr_bob":
+			response = mediaProgress("bob-book")
+		case "/api/libraries":
+			response = map[string]interface{}{"libraries": []map[string]interface{}{{"id": "lib1", "name": "Audiobooks"}}}
+		case "/api/libraries/lib1/items":
+			assert.Empty(t, r.URL.Query().Get("include"), "the admin's progress must not be fetched")
+			items := make([]map[string]interface{}, 0, 3)
+			for _, id := range []string{"alice-book", "bob-book", "admin-book"} {
+				items = append(items, map[string]interface{}{
+					"id":        id,
+					"libraryId": "lib1",
+					"mediaType": "book",
+					"media": map[string]interface{}{
+						"metadata": map[string]interface{}{"title": "Title of " + id},
+						"duration": 3600,
+					},
+				})
+			}
+			response = map[string]interface{}{"results": items, "total": len(items)}
+		default:
+			// Includes /api/me, which would return the admin's own progress
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(response))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.Audiobookshelf.URL = server.URL
+	cfg.Audiobookshelf.Token = "admin-token"
+	cfg.Hardcover.Token = "admin-hardcover-token"
+	cfg.Audiobookshelf.Users = []config.AudiobookshelfUser{
+		{User: "alice", HardcoverToken: "alice-token"},
+		{User: "bob", HardcoverToken: "bob-token"},
+		{User: "usr_dave", HardcoverToken: "dave-token"},
+		{User: "zoe", HardcoverToken: "zoe-token"},
+	}
+	cfg.Sync.MissingProgressPolicy = config.MissingProgressSkip
+	cfg.Sync.StateFile = filepath.Join(dir, "sync_state.json")
+	cfg.Paths.CacheDir = filepath.Join(dir, "cache")
+	cfg.Paths.MismatchOutputDir = filepath.Join(dir, "mismatches")
+
+	hardcoverClients := make(map[string]*MockHardcoverClient)
+	allUsers := NewAllUsersSync(audiobookshelf.NewClient(server.URL, "admin-token"), cfg, func(token string) hardcover.HardcoverClientInterface {
+		hardcoverClients[token] = &MockHardcoverClient{}
+		return hardcoverClients[token]
+	})
+
+	results, err := allUsers.Sync(context.Background())
+	require.NoError(t, err)
+
+	// Only active mapped users get a Hardcover client, each with their own token
+	require.Len(t, hardcoverClients, 2)
+	assert.Contains(t, hardcoverClients, "alice-token")
+	assert.Contains(t, hardcoverClients, "bob-token")
+
+	require.Len(t, results, 3)
+	assert.Equal(t, "alice", results[0].User)
+	assert.Equal(t, "usr_alice", results[0].UserID)
+	require.NoError(t, results[0].Err)
+	assert.Equal(t, "bob", results[1].User)
+	assert.Equal(t, "usr_bob", results[1].UserID)
+	require.NoError(t, results[1].Err)
+	assert.Equal(t, "zoe", results[2].User)
+	assert.Error(t, results[2].Err, "unknown users are reported")
+
+	// Each user's sync only saw their own progress
+	assert.Equal(t, []string{"alice-book"}, lookedUpBooks(results[0].Summary))
+	assert.Equal(t, "usr_alice", results[0].Summary.UserID)
+	assert.Equal(t, []string{"bob-book"}, lookedUpBooks(results[1].Summary))
+	assert.Equal(t, "usr_bob", results[1].Summary.UserID)
+
+	// State is kept apart per user
+	assert.FileExists(t, filepath.Join(dir, "sync_state_usr_alice.json"))
+	assert.FileExists(t, filepath.Join(dir, "sync_state_usr_bob.json"))
+	assert.NoFileExists(t, cfg.Sync.StateFile)
+
+	for _, client := range hardcoverClients {
+		client.AssertExpectations(t)
+	}
+
+	// Services are reused on the next run
+	_, err = allUsers.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, hardcoverClients, 2)
+}
+
+func TestUserConfig(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Hardcover.Token = "admin-hardcover-token"
+	cfg.Sync.StateFile = "/data/sync_state.json"
+	cfg.Paths.CacheDir = "/cache"
+	cfg.Paths.MismatchOutputDir = ""
+	cfg.Audiobookshelf.Users = []config.AudiobookshelfUser{{User: "alice", HardcoverToken: "alice-token"}}
+
+	userCfg := userConfig(cfg, "usr_alice", "alice-token")
+	assert.Equal(t, "alice-token", userCfg.Hardcover.Token)
+	assert.Equal(t, "/data/sync_state_usr_alice.json", userCfg.Sync.StateFile)
+	assert.Equal(t, filepath.Join("/cache", "users", "usr_alice"), userCfg.Paths.CacheDir)
+	assert.Empty(t, userCfg.Paths.MismatchOutputDir)
+	assert.Empty(t, userCfg.Audiobookshelf.Users)
+
+	// The global config is left untouched
+	assert.Equal(t, "admin-hardcover-token", cfg.Hardcover.Token)
+	assert.Equal(t, "/data/sync_state.json", cfg.Sync.StateFile)
+	assert.Len(t, cfg.Audiobookshelf.Users, 1)
+}
+
+func TestProcessBook_IncrementalUsesUserProgress(t *testing.T) {
+	tests := []struct {
+		name          string
+		storedPercent float64
+		storedStatus  string
+		expectSynced  bool
+	}{
+		{name: "unchanged progress is skipped", storedPercent: 600.0 / 3600, storedStatus: "IN_PROGRESS"},
+		{name: "book started since is synced", storedStatus: "WANT_TO_READ", expectSynced: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, hcClient := createTestService()
+			svc.summary = &SyncSummary{}
+			svc.persistentCache = NewPersistentASINCache(t.TempDir())
+			svc.config.Sync.Incremental = true
+			svc.state.UpdateBook("li_1", tt.storedPercent, tt.storedStatus)
+
+			// Items of other users come without progress, which is only in the user's progress
+			book := models.AudiobookshelfBook{ID: "li_1", LibraryID: "lib1", MediaType: "book"}
+			book.Media.Metadata.Title = "Shared Audiobook"
+			book.Media.Metadata.ASIN = "B000000001"
+			book.Media.Duration = 3600
+			var userProgress models.AudiobookshelfUserProgress
+			require.NoError(t, json.Unmarshal([]byte(`{"mediaProgress":[{"libraryItemId":"li_1","currentTime":600,"duration":3600}]}`), &userProgress))
+
+			hcClient.On("SearchBookByASIN", mock.Anything, "B000000001").Return(nil, assert.AnError).Maybe()
+			hcClient.On("SearchBookByASINAnyFormat", mock.Anything, "B000000001").Return(nil, assert.AnError).Maybe()
+
+			_ = svc.processBook(context.Background(), book, &userProgress)
+			if tt.expectSynced {
+				hcClient.AssertCalled(t, "SearchBookByASIN", mock.Anything, "B000000001")
+			} else {
+				hcClient.AssertNotCalled(t, "SearchBookByASIN", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package sync
+
+import (
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// archived reports whether the user hid or archived the item in Audiobookshelf, on the item's own
+// progress or on its most recent media progress entry in the /api/me response
+func archived(book models.AudiobookshelfBook, userProgress *models.AudiobookshelfUserProgress) bool {
+	if book.Progress.HideFromContinueListening {
+		return true
+	}
+	if userProgress == nil {
+		return false
+	}
+
+	hidden, lastUpdate := false, int64(-1)
+	for _, progress := range userProgress.MediaProgress {
+		if progress.LibraryItemID == book.ID && progress.LastUpdate > lastUpdate {
+			hidden, lastUpdate = progress.HideFromContinueListening, progress.LastUpdate
+		}
+	}
+	return hidden
+}
+
+// skipArchived reports whether the book should be skipped because it's hidden or archived and
+// Sync.SkipArchived is enabled
+func (s *Service) skipArchived(book models.AudiobookshelfBook, userProgress *models.AudiobookshelfUserProgress) bool {
+	return s.config.SkipArchivedItems() && archived(book, userProgress)
+}
@@ -0,0 +1,62 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessBook_SkipArchived(t *testing.T) {
+	// The item is archived in the user's media progress
+	userProgress := &models.AudiobookshelfUserProgress{}
+	require.NoError(t, json.Unmarshal([]byte(`{"mediaProgress":[{"libraryItemId":"archived-book","currentTime":120,"lastUpdate":1700000000000,"hideFromContinueListening":true}]}`), userProgress))
+
+	book := newTestBook("archived-book", "Archived Audiobook")
+
+	t.Run("enabled by default", func(t *testing.T) {
+		svc, mockClient := createTestService()
+		svc.summary = &SyncSummary{}
+		svc.config.Sync.SkipArchived = nil
+
+		require.NoError(t, svc.processBook(context.Background(), book, userProgress))
+		assert.Empty(t, lookedUpBooks(svc.summary), "archived book should be skipped")
+		assert.Zero(t, svc.summary.BooksSynced)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		svc, mockClient := createTestService()
+		svc.summary = &SyncSummary{}
+		skipArchived := false
+		svc.config.Sync.SkipArchived = &skipArchived
+
+		require.NoError(t, svc.processBook(context.Background(), book, userProgress))
+		assert.Equal(t, []string{"archived-book"}, lookedUpBooks(svc.summary), "archived book should be processed")
+		mockClient.AssertExpectations(t)
+	})
+}
+
+func TestArchived(t *testing.T) {
+	book := models.AudiobookshelfBook{ID: "book-1"}
+	assert.False(t, archived(book, nil))
+
+	hidden := book
+	hidden.Progress.HideFromContinueListening = true
+	assert.True(t, archived(hidden, nil), "the item's own progress is hidden")
+
+	// The most recent media progress entry decides
+	userProgress := &models.AudiobookshelfUserProgress{}
+	require.NoError(t, json.Unmarshal([]byte(`{"mediaProgress":[
+		{"libraryItemId":"book-1","lastUpdate":2,"hideFromContinueListening":false},
+		{"libraryItemId":"book-1","lastUpdate":1,"hideFromContinueListening":true},
+		{"libraryItemId":"book-2","lastUpdate":3,"hideFromContinueListening":true}
+	]}`), userProgress))
+	assert.False(t, archived(book, userProgress))
+
+	userProgress.MediaProgress[0].HideFromContinueListening = true
+	assert.True(t, archived(book, userProgress))
+}
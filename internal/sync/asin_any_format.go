@@ -0,0 +1,31 @@
+package sync
+
+import (
+	"context"
+	"errors"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// errASINInOtherFormat is returned with a book only matched by the ASIN of an edition in another
+// reading format, usually the ebook, which the book can't be synced to
+var errASINInOtherFormat = errors.New("ASIN only matches an edition in another reading format")
+
+// findBookByASINInOtherFormat looks the book up by its ASIN across all reading formats, once its
+// identifiers matched no edition in its own format. The book is returned without an edition,
+// along with errASINInOtherFormat.
+func (s *Service) findBookByASINInOtherFormat(ctx context.Context, book models.AudiobookshelfBook, log *logger.Logger) (*models.HardcoverBook, bool) {
+	if book.Media.Metadata.ASIN == "" {
+		return nil, false
+	}
+
+	hcBook, err := s.hardcover.SearchBookByASINAnyFormat(ctx, book.Media.Metadata.ASIN)
+	if err != nil {
+		log.Warn("Search by ASIN across reading formats failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, false
+	}
+	return hcBook, hcBook != nil
+}
@@ -0,0 +1,65 @@
+package sync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/mismatch"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// mockEbookOnlyASIN makes Hardcover only have the ASIN of the book on its ebook edition
+func mockEbookOnlyASIN(mockClient *MockHardcoverClient, book models.AudiobookshelfBook) {
+	asin := book.Media.Metadata.ASIN
+	mockClient.On("SearchBookByASIN", mock.Anything, asin).Return(nil, nil)
+	mockClient.On("SearchBookByASINAnyFormat", mock.Anything, asin).
+		Return(&models.HardcoverBook{ID: "10", Title: book.Media.Metadata.Title, EditionASIN: asin}, nil).Maybe()
+}
+
+func TestFindBookInHardcover_ASINInOtherFormatAfterISBN(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.persistentCache = NewPersistentASINCache(t.TempDir())
+	book := newTestBook("abs-1", "The Road")
+	book.Media.Metadata.ASIN = "B0EBOOK001"
+	book.Media.Metadata.ISBN = "9780307387899"
+	mockEbookOnlyASIN(mockClient, book)
+
+	// The ISBN is on the audiobook edition, so the ebook's ASIN is never settled for
+	mockClient.On("SearchBookByISBN13", mock.Anything, "9780307387899").
+		Return(&models.HardcoverBook{ID: "10", EditionID: "200", EditionISBN13: "9780307387899"}, nil)
+	mockClient.On("GetUserBookID", mock.Anything, 200).Return(300, nil).Maybe()
+	mockClient.On("CheckBookOwnership", mock.Anything, 10).Return(true, nil).Maybe()
+
+	hcBook, _, err := svc.findBookInHardcover(context.Background(), book)
+	require.NoError(t, err)
+	require.NotNil(t, hcBook)
+	assert.Equal(t, "200", hcBook.EditionID)
+	mockClient.AssertNotCalled(t, "SearchBookByASINAnyFormat", mock.Anything, mock.Anything)
+}
+
+func TestProcessBook_ASINInOtherFormat(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.summary = &SyncSummary{}
+	svc.persistentCache = NewPersistentASINCache(t.TempDir())
+	book := newTestBook("abs-1", "The Road")
+	book.Media.Metadata.ASIN = "B0EBOOK001"
+	mockEbookOnlyASIN(mockClient, book)
+	mismatch.Clear()
+	defer mismatch.Clear()
+	// Looked up when the mismatch is recorded
+	mockClient.On("SearchBooks", mock.Anything, mock.Anything, mock.Anything).Return([]models.HardcoverBook{}, nil).Maybe()
+
+	err := svc.processBook(context.Background(), book, nil)
+	assert.ErrorIs(t, err, ErrSkippedBook)
+
+	mismatches := mismatch.GetAll()
+	require.Len(t, mismatches, 1)
+	assert.Equal(t, "10", mismatches[0].BookID)
+	assert.Contains(t, mismatches[0].Reason, errASINInOtherFormat.Error())
+	bookState, exists := svc.state.GetBookState("abs-1")
+	require.True(t, exists)
+	assert.Equal(t, "NO_EDITION", bookState.Status)
+}
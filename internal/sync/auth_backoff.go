@@ -0,0 +1,56 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/audiobookshelf"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+)
+
+// isAuthError reports whether err was caused by Hardcover or Audiobookshelf rejecting our credentials
+func isAuthError(err error) bool {
+	return hardcover.IsAuthError(err) || errors.Is(err, audiobookshelf.ErrUnauthorized)
+}
+
+// recordAuthResult tracks consecutive authentication failures across API calls. A successful call
+// resets the count; once Sync.AuthFailureThreshold consecutive failures are seen, syncs are paused
+// for Sync.AuthFailureBackoff and an error wrapping ErrAuthBackoff is returned to abort the run.
+func (s *Service) recordAuthResult(err error) error {
+	s.authMutex.Lock()
+	defer s.authMutex.Unlock()
+
+	if err == nil {
+		s.authFailures = 0
+		return nil
+	}
+	if !isAuthError(err) {
+		return nil
+	}
+
+	s.authFailures++
+	threshold := s.config.Sync.AuthFailureThreshold
+	if threshold <= 0 || s.authFailures < threshold {
+		return nil
+	}
+
+	failures := s.authFailures
+	s.authFailures = 0
+	s.authBackoffUntil = time.Now().Add(s.config.Sync.AuthFailureBackoff)
+
+	s.log.Error("Aborting sync after repeated authentication failures", map[string]interface{}{
+		"failures": failures,
+		"until":    s.authBackoffUntil.Format(time.RFC3339),
+		"error":    err.Error(),
+	})
+	return fmt.Errorf("%w (%d in a row, paused until %s): %v", ErrAuthBackoff, failures, s.authBackoffUntil.Format(time.RFC3339), err)
+}
+
+// AuthBackoffUntil returns the time until which syncs are paused after repeated authentication
+// failures, or the zero time if syncs aren't paused
+func (s *Service) AuthBackoffUntil() time.Time {
+	s.authMutex.Lock()
+	defer s.authMutex.Unlock()
+	return s.authBackoffUntil
+}
@@ -0,0 +1,81 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/audiobookshelf"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSync_RepeatedAuthFailuresTriggerBackoff(t *testing.T) {
+	svc, _ := createTestService()
+	svc.summary = &SyncSummary{}
+	svc.statePath = filepath.Join(t.TempDir(), "state.json")
+	svc.config.Paths.MismatchOutputDir = t.TempDir()
+	svc.config.Sync.AuthFailureThreshold = 2
+	svc.config.Sync.AuthFailureBackoff = time.Hour
+
+	unauthorized := fmt.Errorf("%w: unexpected status code: %d", audiobookshelf.ErrUnauthorized, http.StatusUnauthorized)
+	mockABS := new(MockAudiobookshelfClient)
+	mockABS.On("GetUserProgress", mock.Anything).Return(nil, unauthorized).Once()
+	mockABS.On("GetLibraries", mock.Anything).Return(nil, unauthorized).Once()
+	svc.audiobookshelf = mockABS
+
+	err := svc.Sync(context.Background())
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrAuthBackoff), "run should be aborted with ErrAuthBackoff, got %v", err)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), svc.AuthBackoffUntil(), time.Minute)
+	mockABS.AssertExpectations(t)
+
+	// The next attempt is skipped without calling the APIs while the backoff is active
+	err = svc.Sync(context.Background())
+	assert.True(t, errors.Is(err, ErrAuthBackoff))
+	mockABS.AssertNumberOfCalls(t, "GetUserProgress", 1)
+	mockABS.AssertNumberOfCalls(t, "GetLibraries", 1)
+}
+
+func TestRecordAuthResult(t *testing.T) {
+	hcUnauthorized := fmt.Errorf("failed after 1 attempts: %w", &hardcover.HTTPError{StatusCode: http.StatusUnauthorized})
+
+	t.Run("success resets the count", func(t *testing.T) {
+		svc, _ := createTestService()
+		svc.config.Sync.AuthFailureThreshold = 2
+		svc.config.Sync.AuthFailureBackoff = time.Hour
+
+		assert.NoError(t, svc.recordAuthResult(hcUnauthorized))
+		assert.NoError(t, svc.recordAuthResult(nil))
+		assert.NoError(t, svc.recordAuthResult(hcUnauthorized))
+		assert.True(t, svc.AuthBackoffUntil().IsZero())
+
+		assert.ErrorIs(t, svc.recordAuthResult(hcUnauthorized), ErrAuthBackoff)
+		assert.False(t, svc.AuthBackoffUntil().IsZero())
+	})
+
+	t.Run("other errors are ignored", func(t *testing.T) {
+		svc, _ := createTestService()
+		svc.config.Sync.AuthFailureThreshold = 1
+
+		assert.NoError(t, svc.recordAuthResult(errors.New("connection refused")))
+		assert.NoError(t, svc.recordAuthResult(&hardcover.HTTPError{StatusCode: http.StatusInternalServerError}))
+		assert.True(t, svc.AuthBackoffUntil().IsZero())
+	})
+
+	t.Run("zero threshold never aborts", func(t *testing.T) {
+		svc, _ := createTestService()
+		svc.config.Sync.AuthFailureThreshold = 0
+
+		for i := 0; i < 5; i++ {
+			assert.NoError(t, svc.recordAuthResult(hcUnauthorized))
+		}
+		assert.True(t, svc.AuthBackoffUntil().IsZero())
+	})
+}
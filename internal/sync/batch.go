@@ -77,11 +77,11 @@ func (s *Service) BatchProcessBooks(ctx context.Context, books []models.Audioboo
 			if book.Media.Duration > 0 {
 				currentProgress = book.Progress.CurrentTime / book.Media.Duration
 			}
-			currentStatus := s.determineBookStatus(currentProgress, book.Progress.IsFinished, book.Progress.FinishedAt)
+			currentStatus := s.bookStatus(book, currentProgress)
 			
 			// Check if this book needs syncing
 			minChangeThreshold := float64(s.config.Sync.MinChangeThreshold) / book.Media.Duration
-			if !s.state.NeedsSync(book.ID, currentProgress, currentStatus, minChangeThreshold) {
+			if !s.alwaysReverifyStatus(currentStatus) && !s.state.NeedsSync(book.ID, currentProgress, currentStatus, minChangeThreshold) {
 				skippedCount++
 				continue
 			}
@@ -150,7 +150,7 @@ func (s *Service) BatchProcessBooks(ctx context.Context, books []models.Audioboo
 			default:
 			}
 
-			if err := s.processBook(ctx, book, userProgress); err != nil {
+			if err := s.processBookWithTimeout(ctx, book, userProgress); err != nil {
 				batch.AddError(book.ID, err)
 				s.log.Warn("Failed to process book in batch", map[string]interface{}{
 					"book_id": book.ID,
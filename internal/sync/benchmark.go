@@ -0,0 +1,166 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// Match methods reported by BenchmarkMatching, in the order they are tried
+const (
+	MatchMethodASIN        = "asin"
+	MatchMethodISBN        = "isbn"
+	MatchMethodTitleAuthor = "title_author"
+	MatchMethodUnmatched   = "unmatched"
+)
+
+// MatchMethods lists all match methods in the order they are tried
+var MatchMethods = []string{MatchMethodASIN, MatchMethodISBN, MatchMethodTitleAuthor, MatchMethodUnmatched}
+
+// BenchmarkResult is the matching outcome for a single sampled library item
+type BenchmarkResult struct {
+	BookID   string
+	Title    string
+	Author   string
+	Method   string
+	Duration time.Duration
+}
+
+// MatchBenchmark summarizes how a sample of library items matched against Hardcover
+type MatchBenchmark struct {
+	// LibrarySize is the number of items the sample was drawn from
+	LibrarySize int
+	// Results holds one entry per sampled item
+	Results []BenchmarkResult
+	// Counts holds the number of sampled items per match method
+	Counts map[string]int
+	// Duration is the total time spent matching
+	Duration time.Duration
+}
+
+// SampleSize returns the number of items that were matched
+func (b *MatchBenchmark) SampleSize() int {
+	return len(b.Results)
+}
+
+// Percentage returns the percentage of sampled items matched by the given method
+func (b *MatchBenchmark) Percentage(method string) float64 {
+	if len(b.Results) == 0 {
+		return 0
+	}
+	return float64(b.Counts[method]) * 100 / float64(len(b.Results))
+}
+
+// AverageDuration returns the average time spent matching a single item
+func (b *MatchBenchmark) AverageDuration() time.Duration {
+	if len(b.Results) == 0 {
+		return 0
+	}
+	return b.Duration / time.Duration(len(b.Results))
+}
+
+// BenchmarkMatching matches a random sample of sampleSize items from the synced libraries against
+// Hardcover and reports how each one was matched. It only performs lookups: nothing is written
+// to Hardcover, the sync state or the lookup caches. A sampleSize <= 0 matches every item.
+func (s *Service) BenchmarkMatching(ctx context.Context, sampleSize int, rnd *rand.Rand) (*MatchBenchmark, error) {
+	libraries, err := s.audiobookshelf.GetLibraries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch libraries: %w", err)
+	}
+
+	var items []models.AudiobookshelfBook
+	for _, library := range libraries {
+		if !s.shouldSyncLibrary(&library) {
+			continue
+		}
+		libraryItems, err := s.audiobookshelf.GetLibraryItems(ctx, library.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch items for library %s: %w", library.Name, err)
+		}
+		items = append(items, libraryItems...)
+	}
+
+	benchmark := &MatchBenchmark{
+		LibrarySize: len(items),
+		Counts:      make(map[string]int, len(MatchMethods)),
+	}
+
+	rnd.Shuffle(len(items), func(i, j int) {
+		items[i], items[j] = items[j], items[i]
+	})
+	if sampleSize > 0 && sampleSize < len(items) {
+		items = items[:sampleSize]
+	}
+
+	s.log.Info("Benchmarking matching against Hardcover", map[string]interface{}{
+		"library_size": benchmark.LibrarySize,
+		"sample_size":  len(items),
+	})
+
+	for _, book := range items {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		start := time.Now()
+		method := s.matchMethod(ctx, book)
+		elapsed := time.Since(start)
+
+		benchmark.Results = append(benchmark.Results, BenchmarkResult{
+			BookID:   book.ID,
+			Title:    book.Media.Metadata.Title,
+			Author:   book.Media.Metadata.AuthorName,
+			Method:   method,
+			Duration: elapsed,
+		})
+		benchmark.Counts[method]++
+		benchmark.Duration += elapsed
+	}
+
+	return benchmark, nil
+}
+
+// matchMethod looks the book up in Hardcover the same way findBookInHardcover does (ASIN, then
+// ISBN-13/ISBN-10, then title/author) but without creating user books or touching the caches,
+// and returns the first method that found a match
+func (s *Service) matchMethod(ctx context.Context, book models.AudiobookshelfBook) string {
+	ctx = hardcover.WithReadingFormat(ctx, s.editionFormat(ctx, book))
+	metadata := book.Media.Metadata
+
+	if asin := strings.TrimSpace(metadata.ASIN); asin != "" {
+		if lookupFound(s.hardcover.SearchBookByASIN(ctx, asin)) {
+			return MatchMethodASIN
+		}
+	}
+
+	if isbn := strings.TrimSpace(metadata.ISBN); isbn != "" {
+		if lookupFound(s.hardcover.SearchBookByISBN13(ctx, isbn)) || lookupFound(s.hardcover.SearchBookByISBN10(ctx, isbn)) {
+			return MatchMethodISBN
+		}
+	}
+
+	if metadata.Title != "" && metadata.AuthorName != "" {
+		// A title/author match is always returned together with an error marking it as such
+		if hcBook, _ := s.findBookInHardcoverByTitleAuthor(ctx, book); hcBook != nil {
+			return MatchMethodTitleAuthor
+		}
+	}
+
+	return MatchMethodUnmatched
+}
+
+// lookupFound reports whether a Hardcover lookup identified a book, treating a BookError that carries
+// a book ID as a match like findBookInHardcover does
+func lookupFound(hcBook *models.HardcoverBook, err error) bool {
+	if err != nil {
+		var bookErr *hardcover.BookError
+		return errors.As(err, &bookErr) && bookErr.BookID != ""
+	}
+	return hcBook != nil
+}
@@ -0,0 +1,123 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/audiobookshelf"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBenchmarkMatching(t *testing.T) {
+	newBook := func(id, title, author, asin, isbn string) models.AudiobookshelfBook {
+		book := models.AudiobookshelfBook{ID: id, LibraryID: "lib1", MediaType: "book"}
+		book.Media.Metadata.Title = title
+		book.Media.Metadata.AuthorName = author
+		book.Media.Metadata.ASIN = asin
+		book.Media.Metadata.ISBN = isbn
+		return book
+	}
+
+	library := []models.AudiobookshelfBook{
+		newBook("asin-match", "Dune", "Frank Herbert", "B000000001", ""),
+		newBook("isbn13-match", "Emma", "Jane Austen", "B000000002", "9780000000001"),
+		newBook("isbn10-match", "Ulysses", "James Joyce", "", "0000000002"),
+		newBook("title-match", "Beloved", "Toni Morrison", "", ""),
+		newBook("unmatched", "Unknown Recording", "", "B000000003", ""),
+	}
+	notFound := errors.New("not found")
+
+	setup := func(t *testing.T) (*Service, *MockHardcoverClient, *MockAudiobookshelfClient) {
+		svc, mockClient := createTestService()
+		absClient := &MockAudiobookshelfClient{}
+		svc.audiobookshelf = absClient
+
+		absClient.On("GetLibraries", mock.Anything).Return([]audiobookshelf.AudiobookshelfLibrary{
+			{ID: "lib1", Name: "Audiobooks"},
+			{ID: "lib2", Name: "Podcasts"},
+		}, nil)
+		absClient.On("GetLibraryItems", mock.Anything, "lib1").Return(library, nil)
+
+		mockClient.On("SearchBookByASIN", mock.Anything, "B000000001").Return(&models.HardcoverBook{ID: "1", EditionID: "11"}, nil)
+		mockClient.On("SearchBookByASIN", mock.Anything, "B000000002").Return(nil, notFound)
+		mockClient.On("SearchBookByASIN", mock.Anything, "B000000003").Return(nil, notFound)
+		mockClient.On("SearchBookByISBN13", mock.Anything, "9780000000001").Return(&models.HardcoverBook{ID: "2", EditionID: "22"}, nil)
+		mockClient.On("SearchBookByISBN13", mock.Anything, "0000000002").Return(nil, notFound)
+		mockClient.On("SearchBookByISBN10", mock.Anything, "0000000002").Return(&models.HardcoverBook{ID: "3", EditionID: "33"}, nil)
+		mockClient.On("SearchBooks", mock.Anything, "Beloved Toni Morrison", "").Return([]models.HardcoverBook{{ID: "4", Title: "Beloved"}}, nil)
+		mockClient.On("GetBookByID", mock.Anything, "4").Return(nil, notFound)
+		mockClient.On("GetBookAuthors", mock.Anything, "4").Return([]models.Author{{ID: "40", Name: "Toni Morrison"}}, nil)
+
+		return svc, mockClient, absClient
+	}
+
+	t.Run("whole library", func(t *testing.T) {
+		svc, mockClient, absClient := setup(t)
+		svc.config.Sync.Libraries.Exclude = []string{"Podcasts"}
+
+		benchmark, err := svc.BenchmarkMatching(context.Background(), 0, rand.New(rand.NewSource(1)))
+		require.NoError(t, err)
+
+		assert.Equal(t, 5, benchmark.LibrarySize)
+		assert.Equal(t, 5, benchmark.SampleSize())
+		assert.Equal(t, map[string]int{
+			MatchMethodASIN:        1,
+			MatchMethodISBN:        2,
+			MatchMethodTitleAuthor: 1,
+			MatchMethodUnmatched:   1,
+		}, benchmark.Counts)
+		assert.InDelta(t, 40.0, benchmark.Percentage(MatchMethodISBN), 0.001)
+		assert.InDelta(t, 20.0, benchmark.Percentage(MatchMethodUnmatched), 0.001)
+
+		methods := make(map[string]string)
+		for _, result := range benchmark.Results {
+			methods[result.BookID] = result.Method
+		}
+		assert.Equal(t, map[string]string{
+			"asin-match":   MatchMethodASIN,
+			"isbn13-match": MatchMethodISBN,
+			"isbn10-match": MatchMethodISBN,
+			"title-match":  MatchMethodTitleAuthor,
+			"unmatched":    MatchMethodUnmatched,
+		}, methods)
+
+		// Lookups only: no user books are created and nothing is cached
+		mockClient.AssertNotCalled(t, "GetUserBookID", mock.Anything, mock.Anything)
+		mockClient.AssertNotCalled(t, "CreateUserBook", mock.Anything, mock.Anything, mock.Anything)
+		assert.Empty(t, svc.asinCache)
+		absClient.AssertNotCalled(t, "GetLibraryItems", mock.Anything, "lib2")
+	})
+
+	t.Run("random sample", func(t *testing.T) {
+		svc, _, absClient := setup(t)
+		svc.config.Sync.Libraries.Include = []string{"Audiobooks"}
+
+		benchmark, err := svc.BenchmarkMatching(context.Background(), 2, rand.New(rand.NewSource(1)))
+		require.NoError(t, err)
+
+		assert.Equal(t, 5, benchmark.LibrarySize)
+		assert.Equal(t, 2, benchmark.SampleSize())
+		total := 0
+		for _, method := range MatchMethods {
+			total += benchmark.Counts[method]
+		}
+		assert.Equal(t, 2, total)
+		assert.Equal(t, benchmark.Duration/2, benchmark.AverageDuration())
+		absClient.AssertNotCalled(t, "GetLibraryItems", mock.Anything, "lib2")
+	})
+
+	t.Run("library fetch failure", func(t *testing.T) {
+		svc, _ := createTestService()
+		absClient := &MockAudiobookshelfClient{}
+		svc.audiobookshelf = absClient
+		absClient.On("GetLibraries", mock.Anything).Return(nil, errors.New("connection refused"))
+
+		_, err := svc.BenchmarkMatching(context.Background(), 10, rand.New(rand.NewSource(1)))
+		assert.Error(t, err)
+	})
+}
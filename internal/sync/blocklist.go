@@ -0,0 +1,143 @@
+package sync
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// BlockedItem is an Audiobookshelf item that's never synced, with where it was blocked
+type BlockedItem struct {
+	ItemID string
+	// Source is "config" for items in Sync.Blocklist and the file's path for items in
+	// Paths.BlocklistFile
+	Source string
+}
+
+// BlockedItems returns the items blocked in Sync.Blocklist and Paths.BlocklistFile, sorted by item
+// ID. Items blocked in both are listed once, with the config as their source.
+func BlockedItems(cfg *config.Config) ([]BlockedItem, error) {
+	sources := make(map[string]string)
+	if path := cfg.Paths.BlocklistFile; path != "" {
+		itemIDs, err := ReadBlocklistFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, itemID := range itemIDs {
+			sources[itemID] = path
+		}
+	}
+	for _, itemID := range cfg.Sync.Blocklist {
+		if itemID = strings.TrimSpace(itemID); itemID != "" {
+			sources[itemID] = "config"
+		}
+	}
+
+	items := make([]BlockedItem, 0, len(sources))
+	for itemID, source := range sources {
+		items = append(items, BlockedItem{ItemID: itemID, Source: source})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ItemID < items[j].ItemID })
+	return items, nil
+}
+
+// ReadBlocklistFile reads the item IDs in a blocklist file, one per line. Empty lines and lines
+// starting with # are skipped, and a missing file blocks nothing.
+func ReadBlocklistFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open blocklist file: %w", err)
+	}
+	defer file.Close()
+
+	var itemIDs []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		itemIDs = append(itemIDs, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read blocklist file: %w", err)
+	}
+	return itemIDs, nil
+}
+
+// AppendBlocklistFile adds the item to the blocklist file, creating it if needed. Items already in
+// the file aren't added again. Blocked items are skipped from the next sync run on.
+func AppendBlocklistFile(path, itemID string) error {
+	itemID = strings.TrimSpace(itemID)
+	if itemID == "" {
+		return errors.New("item ID is required")
+	}
+
+	itemIDs, err := ReadBlocklistFile(path)
+	if err != nil {
+		return err
+	}
+	for _, blocked := range itemIDs {
+		if blocked == itemID {
+			return nil
+		}
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open blocklist file: %w", err)
+	}
+	if _, err := fmt.Fprintln(file, itemID); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write blocklist file: %w", err)
+	}
+	return file.Close()
+}
+
+// loadBlocklist loads the items blocked in Sync.Blocklist and Paths.BlocklistFile once per run, so
+// items appended to the file are skipped from the next run on. If the file can't be read, only the
+// items in the config are blocked this run.
+func (s *Service) loadBlocklist() {
+	s.blocklist = make(map[string]struct{}, len(s.config.Sync.Blocklist))
+	for _, itemID := range s.config.Sync.Blocklist {
+		if itemID = strings.TrimSpace(itemID); itemID != "" {
+			s.blocklist[itemID] = struct{}{}
+		}
+	}
+
+	path := s.config.Paths.BlocklistFile
+	if path == "" {
+		return
+	}
+	itemIDs, err := ReadBlocklistFile(path)
+	if err != nil {
+		s.log.Warn("Failed to read the blocklist file, only blocking the items in the config this run", map[string]interface{}{
+			"path":  path,
+			"error": err.Error(),
+		})
+		return
+	}
+	for _, itemID := range itemIDs {
+		s.blocklist[itemID] = struct{}{}
+	}
+	s.log.Info("Loaded blocklist", map[string]interface{}{
+		"path":  path,
+		"items": len(s.blocklist),
+	})
+}
+
+// blocked reports whether the book is in the blocklist loaded for this run
+func (s *Service) blocked(book models.AudiobookshelfBook) bool {
+	_, ok := s.blocklist[book.ID]
+	return ok
+}
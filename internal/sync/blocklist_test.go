@@ -0,0 +1,75 @@
+package sync
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessBook_Blocklist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# always mismatches\nfile-book\n\n"), 0644))
+
+	newBook := func(id string) models.AudiobookshelfBook {
+		book := newTestBook(id, "Unmatchable Audiobook")
+		book.Progress.CurrentTime = 600
+		return book
+	}
+
+	svc, mockClient := createTestService()
+	svc.summary = &SyncSummary{}
+	svc.config.Sync.Blocklist = []string{"config-book"}
+	svc.config.Paths.BlocklistFile = path
+	svc.loadBlocklist()
+
+	for _, id := range []string{"config-book", "file-book"} {
+		require.NoError(t, svc.processBook(context.Background(), newBook(id), nil))
+	}
+	assert.Empty(t, lookedUpBooks(svc.summary), "blocked books shouldn't be looked up")
+	assert.Equal(t, int32(2), svc.summary.TotalBooksProcessed, "blocked books still count as processed")
+	assert.Zero(t, svc.summary.BooksSynced)
+
+	require.NoError(t, svc.processBook(context.Background(), newBook("other-book"), nil))
+	assert.Equal(t, []string{"other-book"}, lookedUpBooks(svc.summary))
+	mockClient.AssertExpectations(t)
+}
+
+func TestAppendBlocklistFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+
+	itemIDs, err := ReadBlocklistFile(path)
+	require.NoError(t, err, "a missing file blocks nothing")
+	assert.Empty(t, itemIDs)
+
+	require.NoError(t, AppendBlocklistFile(path, "li_1"))
+	require.NoError(t, AppendBlocklistFile(path, " li_2 "))
+	require.NoError(t, AppendBlocklistFile(path, "li_1"))
+	assert.Error(t, AppendBlocklistFile(path, " "))
+
+	itemIDs, err = ReadBlocklistFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"li_1", "li_2"}, itemIDs)
+}
+
+func TestBlockedItems(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	require.NoError(t, os.WriteFile(path, []byte("li_3\nli_1\n"), 0644))
+
+	cfg := &config.Config{}
+	cfg.Sync.Blocklist = []string{"li_2", "li_1"}
+	cfg.Paths.BlocklistFile = path
+
+	items, err := BlockedItems(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, []BlockedItem{
+		{ItemID: "li_1", Source: "config"},
+		{ItemID: "li_2", Source: "config"},
+		{ItemID: "li_3", Source: path},
+	}, items)
+}
@@ -0,0 +1,62 @@
+package sync
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// trackBookLevel adds a Hardcover book without a matching edition to the library at the book level
+// when Sync.AllowBookLevelTracking is enabled. Only the status is synced, as Hardcover keeps reading
+// progress per edition. It reports whether the book was handled; otherwise the caller skips it as
+// before.
+func (s *Service) trackBookLevel(ctx context.Context, hcBook *models.HardcoverBook, status string, log *logger.Logger) bool {
+	if !s.config.Sync.AllowBookLevelTracking || s.config.Sync.OwnershipOnly || hcBook == nil || hcBook.ID == "" {
+		return false
+	}
+	if status == "" {
+		log.Debug("No status to sync, not tracking book without edition", nil)
+		return false
+	}
+	if status == "WANT_TO_READ" && s.skipUnownedWantToRead(ctx, hcBook) {
+		return false
+	}
+
+	if s.dryRun(ctx) {
+		log.Info("[DRY-RUN] Would add book without edition at the book level", map[string]interface{}{
+			"book_id": hcBook.ID,
+			"status":  status,
+		})
+		s.planAction(ctx, PlannedAction{Action: PlannedActionCreateUserBook, HardcoverBookID: hcBook.ID, Status: status})
+		return true
+	}
+
+	userBookID, err := s.hardcover.CreateBookLevelUserBook(ctx, hcBook.ID, status)
+	if err != nil {
+		if hardcover.IsOperationDisabled(err) {
+			log.Info("Not adding book without edition, the operation is disabled", map[string]interface{}{
+				"book_id": hcBook.ID,
+			})
+		} else {
+			log.Warn("Failed to add book without edition at the book level", map[string]interface{}{
+				"book_id": hcBook.ID,
+				"status":  status,
+				"error":   err.Error(),
+			})
+		}
+		return false
+	}
+
+	if id, err := strconv.ParseInt(userBookID, 10, 64); err == nil {
+		s.userBooks.add(hcBook.ID, "", id)
+	}
+	log.Info("Added book without edition at the book level", map[string]interface{}{
+		"book_id":      hcBook.ID,
+		"user_book_id": userBookID,
+		"status":       status,
+	})
+	return true
+}
@@ -0,0 +1,119 @@
+package sync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/mismatch"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// mockBookWithoutEdition makes the ASIN of the book match a Hardcover book without edition
+func mockBookWithoutEdition(mockClient *MockHardcoverClient, book models.AudiobookshelfBook) {
+	mockClient.On("SearchBookByASIN", mock.Anything, book.Media.Metadata.ASIN).
+		Return(&models.HardcoverBook{ID: "10", Title: book.Media.Metadata.Title}, nil)
+}
+
+func TestProcessBook_BookLevelTracking(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.summary = &SyncSummary{}
+	svc.persistentCache = NewPersistentASINCache(t.TempDir())
+	svc.config.Sync.AllowBookLevelTracking = true
+	book := newTestBook("abs-1", "Audiobook Without Edition")
+	book.Media.Metadata.ASIN = "B000000001"
+	mockBookWithoutEdition(mockClient, book)
+
+	mockClient.On("CreateBookLevelUserBook", mock.Anything, "10", "IN_PROGRESS").Return("500", nil).Once()
+
+	require.NoError(t, svc.processBook(context.Background(), book, nil))
+
+	mockClient.AssertExpectations(t)
+	mockClient.AssertNotCalled(t, "CreateUserBook", mock.Anything, mock.Anything, mock.Anything)
+	mockClient.AssertNotCalled(t, "GetUserBookID", mock.Anything, mock.Anything)
+	bookState, exists := svc.state.GetBookState("abs-1")
+	require.True(t, exists)
+	assert.Equal(t, "IN_PROGRESS", bookState.Status)
+}
+
+func TestProcessBook_BookLevelTrackingDisabled(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.summary = &SyncSummary{}
+	svc.persistentCache = NewPersistentASINCache(t.TempDir())
+	book := newTestBook("abs-1", "Audiobook Without Edition")
+	book.Media.Metadata.ASIN = "B000000001"
+	mockBookWithoutEdition(mockClient, book)
+	mismatch.Clear()
+	defer mismatch.Clear()
+	// Looked up when the mismatch is recorded
+	mockClient.On("SearchBooks", mock.Anything, mock.Anything, mock.Anything).Return([]models.HardcoverBook{}, nil)
+
+	err := svc.processBook(context.Background(), book, nil)
+	assert.ErrorIs(t, err, ErrSkippedBook)
+
+	mockClient.AssertNotCalled(t, "CreateBookLevelUserBook", mock.Anything, mock.Anything, mock.Anything)
+	bookState, exists := svc.state.GetBookState("abs-1")
+	require.True(t, exists)
+	assert.Equal(t, "NO_EDITION", bookState.Status)
+}
+
+func TestTrackBookLevel(t *testing.T) {
+	hcBook := &models.HardcoverBook{ID: "10"}
+
+	t.Run("no status to sync", func(t *testing.T) {
+		svc, mockClient := createTestService()
+		svc.config.Sync.AllowBookLevelTracking = true
+
+		assert.False(t, svc.trackBookLevel(context.Background(), hcBook, "", svc.log))
+		mockClient.AssertNotCalled(t, "CreateBookLevelUserBook", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		svc, mockClient := createTestService()
+		svc.config.Sync.AllowBookLevelTracking = true
+		svc.config.Sync.DryRun = true
+
+		assert.True(t, svc.trackBookLevel(context.Background(), hcBook, "FINISHED", svc.log))
+		mockClient.AssertNotCalled(t, "CreateBookLevelUserBook", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("operation disabled falls back to skipping", func(t *testing.T) {
+		svc, mockClient := createTestService()
+		svc.config.Sync.AllowBookLevelTracking = true
+		mockClient.On("CreateBookLevelUserBook", mock.Anything, "10", "FINISHED").
+			Return("", &hardcover.OperationDisabledError{Operation: "InsertUserBook"}).Once()
+
+		assert.False(t, svc.trackBookLevel(context.Background(), hcBook, "FINISHED", svc.log))
+		mockClient.AssertExpectations(t)
+	})
+}
+
+func TestProcessBook_BookWithoutEditions(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.summary = &SyncSummary{}
+	svc.persistentCache = NewPersistentASINCache(t.TempDir())
+	mismatch.Clear()
+	defer mismatch.Clear()
+
+	book := newTestBook("abs-1", "Audiobook Without Editions")
+	book.Media.Metadata.AuthorName = "Some Author"
+	book.Media.Metadata.ASIN = "B000000001"
+	mockClient.On("SearchBookByASIN", mock.Anything, "B000000001").
+		Return(nil, hardcover.WithBookID(hardcover.ErrBookHasNoEditions, "10"))
+	// Looked up when the mismatch is recorded
+	mockClient.On("SearchBooks", mock.Anything, mock.Anything, mock.Anything).Return([]models.HardcoverBook{}, nil)
+
+	err := svc.processBook(context.Background(), book, nil)
+	assert.ErrorIs(t, err, ErrSkippedBook)
+
+	mismatches := mismatch.GetAll()
+	require.Len(t, mismatches, 1)
+	assert.Equal(t, "book exists but has no editions", mismatches[0].Reason)
+	assert.Equal(t, "10", mismatches[0].BookID)
+	bookState, exists := svc.state.GetBookState("abs-1")
+	require.True(t, exists)
+	assert.Equal(t, "NO_EDITION", bookState.Status)
+}
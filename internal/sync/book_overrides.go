@@ -0,0 +1,51 @@
+package sync
+
+import (
+	"context"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// bookOverride returns the Hardcover slug or URL the book is pinned to in Sync.BookOverrides, or ""
+func (s *Service) bookOverride(book models.AudiobookshelfBook) string {
+	return s.config.Sync.BookOverrides[book.ID]
+}
+
+// findBookByOverride looks the book up by the Hardcover book it's pinned to in Sync.BookOverrides.
+// The bool reports whether the pin decided the result; a pinned book that can't be found falls back
+// to the usual lookup, and the mismatch recorded for it then carries the pin. A pinned book without
+// an edition of its format is returned without one, like any book found without an edition.
+func (s *Service) findBookByOverride(ctx context.Context, book models.AudiobookshelfBook, log *logger.Logger) (*models.HardcoverBook, bool, error) {
+	override := s.bookOverride(book)
+	if override == "" {
+		return nil, false, nil
+	}
+
+	log = log.With(map[string]interface{}{
+		"book_override": override,
+	})
+
+	hcBook, err := s.hardcover.SearchBookBySlug(ctx, override)
+	if err != nil {
+		log.Warn("Failed to look up the Hardcover book the item is pinned to, trying other methods", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, false, nil
+	}
+	if hcBook == nil {
+		log.Warn("Hardcover book the item is pinned to doesn't exist, trying other methods", nil)
+		return nil, false, nil
+	}
+
+	log.Info("Found book by override", map[string]interface{}{
+		"hardcover_book_id": hcBook.ID,
+		"edition_id":        hcBook.EditionID,
+	})
+
+	if hcBook.EditionID == "" {
+		return hcBook, true, nil
+	}
+	found, err := s.processFoundBook(ctx, hcBook, book)
+	return found, true, err
+}
@@ -0,0 +1,90 @@
+package sync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/mismatch"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/sync/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// pinBook pins the book to a Hardcover book with a book override
+func pinBook(svc *Service, book models.AudiobookshelfBook) {
+	svc.config.Sync.SyncOwned = false
+	svc.config.Sync.BookOverrides = map[string]string{
+		book.ID: "https://hardcover.app/books/pinned-audiobook",
+	}
+}
+
+func TestFindBookInHardcover_BookOverride(t *testing.T) {
+	svc, mockClient := createTestService()
+	book := newTestBook("abs-pinned", "Pinned Audiobook")
+	book.Media.Metadata.ASIN = "B0WRONG001"
+	pinBook(svc, book)
+
+	mockClient.On("SearchBookBySlug", mock.Anything, "https://hardcover.app/books/pinned-audiobook").
+		Return(&models.HardcoverBook{ID: "42", EditionID: "420", Slug: "pinned-audiobook"}, nil).Once()
+	mockClient.On("GetUserBookID", mock.Anything, 420).Return(77, nil).Once()
+
+	hcBook, match, err := svc.findBookInHardcover(context.Background(), book)
+	require.NoError(t, err)
+	require.NotNil(t, hcBook)
+
+	assert.Equal(t, "420", hcBook.EditionID)
+	assert.Equal(t, "77", hcBook.UserBookID)
+	mockClient.AssertExpectations(t)
+	// The pin replaces the lookup by the wrong ASIN
+	mockClient.AssertNotCalled(t, "SearchBookByASIN", mock.Anything, mock.Anything)
+	assert.Equal(t, state.MatchSourceMapping, match.Source)
+}
+
+func TestFindBookInHardcover_BookOverrideNotFound(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.persistentCache = NewPersistentASINCache(t.TempDir())
+	book := newTestBook("abs-pinned", "Pinned Audiobook")
+	book.Media.Metadata.ASIN = "B0WRONG001"
+	pinBook(svc, book)
+
+	mockClient.On("SearchBookBySlug", mock.Anything, mock.Anything).Return(nil, nil).Once()
+	mockClient.On("SearchBookByASIN", mock.Anything, "B0WRONG001").
+		Return(&models.HardcoverBook{ID: "10", EditionID: "100", EditionASIN: "B0WRONG001"}, nil).Once()
+	mockClient.On("GetUserBookID", mock.Anything, 100).Return(55, nil).Once()
+
+	// Falls back to the ASIN lookup
+	hcBook, match, err := svc.findBookInHardcover(context.Background(), book)
+	require.NoError(t, err)
+	require.NotNil(t, hcBook)
+	assert.Equal(t, "100", hcBook.EditionID)
+	assert.Equal(t, state.MatchSourceASIN, match.Source)
+	mockClient.AssertExpectations(t)
+}
+
+func TestProcessBook_BookOverrideInMismatch(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.summary = &SyncSummary{}
+	svc.persistentCache = NewPersistentASINCache(t.TempDir())
+	book := newTestBook("abs-pinned", "Pinned Audiobook")
+	book.Media.Metadata.ASIN = "B0WRONG001"
+	pinBook(svc, book)
+	mismatch.Clear()
+	defer mismatch.Clear()
+
+	// The pinned book has no audiobook edition
+	mockClient.On("SearchBookBySlug", mock.Anything, mock.Anything).
+		Return(&models.HardcoverBook{ID: "42", Title: "Pinned Audiobook"}, nil)
+	// Looked up when the mismatch is recorded
+	mockClient.On("SearchBookByASIN", mock.Anything, mock.Anything).Return(nil, nil)
+	mockClient.On("SearchBooks", mock.Anything, mock.Anything, mock.Anything).Return([]models.HardcoverBook{}, nil)
+
+	err := svc.processBook(context.Background(), book, nil)
+	assert.ErrorIs(t, err, ErrSkippedBook)
+
+	mismatches := mismatch.GetAll()
+	require.Len(t, mismatches, 1)
+	assert.Equal(t, "abs-pinned", mismatches[0].ItemID)
+	assert.Equal(t, "https://hardcover.app/books/pinned-audiobook", mismatches[0].BookOverride)
+}
@@ -0,0 +1,110 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/tracing"
+)
+
+// processBookWithTimeout processes the book with a deadline of Sync.PerBookTimeout. A book that
+// doesn't finish in time is recorded in the summary and ErrBookTimeout is returned, so the caller
+// can move on to the next book. Processing waits for the calls of the book to return on their
+// deadline, so no work of a timed out book is left running while the next one is processed.
+func (s *Service) processBookWithTimeout(ctx context.Context, book models.AudiobookshelfBook, userProgress *models.AudiobookshelfUserProgress) (err error) {
+	ctx, span := tracing.Start(ctx, "sync.book",
+		attribute.String("book.id", book.ID),
+		attribute.String("book.title", book.Media.Metadata.Title),
+	)
+	defer func() { tracing.End(span, err, ErrSkippedBook) }()
+
+	timeout := s.config.Sync.PerBookTimeout
+	if timeout <= 0 {
+		return s.processBook(ctx, book, userProgress)
+	}
+
+	bookCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err = s.processBook(bookCtx, book, userProgress)
+
+	// Cancellation of the whole run isn't a per-book timeout
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err == nil || !errors.Is(bookCtx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+
+	s.recordBookTimeout(book, timeout)
+	return fmt.Errorf("%w after %s", ErrBookTimeout, timeout)
+}
+
+// recordBookTimeout adds the book to the timed out books of the current run
+func (s *Service) recordBookTimeout(book models.AudiobookshelfBook, timeout time.Duration) {
+	s.log.Warn("Book processing timed out, it will be retried on the next run", map[string]interface{}{
+		"book_id": book.ID,
+		"title":   book.Media.Metadata.Title,
+		"timeout": timeout.String(),
+	})
+
+	s.summary.Lock()
+	defer s.summary.Unlock()
+	s.summary.BooksTimedOut = append(s.summary.BooksTimedOut, BookNotFoundInfo{
+		BookID: book.ID,
+		Title:  book.Media.Metadata.Title,
+		Author: book.Media.Metadata.AuthorName,
+		ASIN:   book.Media.Metadata.ASIN,
+		ISBN:   book.Media.Metadata.ISBN,
+		Error:  fmt.Sprintf("timed out after %s", timeout),
+	})
+	s.summary.addSkipReason(SkipReasonTimedOut)
+}
+
+// timedOutBookCount returns the number of books that timed out during the current run
+func (s *Service) timedOutBookCount() int {
+	s.summary.RLock()
+	defer s.summary.RUnlock()
+	return len(s.summary.BooksTimedOut)
+}
+
+// recordBookFailure adds the book to the failed books of the current run, which are retried on the
+// next run
+func (s *Service) recordBookFailure(book models.AudiobookshelfBook, err error) {
+	s.summary.Lock()
+	defer s.summary.Unlock()
+	s.summary.BooksFailed = append(s.summary.BooksFailed, BookNotFoundInfo{
+		BookID: book.ID,
+		Title:  book.Media.Metadata.Title,
+		Author: book.Media.Metadata.AuthorName,
+		ASIN:   book.Media.Metadata.ASIN,
+		ISBN:   book.Media.Metadata.ISBN,
+		Error:  err.Error(),
+	})
+}
+
+// failedBookCount returns the number of books that failed during the current run
+func (s *Service) failedBookCount() int {
+	s.summary.RLock()
+	defer s.summary.RUnlock()
+	return len(s.summary.BooksFailed)
+}
+
+// retriedItems returns the IDs of the items that failed or timed out during the current run
+func (s *Service) retriedItems() map[string]struct{} {
+	s.summary.RLock()
+	defer s.summary.RUnlock()
+	items := make(map[string]struct{}, len(s.summary.BooksFailed)+len(s.summary.BooksTimedOut))
+	for _, book := range s.summary.BooksFailed {
+		items[book.BookID] = struct{}{}
+	}
+	for _, book := range s.summary.BooksTimedOut {
+		items[book.BookID] = struct{}{}
+	}
+	return items
+}
@@ -0,0 +1,93 @@
+package sync
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/audiobookshelf"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessLibrary_PerBookTimeout(t *testing.T) {
+	// The slow book hangs in the ASIN lookup until its deadline passes
+	slow := models.AudiobookshelfBook{ID: "slow-book", LibraryID: "lib1", MediaType: "book"}
+	slow.Media.Metadata.Title = "Slow Audiobook"
+	slow.Media.Metadata.ASIN = "B0SLOWBOOK"
+
+	// Books with only a title aren't found in Hardcover without any client calls
+	fast := models.AudiobookshelfBook{ID: "fast-book", LibraryID: "lib1", MediaType: "book"}
+	fast.Media.Metadata.Title = "Fast Audiobook"
+
+	svc, mockClient := createTestService()
+	svc.summary = &SyncSummary{}
+	svc.config.Sync.PerBookTimeout = 50 * time.Millisecond
+
+	absClient := &MockAudiobookshelfClient{}
+	svc.audiobookshelf = absClient
+	absClient.On("GetLibraryItems", mock.Anything, "lib1").Return([]models.AudiobookshelfBook{slow, fast}, nil)
+	mockClient.On("SearchBookByASINAnyFormat", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	var slowReturned atomic.Bool
+	mockClient.On("SearchBookByASIN", mock.Anything, "B0SLOWBOOK").Run(func(args mock.Arguments) {
+		<-args.Get(0).(context.Context).Done()
+		time.Sleep(20 * time.Millisecond)
+		slowReturned.Store(true)
+	}).Return(nil, context.DeadlineExceeded).Maybe()
+
+	start := time.Now()
+	processed, err := svc.processLibrary(context.Background(), &audiobookshelf.AudiobookshelfLibrary{ID: "lib1", Name: "Audiobooks"}, 0, nil)
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second, "the slow book should not stall the run")
+
+	assert.Equal(t, 1, processed, "only the fast book completes")
+	assert.True(t, slowReturned.Load(), "the slow book is done before the run moves on")
+
+	svc.summary.RLock()
+	defer svc.summary.RUnlock()
+	require.Len(t, svc.summary.BooksTimedOut, 1)
+	assert.Equal(t, "slow-book", svc.summary.BooksTimedOut[0].BookID)
+	require.Len(t, svc.summary.BooksNotFound, 1, "processing continues after the timeout")
+	assert.Equal(t, "fast-book", svc.summary.BooksNotFound[0].BookID)
+}
+
+func TestProcessBookWithTimeout(t *testing.T) {
+	book := models.AudiobookshelfBook{ID: "book-1", LibraryID: "lib1", MediaType: "book"}
+	book.Media.Metadata.Title = "Hanging Audiobook"
+	book.Media.Metadata.ASIN = "B0HANGING1"
+
+	setup := func(t *testing.T) *Service {
+		svc, mockClient := createTestService()
+		svc.summary = &SyncSummary{}
+		svc.config.Sync.PerBookTimeout = 50 * time.Millisecond
+
+		mockClient.On("SearchBookByASINAnyFormat", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+		mockClient.On("SearchBookByASIN", mock.Anything, "B0HANGING1").Return(nil, nil).Run(func(args mock.Arguments) {
+			<-args.Get(0).(context.Context).Done()
+		}).Maybe()
+		return svc
+	}
+
+	t.Run("deadline exceeded", func(t *testing.T) {
+		svc := setup(t)
+
+		err := svc.processBookWithTimeout(context.Background(), book, nil)
+		assert.ErrorIs(t, err, ErrBookTimeout)
+		assert.Equal(t, 1, svc.timedOutBookCount())
+	})
+
+	t.Run("run canceled", func(t *testing.T) {
+		svc := setup(t)
+		svc.config.Sync.PerBookTimeout = time.Minute
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(20*time.Millisecond, cancel)
+
+		err := svc.processBookWithTimeout(ctx, book, nil)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Zero(t, svc.timedOutBookCount(), "cancellation isn't recorded as a timeout")
+	})
+}
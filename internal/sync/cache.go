@@ -21,19 +21,42 @@ type ASINCacheEntry struct {
 
 // PersistentASINCache manages persistent ASIN cache storage
 type PersistentASINCache struct {
-	cacheFile string
-	entries   map[string]*ASINCacheEntry
+	cacheFile  string
+	entries    map[string]*ASINCacheEntry
 	defaultTTL time.Duration
+	// negativeTTL is how long failed lookups are cached, never longer than defaultTTL
+	negativeTTL time.Duration
 }
 
 // NewPersistentASINCache creates a new persistent ASIN cache
 func NewPersistentASINCache(cacheDir string) *PersistentASINCache {
 	cacheFile := filepath.Join(cacheDir, "asin_cache.json")
 	return &PersistentASINCache{
-		cacheFile:  cacheFile,
-		entries:    make(map[string]*ASINCacheEntry),
-		defaultTTL: 24 * time.Hour, // Cache entries for 24 hours by default
+		cacheFile:   cacheFile,
+		entries:     make(map[string]*ASINCacheEntry),
+		defaultTTL:  24 * time.Hour, // Cache entries for 24 hours by default
+		negativeTTL: 24 * time.Hour, // Lowered by SetNegativeTTL
+	}
+}
+
+// SetNegativeTTL sets how long failed lookups are cached, so books added to Hardcover later are
+// looked up again sooner. It's capped at the TTL of successful lookups; ttl <= 0 uses that TTL.
+// Entries already cached, including loaded ones, expire by the new TTL.
+func (c *PersistentASINCache) SetNegativeTTL(ttl time.Duration) {
+	if ttl <= 0 || ttl > c.defaultTTL {
+		ttl = c.defaultTTL
+	}
+	c.negativeTTL = ttl
+}
+
+// expired reports whether the entry has outlived its TTL. Failed lookups expire after at most the
+// negative TTL, whatever TTL they were stored with.
+func (c *PersistentASINCache) expired(entry *ASINCacheEntry, now time.Time) bool {
+	ttl := entry.TTL
+	if entry.Book == nil && c.negativeTTL > 0 && c.negativeTTL < ttl {
+		ttl = c.negativeTTL
 	}
+	return now.Sub(entry.Timestamp) >= ttl
 }
 
 // Load loads the cache from disk
@@ -65,7 +88,7 @@ func (c *PersistentASINCache) Load() error {
 	now := time.Now()
 	validEntries := make(map[string]*ASINCacheEntry)
 	for asin, entry := range entries {
-		if entry != nil && now.Sub(entry.Timestamp) < entry.TTL {
+		if entry != nil && !c.expired(entry, now) {
 			validEntries[asin] = entry
 		}
 	}
@@ -103,7 +126,7 @@ func (c *PersistentASINCache) Get(asin string) (*models.HardcoverBook, bool) {
 	}
 
 	// Check if entry is expired
-	if time.Since(entry.Timestamp) >= entry.TTL {
+	if c.expired(entry, time.Now()) {
 		delete(c.entries, asin)
 		return nil, false
 	}
@@ -111,16 +134,25 @@ func (c *PersistentASINCache) Get(asin string) (*models.HardcoverBook, bool) {
 	return entry.Book, true
 }
 
-// Set stores an entry in the cache
+// Set stores an entry in the cache. A nil book records a failed lookup, cached for the negative TTL.
 func (c *PersistentASINCache) Set(asin string, book *models.HardcoverBook) {
+	ttl := c.defaultTTL
+	if book == nil {
+		ttl = c.negativeTTL
+	}
 	c.entries[asin] = &ASINCacheEntry{
 		ASIN:      asin,
 		Book:      book,
 		Timestamp: time.Now(),
-		TTL:       c.defaultTTL,
+		TTL:       ttl,
 	}
 }
 
+// Delete removes an entry from the cache
+func (c *PersistentASINCache) Delete(asin string) {
+	delete(c.entries, asin)
+}
+
 // SetWithTTL stores an entry in the cache with custom TTL
 func (c *PersistentASINCache) SetWithTTL(asin string, book *models.HardcoverBook, ttl time.Duration) {
 	c.entries[asin] = &ASINCacheEntry{
@@ -160,7 +192,7 @@ func (c *PersistentASINCache) CleanExpired() int {
 	expiredCount := 0
 	
 	for asin, entry := range c.entries {
-		if now.Sub(entry.Timestamp) > entry.TTL {
+		if c.expired(entry, now) {
 			delete(c.entries, asin)
 			expiredCount++
 		}
@@ -0,0 +1,68 @@
+package sync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersistentASINCache_NegativeTTL(t *testing.T) {
+	cache := NewPersistentASINCache(t.TempDir())
+	cache.SetNegativeTTL(time.Hour)
+
+	cache.Set("B000FOUND", &models.HardcoverBook{ID: "10", EditionID: "100"})
+	cache.Set("B000MISSING", nil)
+
+	book, ok := cache.Get("B000MISSING")
+	require.True(t, ok, "fresh negative entries are cached")
+	assert.Nil(t, book)
+
+	// Two hours later the negative entry has expired while the positive one is still cached
+	for _, entry := range cache.entries {
+		entry.Timestamp = entry.Timestamp.Add(-2 * time.Hour)
+	}
+	_, ok = cache.Get("B000MISSING")
+	assert.False(t, ok, "negative entry expired")
+	book, ok = cache.Get("B000FOUND")
+	require.True(t, ok, "positive entry still cached")
+	assert.Equal(t, "10", book.ID)
+}
+
+func TestPersistentASINCache_NegativeTTLAppliesToLoadedEntries(t *testing.T) {
+	dir := t.TempDir()
+
+	// Entries saved before negative_cache_ttl was lowered
+	old := NewPersistentASINCache(dir)
+	old.Set("B000FOUND", &models.HardcoverBook{ID: "10"})
+	old.Set("B000MISSING", nil)
+	for _, entry := range old.entries {
+		entry.Timestamp = entry.Timestamp.Add(-2 * time.Hour)
+	}
+	require.NoError(t, old.Save())
+
+	cache := NewPersistentASINCache(dir)
+	cache.SetNegativeTTL(time.Hour)
+	require.NoError(t, cache.Load())
+
+	_, ok := cache.Get("B000MISSING")
+	assert.False(t, ok, "loaded negative entry expires by the new TTL")
+	_, ok = cache.Get("B000FOUND")
+	assert.True(t, ok)
+}
+
+func TestPersistentASINCache_SetNegativeTTLCapped(t *testing.T) {
+	cache := NewPersistentASINCache(t.TempDir())
+
+	cache.SetNegativeTTL(48 * time.Hour)
+	assert.Equal(t, cache.defaultTTL, cache.negativeTTL, "capped at the positive TTL")
+
+	cache.SetNegativeTTL(0)
+	assert.Equal(t, cache.defaultTTL, cache.negativeTTL, "unset uses the positive TTL")
+
+	cache.SetNegativeTTL(30 * time.Minute)
+	cache.Set("B000MISSING", nil)
+	assert.Equal(t, 30*time.Minute, cache.entries["B000MISSING"].TTL)
+}
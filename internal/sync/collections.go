@@ -0,0 +1,189 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/audiobookshelf"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// noteCollectionMatch records the Hardcover book an item was matched to this run, so syncing the
+// collections doesn't need to look it up again
+func (s *Service) noteCollectionMatch(itemID string, hcBook *models.HardcoverBook) {
+	if !s.config.Sync.SyncCollections || hcBook == nil || hcBook.ID == "" {
+		return
+	}
+	s.collectionMatchesMutex.Lock()
+	defer s.collectionMatchesMutex.Unlock()
+	if s.collectionMatches == nil {
+		s.collectionMatches = make(map[string]string)
+	}
+	s.collectionMatches[itemID] = hcBook.ID
+}
+
+// syncCollections keeps a Hardcover list with the matched books of every collection of the synced
+// libraries when Sync.SyncCollections is enabled. Lists are matched to collections by name,
+// ignoring case, and created when missing. Books removed from a collection aren't removed from its
+// list. Failures are logged, as the books themselves were synced.
+func (s *Service) syncCollections(ctx context.Context, libraries []audiobookshelf.AudiobookshelfLibrary) {
+	if !s.config.Sync.SyncCollections {
+		return
+	}
+	synced := make(map[string]*audiobookshelf.AudiobookshelfLibrary, len(libraries))
+	for i := range libraries {
+		synced[libraries[i].ID] = &libraries[i]
+	}
+
+	collections, err := s.audiobookshelf.GetCollections(ctx)
+	if err != nil {
+		s.log.Warn("Failed to fetch collections from Audiobookshelf, not syncing them", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	if len(collections) == 0 {
+		return
+	}
+
+	lists, err := s.hardcover.GetUserLists(ctx)
+	if err != nil {
+		s.log.Warn("Failed to fetch lists from Hardcover, not syncing collections", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	listsByName := make(map[string]hardcover.List, len(lists))
+	for _, list := range lists {
+		listsByName[collectionListKey(list.Name)] = list
+	}
+
+	for _, collection := range collections {
+		if ctx.Err() != nil {
+			return
+		}
+		library, ok := synced[collection.LibraryID]
+		if !ok {
+			continue
+		}
+		list, exists := listsByName[collectionListKey(collection.Name)]
+		// Collections of a library overridden into dry-run mode are only planned like its books
+		s.syncCollection(s.withLibraryOverride(ctx, library), collection, list, exists)
+	}
+}
+
+// syncCollection adds the matched books of a collection that aren't on its Hardcover list yet to
+// the list, creating the list first if it doesn't exist
+func (s *Service) syncCollection(ctx context.Context, collection audiobookshelf.AudiobookshelfCollection, list hardcover.List, exists bool) {
+	log := s.log.With(map[string]interface{}{
+		"collection_id": collection.ID,
+		"collection":    collection.Name,
+	})
+	if strings.TrimSpace(collection.Name) == "" {
+		return
+	}
+
+	onList := make(map[int]struct{}, len(list.BookIDs))
+	for _, bookID := range list.BookIDs {
+		onList[bookID] = struct{}{}
+	}
+
+	var toAdd []int
+	for _, item := range collection.Books {
+		if s.blocked(item) {
+			continue
+		}
+		bookID := s.collectionBookID(ctx, item)
+		if bookID == 0 {
+			continue
+		}
+		if _, ok := onList[bookID]; ok {
+			continue
+		}
+		onList[bookID] = struct{}{}
+		toAdd = append(toAdd, bookID)
+		log.Info("Book to add to Hardcover list", map[string]interface{}{
+			"item_id": item.ID,
+			"title":   item.Media.Metadata.Title,
+			"book_id": bookID,
+		})
+	}
+	if len(toAdd) == 0 {
+		log.Debug("Hardcover list already has all books of the collection", nil)
+		return
+	}
+
+	if s.dryRun(ctx) {
+		log.Info("Dry-run mode: skipping adding books to Hardcover list", map[string]interface{}{
+			"create_list": !exists,
+			"books":       len(toAdd),
+		})
+		return
+	}
+
+	if !exists {
+		listID, err := s.hardcover.CreateList(ctx, collection.Name)
+		if err != nil {
+			log.Error("Failed to create Hardcover list for collection", map[string]interface{}{
+				"error": err.Error(),
+			})
+			return
+		}
+		list = hardcover.List{ID: listID, Name: collection.Name}
+		log.Info("Created Hardcover list for collection", map[string]interface{}{
+			"list_id": listID,
+		})
+	}
+
+	added := 0
+	for _, bookID := range toAdd {
+		if err := s.hardcover.AddBookToList(ctx, list.ID, bookID); err != nil {
+			log.Warn("Failed to add book to Hardcover list", map[string]interface{}{
+				"list_id": list.ID,
+				"book_id": bookID,
+				"error":   err.Error(),
+			})
+			continue
+		}
+		added++
+	}
+	log.Info("Added books of collection to Hardcover list", map[string]interface{}{
+		"list_id": list.ID,
+		"added":   added,
+	})
+}
+
+// collectionBookID returns the Hardcover book ID of a collection item, 0 if it isn't matched.
+// Items processed this run use their match; others are looked up, where books only found by
+// title/author don't count as matched.
+func (s *Service) collectionBookID(ctx context.Context, item models.AudiobookshelfBook) int {
+	s.collectionMatchesMutex.Lock()
+	hcBookID, matched := s.collectionMatches[item.ID]
+	s.collectionMatchesMutex.Unlock()
+
+	if !matched {
+		hcBook, _, err := s.findBookInHardcover(ctx, item)
+		if (err != nil && !errors.Is(err, errASINInOtherFormat)) || hcBook == nil {
+			s.log.Debug("Collection item not matched to a Hardcover book", map[string]interface{}{
+				"item_id": item.ID,
+				"title":   item.Media.Metadata.Title,
+			})
+			return 0
+		}
+		hcBookID = hcBook.ID
+	}
+
+	bookID, err := strconv.Atoi(hcBookID)
+	if err != nil {
+		return 0
+	}
+	return bookID
+}
+
+// collectionListKey is the key matching a collection to the Hardcover list of the same name
+func collectionListKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
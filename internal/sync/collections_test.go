@@ -0,0 +1,110 @@
+package sync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/audiobookshelf"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSyncCollections(t *testing.T) {
+	logger.Setup(logger.Config{Level: "debug"})
+
+	item := func(id, title string) models.AudiobookshelfBook {
+		var book models.AudiobookshelfBook
+		book.ID = id
+		book.Media.Metadata.Title = title
+		book.Media.Metadata.AuthorName = "Author"
+		return book
+	}
+	collections := []audiobookshelf.AudiobookshelfCollection{
+		{ID: "col_1", LibraryID: "lib1", Name: "Favorites", Books: []models.AudiobookshelfBook{
+			item("li_a", "On List"), item("li_b", "Not On List"), item("li_c", "Unmatched"),
+		}},
+		{ID: "col_2", LibraryID: "lib1", Name: "New Collection", Books: []models.AudiobookshelfBook{
+			item("li_d", "New Book"),
+		}},
+		{ID: "col_3", LibraryID: "lib2", Name: "Other Library", Books: []models.AudiobookshelfBook{
+			item("li_e", "Skipped"),
+		}},
+	}
+	libraries := []audiobookshelf.AudiobookshelfLibrary{{ID: "lib1", Name: "Audiobooks"}}
+
+	tests := []struct {
+		name       string
+		dryRun     bool
+		libraryDry bool
+	}{
+		{name: "sync"},
+		{name: "dry run", dryRun: true},
+		{name: "library in dry-run mode", libraryDry: true},
+	}
+
+	for _, tt := range tests {
+		dryRun := tt.dryRun || tt.libraryDry
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Sync.SyncCollections = true
+			cfg.Sync.DryRun = tt.dryRun
+			if tt.libraryDry {
+				cfg.Sync.Libraries.Overrides = map[string]config.LibraryOverride{
+					"audiobooks": {DryRun: true},
+				}
+			}
+
+			absClient := new(MockAudiobookshelfClient)
+			absClient.On("GetCollections", mock.Anything).Return(collections, nil)
+			hcClient := new(MockHardcoverClient)
+			hcClient.On("GetUserLists", mock.Anything).Return([]hardcover.List{
+				{ID: 7, Name: "favorites", BookIDs: []int{101}},
+			}, nil)
+			hcClient.On("SearchBooks", mock.Anything, mock.Anything, mock.Anything).Return([]models.HardcoverBook{}, nil).Maybe()
+			if !dryRun {
+				hcClient.On("AddBookToList", mock.Anything, 7, 102).Return(nil).Once()
+				hcClient.On("CreateList", mock.Anything, "New Collection").Return(9, nil).Once()
+				hcClient.On("AddBookToList", mock.Anything, 9, 104).Return(nil).Once()
+			}
+
+			svc := &Service{
+				audiobookshelf: absClient,
+				hardcover:      hcClient,
+				config:         cfg,
+				log:            logger.Get(),
+			}
+			svc.noteCollectionMatch("li_a", &models.HardcoverBook{ID: "101"})
+			svc.noteCollectionMatch("li_b", &models.HardcoverBook{ID: "102"})
+			svc.noteCollectionMatch("li_d", &models.HardcoverBook{ID: "104"})
+			svc.noteCollectionMatch("li_e", &models.HardcoverBook{ID: "105"})
+
+			svc.syncCollections(context.Background(), libraries)
+
+			absClient.AssertExpectations(t)
+			hcClient.AssertExpectations(t)
+			if dryRun {
+				hcClient.AssertNotCalled(t, "CreateList", mock.Anything, mock.Anything)
+				hcClient.AssertNotCalled(t, "AddBookToList", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestSyncCollections_Disabled(t *testing.T) {
+	absClient := new(MockAudiobookshelfClient)
+	svc := &Service{
+		audiobookshelf: absClient,
+		config:         config.DefaultConfig(),
+		log:            logger.Get(),
+	}
+	svc.noteCollectionMatch("li_a", &models.HardcoverBook{ID: "101"})
+	svc.syncCollections(context.Background(), nil)
+
+	absClient.AssertNotCalled(t, "GetCollections", mock.Anything)
+	if svc.collectionMatches != nil {
+		t.Errorf("matches must not be recorded while collections aren't synced")
+	}
+}
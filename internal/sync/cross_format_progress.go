@@ -0,0 +1,26 @@
+package sync
+
+import (
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// applyEbookProgress sets the listening position of an item that also has audio from the share of
+// its ebook read when Sync.CrossFormatProgress is enabled. Audiobookshelf tracks ebook progress
+// apart from the audio, so without it an item read as an ebook looks unstarted. Audio progress,
+// when there is any, always takes precedence. Returns whether the ebook progress was applied.
+func (s *Service) applyEbookProgress(book *models.AudiobookshelfBook, ebookProgress float64) bool {
+	if !s.config.Sync.CrossFormatProgress || ebookProgress <= 0 || book.Media.Duration <= 0 || book.Progress.CurrentTime > 0 {
+		return false
+	}
+	if ebookProgress > 1 {
+		ebookProgress = 1
+	}
+
+	book.Progress.CurrentTime = ebookProgress * book.Media.Duration
+	s.log.Debug("Using ebook progress for the audio edition", map[string]interface{}{
+		"book_id":        book.ID,
+		"ebook_progress": ebookProgress,
+		"current_time":   book.Progress.CurrentTime,
+	})
+	return true
+}
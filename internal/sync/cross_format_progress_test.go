@@ -0,0 +1,62 @@
+package sync
+
+import (
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyUserProgress_CrossFormatProgress(t *testing.T) {
+	// newUserProgress returns media progress for a hybrid item with the given audio position and
+	// share of the ebook read
+	newUserProgress := func(currentTime, ebookProgress float64) *models.AudiobookshelfUserProgress {
+		userProgress := &models.AudiobookshelfUserProgress{}
+		userProgress.MediaProgress = append(userProgress.MediaProgress, struct {
+			ID                        string  `json:"id"`
+			LibraryItemID             string  `json:"libraryItemId"`
+			UserID                    string  `json:"userId"`
+			IsFinished                bool    `json:"isFinished"`
+			Progress                  float64 `json:"progress"`
+			CurrentTime               float64 `json:"currentTime"`
+			Duration                  float64 `json:"duration"`
+			StartedAt                 int64   `json:"startedAt"`
+			FinishedAt                int64   `json:"finishedAt"`
+			LastUpdate                int64   `json:"lastUpdate"`
+			TimeListening             float64 `json:"timeListening"`
+			EbookProgress             float64 `json:"ebookProgress"`
+			HideFromContinueListening bool    `json:"hideFromContinueListening"`
+		}{LibraryItemID: "book-1", CurrentTime: currentTime, EbookProgress: ebookProgress, LastUpdate: 100})
+		return userProgress
+	}
+
+	tests := []struct {
+		name          string
+		enabled       bool
+		currentTime   float64
+		ebookProgress float64
+		duration      float64
+		expectedTime  float64
+	}{
+		{"ebook progress ignored by default", false, 0, 0.25, 4000, 0},
+		{"ebook progress mapped to the audio", true, 0, 0.25, 4000, 1000},
+		{"audio progress takes precedence", true, 500, 0.25, 4000, 500},
+		{"ebook progress capped at the end", true, 0, 1.5, 4000, 4000},
+		{"items without audio are left alone", true, 0, 0.25, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := createTestService()
+			svc.config.Sync.CrossFormatProgress = tt.enabled
+
+			book := models.AudiobookshelfBook{ID: "book-1"}
+			book.Media.Duration = tt.duration
+			source := svc.applyUserProgress(&book, newUserProgress(tt.currentTime, tt.ebookProgress))
+
+			assert.Equal(t, config.ProgressSourceMedia, source)
+			assert.InDelta(t, tt.expectedTime, book.Progress.CurrentTime, 0.001)
+		})
+	}
+}
@@ -17,12 +17,13 @@ func TestDetermineBookStatus(t *testing.T) {
 	now := time.Now().Unix()
 
 	tests := []struct {
-		name           string
-		syncWantToRead bool
-		progress       float64
-		isFinished     bool
-		finishedAt     int64
-		expected       string
+		name              string
+		syncWantToRead    bool
+		finishedThreshold float64
+		progress          float64
+		isFinished        bool
+		finishedAt        int64
+		expected          string
 	}{
 		{
 			name:           "finished with timestamp - want to read enabled",
@@ -64,6 +65,40 @@ func TestDetermineBookStatus(t *testing.T) {
 			finishedAt:     0,
 			expected:       "",
 		},
+		{
+			name:           "99% progress - default threshold",
+			syncWantToRead: true,
+			progress:       0.99,
+			isFinished:     false,
+			finishedAt:     0,
+			expected:       "FINISHED",
+		},
+		{
+			name:           "98% progress - default threshold",
+			syncWantToRead: true,
+			progress:       0.978,
+			isFinished:     false,
+			finishedAt:     0,
+			expected:       "IN_PROGRESS",
+		},
+		{
+			name:              "97.8% progress - lower threshold",
+			syncWantToRead:    true,
+			finishedThreshold: 0.97,
+			progress:          0.978,
+			isFinished:        false,
+			finishedAt:        0,
+			expected:          "FINISHED",
+		},
+		{
+			name:              "99.5% progress - threshold of 1",
+			syncWantToRead:    true,
+			finishedThreshold: 1,
+			progress:          0.995,
+			isFinished:        false,
+			finishedAt:        0,
+			expected:          "IN_PROGRESS",
+		},
 		{
 			name:           "finished but no timestamp",
 			syncWantToRead: true,
@@ -79,6 +114,9 @@ func TestDetermineBookStatus(t *testing.T) {
 			// Create a service instance with the specific config for this test case
 			cfg := config.DefaultConfig()
 			cfg.Sync.SyncWantToRead = tt.syncWantToRead
+			if tt.finishedThreshold > 0 {
+				cfg.Sync.FinishedThreshold = tt.finishedThreshold
+			}
 			svc := &Service{
 				config: cfg,
 			}
@@ -0,0 +1,191 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// syncProgressToAudiobookshelf writes the Hardcover progress of a book being read to Audiobookshelf
+// when Sync.Direction and Sync.ConflictResolution let Hardcover's progress win. It returns whether
+// Hardcover's progress won, in which case the Audiobookshelf progress must not be written to
+// Hardcover.
+func (s *Service) syncProgressToAudiobookshelf(ctx context.Context, book models.AudiobookshelfBook, userBookID int64, reads []hardcover.UserBookRead, log *logger.Logger) (bool, error) {
+	direction := s.config.Sync.Direction
+	if direction == "" || direction == config.SyncDirectionABSToHC {
+		return false, nil
+	}
+
+	hcProgress := unfinishedReadProgress(reads)
+	if hcProgress == nil {
+		// Nothing to write, and in hc_to_abs mode Hardcover isn't written either
+		return direction == config.SyncDirectionHCToABS, nil
+	}
+
+	if direction == config.SyncDirectionBoth && !s.hardcoverProgressIsNewer(ctx, book, userBookID, log) {
+		return false, nil
+	}
+
+	log = log.With(map[string]interface{}{
+		"direction":          direction,
+		"abs_progress":       book.Progress.CurrentTime,
+		"hardcover_progress": *hcProgress,
+	})
+
+	// Progress both sides agree on needs no update, which also keeps an update of one side from
+	// being written back to it on the next run
+	if math.Abs(float64(*hcProgress)-book.Progress.CurrentTime) < float64(s.config.Sync.MinChangeThreshold) {
+		log.Debug("Audiobookshelf progress already matches the Hardcover progress", nil)
+		return true, nil
+	}
+
+	isFinished := false
+	if threshold := s.config.Sync.FinishedThreshold; threshold > 0 && book.Media.Duration > 0 {
+		isFinished = float64(*hcProgress) >= book.Media.Duration*threshold
+	}
+
+	if s.dryRun(ctx) {
+		log.Info("Dry-run mode: skipping update of Audiobookshelf progress", nil)
+		return true, nil
+	}
+	if s.audiobookshelf == nil {
+		return true, fmt.Errorf("no Audiobookshelf client to write the progress with")
+	}
+
+	if err := s.audiobookshelf.UpdateMediaProgress(ctx, book.ID, float64(*hcProgress), isFinished); err != nil {
+		log.Error("Failed to write Hardcover progress to Audiobookshelf", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return true, fmt.Errorf("failed to update Audiobookshelf progress: %w", err)
+	}
+
+	log.Info("Synced Hardcover progress to Audiobookshelf", map[string]interface{}{
+		"is_finished": isFinished,
+	})
+	return true, nil
+}
+
+// pullsFromHardcover reports whether Sync.Direction writes Hardcover progress to Audiobookshelf.
+// Progress made only on Hardcover leaves the Audiobookshelf item unchanged, so such runs fetch and
+// process every item instead of skipping unchanged ones.
+func (s *Service) pullsFromHardcover() bool {
+	direction := s.config.Sync.Direction
+	return direction == config.SyncDirectionHCToABS || direction == config.SyncDirectionBoth
+}
+
+// pullHardcoverProgress writes the Hardcover progress of a book that isn't in progress in
+// Audiobookshelf, e.g. one started on the Hardcover web app, to Audiobookshelf when Sync.Direction
+// lets it win. It returns whether it did, in which case the Audiobookshelf status must not be
+// written to Hardcover.
+func (s *Service) pullHardcoverProgress(ctx context.Context, book models.AudiobookshelfBook, userBookID int64, log *logger.Logger) (bool, error) {
+	if !s.pullsFromHardcover() {
+		return false, nil
+	}
+
+	reads, err := s.hardcover.GetUserBookReads(ctx, hardcover.GetUserBookReadsInput{
+		UserBookID: userBookID,
+		Status:     "unfinished",
+	})
+	if err != nil {
+		log.Warn("Failed to get Hardcover reads, not syncing Hardcover progress to Audiobookshelf", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return false, nil
+	}
+	if unfinishedReadProgress(reads) == nil {
+		return false, nil
+	}
+	return s.syncProgressToAudiobookshelf(ctx, book, userBookID, reads, log)
+}
+
+// lookupOnlyKey is the context key marking a book that's only looked up in Hardcover
+type lookupOnlyKey struct{}
+
+// errLookupOnly is returned instead of a user book while a book is only looked up in Hardcover,
+// see withLookupOnly
+var errLookupOnly = errors.New("only looking the book up, not creating a user book")
+
+// withLookupOnly returns a context in which finding a book in Hardcover doesn't change the user's
+// Hardcover library: no user book is created, no edition is marked as owned and no book is tagged
+func withLookupOnly(ctx context.Context) context.Context {
+	return context.WithValue(ctx, lookupOnlyKey{}, true)
+}
+
+// lookupOnly reports whether ctx is from withLookupOnly
+func lookupOnly(ctx context.Context) bool {
+	only, _ := ctx.Value(lookupOnlyKey{}).(bool)
+	return only
+}
+
+// pullUnstartedBook writes the Hardcover progress of a book that's unstarted in Audiobookshelf to
+// Audiobookshelf. Unlike processed books it's only looked up, never added to the user's Hardcover
+// library or recorded as a mismatch, since an unstarted book has nothing to sync otherwise.
+func (s *Service) pullUnstartedBook(ctx context.Context, book models.AudiobookshelfBook, log *logger.Logger) error {
+	hcBook, _, err := s.findBookInHardcover(withLookupOnly(ctx), book)
+	if err != nil || hcBook == nil || hcBook.EditionID == "" {
+		return nil
+	}
+	editionID, err := strconv.Atoi(hcBook.EditionID)
+	if err != nil {
+		return nil
+	}
+
+	userBookID, err := s.hardcover.GetUserBookID(ctx, editionID)
+	if err != nil {
+		return fmt.Errorf("error checking for existing user book ID: %w", err)
+	}
+	if userBookID <= 0 {
+		return nil
+	}
+
+	if _, err := s.pullHardcoverProgress(ctx, book, int64(userBookID), log); err != nil {
+		return fmt.Errorf("error syncing Hardcover progress to Audiobookshelf: %w", err)
+	}
+	return nil
+}
+
+// hardcoverProgressIsNewer decides, per Sync.ConflictResolution, whether the Hardcover progress of
+// a book wins over its Audiobookshelf progress when the sync goes both ways
+func (s *Service) hardcoverProgressIsNewer(ctx context.Context, book models.AudiobookshelfBook, userBookID int64, log *logger.Logger) bool {
+	switch s.config.Sync.ConflictResolution {
+	case config.ConflictResolutionABS:
+		return false
+	case config.ConflictResolutionHardcover:
+		return true
+	}
+
+	// The user book is fetched rather than taken from the cache, which may be from earlier runs
+	userBook, err := s.hardcover.GetUserBook(ctx, strconv.FormatInt(userBookID, 10))
+	if err != nil || userBook == nil || userBook.UpdatedAt == "" {
+		log.Debug("No Hardcover update time, keeping the Audiobookshelf progress", nil)
+		return false
+	}
+	hcUpdated, err := time.Parse(time.RFC3339, userBook.UpdatedAt)
+	if err != nil {
+		log.Warn("Invalid Hardcover update time, keeping the Audiobookshelf progress", map[string]interface{}{
+			"updated_at": userBook.UpdatedAt,
+			"error":      err.Error(),
+		})
+		return false
+	}
+
+	// Without an update time the Audiobookshelf progress may be of any age, not from 1970
+	if book.Progress.LastUpdate <= 0 {
+		log.Debug("No Audiobookshelf update time, keeping the Audiobookshelf progress", nil)
+		return false
+	}
+	absUpdated := time.UnixMilli(book.Progress.LastUpdate)
+	log.Debug("Comparing progress update times", map[string]interface{}{
+		"abs_updated_at":       absUpdated.Format(time.RFC3339),
+		"hardcover_updated_at": hcUpdated.Format(time.RFC3339),
+	})
+	return hcUpdated.After(absUpdated)
+}
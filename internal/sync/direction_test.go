@@ -0,0 +1,355 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncProgressToAudiobookshelf(t *testing.T) {
+	logger.Setup(logger.Config{Level: "debug"})
+
+	absUpdated := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	newer := absUpdated.Add(time.Hour).Format(time.RFC3339)
+	older := absUpdated.Add(-time.Hour).Format(time.RFC3339)
+	seconds := func(s int) *int { return &s }
+
+	tests := []struct {
+		name               string
+		direction          string
+		conflictResolution string
+		hcProgress         *int
+		hcUpdatedAt        string
+		expectHardcoverWin bool
+		expectWrite        bool
+		expectTime         float64
+		expectFinished     bool
+	}{
+		{
+			name:       "abs_to_hc never writes to Audiobookshelf",
+			direction:  config.SyncDirectionABSToHC,
+			hcProgress: seconds(3000),
+		},
+		{
+			name:               "hc_to_abs writes differing progress",
+			direction:          config.SyncDirectionHCToABS,
+			hcProgress:         seconds(3000),
+			expectHardcoverWin: true,
+			expectWrite:        true,
+			expectTime:         3000,
+		},
+		{
+			name:               "hc_to_abs without Hardcover progress writes nothing",
+			direction:          config.SyncDirectionHCToABS,
+			expectHardcoverWin: true,
+		},
+		{
+			name:               "hc_to_abs skips matching progress",
+			direction:          config.SyncDirectionHCToABS,
+			hcProgress:         seconds(1830),
+			expectHardcoverWin: true,
+		},
+		{
+			name:               "hc_to_abs finishes books past the finished threshold",
+			direction:          config.SyncDirectionHCToABS,
+			hcProgress:         seconds(3580),
+			expectHardcoverWin: true,
+			expectWrite:        true,
+			expectTime:         3580,
+			expectFinished:     true,
+		},
+		{
+			name:               "both with newer Hardcover progress",
+			direction:          config.SyncDirectionBoth,
+			conflictResolution: config.ConflictResolutionNewest,
+			hcProgress:         seconds(3000),
+			hcUpdatedAt:        newer,
+			expectHardcoverWin: true,
+			expectWrite:        true,
+			expectTime:         3000,
+		},
+		{
+			name:               "both with newer Audiobookshelf progress",
+			direction:          config.SyncDirectionBoth,
+			conflictResolution: config.ConflictResolutionNewest,
+			hcProgress:         seconds(3000),
+			hcUpdatedAt:        older,
+		},
+		{
+			name:               "both preferring Audiobookshelf",
+			direction:          config.SyncDirectionBoth,
+			conflictResolution: config.ConflictResolutionABS,
+			hcProgress:         seconds(3000),
+			hcUpdatedAt:        newer,
+		},
+		{
+			name:               "both preferring Hardcover",
+			direction:          config.SyncDirectionBoth,
+			conflictResolution: config.ConflictResolutionHardcover,
+			hcProgress:         seconds(600),
+			hcUpdatedAt:        older,
+			expectHardcoverWin: true,
+			expectWrite:        true,
+			expectTime:         600,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Sync.Direction = tt.direction
+			if tt.conflictResolution != "" {
+				cfg.Sync.ConflictResolution = tt.conflictResolution
+			}
+
+			absClient := new(MockAudiobookshelfClient)
+			if tt.expectWrite {
+				absClient.On("UpdateMediaProgress", mock.Anything, "li_1", tt.expectTime, tt.expectFinished).Return(nil).Once()
+			}
+			hcClient := new(MockHardcoverClient)
+			hcClient.On("GetUserBook", mock.Anything, "42").
+				Return(&models.HardcoverBook{ID: "hc-1", UserBookID: "42", UpdatedAt: tt.hcUpdatedAt}, nil).Maybe()
+
+			svc := &Service{
+				audiobookshelf: absClient,
+				hardcover:      hcClient,
+				config:         cfg,
+				log:            logger.Get(),
+			}
+
+			var book models.AudiobookshelfBook
+			book.ID = "li_1"
+			book.Media.Duration = 3600
+			book.Progress.CurrentTime = 1800
+			book.Progress.LastUpdate = absUpdated.UnixMilli()
+
+			var reads []hardcover.UserBookRead
+			if tt.hcProgress != nil {
+				reads = append(reads, hardcover.UserBookRead{ID: 1, UserBookID: 42, ProgressSeconds: tt.hcProgress})
+			}
+
+			hardcoverWins, err := svc.syncProgressToAudiobookshelf(context.Background(), book, 42, reads, svc.log)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectHardcoverWin, hardcoverWins)
+			absClient.AssertExpectations(t)
+			if !tt.expectWrite {
+				absClient.AssertNotCalled(t, "UpdateMediaProgress", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestSyncProgressToAudiobookshelf_UserProgressUpdateTime(t *testing.T) {
+	logger.Setup(logger.Config{Level: "debug"})
+
+	absUpdated := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	hcUpdated := absUpdated.Add(-time.Hour).Format(time.RFC3339)
+	progressSeconds := 3000
+
+	tests := []struct {
+		name         string
+		userProgress string
+	}{
+		{
+			name:         "media progress",
+			userProgress: fmt.Sprintf(`{"mediaProgress":[{"libraryItemId":"li_1","currentTime":1800,"lastUpdate":%d}]}`, absUpdated.UnixMilli()),
+		},
+		{
+			name:         "listening session",
+			userProgress: fmt.Sprintf(`{"listeningSessions":[{"libraryItemId":"li_1","currentTime":1800,"updatedAt":%d}]}`, absUpdated.UnixMilli()),
+		},
+		{
+			name:         "no update time",
+			userProgress: `{"mediaProgress":[{"libraryItemId":"li_1","currentTime":1800}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Sync.Direction = config.SyncDirectionBoth
+			cfg.Sync.ConflictResolution = config.ConflictResolutionNewest
+
+			absClient := new(MockAudiobookshelfClient)
+			hcClient := new(MockHardcoverClient)
+			hcClient.On("GetUserBook", mock.Anything, "42").
+				Return(&models.HardcoverBook{ID: "hc-1", UserBookID: "42", UpdatedAt: hcUpdated}, nil).Maybe()
+			svc := &Service{
+				audiobookshelf: absClient,
+				hardcover:      hcClient,
+				config:         cfg,
+				log:            logger.Get(),
+			}
+
+			// The item was fetched without progress, which only /api/me has
+			var book models.AudiobookshelfBook
+			book.ID = "li_1"
+			book.Media.Duration = 3600
+			userProgress := &models.AudiobookshelfUserProgress{}
+			require.NoError(t, json.Unmarshal([]byte(tt.userProgress), userProgress))
+			require.NotEmpty(t, svc.applyUserProgress(&book, userProgress))
+
+			// The Audiobookshelf progress is kept rather than overwritten by older Hardcover progress
+			reads := []hardcover.UserBookRead{{ID: 1, UserBookID: 42, ProgressSeconds: &progressSeconds}}
+			hardcoverWins, err := svc.syncProgressToAudiobookshelf(context.Background(), book, 42, reads, svc.log)
+			require.NoError(t, err)
+			assert.False(t, hardcoverWins)
+			absClient.AssertNotCalled(t, "UpdateMediaProgress", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestProcessBook_PullsHardcoverOnlyChanges(t *testing.T) {
+	lastSync := time.Now().Add(-time.Hour)
+	before := lastSync.Add(-time.Minute).UnixMilli()
+
+	tests := []struct {
+		name        string
+		currentTime float64
+	}{
+		{name: "book in progress in Audiobookshelf", currentTime: 1800},
+		{name: "book unstarted in Audiobookshelf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, hcClient := createTestService()
+			svc.summary = &SyncSummary{}
+			svc.persistentCache = NewPersistentASINCache(t.TempDir())
+			svc.userBookCache = NewPersistentUserBookCache(t.TempDir())
+			svc.config.Sync.Direction = config.SyncDirectionHCToABS
+			svc.config.Sync.Incremental = true
+			svc.config.Sync.SkipUnchangedBooks = true
+			svc.config.Sync.ProcessUnreadBooks = false
+			svc.updatedSince = lastSync
+
+			// Nothing changed in Audiobookshelf since the last sync
+			book := models.AudiobookshelfBook{ID: "li_1", LibraryID: "lib1", MediaType: "book", UpdatedAt: before}
+			book.Media.Metadata.Title = "Read on Hardcover"
+			book.Media.Metadata.ASIN = "B000000001"
+			book.Media.Duration = 3600
+			book.Progress.CurrentTime = tt.currentTime
+			book.Progress.LastUpdate = before
+			status := "IN_PROGRESS"
+			if tt.currentTime == 0 {
+				status = "WANT_TO_READ"
+			}
+			svc.state.UpdateBook("li_1:200", tt.currentTime/3600, status)
+
+			// The book was read further on Hardcover
+			hcClient.On("SearchBookByASIN", mock.Anything, "B000000001").
+				Return(&models.HardcoverBook{ID: "10", EditionID: "200", EditionASIN: "B000000001"}, nil)
+			hcClient.On("GetUserBookID", mock.Anything, 200).Return(300, nil)
+			hcClient.On("GetUserBook", mock.Anything, "300").Return(&models.HardcoverBook{ID: "10", EditionID: "200"}, nil).Maybe()
+			progressSeconds := 3000
+			hcClient.On("GetUserBookReads", mock.Anything, mock.Anything).
+				Return([]hardcover.UserBookRead{{ID: 1, UserBookID: 300, ProgressSeconds: &progressSeconds}}, nil)
+
+			absClient := new(MockAudiobookshelfClient)
+			absClient.On("UpdateMediaProgress", mock.Anything, "li_1", 3000.0, false).Return(nil).Once()
+			svc.audiobookshelf = absClient
+
+			require.NoError(t, svc.processBook(context.Background(), book, nil))
+			absClient.AssertExpectations(t)
+			hcClient.AssertNotCalled(t, "InsertUserBookRead", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestProcessBook_OnlyLooksUpUnstartedBooks(t *testing.T) {
+	svc, hcClient := createTestService()
+	svc.summary = &SyncSummary{}
+	svc.persistentCache = NewPersistentASINCache(t.TempDir())
+	svc.userBookCache = NewPersistentUserBookCache(t.TempDir())
+	svc.config.Sync.Direction = config.SyncDirectionHCToABS
+	svc.config.Sync.DryRun = false
+	svc.config.Sync.SyncOwned = true
+	svc.config.Sync.SyncWantToRead = true
+	svc.config.Sync.ProcessUnreadBooks = false
+	svc.config.Sync.SourceTag = "abs-sync"
+
+	book := models.AudiobookshelfBook{ID: "li_1", LibraryID: "lib1", MediaType: "book"}
+	book.Media.Metadata.Title = "Never Started"
+	book.Media.Metadata.ASIN = "B000000001"
+	book.Media.Duration = 3600
+
+	// The book isn't in the user's Hardcover library
+	hcClient.On("SearchBookByASIN", mock.Anything, "B000000001").
+		Return(&models.HardcoverBook{ID: "10", EditionID: "200", EditionASIN: "B000000001"}, nil)
+	hcClient.On("GetUserBookID", mock.Anything, 200).Return(0, nil)
+
+	absClient := new(MockAudiobookshelfClient)
+	svc.audiobookshelf = absClient
+
+	require.NoError(t, svc.processBook(context.Background(), book, nil))
+	hcClient.AssertNotCalled(t, "CreateUserBook", mock.Anything, mock.Anything, mock.Anything)
+	hcClient.AssertNotCalled(t, "MarkEditionAsOwned", mock.Anything, mock.Anything)
+	hcClient.AssertNotCalled(t, "AddTagToBook", mock.Anything, mock.Anything, mock.Anything)
+	absClient.AssertNotCalled(t, "UpdateMediaProgress", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProcessBook_KeepsBooksFinishedInAudiobookshelf(t *testing.T) {
+	tests := []struct {
+		name               string
+		direction          string
+		conflictResolution string
+	}{
+		{name: "hc_to_abs", direction: config.SyncDirectionHCToABS},
+		{name: "both preferring Hardcover", direction: config.SyncDirectionBoth, conflictResolution: config.ConflictResolutionHardcover},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, hcClient := createTestService()
+			svc.summary = &SyncSummary{}
+			svc.persistentCache = NewPersistentASINCache(t.TempDir())
+			svc.userBookCache = NewPersistentUserBookCache(t.TempDir())
+			svc.config.Sync.Direction = tt.direction
+			svc.config.Sync.ConflictResolution = tt.conflictResolution
+			svc.config.Sync.DryRun = false
+
+			finishedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+			book := models.AudiobookshelfBook{ID: "li_1", LibraryID: "lib1", MediaType: "book"}
+			book.Media.Metadata.Title = "Finished in Audiobookshelf"
+			book.Media.Metadata.ASIN = "B000000001"
+			book.Media.Duration = 3600
+			book.Progress.CurrentTime = 3600
+			book.Progress.IsFinished = true
+			book.Progress.FinishedAt = finishedAt.UnixMilli()
+
+			// Hardcover still has a stale unfinished read
+			hcClient.On("SearchBookByASIN", mock.Anything, "B000000001").
+				Return(&models.HardcoverBook{ID: "10", EditionID: "200", EditionASIN: "B000000001"}, nil)
+			hcClient.On("GetUserBookID", mock.Anything, 200).Return(300, nil)
+			hcClient.On("GetUserBook", mock.Anything, "300").Return(&models.HardcoverBook{ID: "10", EditionID: "200"}, nil).Maybe()
+			progressSeconds := 1200
+			hcClient.On("GetUserBookReads", mock.Anything, mock.Anything).
+				Return([]hardcover.UserBookRead{{ID: 1, UserBookID: 300, ProgressSeconds: &progressSeconds}}, nil).Maybe()
+			hcClient.On("CheckExistingUserBookRead", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+			hcClient.On("UpdateUserBookRead", mock.Anything, mock.Anything).Return(true, nil).Maybe()
+			hcClient.On("UpdateUserBookStatus", mock.Anything, mock.Anything).Return(nil).Maybe()
+			hcClient.On("InsertUserBookRead", mock.Anything, mock.Anything).Return(1, nil).Maybe()
+
+			absClient := new(MockAudiobookshelfClient)
+			svc.audiobookshelf = absClient
+
+			require.NoError(t, svc.processBook(context.Background(), book, nil))
+			absClient.AssertNotCalled(t, "UpdateMediaProgress", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			if tt.direction == config.SyncDirectionHCToABS {
+				hcClient.AssertNotCalled(t, "InsertUserBookRead", mock.Anything, mock.Anything)
+				hcClient.AssertNotCalled(t, "UpdateUserBookRead", mock.Anything, mock.Anything)
+				hcClient.AssertNotCalled(t, "UpdateUserBookStatus", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// Actions of a PlannedAction
+const (
+	PlannedActionCreateUserBook = "create_user_book"
+	PlannedActionUpdateProgress = "update_progress"
+	PlannedActionMarkFinished   = "mark_finished"
+	PlannedActionMarkOwned      = "mark_owned"
+	// PlannedActionMarkDidNotFinish marks a book carrying Sync.AbandonedTag as Did Not Finish
+	PlannedActionMarkDidNotFinish = "mark_did_not_finish"
+)
+
+// defaultDryRunReportFile is the name of the dry-run report in Paths.MismatchOutputDir when
+// Paths.DryRunReport isn't set
+const defaultDryRunReportFile = "dry_run_report.json"
+
+// PlannedAction is a change to Hardcover that a dry run would have made
+type PlannedAction struct {
+	// Action is one of the PlannedAction* constants
+	Action string `json:"action"`
+	// ItemID and Title identify the Audiobookshelf item
+	ItemID string `json:"item_id,omitempty"`
+	Title  string `json:"title,omitempty"`
+	// HardcoverBookID is set for books added without an edition
+	HardcoverBookID string `json:"hardcover_book_id,omitempty"`
+	EditionID       string `json:"edition_id,omitempty"`
+	// UserBookID is 0 for user books the dry run would have created
+	UserBookID int64  `json:"user_book_id,omitempty"`
+	Status     string `json:"status,omitempty"`
+	// CurrentProgressSeconds is the progress in Hardcover, if known; TargetProgressSeconds is the
+	// Audiobookshelf progress it would be set to
+	CurrentProgressSeconds *int `json:"current_progress_seconds,omitempty"`
+	TargetProgressSeconds  int  `json:"target_progress_seconds,omitempty"`
+	DurationSeconds        int  `json:"duration_seconds,omitempty"`
+}
+
+// dryRunReport is the file written at the end of a dry run
+type dryRunReport struct {
+	GeneratedAt time.Time       `json:"generated_at"`
+	Actions     []PlannedAction `json:"actions"`
+}
+
+// dryRunBookKey is the context key of the book being processed
+type dryRunBookKey struct{}
+
+// withDryRunBook returns a context carrying the book being processed, so the actions planned while
+// processing it are attributed to it
+func withDryRunBook(ctx context.Context, book models.AudiobookshelfBook) context.Context {
+	return context.WithValue(ctx, dryRunBookKey{}, book)
+}
+
+// planAction adds an action a dry run would have taken to the run's report. The Audiobookshelf item
+// is taken from the context when the action doesn't name it. A book can be looked at more than once
+// while it's processed, so an action planned again for the same item and edition replaces the
+// earlier one.
+func (s *Service) planAction(ctx context.Context, action PlannedAction) {
+	if book, ok := ctx.Value(dryRunBookKey{}).(models.AudiobookshelfBook); ok {
+		if action.ItemID == "" {
+			action.ItemID = book.ID
+		}
+		if action.Title == "" {
+			action.Title = book.Media.Metadata.Title
+		}
+		if action.DurationSeconds == 0 {
+			action.DurationSeconds = int(book.Media.Duration)
+		}
+	}
+
+	s.plannedActionsMutex.Lock()
+	defer s.plannedActionsMutex.Unlock()
+	for i, planned := range s.plannedActions {
+		if planned.Action == action.Action && planned.ItemID == action.ItemID &&
+			planned.EditionID == action.EditionID && planned.HardcoverBookID == action.HardcoverBookID {
+			s.plannedActions[i] = action
+			return
+		}
+	}
+	s.plannedActions = append(s.plannedActions, action)
+}
+
+// plannedUserBookID returns the user book ID of a planned action, 0 for the placeholder ID of user
+// books a dry run would have created
+func plannedUserBookID(userBookID int64) int64 {
+	if userBookID < 0 {
+		return 0
+	}
+	return userBookID
+}
+
+// editionIDFromStateKey returns the edition ID of an "itemID:editionID" state key, or ""
+func editionIDFromStateKey(stateKey string) string {
+	_, editionID, _ := strings.Cut(stateKey, ":")
+	return editionID
+}
+
+// unfinishedReadProgress returns the progress in seconds of the furthest unfinished read, or nil
+// when there's none
+func unfinishedReadProgress(reads []hardcover.UserBookRead) *int {
+	var progress *int
+	for _, read := range reads {
+		if (read.FinishedAt != nil && *read.FinishedAt != "") || read.ProgressSeconds == nil {
+			continue
+		}
+		if progress == nil || *read.ProgressSeconds > *progress {
+			seconds := *read.ProgressSeconds
+			progress = &seconds
+		}
+	}
+	return progress
+}
+
+// PlannedActions returns the actions planned by the last dry run
+func (s *Service) PlannedActions() []PlannedAction {
+	s.plannedActionsMutex.Lock()
+	defer s.plannedActionsMutex.Unlock()
+	return append([]PlannedAction(nil), s.plannedActions...)
+}
+
+// dryRunReportPath returns where the dry-run report is written: Paths.DryRunReport, or a file in
+// Paths.MismatchOutputDir
+func (s *Service) dryRunReportPath() string {
+	if s.config.Paths.DryRunReport != "" {
+		return s.config.Paths.DryRunReport
+	}
+	return filepath.Join(s.config.Paths.MismatchOutputDir, defaultDryRunReportFile)
+}
+
+// writeDryRunReport writes the actions planned during a dry run, or for libraries in dry-run mode,
+// to the dry-run report, replacing the report of the previous dry run. Failures are logged, as the
+// report doesn't affect the sync.
+func (s *Service) writeDryRunReport() {
+	if !s.anyDryRun() {
+		return
+	}
+
+	report := dryRunReport{GeneratedAt: time.Now().UTC(), Actions: s.PlannedActions()}
+	if report.Actions == nil {
+		report.Actions = []PlannedAction{}
+	}
+
+	path := s.dryRunReportPath()
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(path), 0755)
+	}
+	if err == nil {
+		err = os.WriteFile(path, data, 0644)
+	}
+	if err != nil {
+		s.log.Warn("Failed to write dry-run report", map[string]interface{}{
+			"path":  path,
+			"error": err.Error(),
+		})
+		return
+	}
+
+	s.log.Info("Wrote dry-run report", map[string]interface{}{
+		"path":    path,
+		"actions": len(report.Actions),
+	})
+}
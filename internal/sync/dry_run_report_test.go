@@ -0,0 +1,102 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/audiobookshelf"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+func TestSync_DryRunReport(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.summary = &SyncSummary{}
+	svc.statePath = filepath.Join(t.TempDir(), "state.json")
+	svc.persistentCache = NewPersistentASINCache(t.TempDir())
+	svc.config.Paths.MismatchOutputDir = t.TempDir()
+	svc.config.Sync.DryRun = true
+
+	// A book listened to halfway that's new to Hardcover
+	reading := models.AudiobookshelfBook{ID: "abs-reading", LibraryID: "lib1", MediaType: "book"}
+	reading.Media.Metadata.Title = "Reading Audiobook"
+	reading.Media.Metadata.ASIN = "B000000001"
+	reading.Media.Duration = 36000
+	reading.Progress.CurrentTime = 18000
+	// A finished book already on the user's shelf
+	finished := models.AudiobookshelfBook{ID: "abs-finished", LibraryID: "lib1", MediaType: "book"}
+	finished.Media.Metadata.Title = "Finished Audiobook"
+	finished.Media.Metadata.ASIN = "B000000002"
+	finished.Media.Duration = 7200
+	finished.Progress.CurrentTime = 7200
+	finished.Progress.IsFinished = true
+
+	mockClient.On("SearchBookByASIN", mock.Anything, "B000000001").
+		Return(&models.HardcoverBook{ID: "10", EditionID: "100", EditionASIN: "B000000001"}, nil)
+	mockClient.On("SearchBookByASIN", mock.Anything, "B000000002").
+		Return(&models.HardcoverBook{ID: "20", EditionID: "200", EditionASIN: "B000000002"}, nil)
+	mockClient.On("SearchBooks", mock.Anything, mock.Anything, mock.Anything).Return([]models.HardcoverBook{}, nil).Maybe()
+	mockClient.On("CheckBookOwnership", mock.Anything, mock.Anything).Return(true, nil).Maybe()
+	mockClient.On("GetUserBookID", mock.Anything, 100).Return(0, nil)
+	mockClient.On("GetUserBookID", mock.Anything, 200).Return(77, nil)
+	mockClient.On("GetUserBook", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	mockClient.On("GetUserBookReads", mock.Anything, mock.Anything).Return([]hardcover.UserBookRead{}, nil).Maybe()
+
+	mockABS := new(MockAudiobookshelfClient)
+	mockABS.On("GetUserProgress", mock.Anything).Return(&models.AudiobookshelfUserProgress{}, nil)
+	mockABS.On("GetLibraries", mock.Anything).Return([]audiobookshelf.AudiobookshelfLibrary{{ID: "lib1", Name: "Audiobooks"}}, nil)
+	mockABS.On("GetLibraryItems", mock.Anything, "lib1").Return([]models.AudiobookshelfBook{reading, finished}, nil)
+	svc.audiobookshelf = mockABS
+
+	require.NoError(t, svc.Sync(context.Background()))
+
+	// Nothing was written to Hardcover
+	mockClient.AssertNotCalled(t, "MarkEditionAsOwned", mock.Anything, mock.Anything)
+	mockClient.AssertNotCalled(t, "UpdateUserBookStatus", mock.Anything, mock.Anything)
+
+	data, err := os.ReadFile(filepath.Join(svc.config.Paths.MismatchOutputDir, defaultDryRunReportFile))
+	require.NoError(t, err)
+	var report dryRunReport
+	require.NoError(t, json.Unmarshal(data, &report))
+	assert.False(t, report.GeneratedAt.IsZero())
+
+	assert.Equal(t, []PlannedAction{
+		{Action: PlannedActionCreateUserBook, ItemID: "abs-reading", Title: "Reading Audiobook", EditionID: "100", Status: "IN_PROGRESS", DurationSeconds: 36000},
+		{Action: PlannedActionUpdateProgress, ItemID: "abs-reading", Title: "Reading Audiobook", EditionID: "100", Status: "IN_PROGRESS", TargetProgressSeconds: 18000, DurationSeconds: 36000},
+		{Action: PlannedActionMarkFinished, ItemID: "abs-finished", Title: "Finished Audiobook", EditionID: "200", UserBookID: 77, Status: "FINISHED", TargetProgressSeconds: 7200, DurationSeconds: 7200},
+	}, report.Actions)
+}
+
+func TestMarkOwned_DryRunPlansAction(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.config.Sync.DryRun = true
+	mockClient.On("CheckBookOwnership", mock.Anything, 10).Return(false, nil).Once()
+
+	book := models.AudiobookshelfBook{ID: "abs-unowned"}
+	book.Media.Metadata.Title = "Unowned Audiobook"
+	svc.markOwned(withDryRunBook(context.Background(), book), &models.HardcoverBook{ID: "10", EditionID: "100"}, svc.log)
+
+	mockClient.AssertNotCalled(t, "MarkEditionAsOwned", mock.Anything, mock.Anything)
+	assert.Equal(t, []PlannedAction{
+		{Action: PlannedActionMarkOwned, ItemID: "abs-unowned", Title: "Unowned Audiobook", EditionID: "100"},
+	}, svc.PlannedActions())
+}
+
+func TestUnfinishedReadProgress(t *testing.T) {
+	seconds := func(v int) *int { return &v }
+	finishedAt := "2024-01-02"
+
+	assert.Nil(t, unfinishedReadProgress(nil))
+	assert.Equal(t, seconds(900), unfinishedReadProgress([]hardcover.UserBookRead{
+		{ID: 1, ProgressSeconds: seconds(300)},
+		{ID: 2, ProgressSeconds: seconds(900)},
+		{ID: 3, ProgressSeconds: seconds(5000), FinishedAt: &finishedAt},
+	}))
+}
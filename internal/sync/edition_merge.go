@@ -0,0 +1,30 @@
+package sync
+
+import (
+	"errors"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// isEditionGone reports whether err means the edition no longer exists in Hardcover, which
+// happens when its book is merged into another one between runs
+func isEditionGone(err error) bool {
+	return errors.Is(err, hardcover.ErrEditionNotFound)
+}
+
+// invalidateCachedEdition removes the cached ASIN lookup that resolved to an edition that no
+// longer exists, from both the in-memory and the persistent cache, so the ASIN is looked up again
+func (s *Service) invalidateCachedEdition(asin string, cached *models.HardcoverBook, err error) {
+	s.log.Warn("Cached edition no longer exists in Hardcover, its book was probably merged; looking it up again", map[string]interface{}{
+		"asin":       asin,
+		"book_id":    cached.ID,
+		"edition_id": cached.EditionID,
+		"error":      err.Error(),
+	})
+
+	s.asinCacheMutex.Lock()
+	defer s.asinCacheMutex.Unlock()
+	delete(s.asinCache, asin)
+	s.persistentCache.Delete(asin)
+}
@@ -0,0 +1,75 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindBookInHardcover_MergedCachedEdition(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.persistentCache = NewPersistentASINCache(t.TempDir())
+
+	book := models.AudiobookshelfBook{ID: "abs-1", LibraryID: "lib1", MediaType: "book"}
+	book.Media.Metadata.Title = "Merged Audiobook"
+	book.Media.Metadata.ASIN = "B0MERGED01"
+	book.Media.Duration = 1000
+	book.Progress.CurrentTime = 300
+
+	// A previous run cached edition 100, which has since been merged away
+	svc.setASINInCache("B0MERGED01", &models.HardcoverBook{ID: "10", EditionID: "100", EditionASIN: "B0MERGED01"})
+
+	mockClient.On("GetUserBookID", mock.Anything, 100).Return(0, nil)
+	mockClient.On("CreateUserBook", mock.Anything, "100", mock.Anything).
+		Return("", fmt.Errorf("failed to get edition details: %w", hardcover.ErrEditionNotFound)).Once()
+
+	// The fresh lookup resolves the ASIN to the edition of the book it was merged into
+	mockClient.On("SearchBookByASIN", mock.Anything, "B0MERGED01").
+		Return(&models.HardcoverBook{ID: "20", EditionID: "200", EditionASIN: "B0MERGED01"}, nil).Once()
+	mockClient.On("GetUserBookID", mock.Anything, 200).Return(555, nil)
+
+	hcBook, _, err := svc.findBookInHardcover(context.Background(), book)
+	require.NoError(t, err)
+	require.NotNil(t, hcBook)
+	assert.Equal(t, "20", hcBook.ID)
+	assert.Equal(t, "200", hcBook.EditionID)
+	assert.Equal(t, "555", hcBook.UserBookID)
+	mockClient.AssertExpectations(t)
+
+	// The cache now holds the re-resolved edition
+	cached, ok := svc.getASINFromCache("B0MERGED01")
+	require.True(t, ok)
+	assert.Equal(t, "200", cached.EditionID)
+	persisted, ok := svc.persistentCache.Get("B0MERGED01")
+	require.True(t, ok)
+	assert.Equal(t, "200", persisted.EditionID)
+}
+
+func TestFindBookInHardcover_CachedEditionOtherError(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.persistentCache = NewPersistentASINCache(t.TempDir())
+
+	book := models.AudiobookshelfBook{ID: "abs-1", LibraryID: "lib1", MediaType: "book"}
+	book.Media.Metadata.Title = "Cached Audiobook"
+	book.Media.Metadata.ASIN = "B0CACHED01"
+
+	svc.setASINInCache("B0CACHED01", &models.HardcoverBook{ID: "10", EditionID: "100", EditionASIN: "B0CACHED01"})
+
+	// Other failures keep the cached edition and don't trigger a new lookup
+	mockClient.On("GetUserBookID", mock.Anything, 100).Return(0, fmt.Errorf("network error"))
+
+	hcBook, _, err := svc.findBookInHardcover(context.Background(), book)
+	require.NoError(t, err)
+	assert.Equal(t, "100", hcBook.EditionID)
+	mockClient.AssertNotCalled(t, "SearchBookByASIN", mock.Anything, mock.Anything)
+
+	cached, ok := svc.getASINFromCache("B0CACHED01")
+	require.True(t, ok)
+	assert.Equal(t, "100", cached.EditionID)
+}
@@ -0,0 +1,147 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// loadEditionOverrides loads Paths.OverridesFile, which maps Audiobookshelf item IDs to Hardcover
+// edition IDs, once per run. Every edition is looked up to verify it; entries whose edition ID
+// isn't numeric or can't be found are reported and left out, so their items are looked up as usual.
+func (s *Service) loadEditionOverrides(ctx context.Context) {
+	s.editionOverrides = nil
+	s.editionOverrideItems = nil
+	path := s.config.Paths.OverridesFile
+	if path == "" {
+		return
+	}
+
+	entries, err := readEditionOverrides(path)
+	if err != nil {
+		s.log.Warn("Failed to read the overrides file, not using any overrides this run", map[string]interface{}{
+			"path":  path,
+			"error": err.Error(),
+		})
+		return
+	}
+
+	overrides := make(map[string]*models.Edition, len(entries))
+	stale := 0
+	for itemID, editionID := range entries {
+		log := s.log.With(map[string]interface{}{
+			"item_id":    itemID,
+			"edition_id": editionID,
+		})
+
+		if id, err := strconv.Atoi(editionID); err != nil || id <= 0 {
+			log.Warn("Override edition ID isn't numeric, ignoring it", nil)
+			stale++
+			continue
+		}
+
+		edition, err := s.hardcover.GetEdition(ctx, editionID)
+		if err != nil {
+			if isEditionGone(err) {
+				log.Warn("Override edition doesn't exist in Hardcover, ignoring it", nil)
+			} else {
+				log.Warn("Failed to verify override edition, ignoring it this run", map[string]interface{}{
+					"error": err.Error(),
+				})
+			}
+			stale++
+			continue
+		}
+		overrides[itemID] = edition
+	}
+
+	s.editionOverrides = overrides
+	s.editionOverrideItems = make(map[string]struct{})
+	s.log.Info("Loaded edition overrides", map[string]interface{}{
+		"path":      path,
+		"overrides": len(overrides),
+		"ignored":   stale,
+	})
+}
+
+// readEditionOverrides parses the overrides file. JSON is valid YAML, so both are read the same way.
+func readEditionOverrides(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries map[string]string
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse overrides file: %w", err)
+	}
+
+	trimmed := make(map[string]string, len(entries))
+	for itemID, editionID := range entries {
+		trimmed[strings.TrimSpace(itemID)] = strings.TrimSpace(editionID)
+	}
+	return trimmed, nil
+}
+
+// noteEditionOverrideItems remembers which of the fetched items have an edition override, for the
+// count logged by logEditionOverrideMatches
+func (s *Service) noteEditionOverrideItems(items []models.AudiobookshelfBook) {
+	if len(s.editionOverrides) == 0 {
+		return
+	}
+	for _, item := range items {
+		if _, ok := s.editionOverrides[item.ID]; ok {
+			s.editionOverrideItems[item.ID] = struct{}{}
+		}
+	}
+}
+
+// logEditionOverrideMatches logs how many of the loaded edition overrides matched an item fetched
+// this run. Overrides for items that no longer exist are candidates for removal; in incremental
+// runs unchanged items aren't fetched, so fewer overrides match.
+func (s *Service) logEditionOverrideMatches() {
+	if len(s.editionOverrides) == 0 {
+		return
+	}
+	s.log.Info("Edition overrides matched fetched items", map[string]interface{}{
+		"overrides": len(s.editionOverrides),
+		"matched":   len(s.editionOverrideItems),
+	})
+}
+
+// editionOverride returns the edition the book is mapped to in Paths.OverridesFile, or nil
+func (s *Service) editionOverride(book models.AudiobookshelfBook) *models.Edition {
+	return s.editionOverrides[book.ID]
+}
+
+// findBookByEditionOverride syncs the book to the edition it's mapped to in Paths.OverridesFile,
+// skipping the lookup entirely. The bool reports whether the book has an override.
+func (s *Service) findBookByEditionOverride(ctx context.Context, book models.AudiobookshelfBook, log *logger.Logger) (*models.HardcoverBook, bool, error) {
+	edition := s.editionOverride(book)
+	if edition == nil {
+		return nil, false, nil
+	}
+
+	hcBook := &models.HardcoverBook{
+		ID:            edition.BookID,
+		Title:         edition.Title,
+		EditionID:     edition.ID,
+		EditionASIN:   edition.ASIN,
+		EditionISBN13: edition.ISBN13,
+		EditionISBN10: edition.ISBN10,
+	}
+	log.Info("Using edition from the overrides file", map[string]interface{}{
+		"hardcover_book_id": hcBook.ID,
+		"edition_id":        hcBook.EditionID,
+	})
+
+	found, err := s.processFoundBook(ctx, hcBook, book)
+	return found, true, err
+}
@@ -0,0 +1,87 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/sync/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// writeOverridesFile writes the overrides file and configures the service to use it
+func writeOverridesFile(t *testing.T, svc *Service, name, content string) {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	svc.config.Paths.OverridesFile = path
+}
+
+func TestLoadEditionOverrides(t *testing.T) {
+	svc, mockClient := createTestService()
+	writeOverridesFile(t, svc, "overrides.yaml", "li_valid: 420\nli_stale: \"999\"\nli_invalid: not-an-id\n")
+
+	mockClient.On("GetEdition", mock.Anything, "420").
+		Return(&models.Edition{ID: "420", BookID: "42", ASIN: "B000000420"}, nil).Once()
+	mockClient.On("GetEdition", mock.Anything, "999").
+		Return(nil, fmt.Errorf("%w: 999", hardcover.ErrEditionNotFound)).Once()
+
+	svc.loadEditionOverrides(context.Background())
+
+	mockClient.AssertExpectations(t)
+	require.Len(t, svc.editionOverrides, 1)
+	assert.Equal(t, "42", svc.editionOverrides["li_valid"].BookID)
+
+	// Only fetched items with an override are counted as matched
+	svc.noteEditionOverrideItems([]models.AudiobookshelfBook{{ID: "li_valid"}, {ID: "li_other"}})
+	assert.Len(t, svc.editionOverrideItems, 1)
+}
+
+func TestLoadEditionOverrides_JSON(t *testing.T) {
+	svc, mockClient := createTestService()
+	writeOverridesFile(t, svc, "overrides.json", `{"li_valid": 420}`)
+
+	mockClient.On("GetEdition", mock.Anything, "420").Return(&models.Edition{ID: "420", BookID: "42"}, nil).Once()
+
+	svc.loadEditionOverrides(context.Background())
+	assert.Len(t, svc.editionOverrides, 1)
+}
+
+func TestLoadEditionOverrides_MissingFile(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.config.Paths.OverridesFile = filepath.Join(t.TempDir(), "missing.yaml")
+
+	svc.loadEditionOverrides(context.Background())
+	assert.Nil(t, svc.editionOverrides)
+	mockClient.AssertNotCalled(t, "GetEdition", mock.Anything, mock.Anything)
+}
+
+func TestFindBookInHardcover_EditionOverride(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.config.Sync.SyncOwned = false
+	svc.editionOverrides = map[string]*models.Edition{
+		"abs-overridden": {ID: "420", BookID: "42", Title: "Overridden Audiobook"},
+	}
+
+	book := newTestBook("abs-overridden", "Overridden Audiobook")
+	book.Media.Metadata.ASIN = "B0WRONG001"
+
+	mockClient.On("GetUserBookID", mock.Anything, 420).Return(77, nil).Once()
+
+	hcBook, match, err := svc.findBookInHardcover(context.Background(), book)
+	require.NoError(t, err)
+	require.NotNil(t, hcBook)
+
+	assert.Equal(t, "42", hcBook.ID)
+	assert.Equal(t, "420", hcBook.EditionID)
+	assert.Equal(t, "77", hcBook.UserBookID)
+	mockClient.AssertExpectations(t)
+	// The override skips the lookup entirely
+	mockClient.AssertNotCalled(t, "SearchBookByASIN", mock.Anything, mock.Anything)
+	assert.Equal(t, state.MatchSourceMapping, match.Source)
+}
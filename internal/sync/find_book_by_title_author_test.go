@@ -252,6 +252,9 @@ func TestFindBookInHardcoverByTitleAuthor(t *testing.T) {
 				first := tt.searchResults[0]
 				mockClient.On("GetBookByID", mock.Anything, first.ID).
 					Return(&models.HardcoverBook{ID: first.ID, Title: first.Title}, nil)
+				// Author verification: the result is by the book's author
+				mockClient.On("GetBookAuthors", mock.Anything, first.ID).
+					Return([]models.Author{{Name: tt.book.Media.Metadata.AuthorName}}, nil).Maybe()
 			}
 
 			// We no longer call GetEdition with book ID in the new implementation
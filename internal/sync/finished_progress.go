@@ -0,0 +1,24 @@
+package sync
+
+import (
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// finishedProgressSeconds returns the progress_seconds written for a finished book as configured by
+// Sync.FinishedProgressHandling: the book's duration for "full", or the Audiobookshelf listening
+// position for "actual". Each falls back to the other when it isn't known; ok is false when neither is.
+func (s *Service) finishedProgressSeconds(book models.AudiobookshelfBook) (seconds int, ok bool) {
+	preferred, fallback := int(book.Media.Duration), int(book.Progress.CurrentTime)
+	if s.config.Sync.FinishedProgressHandling == config.FinishedProgressActual {
+		preferred, fallback = fallback, preferred
+	}
+
+	if preferred > 0 {
+		return preferred, true
+	}
+	if fallback > 0 {
+		return fallback, true
+	}
+	return 0, false
+}
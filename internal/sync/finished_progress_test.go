@@ -0,0 +1,75 @@
+package sync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleFinishedBook_FinishedProgressHandling(t *testing.T) {
+	// A book marked finished by hand at 40% of its 10h duration
+	book := models.AudiobookshelfBook{ID: "abs-manual", LibraryID: "lib1", MediaType: "book"}
+	book.Media.Metadata.Title = "Manually Finished Book"
+	book.Media.Duration = 36000
+	book.Progress.CurrentTime = 14400
+	book.Progress.IsFinished = true
+	book.Progress.FinishedAt = time.Now().Add(-24 * time.Hour).UnixMilli()
+
+	tests := []struct {
+		name     string
+		handling string
+		expected int
+	}{
+		{name: "full", handling: config.FinishedProgressFull, expected: 36000},
+		{name: "actual", handling: config.FinishedProgressActual, expected: 14400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" on new read", func(t *testing.T) {
+			svc, mockClient := createTestService()
+			svc.config.Sync.FinishedProgressHandling = tt.handling
+
+			mockClient.On("GetUserBook", mock.Anything, "100").Return(&models.HardcoverBook{UserBookID: "100", BookStatusID: 3}, nil)
+			mockClient.On("GetUserBookReads", mock.Anything, hardcover.GetUserBookReadsInput{UserBookID: 100}).
+				Return([]hardcover.UserBookRead{}, nil)
+			mockClient.On("CheckExistingUserBookRead", mock.Anything, mock.Anything).
+				Return((*hardcover.CheckExistingUserBookReadResult)(nil), nil)
+
+			var written int
+			mockClient.On("InsertUserBookRead", mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) {
+					written = *args.Get(1).(hardcover.InsertUserBookReadInput).DatesRead.ProgressSeconds
+				}).
+				Return(1, nil)
+
+			require.NoError(t, svc.HandleFinishedBook(context.Background(), book, "200", 100))
+			assert.Equal(t, tt.expected, written)
+		})
+
+		t.Run(tt.name+" on existing read", func(t *testing.T) {
+			svc, mockClient := createTestService()
+			svc.config.Sync.FinishedProgressHandling = tt.handling
+
+			mockClient.On("GetUserBook", mock.Anything, "100").Return(&models.HardcoverBook{UserBookID: "100", BookStatusID: 3}, nil)
+			mockClient.On("GetUserBookReads", mock.Anything, hardcover.GetUserBookReadsInput{UserBookID: 100}).
+				Return([]hardcover.UserBookRead{{ID: 7, UserBookID: 100, ProgressSeconds: intPointer(7200)}}, nil)
+
+			var written interface{}
+			mockClient.On("UpdateUserBookRead", mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) {
+					written = args.Get(1).(hardcover.UpdateUserBookReadInput).Object["progress_seconds"]
+				}).
+				Return(true, nil)
+
+			require.NoError(t, svc.HandleFinishedBook(context.Background(), book, "200", 100))
+			assert.Equal(t, tt.expected, written)
+		})
+	}
+}
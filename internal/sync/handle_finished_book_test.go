@@ -198,6 +198,7 @@ func TestHandleFinishedBook(t *testing.T) {
 
 			// Set up insert expectation if needed
 			if tc.expectInsertCall {
+				mockClient.On("CheckExistingUserBookRead", mock.Anything, mock.Anything).Return((*hardcover.CheckExistingUserBookReadResult)(nil), nil)
 				mockClient.On("InsertUserBookRead", mock.Anything, mock.MatchedBy(func(input hardcover.InsertUserBookReadInput) bool {
 					// Verify the user book ID matches - convert int to int64 for comparison
 					return input.UserBookID == int64(tc.userBookID)
@@ -240,6 +241,48 @@ func TestHandleFinishedBook(t *testing.T) {
 	}
 }
 
+// TestHandleFinishedBook_IdempotentReadCreation verifies that running HandleFinishedBook twice
+// for the same finished date only creates a single read, even if the read list is stale
+func TestHandleFinishedBook_IdempotentReadCreation(t *testing.T) {
+	logger.Setup(logger.Config{Level: "debug", Format: "json"})
+
+	svc, mockClient := createTestService()
+	svc.config = createTestConfigForTests(true)
+	ctx := context.Background()
+
+	userBookID := int64(321)
+	book := convertTestBookToModel(createTestFinishedBook("abs-book-idem", "Idempotent Book", "Some Author", "B000000001", "9780000000001"))
+	finishedAt := time.Unix(book.Progress.FinishedAt/1000, 0).Format("2006-01-02")
+
+	// Book is already FINISHED in Hardcover, so no status update is expected
+	mockClient.On("GetUserBook", mock.Anything, "321").Return(&models.HardcoverBook{
+		ID:           "book-321",
+		UserBookID:   "321",
+		BookStatusID: 3,
+	}, nil)
+
+	// The read list has not caught up with the first insert yet
+	mockClient.On("GetUserBookReads", mock.Anything, hardcover.GetUserBookReadsInput{
+		UserBookID: userBookID,
+	}).Return([]hardcover.UserBookRead{}, nil)
+
+	checkInput := hardcover.CheckExistingUserBookReadInput{UserBookID: int(userBookID), Date: finishedAt}
+	mockClient.On("CheckExistingUserBookRead", mock.Anything, checkInput).Return((*hardcover.CheckExistingUserBookReadResult)(nil), nil).Once()
+	mockClient.On("CheckExistingUserBookRead", mock.Anything, checkInput).Return(&hardcover.CheckExistingUserBookReadResult{ID: 555}, nil).Once()
+
+	mockClient.On("InsertUserBookRead", mock.Anything, mock.MatchedBy(func(input hardcover.InsertUserBookReadInput) bool {
+		return input.UserBookID == userBookID &&
+			input.DatesRead.FinishedAt != nil &&
+			*input.DatesRead.FinishedAt == finishedAt
+	})).Return(555, nil).Once()
+
+	assert.NoError(t, svc.HandleFinishedBook(ctx, book, "654", userBookID))
+	assert.NoError(t, svc.HandleFinishedBook(ctx, book, "654", userBookID))
+
+	mockClient.AssertExpectations(t)
+	mockClient.AssertNumberOfCalls(t, "InsertUserBookRead", 1)
+}
+
 // Helper functions for test data
 func stringPointer(s string) *string {
 	return &s
@@ -8,9 +8,11 @@ import (
 	"time"
 
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/mismatch"
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // TestHandleInProgressBook_NoProgress tests the case where there is no progress to update
@@ -286,6 +288,9 @@ func TestHandleInProgressBook_CreateNewRead(t *testing.T) {
 		UserBookID: userBookID,
 	}).Return([]hardcover.UserBookRead{}, nil).Twice()
 
+	// No existing read for the date this read is keyed by
+	mockClient.On("CheckExistingUserBookRead", mock.Anything, mock.Anything).Return((*hardcover.CheckExistingUserBookReadResult)(nil), nil).Once()
+
 	// Mock the InsertUserBookRead call
 	editionID := int64(456)
 	progressSeconds := 300
@@ -313,6 +318,61 @@ func TestHandleInProgressBook_CreateNewRead(t *testing.T) {
 	mockClient.AssertExpectations(t)
 }
 
+// TestHandleInProgressBook_CreateNewRead_Reread tests that a finished read started on the same day
+// doesn't keep a reread from being created
+func TestHandleInProgressBook_CreateNewRead_Reread(t *testing.T) {
+	svc, mockClient := createTestService()
+
+	// The reread was started on the day the finished read was started
+	startedAt := time.Date(2024, 3, 1, 9, 0, 0, 0, time.Local)
+	testAudiobook := createTestBook("test-book-1", "Test Book", "Test Author", "B08N5KWB9H", "9781234567890")
+	testAudiobook.Progress.CurrentTime = 300
+	testAudiobook.Media.Duration = 1000
+	audiobook := toAudiobookshelfBook(testAudiobook)
+	audiobook.Progress.StartedAt = startedAt.UnixMilli()
+
+	userBookID := int64(123)
+	mockClient.On("GetUserBook", mock.Anything, "123").Return(&models.HardcoverBook{
+		ID:        "book-123",
+		Title:     "Test Book",
+		EditionID: "456",
+	}, nil).Once()
+
+	// Hardcover only has the finished read
+	mockClient.On("GetUserBookReads", mock.Anything, hardcover.GetUserBookReadsInput{
+		UserBookID: userBookID,
+		Status:     "unfinished",
+	}).Return([]hardcover.UserBookRead{}, nil).Once()
+	finishedStarted := "2024-03-01"
+	finishedAt := "2024-03-10"
+	mockClient.On("GetUserBookReads", mock.Anything, hardcover.GetUserBookReadsInput{
+		UserBookID: userBookID,
+	}).Return([]hardcover.UserBookRead{{ID: 700, StartedAt: &finishedStarted, FinishedAt: &finishedAt}}, nil).Twice()
+
+	// The finished read matches the date, but only unfinished reads are looked for
+	mockClient.On("CheckExistingUserBookRead", mock.Anything, hardcover.CheckExistingUserBookReadInput{
+		UserBookID: int(userBookID),
+		Date:       "2024-03-01",
+		Unfinished: true,
+	}).Return((*hardcover.CheckExistingUserBookReadResult)(nil), nil).Once()
+
+	mockClient.On("InsertUserBookRead", mock.Anything, mock.MatchedBy(func(input hardcover.InsertUserBookReadInput) bool {
+		return input.UserBookID == userBookID &&
+			input.DatesRead.StartedAt != nil && *input.DatesRead.StartedAt == "2024-03-01" &&
+			input.DatesRead.FinishedAt == nil
+	})).Return(789, nil).Once()
+	mockClient.On("UpdateUserBookStatus", mock.Anything, hardcover.UpdateUserBookStatusInput{
+		ID:       userBookID,
+		StatusID: 2, // 2 = Currently Reading
+	}).Return(nil).Once()
+
+	stateKey := fmt.Sprintf("%s:456", audiobook.ID)
+	err := svc.handleInProgressBook(context.Background(), userBookID, *audiobook, stateKey)
+
+	assert.NoError(t, err)
+	mockClient.AssertExpectations(t)
+}
+
 func TestHandleInProgressBook_CreateNewRead_UsesStateKeyEdition(t *testing.T) {
 	// Create test service and mock client
 	svc, mockClient := createTestService()
@@ -342,6 +402,9 @@ func TestHandleInProgressBook_CreateNewRead_UsesStateKeyEdition(t *testing.T) {
 		UserBookID: userBookID,
 	}).Return([]hardcover.UserBookRead{}, nil).Twice()
 
+	// No existing read for the date this read is keyed by
+	mockClient.On("CheckExistingUserBookRead", mock.Anything, mock.Anything).Return((*hardcover.CheckExistingUserBookReadResult)(nil), nil).Once()
+
 	// Expect InsertUserBookRead to use the edition ID from stateKey (456), not hcBook.EditionID (999)
 	editionID := int64(456)
 	progressSeconds := 300
@@ -458,6 +521,9 @@ func TestHandleInProgressBook_GetUserBookReadsError(t *testing.T) {
 		UserBookID: userBookID,
 	}).Return([]hardcover.UserBookRead{}, nil).Twice()
 
+	// No existing read for the date this read is keyed by
+	mockClient.On("CheckExistingUserBookRead", mock.Anything, mock.Anything).Return((*hardcover.CheckExistingUserBookReadResult)(nil), nil).Once()
+
 	// Mock the InsertUserBookRead call
 	progressSeconds := 100
 	mockClient.On("InsertUserBookRead", mock.Anything, mock.MatchedBy(func(input hardcover.InsertUserBookReadInput) bool {
@@ -542,3 +608,56 @@ func TestHandleInProgressBook_FinishedBook(t *testing.T) {
 	assert.NoError(t, err, "Should not return an error when updating finished book")
 	mockClient.AssertExpectations(t)
 }
+
+// TestHandleInProgressBook_SuspiciousProgressJump tests that progress jumps beyond the configured
+// cap are refused and recorded as a mismatch
+func TestHandleInProgressBook_SuspiciousProgressJump(t *testing.T) {
+	mismatch.Clear()
+	defer mismatch.Clear()
+
+	// Create test service with a 2 hour cap
+	svc, mockClient := createTestService()
+	svc.config.Sync.MaxProgressJumpSeconds = 7200
+
+	// Create a test book 5 hours ahead of Hardcover
+	testAudiobook := createTestBook("test-book-1", "Test Book", "Test Author", "", "9781234567890")
+	testAudiobook.Progress.CurrentTime = 6 * 3600
+	testAudiobook.Media.Duration = 10 * 3600
+	audiobook := toAudiobookshelfBook(testAudiobook)
+
+	userBookID := int64(123)
+	mockClient.On("GetUserBook", mock.Anything, "123").Return(&models.HardcoverBook{
+		ID:        "book-123",
+		Title:     "Test Book",
+		EditionID: "456",
+	}, nil).Once()
+
+	progressSeconds := 3600
+	editionID := int64(456)
+	mockClient.On("GetUserBookReads", mock.Anything, hardcover.GetUserBookReadsInput{
+		UserBookID: userBookID,
+		Status:     "unfinished",
+	}).Return([]hardcover.UserBookRead{
+		{
+			ID:              789,
+			ProgressSeconds: &progressSeconds,
+			EditionID:       &editionID,
+		},
+	}, nil).Once()
+
+	stateKey := fmt.Sprintf("%s:test-edition", audiobook.ID)
+	err := svc.handleInProgressBook(context.Background(), userBookID, *audiobook, stateKey)
+
+	// The update is refused without error and recorded as a mismatch
+	assert.NoError(t, err)
+	mockClient.AssertExpectations(t)
+	mockClient.AssertNotCalled(t, "UpdateUserBookRead", mock.Anything, mock.Anything)
+	mockClient.AssertNotCalled(t, "UpdateUserBookStatus", mock.Anything, mock.Anything)
+
+	all := mismatch.GetAll()
+	require.Len(t, all, 1)
+	assert.Equal(t, "test-book-1", all[0].BookID)
+	assert.Equal(t, "book-123", all[0].HardcoverBookID)
+	assert.Contains(t, all[0].Reason, "Suspicious progress jump")
+	assert.Contains(t, all[0].Reason, "5h0m0s")
+}
@@ -0,0 +1,75 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// errSettledInHardcover is returned instead of a user book while looking up a book that
+// processBook skips because it's already finished in Hardcover
+var errSettledInHardcover = errors.New("book is already finished in Hardcover, not looking up its user book")
+
+// loadHardcoverFinished fetches the books already Read in Hardcover once per run when
+// Sync.SkipHardcoverFinished is enabled. If fetching fails, no book is skipped this run.
+func (s *Service) loadHardcoverFinished(ctx context.Context) {
+	s.hardcoverFinished = nil
+	if !s.config.Sync.SkipHardcoverFinished {
+		return
+	}
+
+	finished, err := s.hardcover.GetFinishedUserBooks(ctx)
+	if err != nil {
+		s.log.Warn("Failed to fetch books finished in Hardcover, not skipping any this run", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	s.hardcoverFinished = finished
+	s.log.Info("Fetched books finished in Hardcover", map[string]interface{}{
+		"finished_books": len(finished),
+	})
+}
+
+// settledInHardcover reports whether the book is already Read in Hardcover and Audiobookshelf
+// shows no activity after the day it was last read there, so there's nothing to update. A book
+// that's in progress again after that day is being reread and is synced as usual, as is an
+// in-progress book whose Hardcover read date or Audiobookshelf activity is unknown.
+func (s *Service) settledInHardcover(book models.AudiobookshelfBook, hcBook *models.HardcoverBook) bool {
+	if s.hardcoverFinished == nil || hcBook == nil {
+		return false
+	}
+	lastRead, finished := s.hardcoverFinished[hcBook.ID]
+	if !finished {
+		return false
+	}
+
+	lastActivity := book.Progress.LastUpdate
+	for _, ts := range []int64{book.Progress.StartedAt, book.Progress.FinishedAt} {
+		if ts > lastActivity {
+			lastActivity = ts
+		}
+	}
+	if lastRead.IsZero() || lastActivity == 0 {
+		return book.Progress.IsFinished || book.Progress.CurrentTime <= 0
+	}
+
+	// Hardcover only keeps the date, so anything on that day belongs to the finished read
+	return time.UnixMilli(lastActivity).Before(lastRead.AddDate(0, 0, 1))
+}
+
+// findOrCreateUserBookIDForMatch is findOrCreateUserBookIDForBook for a book being looked up in
+// Hardcover. Books settled in Hardcover get errSettledInHardcover instead, saving the user book
+// lookup for a book processBook is going to skip, and books only looked up get errLookupOnly.
+func (s *Service) findOrCreateUserBookIDForMatch(ctx context.Context, book models.AudiobookshelfBook, hcBook *models.HardcoverBook, editionID, status string) (int64, error) {
+	if lookupOnly(ctx) {
+		return 0, errLookupOnly
+	}
+	if s.settledInHardcover(book, hcBook) {
+		return 0, errSettledInHardcover
+	}
+	return s.findOrCreateUserBookIDForBook(ctx, hcBook, editionID, status)
+}
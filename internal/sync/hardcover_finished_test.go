@@ -0,0 +1,133 @@
+package sync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessBook_SkipsBookFinishedInHardcover(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.summary = &SyncSummary{}
+	svc.persistentCache = NewPersistentASINCache(t.TempDir())
+	svc.config.Sync.SkipHardcoverFinished = true
+
+	lastRead := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	mockClient.On("GetFinishedUserBooks", mock.Anything).Return(map[string]time.Time{"10": lastRead}, nil).Once()
+	svc.loadHardcoverFinished(context.Background())
+
+	book := models.AudiobookshelfBook{ID: "abs-1", LibraryID: "lib1", MediaType: "book"}
+	book.Media.Metadata.Title = "Finished Audiobook"
+	book.Media.Metadata.ASIN = "B000000001"
+	book.Media.Duration = 3600
+	book.Progress.CurrentTime = 3600
+	book.Progress.IsFinished = true
+	book.Progress.FinishedAt = lastRead.Add(20 * time.Hour).UnixMilli()
+
+	mockClient.On("SearchBookByASIN", mock.Anything, "B000000001").
+		Return(&models.HardcoverBook{ID: "10", EditionID: "100", EditionASIN: "B000000001"}, nil)
+
+	// Only the lookup is expected, any user book or read call panics
+	require.NoError(t, svc.processBook(context.Background(), book, nil))
+	mockClient.AssertExpectations(t)
+	mockClient.AssertNotCalled(t, "GetUserBookID", mock.Anything, mock.Anything)
+	mockClient.AssertNotCalled(t, "GetUserBookReads", mock.Anything, mock.Anything)
+}
+
+func TestSettledInHardcover(t *testing.T) {
+	lastRead := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	hcBook := &models.HardcoverBook{ID: "10"}
+
+	tests := []struct {
+		name       string
+		finished   map[string]time.Time
+		book       func(*models.AudiobookshelfBook)
+		wantSettle bool
+	}{
+		{
+			name:     "finished in both without newer activity",
+			finished: map[string]time.Time{"10": lastRead},
+			book: func(b *models.AudiobookshelfBook) {
+				b.Progress.IsFinished = true
+				b.Progress.CurrentTime = 3600
+				b.Progress.FinishedAt = lastRead.Add(-24 * time.Hour).UnixMilli()
+			},
+			wantSettle: true,
+		},
+		{
+			name:     "reread started after the last read",
+			finished: map[string]time.Time{"10": lastRead},
+			book: func(b *models.AudiobookshelfBook) {
+				b.Progress.CurrentTime = 600
+				b.Progress.LastUpdate = lastRead.Add(72 * time.Hour).UnixMilli()
+			},
+		},
+		{
+			name:     "stale progress from before the last read",
+			finished: map[string]time.Time{"10": lastRead},
+			book: func(b *models.AudiobookshelfBook) {
+				b.Progress.CurrentTime = 600
+				b.Progress.LastUpdate = lastRead.Add(-72 * time.Hour).UnixMilli()
+			},
+			wantSettle: true,
+		},
+		{
+			name:     "in progress with unknown read date",
+			finished: map[string]time.Time{"10": {}},
+			book: func(b *models.AudiobookshelfBook) {
+				b.Progress.CurrentTime = 600
+				b.Progress.LastUpdate = lastRead.UnixMilli()
+			},
+		},
+		{
+			name:     "finished with unknown read date",
+			finished: map[string]time.Time{"10": {}},
+			book: func(b *models.AudiobookshelfBook) {
+				b.Progress.IsFinished = true
+				b.Progress.CurrentTime = 3600
+			},
+			wantSettle: true,
+		},
+		{
+			name:     "not finished in Hardcover",
+			finished: map[string]time.Time{"20": lastRead},
+			book: func(b *models.AudiobookshelfBook) {
+				b.Progress.IsFinished = true
+			},
+		},
+		{
+			name: "finished books not loaded",
+			book: func(b *models.AudiobookshelfBook) {
+				b.Progress.IsFinished = true
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := createTestService()
+			svc.hardcoverFinished = tt.finished
+
+			book := models.AudiobookshelfBook{ID: "abs-1"}
+			book.Media.Duration = 3600
+			tt.book(&book)
+
+			assert.Equal(t, tt.wantSettle, svc.settledInHardcover(book, hcBook))
+		})
+	}
+}
+
+func TestLoadHardcoverFinished_Disabled(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.hardcoverFinished = map[string]time.Time{"10": {}}
+
+	svc.loadHardcoverFinished(context.Background())
+
+	assert.Nil(t, svc.hardcoverFinished)
+	mockClient.AssertNotCalled(t, "GetFinishedUserBooks", mock.Anything)
+}
@@ -0,0 +1,82 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// defaultHeartbeatInterval is used when Sync.HeartbeatInterval isn't set
+const defaultHeartbeatInterval = 30 * time.Second
+
+// startHeartbeat writes the current time to Sync.HeartbeatFile when a sync starts, so a watchdog
+// can tell a stuck sync from a live process: the file only exists during a sync, and heartbeat
+// rewrites it as the sync makes progress, so a stale modification time means the sync is stuck.
+// The returned function removes the file.
+func (s *Service) startHeartbeat() func() {
+	path := s.config.Sync.HeartbeatFile
+	if path == "" {
+		return func() {}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		s.log.Warn("Failed to create heartbeat file directory, not writing heartbeats", map[string]interface{}{
+			"path":  path,
+			"error": err.Error(),
+		})
+		return func() {}
+	}
+
+	s.heartbeatMutex.Lock()
+	s.heartbeatPath = path
+	s.lastHeartbeat = time.Now()
+	s.heartbeatMutex.Unlock()
+	s.writeHeartbeat(path)
+
+	return func() {
+		s.heartbeatMutex.Lock()
+		s.heartbeatPath = ""
+		s.heartbeatMutex.Unlock()
+
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			s.log.Warn("Failed to remove heartbeat file", map[string]interface{}{
+				"path":  path,
+				"error": err.Error(),
+			})
+		}
+	}
+}
+
+// heartbeat rewrites the heartbeat file of the running sync, at most once every
+// Sync.HeartbeatInterval. It's called as the sync makes progress, e.g. after each book, so the file
+// goes stale when the sync is stuck.
+func (s *Service) heartbeat() {
+	interval := s.config.Sync.HeartbeatInterval
+	if interval <= 0 {
+		interval = defaultHeartbeatInterval
+	}
+
+	s.heartbeatMutex.Lock()
+	path := s.heartbeatPath
+	due := path != "" && time.Since(s.lastHeartbeat) >= interval
+	if due {
+		s.lastHeartbeat = time.Now()
+	}
+	s.heartbeatMutex.Unlock()
+
+	if due {
+		s.writeHeartbeat(path)
+	}
+}
+
+// writeHeartbeat writes the current time to the heartbeat file. Failures are logged, as a missed
+// heartbeat doesn't affect the sync.
+func (s *Service) writeHeartbeat(path string) {
+	now := time.Now()
+	if err := os.WriteFile(path, []byte(now.Format(time.RFC3339)+"\n"), 0644); err != nil {
+		s.log.Warn("Failed to write heartbeat file", map[string]interface{}{
+			"path":  path,
+			"error": err.Error(),
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package sync
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/audiobookshelf"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSync_HeartbeatFollowsProgress(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.summary = &SyncSummary{}
+	svc.statePath = filepath.Join(t.TempDir(), "state.json")
+	svc.persistentCache = NewPersistentASINCache(t.TempDir())
+	svc.config.Paths.MismatchOutputDir = t.TempDir()
+	heartbeatFile := filepath.Join(t.TempDir(), "run", "heartbeat")
+	svc.config.Sync.HeartbeatFile = heartbeatFile
+	svc.config.Sync.HeartbeatInterval = 20 * time.Millisecond
+
+	modTime := func() time.Time {
+		info, err := os.Stat(heartbeatFile)
+		require.NoError(t, err)
+		return info.ModTime()
+	}
+
+	// Fetching the libraries hangs for several heartbeat intervals without any progress
+	var firstBeat, stuckBeat, processingBeat time.Time
+	mockABS := new(MockAudiobookshelfClient)
+	mockABS.On("GetUserProgress", mock.Anything).Return(&models.AudiobookshelfUserProgress{}, nil)
+	mockABS.On("GetLibraries", mock.Anything).Run(func(args mock.Arguments) {
+		firstBeat = modTime()
+		time.Sleep(150 * time.Millisecond)
+		stuckBeat = modTime()
+	}).Return([]audiobookshelf.AudiobookshelfLibrary{{ID: "lib1", Name: "Audiobooks"}}, nil)
+	book := models.AudiobookshelfBook{ID: "li_1", LibraryID: "lib1", MediaType: "book"}
+	book.Media.Metadata.Title = "Audiobook"
+	book.Media.Metadata.ASIN = "B000000001"
+	mockABS.On("GetLibraryItems", mock.Anything, "lib1").Return([]models.AudiobookshelfBook{book}, nil)
+	svc.audiobookshelf = mockABS
+
+	// Processing the fetched book is progress again
+	mockClient.On("SearchBookByASIN", mock.Anything, "B000000001").Run(func(args mock.Arguments) {
+		processingBeat = modTime()
+	}).Return(nil, nil)
+	mockClient.On("SearchBookByASINAnyFormat", mock.Anything, "B000000001").Return(nil, nil).Maybe()
+
+	require.NoError(t, svc.Sync(context.Background()))
+
+	assert.False(t, firstBeat.IsZero(), "the heartbeat file is written when the sync starts")
+	assert.Equal(t, firstBeat, stuckBeat, "no heartbeats are written while the sync is stuck")
+	assert.True(t, processingBeat.After(stuckBeat), "heartbeats are written as the sync makes progress")
+	_, err := os.Stat(heartbeatFile)
+	assert.True(t, os.IsNotExist(err), "the heartbeat file is removed when the sync ends")
+}
+
+func TestHeartbeat_RateLimited(t *testing.T) {
+	svc, _ := createTestService()
+	heartbeatFile := filepath.Join(t.TempDir(), "heartbeat")
+	svc.config.Sync.HeartbeatFile = heartbeatFile
+	svc.config.Sync.HeartbeatInterval = time.Hour
+
+	stop := svc.startHeartbeat()
+	info, err := os.Stat(heartbeatFile)
+	require.NoError(t, err)
+
+	// Within the interval progress doesn't rewrite the file
+	require.NoError(t, os.Chtimes(heartbeatFile, time.Time{}, info.ModTime().Add(-time.Minute)))
+	svc.heartbeat()
+	rewritten, err := os.Stat(heartbeatFile)
+	require.NoError(t, err)
+	assert.Equal(t, info.ModTime().Add(-time.Minute), rewritten.ModTime())
+
+	// Once it passed, it does
+	svc.lastHeartbeat = time.Now().Add(-2 * time.Hour)
+	svc.heartbeat()
+	rewritten, err = os.Stat(heartbeatFile)
+	require.NoError(t, err)
+	assert.False(t, rewritten.ModTime().Before(info.ModTime()))
+
+	// No heartbeat is written after stopping
+	stop()
+	svc.lastHeartbeat = time.Now().Add(-2 * time.Hour)
+	svc.heartbeat()
+	_, err = os.Stat(heartbeatFile)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestStartHeartbeat_Disabled(t *testing.T) {
+	svc, _ := createTestService()
+	svc.config.Sync.HeartbeatFile = ""
+
+	stop := svc.startHeartbeat()
+	stop()
+}
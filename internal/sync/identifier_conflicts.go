@@ -0,0 +1,120 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// IdentifierConflict records a book whose metadata carried several differing ASINs or ISBNs
+type IdentifierConflict struct {
+	BookID string   `json:"book_id"`
+	Title  string   `json:"title"`
+	ASINs  []string `json:"asins,omitempty"`
+	ISBNs  []string `json:"isbns,omitempty"`
+	// MatchedBy is the identifier the book was found by, empty when none of them matched
+	MatchedBy string `json:"matched_by,omitempty"`
+}
+
+// findBookByConflictingIdentifiers looks up a book whose metadata carries several differing ASINs
+// or ISBNs as configured by Sync.IdentifierConflicts, trying the ASINs, then the ISBNs, in priority
+// order. Like findBookInHardcoverByIdentifiers, the bool reports whether the lookup decided the result.
+func (s *Service) findBookByConflictingIdentifiers(ctx context.Context, book models.AudiobookshelfBook, ids models.Identifiers) (*models.HardcoverBook, bookMatch, bool, error) {
+	behavior := s.config.Sync.IdentifierConflicts
+	if behavior == "" {
+		behavior = config.IdentifierConflictsTryAll
+	}
+	log := s.log.With(map[string]interface{}{
+		"book_id":  book.ID,
+		"title":    book.Media.Metadata.Title,
+		"asins":    ids.ASINs,
+		"isbns":    ids.ISBNs,
+		"behavior": behavior,
+	})
+	log.Warn("Book metadata has conflicting identifiers", nil)
+
+	conflict := IdentifierConflict{
+		BookID: book.ID,
+		Title:  book.Media.Metadata.Title,
+		ASINs:  ids.ASINs,
+		ISBNs:  ids.ISBNs,
+	}
+	defer s.recordIdentifierConflict(&conflict)
+
+	if behavior == config.IdentifierConflictsSkip {
+		return nil, bookMatch{}, true, fmt.Errorf("conflicting identifiers (ASINs %v, ISBNs %v), not syncing", ids.ASINs, ids.ISBNs)
+	}
+
+	asins, isbns := ids.ASINs, ids.ISBNs
+	if behavior == config.IdentifierConflictsFirst {
+		asins, isbns = firstIdentifier(asins), firstIdentifier(isbns)
+	}
+
+	// Each identifier is looked up on its own, as if it were the only one in the metadata
+	type candidate struct{ asin, isbn string }
+	var candidates []candidate
+	for _, asin := range asins {
+		candidates = append(candidates, candidate{asin: asin})
+	}
+	for _, isbn := range isbns {
+		candidates = append(candidates, candidate{isbn: isbn})
+	}
+
+	// A lookup that found a book but failed, e.g. on strict identifier verification, is only
+	// returned when none of the other identifiers match
+	var failedBook *models.HardcoverBook
+	var failedMatch bookMatch
+	var failedErr error
+	for _, c := range candidates {
+		identifier := c.asin + c.isbn
+		single := book
+		single.Media.Metadata.ASIN = c.asin
+		single.Media.Metadata.ISBN = c.isbn
+
+		hcBook, match, done, err := s.findBookInHardcoverByIdentifiers(ctx, single, log.With(map[string]interface{}{
+			"identifier": identifier,
+		}))
+		if !done {
+			continue
+		}
+		if err != nil {
+			if failedErr == nil {
+				failedBook, failedMatch, failedErr = hcBook, match, err
+			}
+			continue
+		}
+
+		conflict.MatchedBy = identifier
+		log.Info("Found book by one of its conflicting identifiers", map[string]interface{}{
+			"matched_by": identifier,
+		})
+		return hcBook, match, true, nil
+	}
+
+	if failedErr != nil {
+		return failedBook, failedMatch, true, failedErr
+	}
+	log.Warn("None of the conflicting identifiers matched a book", nil)
+	return nil, bookMatch{}, false, nil
+}
+
+// firstIdentifier returns the first of the identifiers, if any
+func firstIdentifier(identifiers []string) []string {
+	if len(identifiers) > 1 {
+		return identifiers[:1]
+	}
+	return identifiers
+}
+
+// recordIdentifierConflict adds the conflict to the summary of the current run
+func (s *Service) recordIdentifierConflict(conflict *IdentifierConflict) {
+	if s.summary == nil {
+		return
+	}
+
+	s.summary.Lock()
+	defer s.summary.Unlock()
+	s.summary.IdentifierConflicts = append(s.summary.IdentifierConflicts, *conflict)
+}
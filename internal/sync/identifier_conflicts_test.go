@@ -0,0 +1,86 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindBookInHardcover_ConflictingASINs(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.summary = &SyncSummary{}
+	svc.persistentCache = NewPersistentASINCache(t.TempDir())
+
+	// The first ASIN no longer resolves, the second one does
+	mockClient.On("SearchBookByASIN", mock.Anything, "B0STALE001").Return(nil, fmt.Errorf("no edition found")).Once()
+	mockClient.On("SearchBookByASIN", mock.Anything, "B0CURRENT1").
+		Return(&models.HardcoverBook{ID: "20", EditionID: "200", EditionASIN: "B0CURRENT1"}, nil).Once()
+	mockClient.On("GetUserBookID", mock.Anything, 200).Return(555, nil)
+
+	book := newTestBook("abs-1", "Conflicting Audiobook")
+	// The ASIN and ISBN fields carry two different ASINs
+	book.Media.Metadata.ASIN = "B0STALE001"
+	book.Media.Metadata.ISBN = "B0CURRENT1"
+
+	hcBook, _, err := svc.findBookInHardcover(context.Background(), book)
+	require.NoError(t, err)
+	require.NotNil(t, hcBook)
+	assert.Equal(t, "200", hcBook.EditionID)
+	assert.Equal(t, "555", hcBook.UserBookID)
+	mockClient.AssertExpectations(t)
+
+	assert.Equal(t, []IdentifierConflict{{
+		BookID:    "abs-1",
+		Title:     "Conflicting Audiobook",
+		ASINs:     []string{"B0STALE001", "B0CURRENT1"},
+		MatchedBy: "B0CURRENT1",
+	}}, svc.GetSummary().IdentifierConflicts)
+}
+
+func TestFindBookInHardcover_ConflictingASINsFirstOnly(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.summary = &SyncSummary{}
+	svc.persistentCache = NewPersistentASINCache(t.TempDir())
+	svc.config.Sync.IdentifierConflicts = config.IdentifierConflictsFirst
+
+	// Only the first ASIN is tried before falling back to the title/author search, which isn't
+	// possible without an author
+	mockClient.On("SearchBookByASINAnyFormat", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	mockClient.On("SearchBookByASIN", mock.Anything, "B0STALE001").Return(nil, fmt.Errorf("no edition found")).Once()
+
+	book := newTestBook("abs-1", "Conflicting Audiobook")
+	book.Media.Metadata.ASIN = "B0STALE001"
+	book.Media.Metadata.ISBN = "B0CURRENT1"
+
+	hcBook, _, err := svc.findBookInHardcover(context.Background(), book)
+	require.Error(t, err)
+	assert.Nil(t, hcBook)
+	mockClient.AssertExpectations(t)
+
+	conflicts := svc.GetSummary().IdentifierConflicts
+	require.Len(t, conflicts, 1)
+	assert.Empty(t, conflicts[0].MatchedBy)
+}
+
+func TestFindBookInHardcover_ConflictingASINsSkipped(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.summary = &SyncSummary{}
+	svc.config.Sync.IdentifierConflicts = config.IdentifierConflictsSkip
+
+	book := newTestBook("abs-1", "Conflicting Audiobook")
+	book.Media.Metadata.ASIN = "B0STALE001"
+	book.Media.Metadata.ISBN = "B0CURRENT1"
+
+	hcBook, _, err := svc.findBookInHardcover(context.Background(), book)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "conflicting identifiers")
+	assert.Nil(t, hcBook)
+	mockClient.AssertExpectations(t)
+	assert.Len(t, svc.GetSummary().IdentifierConflicts, 1)
+}
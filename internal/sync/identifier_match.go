@@ -0,0 +1,70 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/mismatch"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// errIdentifierMismatch is returned by findBookInHardcover when Sync.StrictIdentifierMatch is enabled
+// and the matched edition doesn't carry the Audiobookshelf identifier it was searched by
+var errIdentifierMismatch = errors.New("identifier mismatch on matched edition")
+
+// verifyMatchedEditionASIN checks that the edition matched by ASIN carries the book's ASIN
+func (s *Service) verifyMatchedEditionASIN(book models.AudiobookshelfBook, hcBook *models.HardcoverBook) error {
+	if !s.config.Sync.StrictIdentifierMatch {
+		return nil
+	}
+
+	asin := book.Media.Metadata.ASIN
+	if strings.EqualFold(strings.TrimSpace(hcBook.EditionASIN), strings.TrimSpace(asin)) {
+		return nil
+	}
+	if hcBook.EditionASIN == "" {
+		return fmt.Errorf("%w: edition %s has no ASIN, expected %s", errIdentifierMismatch, hcBook.EditionID, asin)
+	}
+	return fmt.Errorf("%w: edition %s has ASIN %s, expected %s", errIdentifierMismatch, hcBook.EditionID, hcBook.EditionASIN, asin)
+}
+
+// verifyMatchedEditionISBN checks that the edition matched by ISBN carries the book's ISBN as
+// either its ISBN-13 or ISBN-10
+func (s *Service) verifyMatchedEditionISBN(book models.AudiobookshelfBook, hcBook *models.HardcoverBook) error {
+	if !s.config.Sync.StrictIdentifierMatch {
+		return nil
+	}
+
+	isbn := normalizeISBN(book.Media.Metadata.ISBN)
+	if isbn != "" && (isbn == normalizeISBN(hcBook.EditionISBN13) || isbn == normalizeISBN(hcBook.EditionISBN10)) {
+		return nil
+	}
+
+	var editionISBNs []string
+	for _, editionISBN := range []string{hcBook.EditionISBN13, hcBook.EditionISBN10} {
+		if editionISBN != "" {
+			editionISBNs = append(editionISBNs, editionISBN)
+		}
+	}
+	if len(editionISBNs) == 0 {
+		return fmt.Errorf("%w: edition %s has no ISBN, expected %s", errIdentifierMismatch, hcBook.EditionID, book.Media.Metadata.ISBN)
+	}
+	return fmt.Errorf("%w: edition %s has ISBN %s, expected %s", errIdentifierMismatch, hcBook.EditionID,
+		strings.Join(editionISBNs, "/"), book.Media.Metadata.ISBN)
+}
+
+// normalizeISBN strips hyphens and spaces from an ISBN for comparison
+func normalizeISBN(isbn string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(isbn))
+}
+
+// recordIdentifierMismatch records an "identifier mismatch on matched edition" mismatch for a book
+// whose matched Hardcover edition failed strict identifier verification
+func (s *Service) recordIdentifierMismatch(book models.AudiobookshelfBook, hcBook *models.HardcoverBook, err error) {
+	// e.g. "Identifier mismatch on matched edition: edition 2 has ASIN B0OTHER, expected B0WANTED"
+	reason := err.Error()
+	reason = strings.ToUpper(reason[:1]) + reason[1:]
+
+	mismatch.Add(s.bookMismatch(book, hcBook, reason))
+}
@@ -0,0 +1,110 @@
+package sync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/mismatch"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessBook_StrictIdentifierMatch(t *testing.T) {
+	newBook := func(asin, isbn string) models.AudiobookshelfBook {
+		book := models.AudiobookshelfBook{ID: "abs-book", LibraryID: "lib1", MediaType: "book"}
+		book.Media.Metadata.Title = "Sibling Edition"
+		book.Media.Metadata.ASIN = asin
+		book.Media.Metadata.ISBN = isbn
+		book.Media.Duration = 3600
+		book.Progress.CurrentTime = 600
+		return book
+	}
+
+	tests := []struct {
+		name       string
+		book       models.AudiobookshelfBook
+		setupMock  func(*MockHardcoverClient)
+		wantReason string
+	}{
+		{
+			name: "ASIN of sibling edition",
+			book: newBook("B0WANTED01", ""),
+			setupMock: func(m *MockHardcoverClient) {
+				m.On("SearchBookByASIN", mock.Anything, "B0WANTED01").Return(&models.HardcoverBook{
+					ID: "123", EditionID: "456", Title: "Sibling Edition", EditionASIN: "B0OTHER001",
+				}, nil).Once()
+			},
+			wantReason: "Identifier mismatch on matched edition: edition 456 has ASIN B0OTHER001, expected B0WANTED01",
+		},
+		{
+			name: "edition without ASIN",
+			book: newBook("B0WANTED01", ""),
+			setupMock: func(m *MockHardcoverClient) {
+				m.On("SearchBookByASIN", mock.Anything, "B0WANTED01").Return(&models.HardcoverBook{
+					ID: "123", EditionID: "456", Title: "Sibling Edition",
+				}, nil).Once()
+			},
+			wantReason: "Identifier mismatch on matched edition: edition 456 has no ASIN, expected B0WANTED01",
+		},
+		{
+			name: "ISBN of sibling edition",
+			book: newBook("", "978-1-234567-89-7"),
+			setupMock: func(m *MockHardcoverClient) {
+				m.On("SearchBookByISBN13", mock.Anything, "978-1-234567-89-7").Return(&models.HardcoverBook{
+					ID: "123", EditionID: "456", Title: "Sibling Edition", EditionISBN13: "9780000000002",
+				}, nil).Once()
+			},
+			wantReason: "Identifier mismatch on matched edition: edition 456 has ISBN 9780000000002, expected 978-1-234567-89-7",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mismatch.Clear()
+			defer mismatch.Clear()
+
+			svc, mockClient := createTestService()
+			svc.summary = &SyncSummary{}
+			svc.config.Sync.StrictIdentifierMatch = true
+			tt.setupMock(mockClient)
+
+			require.NoError(t, svc.processBook(context.Background(), tt.book, &models.AudiobookshelfUserProgress{}))
+
+			// Nothing is written to Hardcover for the sibling edition
+			mockClient.AssertExpectations(t)
+			mockClient.AssertNotCalled(t, "MarkEditionAsOwned", mock.Anything, mock.Anything)
+			mockClient.AssertNotCalled(t, "GetUserBookID", mock.Anything, mock.Anything)
+			mockClient.AssertNotCalled(t, "CreateUserBook", mock.Anything, mock.Anything, mock.Anything)
+
+			all := mismatch.GetAll()
+			require.Len(t, all, 1)
+			assert.Equal(t, "abs-book", all[0].BookID)
+			assert.Equal(t, "123", all[0].HardcoverBookID)
+			assert.Equal(t, tt.wantReason, all[0].Reason)
+		})
+	}
+}
+
+func TestVerifyMatchedEdition(t *testing.T) {
+	book := models.AudiobookshelfBook{ID: "abs-book"}
+	book.Media.Metadata.ASIN = "B0WANTED01"
+	book.Media.Metadata.ISBN = "0-306-40615-2"
+
+	svc, _ := createTestService()
+	sibling := &models.HardcoverBook{ID: "123", EditionID: "456", EditionASIN: "B0OTHER001", EditionISBN13: "9780000000002"}
+
+	// Disabled by default
+	assert.NoError(t, svc.verifyMatchedEditionASIN(book, sibling))
+	assert.NoError(t, svc.verifyMatchedEditionISBN(book, sibling))
+
+	svc.config.Sync.StrictIdentifierMatch = true
+	assert.ErrorIs(t, svc.verifyMatchedEditionASIN(book, sibling), errIdentifierMismatch)
+	assert.ErrorIs(t, svc.verifyMatchedEditionISBN(book, sibling), errIdentifierMismatch)
+
+	// Identifiers are compared case-insensitively and without hyphens
+	matching := &models.HardcoverBook{ID: "123", EditionID: "789", EditionASIN: "b0wanted01", EditionISBN10: "0306406152"}
+	assert.NoError(t, svc.verifyMatchedEditionASIN(book, matching))
+	assert.NoError(t, svc.verifyMatchedEditionISBN(book, matching))
+}
@@ -0,0 +1,40 @@
+package sync
+
+import (
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// identifierTrusted reports whether Sync.IdentifierTrust trusts the identifier type, which it does
+// unless it's set to false
+func (s *Service) identifierTrusted(identifier string) bool {
+	trusted, ok := s.config.Sync.IdentifierTrust[identifier]
+	return !ok || trusted
+}
+
+// withoutUntrustedIdentifiers returns the book to look up by its identifiers. When the book has both
+// an ASIN and an ISBN and only one of them is trusted, the other one is dropped, so a wrong scraped
+// identifier can't match a different book. An untrusted identifier is only used when it's all the
+// book has.
+func (s *Service) withoutUntrustedIdentifiers(book models.AudiobookshelfBook, log *logger.Logger) models.AudiobookshelfBook {
+	metadata := book.Media.Metadata
+	if metadata.ASIN == "" || metadata.ISBN == "" {
+		return book
+	}
+
+	asinTrusted, isbnTrusted := s.identifierTrusted(config.IdentifierASIN), s.identifierTrusted(config.IdentifierISBN)
+	switch {
+	case asinTrusted && !isbnTrusted:
+		log.Debug("Not looking the book up by its untrusted ISBN, it has a trusted ASIN", map[string]interface{}{
+			"isbn": metadata.ISBN,
+		})
+		book.Media.Metadata.ISBN = ""
+	case isbnTrusted && !asinTrusted:
+		log.Debug("Not looking the book up by its untrusted ASIN, it has a trusted ISBN", map[string]interface{}{
+			"asin": metadata.ASIN,
+		})
+		book.Media.Metadata.ASIN = ""
+	}
+	return book
+}
@@ -0,0 +1,89 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindBookInHardcover_UntrustedISBNNotUsedWithTrustedASIN(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.persistentCache = NewPersistentASINCache(t.TempDir())
+	svc.config.Sync.IdentifierTrust = map[string]bool{config.IdentifierASIN: true, config.IdentifierISBN: false}
+
+	// The ASIN isn't on Hardcover, and the scraped ISBN would match a different book
+	mockClient.On("SearchBookByASINAnyFormat", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	mockClient.On("SearchBookByASIN", mock.Anything, "B0TRUSTED1").Return(nil, fmt.Errorf("no edition found")).Once()
+	mockClient.On("SearchBookByISBN13", mock.Anything, mock.Anything).
+		Return(&models.HardcoverBook{ID: "99", EditionID: "990"}, nil).Maybe()
+
+	// Both an ASIN and an ISBN, and no author to fall back to
+	book := newTestBook("abs-trust", "Trusted Audiobook")
+	book.Media.Metadata.ASIN = "B0TRUSTED1"
+	book.Media.Metadata.ISBN = "9781234567897"
+
+	hcBook, _, err := svc.findBookInHardcover(context.Background(), book)
+	require.Error(t, err)
+	assert.Nil(t, hcBook)
+	mockClient.AssertExpectations(t)
+	mockClient.AssertNotCalled(t, "SearchBookByISBN13", mock.Anything, mock.Anything)
+	mockClient.AssertNotCalled(t, "SearchBookByISBN10", mock.Anything, mock.Anything)
+}
+
+func TestFindBookInHardcover_TrustedISBNInsteadOfUntrustedASIN(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.persistentCache = NewPersistentASINCache(t.TempDir())
+	svc.config.Sync.IdentifierTrust = map[string]bool{config.IdentifierASIN: false}
+
+	mockClient.On("SearchBookByISBN13", mock.Anything, "9781234567897").
+		Return(&models.HardcoverBook{ID: "10", EditionID: "100", EditionISBN13: "9781234567897"}, nil).Once()
+	mockClient.On("GetUserBookID", mock.Anything, 100).Return(555, nil).Maybe()
+	mockClient.On("CheckBookOwnership", mock.Anything, 10).Return(true, nil).Maybe()
+
+	book := newTestBook("abs-trust", "Trusted Audiobook")
+	book.Media.Metadata.ASIN = "B0TRUSTED1"
+	book.Media.Metadata.ISBN = "9781234567897"
+
+	hcBook, _, err := svc.findBookInHardcover(context.Background(), book)
+	require.NoError(t, err)
+	require.NotNil(t, hcBook)
+	assert.Equal(t, "100", hcBook.EditionID)
+	mockClient.AssertNotCalled(t, "SearchBookByASIN", mock.Anything, mock.Anything)
+}
+
+func TestWithoutUntrustedIdentifiers(t *testing.T) {
+	tests := []struct {
+		name     string
+		trust    map[string]bool
+		asin     string
+		isbn     string
+		wantASIN string
+		wantISBN string
+	}{
+		{"both trusted by default", nil, "B0TRUSTED1", "9781234567897", "B0TRUSTED1", "9781234567897"},
+		{"untrusted ISBN dropped", map[string]bool{"isbn": false}, "B0TRUSTED1", "9781234567897", "B0TRUSTED1", ""},
+		{"untrusted ASIN dropped", map[string]bool{"asin": false}, "B0TRUSTED1", "9781234567897", "", "9781234567897"},
+		{"both untrusted are kept", map[string]bool{"asin": false, "isbn": false}, "B0TRUSTED1", "9781234567897", "B0TRUSTED1", "9781234567897"},
+		{"untrusted ISBN used as a last resort", map[string]bool{"isbn": false}, "", "9781234567897", "", "9781234567897"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := createTestService()
+			svc.config.Sync.IdentifierTrust = tt.trust
+			book := newTestBook("abs-trust", "Trusted Audiobook")
+			book.Media.Metadata.ASIN = tt.asin
+			book.Media.Metadata.ISBN = tt.isbn
+
+			lookup := svc.withoutUntrustedIdentifiers(book, svc.log)
+			assert.Equal(t, tt.wantASIN, lookup.Media.Metadata.ASIN)
+			assert.Equal(t, tt.wantISBN, lookup.Media.Metadata.ISBN)
+		})
+	}
+}
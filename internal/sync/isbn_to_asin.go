@@ -0,0 +1,49 @@
+package sync
+
+import (
+	"context"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/sync/state"
+)
+
+// findBookByISBNToASIN looks up the ASIN of a book that has an ISBN but no ASIN in the metadata
+// provider set by Metadata.ISBNToASINURL, and looks the book up in Hardcover by that ASIN, as
+// Hardcover often only has the ASIN on the audiobook edition. The bool reports whether the lookup
+// decided the result, like findBookInHardcoverByIdentifiers. A book found this way was matched by
+// its ISBN.
+func (s *Service) findBookByISBNToASIN(ctx context.Context, book models.AudiobookshelfBook, log *logger.Logger) (*models.HardcoverBook, bookMatch, bool, error) {
+	if s.asinProvider == nil || book.Media.Metadata.ISBN == "" || book.Media.Metadata.ASIN != "" {
+		return nil, bookMatch{}, false, nil
+	}
+
+	isbn := book.Media.Metadata.ISBN
+	asin, err := s.asinProvider.ASINForISBN(ctx, isbn)
+	if err != nil {
+		log.Warn("Failed to look up the ASIN of the book's ISBN", map[string]interface{}{
+			"isbn":  isbn,
+			"error": err.Error(),
+		})
+		return nil, bookMatch{}, false, nil
+	}
+	if asin == "" {
+		log.Debug("No ASIN known for the book's ISBN", map[string]interface{}{
+			"isbn": isbn,
+		})
+		return nil, bookMatch{}, false, nil
+	}
+
+	log.Info("Searching for book by the ASIN of its ISBN", map[string]interface{}{
+		"isbn": isbn,
+		"asin": asin,
+	})
+	asinBook := book
+	asinBook.Media.Metadata.ASIN = asin
+	asinBook.Media.Metadata.ISBN = ""
+	hcBook, match, done, err := s.findBookInHardcoverByIdentifiers(ctx, asinBook, log)
+	if done {
+		match.Source = state.MatchSourceISBN
+	}
+	return hcBook, match, done, err
+}
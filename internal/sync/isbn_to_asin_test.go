@@ -0,0 +1,63 @@
+package sync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/sync/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeASINProvider knows the ASINs of the ISBNs in its map
+type fakeASINProvider map[string]string
+
+func (p fakeASINProvider) ASINForISBN(_ context.Context, isbn string) (string, error) {
+	return p[isbn], nil
+}
+
+// isbnOnlyBook returns a book with an ISBN Hardcover doesn't have, and no ASIN or author
+func isbnOnlyBook(mockClient *MockHardcoverClient) models.AudiobookshelfBook {
+	book := models.AudiobookshelfBook{ID: "abs-isbn", LibraryID: "lib1", MediaType: "book"}
+	book.Media.Metadata.Title = "ISBN Only Audiobook"
+	book.Media.Metadata.ISBN = "9781234567897"
+	book.Media.Duration = 1000
+	book.Progress.CurrentTime = 300
+
+	mockClient.On("SearchBookByISBN13", mock.Anything, "9781234567897").Return(nil, nil).Once()
+	mockClient.On("SearchBookByISBN10", mock.Anything, "9781234567897").Return(nil, nil).Once()
+	return book
+}
+
+func TestFindBookInHardcover_ISBNToASIN(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.persistentCache = NewPersistentASINCache(t.TempDir())
+	svc.asinProvider = fakeASINProvider{"9781234567897": "B0CROSS001"}
+	book := isbnOnlyBook(mockClient)
+
+	mockClient.On("SearchBookByASIN", mock.Anything, "B0CROSS001").
+		Return(&models.HardcoverBook{ID: "10", EditionID: "100", EditionASIN: "B0CROSS001"}, nil).Once()
+	mockClient.On("GetUserBookID", mock.Anything, 100).Return(555, nil).Maybe()
+	mockClient.On("CheckBookOwnership", mock.Anything, 10).Return(true, nil).Maybe()
+
+	hcBook, match, err := svc.findBookInHardcover(context.Background(), book)
+	require.NoError(t, err)
+	require.NotNil(t, hcBook)
+	assert.Equal(t, "100", hcBook.EditionID)
+	assert.Equal(t, state.MatchSourceISBN, match.Source, "the book was matched by its ISBN")
+	mockClient.AssertExpectations(t)
+}
+
+func TestFindBookInHardcover_ISBNToASINUnknown(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.persistentCache = NewPersistentASINCache(t.TempDir())
+	svc.asinProvider = fakeASINProvider{}
+	book := isbnOnlyBook(mockClient)
+
+	hcBook, _, err := svc.findBookInHardcover(context.Background(), book)
+	require.Error(t, err)
+	assert.Nil(t, hcBook)
+	mockClient.AssertNotCalled(t, "SearchBookByASIN", mock.Anything, mock.Anything)
+}
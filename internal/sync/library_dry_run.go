@@ -0,0 +1,57 @@
+package sync
+
+import (
+	"context"
+	"strings"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/audiobookshelf"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+)
+
+// libraryOverrideKey is the context key of the Sync.Libraries.Overrides entry of the library being
+// processed
+type libraryOverrideKey struct{}
+
+// libraryOverride returns the Sync.Libraries.Overrides entry of the library, matched by name
+// (case-insensitive) or ID like the include and exclude lists
+func (s *Service) libraryOverride(library *audiobookshelf.AudiobookshelfLibrary) (config.LibraryOverride, bool) {
+	for key, override := range s.config.Sync.Libraries.Overrides {
+		if strings.EqualFold(key, library.Name) || key == library.ID {
+			return override, true
+		}
+	}
+	return config.LibraryOverride{}, false
+}
+
+// withLibraryOverride returns a context carrying the override of the library whose books are about
+// to be processed, if it has one
+func (s *Service) withLibraryOverride(ctx context.Context, library *audiobookshelf.AudiobookshelfLibrary) context.Context {
+	override, ok := s.libraryOverride(library)
+	if !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, libraryOverrideKey{}, override)
+}
+
+// dryRun reports whether changes to Hardcover made with ctx are only planned: in dry-run mode, or
+// while processing a library whose override puts it in dry-run mode
+func (s *Service) dryRun(ctx context.Context) bool {
+	if s.config.Sync.DryRun {
+		return true
+	}
+	override, ok := ctx.Value(libraryOverrideKey{}).(config.LibraryOverride)
+	return ok && override.DryRun
+}
+
+// anyDryRun reports whether any books of the run may be processed in dry-run mode
+func (s *Service) anyDryRun() bool {
+	if s.config.Sync.DryRun {
+		return true
+	}
+	for _, override := range s.config.Sync.Libraries.Overrides {
+		if override.DryRun {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package sync
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/audiobookshelf"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+func TestSync_LibraryDryRun(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.summary = &SyncSummary{}
+	svc.statePath = filepath.Join(t.TempDir(), "state.json")
+	svc.persistentCache = NewPersistentASINCache(t.TempDir())
+	svc.config.Paths.MismatchOutputDir = t.TempDir()
+	// Ownership only keeps each book down to a single mutation
+	svc.config.Sync.OwnershipOnly = true
+	svc.config.Sync.SyncOwned = false
+	svc.config.Sync.Libraries.Overrides = map[string]config.LibraryOverride{
+		"testing": {DryRun: true},
+	}
+
+	live := models.AudiobookshelfBook{ID: "abs-live", LibraryID: "lib1", MediaType: "book"}
+	live.Media.Metadata.Title = "Live Audiobook"
+	live.Media.Metadata.ASIN = "B000000001"
+	trial := models.AudiobookshelfBook{ID: "abs-trial", LibraryID: "lib2", MediaType: "book"}
+	trial.Media.Metadata.Title = "Trial Audiobook"
+	trial.Media.Metadata.ASIN = "B000000002"
+
+	mockClient.On("SearchBookByASIN", mock.Anything, "B000000001").
+		Return(&models.HardcoverBook{ID: "10", EditionID: "100", EditionASIN: "B000000001"}, nil)
+	mockClient.On("SearchBookByASIN", mock.Anything, "B000000002").
+		Return(&models.HardcoverBook{ID: "20", EditionID: "200", EditionASIN: "B000000002"}, nil)
+	mockClient.On("SearchBooks", mock.Anything, mock.Anything, mock.Anything).Return([]models.HardcoverBook{}, nil).Maybe()
+	mockClient.On("CheckBookOwnership", mock.Anything, mock.Anything).Return(false, nil)
+	mockClient.On("MarkEditionAsOwned", mock.Anything, 100).Return(nil).Once()
+
+	mockABS := new(MockAudiobookshelfClient)
+	mockABS.On("GetUserProgress", mock.Anything).Return(&models.AudiobookshelfUserProgress{}, nil)
+	mockABS.On("GetLibraries", mock.Anything).Return([]audiobookshelf.AudiobookshelfLibrary{
+		{ID: "lib1", Name: "Audiobooks"},
+		{ID: "lib2", Name: "Testing"},
+	}, nil)
+	mockABS.On("GetLibraryItems", mock.Anything, "lib1").Return([]models.AudiobookshelfBook{live}, nil)
+	mockABS.On("GetLibraryItems", mock.Anything, "lib2").Return([]models.AudiobookshelfBook{trial}, nil)
+	svc.audiobookshelf = mockABS
+
+	require.NoError(t, svc.Sync(context.Background()))
+
+	// Only the library that isn't in dry-run mode was changed in Hardcover
+	mockClient.AssertExpectations(t)
+	mockClient.AssertNotCalled(t, "MarkEditionAsOwned", mock.Anything, 200)
+
+	// The dry-run library's changes are in the dry-run report
+	assert.Equal(t, []PlannedAction{
+		{Action: PlannedActionMarkOwned, ItemID: "abs-trial", Title: "Trial Audiobook", EditionID: "200"},
+	}, svc.PlannedActions())
+	assert.FileExists(t, filepath.Join(svc.config.Paths.MismatchOutputDir, defaultDryRunReportFile))
+}
+
+func TestDryRun(t *testing.T) {
+	svc, _ := createTestService()
+	svc.config.Sync.Libraries.Overrides = map[string]config.LibraryOverride{
+		"lib-dry":  {DryRun: true},
+		"Podcasts": {DryRun: false},
+	}
+
+	tests := []struct {
+		name    string
+		library audiobookshelf.AudiobookshelfLibrary
+		global  bool
+		want    bool
+	}{
+		{"overridden by ID", audiobookshelf.AudiobookshelfLibrary{ID: "lib-dry", Name: "Audiobooks"}, false, true},
+		{"override without dry run", audiobookshelf.AudiobookshelfLibrary{ID: "lib-pod", Name: "podcasts"}, false, false},
+		{"not overridden", audiobookshelf.AudiobookshelfLibrary{ID: "lib-other", Name: "Other"}, false, false},
+		{"global dry run wins", audiobookshelf.AudiobookshelfLibrary{ID: "lib-pod", Name: "Podcasts"}, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc.config.Sync.DryRun = tt.global
+			ctx := svc.withLibraryOverride(context.Background(), &tt.library)
+			assert.Equal(t, tt.want, svc.dryRun(ctx))
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package sync
+
+import (
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/mismatch"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// bookMatch is how findBookInHardcover matched a book
+type bookMatch struct {
+	// Source is the state.MatchSource constant of the lookup that found the book
+	Source string
+	// Score is how certain the match is, from 0 to 1. Identifier lookups and pins are exact, title/author
+	// matches score the similarity of the titles.
+	Score float64
+}
+
+// recordMatch stores how the book was matched to the Hardcover book in the sync state when
+// Sync.RecordMatchInfo is enabled
+func (s *Service) recordMatch(stateKey string, match bookMatch, hcBook *models.HardcoverBook) {
+	if !s.config.Sync.RecordMatchInfo || s.state == nil || hcBook == nil || match.Source == "" {
+		return
+	}
+
+	if s.state.RecordMatch(stateKey, match.Source, match.Score, hcBook.EditionID) {
+		s.log.Debug("Recorded match in sync state", map[string]interface{}{
+			"state_key":    stateKey,
+			"match_source": match.Source,
+			"match_score":  match.Score,
+			"edition_id":   hcBook.EditionID,
+		})
+	}
+}
+
+// titleAuthorSuggestion returns the book found by title/author as a mapping suggestion for its
+// mismatch when Sync.SuggestTitleAuthorMatches is enabled
+func (s *Service) titleAuthorSuggestion(hcBook *models.HardcoverBook) *mismatch.Suggestion {
+	if !s.config.Sync.SuggestTitleAuthorMatches || hcBook == nil || hcBook.ID == "" {
+		return nil
+	}
+	return &mismatch.Suggestion{
+		BookID:    hcBook.ID,
+		EditionID: hcBook.EditionID,
+		Title:     hcBook.Title,
+		Source:    MatchMethodTitleAuthor,
+	}
+}
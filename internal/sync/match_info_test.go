@@ -0,0 +1,92 @@
+package sync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/mismatch"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/sync/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindBookInHardcover_MatchSource(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.persistentCache = NewPersistentASINCache(t.TempDir())
+
+	book := models.AudiobookshelfBook{ID: "abs-1", LibraryID: "lib1", MediaType: "book"}
+	book.Media.Metadata.Title = "Test Audiobook"
+	book.Media.Metadata.ASIN = "B00TEST123"
+	book.Media.Metadata.ISBN = "9781234567890"
+
+	// The ASIN isn't known, the ISBN finds an edition whose identifiers aren't returned
+	mockClient.On("SearchBookByASIN", mock.Anything, "B00TEST123").Return(nil, nil)
+	mockClient.On("SearchBookByISBN13", mock.Anything, "9781234567890").
+		Return(&models.HardcoverBook{ID: "10", EditionID: "100"}, nil)
+	mockClient.On("GetUserBookID", mock.Anything, 100).Return(555, nil).Maybe()
+	mockClient.On("CheckBookOwnership", mock.Anything, 10).Return(true, nil).Maybe()
+
+	hcBook, match, err := svc.findBookInHardcover(context.Background(), book)
+	require.NoError(t, err)
+	require.NotNil(t, hcBook)
+	assert.Equal(t, bookMatch{Source: state.MatchSourceISBN, Score: 1}, match)
+}
+
+func TestRecordMatch(t *testing.T) {
+	svc, _ := createTestService()
+	hcBook := &models.HardcoverBook{ID: "10", EditionID: "100"}
+	match := bookMatch{Source: state.MatchSourceISBN, Score: 1}
+
+	// Disabled by default
+	svc.recordMatch("abs-1:100", match, hcBook)
+	_, exists := svc.state.GetBookState("abs-1:100")
+	assert.False(t, exists)
+
+	svc.config.Sync.RecordMatchInfo = true
+	svc.recordMatch("abs-1:100", match, hcBook)
+	recorded, exists := svc.state.GetBookState("abs-1:100")
+	require.True(t, exists)
+	assert.Equal(t, state.MatchSourceISBN, recorded.MatchSource)
+	assert.Equal(t, 1.0, recorded.MatchScore)
+	assert.Equal(t, "100", recorded.EditionID)
+}
+
+func TestProcessBook_TitleAuthorSuggestion(t *testing.T) {
+	mismatch.Clear()
+	defer mismatch.Clear()
+
+	svc, mockClient := createTestService()
+	svc.summary = &SyncSummary{}
+	svc.config.Sync.SuggestTitleAuthorMatches = true
+
+	// Without identifiers the book can only be found by title/author, which is recorded as a mismatch
+	book := models.AudiobookshelfBook{ID: "abs-1", LibraryID: "lib1", MediaType: "book"}
+	book.Media.Metadata.Title = "Suggested Audiobook"
+	book.Media.Metadata.AuthorName = "Test Author"
+	book.Media.Duration = 3600
+	book.Progress.CurrentTime = 600
+
+	mockClient.On("SearchBooks", mock.Anything, mock.Anything, mock.Anything).
+		Return([]models.HardcoverBook{{ID: "42", Title: "Suggested Audiobook"}}, nil)
+	mockClient.On("GetBookByID", mock.Anything, "42").Return(&models.HardcoverBook{
+		ID:        "42",
+		Title:     "Suggested Audiobook",
+		EditionID: "420",
+		Authors:   []models.Author{{ID: "7", Name: "Test Author"}},
+	}, nil)
+	mockClient.On("GetBookAuthors", mock.Anything, "42").Return([]models.Author{{ID: "7", Name: "Test Author"}}, nil)
+	mockClient.On("GetEdition", mock.Anything, "420").Return(&models.Edition{ID: "420", BookID: "42"}, nil)
+
+	require.NoError(t, svc.processBook(context.Background(), book, nil))
+
+	mismatches := mismatch.GetAll()
+	require.Len(t, mismatches, 1)
+	assert.Equal(t, &mismatch.Suggestion{
+		BookID:    "42",
+		EditionID: "420",
+		Title:     "Suggested Audiobook",
+		Source:    MatchMethodTitleAuthor,
+	}, mismatches[0].Suggestion)
+}
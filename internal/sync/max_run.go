@@ -0,0 +1,34 @@
+package sync
+
+import (
+	"context"
+)
+
+// withMaxRunDuration returns a context for processing the books of a run that expires after
+// Sync.MaxRunDuration, or ctx itself when no maximum is configured
+func (s *Service) withMaxRunDuration(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.config.Sync.MaxRunDuration <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, s.config.Sync.MaxRunDuration)
+}
+
+// maxRunDurationReached reports whether runCtx, created by withMaxRunDuration from ctx, expired
+// while ctx itself is still active, and records it in the summary so the run can end cleanly
+func (s *Service) maxRunDurationReached(ctx, runCtx context.Context) bool {
+	if ctx.Err() != nil || runCtx.Err() == nil {
+		return false
+	}
+
+	s.summary.Lock()
+	s.summary.RunTimedOut = true
+	s.summary.Unlock()
+	return true
+}
+
+// runTimedOut reports whether the current run stopped at Sync.MaxRunDuration
+func (s *Service) runTimedOut() bool {
+	s.summary.RLock()
+	defer s.summary.RUnlock()
+	return s.summary.RunTimedOut
+}
@@ -0,0 +1,67 @@
+package sync
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/audiobookshelf"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/sync/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSync_MaxRunDuration(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.summary = &SyncSummary{}
+	svc.statePath = filepath.Join(t.TempDir(), "state.json")
+	svc.config.Paths.MismatchOutputDir = t.TempDir()
+	svc.config.Sync.Incremental = true
+	svc.config.Sync.MaxRunDuration = 100 * time.Millisecond
+
+	// Every book is slow to look up, so only the first couple finish before the deadline
+	var books []models.AudiobookshelfBook
+	for _, id := range []string{"book-1", "book-2", "book-3", "book-4", "book-5", "book-6"} {
+		book := models.AudiobookshelfBook{ID: id, LibraryID: "lib1", MediaType: "book"}
+		book.Media.Metadata.Title = "Slow " + id
+		book.Media.Metadata.ASIN = "B0" + id
+		books = append(books, book)
+	}
+	var lookups int
+	mockClient.On("SearchBookByASINAnyFormat", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	mockClient.On("SearchBookByASIN", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		lookups++
+		select {
+		case <-time.After(40 * time.Millisecond):
+		case <-args.Get(0).(context.Context).Done():
+		}
+	}).Return(nil, nil)
+	mockClient.On("SearchBooks", mock.Anything, mock.Anything, mock.Anything).Return([]models.HardcoverBook{}, nil).Maybe()
+
+	mockABS := new(MockAudiobookshelfClient)
+	mockABS.On("GetUserProgress", mock.Anything).Return(&models.AudiobookshelfUserProgress{}, nil)
+	mockABS.On("GetLibraries", mock.Anything).Return([]audiobookshelf.AudiobookshelfLibrary{
+		{ID: "lib1", Name: "Audiobooks"},
+		{ID: "lib2", Name: "More Audiobooks"},
+	}, nil)
+	mockABS.On("GetLibraryItems", mock.Anything, "lib1").Return(books, nil)
+	svc.audiobookshelf = mockABS
+
+	start := time.Now()
+	require.NoError(t, svc.Sync(context.Background()), "reaching the maximum duration ends the run cleanly")
+	assert.Less(t, time.Since(start), 2*time.Second)
+
+	assert.True(t, svc.runTimedOut())
+	assert.Less(t, lookups, len(books), "the run stops before processing every book")
+	mockABS.AssertNotCalled(t, "GetLibraryItems", mock.Anything, "lib2")
+
+	// The state is saved, but not as a completed sync, so the next run fetches everything again
+	saved, err := state.LoadState(svc.statePath)
+	require.NoError(t, err)
+	_, ok := saved.GetLibraryState("sync")
+	assert.True(t, ok, "state is checkpointed")
+	assert.True(t, svc.incrementalSince().IsZero(), "the next run continues with all items")
+}
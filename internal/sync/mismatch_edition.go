@@ -0,0 +1,215 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/edition"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/mismatch"
+)
+
+var (
+	// ErrMismatchNotFound is returned for mismatches the last sync run didn't record
+	ErrMismatchNotFound = errors.New("mismatch not found")
+	// ErrMismatchWithoutBook is returned when creating the edition of a mismatch whose book isn't
+	// known in Hardcover, as editions can only be added to existing books
+	ErrMismatchWithoutBook = errors.New("mismatch has no Hardcover book to add the edition to")
+	// ErrMismatchNotMissingEdition is returned when creating the edition of a mismatch that isn't
+	// about a missing audiobook edition, e.g. a wrong match, which another edition wouldn't fix
+	ErrMismatchNotMissingEdition = errors.New("mismatch isn't about a missing audiobook edition")
+)
+
+// HasMismatch reports whether the last sync run recorded the mismatch with the given ID
+func (s *Service) HasMismatch(id string) bool {
+	_, ok := s.summaryMismatch(id)
+	return ok
+}
+
+// CreateMismatchEdition creates the missing audiobook edition of a mismatch recorded by the last
+// sync run from its metadata, then clears the mismatch and the cached lookups that missed the book
+// so it matches on the next run. Mismatches are only kept in memory, so after a restart only those
+// of runs since are found.
+func (s *Service) CreateMismatchEdition(ctx context.Context, id string) (*edition.EditionResult, error) {
+	m, ok := s.summaryMismatch(id)
+	if !ok {
+		return nil, ErrMismatchNotFound
+	}
+	if !missingEdition(m) {
+		return nil, fmt.Errorf("%w: %s", ErrMismatchNotMissingEdition, m.Reason)
+	}
+	input, err := editionInputFromMismatch(m)
+	if err != nil {
+		return nil, err
+	}
+
+	client, ok := s.hardcover.(edition.HardcoverClient)
+	if !ok {
+		return nil, errors.New("the Hardcover client can't create editions")
+	}
+	creator := edition.NewCreator(client, s.log, s.config.Sync.DryRun, s.config.Audiobookshelf.Token)
+	creator.SetDefaults(s.config.Edition.Defaults)
+	creator.SetResolveConcurrency(s.config.Edition.ResolveConcurrency)
+	creator.SetCreateMissingPublishers(s.config.Edition.CreateMissingPublishers)
+	creator.SetFormats(s.config.Edition.Formats)
+
+	result, err := creator.CreateEdition(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create edition: %w", err)
+	}
+
+	s.log.Info("Created edition for mismatch", map[string]interface{}{
+		"mismatch_id": id,
+		"title":       m.Title,
+		"book_id":     input.BookID,
+		"edition_id":  result.EditionID,
+	})
+	if result.EditionID != 0 {
+		s.clearMismatch(id)
+		s.forgetMissedLookups(m)
+	}
+	return result, nil
+}
+
+// forgetMissedLookups removes the cached ASIN and title/author lookups of the mismatched book, which
+// found nothing or a book without the new edition, and saves the caches
+func (s *Service) forgetMissedLookups(m mismatch.BookMismatch) {
+	if m.ASIN != "" {
+		s.asinCacheMutex.Lock()
+		delete(s.asinCache, m.ASIN)
+		s.asinCacheMutex.Unlock()
+		if s.persistentCache != nil {
+			s.persistentCache.Delete(m.ASIN)
+			if err := s.persistentCache.Save(); err != nil {
+				s.log.Warn("Failed to save ASIN cache", map[string]interface{}{
+					"error": err.Error(),
+				})
+			}
+		}
+	}
+
+	if s.titleSearchCache != nil {
+		s.titleSearchCache.Delete(m.Title, m.Author)
+		if err := s.titleSearchCache.Save(); err != nil {
+			s.log.Warn("Failed to save title search cache", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+	}
+}
+
+// missingEdition reports whether the mismatch was recorded because the book has no audiobook
+// edition to sync to
+func missingEdition(m mismatch.BookMismatch) bool {
+	code := m.ReasonCode
+	if code == "" {
+		code = mismatch.ReasonCodeFor(m.Reason)
+	}
+	return code == mismatch.ReasonNoEdition || code == mismatch.ReasonNoEditions
+}
+
+// summaryMismatch returns the mismatch with the given ID from the summary of the last sync run
+func (s *Service) summaryMismatch(id string) (mismatch.BookMismatch, bool) {
+	if s.summary == nil || id == "" {
+		return mismatch.BookMismatch{}, false
+	}
+	s.summary.RLock()
+	defer s.summary.RUnlock()
+	for _, m := range s.summary.Mismatches {
+		if m.ID() == id {
+			return m, true
+		}
+	}
+	return mismatch.BookMismatch{}, false
+}
+
+// clearMismatch removes the mismatch with the given ID from the summary, the collected mismatches
+// and the mismatch report, so the next run doesn't restore it
+func (s *Service) clearMismatch(id string) {
+	s.summary.Lock()
+	for i := range s.summary.Mismatches {
+		if s.summary.Mismatches[i].ID() == id {
+			s.summary.Mismatches = append(s.summary.Mismatches[:i], s.summary.Mismatches[i+1:]...)
+			break
+		}
+	}
+	s.summary.Unlock()
+	mismatch.Remove(id)
+
+	if s.mismatchReport != nil {
+		if err := s.mismatchReport.Remove(id); err != nil {
+			s.log.Warn("Failed to remove the mismatch from the mismatch report", map[string]interface{}{
+				"id":    id,
+				"error": err.Error(),
+			})
+		}
+	}
+}
+
+// editionInputFromMismatch builds the input for the audiobook edition of a mismatched book. Author,
+// narrator and publisher names are passed along for the creator to resolve.
+func editionInputFromMismatch(m mismatch.BookMismatch) (*edition.EditionInput, error) {
+	bookID := 0
+	for _, candidate := range []string{m.HardcoverBookID, m.BookID} {
+		if candidate == "" || candidate == m.ItemID {
+			continue
+		}
+		if id, err := strconv.Atoi(candidate); err == nil && id > 0 {
+			bookID = id
+			break
+		}
+	}
+	if bookID == 0 {
+		return nil, ErrMismatchWithoutBook
+	}
+
+	imageURL := m.ImageURL
+	if imageURL == "" {
+		imageURL = m.CoverURL
+	}
+
+	// Hardcover only takes full dates
+	releaseDate := m.ReleaseDate
+	if _, err := time.Parse("2006-01-02", releaseDate); err != nil {
+		releaseDate = ""
+		if len(m.PublishedYear) == 4 {
+			releaseDate = m.PublishedYear + "-01-01"
+		}
+	}
+
+	return &edition.EditionInput{
+		BookID:        bookID,
+		Title:         m.Title,
+		Subtitle:      m.Subtitle,
+		ImageURL:      imageURL,
+		ISBN10:        m.ISBN10,
+		ISBN13:        m.ISBN13,
+		ASIN:          m.ASIN,
+		PublisherID:   m.PublisherID,
+		LanguageID:    m.LanguageID,
+		CountryID:     m.CountryID,
+		AuthorIDs:     m.AuthorIDs,
+		NarratorIDs:   m.NarratorIDs,
+		AudioLength:   m.DurationSeconds,
+		ReleaseDate:   releaseDate,
+		EditionInfo:   m.EditionInfo,
+		EditionFormat: edition.DefaultFormatKey,
+		AuthorNames:   splitPeople(m.Author),
+		NarratorNames: splitPeople(m.Narrator),
+		PublisherName: m.Publisher,
+	}, nil
+}
+
+// splitPeople splits a comma-separated list of names as Audiobookshelf reports them
+func splitPeople(names string) []string {
+	var people []string
+	for _, name := range strings.Split(names, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			people = append(people, name)
+		}
+	}
+	return people
+}
@@ -0,0 +1,142 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/mismatch"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditionInputFromMismatch(t *testing.T) {
+	input, err := editionInputFromMismatch(mismatch.BookMismatch{
+		BookID:          "li_1",
+		ItemID:          "li_1",
+		HardcoverBookID: "42",
+		Title:           "Dune",
+		Author:          "Frank Herbert",
+		Narrator:        "Scott Brick, Orlagh Cassidy",
+		Publisher:       "Macmillan Audio",
+		ASIN:            "B002V1OF70",
+		CoverURL:        "https://example.com/cover.jpg",
+		PublishedYear:   "2007",
+		DurationSeconds: 75600,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 42, input.BookID)
+	assert.Equal(t, []string{"Frank Herbert"}, input.AuthorNames)
+	assert.Equal(t, []string{"Scott Brick", "Orlagh Cassidy"}, input.NarratorNames)
+	assert.Equal(t, "Macmillan Audio", input.PublisherName)
+	assert.Equal(t, "https://example.com/cover.jpg", input.ImageURL)
+	assert.Equal(t, "2007-01-01", input.ReleaseDate)
+	assert.Equal(t, 75600, input.AudioLength)
+
+	// The book ID falls back to the item ID when the book isn't in Hardcover
+	_, err = editionInputFromMismatch(mismatch.BookMismatch{BookID: "li_2", ItemID: "li_2", Title: "Unknown"})
+	assert.ErrorIs(t, err, ErrMismatchWithoutBook)
+}
+
+func TestCreateMismatchEdition(t *testing.T) {
+	logger.Setup(logger.Config{Level: "debug"})
+	mismatch.Clear()
+	t.Cleanup(mismatch.Clear)
+
+	var created map[string]interface{}
+	client, server := hardcover.CreateTestClientWithHandler(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(req.Query, "insert_edition") {
+			created = req.Variables
+			_, _ = w.Write([]byte(`{"data":{"insert_edition":{"id":555,"errors":[]}}}`))
+			return
+		}
+		if strings.Contains(req.Query, "BookByASIN") {
+			// No edition has the ASIN yet
+			_, _ = w.Write([]byte(`{"data":{"books":[]}}`))
+			return
+		}
+		t.Errorf("unexpected query: %s", req.Query)
+	})
+	defer server.Close()
+
+	m := mismatch.BookMismatch{
+		BookID:          "42",
+		ItemID:          "li_1",
+		Title:           "Dune",
+		ASIN:            "B002V1OF70",
+		AuthorIDs:       []int{7},
+		ReleaseDate:     "2007-02-01",
+		DurationSeconds: 75600,
+		Reason:          "book exists but has no edition ID for book 42",
+	}
+	mismatch.Add(m)
+
+	// The lookups of the run that recorded the mismatch missed the edition
+	cacheDir := t.TempDir()
+	asinCache := NewPersistentASINCache(cacheDir)
+	asinCache.Set("B002V1OF70", nil)
+	titleSearchCache := NewPersistentTitleSearchCache(cacheDir, time.Hour)
+	titleSearchCache.Set("Dune", "", "")
+
+	svc := &Service{
+		hardcover:        client,
+		config:           config.DefaultConfig(),
+		log:              logger.Get(),
+		asinCache:        map[string]*models.HardcoverBook{"B002V1OF70": nil},
+		persistentCache:  asinCache,
+		titleSearchCache: titleSearchCache,
+		summary: &SyncSummary{Mismatches: []mismatch.BookMismatch{m, {
+			BookID:          "43",
+			ItemID:          "li_2",
+			HardcoverBookID: "43",
+			Title:           "Children of Dune",
+			Reason:          `Narrator mismatch: Audiobookshelf narrator "Scott Brick", Hardcover edition 430 narrator "Simon Vance"`,
+		}}},
+	}
+
+	_, err := svc.CreateMismatchEdition(context.Background(), "li_unknown")
+	assert.ErrorIs(t, err, ErrMismatchNotFound)
+
+	// Another edition doesn't fix a book that has its audiobook edition
+	_, err = svc.CreateMismatchEdition(context.Background(), "li_2")
+	assert.ErrorIs(t, err, ErrMismatchNotMissingEdition)
+	assert.True(t, svc.HasMismatch("li_2"))
+
+	require.True(t, svc.HasMismatch("li_1"))
+	result, err := svc.CreateMismatchEdition(context.Background(), "li_1")
+	require.NoError(t, err)
+	assert.Equal(t, 555, result.EditionID)
+	require.NotNil(t, created)
+	assert.EqualValues(t, 42, created["bookId"])
+
+	// The mismatch is cleared
+	assert.False(t, svc.HasMismatch("li_1"))
+	assert.Empty(t, mismatch.GetAll())
+
+	// So are the missed lookups, also for the next run
+	_, cached := svc.getASINFromCache("B002V1OF70")
+	assert.False(t, cached)
+	_, cached = svc.titleSearchCache.Get("Dune", "")
+	assert.False(t, cached)
+	nextASINCache := NewPersistentASINCache(cacheDir)
+	require.NoError(t, nextASINCache.Load())
+	_, cached = nextASINCache.Get("B002V1OF70")
+	assert.False(t, cached)
+	nextTitleSearchCache := NewPersistentTitleSearchCache(cacheDir, time.Hour)
+	require.NoError(t, nextTitleSearchCache.Load())
+	_, cached = nextTitleSearchCache.Get("Dune", "")
+	assert.False(t, cached)
+}
@@ -0,0 +1,115 @@
+package sync
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/mismatch"
+)
+
+// MismatchReport keeps the mismatches of the last saved mismatch report across runs, so a run that
+// doesn't process every item can merge them into its own report
+type MismatchReport struct {
+	reportFile string
+}
+
+// NewMismatchReport creates a new mismatch report in the cache directory
+func NewMismatchReport(cacheDir string) *MismatchReport {
+	return &MismatchReport{
+		reportFile: filepath.Join(cacheDir, "mismatch_report.json"),
+	}
+}
+
+// Load returns the mismatches of the report. A missing report file yields no mismatches.
+func (r *MismatchReport) Load() ([]mismatch.BookMismatch, error) {
+	data, err := os.ReadFile(r.reportFile)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mismatch report file: %w", err)
+	}
+
+	var mismatches []mismatch.BookMismatch
+	if err := json.Unmarshal(data, &mismatches); err != nil {
+		return nil, fmt.Errorf("failed to parse mismatch report file: %w", err)
+	}
+	return mismatches, nil
+}
+
+// Save replaces the mismatches of the report
+func (r *MismatchReport) Save(mismatches []mismatch.BookMismatch) error {
+	if err := os.MkdirAll(filepath.Dir(r.reportFile), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(mismatches, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal mismatch report: %w", err)
+	}
+
+	if err := os.WriteFile(r.reportFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write mismatch report file: %w", err)
+	}
+	return nil
+}
+
+// Remove drops the mismatch with the given ID from the report
+func (r *MismatchReport) Remove(id string) error {
+	mismatches, err := r.Load()
+	if err != nil {
+		return err
+	}
+	for i := range mismatches {
+		if mismatches[i].ID() == id {
+			return r.Save(append(mismatches[:i], mismatches[i+1:]...))
+		}
+	}
+	return nil
+}
+
+// restorePreviousMismatches adds the mismatches of the previous report to the mismatches of a run
+// that didn't process every item, e.g. an incremental run, so its report still lists the items it
+// didn't fetch. Items processed this run keep only what the run recorded for them, unless they
+// failed or timed out and will be retried.
+func (s *Service) restorePreviousMismatches(seenItems map[string]struct{}) {
+	previous, err := s.mismatchReport.Load()
+	if err != nil {
+		s.log.Warn("Failed to load the previous mismatch report, only reporting this run's mismatches", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	retried := s.retriedItems()
+	kept := make([]mismatch.BookMismatch, 0, len(previous))
+	for _, m := range previous {
+		id := m.ID()
+		if _, blocked := s.blocklist[id]; blocked {
+			continue
+		}
+		if _, seen := seenItems[id]; seen {
+			if _, retry := retried[id]; !retry {
+				continue
+			}
+		}
+		kept = append(kept, m)
+	}
+
+	if restored := mismatch.Restore(kept); restored > 0 {
+		s.log.Info("Kept the mismatches of items this run didn't process in the mismatch report", map[string]interface{}{
+			"restored": restored,
+		})
+	}
+}
+
+// saveMismatchReport keeps the mismatches of the run for the next run to merge
+func (s *Service) saveMismatchReport() {
+	if err := s.mismatchReport.Save(mismatch.GetAll()); err != nil {
+		s.log.Warn("Failed to save the mismatch report", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package sync
+
+import (
+	"path/filepath"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/mismatch"
+)
+
+// mismatchChannelBuffer is how many streamed mismatches the channel holds before new ones are dropped
+const mismatchChannelBuffer = 100
+
+// Mismatches returns the channel mismatches are emitted on as they're recorded. It's nil unless
+// Sync.StreamMismatches is enabled.
+func (s *Service) Mismatches() <-chan mismatch.BookMismatch {
+	return s.mismatchCh
+}
+
+// startMismatchStream starts streaming the mismatches of a run to the stream file when
+// Sync.StreamMismatches is enabled. The returned function stops streaming.
+func (s *Service) startMismatchStream() func() {
+	if !s.config.Sync.StreamMismatches || s.config.Paths.MismatchOutputDir == "" {
+		return func() {}
+	}
+
+	path := filepath.Join(s.config.Paths.MismatchOutputDir, mismatch.StreamFileName)
+	stream, err := mismatch.OpenStream(path, s.mismatchCh)
+	if err != nil {
+		s.log.Warn("Failed to open mismatch stream, mismatches will only be saved at the end of the sync", map[string]interface{}{
+			"path":  path,
+			"error": err.Error(),
+		})
+		return func() {}
+	}
+	mismatch.SetStream(stream)
+	s.log.Info("Streaming mismatches as they're recorded", map[string]interface{}{
+		"path": path,
+	})
+
+	return func() {
+		mismatch.SetStream(nil)
+		if err := stream.Close(); err != nil {
+			s.log.Warn("Failed to close mismatch stream", map[string]interface{}{
+				"path":  path,
+				"error": err.Error(),
+			})
+		}
+	}
+}
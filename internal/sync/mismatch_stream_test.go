@@ -0,0 +1,50 @@
+package sync
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/mismatch"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartMismatchStream(t *testing.T) {
+	mismatch.Clear()
+	defer mismatch.Clear()
+
+	svc, _ := createTestService()
+	svc.config.Paths.MismatchOutputDir = t.TempDir()
+	svc.config.Sync.StreamMismatches = true
+	svc.mismatchCh = make(chan mismatch.BookMismatch, mismatchChannelBuffer)
+
+	book := models.AudiobookshelfBook{ID: "abs-book"}
+	book.Media.Metadata.Title = "Streamed Book"
+	path := filepath.Join(svc.config.Paths.MismatchOutputDir, mismatch.StreamFileName)
+
+	stop := svc.startMismatchStream()
+	svc.recordIdentifierMismatch(book, nil, errors.New("identifier mismatch on matched edition: edition 1 has no ASIN, expected B0WANTED01"))
+
+	// The mismatch is on disk and emitted before the sync ends
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(data), "\n"))
+	assert.Contains(t, string(data), `"book_id":"abs-book"`)
+	select {
+	case streamed := <-svc.Mismatches():
+		assert.Equal(t, "abs-book", streamed.BookID)
+	default:
+		t.Fatal("mismatch wasn't emitted on the channel")
+	}
+
+	// Mismatches recorded after the run aren't streamed
+	stop()
+	svc.recordIdentifierMismatch(book, nil, errors.New("identifier mismatch on matched edition: edition 1 has no ASIN, expected B0WANTED01"))
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(data), "\n"))
+}
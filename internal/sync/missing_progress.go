@@ -0,0 +1,36 @@
+package sync
+
+import (
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// missingProgress reports whether the user has no progress at all for the book: no media progress
+// or listening session in the /api/me response and no progress on the library item itself. It is
+// false when the progress data couldn't be fetched, since then nothing is known about the book.
+func missingProgress(book models.AudiobookshelfBook, userProgress *models.AudiobookshelfUserProgress) bool {
+	if userProgress == nil {
+		return false
+	}
+	if book.Progress.CurrentTime > 0 || book.Progress.IsFinished {
+		return false
+	}
+
+	for _, progress := range userProgress.MediaProgress {
+		if progress.LibraryItemID == book.ID {
+			return false
+		}
+	}
+	for _, session := range userProgress.ListeningSessions {
+		if session.LibraryItemID == book.ID {
+			return false
+		}
+	}
+	return true
+}
+
+// skipMissingProgress reports whether the book should be skipped because it has no progress data
+// and Sync.MissingProgressPolicy is "skip"
+func (s *Service) skipMissingProgress(book models.AudiobookshelfBook, userProgress *models.AudiobookshelfUserProgress) bool {
+	return s.config.Sync.MissingProgressPolicy == config.MissingProgressSkip && missingProgress(book, userProgress)
+}
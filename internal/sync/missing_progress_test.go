@@ -0,0 +1,57 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessBook_MissingProgressPolicy(t *testing.T) {
+	// Progress data exists, but only for another item
+	userProgress := &models.AudiobookshelfUserProgress{}
+	require.NoError(t, json.Unmarshal([]byte(`{"mediaProgress":[{"libraryItemId":"other-book","currentTime":120}]}`), userProgress))
+
+	book := newTestBook("unstarted-book", "Unstarted Audiobook")
+	book.Progress.CurrentTime = 0
+
+	t.Run("skip", func(t *testing.T) {
+		svc, mockClient := createTestService()
+		svc.summary = &SyncSummary{}
+		svc.config.Sync.MissingProgressPolicy = config.MissingProgressSkip
+
+		require.NoError(t, svc.processBook(context.Background(), book, userProgress))
+		assert.Empty(t, lookedUpBooks(svc.summary), "book without progress data should be skipped")
+		assert.Zero(t, svc.summary.BooksSynced)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("want_to_read", func(t *testing.T) {
+		svc, mockClient := createTestService()
+		svc.summary = &SyncSummary{}
+		svc.config.Sync.MissingProgressPolicy = config.MissingProgressWantToRead
+
+		require.NoError(t, svc.processBook(context.Background(), book, userProgress))
+		assert.Equal(t, []string{"unstarted-book"}, lookedUpBooks(svc.summary), "book without progress data should be processed")
+		mockClient.AssertExpectations(t)
+	})
+}
+
+func TestMissingProgress(t *testing.T) {
+	book := models.AudiobookshelfBook{ID: "book-1"}
+
+	userProgress := &models.AudiobookshelfUserProgress{}
+	assert.True(t, missingProgress(book, userProgress))
+	assert.False(t, missingProgress(book, nil), "unknown when progress data couldn't be fetched")
+
+	require.NoError(t, json.Unmarshal([]byte(`{"listeningSessions":[{"libraryItemId":"book-1","currentTime":60}]}`), userProgress))
+	assert.False(t, missingProgress(book, userProgress))
+
+	started := book
+	started.Progress.CurrentTime = 30
+	assert.False(t, missingProgress(started, &models.AudiobookshelfUserProgress{}))
+}
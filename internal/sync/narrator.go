@@ -0,0 +1,148 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/mismatch"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/sync/state"
+)
+
+// splitNarratorNames splits an Audiobookshelf narrator string (e.g. "Jane Doe, John Smith & Foo Bar")
+// into normalized individual names
+func splitNarratorNames(narrators string) []string {
+	replacer := strings.NewReplacer("&", ",", ";", ",", " and ", ",")
+	parts := strings.Split(replacer.Replace(narrators), ",")
+
+	names := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if name := normalizeTitle(strings.ToLower(part)); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// narratorsMatch reports whether the Audiobookshelf narrator string shares at least one
+// narrator with the Hardcover edition. Missing data on either side is treated as a match,
+// since there is nothing to compare against.
+func narratorsMatch(absNarrators string, hcNarrators []models.Author) bool {
+	absNames := splitNarratorNames(absNarrators)
+	if len(absNames) == 0 || len(hcNarrators) == 0 {
+		return true
+	}
+
+	return narratorScore(absNames, hcNarrators) > 0
+}
+
+// narratorScore counts the normalized Audiobookshelf narrator names credited on a Hardcover edition
+func narratorScore(absNames []string, hcNarrators []models.Author) int {
+	score := 0
+	for _, absName := range absNames {
+		for _, hcNarrator := range hcNarrators {
+			hcName := normalizeTitle(strings.ToLower(hcNarrator.Name))
+			if hcName == "" {
+				continue
+			}
+			// Allow containment to tolerate middle initials and suffixes
+			if absName == hcName || strings.Contains(absName, hcName) || strings.Contains(hcName, absName) {
+				score++
+				break
+			}
+		}
+	}
+	return score
+}
+
+// preferNarratorEdition switches a book matched by ASIN or ISBN to another audio edition of the
+// same Hardcover book when that edition credits more of the Audiobookshelf narrators, so progress
+// is tied to the narration actually listened to. The matched edition is kept on ties.
+func (s *Service) preferNarratorEdition(ctx context.Context, book models.AudiobookshelfBook, hcBook *models.HardcoverBook) {
+	if !s.config.Sync.MatchNarrator || s.config.Sync.StrictIdentifierMatch {
+		return
+	}
+	if hcBook == nil || hcBook.ID == "" || hcBook.EditionID == "" || s.editionFormat(ctx, book) != "audiobook" {
+		return
+	}
+	absNames := splitNarratorNames(book.Media.Metadata.NarratorName)
+	if len(absNames) == 0 {
+		return
+	}
+
+	editions, err := s.hardcover.GetAudioEditionNarrators(ctx, hcBook.ID)
+	if err != nil {
+		s.log.Warn("Failed to get audio editions for narrator matching, keeping the matched edition", map[string]interface{}{
+			"book_id":    hcBook.ID,
+			"edition_id": hcBook.EditionID,
+			"error":      err.Error(),
+		})
+		return
+	}
+
+	currentScore := narratorScore(absNames, hcBook.Narrators)
+	var best *hardcover.EditionNarrators
+	bestScore := 0
+	for i := range editions {
+		score := narratorScore(absNames, editions[i].Narrators)
+		if strconv.Itoa(editions[i].EditionID) == hcBook.EditionID {
+			currentScore = score
+			continue
+		}
+		if score > bestScore {
+			best, bestScore = &editions[i], score
+		}
+	}
+	if best == nil || bestScore <= currentScore {
+		return
+	}
+
+	s.log.Info("Preferring the audio edition narrated by the Audiobookshelf narrator", map[string]interface{}{
+		"book_id":          hcBook.ID,
+		"title":            book.Media.Metadata.Title,
+		"abs_narrator":     book.Media.Metadata.NarratorName,
+		"matched_edition":  hcBook.EditionID,
+		"narrator_edition": best.EditionID,
+	})
+	hcBook.EditionID = strconv.Itoa(best.EditionID)
+	hcBook.EditionASIN = best.ASIN
+	hcBook.EditionISBN13 = best.ISBN13
+	hcBook.EditionISBN10 = best.ISBN10
+	hcBook.Narrators = best.Narrators
+}
+
+// checkNarratorMismatch records a "narrator mismatch" when the matched Hardcover edition lists
+// narrators that don't overlap with the Audiobookshelf narrators. This usually indicates that the
+// ASIN resolved to a different regional edition, so only books matched by ASIN are checked.
+// Returns true if a mismatch was recorded.
+func (s *Service) checkNarratorMismatch(book models.AudiobookshelfBook, hcBook *models.HardcoverBook, match bookMatch) bool {
+	if !s.config.Sync.VerifyNarrator || hcBook == nil || match.Source != state.MatchSourceASIN {
+		return false
+	}
+
+	if narratorsMatch(book.Media.Metadata.NarratorName, hcBook.Narrators) {
+		return false
+	}
+
+	hcNames := make([]string, 0, len(hcBook.Narrators))
+	for _, narrator := range hcBook.Narrators {
+		hcNames = append(hcNames, narrator.Name)
+	}
+	hcNarrator := strings.Join(hcNames, ", ")
+
+	s.log.Warn("Narrator mismatch between Audiobookshelf and matched Hardcover edition", map[string]interface{}{
+		"book_id":            book.ID,
+		"title":              book.Media.Metadata.Title,
+		"abs_narrator":       book.Media.Metadata.NarratorName,
+		"hardcover_narrator": hcNarrator,
+		"hardcover_book_id":  hcBook.ID,
+		"hardcover_edition":  hcBook.EditionID,
+	})
+
+	mismatch.Add(s.bookMismatch(book, hcBook, fmt.Sprintf("Narrator mismatch: Audiobookshelf narrator %q, Hardcover edition %s narrator %q", book.Media.Metadata.NarratorName, hcBook.EditionID, hcNarrator)))
+
+	return true
+}
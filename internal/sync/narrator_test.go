@@ -0,0 +1,192 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/mismatch"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/sync/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNarratorsMatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		absNarrator string
+		hcNarrators []models.Author
+		expected    bool
+	}{
+		{
+			name:        "same narrator",
+			absNarrator: "Ray Porter",
+			hcNarrators: []models.Author{{Name: "Ray Porter"}},
+			expected:    true,
+		},
+		{
+			name:        "case and punctuation differences",
+			absNarrator: "R.C. Bray",
+			hcNarrators: []models.Author{{Name: "rc bray"}},
+			expected:    true,
+		},
+		{
+			name:        "one of multiple narrators overlaps",
+			absNarrator: "Jane Doe, John Smith & Foo Bar",
+			hcNarrators: []models.Author{{Name: "Foo Bar"}},
+			expected:    true,
+		},
+		{
+			name:        "different narrators",
+			absNarrator: "Ray Porter",
+			hcNarrators: []models.Author{{Name: "Stephen Fry"}},
+			expected:    false,
+		},
+		{
+			name:        "no ABS narrator",
+			absNarrator: "",
+			hcNarrators: []models.Author{{Name: "Stephen Fry"}},
+			expected:    true,
+		},
+		{
+			name:        "no Hardcover narrators",
+			absNarrator: "Ray Porter",
+			hcNarrators: nil,
+			expected:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, narratorsMatch(tt.absNarrator, tt.hcNarrators))
+		})
+	}
+}
+
+func TestCheckNarratorMismatch(t *testing.T) {
+	newBook := func(narrator string) models.AudiobookshelfBook {
+		book := models.AudiobookshelfBook{ID: "abs-narrator-1"}
+		book.Media.Metadata.Title = "Project Hail Mary"
+		book.Media.Metadata.AuthorName = "Andy Weir"
+		book.Media.Metadata.NarratorName = narrator
+		book.Media.Metadata.ASIN = "B08G9PRS1K"
+		return book
+	}
+	hcBook := &models.HardcoverBook{
+		ID:        "123",
+		EditionID: "456",
+		Narrators: []models.Author{{ID: "1", Name: "Ray Porter"}},
+	}
+	asinMatch := bookMatch{Source: state.MatchSourceASIN, Score: 1}
+
+	t.Run("matching narrators records nothing", func(t *testing.T) {
+		mismatch.Clear()
+		svc, _ := createTestService()
+		svc.config.Sync.VerifyNarrator = true
+
+		assert.False(t, svc.checkNarratorMismatch(newBook("Ray Porter"), hcBook, asinMatch))
+		assert.Empty(t, mismatch.GetAll())
+	})
+
+	t.Run("mismatching narrators records a mismatch", func(t *testing.T) {
+		mismatch.Clear()
+		svc, _ := createTestService()
+		svc.config.Sync.VerifyNarrator = true
+
+		assert.True(t, svc.checkNarratorMismatch(newBook("Stephen Fry"), hcBook, asinMatch))
+		all := mismatch.GetAll()
+		require.Len(t, all, 1)
+		assert.Equal(t, "abs-narrator-1", all[0].BookID)
+		assert.Equal(t, "abs-narrator-1", all[0].ItemID)
+		assert.Equal(t, "123", all[0].HardcoverBookID)
+		assert.Contains(t, all[0].Reason, "Narrator mismatch")
+
+		// Checking the book again doesn't add another mismatch
+		assert.True(t, svc.checkNarratorMismatch(newBook("Stephen Fry"), hcBook, asinMatch))
+		assert.Len(t, mismatch.GetAll(), 1)
+		mismatch.Clear()
+	})
+
+	t.Run("books not matched by ASIN record nothing", func(t *testing.T) {
+		mismatch.Clear()
+		svc, _ := createTestService()
+		svc.config.Sync.VerifyNarrator = true
+
+		titleMatch := bookMatch{Source: state.MatchSourceTitleAuthor, Score: 0.9}
+		assert.False(t, svc.checkNarratorMismatch(newBook("Stephen Fry"), hcBook, titleMatch))
+		assert.Empty(t, mismatch.GetAll())
+	})
+
+	t.Run("disabled verification records nothing", func(t *testing.T) {
+		mismatch.Clear()
+		svc, _ := createTestService()
+		svc.config.Sync.VerifyNarrator = false
+
+		assert.False(t, svc.checkNarratorMismatch(newBook("Stephen Fry"), hcBook, asinMatch))
+		assert.Empty(t, mismatch.GetAll())
+	})
+}
+
+func TestPreferNarratorEdition(t *testing.T) {
+	book := models.AudiobookshelfBook{ID: "abs-narrator-2"}
+	book.Media.Metadata.Title = "The Hobbit"
+	book.Media.Metadata.NarratorName = "Andy Serkis"
+	editions := []hardcover.EditionNarrators{
+		{EditionID: 456, ASIN: "B0099SNFYY", Narrators: []models.Author{{ID: "1", Name: "Rob Inglis"}}},
+		{EditionID: 789, ASIN: "B07N7CQ5VR", ISBN13: "9780008376086", Narrators: []models.Author{{ID: "2", Name: "Andy Serkis"}}},
+	}
+	newMatch := func() *models.HardcoverBook {
+		return &models.HardcoverBook{ID: "123", EditionID: "456", EditionASIN: "B0099SNFYY"}
+	}
+
+	t.Run("switches to the edition of the narrator", func(t *testing.T) {
+		svc, mockClient := createTestService()
+		svc.config.Sync.MatchNarrator = true
+		mockClient.On("GetAudioEditionNarrators", mock.Anything, "123").Return(editions, nil)
+
+		hcBook := newMatch()
+		svc.preferNarratorEdition(context.Background(), book, hcBook)
+		assert.Equal(t, "789", hcBook.EditionID)
+		assert.Equal(t, "B07N7CQ5VR", hcBook.EditionASIN)
+		assert.Equal(t, "9780008376086", hcBook.EditionISBN13)
+		assert.Equal(t, []models.Author{{ID: "2", Name: "Andy Serkis"}}, hcBook.Narrators)
+	})
+
+	t.Run("keeps the matched edition when its narrator matches", func(t *testing.T) {
+		svc, mockClient := createTestService()
+		svc.config.Sync.MatchNarrator = true
+		mockClient.On("GetAudioEditionNarrators", mock.Anything, "123").Return(editions, nil)
+
+		other := book
+		other.Media.Metadata.NarratorName = "Rob Inglis"
+		hcBook := newMatch()
+		svc.preferNarratorEdition(context.Background(), other, hcBook)
+		assert.Equal(t, "456", hcBook.EditionID)
+	})
+
+	t.Run("keeps the matched edition when the lookup fails", func(t *testing.T) {
+		svc, mockClient := createTestService()
+		svc.config.Sync.MatchNarrator = true
+		mockClient.On("GetAudioEditionNarrators", mock.Anything, "123").Return(nil, errors.New("boom"))
+
+		hcBook := newMatch()
+		svc.preferNarratorEdition(context.Background(), book, hcBook)
+		assert.Equal(t, "456", hcBook.EditionID)
+	})
+
+	t.Run("disabled or strict matching leaves the edition alone", func(t *testing.T) {
+		svc, mockClient := createTestService()
+		hcBook := newMatch()
+		svc.preferNarratorEdition(context.Background(), book, hcBook)
+		assert.Equal(t, "456", hcBook.EditionID)
+
+		svc.config.Sync.MatchNarrator = true
+		svc.config.Sync.StrictIdentifierMatch = true
+		svc.preferNarratorEdition(context.Background(), book, hcBook)
+		assert.Equal(t, "456", hcBook.EditionID)
+		mockClient.AssertNotCalled(t, "GetAudioEditionNarrators", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,53 @@
+package sync
+
+import (
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// inNotFoundGracePeriod reports whether a book that couldn't be found in Hardcover was first seen
+// less than Sync.NotFoundGracePeriod ago, in which case it is retried on later runs instead of
+// being reported. The first-seen time is kept in the sync state so the window survives restarts.
+func (s *Service) inNotFoundGracePeriod(book models.AudiobookshelfBook) bool {
+	grace := s.config.Sync.NotFoundGracePeriod
+	if grace <= 0 || s.state == nil {
+		return false
+	}
+
+	now := time.Now()
+	firstSeen := time.Unix(s.state.MarkFirstSeen(book.ID, now), 0)
+	if now.Sub(firstSeen) >= grace {
+		return false
+	}
+
+	s.log.Debug("Book not found in Hardcover yet, retrying on later runs before reporting it", map[string]interface{}{
+		"book_id":    book.ID,
+		"title":      book.Media.Metadata.Title,
+		"first_seen": firstSeen.Format(time.RFC3339),
+		"report_at":  firstSeen.Add(grace).Format(time.RFC3339),
+	})
+	return true
+}
+
+// notFoundRetriesPending reports whether any book is still within its Sync.NotFoundGracePeriod.
+// Incremental runs then fetch all items, since a book waiting to appear in Hardcover is usually
+// unchanged in Audiobookshelf and wouldn't be retried otherwise.
+func (s *Service) notFoundRetriesPending() bool {
+	grace := s.config.Sync.NotFoundGracePeriod
+	if grace <= 0 || s.state == nil {
+		return false
+	}
+	return s.state.HasFirstSeenAfter(time.Now().Add(-grace))
+}
+
+// awaitingHardcover reports whether the book is within its Sync.NotFoundGracePeriod, so it's
+// retried even when unchanged since the last sync
+func (s *Service) awaitingHardcover(book models.AudiobookshelfBook) bool {
+	grace := s.config.Sync.NotFoundGracePeriod
+	if grace <= 0 || s.state == nil {
+		return false
+	}
+	firstSeen, exists := s.state.FirstSeenAt(book.ID)
+	return exists && time.Since(time.Unix(firstSeen, 0)) < grace
+}
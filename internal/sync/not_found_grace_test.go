@@ -0,0 +1,102 @@
+package sync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/audiobookshelf"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessBook_NotFoundGracePeriod(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.summary = &SyncSummary{}
+	svc.config.Sync.NotFoundGracePeriod = 24 * time.Hour
+
+	// Without identifiers or an author there is nothing to search Hardcover by, so the book isn't found
+	book := models.AudiobookshelfBook{ID: "new-release", LibraryID: "lib1", MediaType: "book"}
+	book.Media.Metadata.Title = "Brand New Audiobook"
+	book.Media.Duration = 3600
+	book.Progress.CurrentTime = 600
+
+	// Within the grace period the book is retried silently
+	require.NoError(t, svc.processBook(context.Background(), book, nil))
+	assert.Empty(t, svc.summary.BooksNotFound)
+	firstSeen, ok := svc.state.FirstSeen[book.ID]
+	require.True(t, ok, "first-seen time should be tracked in state")
+
+	// Later runs within the window keep the original first-seen time
+	require.NoError(t, svc.processBook(context.Background(), book, nil))
+	assert.Empty(t, svc.summary.BooksNotFound)
+	assert.Equal(t, firstSeen, svc.state.FirstSeen[book.ID])
+
+	// Once the window has passed the book is reported
+	svc.state.FirstSeen[book.ID] = time.Now().Add(-25 * time.Hour).Unix()
+	require.NoError(t, svc.processBook(context.Background(), book, nil))
+	require.Len(t, svc.summary.BooksNotFound, 1)
+	assert.Equal(t, book.ID, svc.summary.BooksNotFound[0].BookID)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestProcessBook_NotFoundWithoutGracePeriod(t *testing.T) {
+	svc, _ := createTestService()
+	svc.summary = &SyncSummary{}
+
+	book := models.AudiobookshelfBook{ID: "new-release", LibraryID: "lib1", MediaType: "book"}
+	book.Media.Metadata.Title = "Brand New Audiobook"
+	book.Media.Duration = 3600
+	book.Progress.CurrentTime = 600
+
+	require.NoError(t, svc.processBook(context.Background(), book, nil))
+	assert.Len(t, svc.summary.BooksNotFound, 1)
+	assert.Empty(t, svc.state.FirstSeen)
+}
+
+func TestProcessLibrary_NotFoundGracePeriodWithIncrementalSync(t *testing.T) {
+	lastSync := time.Now().Add(-time.Hour)
+	before := lastSync.Add(-time.Minute).UnixMilli()
+
+	// The book was added before the last sync and hasn't changed since, but wasn't on Hardcover yet
+	book := models.AudiobookshelfBook{ID: "new-release", LibraryID: "lib1", MediaType: "book", UpdatedAt: before}
+	book.Media.Metadata.Title = "Brand New Audiobook"
+	book.Media.Metadata.ASIN = "B0NEWBOOK1"
+	book.Media.Duration = 3600
+	book.Progress.CurrentTime = 600
+	book.Progress.LastUpdate = before
+
+	svc, mockClient := createTestService()
+	svc.summary = &SyncSummary{}
+	svc.persistentCache = NewPersistentASINCache(t.TempDir())
+	svc.config.Sync.NotFoundGracePeriod = 24 * time.Hour
+	svc.config.Sync.Incremental = true
+	svc.config.Sync.SkipUnchangedBooks = true
+	svc.updatedSince = lastSync
+	svc.state.MarkFirstSeen(book.ID, lastSync.Add(-time.Hour))
+
+	// Only a full fetch returns the unchanged book
+	absClient := &MockAudiobookshelfClient{}
+	svc.audiobookshelf = absClient
+	absClient.On("GetLibraryItems", mock.Anything, "lib1").Return([]models.AudiobookshelfBook{book}, nil).Once()
+	mockClient.On("SearchBookByASIN", mock.Anything, "B0NEWBOOK1").Return(nil, nil).Once()
+	mockClient.On("SearchBookByASINAnyFormat", mock.Anything, "B0NEWBOOK1").Return(nil, nil).Maybe()
+
+	_, err := svc.processLibrary(context.Background(), &audiobookshelf.AudiobookshelfLibrary{ID: "lib1", Name: "Audiobooks"}, 0, nil)
+	require.NoError(t, err)
+
+	absClient.AssertExpectations(t)
+	absClient.AssertNotCalled(t, "GetLibraryItemsUpdatedSince", mock.Anything, mock.Anything, mock.Anything)
+	mockClient.AssertExpectations(t)
+	assert.Empty(t, svc.summary.BooksNotFound, "the book is still within its grace period")
+
+	// Once no book is waiting for Hardcover anymore, only updated items are fetched again
+	svc.state.ClearFirstSeen(book.ID)
+	absClient.On("GetLibraryItemsUpdatedSince", mock.Anything, "lib1", lastSync).Return([]models.AudiobookshelfBook{}, nil).Once()
+	_, err = svc.processLibrary(context.Background(), &audiobookshelf.AudiobookshelfLibrary{ID: "lib1", Name: "Audiobooks"}, 0, nil)
+	require.NoError(t, err)
+	absClient.AssertExpectations(t)
+}
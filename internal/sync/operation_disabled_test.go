@@ -0,0 +1,49 @@
+package sync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/audiobookshelf"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessLibraryItems_OperationDisabled(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.summary = &SyncSummary{}
+	svc.persistentCache = NewPersistentASINCache(t.TempDir())
+	svc.userBookCache = NewPersistentUserBookCache(t.TempDir())
+	svc.config.Sync.SyncOwned = false
+
+	book := newTestBook("abs-reading", "Reading Audiobook")
+	book.Media.Metadata.ASIN = "B000000001"
+
+	mockClient.On("SearchBookByASIN", mock.Anything, "B000000001").
+		Return(&models.HardcoverBook{ID: "10", EditionID: "100", EditionASIN: "B000000001"}, nil)
+	mockClient.On("GetUserBookID", mock.Anything, 100).Return(0, nil)
+	mockClient.On("CreateUserBook", mock.Anything, "100", mock.Anything).
+		Return("", &hardcover.OperationDisabledError{Operation: "InsertUserBook"})
+
+	library := &audiobookshelf.AudiobookshelfLibrary{ID: "lib1", Name: "Audiobooks"}
+	processed, err := svc.processLibraryItems(context.Background(), library, []models.AudiobookshelfBook{book}, 0, nil)
+	require.NoError(t, err)
+
+	// The book is skipped deliberately rather than failing
+	assert.Equal(t, 1, processed)
+	mockClient.AssertExpectations(t)
+}
+
+func TestMarkOwned_OperationDisabled(t *testing.T) {
+	svc, mockClient := createTestService()
+
+	mockClient.On("CheckBookOwnership", mock.Anything, 10).Return(false, nil).Once()
+	mockClient.On("MarkEditionAsOwned", mock.Anything, 100).
+		Return(&hardcover.OperationDisabledError{Operation: "EditionOwned"}).Once()
+
+	svc.markOwned(context.Background(), &models.HardcoverBook{ID: "10", EditionID: "100"}, svc.log)
+	mockClient.AssertExpectations(t)
+}
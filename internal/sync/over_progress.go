@@ -0,0 +1,48 @@
+package sync
+
+import (
+	"fmt"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/mismatch"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// clampOverProgress handles a listening position past the end of the book. Within
+// Sync.OverProgressTolerance of the duration it's rounding, and the position is clamped to the
+// duration so no progress past the end is written. Further past it the duration can't be trusted,
+// so the book is recorded as a mismatch and false is returned to skip it.
+func (s *Service) clampOverProgress(book *models.AudiobookshelfBook, log *logger.Logger) bool {
+	duration := book.Media.Duration
+	if duration <= 0 || book.Progress.CurrentTime <= duration {
+		return true
+	}
+
+	progress := book.Progress.CurrentTime / duration
+	if progress <= 1+s.config.Sync.OverProgressTolerance {
+		log.Debug("Clamping progress past the end of the book to its duration", map[string]interface{}{
+			"current_time": book.Progress.CurrentTime,
+			"duration":     duration,
+		})
+		book.Progress.CurrentTime = duration
+		return true
+	}
+
+	log.Warn("Progress is too far past the end of the book, not syncing it", map[string]interface{}{
+		"current_time":            book.Progress.CurrentTime,
+		"duration":                duration,
+		"progress":                progress,
+		"over_progress_tolerance": s.config.Sync.OverProgressTolerance,
+	})
+	s.recordOverProgress(*book, progress)
+	return false
+}
+
+// recordOverProgress records a "progress exceeds duration" mismatch for a book whose listening
+// position is beyond the tolerance past its duration, which usually means the item's duration is
+// wrong
+func (s *Service) recordOverProgress(book models.AudiobookshelfBook, progress float64) {
+	reason := fmt.Sprintf("Progress exceeds duration: Audiobookshelf progress %s is %.0f%% of the duration %s",
+		formatProgressSeconds(book.Progress.CurrentTime), progress*100, formatProgressSeconds(book.Media.Duration))
+	mismatch.Add(s.bookMismatch(book, nil, reason))
+}
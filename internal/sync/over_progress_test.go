@@ -0,0 +1,71 @@
+package sync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/mismatch"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClampOverProgress(t *testing.T) {
+	tests := []struct {
+		name        string
+		progress    float64
+		tolerance   float64
+		wantSync    bool
+		wantCurrent float64
+	}{
+		{"within duration", 0.5, 0.02, true, 1800},
+		{"101% is clamped", 1.01, 0.02, true, 3600},
+		{"150% is flagged", 1.5, 0.02, false, 5400},
+		{"101% is flagged without tolerance", 1.01, 0, false, 3636},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := createTestService()
+			svc.config.Sync.OverProgressTolerance = tt.tolerance
+			mismatch.Clear()
+			defer mismatch.Clear()
+			book := newTestBook("abs-over", "Over Progress Audiobook")
+			book.Progress.CurrentTime = book.Media.Duration * tt.progress
+
+			assert.Equal(t, tt.wantSync, svc.clampOverProgress(&book, svc.log))
+			assert.InDelta(t, tt.wantCurrent, book.Progress.CurrentTime, 0.001)
+			if tt.wantSync {
+				assert.Empty(t, mismatch.GetAll())
+			} else {
+				mismatches := mismatch.GetAll()
+				require.Len(t, mismatches, 1)
+				assert.Contains(t, mismatches[0].Reason, "Progress exceeds duration")
+			}
+		})
+	}
+}
+
+func TestProcessBook_OverProgressSkipped(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.config.Sync.SyncOwned = false
+	svc.summary = &SyncSummary{}
+	svc.editionOverrides = map[string]*models.Edition{
+		"abs-over": {ID: "420", BookID: "42", Title: "Over Progress Audiobook"},
+	}
+	mismatch.Clear()
+	defer mismatch.Clear()
+
+	mockClient.On("GetUserBookID", mock.Anything, 420).Return(77, nil).Once()
+
+	// The book is skipped before any progress is written
+	book := newTestBook("abs-over", "Over Progress Audiobook")
+	book.Progress.CurrentTime = book.Media.Duration * 1.5
+	err := svc.processBook(context.Background(), book, nil)
+	assert.ErrorIs(t, err, ErrSkippedBook)
+
+	mockClient.AssertExpectations(t)
+	assert.Equal(t, 1, svc.summary.SkipReasons[SkipReasonOverDuration])
+	assert.Len(t, mismatch.GetAll(), 1)
+}
@@ -0,0 +1,105 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// errOwnershipOnly is returned instead of a user book while Sync.OwnershipOnly is enabled, since
+// creating one would set the book's reading status
+var errOwnershipOnly = errors.New("ownership only mode, not creating a user book")
+
+// userBookSkipped reports whether findOrCreateUserBookIDForBook deliberately returned no user book
+func userBookSkipped(err error) bool {
+	return errors.Is(err, errUnownedWantToRead) || errors.Is(err, errOwnershipOnly) || errors.Is(err, errSettledInHardcover) ||
+		errors.Is(err, errLookupOnly)
+}
+
+// markOwned marks the matched edition as owned in Hardcover unless the book is already owned.
+// Failures are logged, as ownership doesn't affect the rest of the sync. Without sync_owned or
+// ownership_only, or for a book only being looked up, it sends no ownership request at all.
+func (s *Service) markOwned(ctx context.Context, hcBook *models.HardcoverBook, log *logger.Logger) {
+	if !s.config.Sync.SyncOwned && !s.config.Sync.OwnershipOnly || lookupOnly(ctx) {
+		return
+	}
+	if hcBook == nil || hcBook.EditionID == "" || hcBook.EditionID == "0" {
+		log.Debug("No edition ID available, not marking as owned", nil)
+		return
+	}
+
+	editionID, err := strconv.Atoi(hcBook.EditionID)
+	if err != nil {
+		log.Warn("Invalid edition ID format for marking as owned", map[string]interface{}{
+			"edition_id": hcBook.EditionID,
+			"error":      err.Error(),
+		})
+		return
+	}
+
+	// Check if book is already marked as owned using Hardcover BOOK ID (client queries by book_id)
+	bookID, err := strconv.Atoi(hcBook.ID)
+	if err != nil {
+		log.Warn("Invalid book ID format for ownership check", map[string]interface{}{
+			"book_id": hcBook.ID,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	// Books checked or marked earlier in the run, e.g. for another item of the same edition, aren't
+	// checked again
+	isOwned, checked := s.ownedThisRun(hcBook.ID)
+	if !checked {
+		isOwned, err = s.hardcover.CheckBookOwnership(ctx, bookID)
+		if err != nil {
+			log.Warn("Failed to check book ownership status", map[string]interface{}{
+				"book_id":    bookID,
+				"edition_id": editionID,
+				"error":      err.Error(),
+			})
+			return
+		}
+		s.setOwnedThisRun(hcBook.ID, isOwned)
+	}
+	if isOwned {
+		log.Debug("Book is already marked as owned", map[string]interface{}{
+			"book_id":    bookID,
+			"edition_id": editionID,
+		})
+		return
+	}
+
+	// Respect DryRun: only log what would happen
+	if s.dryRun(ctx) {
+		log.Info("[DRY-RUN] Would mark edition as owned", map[string]interface{}{
+			"book_id":    bookID,
+			"edition_id": editionID,
+		})
+		s.planAction(ctx, PlannedAction{Action: PlannedActionMarkOwned, EditionID: strconv.Itoa(editionID)})
+		return
+	}
+
+	if err := s.hardcover.MarkEditionAsOwned(ctx, editionID); err != nil {
+		if hardcover.IsOperationDisabled(err) {
+			log.Info("Not marking edition as owned, the operation is disabled", map[string]interface{}{
+				"edition_id": editionID,
+			})
+			return
+		}
+		log.Warn("Failed to mark edition as owned", map[string]interface{}{
+			"edition_id": editionID,
+			"error":      err.Error(),
+		})
+		return
+	}
+	s.setOwnedThisRun(hcBook.ID, true)
+	log.Info("Successfully marked edition as owned", map[string]interface{}{
+		"book_id":    bookID,
+		"edition_id": editionID,
+	})
+}
@@ -0,0 +1,185 @@
+package sync
+
+import (
+	"context"
+	stdsync "sync"
+	"testing"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Only the ownership calls are expected in these tests, so any read, status or user book mutation panics
+
+func TestProcessBook_OwnershipOnly(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.summary = &SyncSummary{}
+	svc.persistentCache = NewPersistentASINCache(t.TempDir())
+	svc.config.Sync.OwnershipOnly = true
+	svc.config.Sync.SyncOwned = false
+
+	inProgress := newTestBook("abs-reading", "Reading Audiobook")
+	inProgress.Media.Metadata.ASIN = "B000000001"
+
+	finished := models.AudiobookshelfBook{ID: "abs-finished", LibraryID: "lib1", MediaType: "book"}
+	finished.Media.Metadata.Title = "Finished Audiobook"
+	finished.Media.Metadata.ASIN = "B000000002"
+	finished.Media.Duration = 3600
+	finished.Progress.CurrentTime = 3600
+	finished.Progress.IsFinished = true
+	finished.Progress.FinishedAt = time.Now().Add(-time.Hour).UnixMilli()
+
+	mockClient.On("SearchBookByASIN", mock.Anything, "B000000001").
+		Return(&models.HardcoverBook{ID: "10", EditionID: "100", EditionASIN: "B000000001"}, nil)
+	mockClient.On("SearchBookByASIN", mock.Anything, "B000000002").
+		Return(&models.HardcoverBook{ID: "20", EditionID: "200", EditionASIN: "B000000002"}, nil)
+
+	// The finished book is already owned
+	mockClient.On("CheckBookOwnership", mock.Anything, 10).Return(false, nil).Once()
+	mockClient.On("CheckBookOwnership", mock.Anything, 20).Return(true, nil).Once()
+	mockClient.On("MarkEditionAsOwned", mock.Anything, 100).Return(nil).Once()
+
+	require.NoError(t, svc.processBook(context.Background(), inProgress, nil))
+	require.NoError(t, svc.processBook(context.Background(), finished, nil))
+
+	mockClient.AssertExpectations(t)
+	mockClient.AssertNumberOfCalls(t, "MarkEditionAsOwned", 1)
+
+	// The books are left for the next full sync
+	_, exists := svc.state.GetBookState("abs-reading:100")
+	assert.False(t, exists)
+}
+
+func TestNoOwnershipRequestsWithoutSyncOwned(t *testing.T) {
+	assertNoOwnershipRequests := func(t *testing.T, mockClient *MockHardcoverClient) {
+		mockClient.AssertNotCalled(t, "CheckBookOwnership", mock.Anything, mock.Anything)
+		mockClient.AssertNotCalled(t, "MarkEditionAsOwned", mock.Anything, mock.Anything)
+	}
+	setup := func(t *testing.T) (*Service, *MockHardcoverClient) {
+		svc, mockClient := createTestService()
+		svc.summary = &SyncSummary{}
+		svc.persistentCache = NewPersistentASINCache(t.TempDir())
+		svc.userBookCache = NewPersistentUserBookCache(t.TempDir())
+		svc.config.Sync.SyncOwned = false
+		return svc, mockClient
+	}
+
+	t.Run("markOwned", func(t *testing.T) {
+		svc, mockClient := setup(t)
+
+		svc.markOwned(context.Background(), &models.HardcoverBook{ID: "10", EditionID: "100"}, svc.log)
+		assertNoOwnershipRequests(t, mockClient)
+	})
+
+	t.Run("book in progress", func(t *testing.T) {
+		svc, mockClient := setup(t)
+		book := newTestBook("abs-reading", "Reading Audiobook")
+		book.Media.Metadata.ASIN = "B000000001"
+
+		mockClient.On("SearchBookByASIN", mock.Anything, "B000000001").
+			Return(&models.HardcoverBook{ID: "10", EditionID: "100", EditionASIN: "B000000001"}, nil)
+		mockClient.On("GetUserBookID", mock.Anything, 100).Return(0, nil)
+		mockClient.On("CreateUserBook", mock.Anything, "100", "IN_PROGRESS").
+			Return("", &hardcover.OperationDisabledError{Operation: "InsertUserBook"})
+
+		_ = svc.processBook(context.Background(), book, nil)
+		mockClient.AssertExpectations(t)
+		assertNoOwnershipRequests(t, mockClient)
+	})
+
+	t.Run("unstarted book added to Want to Read", func(t *testing.T) {
+		svc, mockClient := setup(t)
+		svc.config.Sync.SyncWantToRead = true
+		svc.config.Sync.ProcessUnreadBooks = true
+		book := models.AudiobookshelfBook{ID: "abs-unstarted", LibraryID: "lib1", MediaType: "book"}
+		book.Media.Metadata.Title = "Unstarted Audiobook"
+		book.Media.Metadata.ASIN = "B000000002"
+		book.Media.Duration = 3600
+
+		mockClient.On("SearchBookByASIN", mock.Anything, "B000000002").
+			Return(&models.HardcoverBook{ID: "20", EditionID: "200", EditionASIN: "B000000002"}, nil)
+		mockClient.On("GetUserBookID", mock.Anything, 200).Return(0, nil)
+		mockClient.On("CreateUserBook", mock.Anything, "200", "WANT_TO_READ").
+			Return("", &hardcover.OperationDisabledError{Operation: "InsertUserBook"})
+
+		_ = svc.processBook(context.Background(), book, &models.AudiobookshelfUserProgress{})
+		mockClient.AssertExpectations(t)
+		assertNoOwnershipRequests(t, mockClient)
+	})
+
+	t.Run("book tracked without edition", func(t *testing.T) {
+		svc, mockClient := setup(t)
+		svc.config.Sync.AllowBookLevelTracking = true
+		book := newTestBook("abs-1", "Audiobook Without Edition")
+		book.Media.Metadata.ASIN = "B000000001"
+		mockBookWithoutEdition(mockClient, book)
+		mockClient.On("CreateBookLevelUserBook", mock.Anything, "10", "IN_PROGRESS").Return("500", nil).Once()
+
+		require.NoError(t, svc.processBook(context.Background(), book, nil))
+		mockClient.AssertExpectations(t)
+		assertNoOwnershipRequests(t, mockClient)
+	})
+}
+
+func TestMarkOwned_SameEditionMarkedOncePerRun(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.config.Sync.SyncOwned = true
+
+	mockClient.On("CheckBookOwnership", mock.Anything, 10).Return(false, nil).Once()
+	mockClient.On("MarkEditionAsOwned", mock.Anything, 100).Return(nil).Once()
+
+	// A second Audiobookshelf item of the same edition doesn't check or mark it again
+	for i := 0; i < 2; i++ {
+		svc.markOwned(context.Background(), &models.HardcoverBook{ID: "10", EditionID: "100"}, svc.log)
+	}
+
+	mockClient.AssertExpectations(t)
+	mockClient.AssertNumberOfCalls(t, "CheckBookOwnership", 1)
+	mockClient.AssertNumberOfCalls(t, "MarkEditionAsOwned", 1)
+	owned, checked := svc.ownedThisRun("10")
+	assert.True(t, checked)
+	assert.True(t, owned)
+}
+
+func TestMarkOwned_FailedMarkCheckedAgain(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.config.Sync.SyncOwned = true
+
+	mockClient.On("CheckBookOwnership", mock.Anything, 10).Return(false, nil).Once()
+	mockClient.On("MarkEditionAsOwned", mock.Anything, 100).Return(assert.AnError).Once()
+	mockClient.On("MarkEditionAsOwned", mock.Anything, 100).Return(nil).Once()
+
+	// The book is known not to be owned, so the retry for the next item only marks it
+	for i := 0; i < 2; i++ {
+		svc.markOwned(context.Background(), &models.HardcoverBook{ID: "10", EditionID: "100"}, svc.log)
+	}
+
+	mockClient.AssertExpectations(t)
+	mockClient.AssertNumberOfCalls(t, "CheckBookOwnership", 1)
+	mockClient.AssertNumberOfCalls(t, "MarkEditionAsOwned", 2)
+}
+
+func TestMarkOwned_ConcurrentItemsOfSameEdition(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.config.Sync.SyncOwned = true
+
+	mockClient.On("CheckBookOwnership", mock.Anything, 10).Return(true, nil).Once()
+	svc.markOwned(context.Background(), &models.HardcoverBook{ID: "10", EditionID: "100"}, svc.log)
+
+	var wg stdsync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			svc.markOwned(context.Background(), &models.HardcoverBook{ID: "10", EditionID: "100"}, svc.log)
+		}()
+	}
+	wg.Wait()
+
+	mockClient.AssertNumberOfCalls(t, "CheckBookOwnership", 1)
+	mockClient.AssertNotCalled(t, "MarkEditionAsOwned", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,119 @@
+package sync
+
+import (
+	"context"
+	"errors"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/audiobookshelf"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// libraryPageSize is the number of library items fetched at once when prefetching
+const libraryPageSize = 100
+
+// fetchedPage holds a page of the items fetched for a library, or the error fetching them
+type fetchedPage struct {
+	library int
+	items   []models.AudiobookshelfBook
+	last    bool
+	err     error
+}
+
+// libraryFetcher hands out the items of a sync run's libraries in order. With
+// Sync.PrefetchLibraries set, a goroutine fetches the libraries' items in pages, up to that many
+// pages ahead of the items being processed, so fetching and processing overlap within a library as
+// well as across libraries; otherwise each library is fetched in full when it's asked for.
+type libraryFetcher struct {
+	s         *Service
+	libraries []audiobookshelf.AudiobookshelfLibrary
+	results   chan fetchedPage
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+// newLibraryFetcher creates a fetcher for the libraries and, when prefetching is enabled, starts
+// fetching them. The fetcher must be stopped once the libraries have been processed.
+func (s *Service) newLibraryFetcher(ctx context.Context, libraries []audiobookshelf.AudiobookshelfLibrary) *libraryFetcher {
+	f := &libraryFetcher{s: s, libraries: libraries}
+
+	prefetch := s.config.Sync.PrefetchLibraries
+	if prefetch <= 0 || len(libraries) == 0 {
+		return f
+	}
+
+	s.log.Debug("Prefetching library items while processing", map[string]interface{}{
+		"prefetch_pages": prefetch,
+		"page_size":      libraryPageSize,
+		"libraries":      len(libraries),
+	})
+
+	ctx, f.cancel = context.WithCancel(ctx)
+	// The goroutine holds one fetched page while waiting to hand it over, so the channel buffers
+	// one less than the number of pages fetched ahead
+	f.results = make(chan fetchedPage, prefetch-1)
+	f.done = make(chan struct{})
+	go f.run(ctx)
+	return f
+}
+
+// run fetches the pages of the libraries in order until all are fetched, the context is done or
+// authentication keeps failing
+func (f *libraryFetcher) run(ctx context.Context) {
+	defer close(f.done)
+	defer close(f.results)
+
+	for i := range f.libraries {
+		since := f.s.fetchSince(&f.libraries[i])
+		for page := 0; ; page++ {
+			items, more, err := f.s.fetchLibraryItemsPage(ctx, &f.libraries[i], since, page)
+			select {
+			case f.results <- fetchedPage{library: i, items: items, last: !more, err: err}:
+			case <-ctx.Done():
+				return
+			}
+			if errors.Is(err, ErrAuthBackoff) {
+				return
+			}
+			if err != nil || !more {
+				break
+			}
+		}
+	}
+}
+
+// next returns the next items of the i-th library and whether they are its last ones. Libraries
+// must be requested in order; pages left of earlier libraries are dropped.
+func (f *libraryFetcher) next(ctx context.Context, i int) ([]models.AudiobookshelfBook, bool, error) {
+	if f.results == nil {
+		items, err := f.s.fetchLibraryItems(ctx, &f.libraries[i])
+		return items, true, err
+	}
+
+	for {
+		select {
+		case result, ok := <-f.results:
+			if !ok {
+				if err := ctx.Err(); err != nil {
+					return nil, true, err
+				}
+				return nil, true, errors.New("library prefetching stopped")
+			}
+			// The processing of an earlier library stopped before its last page
+			if result.library < i {
+				continue
+			}
+			return result.items, result.last, result.err
+		case <-ctx.Done():
+			return nil, true, ctx.Err()
+		}
+	}
+}
+
+// stop cancels any fetches still running and waits for the prefetching goroutine to exit
+func (f *libraryFetcher) stop() {
+	if f.cancel == nil {
+		return
+	}
+	f.cancel()
+	<-f.done
+}
@@ -0,0 +1,166 @@
+package sync
+
+import (
+	"context"
+	"path/filepath"
+	stdsync "sync"
+	"testing"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/audiobookshelf"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// prefetchTestBooks returns n books of a library that are looked up by ASIN
+func prefetchTestBooks(libraryID string, n int) []models.AudiobookshelfBook {
+	var books []models.AudiobookshelfBook
+	for i := 0; i < n; i++ {
+		id := libraryID + "-book-" + string(rune('a'+i))
+		book := models.AudiobookshelfBook{ID: id, LibraryID: libraryID, MediaType: "book"}
+		book.Media.Metadata.Title = "Book " + id
+		book.Media.Metadata.ASIN = "B0" + id
+		books = append(books, book)
+	}
+	return books
+}
+
+// newPrefetchTestService returns a service set up for a full sync with prefetching enabled
+func newPrefetchTestService(t *testing.T, prefetch int) (*Service, *MockHardcoverClient, *MockAudiobookshelfClient) {
+	svc, mockClient := createTestService()
+	svc.summary = &SyncSummary{}
+	svc.statePath = filepath.Join(t.TempDir(), "state.json")
+	svc.persistentCache = NewPersistentASINCache(t.TempDir())
+	svc.config.Paths.MismatchOutputDir = t.TempDir()
+	svc.config.Sync.PrefetchLibraries = prefetch
+	mockClient.On("SearchBooks", mock.Anything, mock.Anything, mock.Anything).Return([]models.HardcoverBook{}, nil).Maybe()
+
+	mockABS := new(MockAudiobookshelfClient)
+	mockABS.On("GetUserProgress", mock.Anything).Return(&models.AudiobookshelfUserProgress{}, nil)
+	svc.audiobookshelf = mockABS
+	return svc, mockClient, mockABS
+}
+
+// onPage mocks the fetch of a page of a library's items
+func onPage(mockABS *MockAudiobookshelfClient, libraryID string, page int) *mock.Call {
+	return mockABS.On("GetLibraryItemsPage", mock.Anything, libraryID, mock.Anything, page, libraryPageSize)
+}
+
+func TestSync_PrefetchLibraries(t *testing.T) {
+	svc, mockClient, mockABS := newPrefetchTestService(t, 1)
+
+	mockABS.On("GetLibraries", mock.Anything).Return([]audiobookshelf.AudiobookshelfLibrary{
+		{ID: "lib1", Name: "Audiobooks"},
+		{ID: "lib2", Name: "More Audiobooks"},
+	}, nil)
+	onPage(mockABS, "lib1", 0).Return(prefetchTestBooks("lib1", 2), false, nil)
+
+	// The second library's fetch waits for processing to start, and the first book waits for that
+	// fetch to start, which only happens when fetching and processing overlap
+	processingStarted := make(chan struct{})
+	lib2Fetching := make(chan struct{})
+	var startOnce, fetchOnce stdsync.Once
+	overlapped := false
+	onPage(mockABS, "lib2", 0).Run(func(args mock.Arguments) {
+		fetchOnce.Do(func() { close(lib2Fetching) })
+		select {
+		case <-processingStarted:
+		case <-time.After(2 * time.Second):
+		}
+	}).Return(prefetchTestBooks("lib2", 3), false, nil)
+
+	var lookups int
+	mockClient.On("SearchBookByASINAnyFormat", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	mockClient.On("SearchBookByASIN", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		lookups++
+		startOnce.Do(func() {
+			close(processingStarted)
+			select {
+			case <-lib2Fetching:
+				overlapped = true
+			case <-time.After(2 * time.Second):
+			}
+		})
+	}).Return(nil, nil)
+
+	require.NoError(t, svc.Sync(context.Background()))
+
+	assert.True(t, overlapped, "the next library is fetched while the first is processed")
+	assert.Equal(t, 5, lookups)
+	assert.EqualValues(t, 5, svc.GetSummary().TotalBooksProcessed)
+}
+
+func TestSync_PrefetchLibraryPages(t *testing.T) {
+	svc, mockClient, mockABS := newPrefetchTestService(t, 1)
+
+	mockABS.On("GetLibraries", mock.Anything).Return([]audiobookshelf.AudiobookshelfLibrary{
+		{ID: "lib1", Name: "Audiobooks"},
+	}, nil)
+	books := prefetchTestBooks("lib1", 5)
+	onPage(mockABS, "lib1", 0).Return(books[:2], true, nil)
+
+	// Like the libraries above, the second page is fetched while the first one is processed
+	processingStarted := make(chan struct{})
+	page1Fetching := make(chan struct{})
+	var startOnce, fetchOnce stdsync.Once
+	overlapped := false
+	onPage(mockABS, "lib1", 1).Run(func(args mock.Arguments) {
+		fetchOnce.Do(func() { close(page1Fetching) })
+		select {
+		case <-processingStarted:
+		case <-time.After(2 * time.Second):
+		}
+	}).Return(books[2:4], true, nil)
+	onPage(mockABS, "lib1", 2).Return(books[4:], false, nil)
+
+	var lookups int
+	mockClient.On("SearchBookByASINAnyFormat", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	mockClient.On("SearchBookByASIN", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		lookups++
+		startOnce.Do(func() {
+			close(processingStarted)
+			select {
+			case <-page1Fetching:
+				overlapped = true
+			case <-time.After(2 * time.Second):
+			}
+		})
+	}).Return(nil, nil)
+
+	require.NoError(t, svc.Sync(context.Background()))
+
+	assert.True(t, overlapped, "the next page is fetched while the first is processed")
+	assert.Equal(t, 5, lookups)
+	assert.EqualValues(t, 5, svc.GetSummary().TotalBooksProcessed)
+	mockABS.AssertNotCalled(t, "GetLibraryItems", mock.Anything, mock.Anything)
+}
+
+func TestSync_PrefetchLibrariesTestBookLimit(t *testing.T) {
+	svc, mockClient, mockABS := newPrefetchTestService(t, 2)
+	svc.config.Sync.TestBookLimit = 3
+
+	mockABS.On("GetLibraries", mock.Anything).Return([]audiobookshelf.AudiobookshelfLibrary{
+		{ID: "lib1", Name: "Audiobooks"},
+		{ID: "lib2", Name: "More Audiobooks"},
+		{ID: "lib3", Name: "Even More Audiobooks"},
+	}, nil)
+	onPage(mockABS, "lib1", 0).Return(prefetchTestBooks("lib1", 2), false, nil)
+	books := prefetchTestBooks("lib2", 4)
+	onPage(mockABS, "lib2", 0).Return(books[:2], true, nil)
+	onPage(mockABS, "lib2", 1).Return(books[2:], false, nil).Maybe()
+	onPage(mockABS, "lib3", 0).Return(prefetchTestBooks("lib3", 2), false, nil).Maybe()
+
+	var lookups int
+	mockClient.On("SearchBookByASINAnyFormat", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	mockClient.On("SearchBookByASIN", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		lookups++
+	}).Return(nil, nil)
+
+	require.NoError(t, svc.Sync(context.Background()))
+
+	// Prefetched items don't count towards the limit until they're processed
+	assert.Equal(t, 3, lookups)
+	assert.EqualValues(t, 3, svc.GetSummary().TotalBooksProcessed)
+}
@@ -0,0 +1,140 @@
+package sync
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// defaultProgressCacheTTL is used when Sync.ProgressCacheTTL isn't set
+const defaultProgressCacheTTL = 5 * time.Minute
+
+// defaultProgressCacheRetention is used when Sync.ProgressCacheRetention isn't set
+const defaultProgressCacheRetention = 7 * 24 * time.Hour
+
+// ProgressCacheEntry is the last progress written to Hardcover for a book
+type ProgressCacheEntry struct {
+	// Progress is the progress in seconds
+	Progress  float64   `json:"progress"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// PersistentProgressCache stores the last progress written per book across restarts
+type PersistentProgressCache struct {
+	cacheFile string
+}
+
+// NewPersistentProgressCache creates a new persistent progress cache
+func NewPersistentProgressCache(cacheDir string) *PersistentProgressCache {
+	return &PersistentProgressCache{
+		cacheFile: filepath.Join(cacheDir, "progress_cache.json"),
+	}
+}
+
+// Load returns the entries that are younger than maxAge. A missing cache file yields no entries.
+func (c *PersistentProgressCache) Load(maxAge time.Duration) (map[string]ProgressCacheEntry, error) {
+	data, err := os.ReadFile(c.cacheFile)
+	if os.IsNotExist(err) {
+		return map[string]ProgressCacheEntry{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read progress cache file: %w", err)
+	}
+
+	var entries map[string]ProgressCacheEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse progress cache file: %w", err)
+	}
+
+	for key, entry := range entries {
+		if time.Since(entry.Timestamp) >= maxAge {
+			delete(entries, key)
+		}
+	}
+	if entries == nil {
+		entries = map[string]ProgressCacheEntry{}
+	}
+	return entries, nil
+}
+
+// Save writes the entries that are younger than maxAge to disk
+func (c *PersistentProgressCache) Save(entries map[string]ProgressCacheEntry, maxAge time.Duration) error {
+	if err := os.MkdirAll(filepath.Dir(c.cacheFile), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	valid := make(map[string]ProgressCacheEntry, len(entries))
+	for key, entry := range entries {
+		if time.Since(entry.Timestamp) < maxAge {
+			valid[key] = entry
+		}
+	}
+
+	data, err := json.MarshalIndent(valid, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal progress cache: %w", err)
+	}
+
+	if err := os.WriteFile(c.cacheFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write progress cache file: %w", err)
+	}
+	return nil
+}
+
+// progressCacheTTL returns how long the last progress written for a book suppresses similar writes
+func (s *Service) progressCacheTTL() time.Duration {
+	if s.config.Sync.ProgressCacheTTL > 0 {
+		return s.config.Sync.ProgressCacheTTL
+	}
+	return defaultProgressCacheTTL
+}
+
+// progressCacheRetention returns how long the persisted progress of a book is kept. It's never
+// shorter than the progress cache TTL.
+func (s *Service) progressCacheRetention() time.Duration {
+	retention := s.config.Sync.ProgressCacheRetention
+	if retention <= 0 {
+		retention = defaultProgressCacheRetention
+	}
+	if ttl := s.progressCacheTTL(); retention < ttl {
+		return ttl
+	}
+	return retention
+}
+
+// loadProgressCache fills the last progress updates from the persistent progress cache. The
+// loaded progress suppresses writing the same progress again regardless of the progress cache TTL.
+func (s *Service) loadProgressCache() error {
+	entries, err := s.progressCache.Load(s.progressCacheRetention())
+	if err != nil {
+		return err
+	}
+
+	s.lastProgressMutex.Lock()
+	defer s.lastProgressMutex.Unlock()
+	for key, entry := range entries {
+		s.lastProgressUpdates[key] = progressUpdateInfo{
+			timestamp: entry.Timestamp,
+			progress:  entry.Progress,
+			persisted: true,
+		}
+	}
+	return nil
+}
+
+// saveProgressCache writes the last progress updates to the persistent progress cache
+func (s *Service) saveProgressCache() error {
+	s.lastProgressMutex.RLock()
+	entries := make(map[string]ProgressCacheEntry, len(s.lastProgressUpdates))
+	for key, update := range s.lastProgressUpdates {
+		entries[key] = ProgressCacheEntry{
+			Progress:  update.progress,
+			Timestamp: update.timestamp,
+		}
+	}
+	s.lastProgressMutex.RUnlock()
+
+	return s.progressCache.Save(entries, s.progressCacheRetention())
+}
@@ -0,0 +1,141 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersistentProgressCache_SuppressesWriteAfterRestart(t *testing.T) {
+	cacheDir := t.TempDir()
+	userBookID := int64(123)
+	readID := int64(789)
+	editionID := int64(456)
+	hcProgress := 100
+
+	testAudiobook := createTestBook("test-book-1", "Test Book", "Test Author", "B08N5KWB9H", "9781234567890")
+	testAudiobook.Progress.CurrentTime = 300
+	testAudiobook.Media.Duration = 1000
+	audiobook := toAudiobookshelfBook(testAudiobook)
+	stateKey := fmt.Sprintf("%s:test-edition", audiobook.ID)
+
+	mockReads := func(mockClient *MockHardcoverClient, progressSeconds int) {
+		mockClient.On("GetUserBook", mock.Anything, "123").Return(&models.HardcoverBook{
+			ID:        "book-123",
+			Title:     "Test Book",
+			EditionID: "456",
+		}, nil).Once()
+		mockClient.On("GetUserBookReads", mock.Anything, hardcover.GetUserBookReadsInput{
+			UserBookID: userBookID,
+			Status:     "unfinished",
+		}).Return([]hardcover.UserBookRead{{
+			ID:              readID,
+			ProgressSeconds: &progressSeconds,
+			EditionID:       &editionID,
+		}}, nil).Once()
+	}
+
+	// First run writes the progress to Hardcover and persists it
+	svc, mockClient := createTestService()
+	svc.progressCache = NewPersistentProgressCache(cacheDir)
+	mockReads(mockClient, hcProgress)
+	mockClient.On("UpdateUserBookRead", mock.Anything, mock.MatchedBy(func(input hardcover.UpdateUserBookReadInput) bool {
+		return input.ID == readID && input.Object["progress_seconds"] == int64(300)
+	})).Return(true, nil).Once()
+	mockClient.On("UpdateUserBookStatus", mock.Anything, hardcover.UpdateUserBookStatusInput{
+		ID:       userBookID,
+		StatusID: 2,
+	}).Return(nil).Once()
+
+	require.NoError(t, svc.handleInProgressBook(context.Background(), userBookID, *audiobook, stateKey))
+	require.NoError(t, svc.saveProgressCache())
+	mockClient.AssertExpectations(t)
+
+	// After a restart Hardcover still reports the old progress, e.g. because of a stale read.
+	// The persisted cache knows the same value was just written and skips the redundant write.
+	restarted, restartedClient := createTestService()
+	restarted.progressCache = NewPersistentProgressCache(cacheDir)
+	require.NoError(t, restarted.loadProgressCache())
+	mockReads(restartedClient, hcProgress)
+
+	require.NoError(t, restarted.handleInProgressBook(context.Background(), userBookID, *audiobook, stateKey))
+	restartedClient.AssertExpectations(t)
+	restartedClient.AssertNotCalled(t, "UpdateUserBookRead", mock.Anything, mock.Anything)
+	restartedClient.AssertNotCalled(t, "UpdateUserBookStatus", mock.Anything, mock.Anything)
+}
+
+func TestPersistentProgressCache_OutlivesTTL(t *testing.T) {
+	cacheDir := t.TempDir()
+	userBookID := int64(123)
+	editionID := int64(456)
+	hcProgress := 100
+
+	testAudiobook := createTestBook("test-book-1", "Test Book", "Test Author", "B08N5KWB9H", "9781234567890")
+	testAudiobook.Progress.CurrentTime = 300
+	testAudiobook.Media.Duration = 1000
+	audiobook := toAudiobookshelfBook(testAudiobook)
+
+	// The progress was written by a run a day ago, long before the progress cache TTL, and hasn't
+	// changed since
+	require.NoError(t, NewPersistentProgressCache(cacheDir).Save(map[string]ProgressCacheEntry{
+		fmt.Sprintf("%s:%d", audiobook.ID, userBookID): {Progress: 300, Timestamp: time.Now().Add(-24 * time.Hour)},
+	}, 48*time.Hour))
+
+	svc, mockClient := createTestService()
+	svc.progressCache = NewPersistentProgressCache(cacheDir)
+	require.NoError(t, svc.loadProgressCache())
+	mockClient.On("GetUserBook", mock.Anything, "123").Return(&models.HardcoverBook{
+		ID:        "book-123",
+		Title:     "Test Book",
+		EditionID: "456",
+	}, nil).Once()
+	mockClient.On("GetUserBookReads", mock.Anything, hardcover.GetUserBookReadsInput{
+		UserBookID: userBookID,
+		Status:     "unfinished",
+	}).Return([]hardcover.UserBookRead{{
+		ID:              789,
+		ProgressSeconds: &hcProgress,
+		EditionID:       &editionID,
+	}}, nil).Once()
+
+	require.NoError(t, svc.handleInProgressBook(context.Background(), userBookID, *audiobook, audiobook.ID+":test-edition"))
+	mockClient.AssertNotCalled(t, "UpdateUserBookRead", mock.Anything, mock.Anything)
+
+	// Progress persisted before the retention is dropped
+	svc.config.Sync.ProgressCacheRetention = time.Hour
+	svc.lastProgressUpdates = make(map[string]progressUpdateInfo)
+	require.NoError(t, svc.loadProgressCache())
+	assert.Empty(t, svc.lastProgressUpdates)
+}
+
+func TestPersistentProgressCache_LoadSave(t *testing.T) {
+	cache := NewPersistentProgressCache(t.TempDir())
+
+	// A missing cache file is not an error
+	entries, err := cache.Load(time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	now := time.Now()
+	require.NoError(t, cache.Save(map[string]ProgressCacheEntry{
+		"fresh:1":   {Progress: 120, Timestamp: now},
+		"expired:2": {Progress: 60, Timestamp: now.Add(-2 * time.Minute)},
+	}, time.Minute))
+
+	entries, err = cache.Load(time.Minute)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, 120.0, entries["fresh:1"].Progress)
+
+	// Entries older than a shorter TTL are dropped on load
+	entries, err = cache.Load(time.Nanosecond)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -0,0 +1,31 @@
+package sync
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/mismatch"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// isSuspiciousProgressJump reports whether the difference between the Audiobookshelf and
+// Hardcover progress exceeds the configured cap (a cap of 0 disables the check)
+func (s *Service) isSuspiciousProgressJump(progressDiff float64) bool {
+	maxJump := s.config.Sync.MaxProgressJumpSeconds
+	return maxJump > 0 && progressDiff > float64(maxJump)
+}
+
+// recordSuspiciousProgressJump records a "suspicious progress jump" mismatch for a progress update
+// that was refused because it differs too much from the progress already stored in Hardcover.
+// This usually means the book was matched to the wrong edition.
+func (s *Service) recordSuspiciousProgressJump(book models.AudiobookshelfBook, hcBook *models.HardcoverBook, hcProgressSeconds, progressDiff float64) {
+	reason := fmt.Sprintf("Suspicious progress jump: Audiobookshelf progress %s, Hardcover progress %s (difference %s exceeds cap of %ds)",
+		formatProgressSeconds(book.Progress.CurrentTime), formatProgressSeconds(hcProgressSeconds),
+		formatProgressSeconds(progressDiff), s.config.Sync.MaxProgressJumpSeconds)
+	mismatch.Add(s.bookMismatch(book, hcBook, reason))
+}
+
+// formatProgressSeconds formats a progress value in seconds as a duration string
+func formatProgressSeconds(seconds float64) string {
+	return (time.Duration(int64(seconds)) * time.Second).String()
+}
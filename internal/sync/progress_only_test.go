@@ -0,0 +1,117 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// No UpdateUserBookStatus expectations are set in these tests, so any status mutation panics
+
+func TestHandleInProgressBook_ProgressOnlyUpdatesRead(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.config.Sync.ProgressOnly = true
+
+	testAudiobook := createTestBook("test-book-1", "Test Book", "Test Author", "B08N5KWB9H", "9781234567890")
+	testAudiobook.Progress.CurrentTime = 300
+	testAudiobook.Media.Duration = 1000
+	audiobook := toAudiobookshelfBook(testAudiobook)
+
+	userBookID := int64(123)
+	mockClient.On("GetUserBook", mock.Anything, "123").Return(&models.HardcoverBook{
+		ID:           "book-123",
+		Title:        "Test Book",
+		EditionID:    "456",
+		BookStatusID: 1, // Want to read, which would normally become currently reading
+	}, nil).Once()
+
+	readID := int64(789)
+	progressSeconds := 100
+	editionID := int64(456)
+	mockClient.On("GetUserBookReads", mock.Anything, hardcover.GetUserBookReadsInput{
+		UserBookID: userBookID,
+		Status:     "unfinished",
+	}).Return([]hardcover.UserBookRead{
+		{ID: readID, ProgressSeconds: &progressSeconds, EditionID: &editionID},
+	}, nil).Once()
+
+	mockClient.On("UpdateUserBookRead", mock.Anything, mock.MatchedBy(func(input hardcover.UpdateUserBookReadInput) bool {
+		return input.ID == readID && input.Object["progress_seconds"] == int64(300)
+	})).Return(true, nil).Once()
+
+	stateKey := fmt.Sprintf("%s:test-edition", audiobook.ID)
+	err := svc.handleInProgressBook(context.Background(), userBookID, *audiobook, stateKey)
+
+	assert.NoError(t, err)
+	mockClient.AssertExpectations(t)
+	mockClient.AssertNotCalled(t, "UpdateUserBookStatus", mock.Anything, mock.Anything)
+}
+
+func TestHandleInProgressBook_ProgressOnlyCreatesRead(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.config.Sync.ProgressOnly = true
+
+	testAudiobook := createTestBook("test-book-1", "Test Book", "Test Author", "B08N5KWB9H", "9781234567890")
+	testAudiobook.Progress.CurrentTime = 300
+	testAudiobook.Media.Duration = 1000
+	audiobook := toAudiobookshelfBook(testAudiobook)
+
+	userBookID := int64(123)
+	mockClient.On("GetUserBook", mock.Anything, "123").Return(&models.HardcoverBook{
+		ID:        "book-123",
+		Title:     "Test Book",
+		EditionID: "456",
+	}, nil).Once()
+	mockClient.On("GetUserBookReads", mock.Anything, hardcover.GetUserBookReadsInput{
+		UserBookID: userBookID,
+		Status:     "unfinished",
+	}).Return([]hardcover.UserBookRead{}, nil).Once()
+	mockClient.On("GetUserBookReads", mock.Anything, hardcover.GetUserBookReadsInput{
+		UserBookID: userBookID,
+	}).Return([]hardcover.UserBookRead{}, nil)
+	mockClient.On("CheckExistingUserBookRead", mock.Anything, mock.Anything).Return((*hardcover.CheckExistingUserBookReadResult)(nil), nil).Once()
+	mockClient.On("InsertUserBookRead", mock.Anything, mock.MatchedBy(func(input hardcover.InsertUserBookReadInput) bool {
+		return input.UserBookID == userBookID &&
+			input.DatesRead.ProgressSeconds != nil && *input.DatesRead.ProgressSeconds == 300
+	})).Return(789, nil).Once()
+
+	stateKey := fmt.Sprintf("%s:test-edition", audiobook.ID)
+	err := svc.handleInProgressBook(context.Background(), userBookID, *audiobook, stateKey)
+
+	assert.NoError(t, err)
+	mockClient.AssertExpectations(t)
+	mockClient.AssertNotCalled(t, "UpdateUserBookStatus", mock.Anything, mock.Anything)
+}
+
+func TestHandleFinishedBook_ProgressOnly(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.config = createTestConfigForTests(true)
+	svc.config.Sync.ProgressOnly = true
+
+	userBookID := int64(123)
+	mockClient.On("GetUserBook", mock.Anything, "123").Return(&models.HardcoverBook{
+		ID:           "book-123",
+		UserBookID:   "123",
+		BookStatusID: 2,
+	}, nil).Maybe()
+	mockClient.On("GetUserBookReads", mock.Anything, hardcover.GetUserBookReadsInput{
+		UserBookID: userBookID,
+	}).Return([]hardcover.UserBookRead{
+		{ID: 1, UserBookID: userBookID, StartedAt: stringPointer("2023-01-01"), ProgressSeconds: intPointer(1800)},
+	}, nil)
+	mockClient.On("UpdateUserBookRead", mock.Anything, mock.MatchedBy(func(input hardcover.UpdateUserBookReadInput) bool {
+		return input.ID == 1
+	})).Return(true, nil).Once()
+
+	book := convertTestBookToModel(createTestFinishedBook("abs-book-1", "Test Book", "Test Author", "B123456789", "9781234567890"))
+	err := svc.HandleFinishedBook(context.Background(), book, "456", userBookID)
+
+	assert.NoError(t, err)
+	mockClient.AssertExpectations(t)
+	mockClient.AssertNotCalled(t, "UpdateUserBookStatus", mock.Anything, mock.Anything)
+}
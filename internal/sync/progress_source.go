@@ -0,0 +1,97 @@
+package sync
+
+import (
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// applyUserProgress overrides the book's progress with the matching entry from the /api/me
+// response. When both media progress and a listening session exist for the book, the entry is
+// chosen according to Sync.ProgressSource, and its finished flag is reconciled with the item's own
+// progress according to Sync.FinishedSource. The entry's update time becomes the progress' update
+// time, which items fetched without progress lack. Returns the source that was used, or "" if none
+// matched.
+func (s *Service) applyUserProgress(book *models.AudiobookshelfBook, userProgress *models.AudiobookshelfUserProgress) string {
+	// The item's own progress, whose finished flag is reconciled with the chosen entry's
+	itemFinished, itemFinishedAt := book.Progress.IsFinished, book.Progress.FinishedAt
+	itemHasProgress := itemFinished || book.Progress.CurrentTime > 0 || book.Progress.LastUpdate > 0
+
+	// Find the most recent media progress entry for this book
+	mediaIdx := -1
+	for i := range userProgress.MediaProgress {
+		if userProgress.MediaProgress[i].LibraryItemID == book.ID &&
+			(mediaIdx < 0 || userProgress.MediaProgress[i].LastUpdate > userProgress.MediaProgress[mediaIdx].LastUpdate) {
+			mediaIdx = i
+		}
+	}
+
+	// Find the most recent listening session for this book
+	sessionIdx := -1
+	for i := range userProgress.ListeningSessions {
+		if userProgress.ListeningSessions[i].LibraryItemID == book.ID &&
+			(sessionIdx < 0 || userProgress.ListeningSessions[i].UpdatedAt > userProgress.ListeningSessions[sessionIdx].UpdatedAt) {
+			sessionIdx = i
+		}
+	}
+
+	var useSession bool
+	switch {
+	case mediaIdx < 0 && sessionIdx < 0:
+		return ""
+	case mediaIdx < 0:
+		useSession = true
+	case sessionIdx < 0:
+		useSession = false
+	default:
+		switch s.config.Sync.ProgressSource {
+		case config.ProgressSourceSessions:
+			useSession = true
+		case config.ProgressSourceMostRecent:
+			useSession = userProgress.ListeningSessions[sessionIdx].UpdatedAt > userProgress.MediaProgress[mediaIdx].LastUpdate
+		}
+	}
+
+	source := config.ProgressSourceMedia
+	if useSession {
+		session := userProgress.ListeningSessions[sessionIdx]
+		book.Progress.CurrentTime = session.CurrentTime
+		book.Progress.IsFinished = session.IsFinished
+		if session.UpdatedAt > 0 {
+			book.Progress.LastUpdate = session.UpdatedAt
+		}
+		source = config.ProgressSourceSessions
+	} else {
+		progress := userProgress.MediaProgress[mediaIdx]
+		book.Progress.CurrentTime = progress.CurrentTime
+		book.Progress.IsFinished = progress.IsFinished
+		book.Progress.FinishedAt = progress.FinishedAt
+		book.Progress.StartedAt = progress.StartedAt
+		if progress.LastUpdate > 0 {
+			book.Progress.LastUpdate = progress.LastUpdate
+		}
+		s.applyEbookProgress(book, progress.EbookProgress)
+	}
+
+	if itemHasProgress {
+		book.Progress.IsFinished = s.reconcileFinished(itemFinished, book.Progress.IsFinished)
+		if book.Progress.IsFinished && itemFinished && book.Progress.FinishedAt == 0 {
+			book.Progress.FinishedAt = itemFinishedAt
+		}
+	}
+	return source
+}
+
+// reconcileFinished combines the finished flags of the item's own progress and of the /api/me
+// progress entry according to Sync.FinishedSource, since the two can disagree
+func (s *Service) reconcileFinished(itemFinished, mediaProgressFinished bool) bool {
+	switch s.config.Sync.FinishedSource {
+	case config.FinishedSourceItem:
+		return itemFinished
+	case config.FinishedSourceEither:
+		return itemFinished || mediaProgressFinished
+	case config.FinishedSourceBoth:
+		return itemFinished && mediaProgressFinished
+	default:
+		return mediaProgressFinished
+	}
+}
@@ -0,0 +1,184 @@
+package sync
+
+import (
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyUserProgress(t *testing.T) {
+	// newUserProgress returns media progress and a listening session for the same book with
+	// different values and the given update times
+	newUserProgress := func(mediaUpdated, sessionUpdated int64) *models.AudiobookshelfUserProgress {
+		userProgress := &models.AudiobookshelfUserProgress{}
+		userProgress.MediaProgress = append(userProgress.MediaProgress, struct {
+			ID                        string  `json:"id"`
+			LibraryItemID             string  `json:"libraryItemId"`
+			UserID                    string  `json:"userId"`
+			IsFinished                bool    `json:"isFinished"`
+			Progress                  float64 `json:"progress"`
+			CurrentTime               float64 `json:"currentTime"`
+			Duration                  float64 `json:"duration"`
+			StartedAt                 int64   `json:"startedAt"`
+			FinishedAt                int64   `json:"finishedAt"`
+			LastUpdate                int64   `json:"lastUpdate"`
+			TimeListening             float64 `json:"timeListening"`
+			EbookProgress             float64 `json:"ebookProgress"`
+			HideFromContinueListening bool    `json:"hideFromContinueListening"`
+		}{LibraryItemID: "book-1", CurrentTime: 1000, LastUpdate: mediaUpdated})
+		userProgress.ListeningSessions = append(userProgress.ListeningSessions, struct {
+			ID            string `json:"id"`
+			UserID        string `json:"userId"`
+			LibraryItemID string `json:"libraryItemId"`
+			MediaType     string `json:"mediaType"`
+			MediaMetadata struct {
+				Title  string `json:"title"`
+				Author string `json:"author"`
+			} `json:"mediaMetadata"`
+			Duration    float64 `json:"duration"`
+			CurrentTime float64 `json:"currentTime"`
+			Progress    float64 `json:"progress"`
+			IsFinished  bool    `json:"isFinished"`
+			StartedAt   int64   `json:"startedAt"`
+			UpdatedAt   int64   `json:"updatedAt"`
+		}{LibraryItemID: "book-1", CurrentTime: 2000, UpdatedAt: sessionUpdated})
+		return userProgress
+	}
+
+	tests := []struct {
+		name           string
+		source         string
+		mediaUpdated   int64
+		sessionUpdated int64
+		expectedSource string
+		expectedTime   float64
+	}{
+		{"media preference", config.ProgressSourceMedia, 100, 200, config.ProgressSourceMedia, 1000},
+		{"sessions preference", config.ProgressSourceSessions, 200, 100, config.ProgressSourceSessions, 2000},
+		{"most recent picks session", config.ProgressSourceMostRecent, 100, 200, config.ProgressSourceSessions, 2000},
+		{"most recent picks media", config.ProgressSourceMostRecent, 200, 100, config.ProgressSourceMedia, 1000},
+		{"unset behaves like media", "", 100, 200, config.ProgressSourceMedia, 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := createTestService()
+			svc.config.Sync.ProgressSource = tt.source
+
+			book := models.AudiobookshelfBook{ID: "book-1"}
+			source := svc.applyUserProgress(&book, newUserProgress(tt.mediaUpdated, tt.sessionUpdated))
+
+			assert.Equal(t, tt.expectedSource, source)
+			assert.Equal(t, tt.expectedTime, book.Progress.CurrentTime)
+		})
+	}
+
+	t.Run("falls back when the preferred source is missing", func(t *testing.T) {
+		svc, _ := createTestService()
+		svc.config.Sync.ProgressSource = config.ProgressSourceSessions
+
+		userProgress := newUserProgress(100, 200)
+		userProgress.ListeningSessions = nil
+
+		book := models.AudiobookshelfBook{ID: "book-1"}
+		assert.Equal(t, config.ProgressSourceMedia, svc.applyUserProgress(&book, userProgress))
+		assert.Equal(t, 1000.0, book.Progress.CurrentTime)
+	})
+
+	t.Run("no entry for the book", func(t *testing.T) {
+		svc, _ := createTestService()
+
+		book := models.AudiobookshelfBook{ID: "other-book"}
+		book.Progress.CurrentTime = 42
+		assert.Equal(t, "", svc.applyUserProgress(&book, newUserProgress(100, 200)))
+		assert.Equal(t, 42.0, book.Progress.CurrentTime)
+	})
+}
+
+func TestApplyUserProgress_FinishedSource(t *testing.T) {
+	// newUserProgress returns /api/me media progress for book-1 with the given finished flag
+	newUserProgress := func(finished bool) *models.AudiobookshelfUserProgress {
+		userProgress := &models.AudiobookshelfUserProgress{}
+		userProgress.MediaProgress = append(userProgress.MediaProgress, struct {
+			ID                        string  `json:"id"`
+			LibraryItemID             string  `json:"libraryItemId"`
+			UserID                    string  `json:"userId"`
+			IsFinished                bool    `json:"isFinished"`
+			Progress                  float64 `json:"progress"`
+			CurrentTime               float64 `json:"currentTime"`
+			Duration                  float64 `json:"duration"`
+			StartedAt                 int64   `json:"startedAt"`
+			FinishedAt                int64   `json:"finishedAt"`
+			LastUpdate                int64   `json:"lastUpdate"`
+			TimeListening             float64 `json:"timeListening"`
+			EbookProgress             float64 `json:"ebookProgress"`
+			HideFromContinueListening bool    `json:"hideFromContinueListening"`
+		}{LibraryItemID: "book-1", CurrentTime: 3500, IsFinished: finished, LastUpdate: 100})
+		if finished {
+			userProgress.MediaProgress[0].FinishedAt = 2000
+		}
+		return userProgress
+	}
+	// newBook returns book-1 with its own progress carrying the given finished flag
+	newBook := func(finished bool) models.AudiobookshelfBook {
+		book := models.AudiobookshelfBook{ID: "book-1"}
+		book.Progress.CurrentTime = 3500
+		book.Progress.LastUpdate = 100
+		if finished {
+			book.Progress.IsFinished = true
+			book.Progress.FinishedAt = 1000
+		}
+		return book
+	}
+
+	tests := []struct {
+		name               string
+		source             string
+		itemFinished       bool
+		mediaFinished      bool
+		expectedFinished   bool
+		expectedFinishedAt int64
+	}{
+		{"media_progress: only item finished", config.FinishedSourceMediaProgress, true, false, false, 0},
+		{"media_progress: only media finished", config.FinishedSourceMediaProgress, false, true, true, 2000},
+		{"unset behaves like media_progress", "", true, false, false, 0},
+		{"item: only item finished", config.FinishedSourceItem, true, false, true, 1000},
+		{"item: only media finished", config.FinishedSourceItem, false, true, false, 2000},
+		{"either: only item finished", config.FinishedSourceEither, true, false, true, 1000},
+		{"either: only media finished", config.FinishedSourceEither, false, true, true, 2000},
+		{"either: neither finished", config.FinishedSourceEither, false, false, false, 0},
+		{"both: only item finished", config.FinishedSourceBoth, true, false, false, 0},
+		{"both: only media finished", config.FinishedSourceBoth, false, true, false, 2000},
+		{"both: both finished", config.FinishedSourceBoth, true, true, true, 2000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := createTestService()
+			svc.config.Sync.FinishedSource = tt.source
+
+			book := newBook(tt.itemFinished)
+			svc.applyUserProgress(&book, newUserProgress(tt.mediaFinished))
+
+			assert.Equal(t, tt.expectedFinished, book.Progress.IsFinished)
+			assert.Equal(t, tt.expectedFinishedAt, book.Progress.FinishedAt)
+			// Progress is below 100%, so only the reconciled flag decides whether the book is FINISHED
+			expectedStatus := "IN_PROGRESS"
+			if tt.expectedFinished {
+				expectedStatus = "FINISHED"
+			}
+			assert.Equal(t, expectedStatus, svc.determineBookStatus(book.Progress.CurrentTime/3600, book.Progress.IsFinished, book.Progress.FinishedAt))
+		})
+	}
+
+	t.Run("items without their own progress use the /api/me flag", func(t *testing.T) {
+		svc, _ := createTestService()
+		svc.config.Sync.FinishedSource = config.FinishedSourceBoth
+
+		book := models.AudiobookshelfBook{ID: "book-1"}
+		svc.applyUserProgress(&book, newUserProgress(true))
+		assert.True(t, book.Progress.IsFinished)
+	})
+}
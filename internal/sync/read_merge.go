@@ -0,0 +1,159 @@
+package sync
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+)
+
+// mergeOverlapRatio is how much of the shorter of two reads' date ranges has to overlap the other
+// for them to be taken as the same listening session
+const mergeOverlapRatio = 0.8
+
+// parseReadDate parses the date of a read's started_at or finished_at, which may include a time
+func parseReadDate(date *string) (time.Time, bool) {
+	if date == nil || len(*date) < 10 {
+		return time.Time{}, false
+	}
+	t, err := time.Parse("2006-01-02", (*date)[:10])
+	return t, err == nil
+}
+
+// readDays returns the days a read covers, from its start up to its finish. A read that is
+// unfinished or started and finished on the same day covers its start day only, so a reread started
+// on the day the previous read finished doesn't overlap it.
+func readDays(read hardcover.UserBookRead) (start, end time.Time, ok bool) {
+	start, ok = parseReadDate(read.StartedAt)
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+	end, finished := parseReadDate(read.FinishedAt)
+	if !finished || !end.After(start) {
+		end = start.AddDate(0, 0, 1)
+	}
+	return start, end, true
+}
+
+// readOverlap returns the share of the shorter of the two date ranges covered by both
+func readOverlap(startA, endA, startB, endB time.Time) float64 {
+	start, end := startA, endA
+	if startB.After(start) {
+		start = startB
+	}
+	if endB.Before(end) {
+		end = endB
+	}
+	if !end.After(start) {
+		return 0
+	}
+
+	shorter := endA.Sub(startA)
+	if lengthB := endB.Sub(startB); lengthB < shorter {
+		shorter = lengthB
+	}
+	return float64(end.Sub(start)) / float64(shorter)
+}
+
+// moreCompleteRead reports whether read a is more complete than read b: finished, further along,
+// or covering more days. Ties keep the older read.
+func moreCompleteRead(a, b hardcover.UserBookRead) bool {
+	aFinished := a.FinishedAt != nil && *a.FinishedAt != ""
+	bFinished := b.FinishedAt != nil && *b.FinishedAt != ""
+	if aFinished != bFinished {
+		return aFinished
+	}
+
+	aSeconds, bSeconds := 0, 0
+	if a.ProgressSeconds != nil {
+		aSeconds = *a.ProgressSeconds
+	}
+	if b.ProgressSeconds != nil {
+		bSeconds = *b.ProgressSeconds
+	}
+	if aSeconds != bSeconds {
+		return aSeconds > bSeconds
+	}
+	if a.Progress != b.Progress {
+		return a.Progress > b.Progress
+	}
+
+	aStart, aEnd, _ := readDays(a)
+	bStart, bEnd, _ := readDays(b)
+	if aDays, bDays := aEnd.Sub(aStart), bEnd.Sub(bStart); aDays != bDays {
+		return aDays > bDays
+	}
+	return a.ID < b.ID
+}
+
+// mergeOverlappingReads deletes the reads of a user book that represent the same listening session
+// as a more complete read, when Sync.MergeOverlappingReads is enabled. Reads are the same session
+// when their date ranges overlap by mergeOverlapRatio of the shorter one. The remaining reads are
+// returned in their original order; reads that fail to be deleted are kept.
+func (s *Service) mergeOverlappingReads(ctx context.Context, reads []hardcover.UserBookRead, log *logger.Logger) []hardcover.UserBookRead {
+	if !s.config.Sync.MergeOverlappingReads || len(reads) < 2 {
+		return reads
+	}
+
+	candidates := append([]hardcover.UserBookRead(nil), reads...)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return moreCompleteRead(candidates[i], candidates[j])
+	})
+
+	type keptRead struct {
+		id         int64
+		start, end time.Time
+	}
+	var kept []keptRead
+	deleted := make(map[int64]bool)
+
+	for _, read := range candidates {
+		start, end, ok := readDays(read)
+		if !ok {
+			// Without a start date the read can't be compared, so it's left alone
+			continue
+		}
+
+		var mergeInto *keptRead
+		for i := range kept {
+			if readOverlap(start, end, kept[i].start, kept[i].end) >= mergeOverlapRatio {
+				mergeInto = &kept[i]
+				break
+			}
+		}
+		if mergeInto == nil {
+			kept = append(kept, keptRead{id: read.ID, start: start, end: end})
+			continue
+		}
+
+		if err := s.hardcover.DeleteUserBookRead(ctx, read.ID); err != nil {
+			log.Warn("Failed to delete read overlapping a more complete read", map[string]interface{}{
+				"read_id":      read.ID,
+				"kept_read_id": mergeInto.id,
+				"error":        err.Error(),
+			})
+			continue
+		}
+
+		log.Info("Merged read into a more complete read of the same listening session", map[string]interface{}{
+			"read_id":      read.ID,
+			"kept_read_id": mergeInto.id,
+			"started_at":   read.StartedAt,
+			"finished_at":  read.FinishedAt,
+		})
+		deleted[read.ID] = true
+	}
+
+	if len(deleted) == 0 {
+		return reads
+	}
+	remaining := make([]hardcover.UserBookRead, 0, len(reads)-len(deleted))
+	for _, read := range reads {
+		if !deleted[read.ID] {
+			remaining = append(remaining, read)
+		}
+	}
+	return remaining
+}
@@ -0,0 +1,146 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newRead returns a read of user book 123, unfinished when finishedAt is empty
+func newRead(id int64, startedAt, finishedAt string, progressSeconds int) hardcover.UserBookRead {
+	read := hardcover.UserBookRead{
+		ID:              id,
+		UserBookID:      123,
+		StartedAt:       stringPointer(startedAt),
+		ProgressSeconds: intPointer(progressSeconds),
+	}
+	if finishedAt != "" {
+		read.FinishedAt = stringPointer(finishedAt)
+	}
+	return read
+}
+
+func TestMergeOverlappingReads(t *testing.T) {
+	tests := []struct {
+		name        string
+		reads       []hardcover.UserBookRead
+		deleteErr   error
+		wantDeleted []int64
+		wantIDs     []int64
+	}{
+		{
+			name: "duplicate finished reads keep the one with the most progress",
+			reads: []hardcover.UserBookRead{
+				newRead(1, "2025-03-01", "2025-03-10", 3000),
+				newRead(2, "2025-03-01", "2025-03-10T08:00:00Z", 3600),
+			},
+			wantDeleted: []int64{1},
+			wantIDs:     []int64{2},
+		},
+		{
+			name: "unfinished read within a finished read is merged into it",
+			reads: []hardcover.UserBookRead{
+				newRead(1, "2025-03-05", "", 1800),
+				newRead(2, "2025-03-01", "2025-03-10", 3600),
+			},
+			wantDeleted: []int64{1},
+			wantIDs:     []int64{2},
+		},
+		{
+			name: "duplicate unfinished reads started the same day keep the one furthest along",
+			reads: []hardcover.UserBookRead{
+				newRead(1, "2025-03-01", "", 1800),
+				newRead(2, "2025-03-01", "", 600),
+			},
+			wantDeleted: []int64{2},
+			wantIDs:     []int64{1},
+		},
+		{
+			name: "reread started on the day the previous read finished is kept",
+			reads: []hardcover.UserBookRead{
+				newRead(1, "2025-03-01", "2025-03-10", 3600),
+				newRead(2, "2025-03-10", "", 600),
+			},
+			wantIDs: []int64{1, 2},
+		},
+		{
+			name: "reads overlapping by a few days are kept",
+			reads: []hardcover.UserBookRead{
+				newRead(1, "2025-03-01", "2025-03-10", 3600),
+				newRead(2, "2025-03-08", "2025-03-20", 3600),
+			},
+			wantIDs: []int64{1, 2},
+		},
+		{
+			name: "reads failing to be deleted are kept",
+			reads: []hardcover.UserBookRead{
+				newRead(1, "2025-03-01", "2025-03-10", 3600),
+				newRead(2, "2025-03-01", "2025-03-10", 3600),
+			},
+			deleteErr:   errors.New("API error"),
+			wantDeleted: []int64{2},
+			wantIDs:     []int64{1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, mockClient := createTestService()
+			svc.config.Sync.MergeOverlappingReads = true
+			for _, id := range tt.wantDeleted {
+				mockClient.On("DeleteUserBookRead", mock.Anything, id).Return(tt.deleteErr).Once()
+			}
+
+			remaining := svc.mergeOverlappingReads(context.Background(), tt.reads, svc.log)
+
+			var ids []int64
+			for _, read := range remaining {
+				ids = append(ids, read.ID)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+			mockClient.AssertExpectations(t)
+			mockClient.AssertNumberOfCalls(t, "DeleteUserBookRead", len(tt.wantDeleted))
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		svc, mockClient := createTestService()
+		reads := []hardcover.UserBookRead{
+			newRead(1, "2025-03-01", "2025-03-10", 3600),
+			newRead(2, "2025-03-01", "2025-03-10", 3600),
+		}
+
+		assert.Equal(t, reads, svc.mergeOverlappingReads(context.Background(), reads, svc.log))
+		mockClient.AssertNotCalled(t, "DeleteUserBookRead", mock.Anything, mock.Anything)
+	})
+}
+
+func TestHandleFinishedBook_MergesOverlappingReads(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.config.Sync.MergeOverlappingReads = true
+	book := *toAudiobookshelfBook(createTestFinishedBook("abs-merge", "Merged Book", "Author", "B0MERGE001", ""))
+
+	mockClient.On("GetUserBook", mock.Anything, "123").
+		Return(&models.HardcoverBook{ID: "book-123", UserBookID: "123", BookStatusID: 3}, nil)
+	mockClient.On("GetUserBookReads", mock.Anything, mock.Anything).Return([]hardcover.UserBookRead{
+		newRead(1, "2025-03-01", "2025-03-10", 3600),
+		newRead(2, "2025-03-02", "2025-03-10", 3600),
+		newRead(3, "2025-03-04", "", 1200),
+	}, nil)
+	mockClient.On("DeleteUserBookRead", mock.Anything, int64(2)).Return(nil).Once()
+	mockClient.On("DeleteUserBookRead", mock.Anything, int64(3)).Return(nil).Once()
+	mockClient.On("CheckExistingUserBookRead", mock.Anything, mock.Anything).
+		Return(&hardcover.CheckExistingUserBookReadResult{}, nil).Maybe()
+
+	require.NoError(t, svc.HandleFinishedBook(context.Background(), book, "456", 123))
+
+	mockClient.AssertExpectations(t)
+	mockClient.AssertNotCalled(t, "DeleteUserBookRead", mock.Anything, int64(1))
+	mockClient.AssertNotCalled(t, "InsertUserBookRead", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,59 @@
+package sync
+
+import (
+	"context"
+	"strings"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// readingFormatID returns the Hardcover reading format ID to set on reads for the given book,
+// based on the reading format of its library's override or else Sync.ReadingFormat. In auto mode
+// ebook items are read as ebooks and everything else as audiobooks.
+func (s *Service) readingFormatID(ctx context.Context, book models.AudiobookshelfBook) int {
+	format := s.config.Sync.ReadingFormat
+	if override := libraryReadingFormat(ctx); override != "" {
+		format = override
+	}
+
+	switch format {
+	case config.ReadingFormatAudiobook:
+		return hardcover.ReadingFormatAudiobook
+	case config.ReadingFormatEbook:
+		return hardcover.ReadingFormatEbook
+	case config.ReadingFormatPhysical:
+		return hardcover.ReadingFormatPhysical
+	case config.ReadingFormatBoth:
+		return hardcover.ReadingFormatBoth
+	}
+
+	if strings.EqualFold(strings.TrimSpace(book.MediaType), "ebook") {
+		return hardcover.ReadingFormatEbook
+	}
+	return hardcover.ReadingFormatAudiobook
+}
+
+// editionFormat returns the edition format Hardcover lookups should prefer for the given book:
+// the reading format of its library's override, or else the one derived from its Audiobookshelf
+// media type
+func (s *Service) editionFormat(ctx context.Context, book models.AudiobookshelfBook) string {
+	if override := libraryReadingFormat(ctx); override != "" {
+		return override
+	}
+	if strings.EqualFold(strings.TrimSpace(book.MediaType), "ebook") {
+		return "ebook"
+	}
+	return "audiobook"
+}
+
+// libraryReadingFormat returns the reading format set by the override of the library being
+// processed, or an empty string if it doesn't set one
+func libraryReadingFormat(ctx context.Context) string {
+	override, ok := ctx.Value(libraryOverrideKey{}).(config.LibraryOverride)
+	if !ok {
+		return ""
+	}
+	return override.ReadingFormat
+}
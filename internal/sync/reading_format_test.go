@@ -0,0 +1,111 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/audiobookshelf"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReadingFormatID(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    string
+		mediaType string
+		override  string
+		want      int
+	}{
+		{name: "unset defaults to audiobook", format: "", mediaType: "book", want: hardcover.ReadingFormatAudiobook},
+		{name: "auto audiobook", format: config.ReadingFormatAuto, mediaType: "book", want: hardcover.ReadingFormatAudiobook},
+		{name: "auto ebook", format: config.ReadingFormatAuto, mediaType: "ebook", want: hardcover.ReadingFormatEbook},
+		{name: "explicit ebook", format: config.ReadingFormatEbook, mediaType: "book", want: hardcover.ReadingFormatEbook},
+		{name: "explicit audiobook overrides ebook item", format: config.ReadingFormatAudiobook, mediaType: "ebook", want: hardcover.ReadingFormatAudiobook},
+		{name: "physical", format: config.ReadingFormatPhysical, mediaType: "book", want: hardcover.ReadingFormatPhysical},
+		{name: "both", format: config.ReadingFormatBoth, mediaType: "book", want: hardcover.ReadingFormatBoth},
+		{name: "library override", format: config.ReadingFormatAudiobook, mediaType: "book", override: config.ReadingFormatEbook, want: hardcover.ReadingFormatEbook},
+		{name: "library override of ebook item", format: config.ReadingFormatAuto, mediaType: "ebook", override: config.ReadingFormatPhysical, want: hardcover.ReadingFormatPhysical},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := createTestService()
+			svc.config.Sync.ReadingFormat = tt.format
+			ctx := context.Background()
+			if tt.override != "" {
+				ctx = context.WithValue(ctx, libraryOverrideKey{}, config.LibraryOverride{ReadingFormat: tt.override})
+			}
+			assert.Equal(t, tt.want, svc.readingFormatID(ctx, models.AudiobookshelfBook{MediaType: tt.mediaType}))
+		})
+	}
+}
+
+func TestEditionFormat(t *testing.T) {
+	svc, _ := createTestService()
+	ctx := context.Background()
+	assert.Equal(t, "audiobook", svc.editionFormat(ctx, models.AudiobookshelfBook{MediaType: "book"}))
+	assert.Equal(t, "ebook", svc.editionFormat(ctx, models.AudiobookshelfBook{MediaType: "ebook"}))
+
+	// The library override wins over the media type
+	ctx = context.WithValue(ctx, libraryOverrideKey{}, config.LibraryOverride{ReadingFormat: config.ReadingFormatPhysical})
+	assert.Equal(t, config.ReadingFormatPhysical, svc.editionFormat(ctx, models.AudiobookshelfBook{MediaType: "ebook"}))
+}
+
+func TestShouldSyncLibrary_SkipPodcasts(t *testing.T) {
+	svc, _ := createTestService()
+	podcasts := &audiobookshelf.AudiobookshelfLibrary{ID: "lib2", Name: "Podcasts", MediaType: "podcast"}
+	books := &audiobookshelf.AudiobookshelfLibrary{ID: "lib1", Name: "Audiobooks", MediaType: "book"}
+
+	assert.True(t, svc.shouldSyncLibrary(podcasts))
+
+	svc.config.Sync.SkipPodcasts = true
+	assert.False(t, svc.shouldSyncLibrary(podcasts))
+	assert.True(t, svc.shouldSyncLibrary(books))
+}
+
+// TestHandleInProgressBook_CreateNewRead_ReadingFormat verifies that newly created reads carry the configured format
+func TestHandleInProgressBook_CreateNewRead_ReadingFormat(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.config.Sync.ReadingFormat = config.ReadingFormatEbook
+
+	testAudiobook := createTestBook("test-book-1", "Test Book", "Test Author", "B08N5KWB9H", "9781234567890")
+	testAudiobook.Progress.CurrentTime = 300
+	testAudiobook.Media.Duration = 1000
+	audiobook := toAudiobookshelfBook(testAudiobook)
+
+	userBookID := int64(123)
+	mockClient.On("GetUserBook", mock.Anything, "123").Return(&models.HardcoverBook{
+		ID:        "book-123",
+		Title:     "Test Book",
+		EditionID: "456",
+	}, nil).Once()
+	mockClient.On("GetUserBookReads", mock.Anything, hardcover.GetUserBookReadsInput{
+		UserBookID: userBookID,
+		Status:     "unfinished",
+	}).Return([]hardcover.UserBookRead{}, nil).Once()
+	mockClient.On("GetUserBookReads", mock.Anything, hardcover.GetUserBookReadsInput{
+		UserBookID: userBookID,
+	}).Return([]hardcover.UserBookRead{}, nil).Twice()
+	mockClient.On("CheckExistingUserBookRead", mock.Anything, mock.Anything).Return((*hardcover.CheckExistingUserBookReadResult)(nil), nil).Once()
+
+	mockClient.On("InsertUserBookRead", mock.Anything, mock.MatchedBy(func(input hardcover.InsertUserBookReadInput) bool {
+		return input.UserBookID == userBookID &&
+			input.DatesRead.ReadingFormatID != nil &&
+			*input.DatesRead.ReadingFormatID == hardcover.ReadingFormatEbook
+	})).Return(789, nil).Once()
+	mockClient.On("UpdateUserBookStatus", mock.Anything, hardcover.UpdateUserBookStatusInput{
+		ID:       userBookID,
+		StatusID: 2,
+	}).Return(nil).Once()
+
+	stateKey := fmt.Sprintf("%s:test-edition", audiobook.ID)
+	err := svc.handleInProgressBook(context.Background(), userBookID, *audiobook, stateKey)
+
+	assert.NoError(t, err)
+	mockClient.AssertExpectations(t)
+}
@@ -0,0 +1,32 @@
+package sync
+
+import (
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// alwaysReverifyStatus reports whether books with the status are listed in Sync.AlwaysReverify and
+// so are processed even when incremental sync would skip them as unchanged
+func (s *Service) alwaysReverifyStatus(status string) bool {
+	if status == "" {
+		return false
+	}
+	for _, reverify := range s.config.Sync.AlwaysReverify {
+		if reverify == status {
+			return true
+		}
+	}
+	return false
+}
+
+// alwaysReverifyBook reports whether the book's current status is listed in Sync.AlwaysReverify
+func (s *Service) alwaysReverifyBook(book models.AudiobookshelfBook) bool {
+	if len(s.config.Sync.AlwaysReverify) == 0 {
+		return false
+	}
+
+	progress := 0.0
+	if book.Media.Duration > 0 {
+		progress = book.Progress.CurrentTime / book.Media.Duration
+	}
+	return s.alwaysReverifyStatus(s.bookStatus(book, progress))
+}
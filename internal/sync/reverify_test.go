@@ -0,0 +1,48 @@
+package sync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessBook_AlwaysReverify(t *testing.T) {
+	inProgress := newTestBook("book-reading", "Reading Audiobook")
+	inProgress.Progress.CurrentTime = 1800
+
+	finished := newTestBook("book-finished", "Finished Audiobook")
+	finished.Progress.CurrentTime = 3600
+	finished.Progress.IsFinished = true
+	finished.Progress.FinishedAt = time.Now().Add(-24 * time.Hour).UnixMilli()
+
+	tests := []struct {
+		name            string
+		alwaysReverify  []string
+		wantReprocessed []string
+	}{
+		{name: "finished books re-verified", alwaysReverify: []string{"FINISHED"}, wantReprocessed: []string{"book-finished"}},
+		{name: "nothing re-verified", alwaysReverify: nil, wantReprocessed: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, mockClient := createTestService()
+			svc.summary = &SyncSummary{}
+			svc.config.Sync.Incremental = true
+			svc.config.Sync.AlwaysReverify = tt.alwaysReverify
+
+			// Both books were synced before and haven't changed since
+			svc.state.UpdateBook(inProgress.ID, 0.5, "IN_PROGRESS")
+			svc.state.UpdateBook(finished.ID, 1.0, "FINISHED")
+
+			require.NoError(t, svc.processBook(context.Background(), inProgress, nil))
+			require.NoError(t, svc.processBook(context.Background(), finished, nil))
+
+			assert.Equal(t, tt.wantReprocessed, lookedUpBooks(svc.summary))
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
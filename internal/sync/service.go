@@ -11,24 +11,36 @@ import (
 	"time"
 	"unicode"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/audiobookshelf"
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/metadata"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/metrics"
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/mismatch"
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/sync/state"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/tracing"
 )
 
 // Error definitions
 var (
 	ErrSkippedBook = errors.New("book was skipped")
+	// ErrAuthBackoff is returned when a sync is aborted or skipped because of repeated authentication failures
+	ErrAuthBackoff = errors.New("sync paused after repeated authentication failures")
+	// ErrBookTimeout is returned for a book that couldn't be processed within Sync.PerBookTimeout
+	ErrBookTimeout = errors.New("book processing timed out")
 )
 
 // progressUpdateInfo stores information about the last progress update for a book
 type progressUpdateInfo struct {
 	timestamp time.Time
 	progress  float64
+	// persisted is set for updates loaded from the persistent progress cache, written by an
+	// earlier run
+	persisted bool
 }
 
 // SyncSummary tracks the results of a sync operation
@@ -38,7 +50,19 @@ type SyncSummary struct {
 	BooksNotFound       []BookNotFoundInfo      `json:"books_not_found,omitempty"`
 	Mismatches          []mismatch.BookMismatch `json:"mismatches,omitempty"`
 	BooksSynced         int32                   `json:"books_synced,omitempty"`
-	sync.RWMutex        `json:"-"`
+	// BooksTimedOut holds the books of the current run that exceeded Sync.PerBookTimeout
+	BooksTimedOut []BookNotFoundInfo `json:"books_timed_out,omitempty"`
+	// BooksFailed holds the books of the current run that failed with an error
+	BooksFailed []BookNotFoundInfo `json:"books_failed,omitempty"`
+	// RunTimedOut is set when the current run stopped at Sync.MaxRunDuration
+	RunTimedOut bool `json:"run_timed_out,omitempty"`
+	// IdentifierConflicts holds the books of the current run whose metadata carried conflicting identifiers
+	IdentifierConflicts []IdentifierConflict `json:"identifier_conflicts,omitempty"`
+	// MatchSources counts the books of the current run matched per state.MatchSource constant
+	MatchSources map[string]int `json:"match_sources,omitempty"`
+	// SkipReasons counts the books of the current run that weren't synced per SkipReason constant
+	SkipReasons  map[string]int `json:"skip_reasons,omitempty"`
+	sync.RWMutex `json:"-"`
 }
 
 // BookNotFoundInfo contains information about a book that couldn't be found in Hardcover
@@ -64,11 +88,59 @@ type Service struct {
 	asinCache           map[string]*models.HardcoverBook // Cache for ASIN lookups (in-memory)
 	asinCacheMutex      sync.RWMutex                     // Mutex to protect ASIN cache
 	persistentCache     *PersistentASINCache             // Persistent ASIN cache across runs
+	titleSearchCache    *PersistentTitleSearchCache      // Persistent title/author search outcomes across runs
 	userBookCache       *PersistentUserBookCache         // Persistent user book cache
+	progressCache       *PersistentProgressCache         // Persistent last progress updates, nil unless enabled
+	mismatchReport      *MismatchReport                  // Mismatches of the last saved mismatch report
 	summary             *SyncSummary                     // Tracks sync operation results
 	// Per-run guard to prevent duplicate read inserts
 	createdReadsThisRun map[int64]struct{}
 	createdReadsMutex   sync.Mutex
+	// Start of the last completed sync; only items updated after it are fetched (zero fetches all)
+	updatedSince time.Time
+	// Hardcover book IDs already tagged with the source tag by this process
+	taggedBooks      map[string]struct{}
+	taggedBooksMutex sync.Mutex
+	// Consecutive authentication failures and the time until which syncs are paused because of them
+	authFailures     int
+	authBackoffUntil time.Time
+	authMutex        sync.Mutex
+	// Ownership of Hardcover book IDs checked or marked this run, for Sync.WantToReadOwnedOnly and
+	// Sync.SyncOwned
+	ownedBooksThisRun map[string]bool
+	ownedBooksMutex   sync.Mutex
+	// Hardcover book IDs of the items matched this run, by item ID, for Sync.SyncCollections
+	collectionMatches      map[string]string
+	collectionMatchesMutex sync.Mutex
+	// Changes to Hardcover a dry run would have made, written to the dry-run report
+	plannedActions      []PlannedAction
+	plannedActionsMutex sync.Mutex
+	// Hardcover book IDs Read in Hardcover with their last read date, loaded at the start of a run
+	// when Sync.SkipHardcoverFinished is enabled and only read while books are processed
+	hardcoverFinished map[string]time.Time
+	// When the user progress of the current run was fetched if it's the progress cached by an
+	// earlier run, zero otherwise; set at the start of a run and only read while books are processed
+	userProgressCachedAt time.Time
+	// The user's books, loaded at the start of a run when Sync.PrefetchUserBooks is enabled
+	userBooks *userBookIndex
+	// User books of the editions the processed items were matched to before, prefetched per
+	// library in batches when userBooks isn't loaded
+	editionUserBooks *userBookIndex
+	// Editions of the items in Paths.OverridesFile, loaded at the start of a run, and the items
+	// among them fetched this run
+	editionOverrides     map[string]*models.Edition
+	editionOverrideItems map[string]struct{}
+	// Items in Sync.Blocklist and Paths.BlocklistFile, loaded at the start of a run
+	blocklist map[string]struct{}
+	// Mismatches emitted as they're recorded when Sync.StreamMismatches is enabled
+	mismatchCh chan mismatch.BookMismatch
+	// Looks up the ASIN of books only found by an ISBN unknown to Hardcover, nil unless
+	// Metadata.ISBNToASINURL is set
+	asinProvider metadata.Provider
+	// Sync.HeartbeatFile while a sync runs and when it was last written, see heartbeat
+	heartbeatPath  string
+	lastHeartbeat  time.Time
+	heartbeatMutex sync.Mutex
 }
 
 // Config is the configuration type for the sync service
@@ -85,12 +157,29 @@ func NewService(absClient *audiobookshelf.Client, hcClient hardcover.HardcoverCl
 		lastProgressUpdates: make(map[string]progressUpdateInfo),
 		asinCache:           make(map[string]*models.HardcoverBook),
 		persistentCache:     NewPersistentASINCache(cfg.Paths.CacheDir),
+		titleSearchCache:    NewPersistentTitleSearchCache(cfg.Paths.CacheDir, cfg.Hardcover.TitleSearchCacheTTL),
 		userBookCache:       NewPersistentUserBookCache(cfg.Paths.CacheDir),
+		mismatchReport:      NewMismatchReport(cfg.Paths.CacheDir),
 		summary: &SyncSummary{
 			BooksNotFound: make([]BookNotFoundInfo, 0),
 			Mismatches:    make([]mismatch.BookMismatch, 0),
 		},
 		createdReadsThisRun: make(map[int64]struct{}),
+		taggedBooks:         make(map[string]struct{}),
+	}
+
+	// Mismatches recorded by this service use the configured edition defaults, publisher source, fix
+	// suggestions and log limit
+	mismatch.SetEditionDefaults(cfg.Edition.Defaults)
+	mismatch.SetPublisherSource(cfg.Edition.PublisherSource)
+	mismatch.SetFixSuggestions(cfg.Sync.MismatchFixSuggestions)
+	mismatch.SetLogLimit(cfg.Logging.MismatchLogLimit)
+
+	if cfg.Sync.StreamMismatches {
+		svc.mismatchCh = make(chan mismatch.BookMismatch, mismatchChannelBuffer)
+	}
+	if cfg.Metadata.ISBNToASINURL != "" {
+		svc.asinProvider = metadata.NewHTTPProvider(cfg.Metadata.ISBNToASINURL, svc.log)
 	}
 
 	// Migrate old state file if it exists
@@ -107,8 +196,17 @@ func NewService(absClient *audiobookshelf.Client, hcClient hardcover.HardcoverCl
 	if err != nil {
 		return nil, fmt.Errorf("failed to load state: %w", err)
 	}
+	if cfg.Sync.RepairStateKeys {
+		if repaired := svc.state.RepairKeys(); repaired > 0 {
+			svc.log.Info("Repaired book keys of the sync state", map[string]interface{}{
+				"repaired":   repaired,
+				"state_file": svc.statePath,
+			})
+		}
+	}
 
-	// Load persistent ASIN cache
+	// Load persistent ASIN cache, retrying failed lookups sooner than successful ones
+	svc.persistentCache.SetNegativeTTL(cfg.Hardcover.NegativeCacheTTL)
 	if err := svc.persistentCache.Load(); err != nil {
 		svc.log.Warn("Failed to load persistent ASIN cache, starting with empty cache", map[string]interface{}{
 			"error": err.Error(),
@@ -122,6 +220,19 @@ func NewService(absClient *audiobookshelf.Client, hcClient hardcover.HardcoverCl
 		})
 	}
 
+	// Load persistent title search cache
+	if err := svc.titleSearchCache.Load(); err != nil {
+		svc.log.Warn("Failed to load persistent title search cache, starting with empty cache", map[string]interface{}{
+			"error": err.Error(),
+		})
+	} else if total, matched, notFound := svc.titleSearchCache.Stats(); total > 0 {
+		svc.log.Info("Loaded persistent title search cache", map[string]interface{}{
+			"total_entries": total,
+			"matched":       matched,
+			"not_found":     notFound,
+		})
+	}
+
 	// Load persistent user book cache
 	if err := svc.userBookCache.Load(); err != nil {
 		svc.log.Warn("Failed to load persistent user book cache, starting with empty cache", map[string]interface{}{
@@ -136,6 +247,20 @@ func NewService(absClient *audiobookshelf.Client, hcClient hardcover.HardcoverCl
 		})
 	}
 
+	// Load the last progress updates of previous runs
+	if cfg.Sync.PersistProgressCache {
+		svc.progressCache = NewPersistentProgressCache(cfg.Paths.CacheDir)
+		if err := svc.loadProgressCache(); err != nil {
+			svc.log.Warn("Failed to load persistent progress cache, starting with empty cache", map[string]interface{}{
+				"error": err.Error(),
+			})
+		} else {
+			svc.log.Info("Loaded persistent progress cache", map[string]interface{}{
+				"total_entries": len(svc.lastProgressUpdates),
+			})
+		}
+	}
+
 	return svc, nil
 }
 
@@ -202,6 +327,7 @@ func (s *Service) recordBookNotFound(book models.AudiobookshelfBook, err error)
 	}
 
 	s.summary.BooksNotFound = append(s.summary.BooksNotFound, bookInfo)
+	s.summary.addSkipReason(SkipReasonNotFound)
 }
 
 // recordMismatch records a book mismatch
@@ -223,6 +349,62 @@ func (s *Service) recordMismatch(m mismatch.BookMismatch) {
 	s.summary.Mismatches = append(s.summary.Mismatches, m)
 }
 
+// bookMismatch returns a mismatch for the Audiobookshelf book with the given reason, including the
+// matched Hardcover book when there is one
+func (s *Service) bookMismatch(book models.AudiobookshelfBook, hcBook *models.HardcoverBook, reason string) mismatch.BookMismatch {
+	coverURL := ""
+	if book.Media.CoverPath != "" {
+		coverURL = fmt.Sprintf("%s/api/items/%s/cover", s.config.Audiobookshelf.URL, book.ID)
+	}
+
+	bookMismatch := mismatch.BookMismatch{
+		BookID:          book.ID,
+		Title:           book.Media.Metadata.Title,
+		Subtitle:        book.Media.Metadata.Subtitle,
+		Author:          book.Media.Metadata.AuthorName,
+		Narrator:        book.Media.Metadata.NarratorName,
+		ASIN:            book.Media.Metadata.ASIN,
+		ISBN:            book.Media.Metadata.ISBN,
+		LibraryID:       book.LibraryID,
+		ItemID:          book.ID,
+		PublishedYear:   book.Media.Metadata.PublishedYear,
+		DurationSeconds: int(book.Media.Duration),
+		CoverURL:        coverURL,
+		Publisher:       book.Media.Metadata.Publisher,
+		Reason:          reason,
+		Timestamp:       time.Now().Unix(),
+		CreatedAt:       time.Now(),
+	}
+	if hcBook != nil {
+		bookMismatch.HardcoverBookID = hcBook.ID
+		bookMismatch.HardcoverTitle = hcBook.Title
+		bookMismatch.HardcoverASIN = hcBook.EditionASIN
+		if hcBook.EditionISBN13 != "" {
+			bookMismatch.HardcoverISBN = hcBook.EditionISBN13
+		} else {
+			bookMismatch.HardcoverISBN = hcBook.EditionISBN10
+		}
+	}
+	return bookMismatch
+}
+
+// SetUserID sets the user the service syncs for, as reported in its summary
+func (s *Service) SetUserID(userID string) {
+	s.summary.Lock()
+	defer s.summary.Unlock()
+	s.summary.UserID = userID
+}
+
+// metricsUser returns the user label of the service's metrics, empty in single-user mode
+func (s *Service) metricsUser() string {
+	if s.summary == nil {
+		return ""
+	}
+	s.summary.RLock()
+	defer s.summary.RUnlock()
+	return s.summary.UserID
+}
+
 // GetSummary returns the current sync summary
 func (s *Service) GetSummary() *SyncSummary {
 	// If summary is nil, return a new empty summary
@@ -259,6 +441,9 @@ func (s *Service) GetSummary() *SyncSummary {
 
 	copy(summaryCopy.BooksNotFound, s.summary.BooksNotFound)
 	copy(summaryCopy.Mismatches, s.summary.Mismatches)
+	summaryCopy.IdentifierConflicts = append([]IdentifierConflict(nil), s.summary.IdentifierConflicts...)
+	summaryCopy.MatchSources = copyCounts(s.summary.MatchSources)
+	summaryCopy.SkipReasons = copyCounts(s.summary.SkipReasons)
 
 	// Log the copy values for debugging
 	s.log.Debug("GetSummary: returning copy", map[string]interface{}{
@@ -284,6 +469,10 @@ func (s *Service) logSyncSummary() {
 	copy(booksNotFound, s.summary.BooksNotFound)
 	mismatches := make([]mismatch.BookMismatch, len(s.summary.Mismatches))
 	copy(mismatches, s.summary.Mismatches)
+	booksTimedOut := make([]BookNotFoundInfo, len(s.summary.BooksTimedOut))
+	copy(booksTimedOut, s.summary.BooksTimedOut)
+	booksFailed := make([]BookNotFoundInfo, len(s.summary.BooksFailed))
+	copy(booksFailed, s.summary.BooksFailed)
 
 	// Log summary header
 	s.log.Info("========================================", nil)
@@ -320,8 +509,30 @@ func (s *Service) logSyncSummary() {
 		s.log.Info("Note: Check the mismatches directory for detailed information about mismatched books.", nil)
 	}
 
+	// Log books that timed out
+	if len(booksTimedOut) > 0 {
+		s.log.Warn(fmt.Sprintf("Books timed out (will be retried): %d", len(booksTimedOut)), nil)
+		for i, book := range booksTimedOut {
+			s.log.Warn(fmt.Sprintf("  %d. %s by %s", i+1, book.Title, book.Author), map[string]interface{}{
+				"book_id": book.BookID,
+				"error":   book.Error,
+			})
+		}
+	}
+
+	// Log books that failed
+	if len(booksFailed) > 0 {
+		s.log.Warn(fmt.Sprintf("Books failed (will be retried): %d", len(booksFailed)), nil)
+		for i, book := range booksFailed {
+			s.log.Warn(fmt.Sprintf("  %d. %s by %s", i+1, book.Title, book.Author), map[string]interface{}{
+				"book_id": book.BookID,
+				"error":   book.Error,
+			})
+		}
+	}
+
 	// Log summary footer
-	if len(booksNotFound) == 0 && len(mismatches) == 0 {
+	if len(booksNotFound) == 0 && len(mismatches) == 0 && len(booksTimedOut) == 0 && len(booksFailed) == 0 {
 		s.log.Info("All books were successfully processed with no issues.", nil)
 	}
 
@@ -390,6 +601,13 @@ func (s *Service) findOrCreateUserBookID(ctx context.Context, editionID, status
 		"editionIDInt": editionIDInt,
 	})
 
+	if userBookID := s.editionUserBooks.lookup("", editionID); userBookID > 0 {
+		logCtx.Debug("Found prefetched user book ID", map[string]interface{}{
+			"userBookID": userBookID,
+		})
+		return userBookID, nil
+	}
+
 	userBookID, err := s.hardcover.GetUserBookID(ctx, int(editionIDInt))
 	if err != nil {
 		errMsg := fmt.Sprintf("Error checking for existing user book ID: %v", err)
@@ -415,23 +633,14 @@ func (s *Service) findOrCreateUserBookID(ctx context.Context, editionID, status
 	})
 
 	// If dry-run mode is enabled, log and return early without creating
-	if s.config.Sync.DryRun {
-		dryRunMsg := fmt.Sprintf("[DRY-RUN] Would create new user book with status: %s", status)
-		logCtx.Info(dryRunMsg, map[string]interface{}{
-			"status": status,
-		})
-		// Return a negative value to indicate dry-run mode
-		return -1, nil
+	if s.dryRun(ctx) {
+		return s.createUserBookID(ctx, editionID, status)
 	}
 
 	logCtx.Info("Creating new user book with status", map[string]interface{}{
 		"status": status,
 	})
 
-	logCtx.Info("Creating new user book with status", map[string]interface{}{
-		"status": status,
-	})
-
 	// Double-check if the user book exists to prevent race conditions
 	logCtx.Debug("Performing second check for existing user book ID to prevent race conditions", nil)
 
@@ -455,6 +664,26 @@ func (s *Service) findOrCreateUserBookID(ctx context.Context, editionID, status
 		return int64(userBookID), nil
 	}
 
+	return s.createUserBookID(ctx, editionID, status)
+}
+
+// createUserBookID creates a user book with the given status for an edition the user has no
+// user book for yet. In dry-run mode it only plans the creation and returns -1.
+func (s *Service) createUserBookID(ctx context.Context, editionID, status string) (int64, error) {
+	logCtx := s.log.With(map[string]interface{}{
+		"editionID": editionID,
+	})
+
+	if s.dryRun(ctx) {
+		dryRunMsg := fmt.Sprintf("[DRY-RUN] Would create new user book with status: %s", status)
+		logCtx.Info(dryRunMsg, map[string]interface{}{
+			"status": status,
+		})
+		s.planAction(ctx, PlannedAction{Action: PlannedActionCreateUserBook, EditionID: editionID, Status: status})
+		// Return a negative value to indicate dry-run mode
+		return -1, nil
+	}
+
 	// Create a new user book with the specified status
 	logCtx.Info("Attempting to create new user book", map[string]interface{}{
 		"status": status,
@@ -465,7 +694,7 @@ func (s *Service) findOrCreateUserBookID(ctx context.Context, editionID, status
 		errMsg := fmt.Sprintf("Failed to create user book: %v", err)
 		s.log.Error(errMsg, map[string]interface{}{
 			"error":     err,
-			"editionID": editionIDInt,
+			"editionID": editionID,
 			"status":    status,
 		})
 		return 0, fmt.Errorf("failed to create user book: %w", err)
@@ -482,7 +711,7 @@ func (s *Service) findOrCreateUserBookID(ctx context.Context, editionID, status
 	}
 
 	s.log.Info("Successfully created new user book with status", map[string]interface{}{
-		"editionID":  editionIDInt,
+		"editionID":  editionID,
 		"userBookID": userBookID64,
 		"status":     status,
 	})
@@ -491,12 +720,45 @@ func (s *Service) findOrCreateUserBookID(ctx context.Context, editionID, status
 }
 
 // Sync performs a full synchronization between Audiobookshelf and Hardcover
-func (s *Service) Sync(ctx context.Context) error {
+func (s *Service) Sync(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "sync.run",
+		attribute.Bool("sync.dry_run", s.config.Sync.DryRun),
+		attribute.Bool("sync.incremental", s.config.Sync.Incremental),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	// Don't hit the APIs again while paused after repeated authentication failures
+	if until := s.AuthBackoffUntil(); time.Now().Before(until) {
+		s.log.Warn("Skipping sync, paused after repeated authentication failures", map[string]interface{}{
+			"until": until.Format(time.RFC3339),
+		})
+		return fmt.Errorf("%w until %s", ErrAuthBackoff, until.Format(time.RFC3339))
+	}
+
+	// Report the run to the summary webhook once it's done
+	startedAt := time.Now()
+	defer func() {
+		s.postRunSummary(startedAt, err)
+		metrics.ObserveSync(s.metricsUser(), startedAt, err)
+	}()
+
+	// Let watchdogs see the run is making progress
+	stopHeartbeat := s.startHeartbeat()
+	defer stopHeartbeat()
+
+	// Limit how long the books are processed for; state and caches are still saved afterwards
+	runCtx, cancelRun := s.withMaxRunDuration(ctx)
+	defer cancelRun()
+
 	// Clear any existing mismatches at the start of each sync cycle
 	// This prevents accumulation of resolved mismatches in continuous sync mode
 	mismatch.Clear()
 	s.log.Info("Cleared previous mismatches at start of sync cycle", nil)
 
+	// Stream this run's mismatches to a file as they're recorded
+	stopMismatchStream := s.startMismatchStream()
+	defer stopMismatchStream()
+
 	// Clear ASIN cache to ensure fresh lookups for this sync run
 	s.clearASINCache()
 
@@ -504,11 +766,26 @@ func (s *Service) Sync(ctx context.Context) error {
 	s.createdReadsMutex.Lock()
 	s.createdReadsThisRun = make(map[int64]struct{})
 	s.createdReadsMutex.Unlock()
+	s.ownedBooksMutex.Lock()
+	s.ownedBooksThisRun = nil
+	s.ownedBooksMutex.Unlock()
+	s.collectionMatchesMutex.Lock()
+	s.collectionMatches = nil
+	s.collectionMatchesMutex.Unlock()
+	s.plannedActionsMutex.Lock()
+	s.plannedActions = nil
+	s.plannedActionsMutex.Unlock()
 
 	// Reset only the counters, not the entire summary
 	s.summary.Lock()
 	s.summary.TotalBooksProcessed = 0
 	s.summary.BooksSynced = 0
+	s.summary.BooksTimedOut = nil
+	s.summary.BooksFailed = nil
+	s.summary.RunTimedOut = false
+	s.summary.IdentifierConflicts = nil
+	s.summary.MatchSources = nil
+	s.summary.SkipReasons = nil
 	s.summary.Unlock()
 
 	// Keep BooksNotFound and Mismatches as they are for historical tracking
@@ -526,9 +803,28 @@ func (s *Service) Sync(ctx context.Context) error {
 	s.log.Info("STARTING FULL SYNCHRONIZATION", nil)
 	s.log.Info("========================================", nil)
 
+	// Determine which items need fetching before recording this run's start time
+	s.updatedSince = s.incrementalSince()
+
 	// Update the last sync start time
 	s.state.UpdateLibrary("sync") // Using "sync" as a special library ID for global sync state
 
+	// Periodically flush state changes to disk while the sync runs (and once more on cancellation)
+	if interval := s.config.Sync.StateFlushInterval; interval > 0 {
+		flusher := s.state.StartWriteBehind(ctx, s.statePath, interval, func(err error) {
+			s.log.Warn("Failed to flush sync state", map[string]interface{}{
+				"error": err.Error(),
+			})
+		})
+		defer func() {
+			if err := flusher.Stop(); err != nil {
+				s.log.Warn("Failed to flush sync state", map[string]interface{}{
+					"error": err.Error(),
+				})
+			}
+		}()
+	}
+
 	// Log service configuration (without accessing unexported fields directly)
 	s.log.Info("SYNC CONFIGURATION", nil)
 	s.log.Info("========================================", nil)
@@ -565,8 +861,11 @@ func (s *Service) Sync(ctx context.Context) error {
 
 	// Fetch user progress data from Audiobookshelf
 	s.log.Info("Fetching user progress data from Audiobookshelf...", nil)
-	userProgress, err := s.audiobookshelf.GetUserProgress(ctx)
-	if err != nil {
+	userProgress, err := s.fetchUserProgress(runCtx)
+	if authErr := s.recordAuthResult(err); authErr != nil {
+		return authErr
+	}
+	if userProgress == nil {
 		s.log.Warn("Failed to fetch user progress data, falling back to basic progress tracking", map[string]interface{}{
 			"error": err,
 		})
@@ -577,9 +876,17 @@ func (s *Service) Sync(ctx context.Context) error {
 		})
 	}
 
+	s.loadHardcoverFinished(runCtx)
+	s.loadUserBooks(runCtx)
+	s.loadEditionOverrides(runCtx)
+	s.loadBlocklist()
+
 	// Get all libraries from Audiobookshelf
 	s.log.Info("Fetching libraries from Audiobookshelf...", nil)
-	libraries, err := s.audiobookshelf.GetLibraries(ctx)
+	libraries, err := s.audiobookshelf.GetLibraries(runCtx)
+	if authErr := s.recordAuthResult(err); authErr != nil {
+		return authErr
+	}
 	if err != nil {
 		s.log.Error("Failed to fetch libraries", map[string]interface{}{
 			"error": err,
@@ -618,7 +925,6 @@ func (s *Service) Sync(ctx context.Context) error {
 		}
 	} else {
 		s.log.Info("No library filtering configured, processing all libraries", nil)
-		filteredLibraries = libraries
 	}
 
 	// Track total books processed across all libraries
@@ -631,6 +937,17 @@ func (s *Service) Sync(ctx context.Context) error {
 		})
 	}
 
+	// Fetch the libraries' items, ahead of processing when prefetching is enabled
+	fetcher := s.newLibraryFetcher(runCtx, filteredLibraries)
+	defer fetcher.stop()
+
+	// Items fetched this run, to prune the state of deleted items when every library was fetched in
+	// full. Runs limited to a few books for testing don't prune.
+	seenItems := make(map[string]struct{})
+	fetchedAll := s.updatedSince.IsZero() && totalBooksLimit == 0
+	// Libraries whose items couldn't all be fetched or processed
+	librariesFailed := 0
+
 	// Process each filtered library
 	for i := range filteredLibraries {
 		// Skip processing if we've reached the limit
@@ -642,13 +959,45 @@ func (s *Service) Sync(ctx context.Context) error {
 			break
 		}
 
-		// Process the library and get the number of books processed
-		processed, err := s.processLibrary(ctx, &filteredLibraries[i], totalBooksLimit-totalBooksProcessed, userProgress)
+		// Process the library as its items are fetched and get the number of books processed
+		processed := 0
+		var err error
+		for last := false; !last; {
+			var items []models.AudiobookshelfBook
+			items, last, err = fetcher.next(runCtx, i)
+			s.heartbeat()
+			if err != nil {
+				fetchedAll = false
+				break
+			}
+			for _, item := range items {
+				seenItems[item.ID] = struct{}{}
+			}
+			s.noteEditionOverrideItems(items)
+
+			var itemsProcessed int
+			itemsProcessed, err = s.processLibraryItems(runCtx, &filteredLibraries[i], items, totalBooksLimit-totalBooksProcessed-processed, userProgress)
+			processed += itemsProcessed
+			if err != nil || (totalBooksLimit > 0 && totalBooksProcessed+processed >= totalBooksLimit) {
+				// The library's remaining items weren't seen
+				if !last {
+					fetchedAll = false
+				}
+				break
+			}
+		}
+		if s.maxRunDurationReached(ctx, runCtx) {
+			break
+		}
+		if errors.Is(err, ErrAuthBackoff) {
+			return err
+		}
 		if err != nil {
 			s.log.Error("Failed to process library", map[string]interface{}{
 				"error":      err,
 				"library_id": filteredLibraries[i].ID,
 			})
+			librariesFailed++
 			continue
 		}
 
@@ -671,34 +1020,82 @@ func (s *Service) Sync(ctx context.Context) error {
 		}
 	}
 
-	// Save any mismatches that occurred during sync
+	s.logEditionOverrideMatches()
+	if !s.runTimedOut() {
+		s.syncCollections(runCtx, filteredLibraries)
+	}
+
+	// Record any mismatches in the summary
+	mismatches := mismatch.GetAll()
+	for _, m := range mismatches {
+		s.recordMismatch(m)
+	}
+	metrics.MismatchesRecorded(s.metricsUser(), len(mismatches))
+
+	// Save any mismatches that occurred during sync, along with those of the previous report when
+	// not every item was processed
+	if s.mismatchReport != nil {
+		if !fetchedAll || s.runTimedOut() || librariesFailed > 0 {
+			s.restorePreviousMismatches(seenItems)
+		}
+		s.saveMismatchReport()
+	}
 	if err := mismatch.SaveToFile(ctx, s.hardcover, "", s.config); err != nil {
 		s.log.Error("Failed to save mismatch files", map[string]interface{}{
 			"error": err,
 		})
 		// Don't return error here as the sync itself completed successfully
 	}
+	mismatch.LogSuppressedSummary()
+	s.writeDryRunReport()
+	s.exportUnmatched(ctx)
 
-	// Record any mismatches in the summary
-	mismatches := mismatch.GetAll()
-	for _, m := range mismatches {
-		s.recordMismatch(m)
+	// Update the last sync time, unless books timed out, failed or weren't reached and need to be
+	// fetched again on the next run
+	if s.runTimedOut() {
+		s.log.Warn("Sync reached its maximum run duration, the next run will continue with the remaining books", map[string]interface{}{
+			"max_run_duration": s.config.Sync.MaxRunDuration.String(),
+		})
+	} else if timedOut := s.timedOutBookCount(); timedOut > 0 {
+		s.log.Warn("Books timed out, the next run will fetch all items again to retry them", map[string]interface{}{
+			"timed_out": timedOut,
+		})
+	} else if failed := s.failedBookCount(); failed > 0 || librariesFailed > 0 {
+		s.log.Warn("Books failed, the next run will fetch the items again to retry them", map[string]interface{}{
+			"failed_books":     failed,
+			"failed_libraries": librariesFailed,
+		})
+	} else {
+		s.state.SetFullSync()
+		if fetchedAll {
+			s.state.SetFullFetch()
+			s.pruneUnseenItems(seenItems)
+		}
 	}
 
-	// Update the last sync time
-	s.state.SetFullSync()
+	// Log ASIN cache performance statistics
+	s.logASINCacheStats()
+
+	s.saveStateAndCaches()
+
+	// Log the sync summary
+	s.logSyncSummary()
+
+	s.log.Info("Sync completed successfully", nil)
+
+	return nil
+}
 
+// saveStateAndCaches saves the sync state and the persistent caches at the end of a sync. Failures
+// are only logged, as the books themselves were synced.
+func (s *Service) saveStateAndCaches() {
 	// Save the state
 	if err := s.state.Save(s.statePath); err != nil {
 		s.log.Error("Failed to save sync state", map[string]interface{}{
 			"error": err.Error(),
 		})
-		// Don't return the error here as the sync itself was successful
 	}
 
-	// Log ASIN cache performance statistics
-	s.logASINCacheStats()
-
 	// Save persistent ASIN cache
 	if err := s.persistentCache.Save(); err != nil {
 		s.log.Warn("Failed to save persistent ASIN cache", map[string]interface{}{
@@ -708,6 +1105,13 @@ func (s *Service) Sync(ctx context.Context) error {
 		s.log.Debug("Saved persistent ASIN cache", nil)
 	}
 
+	// Save persistent title search cache
+	if err := s.titleSearchCache.Save(); err != nil {
+		s.log.Warn("Failed to save persistent title search cache", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
 	// Save persistent user book cache
 	if err := s.userBookCache.Save(); err != nil {
 		s.log.Warn("Failed to save persistent user book cache", map[string]interface{}{
@@ -717,16 +1121,25 @@ func (s *Service) Sync(ctx context.Context) error {
 		s.log.Debug("Saved persistent user book cache", nil)
 	}
 
-	// Log the sync summary
-	s.logSyncSummary()
-
-	s.log.Info("Sync completed successfully", nil)
-
-	return nil
+	// Save persistent progress cache
+	if s.progressCache != nil {
+		if err := s.saveProgressCache(); err != nil {
+			s.log.Warn("Failed to save persistent progress cache", map[string]interface{}{
+				"error": err.Error(),
+			})
+		} else {
+			s.log.Debug("Saved persistent progress cache", nil)
+		}
+	}
 }
 
 // shouldSyncLibrary determines if a library should be synced based on configuration
 func (s *Service) shouldSyncLibrary(library *audiobookshelf.AudiobookshelfLibrary) bool {
+	// Podcast libraries have no books on Hardcover
+	if s.config.Sync.SkipPodcasts && strings.EqualFold(library.MediaType, "podcast") {
+		return false
+	}
+
 	// If include list is specified, only sync libraries in the include list
 	if len(s.config.Sync.Libraries.Include) > 0 {
 		for _, included := range s.config.Sync.Libraries.Include {
@@ -753,8 +1166,62 @@ func (s *Service) shouldSyncLibrary(library *audiobookshelf.AudiobookshelfLibrar
 	return true
 }
 
+// incrementalSince returns the start time of the last sync if it completed successfully and
+// incremental mode is enabled, or the zero time if all library items should be fetched
+func (s *Service) incrementalSince() time.Time {
+	if !s.config.Sync.Incremental || s.state == nil {
+		return time.Time{}
+	}
+
+	lastSync, exists := s.state.GetLibraryState("sync")
+	if !exists || lastSync.LastUpdated == 0 {
+		return time.Time{}
+	}
+
+	// A run that didn't finish may have skipped items, so fetch everything again
+	if s.state.GetLastFullSync() < lastSync.LastUpdated {
+		return time.Time{}
+	}
+
+	// Fetch everything once in a while, which is when the state of deleted items is pruned
+	if interval := s.config.Sync.FullSyncInterval; interval > 0 {
+		if lastFullFetch := time.Unix(s.state.GetLastFullFetch(), 0); time.Since(lastFullFetch) >= interval {
+			s.log.Info("Fetching all items, the full sync interval has passed", map[string]interface{}{
+				"full_sync_interval": interval.String(),
+				"last_full_fetch":    lastFullFetch.Format(time.RFC3339),
+			})
+			return time.Time{}
+		}
+	}
+
+	return time.Unix(lastSync.LastUpdated, 0)
+}
+
+// fullFetchReason returns why all items have to be fetched even in incremental mode, or an empty
+// string if only recently updated ones are needed
+func (s *Service) fullFetchReason() string {
+	switch {
+	case len(s.config.Sync.AlwaysReverify) > 0:
+		return "some statuses are always re-verified"
+	case s.pullsFromHardcover():
+		return "Hardcover progress is synced to Audiobookshelf"
+	case s.notFoundRetriesPending():
+		return "books not found in Hardcover yet are retried"
+	}
+	return ""
+}
+
 // processLibrary processes a library and returns the number of books processed
 func (s *Service) processLibrary(ctx context.Context, library *audiobookshelf.AudiobookshelfLibrary, maxBooks int, userProgress *models.AudiobookshelfUserProgress) (int, error) {
+	items, err := s.fetchLibraryItems(ctx, library)
+	if err != nil {
+		return 0, err
+	}
+	return s.processLibraryItems(ctx, library, items, maxBooks, userProgress)
+}
+
+// fetchLibraryItems gets the items of a library, limited to recently updated ones in incremental mode
+func (s *Service) fetchLibraryItems(ctx context.Context, library *audiobookshelf.AudiobookshelfLibrary) ([]models.AudiobookshelfBook, error) {
 	// Create a logger with library context
 	libraryLog := s.log.With(map[string]interface{}{
 		"library_id":   library.ID,
@@ -763,10 +1230,19 @@ func (s *Service) processLibrary(ctx context.Context, library *audiobookshelf.Au
 
 	libraryLog.Info("Processing library", nil)
 
-	// Get all items from the library
-	items, err := s.audiobookshelf.GetLibraryItems(ctx, library.ID)
+	// Get the items from the library, limited to recently updated ones in incremental mode
+	var items []models.AudiobookshelfBook
+	var err error
+	if since := s.fetchSince(library); !since.IsZero() {
+		items, err = s.audiobookshelf.GetLibraryItemsUpdatedSince(ctx, library.ID, since)
+	} else {
+		items, err = s.audiobookshelf.GetLibraryItems(ctx, library.ID)
+	}
+	if authErr := s.recordAuthResult(err); authErr != nil {
+		return nil, authErr
+	}
 	if err != nil {
-		return 0, fmt.Errorf("failed to get library items: %w", err)
+		return nil, fmt.Errorf("failed to get library items: %w", err)
 	}
 
 	libraryLog.Info("Found items in library", map[string]interface{}{
@@ -775,6 +1251,85 @@ func (s *Service) processLibrary(ctx context.Context, library *audiobookshelf.Au
 		"items_count":  len(items),
 	})
 
+	return items, nil
+}
+
+// fetchLibraryItemsPage fetches a page of libraryPageSize items of the library, counting pages
+// from 0, and reports whether the library has more pages. With a non-zero since, which
+// fetchSince returns once per library, only items updated since then are fetched.
+func (s *Service) fetchLibraryItemsPage(ctx context.Context, library *audiobookshelf.AudiobookshelfLibrary, since time.Time, page int) ([]models.AudiobookshelfBook, bool, error) {
+	libraryLog := s.log.With(map[string]interface{}{
+		"library_id":   library.ID,
+		"library_name": library.Name,
+		"page":         page,
+	})
+	if page == 0 {
+		libraryLog.Info("Processing library", nil)
+	}
+
+	items, more, err := s.audiobookshelf.GetLibraryItemsPage(ctx, library.ID, since, page, libraryPageSize)
+	if authErr := s.recordAuthResult(err); authErr != nil {
+		return nil, false, authErr
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get library items: %w", err)
+	}
+
+	libraryLog.Debug("Found page of items in library", map[string]interface{}{
+		"items_count": len(items),
+		"more":        more,
+	})
+
+	return items, more, nil
+}
+
+// fetchSince returns the time the library's items are fetched updated since in incremental mode,
+// or the zero time when all items are fetched
+func (s *Service) fetchSince(library *audiobookshelf.AudiobookshelfLibrary) time.Time {
+	if s.updatedSince.IsZero() {
+		return time.Time{}
+	}
+	libraryLog := s.log.With(map[string]interface{}{
+		"library_id":   library.ID,
+		"library_name": library.Name,
+	})
+	if reason := s.fullFetchReason(); reason != "" {
+		// Unchanged items that need processing anyway wouldn't be fetched otherwise
+		libraryLog.Debug("Fetching all items despite incremental sync", map[string]interface{}{
+			"reason": reason,
+		})
+		return time.Time{}
+	}
+	libraryLog.Info("Fetching only items updated since last sync", map[string]interface{}{
+		"since": s.updatedSince.Format(time.RFC3339),
+	})
+	return s.updatedSince
+}
+
+// processLibraryItems processes the fetched items of a library, at most maxBooks of them when
+// maxBooks is positive, and returns the number of books processed
+func (s *Service) processLibraryItems(ctx context.Context, library *audiobookshelf.AudiobookshelfLibrary, items []models.AudiobookshelfBook, maxBooks int, userProgress *models.AudiobookshelfUserProgress) (processed int, err error) {
+	ctx, span := tracing.Start(ctx, "sync.library",
+		attribute.String("library.id", library.ID),
+		attribute.String("library.name", library.Name),
+		attribute.Int("library.items", len(items)),
+	)
+	defer func() {
+		span.SetAttributes(attribute.Int("library.books_processed", processed))
+		tracing.End(span, err)
+	}()
+
+	libraryLog := s.log.With(map[string]interface{}{
+		"library_id":   library.ID,
+		"library_name": library.Name,
+	})
+
+	// Books of a library overridden into dry-run mode are only planned, not written to Hardcover
+	ctx = s.withLibraryOverride(ctx, library)
+	if s.dryRun(ctx) && !s.config.Sync.DryRun {
+		libraryLog.Info("Library is in dry-run mode, no changes will be made to Hardcover for its books", nil)
+	}
+
 	// If we have a maxBooks limit, apply it
 	if maxBooks > 0 && len(items) > maxBooks {
 		libraryLog.Info("Limiting number of books to process based on remaining test book limit", map[string]interface{}{
@@ -784,11 +1339,22 @@ func (s *Service) processLibrary(ctx context.Context, library *audiobookshelf.Au
 		items = items[:maxBooks]
 	}
 
+	// Look up the user books of the editions the items were matched to before in batches
+	s.prefetchEditionUserBooks(ctx, items)
+
 	// Process each item in the library
-	processed := 0
 	for _, book := range items {
-		// Process the item
-		err := s.processBook(ctx, book, userProgress)
+		// Stop when the run is canceled or reaches its maximum duration
+		if err := ctx.Err(); err != nil {
+			return processed, err
+		}
+
+		// Process the item, showing watchdogs the run is still making progress
+		s.heartbeat()
+		err := s.processBookWithTimeout(ctx, book, userProgress)
+		if authErr := s.recordAuthResult(err); authErr != nil {
+			return processed, authErr
+		}
 		if err != nil {
 			// Check if this is ErrSkippedBook - which we still count as processed
 			// since we've recorded a mismatch and updated state for these books
@@ -797,12 +1363,24 @@ func (s *Service) processLibrary(ctx context.Context, library *audiobookshelf.Au
 					"item_id": book.ID,
 				})
 				processed++
+			} else if hardcover.IsOperationDisabled(err) {
+				// A Hardcover mutation the book needed is disabled by configuration, which is a
+				// deliberate restriction rather than a failure
+				libraryLog.Info("Book not fully synced, a required Hardcover operation is disabled", map[string]interface{}{
+					"error":   err.Error(),
+					"item_id": book.ID,
+				})
+				processed++
 			} else {
 				// For other errors, log and skip without incrementing processed count
 				libraryLog.Error("Failed to process item", map[string]interface{}{
 					"error":   err,
 					"item_id": book.ID,
 				})
+				// Timed out books are already recorded, and books of a canceled run aren't failures
+				if !errors.Is(err, ErrBookTimeout) && ctx.Err() == nil {
+					s.recordBookFailure(book, err)
+				}
 			}
 			continue
 		}
@@ -821,6 +1399,8 @@ func (s *Service) processLibrary(ctx context.Context, library *audiobookshelf.Au
 
 // processBook processes a single book and updates its status in Hardcover
 func (s *Service) processBook(ctx context.Context, book models.AudiobookshelfBook, userProgress *models.AudiobookshelfUserProgress) error {
+	ctx = withDryRunBook(ctx, book)
+
 	// Create a logger with book context at the start of the function
 	bookTitle := book.Media.Metadata.Title
 	authorName := ""
@@ -838,6 +1418,8 @@ func (s *Service) processBook(ctx context.Context, book models.AudiobookshelfBoo
 	// Start with false, will be set to true when processing completes successfully
 	var bookProcessed bool
 	defer func() {
+		metrics.BookProcessed(s.metricsUser())
+
 		// Use the mutex to safely update the counters
 		s.summary.Lock()
 		defer s.summary.Unlock()
@@ -861,6 +1443,34 @@ func (s *Service) processBook(ctx context.Context, book models.AudiobookshelfBoo
 	}()
 
 	bookLog.Debug("Starting book processing")
+
+	// Blocked books are left alone entirely, so they never produce mismatches
+	if s.blocked(book) {
+		bookLog.Debug("Skipping book - it's in the blocklist", nil)
+		return nil
+	}
+
+	// Skip books untouched since the last successful sync before doing any per-book work
+	if s.unchangedSinceLastSync(book, userProgress) {
+		bookLog.Debug("Skipping book - unchanged since last successful sync", map[string]interface{}{
+			"last_changed": formatMillis(lastChangedAt(book, userProgress)),
+			"last_sync":    s.updatedSince.Format(time.RFC3339),
+		})
+		return nil
+	}
+
+	// Items the user has no progress for are handled according to the missing progress policy
+	if s.skipMissingProgress(book, userProgress) {
+		bookLog.Debug("Skipping book without progress data (missing_progress_policy is skip)", nil)
+		return nil
+	}
+
+	// Items the user hid or archived in Audiobookshelf aren't synced unless skip_archived is disabled
+	if s.skipArchived(book, userProgress) {
+		bookLog.Debug("Skipping book hidden or archived in Audiobookshelf (skip_archived is enabled)", nil)
+		return nil
+	}
+
 	// Mark as processed by default, will be set to false if there's an error
 	bookProcessed = true
 
@@ -874,6 +1484,13 @@ func (s *Service) processBook(ctx context.Context, book models.AudiobookshelfBoo
 		bookProcessed = false // not counted as synced
 		return ErrSkippedBook
 	}
+	if mediaType == "podcast" && s.config.Sync.SkipPodcasts {
+		bookLog.Info("Skipping podcast because skip_podcasts is enabled", map[string]interface{}{
+			"media_type": book.MediaType,
+		})
+		bookProcessed = false // not counted as synced
+		return ErrSkippedBook
+	}
 
 	// Track if we found the book in Hardcover
 
@@ -888,10 +1505,28 @@ func (s *Service) processBook(ctx context.Context, book models.AudiobookshelfBoo
 		}
 		bookLog.Debugf("Book matches test book filter, processing: %s", map[string]interface{}{
 			"filter":  s.config.Sync.TestBookFilter,
-			"dry_run": s.config.Sync.DryRun,
+			"dry_run": s.dryRun(ctx),
 		})
 	}
 
+	// Enhance book data with user progress if available, before the incremental check compares
+	// it with the stored progress
+	if userProgress != nil {
+		if source := s.applyUserProgress(&book, userProgress); source != "" {
+			bookLog = bookLog.With(map[string]interface{}{
+				"progress_source": source,
+				"is_finished":     book.Progress.IsFinished,
+			})
+
+			bookLog.Debug("Using enhanced progress from /api/me response", map[string]interface{}{
+				"current_time": book.Progress.CurrentTime,
+				"finished_at":  book.Progress.FinishedAt,
+			})
+		} else {
+			bookLog.Debug("No enhanced progress data found in /api/me response", nil)
+		}
+	}
+
 	// Early filtering for incremental sync - check if book needs syncing
 	if s.config.Sync.Incremental {
 		// Calculate current progress and status
@@ -899,7 +1534,7 @@ func (s *Service) processBook(ctx context.Context, book models.AudiobookshelfBoo
 		if book.Media.Duration > 0 { // Ensure duration is not zero to avoid division by zero
 			currentProgress = book.Progress.CurrentTime / book.Media.Duration
 		}
-		currentStatus := s.determineBookStatus(currentProgress, book.Progress.IsFinished, book.Progress.FinishedAt)
+		currentStatus := s.bookStatus(book, currentProgress)
 
 		// Create preliminary state key (we'll update it with edition ID later if found)
 		preliminaryStateKey := book.ID
@@ -911,7 +1546,15 @@ func (s *Service) processBook(ctx context.Context, book models.AudiobookshelfBoo
 			minChangeThreshold = float64(s.config.Sync.MinChangeThreshold) / book.Media.Duration
 		}
 
-		if !s.state.NeedsSync(preliminaryStateKey, currentProgress, currentStatus, minChangeThreshold) {
+		if s.alwaysReverifyStatus(currentStatus) {
+			bookLog.Debug("Processing book - its status is always re-verified", map[string]interface{}{
+				"current_status": currentStatus,
+			})
+		} else if s.pullsFromHardcover() {
+			bookLog.Debug("Processing book - its Hardcover progress may have changed", map[string]interface{}{
+				"direction": s.config.Sync.Direction,
+			})
+		} else if !s.state.NeedsSync(preliminaryStateKey, currentProgress, currentStatus, minChangeThreshold) {
 			bookLog.Debug("Skipping book - no significant changes since last sync", map[string]interface{}{
 				"current_progress": currentProgress,
 				"current_status":   currentStatus,
@@ -928,48 +1571,67 @@ func (s *Service) processBook(ctx context.Context, book models.AudiobookshelfBoo
 		})
 	}
 
+	// An unstarted book may still be read on Hardcover, see Sync.Direction. It's only looked up
+	// rather than matched, so it isn't added to the user's Hardcover library.
+	if book.Progress.CurrentTime <= 0 && !s.config.Sync.ProcessUnreadBooks && s.pullsFromHardcover() {
+		return s.pullUnstartedBook(ctx, book, bookLog)
+	}
+
 	// Declare variables at the top of the function to avoid redeclaration
 	var (
 		hcBook    *models.HardcoverBook
+		match     bookMatch
 		findErr   error
 		editionID string
 		stateKey  string
 	)
 
 	// Find the book in Hardcover to get the edition ID
-	hcBook, findErr = s.findBookInHardcover(ctx, book)
+	hcBook, match, findErr = s.findBookInHardcover(ctx, book)
+
+	// A book found without any editions is handled like one without a matching edition, under its
+	// own mismatch reason
+	noEditions := errors.Is(findErr, hardcover.ErrBookHasNoEditions) && hcBook != nil
+	// So is a book only matched by the ASIN of an edition in another reading format
+	otherFormat := errors.Is(findErr, errASINInOtherFormat) && hcBook != nil
+	if noEditions || otherFormat {
+		findErr = nil
+	}
 	if findErr != nil {
+		// The matched edition failed strict identifier verification
+		if errors.Is(findErr, errIdentifierMismatch) {
+			s.recordIdentifierMismatch(book, hcBook, findErr)
+			s.countSkipReason(SkipReasonIdentifierMismatch)
+			bookLog.Warn("Recorded identifier mismatch on matched edition, not syncing", map[string]interface{}{
+				"error": findErr.Error(),
+			})
+			bookProcessed = true
+			return nil
+		}
+
+		// No title/author search result was similar enough, or by the book's author, to suggest one
+		if errors.Is(findErr, errWeakTitleMatch) || errors.Is(findErr, errAuthorMismatch) {
+			s.recordTitleMatchRejected(book, findErr)
+			if errors.Is(findErr, errAuthorMismatch) {
+				s.countSkipReason(SkipReasonAuthorMismatch)
+			} else {
+				s.countSkipReason(SkipReasonWeakTitleMatch)
+			}
+			bookLog.Warn("Recorded mismatch without a title/author match, not syncing", map[string]interface{}{
+				"error": findErr.Error(),
+			})
+			bookProcessed = true
+			return nil
+		}
+
 		// Handle mismatch case (found by title/author)
 		if strings.Contains(findErr.Error(), "found by title/author only") {
 			// Try to find the book by title/author to get the Hardcover book details
 			hcBook, _ = s.findBookInHardcoverByTitleAuthor(ctx, book)
 			foundByTitleAuthor := hcBook != nil
 
-			// Build cover URL if cover path is available
-			coverURL := ""
-			if book.Media.CoverPath != "" {
-				coverURL = fmt.Sprintf("%s/api/items/%s/cover", s.config.Audiobookshelf.URL, book.ID)
-			}
-
-			// Create mismatch with both Audiobookshelf and Hardcover details
-			mismatchData := mismatch.BookMismatch{
-				BookID:          book.ID,
-				Title:           book.Media.Metadata.Title,
-				Subtitle:        book.Media.Metadata.Subtitle,
-				Author:          book.Media.Metadata.AuthorName,
-				Narrator:        book.Media.Metadata.NarratorName,
-				ASIN:            book.Media.Metadata.ASIN,
-				ISBN:            book.Media.Metadata.ISBN,
-				LibraryID:       book.LibraryID,
-				PublishedYear:   book.Media.Metadata.PublishedYear,
-				DurationSeconds: int(book.Media.Duration),
-				CoverURL:        coverURL,
-				Publisher:       book.Media.Metadata.Publisher,
-				Reason:          "Found by title/author only - manual verification required",
-				Timestamp:       time.Now().Unix(),
-				CreatedAt:       time.Now(),
-			}
-
+			// Create mismatch with the Audiobookshelf details, the Hardcover details are added below
+			mismatchData := s.bookMismatch(book, nil, "Found by title/author only - manual verification required")
 			// Add Hardcover book details if available
 			if foundByTitleAuthor && hcBook != nil {
 				// Hydrate Hardcover book with full details (including slug) before recording mismatch
@@ -1080,10 +1742,12 @@ func (s *Service) processBook(ctx context.Context, book models.AudiobookshelfBoo
 					PublishedYear: book.Media.Metadata.PublishedYear,
 					ISBN:          book.Media.Metadata.ISBN,
 					ASIN:          book.Media.Metadata.ASIN,
-					CoverURL:      coverURL,
+					CoverURL:      mismatchData.CoverURL,
 					Duration:      book.Media.Duration,
 					LibraryID:     book.LibraryID,
 					FolderID:      "",
+					BookOverride:  s.bookOverride(book),
+					Suggestion:    s.titleAuthorSuggestion(hcBook),
 				},
 				book.ID,
 				edID,
@@ -1093,6 +1757,8 @@ func (s *Service) processBook(ctx context.Context, book models.AudiobookshelfBoo
 				s.hardcover,
 			)
 			bookLog.Info("Book found by title/author - recorded as mismatch (with enrichment)")
+			s.countMatchSource(state.MatchSourceTitleAuthor)
+			s.countSkipReason(SkipReasonTitleAuthorOnly)
 
 			// Set the book as processed
 			bookProcessed = true
@@ -1100,6 +1766,21 @@ func (s *Service) processBook(ctx context.Context, book models.AudiobookshelfBoo
 			// Return early as we don't need to process this book further
 			return nil
 		} else {
+			// A rejected token says nothing about the book, so count it towards the run's
+			// authentication failures instead of recording the book as not found
+			if isAuthError(findErr) {
+				return findErr
+			}
+			// Neither does a lookup cut short by the book's deadline or the end of the run
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			// Newly published books may not be on Hardcover yet
+			if s.inNotFoundGracePeriod(book) {
+				return nil
+			}
+
 			// Record as not found
 			s.recordBookNotFound(book, findErr)
 			bookLog.Warn("Book not found in Hardcover", map[string]interface{}{
@@ -1111,16 +1792,33 @@ func (s *Service) processBook(ctx context.Context, book models.AudiobookshelfBoo
 	} else if hcBook != nil {
 		// Book was found successfully
 		bookProcessed = true
+		s.countMatchSource(match.Source)
+		s.state.ClearFirstSeen(book.ID)
+		s.noteCollectionMatch(book.ID, hcBook)
 		if hcBook.EditionID != "" {
 			editionID = hcBook.EditionID
 		}
+
+		// Ownership only mode marks the book as owned and leaves reads and status alone. The sync
+		// state isn't updated, so a later full sync still processes the book.
+		if s.config.Sync.OwnershipOnly {
+			s.markOwned(ctx, hcBook, bookLog)
+			return nil
+		}
 	}
 
 	// Create a composite key for state tracking: bookID:editionID
 	stateKey = book.ID
 	if editionID != "" {
 		stateKey = fmt.Sprintf("%s:%s", book.ID, editionID)
+		// Keep the book's history when its edition was just resolved, a rematched book is synced again
+		if s.state.MigrateBookKey(stateKey) {
+			bookLog.Debug("Migrated book state to the current edition", map[string]interface{}{
+				"state_key": stateKey,
+			})
+		}
 	}
+	s.recordMatch(stateKey, match, hcBook)
 
 	// Add edition info to log context
 	bookLog = bookLog.With(map[string]interface{}{
@@ -1144,8 +1842,8 @@ func (s *Service) processBook(ctx context.Context, book models.AudiobookshelfBoo
 		}
 
 		// Get the last sync state for this book using the composite key
-		bookState, exists := s.state.Books[stateKey]
-		if exists && !s.config.Sync.DryRun {
+		bookState, exists := s.state.GetBookState(stateKey)
+		if exists && !s.dryRun(ctx) {
 			// Normalize legacy percentage values stored in state if necessary
 			storedProgress := bookState.LastProgress
 			if storedProgress > 1.0 {
@@ -1156,7 +1854,7 @@ func (s *Service) processBook(ctx context.Context, book models.AudiobookshelfBoo
 			progressChanged := math.Abs(currentProgress-storedProgress) > 0.01
 
 			// Check if status has changed
-			currentStatus := s.determineBookStatus(currentProgress, book.Progress.IsFinished, book.Progress.FinishedAt)
+			currentStatus := s.bookStatus(book, currentProgress)
 			statusChanged := currentStatus != bookState.Status
 
 			// Check if there's any activity that would require an update
@@ -1168,8 +1866,9 @@ func (s *Service) processBook(ctx context.Context, book models.AudiobookshelfBoo
 			}
 			activityChanged := lastActivity > bookState.LastUpdated
 
-			// If nothing has changed, skip this book
-			if !progressChanged && !statusChanged && !activityChanged {
+			// If nothing has changed, skip this book unless its status is always re-verified or its
+			// Hardcover progress may have changed
+			if !progressChanged && !statusChanged && !activityChanged && !s.alwaysReverifyStatus(currentStatus) && !s.pullsFromHardcover() {
 				bookLog.Debug("Skipping unchanged book in incremental sync mode", map[string]interface{}{
 					"last_updated":     time.Unix(bookState.LastUpdated, 0).Format(time.RFC3339),
 					"last_progress":    bookState.LastProgress,
@@ -1193,97 +1892,11 @@ func (s *Service) processBook(ctx context.Context, book models.AudiobookshelfBoo
 		}
 	}
 
-	// Enhance book data with user progress if available
-	if userProgress != nil {
-		// Try to find matching progress in mediaProgress (most accurate source)
-		var bestProgress *struct {
-			ID            string  `json:"id"`
-			LibraryItemID string  `json:"libraryItemId"`
-			UserID        string  `json:"userId"`
-			IsFinished    bool    `json:"isFinished"`
-			Progress      float64 `json:"progress"`
-			CurrentTime   float64 `json:"currentTime"`
-			Duration      float64 `json:"duration"`
-			StartedAt     int64   `json:"startedAt"`
-			FinishedAt    int64   `json:"finishedAt"`
-			LastUpdate    int64   `json:"lastUpdate"`
-			TimeListening float64 `json:"timeListening"`
-		}
-
-		// Find the most recent progress entry for this book
-		for i := range userProgress.MediaProgress {
-			if userProgress.MediaProgress[i].LibraryItemID == book.ID {
-				if bestProgress == nil || userProgress.MediaProgress[i].LastUpdate > bestProgress.LastUpdate {
-					bestProgress = &userProgress.MediaProgress[i]
-				}
-			}
-		}
-
-		// If we found progress in mediaProgress, use it
-		if bestProgress != nil {
-			bookLog = bookLog.With(map[string]interface{}{
-				"has_media_progress": true,
-				"progress":           bestProgress.Progress,
-				"is_finished":        bestProgress.IsFinished,
-				"last_update":        bestProgress.LastUpdate,
-			})
-
-			// Update book progress with the most accurate data
-			book.Progress.CurrentTime = bestProgress.CurrentTime
-			book.Progress.IsFinished = bestProgress.IsFinished
-			book.Progress.FinishedAt = bestProgress.FinishedAt
-			book.Progress.StartedAt = bestProgress.StartedAt
-
-			bookLog.Debug("Using enhanced progress from media progress data", map[string]interface{}{
-				"current_time": book.Progress.CurrentTime,
-				"finished_at":  book.Progress.FinishedAt,
-			})
-		} else {
-			// Fall back to listening sessions if no media progress found
-			var bestSession *struct {
-				ID            string `json:"id"`
-				UserID        string `json:"userId"`
-				LibraryItemID string `json:"libraryItemId"`
-				MediaType     string `json:"mediaType"`
-				MediaMetadata struct {
-					Title  string `json:"title"`
-					Author string `json:"author"`
-				} `json:"mediaMetadata"`
-				Duration    float64 `json:"duration"`
-				CurrentTime float64 `json:"currentTime"`
-				Progress    float64 `json:"progress"`
-				IsFinished  bool    `json:"isFinished"`
-				StartedAt   int64   `json:"startedAt"`
-				UpdatedAt   int64   `json:"updatedAt"`
-			}
-
-			// Find the most recent listening session for this book
-			for i := range userProgress.ListeningSessions {
-				session := &userProgress.ListeningSessions[i]
-				if session.LibraryItemID == book.ID &&
-					(bestSession == nil || session.UpdatedAt > bestSession.UpdatedAt) {
-					bestSession = session
-				}
-			}
-
-			if bestSession != nil {
-				bookLog = bookLog.WithFields(map[string]interface{}{
-					"has_session_progress": true,
-					"session_progress":     bestSession.Progress,
-					"session_finished":     bestSession.IsFinished,
-					"session_updated":      bestSession.UpdatedAt,
-				})
-
-				book.Progress.CurrentTime = bestSession.CurrentTime
-				book.Progress.IsFinished = bestSession.IsFinished
-
-				bookLog.Debug("Using progress from listening session", map[string]interface{}{
-					"current_time": book.Progress.CurrentTime,
-				})
-			} else {
-				bookLog.Debug("No enhanced progress data found in /api/me response", nil)
-			}
-		}
+	// Progress slightly past the end is clamped to the duration; far past it the book is skipped
+	if !s.clampOverProgress(&book, bookLog) {
+		s.countSkipReason(SkipReasonOverDuration)
+		bookProcessed = false
+		return ErrSkippedBook
 	}
 
 	// Skip books that haven't been started unless ProcessUnreadBooks is true
@@ -1323,13 +1936,15 @@ func (s *Service) processBook(ctx context.Context, book models.AudiobookshelfBoo
 	}
 
 	// Determine the target status for the book after enhancing progress data
-	targetStatus := s.determineBookStatus(progress, book.Progress.IsFinished, book.Progress.FinishedAt)
+	targetStatus := s.bookStatus(book, progress)
 
 	// Log what we're going to do (regardless of dry-run)
 	action := "skip"
 	switch targetStatus {
 	case "FINISHED":
 		action = "mark as FINISHED"
+	case "DID_NOT_FINISH":
+		action = "mark as DID_NOT_FINISH"
 	case "IN_PROGRESS":
 		action = "update reading progress"
 	case "WANT_TO_READ":
@@ -1372,13 +1987,27 @@ func (s *Service) processBook(ctx context.Context, book models.AudiobookshelfBoo
 		"status":      status,
 	})
 
+	// Books already finished in Hardcover need no update unless they're being reread
+	if s.settledInHardcover(book, hcBook) {
+		bookLog.Debug("Skipping book already finished in Hardcover without newer activity", nil)
+		return nil
+	}
+
 	// Find the book in Hardcover
-	hcBook, findErr = s.findBookInHardcover(ctx, book)
+	hcBook, match, findErr = s.findBookInHardcover(ctx, book)
+	noEditions = errors.Is(findErr, hardcover.ErrBookHasNoEditions) && hcBook != nil
+	otherFormat = errors.Is(findErr, errASINInOtherFormat) && hcBook != nil
+	if noEditions || otherFormat {
+		findErr = nil
+	}
+
 	if findErr != nil {
 		errMsg := "error finding book in Hardcover"
-		bookLog.Error("Error finding book in Hardcover, skipping", map[string]interface{}{
-			"error": findErr,
-		})
+		if mismatch.ShouldLogDetail(fmt.Sprintf("%s: %v", errMsg, findErr)) {
+			bookLog.Error("Error finding book in Hardcover, skipping", map[string]interface{}{
+				"error": findErr,
+			})
+		}
 
 		// Build cover URL if cover path is available
 		coverURL := ""
@@ -1419,6 +2048,7 @@ func (s *Service) processBook(ctx context.Context, book models.AudiobookshelfBoo
 				Duration:      book.Media.Duration,
 				LibraryID:     book.LibraryID,
 				FolderID:      "",
+				BookOverride:  s.bookOverride(book),
 			},
 			bookID,    // Use the book ID if available
 			editionID, // Use the edition ID if available
@@ -1443,11 +2073,31 @@ func (s *Service) processBook(ctx context.Context, book models.AudiobookshelfBoo
 
 	// Check if book was found but has no edition ID
 	if hcBook != nil && hcBook.EditionID == "" {
+		progressPct := 0.0
+		if book.Media.Duration > 0 {
+			progressPct = (book.Progress.CurrentTime / book.Media.Duration) * 100
+		}
+
+		// Fall back to tracking the book without an edition if the user allows it
+		if s.trackBookLevel(ctx, hcBook, status, bookLog) {
+			if !s.dryRun(ctx) {
+				s.state.UpdateBook(stateKey, progressPct, status)
+			}
+			return nil
+		}
+
 		errMsg := "book found by title/author search but no edition ID available"
-		bookLog.Warn(errMsg, map[string]interface{}{
-			"book_id": hcBook.ID,
-			"title":   hcBook.Title,
-		})
+		if noEditions {
+			errMsg = hardcover.ErrBookHasNoEditions.Error()
+		} else if otherFormat {
+			errMsg = errASINInOtherFormat.Error()
+		}
+		if mismatch.ShouldLogDetail(errMsg) {
+			bookLog.Warn(errMsg, map[string]interface{}{
+				"book_id": hcBook.ID,
+				"title":   hcBook.Title,
+			})
+		}
 
 		// Build cover URL if cover path is available
 		coverURL := ""
@@ -1470,6 +2120,7 @@ func (s *Service) processBook(ctx context.Context, book models.AudiobookshelfBoo
 				Duration:      book.Media.Duration,
 				LibraryID:     book.LibraryID,
 				FolderID:      "",
+				BookOverride:  s.bookOverride(book),
 			},
 			hcBook.ID, // Use the book ID we found
 			"",        // No edition ID
@@ -1480,10 +2131,6 @@ func (s *Service) processBook(ctx context.Context, book models.AudiobookshelfBoo
 		)
 
 		// Update the state to track this book with current progress
-		progressPct := 0.0
-		if book.Media.Duration > 0 {
-			progressPct = (book.Progress.CurrentTime / book.Media.Duration) * 100
-		}
 		if updated := s.state.UpdateBook(stateKey, progressPct, "NO_EDITION"); updated {
 			bookLog.Debug("Updated book state to NO_EDITION", map[string]interface{}{
 				"progress": progressPct,
@@ -1493,17 +2140,24 @@ func (s *Service) processBook(ctx context.Context, book models.AudiobookshelfBoo
 	}
 
 	if hcBook == nil {
+		// Newly published books may not be on Hardcover yet
+		if s.inNotFoundGracePeriod(book) {
+			return nil
+		}
+
 		errMsg := "could not find book in Hardcover"
 		if book.Media.Metadata.Title == "" {
 			errMsg = "book title is empty, cannot search by title/author"
 		}
-		bookLog.Error(errMsg, map[string]interface{}{
-			"book_id": book.ID,
-			"title":   book.Media.Metadata.Title,
-			"author":  book.Media.Metadata.AuthorName,
-			"isbn":    book.Media.Metadata.ISBN,
-			"asin":    book.Media.Metadata.ASIN,
-		})
+		if mismatch.ShouldLogDetail(errMsg) {
+			bookLog.Error(errMsg, map[string]interface{}{
+				"book_id": book.ID,
+				"title":   book.Media.Metadata.Title,
+				"author":  book.Media.Metadata.AuthorName,
+				"isbn":    book.Media.Metadata.ISBN,
+				"asin":    book.Media.Metadata.ASIN,
+			})
+		}
 
 		// Build cover URL if cover path is available
 		coverURL := ""
@@ -1530,6 +2184,7 @@ func (s *Service) processBook(ctx context.Context, book models.AudiobookshelfBoo
 				Duration:      book.Media.Duration,
 				LibraryID:     book.LibraryID,
 				FolderID:      "",
+				BookOverride:  s.bookOverride(book),
 			},
 			bookID,    // Use the book ID if available
 			editionID, // Use the edition ID if available
@@ -1597,6 +2252,7 @@ func (s *Service) processBook(ctx context.Context, book models.AudiobookshelfBoo
 				Duration:      book.Media.Duration,
 				LibraryID:     book.LibraryID,
 				FolderID:      "",
+				BookOverride:  s.bookOverride(book),
 			},
 			bookID,    // Use the book ID from BookError if available
 			editionID, // Empty since we don't have an edition ID
@@ -1609,13 +2265,21 @@ func (s *Service) processBook(ctx context.Context, book models.AudiobookshelfBoo
 		return nil
 	}
 
+	// Optionally verify that the matched edition has the same narrator(s) as ABS.
+	// A mismatch is recorded for review but the sync proceeds with the matched edition.
+	s.checkNarratorMismatch(book, hcBook, match)
+
 	// Log the edition ID we're using
 	bookLog.Debug("Looking up or creating user book ID for edition", map[string]interface{}{
 		"edition_id": editionID,
 	})
 
 	// Find or create a user book ID for this edition with the determined status
-	userBookID, err := s.findOrCreateUserBookID(ctx, editionID, status)
+	userBookID, err := s.findOrCreateUserBookIDForBook(ctx, hcBook, editionID, status)
+	if errors.Is(err, errUnownedWantToRead) {
+		bookProcessed = true // Count as processed since we made a decision to skip
+		return nil
+	}
 	if err != nil {
 		bookLog.Error("Failed to get or create user book ID", map[string]interface{}{
 			"error":      err.Error(),
@@ -1638,6 +2302,28 @@ func (s *Service) processBook(ctx context.Context, book models.AudiobookshelfBoo
 		"user_book_id": userBookID,
 	})
 
+	// A book that isn't started in Audiobookshelf may be read on Hardcover, see Sync.Direction. A
+	// book finished in Audiobookshelf is never reset to the progress of an unfinished Hardcover read.
+	if status != "IN_PROGRESS" && status != "READING" && status != "FINISHED" {
+		if hardcoverWins, err := s.pullHardcoverProgress(ctx, book, userBookID, bookLog); hardcoverWins {
+			if err != nil {
+				return fmt.Errorf("error syncing Hardcover progress to Audiobookshelf: %w", err)
+			}
+			bookProcessed = true
+			return nil
+		}
+	}
+
+	// Syncing from Hardcover to Audiobookshelf only, the Audiobookshelf status isn't written to
+	// Hardcover either
+	if s.config.Sync.Direction == config.SyncDirectionHCToABS && status != "IN_PROGRESS" && status != "READING" {
+		bookLog.Debug("Not syncing the Audiobookshelf status to Hardcover in hc_to_abs mode", map[string]interface{}{
+			"status": status,
+		})
+		bookProcessed = true
+		return nil
+	}
+
 	// Handle progress update based on status
 	switch status {
 	case "FINISHED":
@@ -1673,6 +2359,21 @@ func (s *Service) processBook(ctx context.Context, book models.AudiobookshelfBoo
 		bookProcessed = true
 		return nil
 
+	case "DID_NOT_FINISH":
+		// Abandoned books keep their progress, but their read is never finished
+		bookLog.Info("Processing abandoned book", map[string]interface{}{
+			"status":   status,
+			"progress": progress,
+		})
+		if err := s.handleAbandonedBook(ctx, userBookID, book, stateKey); err != nil {
+			bookLog.Error("Failed to handle abandoned book", map[string]interface{}{
+				"error": err,
+			})
+			return fmt.Errorf("error handling abandoned book: %w", err)
+		}
+		bookLog.Info("Successfully processed abandoned book")
+		bookProcessed = true
+
 	default:
 		// For any other status, we still consider it processed successfully
 		bookProcessed = true
@@ -1712,6 +2413,33 @@ func (s *Service) createFinishedBookLogger(userBookID int64, editionID string, b
 	return s.log.With(fields)
 }
 
+// readExistsForDate reports whether Hardcover already has a read for the user book that was
+// started, finished or created on the given date (YYYY-MM-DD). With unfinished only reads that
+// aren't finished count, so a reread started on the day of a finished read is still created. This
+// makes read creation idempotent when a sync runs again before local state has been updated.
+// Lookup errors are logged and treated as "no existing read" so that sync is not blocked.
+func (s *Service) readExistsForDate(ctx context.Context, userBookID int64, date string, unfinished bool) bool {
+	if date == "" {
+		return false
+	}
+
+	existing, err := s.hardcover.CheckExistingUserBookRead(ctx, hardcover.CheckExistingUserBookReadInput{
+		UserBookID: int(userBookID),
+		Date:       date,
+		Unfinished: unfinished,
+	})
+	if err != nil {
+		s.log.Warn("Failed to check for existing read, proceeding with read creation", map[string]interface{}{
+			"user_book_id": userBookID,
+			"date":         date,
+			"error":        err.Error(),
+		})
+		return false
+	}
+
+	return existing != nil
+}
+
 // HandleFinishedBook processes a book that has been marked as finished
 func (s *Service) HandleFinishedBook(ctx context.Context, book models.AudiobookshelfBook, editionID string, userBookID int64) error {
 	// Create a logger with context
@@ -1739,6 +2467,18 @@ func (s *Service) HandleFinishedBook(ctx context.Context, book models.Audiobooks
 		}
 	}()
 
+	if s.dryRun(ctx) {
+		log.Info("[DRY-RUN] Would mark book as FINISHED", nil)
+		s.planAction(ctx, PlannedAction{
+			Action:                PlannedActionMarkFinished,
+			EditionID:             editionID,
+			UserBookID:            plannedUserBookID(userBookID),
+			Status:                "FINISHED",
+			TargetProgressSeconds: int(math.Round(book.Progress.CurrentTime)),
+		})
+		return nil
+	}
+
 	// First, check the current status of the book and update to FINISHED if needed
 	log.Info("Checking current book status", map[string]interface{}{
 		"user_book_id": userBookID,
@@ -1768,7 +2508,11 @@ func (s *Service) HandleFinishedBook(ctx context.Context, book models.Audiobooks
 			})
 		}
 	}
-	if getUserBookErr != nil {
+	if s.config.Sync.ProgressOnly {
+		log.Debug("Progress only mode, not updating book status to FINISHED", map[string]interface{}{
+			"user_book_id": userBookID,
+		})
+	} else if getUserBookErr != nil {
 		log.Warn("Failed to get current book status, will attempt to update anyway", map[string]interface{}{
 			"error": getUserBookErr,
 		})
@@ -1843,6 +2587,7 @@ func (s *Service) HandleFinishedBook(ctx context.Context, book models.Audiobooks
 	log.Info("Received read statuses from Hardcover", map[string]interface{}{
 		"count": len(readStatuses),
 	})
+	readStatuses = s.mergeOverlappingReads(ctx, readStatuses, log)
 
 	// Look for the most recent unfinished read and check for existing finished reads today
 	var latestUnfinishedRead *hardcover.UserBookRead
@@ -1969,12 +2714,12 @@ func (s *Service) HandleFinishedBook(ctx context.Context, book models.Audiobooks
 				"progress":    progress, // Always set to 100% when marking as finished
 			}
 
-			// Add progress_seconds if available, otherwise use book's duration
-			if latestUnfinishedRead.ProgressSeconds != nil {
+			// Set progress_seconds as configured by finished_progress_handling, keeping the read's
+			// progress when the book's isn't known
+			if progressSeconds, ok := s.finishedProgressSeconds(book); ok {
+				updateObj["progress_seconds"] = progressSeconds
+			} else if latestUnfinishedRead.ProgressSeconds != nil {
 				updateObj["progress_seconds"] = *latestUnfinishedRead.ProgressSeconds
-			} else if book.Media.Duration > 0 {
-				durationInt := int(book.Media.Duration)
-				updateObj["progress_seconds"] = durationInt
 			}
 
 			// Preserve started_at if it exists, or use ABS started_at
@@ -2050,38 +2795,35 @@ func (s *Service) HandleFinishedBook(ctx context.Context, book models.Audiobooks
 			startedAt = finishedAt // Use finished date as fallback if no started date
 		}
 
-		// If we have progress from the book, use it
-		var progressSeconds *int
-
-		if book.Progress.CurrentTime > 0 {
-			seconds := int(book.Progress.CurrentTime)
-			progressSeconds = &seconds
-		}
-
 		// Convert editionID to int64
 		editionIDInt, _ := strconv.ParseInt(editionID, 10, 64)
 
-		// Set progress to 100% when creating a new finished read
-		// We'll use progress_seconds to set the progress
-		var finalProgressSeconds int
-		if progressSeconds != nil {
-			finalProgressSeconds = *progressSeconds
-		} else if book.Media.Duration > 0 {
-			// If no progress seconds but we have duration, use that
-			finalProgressSeconds = int(book.Media.Duration)
-		} else {
+		// Set progress_seconds as configured by finished_progress_handling
+		finalProgressSeconds, ok := s.finishedProgressSeconds(book)
+		if !ok {
 			// Default to a reasonable value if we have no other info
 			finalProgressSeconds = 3600 // 1 hour as fallback
 		}
 
+		// Skip creation if a read already exists for this finished date
+		if s.readExistsForDate(ctx, userBookID, finishedAt, false) {
+			log.Info("Read already exists for finished date, skipping read record creation", map[string]interface{}{
+				"finished_at": finishedAt,
+			})
+			success = true
+			return nil
+		}
+
 		// Create the read record using the proper input type
+		readingFormatID := s.readingFormatID(ctx, book)
 		_, err = s.hardcover.InsertUserBookRead(ctx, hardcover.InsertUserBookReadInput{
 			UserBookID: userBookID,
 			DatesRead: hardcover.DatesReadInput{
 				FinishedAt:      &finishedAt,
 				StartedAt:       &startedAt,
-				ProgressSeconds: &finalProgressSeconds, // This will effectively set progress to 100%
+				ProgressSeconds: &finalProgressSeconds,
 				EditionID:       &editionIDInt,
+				ReadingFormatID: &readingFormatID,
 			},
 		})
 
@@ -2198,14 +2940,29 @@ func (s *Service) handleInProgressBook(ctx context.Context, userBookID int64, bo
 	// Create logger with all context
 	log = s.log.With(logCtx)
 
+	// The Hardcover progress may win over Audiobookshelf's, see Sync.Direction
+	if hardcoverWins, err := s.syncProgressToAudiobookshelf(ctx, book, userBookID, readStatuses, log); hardcoverWins {
+		return err
+	}
+
 	// In dry-run mode, log that we're in dry-run and continue with checks
-	if s.config.Sync.DryRun {
+	if s.dryRun(ctx) {
 		logCtx["action"] = "dry_run_skipped"
 		logCtx["reason"] = "dry-run mode is enabled"
 		s.log.With(logCtx).Info("Dry-run mode: skipping update", nil)
+		s.planAction(ctx, PlannedAction{
+			Action:                 PlannedActionUpdateProgress,
+			EditionID:              editionIDFromStateKey(stateKey),
+			UserBookID:             plannedUserBookID(userBookID),
+			Status:                 "IN_PROGRESS",
+			CurrentProgressSeconds: unfinishedReadProgress(readStatuses),
+			TargetProgressSeconds:  int(math.Round(book.Progress.CurrentTime)),
+		})
 		return nil
 	}
 
+	readStatuses = s.mergeOverlappingReads(ctx, readStatuses, log)
+
 	// Log the current progress from Audiobookshelf
 	dbgCtx := make(map[string]interface{}, len(logCtx)+4)
 	for k, v := range logCtx {
@@ -2229,9 +2986,10 @@ func (s *Service) handleInProgressBook(ctx context.Context, userBookID int64, bo
 	lastUpdate, exists := s.lastProgressUpdates[bookCacheKey]
 	s.lastProgressMutex.RUnlock()
 
-	// If we've updated this book in the last 5 minutes and the progress is very similar (within 5 seconds),
-	// skip the update to prevent unnecessary API calls
-	if exists && time.Since(lastUpdate.timestamp) < 5*time.Minute {
+	// If we've updated this book within the progress cache TTL, or an earlier run persisted its
+	// progress, and the progress is very similar (within 5 seconds), skip the update to prevent
+	// unnecessary API calls
+	if exists && (lastUpdate.persisted || time.Since(lastUpdate.timestamp) < s.progressCacheTTL()) {
 		progressDiff := math.Abs(book.Progress.CurrentTime - lastUpdate.progress)
 		if progressDiff < 5.0 {
 			logCtx["last_update_time"] = lastUpdate.timestamp
@@ -2326,7 +3084,7 @@ func (s *Service) handleInProgressBook(ctx context.Context, userBookID int64, bo
 		log.Warn(fmt.Sprintf("Found %d duplicate unfinished read entries, will clean up", len(duplicateUnfinishedReads)), nil)
 
 		// We'll only delete the duplicates if we're not in dry-run mode
-		if !s.config.Sync.DryRun {
+		if !s.dryRun(ctx) {
 			for _, duplicateRead := range duplicateUnfinishedReads {
 				log.Info("Marking duplicate read entry as deleted", map[string]interface{}{
 					"read_id": duplicateRead.ID,
@@ -2430,7 +3188,7 @@ func (s *Service) handleInProgressBook(ctx context.Context, userBookID int64, bo
 				}
 			}
 			// If we found duplicates now, clean them up just like in the first pass
-			if len(duplicateUnfinishedReads) > 0 && !s.config.Sync.DryRun {
+			if len(duplicateUnfinishedReads) > 0 && !s.dryRun(ctx) {
 				for _, duplicateRead := range duplicateUnfinishedReads {
 					log.Info("Marking duplicate read entry as deleted (second-chance)", map[string]interface{}{
 						"read_id": duplicateRead.ID,
@@ -2557,6 +3315,13 @@ func (s *Service) handleInProgressBook(ctx context.Context, userBookID int64, bo
 				return nil
 			}
 
+			// Progress cached by an earlier run mustn't overwrite a newer read
+			if s.cachedProgressBehindRead(book, readStatusToUpdate, hcProgressSeconds) {
+				logCtx["progress_cached_at"] = s.userProgressCachedAt.Format(time.RFC3339)
+				log.Info("Skipping update - the cached Audiobookshelf progress is older than the Hardcover read", logCtx)
+				return nil
+			}
+
 			// Warn about extremely large progress differences (> 1 hour)
 			if progressDiff > 3600 {
 				logCtx["progress_diff_hours"] = fmt.Sprintf("%.2f", progressDiff/3600)
@@ -2565,8 +3330,16 @@ func (s *Service) handleInProgressBook(ctx context.Context, userBookID int64, bo
 				log.Warn("Extremely large progress difference detected. Possible book mapping or sync issue.", logCtx)
 			}
 
+			// Refuse progress jumps beyond the configured cap rather than risk writing a bad edition's progress
+			if s.isSuspiciousProgressJump(progressDiff) {
+				logCtx["max_progress_jump_seconds"] = s.config.Sync.MaxProgressJumpSeconds
+				log.Warn("Refusing suspicious progress jump, recording mismatch instead of updating", logCtx)
+				s.recordSuspiciousProgressJump(book, hcBook, hcProgressSeconds, progressDiff)
+				return nil
+			}
+
 			// Store the last update time and progress for this book to prevent frequent updates
-			// This is a memory-only cache that will be reset when the service restarts
+			// This cache only survives restarts when Sync.PersistProgressCache is enabled
 			bookCacheKey := fmt.Sprintf("%s:%d", book.ID, userBookID)
 			s.lastProgressMutex.Lock()
 			s.lastProgressUpdates[bookCacheKey] = progressUpdateInfo{
@@ -2599,7 +3372,7 @@ func (s *Service) handleInProgressBook(ctx context.Context, userBookID int64, bo
 	// Prepare the update object with progress and format
 	updateObj := map[string]interface{}{
 		"progress_seconds":  int64(book.Progress.CurrentTime),
-		"reading_format_id": 2, // 2 = Audiobook format
+		"reading_format_id": s.readingFormatID(ctx, book),
 	}
 
 	// Format dates as YYYY-MM-DD strings
@@ -2715,7 +3488,9 @@ func (s *Service) handleInProgressBook(ctx context.Context, userBookID int64, bo
 		log.Info("Successfully updated read status in Hardcover", logCtx)
 
 		// Update book status based on progress
-		if hcBook != nil {
+		if s.config.Sync.ProgressOnly {
+			log.Debug("Progress only mode, not updating book status", logCtx)
+		} else if hcBook != nil {
 			// If the book is marked as finished in ABS but not in Hardcover, update status
 			if book.Progress.IsFinished && !isFinishedInHC {
 				log.Debug("Updating book status to COMPLETED", logCtx)
@@ -2755,8 +3530,10 @@ func (s *Service) handleInProgressBook(ctx context.Context, userBookID int64, bo
 	} else {
 		// Create a new read status since none exists
 		progressSeconds := int(book.Progress.CurrentTime)
+		readingFormatID := s.readingFormatID(ctx, book)
 		createObj := hardcover.DatesReadInput{
 			ProgressSeconds: &progressSeconds,
+			ReadingFormatID: &readingFormatID,
 		}
 
 		// Add dates if available
@@ -2803,7 +3580,7 @@ func (s *Service) handleInProgressBook(ctx context.Context, userBookID int64, bo
 
 		// Set status to IN_PROGRESS before attempting insert so any server-side
 		// auto-created unfinished read becomes visible to the next fetch
-		if !isFinishedInHC {
+		if !isFinishedInHC && !s.config.Sync.ProgressOnly {
 			if err := s.hardcover.UpdateUserBookStatus(ctx, hardcover.UpdateUserBookStatusInput{
 				ID:       userBookID,
 				StatusID: 2, // Currently Reading
@@ -2833,7 +3610,7 @@ func (s *Service) handleInProgressBook(ctx context.Context, userBookID int64, bo
 				// Build update object
 				updateObj := map[string]interface{}{
 					"progress_seconds":  int64(book.Progress.CurrentTime),
-					"reading_format_id": 2,
+					"reading_format_id": s.readingFormatID(ctx, book),
 				}
 				if book.Progress.StartedAt > 0 {
 					startedAt := time.Unix(book.Progress.StartedAt/1000, 0).Format("2006-01-02")
@@ -2887,6 +3664,21 @@ func (s *Service) handleInProgressBook(ctx context.Context, userBookID int64, bo
 			}
 		}
 
+		// Skip creation if a read already exists for the date this read is keyed by. Finished reads
+		// don't count for an unfinished one, which may be a reread started on the same day.
+		readDate := time.Now().Format("2006-01-02")
+		if createObj.FinishedAt != nil {
+			readDate = *createObj.FinishedAt
+		} else if createObj.StartedAt != nil {
+			readDate = *createObj.StartedAt
+		}
+		if s.readExistsForDate(ctx, userBookID, readDate, createObj.FinishedAt == nil) {
+			log.Info("Read already exists for date, skipping read status creation", map[string]interface{}{
+				"date": readDate,
+			})
+			return nil
+		}
+
 		// Per-run guard to avoid duplicate inserts for the same userBookID
 		s.createdReadsMutex.Lock()
 		if _, exists := s.createdReadsThisRun[userBookID]; exists {
@@ -2936,6 +3728,11 @@ func (s *Service) determineBookStatus(progress float64, isFinished bool, finishe
 		return "FINISHED"
 	}
 
+	// Books stopped just short of the end, e.g. before the end credits, are finished too
+	if threshold := s.config.Sync.FinishedThreshold; threshold > 0 && progress >= threshold {
+		return "FINISHED"
+	}
+
 	// If there's some progress but not finished, consider it in progress
 	if progress > 0 {
 		return "IN_PROGRESS"
@@ -2970,59 +3767,9 @@ func (s *Service) processFoundBook(ctx context.Context, hcBook *models.Hardcover
 	}
 	log := s.log.With(logCtx)
 
-	// Mark book as owned if sync_owned is enabled
-	if s.config.Sync.SyncOwned && hcBook != nil && hcBook.EditionID != "" && hcBook.EditionID != "0" {
-		editionID, err := strconv.Atoi(hcBook.EditionID)
-		if err != nil {
-			log.Warn("Invalid edition ID format for marking as owned", map[string]interface{}{
-				"edition_id": hcBook.EditionID,
-				"error":      err.Error(),
-			})
-		} else {
-			// Check if book is already marked as owned using Hardcover BOOK ID (client queries by book_id)
-			bookIDInt, err := strconv.Atoi(hcBook.ID)
-			if err != nil {
-				log.Warn("Invalid book ID format for ownership check", map[string]interface{}{
-					"book_id": hcBook.ID,
-					"error":   err.Error(),
-				})
-			} else {
-				isOwned, err := s.hardcover.CheckBookOwnership(ctx, bookIDInt)
-				if err != nil {
-					log.Warn("Failed to check book ownership status", map[string]interface{}{
-						"book_id":    bookIDInt,
-						"edition_id": editionID,
-						"error":      err.Error(),
-					})
-				} else if !isOwned {
-					// Respect DryRun: only log what would happen
-					if s.config.Sync.DryRun {
-						log.Info("[DRY-RUN] Would mark edition as owned", map[string]interface{}{
-							"book_id":    bookIDInt,
-							"edition_id": editionID,
-						})
-					} else {
-						err = s.hardcover.MarkEditionAsOwned(ctx, editionID)
-						if err != nil {
-							log.Warn("Failed to mark edition as owned", map[string]interface{}{
-								"edition_id": editionID,
-								"error":      err.Error(),
-							})
-						} else {
-							log.Info("Successfully marked edition as owned", map[string]interface{}{
-								"book_id":    bookIDInt,
-								"edition_id": editionID,
-							})
-						}
-					}
-				} else {
-					log.Debug("Book is already marked as owned", map[string]interface{}{
-						"book_id":    bookIDInt,
-						"edition_id": editionID,
-					})
-				}
-			}
-		}
+	// Mark book as owned if sync_owned is enabled; in ownership only mode processBook does this
+	if s.config.Sync.SyncOwned && !s.config.Sync.OwnershipOnly {
+		s.markOwned(ctx, hcBook, log)
 	}
 
 	// If we don't have an edition ID but have a book ID, try to get the first edition
@@ -3057,12 +3804,14 @@ func (s *Service) processFoundBook(ctx context.Context, hcBook *models.Hardcover
 	}
 
 	// Determine the status based on progress and isFinished flag
-	status := s.determineBookStatus(progress, isFinished, finishedAt)
+	status := s.bookStatus(book, progress)
 
 	// Only try to get/create user book ID if we have a valid edition ID
 	if hcBook.EditionID != "" && hcBook.EditionID != "0" {
-		userBookID, err := s.findOrCreateUserBookID(ctx, hcBook.EditionID, status)
-		if err != nil {
+		userBookID, err := s.findOrCreateUserBookIDForMatch(ctx, book, hcBook, hcBook.EditionID, status)
+		if userBookSkipped(err) {
+			// Kept off the Want to Read shelf or ownership only, processBook takes care of the book
+		} else if err != nil {
 			fields := map[string]interface{}{
 				"edition_id": hcBook.EditionID,
 				"error":      err.Error(),
@@ -3070,6 +3819,7 @@ func (s *Service) processFoundBook(ctx context.Context, hcBook *models.Hardcover
 			log.Warn("Failed to get or create user book ID", fields)
 		} else {
 			hcBook.UserBookID = strconv.FormatInt(userBookID, 10)
+			s.applySourceTag(ctx, hcBook)
 		}
 	} else {
 		log.Warn("Skipping user book ID creation: no valid edition ID available", nil)
@@ -3204,6 +3954,23 @@ func (s *Service) findBookInHardcoverByTitleAuthor(ctx context.Context, book mod
 		"query": searchQuery,
 	})
 
+	// Searches of earlier runs aren't repeated until their outcome expires
+	if bookID, cached := s.titleSearchCache.Get(title, author); cached {
+		if bookID == "" {
+			log.Info("No books found matching search query in an earlier run, not searching again", nil)
+			return nil, fmt.Errorf("no books found matching search query: %s", searchQuery)
+		}
+		if cachedBook, err := s.hardcover.GetBookByID(ctx, bookID); err == nil && cachedBook != nil {
+			log.Info("Found book by title/author search of an earlier run", map[string]interface{}{
+				"book_id": cachedBook.ID,
+				"title":   cachedBook.Title,
+			})
+			return cachedBook, fmt.Errorf("found by title/author only")
+		}
+		// The book may have been deleted or merged since, so search again
+		s.titleSearchCache.Delete(title, author)
+	}
+
 	// Search for books using the search API
 	searchResults, err := s.hardcover.SearchBooks(ctx, searchQuery, "")
 	if err != nil {
@@ -3215,16 +3982,18 @@ func (s *Service) findBookInHardcoverByTitleAuthor(ctx context.Context, book mod
 
 	if len(searchResults) == 0 {
 		log.Info("No books found matching search query", nil)
+		s.titleSearchCache.Set(title, author, "")
 		return nil, fmt.Errorf("no books found matching search query: %s", searchQuery)
 	}
 
-	// Check if the original title contains "summary" to avoid filtering in that case
-	titleHasSummary := strings.Contains(strings.ToLower(title), "summary")
+	// Patterns for results that should be skipped (e.g. "summary" books)
+	excludePatterns := s.compileTitlePatterns()
 
 	// Get the best result based on filtering and similarity scoring
 	var bestMatch *models.HardcoverBook
 	var highestScore float64
 	var matchDetails []string
+	var candidates []titleMatchCandidate
 
 	// Log number of results found
 	log.Info("Search returned multiple results, will apply filtering and scoring", map[string]interface{}{
@@ -3234,15 +4003,13 @@ func (s *Service) findBookInHardcoverByTitleAuthor(ctx context.Context, book mod
 	// Calculate scores for each result and find the best match
 	for _, result := range searchResults {
 		resultTitle := result.Title
-		resultLower := strings.ToLower(resultTitle)
 
-		// Skip results that contain "Summary" if original doesn't (summary books are less relevant)
-		if !titleHasSummary && (strings.Contains(resultLower, "summary of") ||
-			strings.Contains(resultLower, "summary:") ||
-			strings.HasPrefix(resultLower, "summary ")) {
-			log.Debug("Skipping summary book", map[string]interface{}{
-				"title": resultTitle,
-				"id":    result.ID,
+		// Skip results matching an exclusion pattern the original title doesn't match
+		if pattern := matchExcludedTitle(excludePatterns, title, resultTitle); pattern != "" {
+			log.Debug("Skipping search result matching title exclusion pattern", map[string]interface{}{
+				"title":   resultTitle,
+				"id":      result.ID,
+				"pattern": pattern,
 			})
 			continue
 		}
@@ -3262,22 +4029,25 @@ func (s *Service) findBookInHardcoverByTitleAuthor(ctx context.Context, book mod
 		matchDetails = append(matchDetails, fmt.Sprintf("%s (ID: %s, Score: %.2f)",
 			resultTitle, result.ID, score))
 
+		candidate := &models.HardcoverBook{
+			ID:           result.ID,
+			Title:        resultTitle,
+			BookStatusID: 0, // Will be set when we get the full book details
+			// Include additional details from search result
+			Authors:       result.Authors,
+			Publisher:     result.Publisher,
+			ReleaseDate:   result.ReleaseDate,
+			CoverImageURL: result.CoverImageURL,
+			ASIN:          result.ASIN,
+			EditionISBN13: result.EditionISBN13,
+			EditionISBN10: result.EditionISBN10,
+		}
+		candidates = append(candidates, titleMatchCandidate{book: candidate, score: score})
+
 		// Update best match if score is higher
 		if score > highestScore {
 			highestScore = score
-			bestMatch = &models.HardcoverBook{
-				ID:           result.ID,
-				Title:        resultTitle,
-				BookStatusID: 0, // Will be set when we get the full book details
-				// Include additional details from search result
-				Authors:       result.Authors,
-				Publisher:     result.Publisher,
-				ReleaseDate:   result.ReleaseDate,
-				CoverImageURL: result.CoverImageURL,
-				ASIN:          result.ASIN,
-				EditionISBN13: result.EditionISBN13,
-				EditionISBN10: result.EditionISBN10,
-			}
+			bestMatch = candidate
 		}
 	}
 
@@ -3286,6 +4056,21 @@ func (s *Service) findBookInHardcoverByTitleAuthor(ctx context.Context, book mod
 		"matches": matchDetails,
 	})
 
+	// A best result below the threshold is most likely a different book sharing a few words with
+	// the title, so none is selected
+	if threshold := s.config.Sync.TitleMatchThreshold; threshold > 0 && highestScore < threshold {
+		fields := map[string]interface{}{
+			"match_score": highestScore,
+			"threshold":   threshold,
+		}
+		if bestMatch != nil {
+			fields["match_title"] = bestMatch.Title
+			fields["book_id"] = bestMatch.ID
+		}
+		log.Warn("No search result is similar enough to the title, not selecting one", fields)
+		return nil, weakTitleMatchError(bestMatch, highestScore, threshold)
+	}
+
 	// If no match found after filtering, fall back to first result
 	if bestMatch == nil && len(searchResults) > 0 {
 		log.Warn("No best match found after filtering, falling back to first result", nil)
@@ -3305,6 +4090,14 @@ func (s *Service) findBookInHardcoverByTitleAuthor(ctx context.Context, book mod
 		}
 	}
 
+	// Books sharing a title are told apart by their authors
+	if author != "" && len(candidates) > 0 {
+		bestMatch, highestScore, err = s.selectCandidateByAuthor(ctx, candidates, author, log)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Add best match details to logger
 	// Safety check before dereferencing bestMatch to satisfy staticcheck
 	if bestMatch == nil {
@@ -3359,22 +4152,18 @@ func (s *Service) findBookInHardcoverByTitleAuthor(ctx context.Context, book mod
 		}
 	}
 
+	s.titleSearchCache.Set(title, author, bestMatch.ID)
+
 	// Return the book data we have from search results
 	return bestMatch, fmt.Errorf("found by title/author only")
 }
 
-// findBookInHardcover finds a book in Hardcover by various methods
+// findBookInHardcover finds a book in Hardcover by various methods, and returns how it was matched
 // It first tries ASIN, then ISBN-13, then ISBN-10
 // Title/author search is only used for mismatches and should be called separately
-func (s *Service) findBookInHardcover(ctx context.Context, book models.AudiobookshelfBook) (*models.HardcoverBook, error) {
-	// Derive desired reading format from source media type
-	mediaType := strings.ToLower(strings.TrimSpace(book.MediaType))
-	desiredFormat := "audiobook"
-	if mediaType == "ebook" {
-		desiredFormat = "ebook"
-	}
-	// Attach to context for client to respect
-	ctx = hardcover.WithReadingFormat(ctx, desiredFormat)
+func (s *Service) findBookInHardcover(ctx context.Context, book models.AudiobookshelfBook) (*models.HardcoverBook, bookMatch, error) {
+	// Attach the desired reading format, derived from the source media type, for the client to respect
+	ctx = hardcover.WithReadingFormat(ctx, s.editionFormat(ctx, book))
 	// Create a logger with book context
 	logCtx := map[string]interface{}{
 		"book_id": book.ID,
@@ -3392,6 +4181,89 @@ func (s *Service) findBookInHardcover(ctx context.Context, book models.Audiobook
 
 	log := s.log.With(logCtx)
 
+	// 0. Use the edition or Hardcover book the item is pinned to, if any
+	mappingMatch := bookMatch{Source: state.MatchSourceMapping, Score: 1}
+	if hcBook, done, err := s.findBookByEditionOverride(ctx, book, log); done {
+		return hcBook, mappingMatch, err
+	}
+	if hcBook, done, err := s.findBookByOverride(ctx, book, log); done {
+		return hcBook, mappingMatch, err
+	}
+
+	// 1-2. Look the book up by its ASIN and ISBN, leaving out an untrusted one next to a trusted one
+	lookupBook := s.withoutUntrustedIdentifiers(book, log)
+	if ids := models.ExtractIdentifiers(lookupBook.Media.Metadata); ids.Conflicting() {
+		if hcBook, match, done, err := s.findBookByConflictingIdentifiers(ctx, lookupBook, ids); done {
+			return hcBook, match, err
+		}
+	} else if hcBook, match, done, err := s.findBookInHardcoverByIdentifiers(ctx, lookupBook, log); done {
+		return hcBook, match, err
+	}
+	// Retry a book only known by an ISBN Hardcover doesn't have by the ASIN of that ISBN
+	if hcBook, match, done, err := s.findBookByISBNToASIN(ctx, lookupBook, log); done {
+		return hcBook, match, err
+	}
+	// Only then settle for the ASIN of an edition in another reading format
+	if hcBook, found := s.findBookByASINInOtherFormat(ctx, lookupBook, log); found {
+		return hcBook, bookMatch{Source: state.MatchSourceASIN, Score: 1}, errASINInOtherFormat
+	}
+
+	// 3. If we get here, we couldn't find the book by ASIN or ISBN, try title/author search
+	if book.Media.Metadata.Title != "" && book.Media.Metadata.AuthorName != "" {
+		log.Info("Trying title/author search after ASIN/ISBN search failed", map[string]interface{}{
+			"search_method": "title_author",
+			"title":         book.Media.Metadata.Title,
+			"author":        book.Media.Metadata.AuthorName,
+		})
+
+		hcBook, err := s.findBookInHardcoverByTitleAuthor(ctx, book)
+		if err != nil {
+			log.Warn("Title/author search failed or edition not found", map[string]interface{}{
+				"search_method": "title_author",
+				"title":         book.Media.Metadata.Title,
+				"author":        book.Media.Metadata.AuthorName,
+				"error":         err.Error(),
+			})
+			// Return the error to be handled as a mismatch
+			return nil, bookMatch{}, fmt.Errorf("book found but edition not available: %w", err)
+		}
+
+		// If we get here, we found a book by title/author - this is a mismatch case
+		log.Info("Book found by title/author search - will be treated as mismatch", map[string]interface{}{
+			"book_id": hcBook.ID,
+			"title":   hcBook.Title,
+		})
+		match := bookMatch{
+			Source: state.MatchSourceTitleAuthor,
+			Score:  calculateTitleSimilarity(book.Media.Metadata.Title, hcBook.Title),
+		}
+		return hcBook, match, fmt.Errorf("found by title/author only")
+
+		// Unreachable code removed; mismatch is already indicated by the return above
+	}
+
+	log.Warn("Book not found in Hardcover by any search method", map[string]interface{}{
+		"book_id": book.ID,
+		"title":   book.Media.Metadata.Title,
+		"author":  book.Media.Metadata.AuthorName,
+		"isbn":    book.Media.Metadata.ISBN,
+		"asin":    book.Media.Metadata.ASIN,
+	})
+
+	// Return a specific error that indicates this is a potential mismatch
+	return nil, bookMatch{}, fmt.Errorf("book not found by ASIN/ISBN or title/author, potential mismatch")
+}
+
+// findBookInHardcoverByIdentifiers looks the book up by its ASIN, then by its ISBN, returning the
+// identifier that found it. The bool reports whether the lookup decided the result; when it's false
+// the book wasn't found by either identifier.
+func (s *Service) findBookInHardcoverByIdentifiers(ctx context.Context, book models.AudiobookshelfBook, log *logger.Logger) (*models.HardcoverBook, bookMatch, bool, error) {
+	// A book found without any editions is only reported when no other identifier matches
+	var noEditionsErr error
+	var noEditionsMatch bookMatch
+	asinMatch := bookMatch{Source: state.MatchSourceASIN, Score: 1}
+	isbnMatch := bookMatch{Source: state.MatchSourceISBN, Score: 1}
+
 	// 1. First try to find by ASIN if available
 	if book.Media.Metadata.ASIN != "" {
 		// Check ASIN cache first
@@ -3411,59 +4283,66 @@ func (s *Service) findBookInHardcover(ctx context.Context, book models.Audiobook
 
 				// Create a copy of the cached book to avoid modifying the cached version
 				hcBook := &models.HardcoverBook{
-					ID:        cachedBook.ID,
-					Title:     cachedBook.Title,
-					EditionID: cachedBook.EditionID,
+					ID:          cachedBook.ID,
+					Title:       cachedBook.Title,
+					EditionID:   cachedBook.EditionID,
+					EditionASIN: cachedBook.EditionASIN,
+					Narrators:   cachedBook.Narrators,
 					// Copy other fields as needed
 				}
 
+				if err := s.verifyMatchedEditionASIN(book, hcBook); err != nil {
+					log.Warn("Cached edition doesn't carry the book's ASIN, not syncing", map[string]interface{}{
+						"error": err.Error(),
+					})
+					return hcBook, asinMatch, true, err
+				}
+
 				// Still need to get/create user book ID for this specific book
 				editionIDStr := hcBook.EditionID
 				progress := 0.0
-				isFinished := book.Progress.IsFinished
-				finishedAt := book.Progress.FinishedAt
 				if book.Media.Duration > 0 {
 					progress = book.Progress.CurrentTime / book.Media.Duration
 				}
 
 				// Determine the status based on progress and isFinished flag
-				status := s.determineBookStatus(progress, isFinished, finishedAt)
-				userBookID, err := s.findOrCreateUserBookID(ctx, editionIDStr, status)
-				if err != nil {
-					s.log.Warn("Failed to get or create user book ID for cached edition", map[string]interface{}{
-						"edition_id": editionIDStr,
-						"error":      err.Error(),
-					})
+				status := s.bookStatus(book, progress)
+				userBookID, err := s.findOrCreateUserBookIDForMatch(ctx, book, hcBook, editionIDStr, status)
+				if isEditionGone(err) {
+					// Fall through to a fresh ASIN lookup, which resolves the merged book
+					s.invalidateCachedEdition(book.Media.Metadata.ASIN, cachedBook, err)
 				} else {
-					hcBook.UserBookID = strconv.FormatInt(userBookID, 10)
-				}
+					if userBookSkipped(err) {
+						// Kept off the Want to Read shelf or ownership only, processBook takes care of the book
+					} else if err != nil {
+						s.log.Warn("Failed to get or create user book ID for cached edition", map[string]interface{}{
+							"edition_id": editionIDStr,
+							"error":      err.Error(),
+						})
+					} else {
+						hcBook.UserBookID = strconv.FormatInt(userBookID, 10)
+					}
 
-				s.log.Info("Using cached book by ASIN", map[string]interface{}{
-					"book_id":      hcBook.ID,
-					"edition_id":   hcBook.EditionID,
-					"user_book_id": hcBook.UserBookID,
-				})
+					s.log.Info("Using cached book by ASIN", map[string]interface{}{
+						"book_id":      hcBook.ID,
+						"edition_id":   hcBook.EditionID,
+						"user_book_id": hcBook.UserBookID,
+					})
 
-				return hcBook, nil
+					return hcBook, asinMatch, true, nil
+				}
 			}
 		}
 
 		log.Info(fmt.Sprintf("Searching for book by ASIN: %s", book.Media.Metadata.ASIN), nil)
 
 		hcBook, err := s.hardcover.SearchBookByASIN(ctx, book.Media.Metadata.ASIN)
-		if err != nil {
-			// Check if this is a BookError with a book ID
-			var bookErr *hardcover.BookError
-			if err != nil && errors.As(err, &bookErr) && bookErr.BookID != "" {
-				log.Info("Found book ID in BookError", map[string]interface{}{
-					"book_id": bookErr.BookID,
-					"error":   bookErr.Error(),
-				})
-				// Create a minimal book with just the ID
-				return &models.HardcoverBook{
-					ID: bookErr.BookID,
-				}, nil
-			}
+		if errors.Is(err, hardcover.ErrBookHasNoEditions) {
+			log.Warn("Book found by ASIN has no editions, will try other methods", map[string]interface{}{
+				"error": err.Error(),
+			})
+			noEditionsErr, noEditionsMatch = err, asinMatch
+		} else if err != nil {
 			// Cache the negative result to avoid repeated failed lookups
 			s.setASINInCache(book.Media.Metadata.ASIN, nil)
 			log.Debug("Cached negative ASIN lookup result", map[string]interface{}{
@@ -3471,6 +4350,8 @@ func (s *Service) findBookInHardcover(ctx context.Context, book models.Audiobook
 			})
 			log.Warn(fmt.Sprintf("Search by ASIN failed, will try other methods: %v", err), nil)
 		} else if hcBook != nil {
+			s.preferNarratorEdition(ctx, book, hcBook)
+
 			// Cache the ASIN lookup result for future use
 			s.setASINInCache(book.Media.Metadata.ASIN, hcBook)
 			log.Debug("Cached ASIN lookup result", map[string]interface{}{
@@ -3479,19 +4360,26 @@ func (s *Service) findBookInHardcover(ctx context.Context, book models.Audiobook
 				"edition_id": hcBook.EditionID,
 			})
 
+			if err := s.verifyMatchedEditionASIN(book, hcBook); err != nil {
+				log.Warn("Matched edition doesn't carry the book's ASIN, not syncing", map[string]interface{}{
+					"error": err.Error(),
+				})
+				return hcBook, asinMatch, true, err
+			}
+
 			// Get or create user book ID for this edition
 			editionIDStr := hcBook.EditionID
 			progress := 0.0
-			isFinished := book.Progress.IsFinished
-			finishedAt := book.Progress.FinishedAt
 			if book.Media.Duration > 0 {
 				progress = book.Progress.CurrentTime / book.Media.Duration
 			}
 
 			// Determine the status based on progress and isFinished flag
-			status := s.determineBookStatus(progress, isFinished, finishedAt)
-			userBookID, err := s.findOrCreateUserBookID(ctx, editionIDStr, status)
-			if err != nil {
+			status := s.bookStatus(book, progress)
+			userBookID, err := s.findOrCreateUserBookIDForMatch(ctx, book, hcBook, editionIDStr, status)
+			if userBookSkipped(err) {
+				// Kept off the Want to Read shelf or ownership only, processBook takes care of the book
+			} else if err != nil {
 				s.log.Warn("Failed to get or create user book ID for edition", map[string]interface{}{
 					"edition_id": editionIDStr,
 					"error":      err.Error(),
@@ -3506,7 +4394,7 @@ func (s *Service) findBookInHardcover(ctx context.Context, book models.Audiobook
 				"user_book_id": hcBook.UserBookID,
 			})
 
-			return hcBook, nil
+			return hcBook, asinMatch, true, nil
 		}
 	}
 
@@ -3516,42 +4404,48 @@ func (s *Service) findBookInHardcover(ctx context.Context, book models.Audiobook
 
 		// Try to find by ISBN-13 first
 		hcBook, err := s.hardcover.SearchBookByISBN13(ctx, book.Media.Metadata.ISBN)
-		if err != nil {
-			// Check if this is a BookError with a book ID
-			var bookErr *hardcover.BookError
-			if err != nil && errors.As(err, &bookErr) {
-				log.Info("Found book ID in BookError from ISBN-13 search", map[string]interface{}{
-					"book_id": bookErr.BookID,
-					"error":   bookErr.Error(),
-				})
-				// Create a minimal book with just the ID
-				return &models.HardcoverBook{
-					ID: bookErr.BookID,
-				}, bookErr
+		if errors.Is(err, hardcover.ErrBookHasNoEditions) {
+			log.Warn("Book found by ISBN-13 has no editions, will try ISBN-10", map[string]interface{}{
+				"error": err.Error(),
+			})
+			if noEditionsErr == nil {
+				noEditionsErr, noEditionsMatch = err, isbnMatch
 			}
+		} else if err != nil {
 			log.Warn(fmt.Sprintf("Search by ISBN-13 failed, will try ISBN-10: %v", err), nil)
 		} else if hcBook != nil {
-			return s.processFoundBook(ctx, hcBook, book)
+			if err := s.verifyMatchedEditionISBN(book, hcBook); err != nil {
+				log.Warn("Matched edition doesn't carry the book's ISBN, not syncing", map[string]interface{}{
+					"error": err.Error(),
+				})
+				return hcBook, isbnMatch, true, err
+			}
+			s.preferNarratorEdition(ctx, book, hcBook)
+			found, err := s.processFoundBook(ctx, hcBook, book)
+			return found, isbnMatch, true, err
 		}
 
 		// If ISBN-13 search failed or returned no results, try ISBN-10
 		hcBook, err = s.hardcover.SearchBookByISBN10(ctx, book.Media.Metadata.ISBN)
-		if err != nil {
-			// Check if this is a BookError with a book ID
-			var bookErr *hardcover.BookError
-			if err != nil && errors.As(err, &bookErr) && bookErr.BookID != "" {
-				log.Info("Found book ID in BookError from ISBN-10 search", map[string]interface{}{
-					"book_id": bookErr.BookID,
-					"error":   bookErr.Error(),
-				})
-				// Create a minimal book with just the ID
-				return &models.HardcoverBook{
-					ID: bookErr.BookID,
-				}, nil
+		if errors.Is(err, hardcover.ErrBookHasNoEditions) {
+			log.Warn("Book found by ISBN-10 has no editions", map[string]interface{}{
+				"error": err.Error(),
+			})
+			if noEditionsErr == nil {
+				noEditionsErr, noEditionsMatch = err, isbnMatch
 			}
+		} else if err != nil {
 			log.Warn(fmt.Sprintf("Search by ISBN-10 failed: %v", err), nil)
 		} else if hcBook != nil {
-			return s.processFoundBook(ctx, hcBook, book)
+			if err := s.verifyMatchedEditionISBN(book, hcBook); err != nil {
+				log.Warn("Matched edition doesn't carry the book's ISBN, not syncing", map[string]interface{}{
+					"error": err.Error(),
+				})
+				return hcBook, isbnMatch, true, err
+			}
+			s.preferNarratorEdition(ctx, book, hcBook)
+			found, err := s.processFoundBook(ctx, hcBook, book)
+			return found, isbnMatch, true, err
 		}
 
 		log.Warn("Failed to find book by ISBN, will try other methods", map[string]interface{}{
@@ -3563,44 +4457,11 @@ func (s *Service) findBookInHardcover(ctx context.Context, book models.Audiobook
 		// Don't return here - fall through to try ASIN or title/author search
 	}
 
-	// 3. If we get here, we couldn't find the book by ASIN or ISBN, try title/author search
-	if book.Media.Metadata.Title != "" && book.Media.Metadata.AuthorName != "" {
-		log.Info("Trying title/author search after ASIN/ISBN search failed", map[string]interface{}{
-			"search_method": "title_author",
-			"title":         book.Media.Metadata.Title,
-			"author":        book.Media.Metadata.AuthorName,
-		})
-
-		hcBook, err := s.findBookInHardcoverByTitleAuthor(ctx, book)
-		if err != nil {
-			log.Warn("Title/author search failed or edition not found", map[string]interface{}{
-				"search_method": "title_author",
-				"title":         book.Media.Metadata.Title,
-				"author":        book.Media.Metadata.AuthorName,
-				"error":         err.Error(),
-			})
-			// Return the error to be handled as a mismatch
-			return nil, fmt.Errorf("book found but edition not available: %w", err)
-		}
-
-		// If we get here, we found a book by title/author - this is a mismatch case
-		log.Info("Book found by title/author search - will be treated as mismatch", map[string]interface{}{
-			"book_id": hcBook.ID,
-			"title":   hcBook.Title,
-		})
-		return hcBook, fmt.Errorf("found by title/author only")
-
-		// Unreachable code removed; mismatch is already indicated by the return above
+	// The book exists, so a title/author search would only find it without an edition again
+	if noEditionsErr != nil {
+		bookID, _ := hardcover.GetBookID(noEditionsErr)
+		return &models.HardcoverBook{ID: bookID}, noEditionsMatch, true, noEditionsErr
 	}
 
-	log.Warn("Book not found in Hardcover by any search method", map[string]interface{}{
-		"book_id": book.ID,
-		"title":   book.Media.Metadata.Title,
-		"author":  book.Media.Metadata.AuthorName,
-		"isbn":    book.Media.Metadata.ISBN,
-		"asin":    book.Media.Metadata.ASIN,
-	})
-
-	// Return a specific error that indicates this is a potential mismatch
-	return nil, fmt.Errorf("book not found by ASIN/ISBN or title/author, potential mismatch")
+	return nil, bookMatch{}, false, nil
 }
@@ -96,6 +96,12 @@ func (m *MockHardcoverClient) UpdateUserBookRead(ctx context.Context, input hard
 	return args.Bool(0), args.Error(1)
 }
 
+// DeleteUserBookRead mocks the DeleteUserBookRead method
+func (m *MockHardcoverClient) DeleteUserBookRead(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 // CheckExistingUserBookRead mocks the CheckExistingUserBookRead method
 func (m *MockHardcoverClient) CheckExistingUserBookRead(ctx context.Context, input hardcover.CheckExistingUserBookReadInput) (*hardcover.CheckExistingUserBookReadResult, error) {
 	args := m.Called(ctx, input)
@@ -117,6 +123,78 @@ func (m *MockHardcoverClient) CreateUserBook(ctx context.Context, editionID, sta
 	return args.String(0), args.Error(1)
 }
 
+// SearchBookBySlug mocks the SearchBookBySlug method
+func (m *MockHardcoverClient) SearchBookBySlug(ctx context.Context, slug string) (*models.HardcoverBook, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.HardcoverBook), args.Error(1)
+}
+
+// CreateBookLevelUserBook mocks the CreateBookLevelUserBook method
+func (m *MockHardcoverClient) CreateBookLevelUserBook(ctx context.Context, bookID, status string) (string, error) {
+	args := m.Called(ctx, bookID, status)
+	return args.String(0), args.Error(1)
+}
+
+// GetFinishedUserBooks mocks the GetFinishedUserBooks method
+func (m *MockHardcoverClient) GetFinishedUserBooks(ctx context.Context) (map[string]time.Time, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]time.Time), args.Error(1)
+}
+
+// GetUserBookRefs mocks the GetUserBookRefs method
+func (m *MockHardcoverClient) GetUserBookRefs(ctx context.Context) ([]hardcover.UserBookRef, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]hardcover.UserBookRef), args.Error(1)
+}
+
+// GetUserBooksByEditionIDs mocks the GetUserBooksByEditionIDs method
+func (m *MockHardcoverClient) GetUserBooksByEditionIDs(ctx context.Context, editionIDs []int) ([]hardcover.UserBookRef, error) {
+	args := m.Called(ctx, editionIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]hardcover.UserBookRef), args.Error(1)
+}
+
+// GetAudioEditionNarrators mocks the GetAudioEditionNarrators method
+func (m *MockHardcoverClient) GetAudioEditionNarrators(ctx context.Context, bookID string) ([]hardcover.EditionNarrators, error) {
+	args := m.Called(ctx, bookID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]hardcover.EditionNarrators), args.Error(1)
+}
+
+// GetUserLists mocks the GetUserLists method
+func (m *MockHardcoverClient) GetUserLists(ctx context.Context) ([]hardcover.List, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]hardcover.List), args.Error(1)
+}
+
+// CreateList mocks the CreateList method
+func (m *MockHardcoverClient) CreateList(ctx context.Context, name string) (int, error) {
+	args := m.Called(ctx, name)
+	return args.Int(0), args.Error(1)
+}
+
+// AddBookToList mocks the AddBookToList method
+func (m *MockHardcoverClient) AddBookToList(ctx context.Context, listID, bookID int) error {
+	args := m.Called(ctx, listID, bookID)
+	return args.Error(0)
+}
+
 // SearchPublishers mocks the SearchPublishers method
 func (m *MockHardcoverClient) SearchPublishers(ctx context.Context, name string, limit int) ([]models.Publisher, error) {
 	args := m.Called(ctx, name, limit)
@@ -213,6 +291,12 @@ func (m *MockHardcoverClient) GetUserBook(ctx context.Context, userBookID string
 	}, args.Error(1)
 }
 
+// AddTagToBook mocks the AddTagToBook method
+func (m *MockHardcoverClient) AddTagToBook(ctx context.Context, bookID int, tag string) error {
+	args := m.Called(ctx, bookID, tag)
+	return args.Error(0)
+}
+
 func (m *MockHardcoverClient) GetBookByID(ctx context.Context, bookID string) (*models.HardcoverBook, error) {
 	args := m.Called(ctx, bookID)
 	// Support both *models.HardcoverBook and *TestHardcoverBook, including typed-nil
@@ -234,6 +318,15 @@ func (m *MockHardcoverClient) GetBookByID(ctx context.Context, bookID string) (*
 	panic("GetBookByID mock return value must be either *models.HardcoverBook or *TestHardcoverBook or nil")
 }
 
+// GetBookAuthors mocks the GetBookAuthors method
+func (m *MockHardcoverClient) GetBookAuthors(ctx context.Context, bookID string) ([]models.Author, error) {
+	args := m.Called(ctx, bookID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Author), args.Error(1)
+}
+
 // SearchBookByISBN13 mocks the SearchBookByISBN13 method
 func (m *MockHardcoverClient) SearchBookByISBN13(ctx context.Context, isbn13 string) (*models.HardcoverBook, error) {
     args := m.Called(ctx, isbn13)
@@ -277,6 +370,15 @@ func (m *MockHardcoverClient) SearchBookByISBN13(ctx context.Context, isbn13 str
     panic("SearchBookByASIN mock return must be *models.HardcoverBook, *TestHardcoverBook or nil")
 }
 
+// SearchBookByASINAnyFormat mocks the SearchBookByASINAnyFormat method
+func (m *MockHardcoverClient) SearchBookByASINAnyFormat(ctx context.Context, asin string) (*models.HardcoverBook, error) {
+	args := m.Called(ctx, asin)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.HardcoverBook), args.Error(1)
+}
+
 // SearchBookByASIN mocks the SearchBookByASIN method
 func (m *MockHardcoverClient) SearchBookByASIN(ctx context.Context, asin string) (*models.HardcoverBook, error) {
     args := m.Called(ctx, asin)
@@ -421,6 +523,7 @@ func createTestConfig(syncOwned bool) *config.Config {
 	cfg.Logging.Format = "console"
 	cfg.Server.Port = "8080"
 	cfg.Server.ShutdownTimeout = 30 * time.Second
+	cfg.Paths.CacheDir = "/tmp/test-cache"
 	
 	// Clear deprecated fields in App
 	cfg.App = struct {
@@ -703,6 +806,27 @@ func createTestService() (*Service, *MockHardcoverClient) {
 	return svc, mockClient
 }
 
+// newTestBook returns a book of the test library that's halfway through its hour of audio. Tests set
+// the identifiers, tags and progress they're about on the returned book. Without identifiers or an
+// author, processBook records the book as not found without any Hardcover requests, so
+// lookedUpBooks shows whether it got past the checks that skip books.
+func newTestBook(id, title string) models.AudiobookshelfBook {
+	book := models.AudiobookshelfBook{ID: id, LibraryID: "lib1", MediaType: "book"}
+	book.Media.Metadata.Title = title
+	book.Media.Duration = 3600
+	book.Progress.CurrentTime = 1800
+	return book
+}
+
+// lookedUpBooks returns the IDs of the books of the summary that were looked up in Hardcover
+func lookedUpBooks(summary *SyncSummary) []string {
+	ids := make([]string, 0, len(summary.BooksNotFound))
+	for _, book := range summary.BooksNotFound {
+		ids = append(ids, book.BookID)
+	}
+	return ids
+}
+
 func TestProcessFoundBook_WithBook(t *testing.T) {
 	svc, mockClient := createTestService()
 	ctx := context.Background()
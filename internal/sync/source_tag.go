@@ -0,0 +1,59 @@
+package sync
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// applySourceTag tags the Hardcover book with Sync.SourceTag so users can identify books that were
+// synced by this tool. Each book is tagged at most once per process, and books only being looked up
+// aren't tagged; failures are only logged.
+func (s *Service) applySourceTag(ctx context.Context, hcBook *models.HardcoverBook) {
+	tag := s.config.Sync.SourceTag
+	if tag == "" || hcBook == nil || hcBook.ID == "" || lookupOnly(ctx) {
+		return
+	}
+
+	log := s.log.With(map[string]interface{}{
+		"book_id": hcBook.ID,
+		"tag":     tag,
+	})
+
+	bookID, err := strconv.Atoi(hcBook.ID)
+	if err != nil {
+		log.Warn("Invalid book ID format for source tagging", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	s.taggedBooksMutex.Lock()
+	if s.taggedBooks == nil {
+		s.taggedBooks = make(map[string]struct{})
+	}
+	_, tagged := s.taggedBooks[hcBook.ID]
+	s.taggedBooksMutex.Unlock()
+	if tagged {
+		return
+	}
+
+	if s.dryRun(ctx) {
+		log.Info("[DRY-RUN] Would tag book with source tag", nil)
+		return
+	}
+
+	if err := s.hardcover.AddTagToBook(ctx, bookID, tag); err != nil {
+		log.Warn("Failed to tag book with source tag", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	s.taggedBooksMutex.Lock()
+	s.taggedBooks[hcBook.ID] = struct{}{}
+	s.taggedBooksMutex.Unlock()
+
+	log.Debug("Tagged book with source tag", nil)
+}
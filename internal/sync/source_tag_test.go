@@ -0,0 +1,56 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestApplySourceTag(t *testing.T) {
+	hcBook := &models.HardcoverBook{ID: "123", EditionID: "456"}
+
+	t.Run("tags each book once", func(t *testing.T) {
+		svc, mockClient := createTestService()
+		svc.config.Sync.SourceTag = "abs-sync"
+		mockClient.On("AddTagToBook", mock.Anything, 123, "abs-sync").Return(nil).Once()
+
+		svc.applySourceTag(context.Background(), hcBook)
+		svc.applySourceTag(context.Background(), hcBook)
+
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("failed tagging is retried", func(t *testing.T) {
+		svc, mockClient := createTestService()
+		svc.config.Sync.SourceTag = "abs-sync"
+		mockClient.On("AddTagToBook", mock.Anything, 123, "abs-sync").Return(errors.New("rate limited")).Once()
+		mockClient.On("AddTagToBook", mock.Anything, 123, "abs-sync").Return(nil).Once()
+
+		svc.applySourceTag(context.Background(), hcBook)
+		svc.applySourceTag(context.Background(), hcBook)
+
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("disabled without a source tag", func(t *testing.T) {
+		svc, mockClient := createTestService()
+		svc.config.Sync.SourceTag = ""
+
+		svc.applySourceTag(context.Background(), hcBook)
+
+		mockClient.AssertNotCalled(t, "AddTagToBook", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("dry run does not tag", func(t *testing.T) {
+		svc, mockClient := createTestService()
+		svc.config.Sync.SourceTag = "abs-sync"
+		svc.config.Sync.DryRun = true
+
+		svc.applySourceTag(context.Background(), hcBook)
+
+		mockClient.AssertNotCalled(t, "AddTagToBook", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,75 @@
+package state
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// WriteBehind periodically saves a state to disk in the background while it has unsaved
+// changes, so progress made during a long sync survives a crash or cancellation.
+type WriteBehind struct {
+	state    *State
+	path     string
+	interval time.Duration
+	onError  func(error)
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+	done     chan struct{}
+	err      error
+}
+
+// StartWriteBehind starts flushing the state to path every interval. Flushing stops when ctx is
+// canceled or Stop is called; both trigger a final flush. onError, if set, is called for failed
+// periodic flushes.
+func (s *State) StartWriteBehind(ctx context.Context, path string, interval time.Duration, onError func(error)) *WriteBehind {
+	w := &WriteBehind{
+		state:    s,
+		path:     path,
+		interval: interval,
+		onError:  onError,
+		stopCh:   make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go w.run(ctx)
+	return w
+}
+
+func (w *WriteBehind) run(ctx context.Context) {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := w.flush(); err != nil && w.onError != nil {
+				w.onError(err)
+			}
+		case <-ctx.Done():
+			w.err = w.flush()
+			return
+		case <-w.stopCh:
+			w.err = w.flush()
+			return
+		}
+	}
+}
+
+// flush saves the state if it has changed since the last save
+func (w *WriteBehind) flush() error {
+	if !w.state.Dirty() {
+		return nil
+	}
+	return w.state.Save(w.path)
+}
+
+// Stop stops the background flushing after a final flush and returns the final flush's error.
+// It is safe to call Stop more than once.
+func (w *WriteBehind) Stop() error {
+	w.stopOnce.Do(func() { close(w.stopCh) })
+	<-w.done
+	return w.err
+}
@@ -0,0 +1,103 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteBehind_PeriodicFlush(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	state := NewState()
+
+	w := state.StartWriteBehind(context.Background(), path, 10*time.Millisecond, nil)
+	defer w.Stop()
+
+	state.UpdateBook("book1", 0.5, "IN_PROGRESS")
+
+	// The update reaches disk without an explicit save
+	require.Eventually(t, func() bool {
+		loaded, err := LoadState(path)
+		if err != nil {
+			return false
+		}
+		_, exists := loaded.GetBookState("book1")
+		return exists
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.False(t, state.Dirty())
+}
+
+func TestWriteBehind_SkipsCleanState(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	state := NewState()
+
+	w := state.StartWriteBehind(context.Background(), path, 5*time.Millisecond, nil)
+	time.Sleep(30 * time.Millisecond)
+	require.NoError(t, w.Stop())
+
+	_, err := os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "nothing should be written for an unchanged state")
+}
+
+func TestWriteBehind_FlushOnCancel(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	state := NewState()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := state.StartWriteBehind(ctx, path, time.Hour, nil)
+
+	state.UpdateBook("book1", 0.25, "IN_PROGRESS")
+	cancel()
+	require.NoError(t, w.Stop())
+
+	loaded, err := LoadState(path)
+	require.NoError(t, err)
+	book, exists := loaded.GetBookState("book1")
+	require.True(t, exists)
+	assert.Equal(t, 0.25, book.LastProgress)
+}
+
+func TestWriteBehind_ConcurrentUpdates(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	state := NewState()
+
+	w := state.StartWriteBehind(context.Background(), path, time.Millisecond, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 1; j <= 100; j++ {
+				state.UpdateBook(fmt.Sprintf("book%d", i), float64(j)/100.0, "IN_PROGRESS")
+			}
+		}(i)
+	}
+	wg.Wait()
+	require.NoError(t, w.Stop())
+	assert.False(t, state.Dirty())
+
+	// The final flush leaves the file consistent with the in-memory state
+	loaded, err := LoadState(path)
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		book, exists := loaded.GetBookState(fmt.Sprintf("book%d", i))
+		require.True(t, exists)
+		assert.Equal(t, 1.0, book.LastProgress)
+	}
+}
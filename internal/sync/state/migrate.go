@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 )
 
 // MigrateOldState migrates the old sync state file to the new location
@@ -63,3 +65,100 @@ func MigrateOldState(oldPath, newPath string) (bool, error) {
 
 	return true, nil
 }
+
+// RepairKeys validates and normalizes the book keys of the state, as written by older versions or
+// edited by hand: keys are either an Audiobookshelf item ID or its composite "itemID:editionID"
+// key. Surrounding whitespace and empty or malformed edition parts are dropped, keys without an
+// item ID removed, and percentages stored as progress turned into fractions. Entries folded into
+// the same key are merged, keeping the most recent progress and any match recorded. Bare keys of
+// books matched to an edition get their composite key, and composite keys their aggregate entry
+// under the item ID, as the sync keeps both. Returns the number of keys repaired.
+func (s *State) RepairKeys() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repaired := 0
+	books := make(map[string]Book, len(s.Books))
+	for key, book := range s.Books {
+		normalized, ok := normalizeBookKey(key)
+		if !ok {
+			repaired++
+			continue
+		}
+		if normalized != key {
+			repaired++
+		}
+		if book.LastProgress > 1.0 {
+			book.LastProgress /= 100.0
+			repaired++
+		}
+		if existing, exists := books[normalized]; exists {
+			book = mergeBooks(existing, book)
+		}
+		books[normalized] = book
+	}
+
+	for key, book := range books {
+		itemID, editionID, composite := strings.Cut(key, ":")
+		if !composite {
+			if _, err := strconv.Atoi(book.EditionID); err == nil {
+				if _, exists := books[key+":"+book.EditionID]; !exists {
+					books[key+":"+book.EditionID] = book
+					repaired++
+				}
+			}
+			continue
+		}
+		if aggregate, exists := books[itemID]; !exists || aggregate.LastUpdated < book.LastUpdated {
+			if exists {
+				book = mergeBooks(aggregate, book)
+			}
+			if book.EditionID == "" {
+				book.EditionID = editionID
+			}
+			books[itemID] = book
+			repaired++
+		}
+	}
+
+	if repaired > 0 {
+		s.Books = books
+		s.generation++
+	}
+	return repaired
+}
+
+// normalizeBookKey returns the normalized form of a book key, or false if it has no item ID
+func normalizeBookKey(key string) (string, bool) {
+	itemID, editionID, composite := strings.Cut(strings.TrimSpace(key), ":")
+	itemID = strings.TrimSpace(itemID)
+	if itemID == "" {
+		return "", false
+	}
+	if !composite {
+		return itemID, true
+	}
+	editionID = strings.TrimSpace(editionID)
+	if id, err := strconv.Atoi(editionID); err != nil || id <= 0 {
+		return itemID, true
+	}
+	return itemID + ":" + editionID, true
+}
+
+// mergeBooks merges two entries of the same key, keeping the progress of the most recently
+// updated one and the match of either
+func mergeBooks(a, b Book) Book {
+	if b.LastUpdated > a.LastUpdated {
+		a, b = b, a
+	}
+	if a.Status == "" {
+		a.Status = b.Status
+	}
+	if a.MatchSource == "" {
+		a.MatchSource, a.MatchScore = b.MatchSource, b.MatchScore
+	}
+	if a.EditionID == "" {
+		a.EditionID = b.EditionID
+	}
+	return a
+}
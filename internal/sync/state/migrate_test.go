@@ -82,3 +82,83 @@ func TestMigrateOldState(t *testing.T) {
 		assert.NoFileExists(t, newPath)
 	})
 }
+
+func TestLoadState_RepairsLegacyKeys(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "state_v2_1.json")
+
+	// A 2.1 state with bare keys, a composite key without its aggregate entry, whitespace, an empty
+	// edition part, a percentage progress and a key without an item ID
+	legacy := `{
+		"version": "2.1",
+		"lastSync": 1751108977,
+		"lastFullSync": 1751108977,
+		"books": {
+			"item1": {"lastProgress": 0.25, "lastUpdated": 1751100000, "status": "IN_PROGRESS", "matchSource": "asin", "matchScore": 1, "editionId": "100"},
+			"item2:200": {"lastProgress": 0.5, "lastUpdated": 1751100000, "status": "IN_PROGRESS"},
+			" item3 ": {"lastProgress": 75, "lastUpdated": 1751100000, "status": "IN_PROGRESS"},
+			"item4:": {"lastProgress": 1, "lastUpdated": 1751100000, "status": "FINISHED"},
+			":300": {"lastProgress": 0.1, "lastUpdated": 1751100000}
+		}
+	}`
+	require.NoError(t, os.WriteFile(statePath, []byte(legacy), 0644))
+
+	state, err := LoadState(statePath)
+	require.NoError(t, err)
+	assert.Equal(t, CurrentVersion, state.Version)
+	assert.True(t, state.Dirty(), "the repaired state is saved with the next flush")
+
+	// Bare keys matched to an edition get their composite key, keeping the match
+	for _, key := range []string{"item1", "item1:100"} {
+		book, ok := state.GetBookState(key)
+		require.True(t, ok, key)
+		assert.Equal(t, 0.25, book.LastProgress)
+		assert.Equal(t, "asin", book.MatchSource)
+		assert.Equal(t, "100", book.EditionID)
+	}
+
+	// Composite keys get their aggregate entry
+	book, ok := state.GetBookState("item2")
+	require.True(t, ok)
+	assert.Equal(t, 0.5, book.LastProgress)
+	assert.Equal(t, "200", book.EditionID)
+
+	book, ok = state.GetBookState("item3")
+	require.True(t, ok)
+	assert.Equal(t, 0.75, book.LastProgress)
+
+	book, ok = state.GetBookState("item4")
+	require.True(t, ok)
+	assert.Equal(t, "FINISHED", book.Status)
+
+	assert.Len(t, state.Books, 6)
+	for key := range state.Books {
+		assert.NotContains(t, []string{" item3 ", "item4:", ":300"}, key)
+	}
+
+	// The repaired state loads as is
+	require.NoError(t, state.Save(statePath))
+	reloaded, err := LoadState(statePath)
+	require.NoError(t, err)
+	assert.Equal(t, state.Books, reloaded.Books)
+	assert.Zero(t, reloaded.RepairKeys())
+}
+
+func TestState_RepairKeys_MergesDuplicates(t *testing.T) {
+	state := NewState()
+	state.Books = map[string]Book{
+		"item1:100":   {LastProgress: 0.2, LastUpdated: 100, Status: "IN_PROGRESS", MatchSource: "isbn", MatchScore: 1},
+		" item1:100 ": {LastProgress: 0.6, LastUpdated: 200, Status: "IN_PROGRESS"},
+		"item1":       {LastProgress: 0.6, LastUpdated: 200, Status: "IN_PROGRESS"},
+	}
+
+	assert.Positive(t, state.RepairKeys())
+	require.Len(t, state.Books, 2)
+
+	// The most recent progress wins, without losing the recorded match
+	book := state.Books["item1:100"]
+	assert.Equal(t, 0.6, book.LastProgress)
+	assert.Equal(t, "isbn", book.MatchSource)
+
+	// Consistent keys need no repair
+	assert.Zero(t, state.RepairKeys())
+}
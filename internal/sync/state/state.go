@@ -7,14 +7,16 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 )
 
 const (
-	// CurrentVersion is the current version of the sync state format
-	CurrentVersion = "2.0"
+	// CurrentVersion is the current version of the sync state format. Files of older versions have
+	// their book keys repaired on load.
+	CurrentVersion = "2.2"
 	// DefaultStateFile is the default path for the sync state file
 	DefaultStateFile = "./data/sync_state.json"
 )
@@ -26,7 +28,21 @@ type State struct {
 	LastFullSync int64              `json:"lastFullSync"`
 	Libraries    map[string]Library `json:"libraries,omitempty"`
 	Books        map[string]Book    `json:"books,omitempty"`
-	mu           sync.RWMutex       `json:"-"`
+	// LastFullFetch is when the last sync that fetched every item completed (Unix seconds)
+	LastFullFetch int64 `json:"lastFullFetch,omitempty"`
+	// FirstSeen holds when books that couldn't be found in Hardcover were first seen (Unix seconds),
+	// keyed by Audiobookshelf item ID
+	FirstSeen map[string]int64 `json:"firstSeen,omitempty"`
+	// Unseen holds how many full syncs in a row didn't see books, keyed by
+	// Audiobookshelf item ID, until they're seen again or pruned
+	Unseen map[string]int `json:"unseen,omitempty"`
+	mu     sync.RWMutex   `json:"-"`
+
+	// generation is incremented on every change; savedGeneration is the generation last written to disk
+	generation      uint64
+	savedGeneration uint64
+	// saveMu serializes writes to disk so an older snapshot can't overwrite a newer one
+	saveMu sync.Mutex
 }
 
 // Library represents the sync state of a library
@@ -39,8 +55,22 @@ type Book struct {
 	LastProgress float64 `json:"lastProgress"`
 	LastUpdated  int64   `json:"lastUpdated"`
 	Status       string  `json:"status,omitempty"` // e.g., "WANT_TO_READ", "IN_PROGRESS", "FINISHED"
+	// MatchSource is how the book was last matched to Hardcover, one of the MatchSource constants
+	MatchSource string `json:"matchSource,omitempty"`
+	// MatchScore is the confidence of the last match, from 0 to 1
+	MatchScore float64 `json:"matchScore,omitempty"`
+	// EditionID is the Hardcover edition the book was last matched to
+	EditionID string `json:"editionId,omitempty"`
 }
 
+// Match sources recorded in Book.MatchSource
+const (
+	MatchSourceASIN        = "asin"
+	MatchSourceISBN        = "isbn"
+	MatchSourceTitleAuthor = "title-author"
+	MatchSourceMapping     = "mapping"
+)
+
 // NewState creates a new empty state with current version
 func NewState() *State {
 	return &State{
@@ -97,8 +127,10 @@ func LoadState(path string) (*State, error) {
 			return nil, fmt.Errorf("failed to parse v1 state: %w", err)
 		}
 		state = migrateV1ToV2(v1)
-	case CurrentVersion:
-		// Current version - initialize with empty maps first
+	case "2.0", "2.1", CurrentVersion:
+		// 2.0 only lacks the optional match fields of books, and 2.1 may lack the composite or
+		// aggregate keys of books, so they load as the current version with their keys repaired
+		// Initialize with empty maps first
 		state = &State{
 			Libraries: make(map[string]Library),
 			Books:     make(map[string]Book),
@@ -113,6 +145,10 @@ func LoadState(path string) (*State, error) {
 		if state.Books == nil {
 			state.Books = make(map[string]Book)
 		}
+		if version.Version != CurrentVersion {
+			state.RepairKeys()
+		}
+		state.Version = CurrentVersion
 	default:
 		return nil, fmt.Errorf("unsupported state version: %s", version.Version)
 	}
@@ -121,14 +157,25 @@ func LoadState(path string) (*State, error) {
 }
 
 // Save writes the state to a file
+// The state is snapshotted under a read lock, so updates are not blocked while writing to disk.
 func (s *State) Save(path string) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
 
 	if path == "" {
 		path = DefaultStateFile
 	}
 
+	// Snapshot the state with indentation
+	s.mu.RLock()
+	data, err := json.MarshalIndent(s, "", "  ")
+	generation := s.generation
+	s.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+	data = append(data, '\n')
+
 	targetDir := filepath.Dir(path)
 
 	// Ensure directory exists with proper permissions
@@ -149,11 +196,8 @@ func (s *State) Save(path string) error {
 		}
 	}()
 
-	// Write JSON with indentation
-	encoder := json.NewEncoder(tmpFile)
-	encoder.SetIndent("", "  ")
-	if err := encoder.Encode(s); err != nil {
-		return fmt.Errorf("failed to encode state: %w", err)
+	if _, err := tmpFile.Write(data); err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
 	}
 
 	// Ensure data is written to disk
@@ -184,9 +228,23 @@ func (s *State) Save(path string) error {
 		return fmt.Errorf("failed to set permissions on state file: %w", err)
 	}
 
+	s.mu.Lock()
+	if generation > s.savedGeneration {
+		s.savedGeneration = generation
+	}
+	s.mu.Unlock()
+
 	return nil
 }
 
+// Dirty reports whether the state has changes that have not been saved yet
+func (s *State) Dirty() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.generation != s.savedGeneration
+}
+
 // UpdateBook updates the state for a book if there are actual changes
 // Returns true if the state was updated, false if no changes were needed
 // bookID should be in the format "bookID:editionID" to handle multiple editions
@@ -234,12 +292,11 @@ func (s *State) UpdateBook(bookID string, progress float64, status string) bool
 			// Even if we don't change this specific entry, we may still update
 			// the aggregate base-ID entry below.
 		} else {
-			// Update only the changed fields
-			s.Books[bookID] = Book{
-				LastProgress: normalizedProgress,
-				LastUpdated:  now,
-				Status:       status,
-			}
+			// Update only the changed fields, keeping the recorded match
+			existing.LastProgress = normalizedProgress
+			existing.LastUpdated = now
+			existing.Status = status
+			s.Books[bookID] = existing
 			updated = true
 			if debugLog {
 				log.Printf("DEBUG - Updated book %s state - progress: %.4f, status: %s", bookID, normalizedProgress, status)
@@ -271,11 +328,10 @@ func (s *State) UpdateBook(bookID string, progress float64, status string) bool
 			progressDiff := math.Abs(storedProgress - normalizedProgress)
 			statusChanged := existing.Status != status
 			if progressDiff > 0.001 || statusChanged {
-				s.Books[baseID] = Book{
-					LastProgress: normalizedProgress,
-					LastUpdated:  now,
-					Status:       status,
-				}
+				existing.LastProgress = normalizedProgress
+				existing.LastUpdated = now
+				existing.Status = status
+				s.Books[baseID] = existing
 			}
 		} else {
 			// No existing aggregate entry; create one.
@@ -288,9 +344,80 @@ func (s *State) UpdateBook(bookID string, progress float64, status string) bool
 	}
 
 	s.LastSync = now
+	s.generation++
+	return updated
+}
+
+// RecordMatch stores how a book was matched to Hardcover. Like UpdateBook it also updates the
+// aggregate entry keyed by the base ABS book ID. Returns true if the recorded match changed.
+func (s *State) RecordMatch(bookID, source string, score float64, editionID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	updated := false
+	keys := []string{bookID}
+	if baseID := strings.SplitN(bookID, ":", 2)[0]; baseID != "" && baseID != bookID {
+		keys = append(keys, baseID)
+	}
+	for _, key := range keys {
+		book := s.Books[key]
+		if book.MatchSource == source && book.MatchScore == score && book.EditionID == editionID {
+			continue
+		}
+		book.MatchSource = source
+		book.MatchScore = score
+		book.EditionID = editionID
+		s.Books[key] = book
+		updated = true
+	}
+
+	if updated {
+		s.generation++
+	}
 	return updated
 }
 
+// MigrateBookKey carries a book's history over to key, its composite "bookID:editionID" key, when
+// the edition was just resolved: the aggregate entry under the base ABS book ID is copied if the
+// book has no entry for any edition yet. Entries of other editions are dropped instead of carried
+// over, as the progress they record was written to another edition, so a book rematched to a
+// different edition is synced to it again. Returns true if an entry was migrated.
+func (s *State) MigrateBookKey(key string) bool {
+	baseID := strings.SplitN(key, ":", 2)[0]
+	if baseID == "" || baseID == key {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.Books[key]; exists {
+		return false
+	}
+
+	// The aggregate entry mirrors the editions synced so far, so it's only carried over when the
+	// book had no edition yet
+	prefix := baseID + ":"
+	rematched := false
+	for k := range s.Books {
+		if k != key && strings.HasPrefix(k, prefix) {
+			delete(s.Books, k)
+			rematched = true
+		}
+	}
+	if rematched {
+		s.generation++
+		return false
+	}
+
+	if book, exists := s.Books[baseID]; exists {
+		s.Books[key] = book
+		s.generation++
+		return true
+	}
+	return false
+}
+
 // UpdateLibrary updates the state for a library
 func (s *State) UpdateLibrary(libraryID string) {
 	s.mu.Lock()
@@ -301,6 +428,7 @@ func (s *State) UpdateLibrary(libraryID string) {
 		LastUpdated: now,
 	}
 	s.LastSync = now
+	s.generation++
 }
 
 // SetFullSync updates the last full sync timestamp
@@ -309,6 +437,7 @@ func (s *State) SetFullSync() {
 	defer s.mu.Unlock()
 
 	s.LastFullSync = time.Now().Unix()
+	s.generation++
 }
 
 // NeedsSync checks if a book needs syncing based on changes since last sync
@@ -357,6 +486,145 @@ func (s *State) GetBookState(bookID string) (Book, bool) {
 	return book, exists
 }
 
+// GetLibraryState returns the last known state of a library
+func (s *State) GetLibraryState(libraryID string) (Library, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	library, exists := s.Libraries[libraryID]
+	return library, exists
+}
+
+// MarkFirstSeen records now as the first-seen time of a book unless one is already recorded,
+// and returns the recorded first-seen time (Unix seconds)
+func (s *State) MarkFirstSeen(bookID string, now time.Time) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if firstSeen, exists := s.FirstSeen[bookID]; exists {
+		return firstSeen
+	}
+	if s.FirstSeen == nil {
+		s.FirstSeen = make(map[string]int64)
+	}
+	s.FirstSeen[bookID] = now.Unix()
+	s.generation++
+	return s.FirstSeen[bookID]
+}
+
+// FirstSeenAt returns the first-seen time of a book (Unix seconds), if one is recorded
+func (s *State) FirstSeenAt(bookID string) (int64, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	firstSeen, exists := s.FirstSeen[bookID]
+	return firstSeen, exists
+}
+
+// HasFirstSeenAfter reports whether any book was first seen after cutoff
+func (s *State) HasFirstSeenAfter(cutoff time.Time) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, firstSeen := range s.FirstSeen {
+		if firstSeen > cutoff.Unix() {
+			return true
+		}
+	}
+	return false
+}
+
+// ClearFirstSeen forgets the first-seen time of a book
+func (s *State) ClearFirstSeen(bookID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.FirstSeen[bookID]; exists {
+		delete(s.FirstSeen, bookID)
+		s.generation++
+	}
+}
+
+// PruneUnseen forgets the books whose Audiobookshelf item IDs aren't in seen, the items fetched by
+// a full sync, once they've been missing from retentionRuns full syncs in a row. All entries of a
+// book are removed: its composite "bookID:editionID" keys, its aggregate entry under the base ABS
+// book ID and its first-seen time. Books seen again start counting from zero. Returns the item IDs
+// of the pruned books.
+func (s *State) PruneUnseen(seen map[string]struct{}, retentionRuns int) []string {
+	if retentionRuns <= 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	itemIDs := make(map[string]struct{})
+	for key := range s.Books {
+		itemIDs[strings.SplitN(key, ":", 2)[0]] = struct{}{}
+	}
+	for itemID := range s.FirstSeen {
+		itemIDs[itemID] = struct{}{}
+	}
+	for itemID := range s.Unseen {
+		itemIDs[itemID] = struct{}{}
+	}
+
+	var pruned []string
+	for itemID := range itemIDs {
+		if _, ok := seen[itemID]; ok {
+			delete(s.Unseen, itemID)
+			continue
+		}
+		if s.Unseen == nil {
+			s.Unseen = make(map[string]int)
+		}
+		s.Unseen[itemID]++
+		if s.Unseen[itemID] < retentionRuns {
+			continue
+		}
+
+		prefix := itemID + ":"
+		for key := range s.Books {
+			if key == itemID || strings.HasPrefix(key, prefix) {
+				delete(s.Books, key)
+			}
+		}
+		delete(s.FirstSeen, itemID)
+		delete(s.Unseen, itemID)
+		pruned = append(pruned, itemID)
+	}
+
+	s.generation++
+	sort.Strings(pruned)
+	return pruned
+}
+
+// SetFullFetch records that a sync fetching every item completed now
+func (s *State) SetFullFetch() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.LastFullFetch = time.Now().Unix()
+	s.generation++
+}
+
+// GetLastFullFetch returns when the last sync fetching every item completed (Unix seconds, 0 if
+// never)
+func (s *State) GetLastFullFetch() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.LastFullFetch
+}
+
+// GetLastFullSync returns when the last full sync completed (Unix seconds, 0 if never)
+func (s *State) GetLastFullSync() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.LastFullSync
+}
+
 // GetStaleBooks returns books that haven't been updated in a while and might need refresh
 func (s *State) GetStaleBooks(maxAge time.Duration) []string {
 	s.mu.RLock()
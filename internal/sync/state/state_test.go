@@ -55,6 +55,117 @@ func TestLoadState_V1(t *testing.T) {
 	assert.Equal(t, expectedTime, state.LastFullSync)
 }
 
+func TestLoadState_V2_0(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	statePath := filepath.Join(tempDir, "state_v2_0.json")
+
+	// A 2.0 state file has no match fields on its books
+	v20State := `{
+		"version": "2.0",
+		"lastSync": 1751108977,
+		"lastFullSync": 1751108977,
+		"books": {
+			"book1:100": {"lastProgress": 0.5, "lastUpdated": 1751108977, "status": "IN_PROGRESS"}
+		}
+	}`
+	require.NoError(t, os.WriteFile(statePath, []byte(v20State), 0644))
+
+	state, err := LoadState(statePath)
+	require.NoError(t, err)
+	assert.Equal(t, CurrentVersion, state.Version)
+	assert.NotNil(t, state.Libraries)
+
+	book, exists := state.GetBookState("book1:100")
+	require.True(t, exists)
+	assert.Equal(t, 0.5, book.LastProgress)
+	assert.Equal(t, "IN_PROGRESS", book.Status)
+	assert.Empty(t, book.MatchSource)
+	assert.Zero(t, book.MatchScore)
+	assert.Empty(t, book.EditionID)
+}
+
+func TestState_RecordMatch(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	statePath := filepath.Join(tempDir, "state.json")
+
+	state := NewState()
+	state.UpdateBook("book1:100", 0.25, "IN_PROGRESS")
+	assert.True(t, state.RecordMatch("book1:100", MatchSourceASIN, 1, "100"))
+	assert.False(t, state.RecordMatch("book1:100", MatchSourceASIN, 1, "100"), "an unchanged match isn't an update")
+
+	// Progress updates keep the recorded match
+	state.UpdateBook("book1:100", 0.5, "IN_PROGRESS")
+	require.NoError(t, state.Save(statePath))
+
+	data, err := os.ReadFile(statePath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"matchSource": "asin"`)
+	assert.Contains(t, string(data), `"editionId": "100"`)
+
+	loaded, err := LoadState(statePath)
+	require.NoError(t, err)
+	for _, key := range []string{"book1:100", "book1"} {
+		book, exists := loaded.GetBookState(key)
+		require.True(t, exists, key)
+		assert.Equal(t, 0.5, book.LastProgress, key)
+		assert.Equal(t, MatchSourceASIN, book.MatchSource, key)
+		assert.Equal(t, 1.0, book.MatchScore, key)
+		assert.Equal(t, "100", book.EditionID, key)
+	}
+}
+
+func TestState_MigrateBookKey(t *testing.T) {
+	t.Parallel()
+
+	t.Run("edition resolved", func(t *testing.T) {
+		state := NewState()
+		state.UpdateBook("book1", 0.25, "NO_EDITION")
+
+		assert.True(t, state.MigrateBookKey("book1:100"))
+		book, exists := state.GetBookState("book1:100")
+		require.True(t, exists)
+		assert.Equal(t, 0.25, book.LastProgress)
+		assert.Equal(t, "NO_EDITION", book.Status)
+
+		// The aggregate entry is kept
+		_, exists = state.GetBookState("book1")
+		assert.True(t, exists)
+	})
+
+	t.Run("edition changed", func(t *testing.T) {
+		state := NewState()
+		state.UpdateBook("book1:100", 0.5, "IN_PROGRESS")
+		assert.True(t, state.RecordMatch("book1:100", MatchSourceASIN, 1, "100"))
+
+		// The progress was written to the old edition, so the new one has no entry and is synced again
+		assert.False(t, state.MigrateBookKey("book1:200"))
+		_, exists := state.GetBookState("book1:200")
+		assert.False(t, exists)
+		_, exists = state.GetBookState("book1:100")
+		assert.False(t, exists, "the old edition's entry is dropped, not orphaned")
+
+		state.UpdateBook("book1:200", 0.5, "IN_PROGRESS")
+		assert.False(t, state.MigrateBookKey("book1:200"), "an existing entry isn't overwritten")
+		book, exists := state.GetBookState("book1:200")
+		require.True(t, exists)
+		assert.Equal(t, 0.5, book.LastProgress)
+	})
+
+	t.Run("unknown book", func(t *testing.T) {
+		state := NewState()
+		state.UpdateBook("book2:100", 0.5, "IN_PROGRESS")
+
+		assert.False(t, state.MigrateBookKey("book1:100"))
+		assert.False(t, state.MigrateBookKey("book1"))
+		_, exists := state.GetBookState("book1:100")
+		assert.False(t, exists)
+	})
+}
+
 func TestLoadState_InvalidJSON(t *testing.T) {
 	t.Parallel()
 
@@ -176,6 +287,66 @@ func TestSetFullSync(t *testing.T) {
 	assert.GreaterOrEqual(t, state.LastFullSync, now)
 }
 
+func TestFirstSeen(t *testing.T) {
+	t.Parallel()
+
+	state := NewState()
+	first := time.Unix(1700000000, 0)
+
+	assert.Equal(t, first.Unix(), state.MarkFirstSeen("book1", first))
+	assert.Equal(t, first.Unix(), state.MarkFirstSeen("book1", first.Add(time.Hour)), "first-seen time should not move")
+
+	// First-seen times survive a save/load round trip
+	path := filepath.Join(t.TempDir(), "state.json")
+	require.NoError(t, state.Save(path))
+	loaded, err := LoadState(path)
+	require.NoError(t, err)
+	assert.Equal(t, first.Unix(), loaded.FirstSeen["book1"])
+
+	firstSeen, ok := state.FirstSeenAt("book1")
+	assert.True(t, ok)
+	assert.Equal(t, first.Unix(), firstSeen)
+	assert.True(t, state.HasFirstSeenAfter(first.Add(-time.Minute)))
+	assert.False(t, state.HasFirstSeenAfter(first))
+
+	state.ClearFirstSeen("book1")
+	assert.NotContains(t, state.FirstSeen, "book1")
+	_, ok = state.FirstSeenAt("book1")
+	assert.False(t, ok)
+}
+
+func TestPruneUnseen(t *testing.T) {
+	t.Parallel()
+
+	state := NewState()
+	state.UpdateBook("deleted:100", 0.5, "IN_PROGRESS")
+	state.UpdateBook("deleted:200", 0.7, "IN_PROGRESS")
+	state.UpdateBook("kept:300", 1.0, "FINISHED")
+	state.UpdateBook("deleted-too", 0.1, "IN_PROGRESS")
+	state.MarkFirstSeen("not-found", time.Now())
+	seen := map[string]struct{}{"kept": {}}
+
+	assert.Empty(t, state.PruneUnseen(seen, 3))
+
+	// An item seen again starts counting from zero
+	assert.Empty(t, state.PruneUnseen(map[string]struct{}{"kept": {}, "deleted-too": {}}, 3))
+	assert.NotContains(t, state.Unseen, "deleted-too")
+	assert.Contains(t, state.Books, "deleted:100", "books are kept until they're missing from three full syncs")
+
+	assert.Equal(t, []string{"deleted", "not-found"}, state.PruneUnseen(seen, 3))
+	assert.NotContains(t, state.Books, "deleted", "the aggregate entry is pruned with the composite keys")
+	assert.NotContains(t, state.Books, "deleted:100")
+	assert.NotContains(t, state.Books, "deleted:200")
+	assert.NotContains(t, state.FirstSeen, "not-found")
+	assert.NotContains(t, state.Unseen, "deleted")
+	assert.Contains(t, state.Books, "kept")
+	assert.Contains(t, state.Books, "kept:300")
+	assert.Contains(t, state.Books, "deleted-too")
+
+	assert.Empty(t, state.PruneUnseen(nil, 0), "a retention of 0 never prunes")
+	assert.Contains(t, state.Books, "kept:300")
+}
+
 func TestCustomStatePathAndPermissions(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,52 @@
+package sync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessBook_ResyncsAfterEditionChange(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.summary = &SyncSummary{}
+	svc.persistentCache = NewPersistentASINCache(t.TempDir())
+	svc.userBookCache = NewPersistentUserBookCache(t.TempDir())
+	svc.config.Sync.Incremental = true
+
+	// The last run synced the book's progress against edition 100. Without an aggregate entry, as
+	// in state files written before it existed, the book isn't skipped before it's looked up.
+	svc.state.UpdateBook("abs-1:100", 0.5, "IN_PROGRESS")
+	delete(svc.state.Books, "abs-1")
+
+	book := newTestBook("abs-1", "Rematched Audiobook")
+	book.Media.Metadata.ASIN = "B000000001"
+
+	// Hardcover now matches the ASIN to edition 200
+	mockClient.On("SearchBookByASIN", mock.Anything, "B000000001").
+		Return(&models.HardcoverBook{ID: "10", EditionID: "200", EditionASIN: "B000000001"}, nil)
+	mockClient.On("GetUserBookID", mock.Anything, 200).Return(300, nil)
+	mockClient.On("GetUserBook", mock.Anything, "300").Return(&models.HardcoverBook{ID: "10", EditionID: "200"}, nil).Maybe()
+	mockClient.On("GetUserBookReads", mock.Anything, mock.Anything).Return([]hardcover.UserBookRead{}, nil)
+	mockClient.On("CheckExistingUserBookRead", mock.Anything, mock.Anything).Return((*hardcover.CheckExistingUserBookReadResult)(nil), nil).Maybe()
+	mockClient.On("InsertUserBookRead", mock.Anything, mock.MatchedBy(func(input hardcover.InsertUserBookReadInput) bool {
+		return input.DatesRead.EditionID != nil && *input.DatesRead.EditionID == 200
+	})).Return(789, nil).Once()
+	mockClient.On("UpdateUserBookStatus", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	require.NoError(t, svc.processBook(context.Background(), book, nil))
+
+	// The progress was written to edition 100, so it's written to edition 200 again instead of the
+	// book being skipped as unchanged
+	mockClient.AssertCalled(t, "InsertUserBookRead", mock.Anything, mock.Anything)
+	bookState, exists := svc.state.GetBookState("abs-1:200")
+	require.True(t, exists)
+	assert.Equal(t, 0.5, bookState.LastProgress)
+	assert.Equal(t, "IN_PROGRESS", bookState.Status)
+	_, exists = svc.state.GetBookState("abs-1:100")
+	assert.False(t, exists, "the old edition's entry is dropped, not orphaned")
+}
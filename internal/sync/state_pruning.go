@@ -0,0 +1,22 @@
+package sync
+
+// pruneUnseenItems drops the sync state of items that weren't fetched in Sync.StateRetentionRuns
+// full syncs in a row, such as items deleted from Audiobookshelf, so the state file doesn't keep
+// growing. seen holds the items fetched by this full sync.
+func (s *Service) pruneUnseenItems(seen map[string]struct{}) {
+	if s.config.Sync.StateRetentionRuns <= 0 || s.state == nil {
+		return
+	}
+
+	pruned := s.state.PruneUnseen(seen, s.config.Sync.StateRetentionRuns)
+	if len(pruned) == 0 {
+		return
+	}
+	s.log.Info("Pruned sync state of items no longer in Audiobookshelf", map[string]interface{}{
+		"items":          len(pruned),
+		"retention_runs": s.config.Sync.StateRetentionRuns,
+	})
+	s.log.Debug("Pruned items", map[string]interface{}{
+		"item_ids": pruned,
+	})
+}
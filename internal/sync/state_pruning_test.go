@@ -0,0 +1,64 @@
+package sync
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/audiobookshelf"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSync_PrunesDeletedItemsWithDefaultConfig(t *testing.T) {
+	svc, _ := createTestService()
+	svc.summary = &SyncSummary{}
+	svc.statePath = filepath.Join(t.TempDir(), "state.json")
+	svc.config = config.DefaultConfig()
+	svc.config.Paths.MismatchOutputDir = t.TempDir()
+	svc.config.Paths.CacheDir = t.TempDir()
+	require.True(t, svc.config.Sync.Incremental)
+
+	// The item was synced before it was deleted from Audiobookshelf
+	svc.state.UpdateBook("deleted-item:100", 0.5, "IN_PROGRESS")
+
+	mockABS := new(MockAudiobookshelfClient)
+	mockABS.On("GetUserProgress", mock.Anything).Return(&models.AudiobookshelfUserProgress{}, nil)
+	mockABS.On("GetLibraries", mock.Anything).Return([]audiobookshelf.AudiobookshelfLibrary{
+		{ID: "lib1", Name: "Audiobooks"},
+	}, nil)
+	mockABS.On("GetLibraryItemsUpdatedSince", mock.Anything, "lib1", mock.Anything).Return([]models.AudiobookshelfBook{}, nil).Maybe()
+	svc.audiobookshelf = mockABS
+
+	fullSync := func() {
+		t.Helper()
+		mockABS.On("GetLibraryItems", mock.Anything, "lib1").Return([]models.AudiobookshelfBook{}, nil).Once()
+		require.NoError(t, svc.Sync(context.Background()))
+		mockABS.AssertExpectations(t)
+	}
+	pastFullSyncInterval := func() {
+		svc.state.LastFullFetch = time.Now().Add(-svc.config.Sync.FullSyncInterval - time.Minute).Unix()
+	}
+
+	// The first run fetches everything
+	fullSync()
+	assert.Equal(t, 1, svc.state.Unseen["deleted-item"])
+
+	// Incremental runs don't count towards the retention
+	require.NoError(t, svc.Sync(context.Background()))
+	mockABS.AssertNumberOfCalls(t, "GetLibraryItemsUpdatedSince", 1)
+	assert.Equal(t, 1, svc.state.Unseen["deleted-item"])
+
+	// Once the full sync interval passed, runs fetch everything again until the item is pruned
+	for run := 2; run <= svc.config.Sync.StateRetentionRuns; run++ {
+		pastFullSyncInterval()
+		fullSync()
+	}
+	_, exists := svc.state.GetBookState("deleted-item:100")
+	assert.False(t, exists, "the deleted item's state should be pruned")
+	assert.NotContains(t, svc.state.Unseen, "deleted-item")
+}
@@ -0,0 +1,199 @@
+package sync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/metrics"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/mismatch"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/sync/state"
+)
+
+// RunSummarySchemaVersion is the version of the RunSummary payload. It only changes when fields are
+// renamed, removed or change meaning; new fields may be added within a version.
+const RunSummarySchemaVersion = 1
+
+// summaryWebhookTimeout bounds posting the run summary, so a slow endpoint can't hold up the next run
+const summaryWebhookTimeout = 10 * time.Second
+
+// Reasons books of a run weren't synced, counted in SyncSummary.SkipReasons
+const (
+	SkipReasonNotFound           = "not_found"
+	SkipReasonTitleAuthorOnly    = "title_author_only"
+	SkipReasonIdentifierMismatch = "identifier_mismatch"
+	SkipReasonTimedOut           = "timed_out"
+	SkipReasonOverDuration       = "over_duration"
+	SkipReasonWeakTitleMatch     = "weak_title_match"
+	SkipReasonAuthorMismatch     = "author_mismatch"
+)
+
+// Run statuses reported in RunSummary.Status
+const (
+	RunStatusCompleted = "completed"
+	RunStatusTimedOut  = "timed_out"
+	RunStatusFailed    = "failed"
+)
+
+// summaryMatchSources and summarySkipReasons are always present in a RunSummary, zero or not
+var (
+	summaryMatchSources = []string{state.MatchSourceASIN, state.MatchSourceISBN, state.MatchSourceTitleAuthor, state.MatchSourceMapping}
+	summarySkipReasons  = []string{SkipReasonNotFound, SkipReasonTitleAuthorOnly, SkipReasonIdentifierMismatch, SkipReasonTimedOut, SkipReasonOverDuration, SkipReasonWeakTitleMatch,
+		SkipReasonAuthorMismatch}
+)
+
+// RunSummary is the JSON payload posted to Sync.SummaryWebhookURL at the end of every run
+type RunSummary struct {
+	SchemaVersion int    `json:"schema_version"`
+	UserID        string `json:"user_id"`
+	// Status is one of the RunStatus constants, with Error set for failed runs
+	Status          string    `json:"status"`
+	Error           string    `json:"error,omitempty"`
+	DryRun          bool      `json:"dry_run"`
+	StartedAt       time.Time `json:"started_at"`
+	FinishedAt      time.Time `json:"finished_at"`
+	DurationSeconds float64   `json:"duration_seconds"`
+	BooksProcessed  int       `json:"books_processed"`
+	BooksSynced     int       `json:"books_synced"`
+	Mismatches      int       `json:"mismatches"`
+	// MatchSources counts the matched books per state.MatchSource constant
+	MatchSources map[string]int `json:"match_sources"`
+	// SkipReasons counts the books that weren't synced per SkipReason constant
+	SkipReasons map[string]int `json:"skip_reasons"`
+}
+
+// addSkipReason counts a book of the current run that wasn't synced. The caller holds the lock.
+func (sum *SyncSummary) addSkipReason(reason string) {
+	if sum.SkipReasons == nil {
+		sum.SkipReasons = make(map[string]int)
+	}
+	sum.SkipReasons[reason]++
+}
+
+// countSkipReason counts a book of the current run that wasn't synced for the given reason
+func (s *Service) countSkipReason(reason string) {
+	if s.summary == nil {
+		return
+	}
+
+	s.summary.Lock()
+	defer s.summary.Unlock()
+	s.summary.addSkipReason(reason)
+}
+
+// countMatchSource counts a book of the current run matched by the given state.MatchSource
+func (s *Service) countMatchSource(source string) {
+	metrics.BookMatched(s.metricsUser(), source)
+	if s.summary == nil {
+		return
+	}
+
+	s.summary.Lock()
+	defer s.summary.Unlock()
+	if s.summary.MatchSources == nil {
+		s.summary.MatchSources = make(map[string]int)
+	}
+	s.summary.MatchSources[source]++
+}
+
+// copyCounts returns a copy of the counts, or nil when there are none
+func copyCounts(counts map[string]int) map[string]int {
+	if counts == nil {
+		return nil
+	}
+	copied := make(map[string]int, len(counts))
+	for key, count := range counts {
+		copied[key] = count
+	}
+	return copied
+}
+
+// runSummary builds the summary of the run started at startedAt that ended with runErr
+func (s *Service) runSummary(startedAt, finishedAt time.Time, runErr error) RunSummary {
+	s.summary.RLock()
+	defer s.summary.RUnlock()
+
+	summary := RunSummary{
+		SchemaVersion:   RunSummarySchemaVersion,
+		UserID:          s.summary.UserID,
+		Status:          RunStatusCompleted,
+		DryRun:          s.config.Sync.DryRun,
+		StartedAt:       startedAt.UTC(),
+		FinishedAt:      finishedAt.UTC(),
+		DurationSeconds: finishedAt.Sub(startedAt).Seconds(),
+		BooksProcessed:  int(s.summary.TotalBooksProcessed),
+		BooksSynced:     int(s.summary.BooksSynced),
+		Mismatches:      len(mismatch.GetAll()),
+		MatchSources:    make(map[string]int, len(summaryMatchSources)),
+		SkipReasons:     make(map[string]int, len(summarySkipReasons)),
+	}
+	switch {
+	case runErr != nil:
+		summary.Status = RunStatusFailed
+		summary.Error = runErr.Error()
+	case s.summary.RunTimedOut:
+		summary.Status = RunStatusTimedOut
+	}
+
+	for _, source := range summaryMatchSources {
+		summary.MatchSources[source] = s.summary.MatchSources[source]
+	}
+	for _, reason := range summarySkipReasons {
+		summary.SkipReasons[reason] = s.summary.SkipReasons[reason]
+	}
+	return summary
+}
+
+// postRunSummary posts the summary of the run to Sync.SummaryWebhookURL when set. Failures are
+// logged, as the webhook doesn't affect the sync itself.
+func (s *Service) postRunSummary(startedAt time.Time, runErr error) {
+	url := s.config.Sync.SummaryWebhookURL
+	if url == "" {
+		return
+	}
+
+	summary := s.runSummary(startedAt, time.Now(), runErr)
+	if err := postJSON(url, summary); err != nil {
+		s.log.Warn("Failed to post run summary to webhook", map[string]interface{}{
+			"url":   url,
+			"error": err.Error(),
+		})
+		return
+	}
+	s.log.Info("Posted run summary to webhook", map[string]interface{}{
+		"url":    url,
+		"status": summary.Status,
+	})
+}
+
+// postJSON posts the payload as JSON to the URL, expecting a 2xx response
+func postJSON(url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	// Not tied to the run's context, so canceled runs are still reported
+	ctx, cancel := context.WithTimeout(context.Background(), summaryWebhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
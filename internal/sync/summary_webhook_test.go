@@ -0,0 +1,124 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/audiobookshelf"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSync_SummaryWebhook(t *testing.T) {
+	payloads := make(chan map[string]interface{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var payload map[string]interface{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		payloads <- payload
+	}))
+	defer server.Close()
+
+	svc, mockClient := createTestService()
+	svc.summary = &SyncSummary{UserID: "user-1"}
+	svc.statePath = filepath.Join(t.TempDir(), "state.json")
+	svc.persistentCache = NewPersistentASINCache(t.TempDir())
+	svc.config.Paths.MismatchOutputDir = t.TempDir()
+	svc.config.Sync.SummaryWebhookURL = server.URL
+	// Ownership only keeps the matched book's processing short
+	svc.config.Sync.OwnershipOnly = true
+	svc.config.Sync.SyncOwned = false
+
+	found := models.AudiobookshelfBook{ID: "abs-found", LibraryID: "lib1", MediaType: "book"}
+	found.Media.Metadata.Title = "Found Audiobook"
+	found.Media.Metadata.ASIN = "B000000001"
+	missing := models.AudiobookshelfBook{ID: "abs-missing", LibraryID: "lib1", MediaType: "book"}
+	missing.Media.Metadata.Title = "Missing Audiobook"
+	missing.Media.Metadata.ASIN = "B000000002"
+
+	mockClient.On("SearchBookByASINAnyFormat", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	mockClient.On("SearchBookByASIN", mock.Anything, "B000000001").
+		Return(&models.HardcoverBook{ID: "10", EditionID: "100", EditionASIN: "B000000001"}, nil)
+	mockClient.On("SearchBookByASIN", mock.Anything, "B000000002").Return(nil, nil)
+	mockClient.On("SearchBooks", mock.Anything, mock.Anything, mock.Anything).Return([]models.HardcoverBook{}, nil).Maybe()
+	mockClient.On("CheckBookOwnership", mock.Anything, 10).Return(true, nil)
+
+	mockABS := new(MockAudiobookshelfClient)
+	mockABS.On("GetUserProgress", mock.Anything).Return(&models.AudiobookshelfUserProgress{}, nil)
+	mockABS.On("GetLibraries", mock.Anything).Return([]audiobookshelf.AudiobookshelfLibrary{{ID: "lib1", Name: "Audiobooks"}}, nil)
+	mockABS.On("GetLibraryItems", mock.Anything, "lib1").Return([]models.AudiobookshelfBook{found, missing}, nil)
+	svc.audiobookshelf = mockABS
+
+	require.NoError(t, svc.Sync(context.Background()))
+
+	var payload map[string]interface{}
+	select {
+	case payload = <-payloads:
+	case <-time.After(5 * time.Second):
+		t.Fatal("run summary wasn't posted")
+	}
+
+	// The schema is stable: exactly these fields, with every match source and skip reason present
+	keys := make([]string, 0, len(payload))
+	for key := range payload {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	assert.Equal(t, []string{
+		"books_processed", "books_synced", "dry_run", "duration_seconds", "finished_at", "match_sources",
+		"mismatches", "schema_version", "skip_reasons", "started_at", "status", "user_id",
+	}, keys)
+
+	assert.Equal(t, float64(RunSummarySchemaVersion), payload["schema_version"])
+	assert.Equal(t, "user-1", payload["user_id"])
+	assert.Equal(t, RunStatusCompleted, payload["status"])
+	assert.Equal(t, false, payload["dry_run"])
+	assert.Equal(t, float64(2), payload["books_processed"])
+	assert.GreaterOrEqual(t, payload["duration_seconds"].(float64), 0.0)
+	for _, field := range []string{"started_at", "finished_at"} {
+		_, err := time.Parse(time.RFC3339Nano, payload[field].(string))
+		assert.NoError(t, err, field)
+	}
+	assert.Equal(t, map[string]interface{}{
+		"asin": float64(1), "isbn": float64(0), "title-author": float64(0), "mapping": float64(0),
+	}, payload["match_sources"])
+	assert.Equal(t, map[string]interface{}{
+		"not_found": float64(1), "title_author_only": float64(0), "identifier_mismatch": float64(0), "timed_out": float64(0),
+		"over_duration": float64(0),
+		"weak_title_match": float64(0), "author_mismatch": float64(0),
+	}, payload["skip_reasons"])
+}
+
+func TestRunSummary_Failed(t *testing.T) {
+	svc, _ := createTestService()
+	svc.summary = &SyncSummary{}
+
+	startedAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	summary := svc.runSummary(startedAt, startedAt.Add(90*time.Second), errors.New("failed to fetch libraries"))
+	assert.Equal(t, RunStatusFailed, summary.Status)
+	assert.Equal(t, "failed to fetch libraries", summary.Error)
+	assert.Equal(t, 90.0, summary.DurationSeconds)
+	assert.Len(t, summary.MatchSources, len(summaryMatchSources))
+	assert.Len(t, summary.SkipReasons, len(summarySkipReasons))
+}
+
+func TestPostJSON_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	err := postJSON(server.URL, RunSummary{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "500")
+}
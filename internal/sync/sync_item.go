@@ -0,0 +1,78 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/audiobookshelf"
+)
+
+// SyncItem syncs a single library item, e.g. after Audiobookshelf reported progress on it, without
+// fetching the other items of its library. The item is skipped when its library is excluded from
+// syncing. The sync state and caches are saved afterwards like at the end of a full sync.
+func (s *Service) SyncItem(ctx context.Context, itemID string) error {
+	itemLog := s.log.With(map[string]interface{}{
+		"item_id": itemID,
+	})
+
+	// Don't hit the APIs again while paused after repeated authentication failures
+	if until := s.AuthBackoffUntil(); time.Now().Before(until) {
+		return fmt.Errorf("%w until %s", ErrAuthBackoff, until.Format(time.RFC3339))
+	}
+
+	book, err := s.audiobookshelf.GetLibraryItem(ctx, itemID)
+	if authErr := s.recordAuthResult(err); authErr != nil {
+		return authErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get library item %s: %w", itemID, err)
+	}
+
+	libraries, err := s.audiobookshelf.GetLibraries(ctx)
+	if authErr := s.recordAuthResult(err); authErr != nil {
+		return authErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch libraries: %w", err)
+	}
+	var library *audiobookshelf.AudiobookshelfLibrary
+	for i := range libraries {
+		if libraries[i].ID == book.LibraryID {
+			library = &libraries[i]
+			break
+		}
+	}
+	if library == nil || !s.shouldSyncLibrary(library) {
+		itemLog.Info("Skipping item sync, its library isn't synced", map[string]interface{}{
+			"library_id": book.LibraryID,
+		})
+		return nil
+	}
+
+	userProgress, err := s.fetchUserProgress(ctx)
+	if authErr := s.recordAuthResult(err); authErr != nil {
+		return authErr
+	}
+	s.loadHardcoverFinished(ctx)
+	// Fetching all of the user's books would cost more than looking up the one of this item
+	s.userBooks = nil
+	s.editionUserBooks = nil
+	s.loadEditionOverrides(ctx)
+	s.loadBlocklist()
+
+	itemLog.Info("Syncing single library item", map[string]interface{}{
+		"title": book.Media.Metadata.Title,
+	})
+	err = s.processBookWithTimeout(s.withLibraryOverride(ctx, library), *book, userProgress)
+	if authErr := s.recordAuthResult(err); authErr != nil {
+		return authErr
+	}
+
+	s.saveStateAndCaches()
+
+	if err != nil && err != ErrSkippedBook {
+		return fmt.Errorf("failed to sync library item %s: %w", itemID, err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package sync
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/audiobookshelf"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncItem(t *testing.T) {
+	newBook := func(id, libraryID string) *models.AudiobookshelfBook {
+		book := newTestBook(id, "Unmatchable Audiobook")
+		book.LibraryID = libraryID
+		book.Progress.CurrentTime = 600
+		return &book
+	}
+	libraries := []audiobookshelf.AudiobookshelfLibrary{
+		{ID: "lib1", Name: "Audiobooks"},
+		{ID: "lib2", Name: "Kids"},
+	}
+
+	newService := func(t *testing.T) (*Service, *MockAudiobookshelfClient) {
+		svc, _ := createTestService()
+		svc.summary = &SyncSummary{}
+		svc.statePath = filepath.Join(t.TempDir(), "sync_state.json")
+		svc.config.Sync.Libraries.Exclude = []string{"Kids"}
+		svc.config.Sync.SkipHardcoverFinished = false
+
+		absClient := new(MockAudiobookshelfClient)
+		absClient.On("GetLibraries", mock.Anything).Return(libraries, nil)
+		absClient.On("GetUserProgress", mock.Anything).Return(&models.AudiobookshelfUserProgress{}, nil).Maybe()
+		svc.audiobookshelf = absClient
+		return svc, absClient
+	}
+
+	t.Run("syncs only the given item", func(t *testing.T) {
+		svc, absClient := newService(t)
+		svc.config.Sync.PrefetchUserBooks = true
+		absClient.On("GetLibraryItem", mock.Anything, "li_1").Return(newBook("li_1", "lib1"), nil)
+
+		require.NoError(t, svc.SyncItem(context.Background(), "li_1"))
+		assert.Equal(t, []string{"li_1"}, lookedUpBooks(svc.summary))
+		assert.FileExists(t, svc.statePath)
+		absClient.AssertNotCalled(t, "GetLibraryItems", mock.Anything, mock.Anything)
+		// The user's books aren't all fetched for one item
+		svc.hardcover.(*MockHardcoverClient).AssertNotCalled(t, "GetUserBookRefs", mock.Anything)
+	})
+
+	t.Run("saves the caches", func(t *testing.T) {
+		svc, absClient := newService(t)
+		cacheDir := t.TempDir()
+		svc.progressCache = NewPersistentProgressCache(cacheDir)
+		svc.titleSearchCache = NewPersistentTitleSearchCache(cacheDir, time.Hour)
+		absClient.On("GetLibraryItem", mock.Anything, "li_1").Return(newBook("li_1", "lib1"), nil)
+
+		// Progress written and a title searched while syncing the item
+		svc.lastProgressUpdates["li_1:300"] = progressUpdateInfo{timestamp: time.Now(), progress: 600}
+		svc.titleSearchCache.Set("Unmatchable Audiobook", "", "")
+
+		require.NoError(t, svc.SyncItem(context.Background(), "li_1"))
+		progress, err := NewPersistentProgressCache(cacheDir).Load(time.Hour)
+		require.NoError(t, err)
+		assert.Contains(t, progress, "li_1:300")
+		titleSearches := NewPersistentTitleSearchCache(cacheDir, time.Hour)
+		require.NoError(t, titleSearches.Load())
+		_, cached := titleSearches.Get("Unmatchable Audiobook", "")
+		assert.True(t, cached)
+	})
+
+	t.Run("skips items of excluded libraries", func(t *testing.T) {
+		svc, absClient := newService(t)
+		absClient.On("GetLibraryItem", mock.Anything, "li_2").Return(newBook("li_2", "lib2"), nil)
+
+		require.NoError(t, svc.SyncItem(context.Background(), "li_2"))
+		assert.Empty(t, lookedUpBooks(svc.summary))
+		assert.Zero(t, svc.summary.TotalBooksProcessed)
+	})
+
+	t.Run("reports unknown items", func(t *testing.T) {
+		svc, absClient := newService(t)
+		absClient.On("GetLibraryItem", mock.Anything, "missing").Return(nil, assert.AnError)
+
+		assert.ErrorIs(t, svc.SyncItem(context.Background(), "missing"), assert.AnError)
+	})
+}
@@ -2,16 +2,19 @@ package sync
 
 import (
 	"context"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/audiobookshelf"
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/mismatch"
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
 	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/sync/state"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // AudiobookshelfClientInterface defines the interface for audiobookshelf.Client
@@ -46,7 +49,34 @@ func (m *MockAudiobookshelfClient) GetLibraryItems(ctx context.Context, libraryI
 	return args.Get(0).([]models.AudiobookshelfBook), args.Error(1)
 }
 
+// GetLibraryItem mocks the GetLibraryItem method
+func (m *MockAudiobookshelfClient) GetLibraryItem(ctx context.Context, itemID string) (*models.AudiobookshelfBook, error) {
+	args := m.Called(ctx, itemID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AudiobookshelfBook), args.Error(1)
+}
+
 // GetUserProgress mocks the GetUserProgress method
+// GetLibraryItemsUpdatedSince mocks the GetLibraryItemsUpdatedSince method
+func (m *MockAudiobookshelfClient) GetLibraryItemsUpdatedSince(ctx context.Context, libraryID string, since time.Time) ([]models.AudiobookshelfBook, error) {
+	args := m.Called(ctx, libraryID, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.AudiobookshelfBook), args.Error(1)
+}
+
+// GetLibraryItemsPage mocks the GetLibraryItemsPage method
+func (m *MockAudiobookshelfClient) GetLibraryItemsPage(ctx context.Context, libraryID string, since time.Time, page, limit int) ([]models.AudiobookshelfBook, bool, error) {
+	args := m.Called(ctx, libraryID, since, page, limit)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).([]models.AudiobookshelfBook), args.Bool(1), args.Error(2)
+}
+
 func (m *MockAudiobookshelfClient) GetUserProgress(ctx context.Context) (*models.AudiobookshelfUserProgress, error) {
 	args := m.Called(ctx)
 	if args.Get(0) == nil {
@@ -64,6 +94,21 @@ func (m *MockAudiobookshelfClient) GetListeningSessions(ctx context.Context, sin
 	return args.Get(0).([]models.AudiobookshelfBook), args.Error(1)
 }
 
+// GetCollections mocks the GetCollections method
+func (m *MockAudiobookshelfClient) GetCollections(ctx context.Context) ([]audiobookshelf.AudiobookshelfCollection, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]audiobookshelf.AudiobookshelfCollection), args.Error(1)
+}
+
+// UpdateMediaProgress mocks the UpdateMediaProgress method
+func (m *MockAudiobookshelfClient) UpdateMediaProgress(ctx context.Context, itemID string, currentTime float64, isFinished bool) error {
+	args := m.Called(ctx, itemID, currentTime, isFinished)
+	return args.Error(0)
+}
+
 // TestSync tests the Sync function
 func TestSync(t *testing.T) {
 	// Skip this test for now until all struct issues are fixed
@@ -145,17 +190,19 @@ func TestSync(t *testing.T) {
 		ID: "user1",
 		Username: "testuser",
 		MediaProgress: []struct {
-			ID            string  `json:"id"`
-			LibraryItemID string  `json:"libraryItemId"`
-			UserID        string  `json:"userId"`
-			IsFinished    bool    `json:"isFinished"`
-			Progress      float64 `json:"progress"`
-			CurrentTime   float64 `json:"currentTime"`
-			Duration      float64 `json:"duration"`
-			StartedAt     int64   `json:"startedAt"`
-			FinishedAt    int64   `json:"finishedAt"`
-			LastUpdate    int64   `json:"lastUpdate"`
-			TimeListening float64 `json:"timeListening"`
+			ID                        string  `json:"id"`
+			LibraryItemID             string  `json:"libraryItemId"`
+			UserID                    string  `json:"userId"`
+			IsFinished                bool    `json:"isFinished"`
+			Progress                  float64 `json:"progress"`
+			CurrentTime               float64 `json:"currentTime"`
+			Duration                  float64 `json:"duration"`
+			StartedAt                 int64   `json:"startedAt"`
+			FinishedAt                int64   `json:"finishedAt"`
+			LastUpdate                int64   `json:"lastUpdate"`
+			TimeListening             float64 `json:"timeListening"`
+			EbookProgress             float64 `json:"ebookProgress"`
+			HideFromContinueListening bool    `json:"hideFromContinueListening"`
 		}{},
 		ListeningSessions: []struct {
 			ID            string `json:"id"`
@@ -277,17 +324,19 @@ func TestProcessLibrary(t *testing.T) {
 		ID: "user1",
 		Username: "testuser",
 		MediaProgress: []struct {
-			ID            string  `json:"id"`
-			LibraryItemID string  `json:"libraryItemId"`
-			UserID        string  `json:"userId"`
-			IsFinished    bool    `json:"isFinished"`
-			Progress      float64 `json:"progress"`
-			CurrentTime   float64 `json:"currentTime"`
-			Duration      float64 `json:"duration"`
-			StartedAt     int64   `json:"startedAt"`
-			FinishedAt    int64   `json:"finishedAt"`
-			LastUpdate    int64   `json:"lastUpdate"`
-			TimeListening float64 `json:"timeListening"`
+			ID                        string  `json:"id"`
+			LibraryItemID             string  `json:"libraryItemId"`
+			UserID                    string  `json:"userId"`
+			IsFinished                bool    `json:"isFinished"`
+			Progress                  float64 `json:"progress"`
+			CurrentTime               float64 `json:"currentTime"`
+			Duration                  float64 `json:"duration"`
+			StartedAt                 int64   `json:"startedAt"`
+			FinishedAt                int64   `json:"finishedAt"`
+			LastUpdate                int64   `json:"lastUpdate"`
+			TimeListening             float64 `json:"timeListening"`
+			EbookProgress             float64 `json:"ebookProgress"`
+			HideFromContinueListening bool    `json:"hideFromContinueListening"`
 		}{},
 		ListeningSessions: []struct {
 			ID            string `json:"id"`
@@ -340,6 +389,7 @@ func TestProcessLibrary(t *testing.T) {
 					Metadata  models.AudiobookshelfMetadataStruct `json:"metadata"`
 					CoverPath string                              `json:"coverPath"`
 					Duration  float64                             `json:"duration"`
+					Tags      []string                            `json:"tags,omitempty"`
 				}{
 					ID: "media1",
 					Metadata: models.AudiobookshelfMetadataStruct{
@@ -352,10 +402,12 @@ func TestProcessLibrary(t *testing.T) {
 					Duration:  3600,
 				},
 				Progress: struct {
-					CurrentTime float64 `json:"currentTime"`
-					IsFinished  bool    `json:"isFinished"`
-					StartedAt   int64   `json:"startedAt"`
-					FinishedAt  int64   `json:"finishedAt"`
+					CurrentTime               float64 `json:"currentTime"`
+					IsFinished                bool    `json:"isFinished"`
+					StartedAt                 int64   `json:"startedAt"`
+					FinishedAt                int64   `json:"finishedAt"`
+					LastUpdate                int64   `json:"lastUpdate,omitempty"`
+					HideFromContinueListening bool    `json:"hideFromContinueListening,omitempty"`
 				}{
 					CurrentTime: 0,
 					IsFinished:  false,
@@ -404,3 +456,194 @@ func TestProcessLibrary(t *testing.T) {
 
 // TestProcessBook and TestFindBookInHardcover functions would follow the same pattern
 // with their test configurations updated similarly to the above examples.
+
+// TestSync_IncludeFilterLimitsLibraries verifies that only the included library is processed,
+// which is how the --limit-library flag restricts one-time syncs
+func TestSync_IncludeFilterLimitsLibraries(t *testing.T) {
+	svc, _ := createTestService()
+	svc.summary = &SyncSummary{}
+	svc.statePath = filepath.Join(t.TempDir(), "state.json")
+	svc.config.Paths.MismatchOutputDir = t.TempDir()
+	svc.config.Sync.Libraries.Include = []string{"lib2"}
+	svc.config.Sync.Libraries.Exclude = nil
+
+	mockABS := new(MockAudiobookshelfClient)
+	mockABS.On("GetUserProgress", mock.Anything).Return(&models.AudiobookshelfUserProgress{}, nil)
+	mockABS.On("GetLibraries", mock.Anything).Return([]audiobookshelf.AudiobookshelfLibrary{
+		{ID: "lib1", Name: "Audiobooks"},
+		{ID: "lib2", Name: "Podcasts"},
+	}, nil)
+	mockABS.On("GetLibraryItems", mock.Anything, "lib2").Return([]models.AudiobookshelfBook{}, nil)
+	svc.audiobookshelf = mockABS
+
+	err := svc.Sync(context.Background())
+	assert.NoError(t, err)
+
+	mockABS.AssertExpectations(t)
+	mockABS.AssertNotCalled(t, "GetLibraryItems", mock.Anything, "lib1")
+}
+
+// TestSync_IncrementalFetchesUpdatedItems verifies that after a completed sync, incremental
+// runs only fetch items updated since that sync started
+func TestSync_IncrementalFetchesUpdatedItems(t *testing.T) {
+	svc, _ := createTestService()
+	svc.summary = &SyncSummary{}
+	svc.statePath = filepath.Join(t.TempDir(), "state.json")
+	svc.config.Paths.MismatchOutputDir = t.TempDir()
+	svc.config.Sync.Incremental = true
+
+	mockABS := new(MockAudiobookshelfClient)
+	mockABS.On("GetUserProgress", mock.Anything).Return(&models.AudiobookshelfUserProgress{}, nil)
+	mockABS.On("GetLibraries", mock.Anything).Return([]audiobookshelf.AudiobookshelfLibrary{
+		{ID: "lib1", Name: "Audiobooks"},
+	}, nil)
+	mockABS.On("GetLibraryItems", mock.Anything, "lib1").Return([]models.AudiobookshelfBook{}, nil).Once()
+	svc.audiobookshelf = mockABS
+
+	// The first run has no previous sync, so it fetches everything
+	require.NoError(t, svc.Sync(context.Background()))
+	lastSync, ok := svc.state.GetLibraryState("sync")
+	require.True(t, ok)
+
+	mockABS.On("GetLibraryItemsUpdatedSince", mock.Anything, "lib1", time.Unix(lastSync.LastUpdated, 0)).
+		Return([]models.AudiobookshelfBook{}, nil).Once()
+
+	require.NoError(t, svc.Sync(context.Background()))
+	mockABS.AssertExpectations(t)
+
+	// Progress made on Hardcover doesn't update Audiobookshelf items, so all are fetched to pull it
+	svc.config.Sync.Direction = config.SyncDirectionBoth
+	mockABS.On("GetLibraryItems", mock.Anything, "lib1").Return([]models.AudiobookshelfBook{}, nil).Once()
+	require.NoError(t, svc.Sync(context.Background()))
+	mockABS.AssertExpectations(t)
+	svc.config.Sync.Direction = config.SyncDirectionABSToHC
+
+	// A full fetch is used again when incremental mode is off
+	svc.config.Sync.Incremental = false
+	mockABS.On("GetLibraryItems", mock.Anything, "lib1").Return([]models.AudiobookshelfBook{}, nil).Once()
+	require.NoError(t, svc.Sync(context.Background()))
+	mockABS.AssertExpectations(t)
+}
+
+func TestIncrementalSince_FullSyncInterval(t *testing.T) {
+	svc, _ := createTestService()
+	svc.config.Sync.Incremental = true
+	svc.config.Sync.FullSyncInterval = 24 * time.Hour
+	svc.state.UpdateLibrary("sync")
+	svc.state.SetFullSync()
+
+	assert.True(t, svc.incrementalSince().IsZero(), "no full fetch yet")
+
+	svc.state.SetFullFetch()
+	assert.False(t, svc.incrementalSince().IsZero(), "full fetch within the interval")
+
+	svc.state.LastFullFetch = time.Now().Add(-25 * time.Hour).Unix()
+	assert.True(t, svc.incrementalSince().IsZero(), "full fetch older than the interval")
+
+	svc.config.Sync.FullSyncInterval = 0
+	assert.False(t, svc.incrementalSince().IsZero(), "full syncs not scheduled")
+}
+
+func TestIncrementalSince_IncompleteRun(t *testing.T) {
+	svc, _ := createTestService()
+	svc.config.Sync.Incremental = true
+
+	assert.True(t, svc.incrementalSince().IsZero(), "no previous sync")
+
+	svc.state.UpdateLibrary("sync")
+	svc.state.SetFullSync()
+	svc.state.SetFullFetch()
+	assert.False(t, svc.incrementalSince().IsZero(), "previous sync completed")
+
+	// Simulate a run that started after the last completed sync and never finished
+	svc.state.LastFullSync = 1
+	assert.True(t, svc.incrementalSince().IsZero(), "previous sync did not complete")
+}
+
+// TestSync_RetriesFailedBooks verifies that a run with failed books doesn't complete, so the next
+// incremental run fetches the failed books again
+func TestSync_RetriesFailedBooks(t *testing.T) {
+	svc, hcClient := createTestService()
+	svc.summary = &SyncSummary{}
+	svc.statePath = filepath.Join(t.TempDir(), "state.json")
+	svc.persistentCache = NewPersistentASINCache(t.TempDir())
+	svc.userBookCache = NewPersistentUserBookCache(t.TempDir())
+	svc.config.Paths.MismatchOutputDir = t.TempDir()
+	svc.config.Sync.Incremental = true
+
+	book := newTestBook("li_1", "Failing Audiobook")
+	book.Media.Metadata.ASIN = "B000000001"
+	book.Progress.CurrentTime = 600
+
+	// Hardcover fails while the book's user book is looked up
+	hcClient.On("SearchBookByASIN", mock.Anything, "B000000001").
+		Return(&models.HardcoverBook{ID: "10", EditionID: "200", EditionASIN: "B000000001"}, nil)
+	hcClient.On("GetUserBookID", mock.Anything, 200).Return(0, assert.AnError)
+	hcClient.On("GetUserBooksByEditionIDs", mock.Anything, mock.Anything).Return(nil, assert.AnError).Maybe()
+
+	mockABS := new(MockAudiobookshelfClient)
+	mockABS.On("GetUserProgress", mock.Anything).Return(&models.AudiobookshelfUserProgress{}, nil)
+	mockABS.On("GetLibraries", mock.Anything).Return([]audiobookshelf.AudiobookshelfLibrary{
+		{ID: "lib1", Name: "Audiobooks"},
+	}, nil)
+	mockABS.On("GetLibraryItems", mock.Anything, "lib1").Return([]models.AudiobookshelfBook{book}, nil).Twice()
+	svc.audiobookshelf = mockABS
+
+	require.NoError(t, svc.Sync(context.Background()))
+	require.Equal(t, 1, svc.failedBookCount())
+	assert.Equal(t, "li_1", svc.summary.BooksFailed[0].BookID)
+	assert.Zero(t, svc.state.LastFullSync, "a run with failed books doesn't complete")
+
+	// The next run fetches all items again instead of only the updated ones
+	require.NoError(t, svc.Sync(context.Background()))
+	mockABS.AssertExpectations(t)
+	mockABS.AssertNotCalled(t, "GetLibraryItemsUpdatedSince", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestSync_IncrementalKeepsMismatchReport verifies that the mismatch report of an incremental run
+// keeps the mismatches of the items the run didn't fetch
+func TestSync_IncrementalKeepsMismatchReport(t *testing.T) {
+	mismatch.Clear()
+	t.Cleanup(mismatch.Clear)
+
+	svc, _ := createTestService()
+	svc.summary = &SyncSummary{}
+	svc.statePath = filepath.Join(t.TempDir(), "state.json")
+	svc.mismatchReport = NewMismatchReport(t.TempDir())
+	svc.config.Paths.MismatchOutputDir = t.TempDir()
+	svc.config.Sync.Incremental = true
+
+	// The previous run completed and reported both items
+	svc.state.UpdateLibrary("sync")
+	svc.state.SetFullSync()
+	svc.state.SetFullFetch()
+	require.NoError(t, svc.mismatchReport.Save([]mismatch.BookMismatch{
+		{BookID: "li_1", ItemID: "li_1", Title: "Unchanged Audiobook", Reason: "could not find book in Hardcover"},
+		{BookID: "li_2", ItemID: "li_2", Title: "Updated Audiobook", Reason: "could not find book in Hardcover"},
+	}))
+
+	// Only the updated item is fetched, and it's no longer a mismatch
+	updated := newTestBook("li_2", "Updated Audiobook")
+	updated.Progress.CurrentTime = 600
+	mockABS := new(MockAudiobookshelfClient)
+	mockABS.On("GetUserProgress", mock.Anything).Return(&models.AudiobookshelfUserProgress{}, nil)
+	mockABS.On("GetLibraries", mock.Anything).Return([]audiobookshelf.AudiobookshelfLibrary{
+		{ID: "lib1", Name: "Audiobooks"},
+	}, nil)
+	mockABS.On("GetLibraryItemsUpdatedSince", mock.Anything, "lib1", mock.Anything).Return([]models.AudiobookshelfBook{updated}, nil).Once()
+	svc.audiobookshelf = mockABS
+
+	require.NoError(t, svc.Sync(context.Background()))
+	mockABS.AssertExpectations(t)
+	assert.Empty(t, svc.summary.Mismatches, "the restored mismatches aren't this run's")
+
+	// The unchanged item keeps its mismatch, also for the next run
+	all := mismatch.GetAll()
+	require.Len(t, all, 1)
+	assert.Equal(t, "li_1", all[0].ID())
+	assert.FileExists(t, filepath.Join(svc.config.Paths.MismatchOutputDir, "edition_001_Unchanged Audiobook.json"))
+	saved, err := svc.mismatchReport.Load()
+	require.NoError(t, err)
+	require.Len(t, saved, 1)
+	assert.Equal(t, "li_1", saved[0].ID())
+}
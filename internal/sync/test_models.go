@@ -55,6 +55,7 @@ func toAudiobookshelfBook(testBook *TestAudiobookshelfBook) *models.Audiobookshe
 		Metadata  models.AudiobookshelfMetadataStruct `json:"metadata"`
 		CoverPath string                          `json:"coverPath"`
 		Duration  float64                         `json:"duration"`
+		Tags      []string                        `json:"tags,omitempty"`
 	}
 	media.ID = testBook.Media.ID
 	media.Metadata = metadata
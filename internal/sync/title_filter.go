@@ -0,0 +1,34 @@
+package sync
+
+import (
+	"regexp"
+)
+
+// compileTitlePatterns compiles the configured title exclusion patterns, skipping invalid ones
+func (s *Service) compileTitlePatterns() []*regexp.Regexp {
+	patterns := make([]*regexp.Regexp, 0, len(s.config.Sync.ExcludeTitlePatterns))
+	for _, pattern := range s.config.Sync.ExcludeTitlePatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			s.log.Warn("Ignoring invalid title exclusion pattern", map[string]interface{}{
+				"pattern": pattern,
+				"error":   err.Error(),
+			})
+			continue
+		}
+		patterns = append(patterns, re)
+	}
+	return patterns
+}
+
+// matchExcludedTitle returns the first pattern that excludes resultTitle, or "" if none does.
+// A pattern that also matches the Audiobookshelf title is not applied, so that searching for
+// e.g. an actual "Summary of ..." book still finds it.
+func matchExcludedTitle(patterns []*regexp.Regexp, absTitle, resultTitle string) string {
+	for _, re := range patterns {
+		if re.MatchString(resultTitle) && !re.MatchString(absTitle) {
+			return re.String()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,82 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchExcludedTitle(t *testing.T) {
+	svc, _ := createTestService()
+	svc.config.Sync.ExcludeTitlePatterns = config.DefaultExcludeTitlePatterns
+	patterns := svc.compileTitlePatterns()
+	require.Len(t, patterns, len(config.DefaultExcludeTitlePatterns))
+
+	assert.NotEmpty(t, matchExcludedTitle(patterns, "Atomic Habits", "Summary of Atomic Habits"))
+	assert.NotEmpty(t, matchExcludedTitle(patterns, "Atomic Habits", "SUMMARY: Atomic Habits"))
+	assert.NotEmpty(t, matchExcludedTitle(patterns, "Atomic Habits", "Summary Atomic Habits"))
+	assert.Empty(t, matchExcludedTitle(patterns, "Atomic Habits", "Atomic Habits"))
+	// Not applied when the original title matches the same pattern
+	assert.Empty(t, matchExcludedTitle(patterns, "Summary of a Summary", "Summary of a Summary"))
+
+	t.Run("invalid patterns are ignored", func(t *testing.T) {
+		svc.config.Sync.ExcludeTitlePatterns = []string{"(unclosed", "(?i)abridged"}
+		assert.Len(t, svc.compileTitlePatterns(), 1)
+	})
+}
+
+func TestFindBookInHardcoverByTitleAuthor_ExcludeTitlePatterns(t *testing.T) {
+	book := models.AudiobookshelfBook{ID: "abs-habits"}
+	book.Media.Metadata.Title = "Atomic Habits"
+	book.Media.Metadata.AuthorName = "James Clear"
+
+	searchResults := []models.HardcoverBook{
+		{ID: "hc-summary", Title: "Summary of Atomic Habits"},
+		{ID: "hc-other", Title: "Habits of Highly Effective People"},
+	}
+
+	tests := []struct {
+		name       string
+		patterns   []string
+		expectedID string
+	}{
+		{
+			name:       "default patterns skip summary books",
+			patterns:   config.DefaultExcludeTitlePatterns,
+			expectedID: "hc-other",
+		},
+		{
+			name:       "custom patterns replace the defaults",
+			patterns:   []string{`(?i)effective people`},
+			expectedID: "hc-summary",
+		},
+		{
+			name:       "empty list disables filtering",
+			patterns:   []string{},
+			expectedID: "hc-summary",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, mockClient := createTestService()
+			svc.config.Sync.ExcludeTitlePatterns = tt.patterns
+			// Only the filtering is tested here, the remaining results are weak matches
+			svc.config.Sync.TitleMatchThreshold = 0
+
+			mockClient.On("SearchBooks", mock.Anything, "Atomic Habits James Clear", "").Return(searchResults, nil)
+			mockClient.On("GetBookByID", mock.Anything, mock.Anything).Return(nil, errors.New("not found")).Maybe()
+			mockClient.On("GetBookAuthors", mock.Anything, mock.Anything).Return([]models.Author{{Name: "James Clear"}}, nil).Maybe()
+
+			hcBook, _ := svc.findBookInHardcoverByTitleAuthor(context.Background(), book)
+			require.NotNil(t, hcBook)
+			assert.Equal(t, tt.expectedID, hcBook.ID)
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/mismatch"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// errWeakTitleMatch is returned by findBookInHardcoverByTitleAuthor when no search result is at
+// least Sync.TitleMatchThreshold similar to the book's title
+var errWeakTitleMatch = errors.New("no title/author match above the similarity threshold")
+
+// errAuthorMismatch is returned by findBookInHardcoverByTitleAuthor when the search results similar
+// enough to the book's title are by other authors
+var errAuthorMismatch = errors.New("no title/author match by the book's author")
+
+// maxAuthorVerifications is how many of the best search results have their authors fetched before
+// giving up on a title/author match
+const maxAuthorVerifications = 5
+
+// titleMatchCandidate is a title/author search result with its title similarity
+type titleMatchCandidate struct {
+	book  *models.HardcoverBook
+	score float64
+}
+
+// weakTitleMatchError describes the best search result that was rejected, if there was one
+func weakTitleMatchError(bestMatch *models.HardcoverBook, score, threshold float64) error {
+	if bestMatch == nil {
+		return fmt.Errorf("%w of %.2f: no search result left after filtering", errWeakTitleMatch, threshold)
+	}
+	return fmt.Errorf("%w of %.2f: best result %q (ID: %s) scored %.2f",
+		errWeakTitleMatch, threshold, bestMatch.Title, bestMatch.ID, score)
+}
+
+// selectCandidateByAuthor returns the most similar candidate, at least Sync.TitleMatchThreshold
+// similar to the title, whose Hardcover authors include one of the book's authors. Candidates whose
+// authors can't be fetched are taken as is. errAuthorMismatch is returned when all of them are by
+// other authors.
+func (s *Service) selectCandidateByAuthor(ctx context.Context, candidates []titleMatchCandidate, author string, log *logger.Logger) (*models.HardcoverBook, float64, error) {
+	sorted := append([]titleMatchCandidate(nil), candidates...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].score > sorted[j].score
+	})
+
+	var rejected []string
+	for i, candidate := range sorted {
+		if i >= maxAuthorVerifications || candidate.score < s.config.Sync.TitleMatchThreshold {
+			break
+		}
+
+		authors, err := s.hardcover.GetBookAuthors(ctx, candidate.book.ID)
+		if err != nil || len(authors) == 0 {
+			fields := map[string]interface{}{
+				"book_id":     candidate.book.ID,
+				"match_title": candidate.book.Title,
+				"match_score": candidate.score,
+			}
+			if err != nil {
+				fields["error"] = err.Error()
+			}
+			log.Warn("Couldn't verify the authors of the best matching book, selecting it anyway", fields)
+			return candidate.book, candidate.score, nil
+		}
+
+		names := make([]string, 0, len(authors))
+		for _, a := range authors {
+			names = append(names, a.Name)
+		}
+		if authorsOverlap(author, authors) {
+			log.Info("Verified the author of the best matching book", map[string]interface{}{
+				"book_id":     candidate.book.ID,
+				"match_title": candidate.book.Title,
+				"match_score": candidate.score,
+				"authors":     names,
+			})
+			candidate.book.Authors = authors
+			return candidate.book, candidate.score, nil
+		}
+
+		log.Info("Rejecting search result by other authors", map[string]interface{}{
+			"book_id":     candidate.book.ID,
+			"match_title": candidate.book.Title,
+			"match_score": candidate.score,
+			"authors":     names,
+		})
+		rejected = append(rejected, fmt.Sprintf("%q (ID: %s) by %s", candidate.book.Title, candidate.book.ID, strings.Join(names, ", ")))
+	}
+
+	return nil, 0, fmt.Errorf("%w %q: rejected %s", errAuthorMismatch, author, strings.Join(rejected, "; "))
+}
+
+// authorsOverlap reports whether one of the Hardcover authors is one of the authors in
+// Audiobookshelf's author name, which joins several with commas or "&"
+func authorsOverlap(authorName string, authors []models.Author) bool {
+	for _, name := range strings.FieldsFunc(authorName, func(r rune) bool { return r == ',' || r == '&' || r == ';' }) {
+		for _, author := range authors {
+			if authorNamesMatch(name, author.Name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// authorNamesMatch compares author names loosely: the last names have to be the same, and the first
+// names start with the same letter, so "J.R.R. Tolkien" matches "J. R. R. Tolkien" and
+// "Steve King" matches "Stephen King". A single name only needs to match the last name.
+func authorNamesMatch(a, b string) bool {
+	tokensA, tokensB := nameTokens(a), nameTokens(b)
+	if len(tokensA) == 0 || len(tokensB) == 0 {
+		return false
+	}
+	if tokensA[len(tokensA)-1] != tokensB[len(tokensB)-1] {
+		return false
+	}
+	if len(tokensA) == 1 || len(tokensB) == 1 {
+		return true
+	}
+	return tokensA[0][0] == tokensB[0][0]
+}
+
+// nameTokens returns the lowercased words of a name without punctuation
+func nameTokens(name string) []string {
+	return strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// recordTitleMatchRejected records a mismatch without a Hardcover suggestion for a book whose
+// title/author search found no result similar enough to its title or by its author
+func (s *Service) recordTitleMatchRejected(book models.AudiobookshelfBook, err error) {
+	// e.g. "No title/author match above the similarity threshold of 0.75: best result "Habits" (ID: 1) scored 0.33"
+	reason := err.Error()
+	for _, sentinel := range []error{errWeakTitleMatch, errAuthorMismatch} {
+		if i := strings.Index(reason, sentinel.Error()); i >= 0 {
+			reason = reason[i:]
+			break
+		}
+	}
+	reason = strings.ToUpper(reason[:1]) + reason[1:]
+
+	mismatch.Add(s.bookMismatch(book, nil, reason))
+}
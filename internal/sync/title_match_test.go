@@ -0,0 +1,180 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/mismatch"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newTitleMatchBook returns a book without identifiers, so it's only searched by title and author
+func newTitleMatchBook() models.AudiobookshelfBook {
+	book := models.AudiobookshelfBook{ID: "abs-title", LibraryID: "lib1", MediaType: "book"}
+	book.Media.Metadata.Title = "The Road"
+	book.Media.Metadata.AuthorName = "Cormac McCarthy"
+	book.Media.Duration = 36000
+	book.Progress.CurrentTime = 18000
+	return book
+}
+
+func TestFindBookInHardcoverByTitleAuthor_TitleMatchThreshold(t *testing.T) {
+	tests := []struct {
+		name       string
+		results    []models.HardcoverBook
+		threshold  float64
+		expectedID string
+	}{
+		{
+			name:       "result above the threshold is selected",
+			results:    []models.HardcoverBook{{ID: "hc-other", Title: "Road Trip Stories"}, {ID: "hc-road", Title: "The Road"}},
+			threshold:  0.75,
+			expectedID: "hc-road",
+		},
+		{
+			name:      "weak result below the threshold isn't selected",
+			results:   []models.HardcoverBook{{ID: "hc-other", Title: "The Long Road Home"}},
+			threshold: 0.75,
+		},
+		{
+			name:       "no threshold selects the weak result",
+			results:    []models.HardcoverBook{{ID: "hc-other", Title: "The Long Road Home"}},
+			threshold:  0,
+			expectedID: "hc-other",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, mockClient := createTestService()
+			svc.config.Sync.TitleMatchThreshold = tt.threshold
+
+			mockClient.On("SearchBooks", mock.Anything, "The Road Cormac McCarthy", "").Return(tt.results, nil)
+			mockClient.On("GetBookByID", mock.Anything, mock.Anything).Return(nil, errors.New("not found")).Maybe()
+			mockClient.On("GetBookAuthors", mock.Anything, mock.Anything).Return([]models.Author{{Name: "Cormac McCarthy"}}, nil).Maybe()
+
+			hcBook, err := svc.findBookInHardcoverByTitleAuthor(context.Background(), newTitleMatchBook())
+			if tt.expectedID == "" {
+				assert.Nil(t, hcBook)
+				assert.ErrorIs(t, err, errWeakTitleMatch)
+				assert.Contains(t, err.Error(), "The Long Road Home")
+				return
+			}
+			require.NotNil(t, hcBook)
+			assert.Equal(t, tt.expectedID, hcBook.ID)
+			assert.NotErrorIs(t, err, errWeakTitleMatch)
+		})
+	}
+}
+
+func TestProcessBook_WeakTitleMatchRecorded(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.summary = &SyncSummary{}
+	mismatch.Clear()
+	defer mismatch.Clear()
+
+	mockClient.On("SearchBooks", mock.Anything, mock.Anything, mock.Anything).
+		Return([]models.HardcoverBook{{ID: "hc-other", Title: "The Long Road Home"}}, nil)
+
+	require.NoError(t, svc.processBook(context.Background(), newTitleMatchBook(), nil))
+
+	assert.Equal(t, 1, svc.summary.SkipReasons[SkipReasonWeakTitleMatch])
+	assert.Zero(t, svc.summary.SkipReasons[SkipReasonTitleAuthorOnly])
+	mismatches := mismatch.GetAll()
+	require.Len(t, mismatches, 1)
+	assert.Contains(t, mismatches[0].Reason, "No title/author match above the similarity threshold of 0.75")
+	assert.Empty(t, mismatches[0].HardcoverBookID)
+}
+
+func TestAuthorNamesMatch(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"Cormac McCarthy", "Cormac McCarthy", true},
+		{"cormac mccarthy", "Cormac McCarthy", true},
+		{"J.R.R. Tolkien", "J. R. R. Tolkien", true},
+		{"Steve King", "Stephen King", true},
+		{"Homer", "Homer", true},
+		{"Cormac McCarthy", "Cormac McCarthy Jr", false},
+		{"Cormac McCarthy", "Kevin McCarthy", false},
+		{"Stephen King", "Stephen Kingsley", false},
+		{"", "Stephen King", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, authorNamesMatch(tt.a, tt.b), "%q vs %q", tt.a, tt.b)
+	}
+
+	assert.True(t, authorsOverlap("Neil Gaiman, Terry Pratchett", []models.Author{{Name: "Terry Pratchett"}}))
+	assert.True(t, authorsOverlap("Neil Gaiman & Terry Pratchett", []models.Author{{Name: "Neil Gaiman"}}))
+	assert.False(t, authorsOverlap("Neil Gaiman", []models.Author{{Name: "Terry Pratchett"}}))
+}
+
+func TestFindBookInHardcoverByTitleAuthor_AuthorVerification(t *testing.T) {
+	results := []models.HardcoverBook{
+		{ID: "hc-other-road", Title: "The Road"},
+		{ID: "hc-road", Title: "The Road"},
+	}
+
+	t.Run("result by another author is rejected", func(t *testing.T) {
+		svc, mockClient := createTestService()
+		mockClient.On("SearchBooks", mock.Anything, mock.Anything, mock.Anything).Return(results, nil)
+		mockClient.On("GetBookAuthors", mock.Anything, "hc-other-road").Return([]models.Author{{Name: "Jack London"}}, nil).Once()
+		mockClient.On("GetBookAuthors", mock.Anything, "hc-road").Return([]models.Author{{Name: "Cormac McCarthy"}}, nil).Once()
+		mockClient.On("GetBookByID", mock.Anything, "hc-road").Return(nil, errors.New("not found"))
+
+		hcBook, err := svc.findBookInHardcoverByTitleAuthor(context.Background(), newTitleMatchBook())
+		require.NotNil(t, hcBook)
+		assert.Equal(t, "hc-road", hcBook.ID)
+		assert.Equal(t, []models.Author{{Name: "Cormac McCarthy"}}, hcBook.Authors)
+		assert.Contains(t, err.Error(), "found by title/author only")
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("all results by other authors", func(t *testing.T) {
+		svc, mockClient := createTestService()
+		mockClient.On("SearchBooks", mock.Anything, mock.Anything, mock.Anything).Return(results, nil)
+		mockClient.On("GetBookAuthors", mock.Anything, mock.Anything).Return([]models.Author{{Name: "Jack London"}}, nil).Twice()
+
+		hcBook, err := svc.findBookInHardcoverByTitleAuthor(context.Background(), newTitleMatchBook())
+		assert.Nil(t, hcBook)
+		assert.ErrorIs(t, err, errAuthorMismatch)
+		assert.Contains(t, err.Error(), "by Jack London")
+		mockClient.AssertNotCalled(t, "GetBookByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("result whose authors can't be fetched is kept", func(t *testing.T) {
+		svc, mockClient := createTestService()
+		mockClient.On("SearchBooks", mock.Anything, mock.Anything, mock.Anything).Return(results, nil)
+		mockClient.On("GetBookAuthors", mock.Anything, "hc-other-road").Return(nil, errors.New("API error")).Once()
+		mockClient.On("GetBookByID", mock.Anything, "hc-other-road").Return(nil, errors.New("not found"))
+
+		hcBook, _ := svc.findBookInHardcoverByTitleAuthor(context.Background(), newTitleMatchBook())
+		require.NotNil(t, hcBook)
+		assert.Equal(t, "hc-other-road", hcBook.ID)
+	})
+}
+
+func TestProcessBook_AuthorMismatchRecorded(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.summary = &SyncSummary{}
+	mismatch.Clear()
+	defer mismatch.Clear()
+
+	mockClient.On("SearchBooks", mock.Anything, mock.Anything, mock.Anything).
+		Return([]models.HardcoverBook{{ID: "hc-other-road", Title: "The Road"}}, nil)
+	mockClient.On("GetBookAuthors", mock.Anything, "hc-other-road").Return([]models.Author{{Name: "Jack London"}}, nil)
+
+	require.NoError(t, svc.processBook(context.Background(), newTitleMatchBook(), nil))
+
+	assert.Equal(t, 1, svc.summary.SkipReasons[SkipReasonAuthorMismatch])
+	mismatches := mismatch.GetAll()
+	require.Len(t, mismatches, 1)
+	assert.Contains(t, mismatches[0].Reason, `No title/author match by the book's author "Cormac McCarthy"`)
+	assert.Empty(t, mismatches[0].HardcoverBookID)
+}
@@ -0,0 +1,156 @@
+package sync
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TitleSearchCacheEntry is the outcome of a title/author search: the ID of the book it matched,
+// or none when the search found nothing
+type TitleSearchCacheEntry struct {
+	BookID    string    `json:"book_id,omitempty"` // Empty for searches without results
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// PersistentTitleSearchCache stores title/author search outcomes across runs, keyed by the
+// normalized title and author, so books that never match aren't searched for again every run
+type PersistentTitleSearchCache struct {
+	cacheFile string
+	ttl       time.Duration
+	mu        sync.RWMutex
+	entries   map[string]TitleSearchCacheEntry
+}
+
+// NewPersistentTitleSearchCache creates a title search cache whose entries expire after ttl.
+// A ttl <= 0 disables the cache.
+func NewPersistentTitleSearchCache(cacheDir string, ttl time.Duration) *PersistentTitleSearchCache {
+	return &PersistentTitleSearchCache{
+		cacheFile: filepath.Join(cacheDir, "title_search_cache.json"),
+		ttl:       ttl,
+		entries:   make(map[string]TitleSearchCacheEntry),
+	}
+}
+
+// titleSearchKey is the cache key of a title and author, ignoring case and whitespace
+func titleSearchKey(title, author string) string {
+	normalize := func(s string) string {
+		return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+	}
+	return normalize(title) + "|" + normalize(author)
+}
+
+// Load loads the unexpired entries from disk. A missing cache file yields no entries.
+func (c *PersistentTitleSearchCache) Load() error {
+	if c == nil || c.ttl <= 0 {
+		return nil
+	}
+
+	data, err := os.ReadFile(c.cacheFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read title search cache file: %w", err)
+	}
+
+	var entries map[string]TitleSearchCacheEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("failed to parse title search cache file: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]TitleSearchCacheEntry, len(entries))
+	for key, entry := range entries {
+		if time.Since(entry.Timestamp) < c.ttl {
+			c.entries[key] = entry
+		}
+	}
+	return nil
+}
+
+// Save writes the unexpired entries to disk
+func (c *PersistentTitleSearchCache) Save() error {
+	if c == nil || c.ttl <= 0 {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(c.cacheFile), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	c.mu.RLock()
+	valid := make(map[string]TitleSearchCacheEntry, len(c.entries))
+	for key, entry := range c.entries {
+		if time.Since(entry.Timestamp) < c.ttl {
+			valid[key] = entry
+		}
+	}
+	c.mu.RUnlock()
+
+	data, err := json.MarshalIndent(valid, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal title search cache: %w", err)
+	}
+	if err := os.WriteFile(c.cacheFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write title search cache file: %w", err)
+	}
+	return nil
+}
+
+// Get returns the book ID a search for title and author matched, "" if it found nothing, and
+// whether the outcome is cached
+func (c *PersistentTitleSearchCache) Get(title, author string) (string, bool) {
+	if c == nil || c.ttl <= 0 {
+		return "", false
+	}
+	c.mu.RLock()
+	entry, exists := c.entries[titleSearchKey(title, author)]
+	c.mu.RUnlock()
+	if !exists || time.Since(entry.Timestamp) >= c.ttl {
+		return "", false
+	}
+	return entry.BookID, true
+}
+
+// Set caches the book ID a search for title and author matched, "" recording that it found nothing
+func (c *PersistentTitleSearchCache) Set(title, author, bookID string) {
+	if c == nil || c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[titleSearchKey(title, author)] = TitleSearchCacheEntry{BookID: bookID, Timestamp: time.Now()}
+}
+
+// Delete removes the cached outcome of a search for title and author
+func (c *PersistentTitleSearchCache) Delete(title, author string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, titleSearchKey(title, author))
+}
+
+// Stats returns the number of cached searches, and how many of them matched a book or found nothing
+func (c *PersistentTitleSearchCache) Stats() (total, matched, notFound int) {
+	if c == nil {
+		return
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, entry := range c.entries {
+		total++
+		if entry.BookID != "" {
+			matched++
+		} else {
+			notFound++
+		}
+	}
+	return
+}
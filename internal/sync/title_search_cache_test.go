@@ -0,0 +1,104 @@
+package sync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/config"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/logger"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersistentTitleSearchCache(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewPersistentTitleSearchCache(dir, time.Hour)
+	require.NoError(t, cache.Load())
+
+	cache.Set("The Hobbit", "J.R.R. Tolkien", "123")
+	cache.Set("Unknown  Book", "Nobody", "")
+
+	// Keys ignore case and whitespace
+	bookID, ok := cache.Get("the hobbit", " J.R.R.  Tolkien")
+	assert.True(t, ok)
+	assert.Equal(t, "123", bookID)
+	_, ok = cache.Get("The Hobbit", "Someone Else")
+	assert.False(t, ok)
+
+	// Both outcomes survive a restart
+	require.NoError(t, cache.Save())
+	reloaded := NewPersistentTitleSearchCache(dir, time.Hour)
+	require.NoError(t, reloaded.Load())
+	bookID, ok = reloaded.Get("Unknown Book", "Nobody")
+	assert.True(t, ok)
+	assert.Empty(t, bookID)
+	total, matched, notFound := reloaded.Stats()
+	assert.Equal(t, 2, total)
+	assert.Equal(t, 1, matched)
+	assert.Equal(t, 1, notFound)
+
+	// Expired entries are dropped on load
+	expired := NewPersistentTitleSearchCache(dir, time.Nanosecond)
+	require.NoError(t, expired.Load())
+	_, ok = expired.Get("The Hobbit", "J.R.R. Tolkien")
+	assert.False(t, ok)
+}
+
+func TestPersistentTitleSearchCache_Disabled(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewPersistentTitleSearchCache(dir, 0)
+	cache.Set("The Hobbit", "J.R.R. Tolkien", "123")
+	_, ok := cache.Get("The Hobbit", "J.R.R. Tolkien")
+	assert.False(t, ok)
+
+	require.NoError(t, cache.Save())
+	assert.NoFileExists(t, cache.cacheFile)
+}
+
+func TestFindBookInHardcoverByTitleAuthor_TitleSearchCache(t *testing.T) {
+	logger.Setup(logger.Config{Level: "debug", Format: "json"})
+
+	newBook := func(title, author string) models.AudiobookshelfBook {
+		var book models.AudiobookshelfBook
+		book.ID = "abs-" + title
+		book.Media.Metadata = models.AudiobookshelfMetadataStruct{Title: title, AuthorName: author}
+		return book
+	}
+
+	mockClient := new(MockHardcoverClient)
+	mockClient.On("SearchBooks", mock.Anything, "Missing Book Missing Author", "").
+		Return([]models.HardcoverBook{}, nil).Once()
+	mockClient.On("SearchBooks", mock.Anything, "Found Book Found Author", "").
+		Return([]*TestHardcoverBook{{ID: "hc-1", Title: "Found Book"}}, nil).Once()
+	mockClient.On("GetBookByID", mock.Anything, "hc-1").
+		Return(&models.HardcoverBook{ID: "hc-1", Title: "Found Book"}, nil)
+	mockClient.On("GetBookAuthors", mock.Anything, "hc-1").
+		Return([]models.Author{{Name: "Found Author"}}, nil).Maybe()
+
+	service := &Service{
+		hardcover:        mockClient,
+		log:              logger.Get(),
+		config:           config.DefaultConfig(),
+		titleSearchCache: NewPersistentTitleSearchCache(t.TempDir(), time.Hour),
+	}
+
+	// Each book is searched for once, later lookups use the cached outcome
+	for i := 0; i < 2; i++ {
+		result, err := service.findBookInHardcoverByTitleAuthor(context.Background(), newBook("Missing Book", "Missing Author"))
+		assert.Nil(t, result)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no books found matching search query")
+
+		result, err = service.findBookInHardcoverByTitleAuthor(context.Background(), newBook("Found Book", "Found Author"))
+		require.NotNil(t, result)
+		assert.Equal(t, "hc-1", result.ID)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "found by title/author only")
+	}
+
+	mockClient.AssertExpectations(t)
+	mockClient.AssertNumberOfCalls(t, "SearchBooks", 2)
+}
@@ -0,0 +1,75 @@
+package sync
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/audiobookshelf"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+func TestSync_Tracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	defer otel.SetTracerProvider(previous)
+
+	svc, mockClient := createTestService()
+	svc.summary = &SyncSummary{}
+	svc.statePath = filepath.Join(t.TempDir(), "state.json")
+	svc.persistentCache = NewPersistentASINCache(t.TempDir())
+	svc.config.Paths.MismatchOutputDir = t.TempDir()
+	svc.config.Sync.OwnershipOnly = true
+	svc.config.Sync.SyncOwned = false
+
+	found := models.AudiobookshelfBook{ID: "abs-found", LibraryID: "lib1", MediaType: "book"}
+	found.Media.Metadata.Title = "Found Audiobook"
+	found.Media.Metadata.ASIN = "B000000001"
+	missing := models.AudiobookshelfBook{ID: "abs-missing", LibraryID: "lib1", MediaType: "book"}
+	missing.Media.Metadata.Title = "Missing Audiobook"
+	missing.Media.Metadata.ASIN = "B000000002"
+
+	mockClient.On("SearchBookByASINAnyFormat", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	mockClient.On("SearchBookByASIN", mock.Anything, "B000000001").
+		Return(&models.HardcoverBook{ID: "10", EditionID: "100", EditionASIN: "B000000001"}, nil)
+	mockClient.On("SearchBookByASIN", mock.Anything, "B000000002").Return(nil, nil)
+	mockClient.On("SearchBooks", mock.Anything, mock.Anything, mock.Anything).Return([]models.HardcoverBook{}, nil).Maybe()
+	mockClient.On("CheckBookOwnership", mock.Anything, 10).Return(true, nil)
+
+	mockABS := new(MockAudiobookshelfClient)
+	mockABS.On("GetUserProgress", mock.Anything).Return(&models.AudiobookshelfUserProgress{}, nil)
+	mockABS.On("GetLibraries", mock.Anything).Return([]audiobookshelf.AudiobookshelfLibrary{{ID: "lib1", Name: "Audiobooks"}}, nil)
+	mockABS.On("GetLibraryItems", mock.Anything, "lib1").Return([]models.AudiobookshelfBook{found, missing}, nil)
+	svc.audiobookshelf = mockABS
+
+	require.NoError(t, svc.Sync(context.Background()))
+
+	byName := make(map[string][]tracetest.SpanStub)
+	for _, span := range exporter.GetSpans() {
+		byName[span.Name] = append(byName[span.Name], span)
+	}
+
+	// sync.run > sync.library > sync.book, all in one trace
+	require.Len(t, byName["sync.run"], 1)
+	run := byName["sync.run"][0]
+	assert.False(t, run.Parent.IsValid())
+
+	require.Len(t, byName["sync.library"], 1)
+	library := byName["sync.library"][0]
+	assert.Equal(t, run.SpanContext.SpanID(), library.Parent.SpanID())
+
+	books := byName["sync.book"]
+	require.Len(t, books, 2)
+	for _, book := range books {
+		assert.Equal(t, library.SpanContext.SpanID(), book.Parent.SpanID())
+		assert.Equal(t, run.SpanContext.TraceID(), book.SpanContext.TraceID())
+	}
+}
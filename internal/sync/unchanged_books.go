@@ -0,0 +1,52 @@
+package sync
+
+import (
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// lastChangedAt returns the most recent Audiobookshelf change to a book or its progress (Unix
+// milliseconds), or 0 if Audiobookshelf didn't report when the book last changed
+func lastChangedAt(book models.AudiobookshelfBook, userProgress *models.AudiobookshelfUserProgress) int64 {
+	latest := book.UpdatedAt
+	if book.Progress.LastUpdate > latest {
+		latest = book.Progress.LastUpdate
+	}
+
+	if userProgress != nil {
+		for _, progress := range userProgress.MediaProgress {
+			if progress.LibraryItemID == book.ID && progress.LastUpdate > latest {
+				latest = progress.LastUpdate
+			}
+		}
+		for _, session := range userProgress.ListeningSessions {
+			if session.LibraryItemID == book.ID && session.UpdatedAt > latest {
+				latest = session.UpdatedAt
+			}
+		}
+	}
+
+	return latest
+}
+
+// unchangedSinceLastSync reports whether Sync.SkipUnchangedBooks is enabled and neither the book
+// nor its progress changed since the last successful sync started. The start rather than the
+// completion time is used so changes made while that sync was running aren't missed. Books without
+// any change timestamp, with a status listed in Sync.AlwaysReverify or still within their
+// Sync.NotFoundGracePeriod are never considered unchanged, nor are any books when Hardcover progress
+// is synced to Audiobookshelf.
+func (s *Service) unchangedSinceLastSync(book models.AudiobookshelfBook, userProgress *models.AudiobookshelfUserProgress) bool {
+	if !s.config.Sync.SkipUnchangedBooks || s.updatedSince.IsZero() || s.alwaysReverifyBook(book) ||
+		s.pullsFromHardcover() || s.awaitingHardcover(book) {
+		return false
+	}
+
+	changedAt := lastChangedAt(book, userProgress)
+	return changedAt > 0 && changedAt < s.updatedSince.UnixMilli()
+}
+
+// formatMillis formats an Audiobookshelf timestamp in Unix milliseconds for logging
+func formatMillis(ms int64) string {
+	return time.UnixMilli(ms).Format(time.RFC3339)
+}
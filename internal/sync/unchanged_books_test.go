@@ -0,0 +1,69 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessBook_SkipsBooksUnchangedSinceLastSync(t *testing.T) {
+	lastSync := time.Now().Add(-time.Hour)
+
+	newBook := func(updatedAt, progressUpdate int64) models.AudiobookshelfBook {
+		book := newTestBook("book-1", "Unchanged Audiobook")
+		book.UpdatedAt = updatedAt
+		book.Progress.CurrentTime = 600
+		book.Progress.LastUpdate = progressUpdate
+		return book
+	}
+	before := lastSync.Add(-time.Minute).UnixMilli()
+	after := lastSync.Add(time.Minute).UnixMilli()
+
+	tests := []struct {
+		name         string
+		book         models.AudiobookshelfBook
+		userProgress *models.AudiobookshelfUserProgress
+		disabled     bool
+		wantSkipped  bool
+	}{
+		{name: "untouched since last sync", book: newBook(before, before), wantSkipped: true},
+		{name: "item updated since last sync", book: newBook(after, before)},
+		{name: "progress updated since last sync", book: newBook(before, after)},
+		{name: "no change timestamps", book: newBook(0, 0)},
+		{
+			name: "listening session since last sync",
+			book: newBook(before, before),
+			userProgress: func() *models.AudiobookshelfUserProgress {
+				up := &models.AudiobookshelfUserProgress{}
+				raw := fmt.Sprintf(`{"listeningSessions":[{"libraryItemId":"book-1","currentTime":900,"updatedAt":%d}]}`, after)
+				require.NoError(t, json.Unmarshal([]byte(raw), up))
+				return up
+			}(),
+		},
+		{name: "option disabled", book: newBook(before, before), disabled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, mockClient := createTestService()
+			svc.summary = &SyncSummary{}
+			svc.config.Sync.SkipUnchangedBooks = !tt.disabled
+			svc.updatedSince = lastSync
+
+			require.NoError(t, svc.processBook(context.Background(), tt.book, tt.userProgress))
+			if tt.wantSkipped {
+				assert.Empty(t, lookedUpBooks(svc.summary), "book should be skipped before any per-book work")
+				assert.Zero(t, svc.summary.BooksSynced)
+			} else {
+				assert.Equal(t, []string{"book-1"}, lookedUpBooks(svc.summary), "book should be processed")
+			}
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package sync
+
+import (
+	"context"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/mismatch"
+)
+
+// exportUnmatched writes the books not found in Hardcover this run to Paths.UnmatchedExport, if
+// set. Failures are logged, as the export doesn't affect the sync.
+func (s *Service) exportUnmatched(ctx context.Context) {
+	path := s.config.Paths.UnmatchedExport
+	if path == "" {
+		return
+	}
+
+	count, err := mismatch.ExportUnmatched(ctx, s.hardcover, path)
+	if err != nil {
+		s.log.Warn("Failed to export unmatched books", map[string]interface{}{
+			"path":  path,
+			"error": err.Error(),
+		})
+		return
+	}
+	s.log.Info("Exported unmatched books", map[string]interface{}{
+		"path":  path,
+		"books": count,
+	})
+}
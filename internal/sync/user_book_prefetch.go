@@ -0,0 +1,153 @@
+package sync
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// userBookIndex maps Hardcover book and edition IDs to the user's books, fetched once at the start
+// of a run when Sync.PrefetchUserBooks is enabled. User books created during the run are added.
+type userBookIndex struct {
+	mu        sync.RWMutex
+	byBook    map[string]int64
+	byEdition map[string]int64
+}
+
+// loadUserBooks fetches the user's books once per run when Sync.PrefetchUserBooks is enabled. If
+// fetching fails, user books are looked up per book this run.
+func (s *Service) loadUserBooks(ctx context.Context) {
+	s.userBooks = nil
+	s.editionUserBooks = nil
+	if !s.config.Sync.PrefetchUserBooks {
+		return
+	}
+
+	refs, err := s.hardcover.GetUserBookRefs(ctx)
+	if err != nil {
+		s.log.Warn("Failed to prefetch user books from Hardcover, looking them up per book this run", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	index := &userBookIndex{
+		byBook:    make(map[string]int64, len(refs)),
+		byEdition: make(map[string]int64, len(refs)),
+	}
+	for _, ref := range refs {
+		bookID := ""
+		if ref.BookID > 0 {
+			bookID = strconv.Itoa(ref.BookID)
+		}
+		editionID := ""
+		if ref.EditionID > 0 {
+			editionID = strconv.Itoa(ref.EditionID)
+		}
+		index.add(bookID, editionID, int64(ref.ID))
+	}
+	s.userBooks = index
+	s.log.Info("Prefetched user books from Hardcover", map[string]interface{}{
+		"user_books": len(refs),
+	})
+}
+
+// prefetchEditionUserBooks looks up the user books of the editions the items were matched to
+// before, from the ASIN cache and the recorded match info, in batches, so books already mapped
+// don't need a user book request each. Editions without a user book are looked up per book as
+// before, as the user may have the book on another edition. Nothing is prefetched when all of the
+// user's books were already loaded, and failures are logged, as the lookups are only skipped.
+func (s *Service) prefetchEditionUserBooks(ctx context.Context, items []models.AudiobookshelfBook) {
+	if s.userBooks != nil || s.config.Sync.OwnershipOnly {
+		return
+	}
+
+	seen := make(map[int]struct{})
+	var editionIDs []int
+	addEdition := func(editionID string) {
+		id, err := strconv.Atoi(editionID)
+		if err != nil || id <= 0 {
+			return
+		}
+		if _, dup := seen[id]; dup || s.editionUserBooks.lookup("", editionID) > 0 {
+			return
+		}
+		seen[id] = struct{}{}
+		editionIDs = append(editionIDs, id)
+	}
+	for _, item := range items {
+		if asin := item.Media.Metadata.ASIN; asin != "" {
+			if cached, ok := s.getASINFromCache(asin); ok && cached != nil {
+				addEdition(cached.EditionID)
+			}
+		}
+		if s.state != nil {
+			if bookState, ok := s.state.GetBookState(item.ID); ok {
+				addEdition(bookState.EditionID)
+			}
+		}
+	}
+	if len(editionIDs) == 0 {
+		return
+	}
+
+	refs, err := s.hardcover.GetUserBooksByEditionIDs(ctx, editionIDs)
+	if err != nil {
+		s.log.Warn("Failed to prefetch user books by edition, looking them up per book", map[string]interface{}{
+			"editions": len(editionIDs),
+			"error":    err.Error(),
+		})
+		return
+	}
+
+	if s.editionUserBooks == nil {
+		s.editionUserBooks = &userBookIndex{
+			byBook:    make(map[string]int64),
+			byEdition: make(map[string]int64, len(refs)),
+		}
+	}
+	for _, ref := range refs {
+		if ref.EditionID > 0 {
+			s.editionUserBooks.add("", strconv.Itoa(ref.EditionID), int64(ref.ID))
+		}
+	}
+	s.log.Info("Prefetched user books by edition", map[string]interface{}{
+		"editions":   len(editionIDs),
+		"user_books": len(refs),
+	})
+}
+
+// lookup returns the user book of a Hardcover book or edition. Like GetUserBookID it prefers the
+// user book of the book, which may be on another edition. The user has no such book when it
+// returns 0, as when the index is nil.
+func (i *userBookIndex) lookup(bookID, editionID string) int64 {
+	if i == nil {
+		return 0
+	}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	if userBookID, ok := i.byBook[bookID]; ok && bookID != "" {
+		return userBookID
+	}
+	if userBookID, ok := i.byEdition[editionID]; ok && editionID != "" {
+		return userBookID
+	}
+	return 0
+}
+
+// add records a user book, keeping the first user book seen for a book or edition
+func (i *userBookIndex) add(bookID, editionID string, userBookID int64) {
+	if i == nil || userBookID <= 0 {
+		return
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if _, exists := i.byBook[bookID]; bookID != "" && !exists {
+		i.byBook[bookID] = userBookID
+	}
+	if _, exists := i.byEdition[editionID]; editionID != "" && !exists {
+		i.byEdition[editionID] = userBookID
+	}
+}
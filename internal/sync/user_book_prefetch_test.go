@@ -0,0 +1,117 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindOrCreateUserBookIDForBook_PrefetchedUserBooks(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.config.Sync.PrefetchUserBooks = true
+
+	mockClient.On("GetUserBookRefs", mock.Anything).Return([]hardcover.UserBookRef{
+		{ID: 501, BookID: 10, EditionID: 100},
+		{ID: 502, BookID: 20},
+	}, nil).Once()
+	svc.loadUserBooks(context.Background())
+
+	// Found by edition, and by book when the user has it on another edition or without one
+	userBookID, err := svc.findOrCreateUserBookIDForBook(context.Background(), &models.HardcoverBook{ID: "10"}, "100", "IN_PROGRESS")
+	require.NoError(t, err)
+	assert.Equal(t, int64(501), userBookID)
+	userBookID, err = svc.findOrCreateUserBookIDForBook(context.Background(), &models.HardcoverBook{ID: "20"}, "200", "IN_PROGRESS")
+	require.NoError(t, err)
+	assert.Equal(t, int64(502), userBookID)
+
+	// Books the user doesn't have yet are created right away, and only once per run
+	mockClient.On("CreateUserBook", mock.Anything, "300", "IN_PROGRESS").Return("503", nil).Once()
+	for i := 0; i < 2; i++ {
+		userBookID, err = svc.findOrCreateUserBookIDForBook(context.Background(), &models.HardcoverBook{ID: "30"}, "300", "IN_PROGRESS")
+		require.NoError(t, err)
+		assert.Equal(t, int64(503), userBookID)
+	}
+
+	mockClient.AssertExpectations(t)
+	mockClient.AssertNotCalled(t, "GetUserBookID", mock.Anything, mock.Anything)
+}
+
+func TestLoadUserBooks(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		svc, mockClient := createTestService()
+		svc.loadUserBooks(context.Background())
+		assert.Nil(t, svc.userBooks)
+		mockClient.AssertNotCalled(t, "GetUserBookRefs", mock.Anything)
+	})
+
+	t.Run("falls back to per-book lookups when fetching fails", func(t *testing.T) {
+		svc, mockClient := createTestService()
+		svc.config.Sync.PrefetchUserBooks = true
+		mockClient.On("GetUserBookRefs", mock.Anything).Return(nil, errors.New("API error")).Once()
+		svc.loadUserBooks(context.Background())
+		assert.Nil(t, svc.userBooks)
+
+		mockClient.On("GetUserBookID", mock.Anything, 100).Return(501, nil).Once()
+		userBookID, err := svc.findOrCreateUserBookIDForBook(context.Background(), &models.HardcoverBook{ID: "10"}, "100", "IN_PROGRESS")
+		require.NoError(t, err)
+		assert.Equal(t, int64(501), userBookID)
+		mockClient.AssertExpectations(t)
+	})
+}
+
+func TestPrefetchEditionUserBooks(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.persistentCache = NewPersistentASINCache(t.TempDir())
+	svc.setASINInCache("B000000001", &models.HardcoverBook{ID: "10", EditionID: "100"})
+	svc.setASINInCache("B000000002", &models.HardcoverBook{ID: "20", EditionID: "200"})
+	svc.state.RecordMatch("item-3", "asin", 1, "300")
+
+	items := []models.AudiobookshelfBook{{ID: "item-1"}, {ID: "item-2"}, {ID: "item-3"}, {ID: "item-4"}}
+	items[0].Media.Metadata.ASIN = "B000000001"
+	items[1].Media.Metadata.ASIN = "B000000002"
+	items[3].Media.Metadata.ASIN = "B000000001" // Another item of the same edition
+
+	mockClient.On("GetUserBooksByEditionIDs", mock.Anything, []int{100, 200, 300}).
+		Return([]hardcover.UserBookRef{{ID: 501, BookID: 10, EditionID: 100}, {ID: 503, BookID: 30, EditionID: 300}}, nil).Once()
+	svc.prefetchEditionUserBooks(context.Background(), items)
+
+	// Prefetched user books need no lookup, the others are looked up as before
+	userBookID, err := svc.findOrCreateUserBookID(context.Background(), "100", "IN_PROGRESS")
+	require.NoError(t, err)
+	assert.Equal(t, int64(501), userBookID)
+	userBookID, err = svc.findOrCreateUserBookID(context.Background(), "300", "IN_PROGRESS")
+	require.NoError(t, err)
+	assert.Equal(t, int64(503), userBookID)
+
+	mockClient.On("GetUserBookID", mock.Anything, 200).Return(502, nil).Once()
+	userBookID, err = svc.findOrCreateUserBookID(context.Background(), "200", "IN_PROGRESS")
+	require.NoError(t, err)
+	assert.Equal(t, int64(502), userBookID)
+
+	// Editions with a prefetched user book aren't looked up again for the next library
+	mockClient.On("GetUserBooksByEditionIDs", mock.Anything, []int{200}).Return([]hardcover.UserBookRef{}, nil).Once()
+	svc.prefetchEditionUserBooks(context.Background(), items)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestPrefetchEditionUserBooks_AllUserBooksLoaded(t *testing.T) {
+	svc, mockClient := createTestService()
+	svc.config.Sync.PrefetchUserBooks = true
+	mockClient.On("GetUserBookRefs", mock.Anything).Return([]hardcover.UserBookRef{}, nil).Once()
+	svc.loadUserBooks(context.Background())
+
+	svc.persistentCache = NewPersistentASINCache(t.TempDir())
+	svc.setASINInCache("B000000001", &models.HardcoverBook{ID: "10", EditionID: "100"})
+	item := models.AudiobookshelfBook{ID: "item-1"}
+	item.Media.Metadata.ASIN = "B000000001"
+	svc.prefetchEditionUserBooks(context.Background(), []models.AudiobookshelfBook{item})
+
+	mockClient.AssertNotCalled(t, "GetUserBooksByEditionIDs", mock.Anything, mock.Anything)
+}
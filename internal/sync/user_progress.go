@@ -0,0 +1,184 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// userProgressRetryDelay is the wait before the first retry of a failed user progress fetch. It
+// doubles with every further retry.
+var userProgressRetryDelay = 2 * time.Second
+
+// defaultUserProgressCacheMaxAge is used when neither Sync.UserProgressCacheMaxAge nor
+// Sync.SyncInterval is set
+const defaultUserProgressCacheMaxAge = 2 * time.Hour
+
+// cachedUserProgress is the last user progress fetched successfully, as stored on disk
+type cachedUserProgress struct {
+	FetchedAt time.Time                          `json:"fetched_at"`
+	Progress  *models.AudiobookshelfUserProgress `json:"progress"`
+}
+
+// userProgressCacheFile returns the file the last fetched user progress is kept in, or "" without a
+// cache directory. Each user of a multi-user setup gets their own file, as the cache directory is
+// shared.
+func (s *Service) userProgressCacheFile() string {
+	if s.config.Paths.CacheDir == "" {
+		return ""
+	}
+
+	userID := ""
+	if s.summary != nil {
+		s.summary.Lock()
+		userID = s.summary.UserID
+		s.summary.Unlock()
+	}
+
+	name := "user_progress.json"
+	if userID != "" {
+		safeID := strings.Map(func(r rune) rune {
+			if r == '-' || r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+				return r
+			}
+			return '_'
+		}, userID)
+		name = fmt.Sprintf("user_progress_%s.json", safeID)
+	}
+	return filepath.Join(s.config.Paths.CacheDir, name)
+}
+
+// fetchUserProgress fetches the user's progress from Audiobookshelf, retrying up to
+// Sync.UserProgressRetries times. Successful fetches are cached to disk; when every attempt fails,
+// the cached progress of a previous run is returned instead if it's younger than
+// Sync.UserProgressCacheMaxAge. The error of the last attempt is returned either way, so the caller
+// can track authentication failures.
+func (s *Service) fetchUserProgress(ctx context.Context) (*models.AudiobookshelfUserProgress, error) {
+	s.userProgressCachedAt = time.Time{}
+	var err error
+	delay := userProgressRetryDelay
+	for attempt := 0; ; attempt++ {
+		var progress *models.AudiobookshelfUserProgress
+		progress, err = s.audiobookshelf.GetUserProgress(ctx)
+		if err == nil {
+			if saveErr := s.saveUserProgress(progress); saveErr != nil {
+				s.log.Warn("Failed to cache user progress", map[string]interface{}{
+					"error": saveErr.Error(),
+				})
+			}
+			return progress, nil
+		}
+		// Retrying won't help with rejected credentials or a cancelled run
+		if attempt >= s.config.Sync.UserProgressRetries || isAuthError(err) || ctx.Err() != nil {
+			break
+		}
+
+		s.log.Warn("Failed to fetch user progress data, retrying", map[string]interface{}{
+			"attempt": attempt + 1,
+			"delay":   delay.String(),
+			"error":   err.Error(),
+		})
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+
+	cached, loadErr := s.loadUserProgress()
+	if loadErr != nil {
+		s.log.Warn("Failed to load cached user progress", map[string]interface{}{
+			"error": loadErr.Error(),
+		})
+		return nil, err
+	}
+	if cached == nil {
+		return nil, err
+	}
+
+	s.log.Warn("Failed to fetch user progress data, using the progress cached by a previous run", map[string]interface{}{
+		"fetched_at": cached.FetchedAt.Format(time.RFC3339),
+		"age":        time.Since(cached.FetchedAt).Round(time.Second).String(),
+		"error":      err.Error(),
+	})
+	s.userProgressCachedAt = cached.FetchedAt
+	return cached.Progress, err
+}
+
+// cachedProgressBehindRead reports whether the run uses user progress cached by an earlier run
+// that's older than the Hardcover read, which is further along or was started after the progress
+// was cached. Writing the cached progress would roll the read back.
+func (s *Service) cachedProgressBehindRead(book models.AudiobookshelfBook, read *hardcover.UserBookRead, readProgress float64) bool {
+	if s.userProgressCachedAt.IsZero() || read == nil {
+		return false
+	}
+	if readProgress > book.Progress.CurrentTime {
+		return true
+	}
+	if read.StartedAt == nil {
+		return false
+	}
+	startedAt, err := time.Parse("2006-01-02", *read.StartedAt)
+	return err == nil && startedAt.After(s.userProgressCachedAt.UTC().Truncate(24*time.Hour))
+}
+
+// saveUserProgress writes the fetched user progress to the cache directory
+func (s *Service) saveUserProgress(progress *models.AudiobookshelfUserProgress) error {
+	cacheFile := s.userProgressCacheFile()
+	if cacheFile == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(cacheFile), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.Marshal(cachedUserProgress{FetchedAt: time.Now(), Progress: progress})
+	if err != nil {
+		return fmt.Errorf("failed to marshal user progress: %w", err)
+	}
+	if err := os.WriteFile(cacheFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write user progress cache file: %w", err)
+	}
+	return nil
+}
+
+// loadUserProgress returns the cached user progress, or nil if there is none or it's too old to use
+func (s *Service) loadUserProgress() (*cachedUserProgress, error) {
+	cacheFile := s.userProgressCacheFile()
+	if cacheFile == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(cacheFile)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read user progress cache file: %w", err)
+	}
+
+	var cached cachedUserProgress
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil, fmt.Errorf("failed to parse user progress cache file: %w", err)
+	}
+
+	maxAge := s.config.Sync.UserProgressCacheMaxAge
+	if maxAge <= 0 {
+		maxAge = 2 * s.config.Sync.SyncInterval
+	}
+	if maxAge <= 0 {
+		maxAge = defaultUserProgressCacheMaxAge
+	}
+	if cached.Progress == nil || time.Since(cached.FetchedAt) > maxAge {
+		return nil, nil
+	}
+	return &cached, nil
+}
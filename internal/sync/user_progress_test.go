@@ -0,0 +1,182 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/audiobookshelf"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/api/hardcover"
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newUserProgressTestService(t *testing.T) (*Service, *MockAudiobookshelfClient) {
+	t.Helper()
+	delay := userProgressRetryDelay
+	userProgressRetryDelay = time.Millisecond
+	t.Cleanup(func() { userProgressRetryDelay = delay })
+
+	svc, _ := createTestService()
+	svc.summary = &SyncSummary{}
+	svc.config.Paths.CacheDir = t.TempDir()
+	svc.config.Sync.UserProgressRetries = 2
+	svc.config.Sync.UserProgressCacheMaxAge = time.Hour
+
+	mockABS := new(MockAudiobookshelfClient)
+	svc.audiobookshelf = mockABS
+	return svc, mockABS
+}
+
+func testUserProgress() *models.AudiobookshelfUserProgress {
+	progress := &models.AudiobookshelfUserProgress{ID: "user-1"}
+	progress.MediaProgress = append(progress.MediaProgress, struct {
+		ID                        string  `json:"id"`
+		LibraryItemID             string  `json:"libraryItemId"`
+		UserID                    string  `json:"userId"`
+		IsFinished                bool    `json:"isFinished"`
+		Progress                  float64 `json:"progress"`
+		CurrentTime               float64 `json:"currentTime"`
+		Duration                  float64 `json:"duration"`
+		StartedAt                 int64   `json:"startedAt"`
+		FinishedAt                int64   `json:"finishedAt"`
+		LastUpdate                int64   `json:"lastUpdate"`
+		TimeListening             float64 `json:"timeListening"`
+		EbookProgress             float64 `json:"ebookProgress"`
+		HideFromContinueListening bool    `json:"hideFromContinueListening"`
+	}{LibraryItemID: "li-1", Progress: 0.5, CurrentTime: 1800, Duration: 3600})
+	return progress
+}
+
+func TestFetchUserProgress_RetriesTransientFailure(t *testing.T) {
+	svc, mockABS := newUserProgressTestService(t)
+
+	mockABS.On("GetUserProgress", mock.Anything).Return(nil, errors.New("connection reset")).Once()
+	mockABS.On("GetUserProgress", mock.Anything).Return(testUserProgress(), nil).Once()
+
+	progress, err := svc.fetchUserProgress(context.Background())
+	require.NoError(t, err)
+	require.Len(t, progress.MediaProgress, 1)
+	mockABS.AssertExpectations(t)
+
+	// The successful fetch is cached for later runs
+	_, err = os.Stat(svc.userProgressCacheFile())
+	assert.NoError(t, err)
+}
+
+func TestFetchUserProgress_FallsBackToCachedProgress(t *testing.T) {
+	svc, mockABS := newUserProgressTestService(t)
+	svc.SetUserID("profile/1")
+
+	mockABS.On("GetUserProgress", mock.Anything).Return(testUserProgress(), nil).Once()
+	_, err := svc.fetchUserProgress(context.Background())
+	require.NoError(t, err)
+	assert.Contains(t, svc.userProgressCacheFile(), "user_progress_profile_1.json")
+
+	// Every attempt of the next run fails
+	fetchErr := errors.New("service unavailable")
+	mockABS.On("GetUserProgress", mock.Anything).Return(nil, fetchErr).Times(3)
+
+	progress, err := svc.fetchUserProgress(context.Background())
+	assert.ErrorIs(t, err, fetchErr)
+	require.NotNil(t, progress)
+	require.Len(t, progress.MediaProgress, 1)
+	assert.Equal(t, "li-1", progress.MediaProgress[0].LibraryItemID)
+	assert.Equal(t, 1800.0, progress.MediaProgress[0].CurrentTime)
+	assert.False(t, svc.userProgressCachedAt.IsZero(), "the run knows its progress is cached")
+	mockABS.AssertExpectations(t)
+}
+
+func TestFetchUserProgress_DefaultMaxAgeFollowsSyncInterval(t *testing.T) {
+	svc, mockABS := newUserProgressTestService(t)
+	svc.config.Sync.UserProgressRetries = 0
+	svc.config.Sync.UserProgressCacheMaxAge = 0
+	svc.config.Sync.SyncInterval = 30 * time.Minute
+
+	writeCache := func(age time.Duration) {
+		data, err := json.Marshal(cachedUserProgress{FetchedAt: time.Now().Add(-age), Progress: testUserProgress()})
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(svc.userProgressCacheFile(), data, 0644))
+	}
+	mockABS.On("GetUserProgress", mock.Anything).Return(nil, errors.New("timeout")).Twice()
+
+	// The progress of the previous run is used
+	writeCache(45 * time.Minute)
+	progress, _ := svc.fetchUserProgress(context.Background())
+	assert.NotNil(t, progress)
+
+	// Older progress isn't
+	writeCache(90 * time.Minute)
+	progress, _ = svc.fetchUserProgress(context.Background())
+	assert.Nil(t, progress)
+	assert.True(t, svc.userProgressCachedAt.IsZero())
+}
+
+func TestHandleInProgressBook_CachedProgressBehindRead(t *testing.T) {
+	userBookID := int64(123)
+	editionID := int64(456)
+
+	testAudiobook := createTestBook("test-book-1", "Test Book", "Test Author", "B08N5KWB9H", "9781234567890")
+	testAudiobook.Progress.CurrentTime = 300
+	testAudiobook.Media.Duration = 1000
+	audiobook := toAudiobookshelfBook(testAudiobook)
+	aheadProgress, behindProgress := 600, 100
+	today := time.Now().UTC().Format("2006-01-02")
+
+	for name, read := range map[string]hardcover.UserBookRead{
+		"further along":         {ID: 789, ProgressSeconds: &aheadProgress, EditionID: &editionID},
+		"started after caching": {ID: 789, ProgressSeconds: &behindProgress, EditionID: &editionID, StartedAt: &today},
+	} {
+		t.Run(name, func(t *testing.T) {
+			svc, mockClient := createTestService()
+			svc.userProgressCachedAt = time.Now().Add(-36 * time.Hour)
+			mockClient.On("GetUserBook", mock.Anything, "123").Return(&models.HardcoverBook{
+				ID:        "book-123",
+				Title:     "Test Book",
+				EditionID: "456",
+			}, nil).Once()
+			mockClient.On("GetUserBookReads", mock.Anything, hardcover.GetUserBookReadsInput{
+				UserBookID: userBookID,
+				Status:     "unfinished",
+			}).Return([]hardcover.UserBookRead{read}, nil).Once()
+
+			require.NoError(t, svc.handleInProgressBook(context.Background(), userBookID, *audiobook, audiobook.ID+":test-edition"))
+			mockClient.AssertNotCalled(t, "UpdateUserBookRead", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestFetchUserProgress_IgnoresStaleCache(t *testing.T) {
+	svc, mockABS := newUserProgressTestService(t)
+	svc.config.Sync.UserProgressRetries = 0
+
+	data, err := json.Marshal(cachedUserProgress{FetchedAt: time.Now().Add(-2 * time.Hour), Progress: testUserProgress()})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(svc.userProgressCacheFile(), data, 0644))
+
+	mockABS.On("GetUserProgress", mock.Anything).Return(nil, errors.New("timeout")).Once()
+
+	progress, err := svc.fetchUserProgress(context.Background())
+	assert.Error(t, err)
+	assert.Nil(t, progress)
+	mockABS.AssertExpectations(t)
+}
+
+func TestFetchUserProgress_DoesNotRetryAuthErrors(t *testing.T) {
+	svc, mockABS := newUserProgressTestService(t)
+
+	unauthorized := fmt.Errorf("%w: unexpected status code: %d", audiobookshelf.ErrUnauthorized, http.StatusUnauthorized)
+	mockABS.On("GetUserProgress", mock.Anything).Return(nil, unauthorized).Once()
+
+	progress, err := svc.fetchUserProgress(context.Background())
+	assert.ErrorIs(t, err, audiobookshelf.ErrUnauthorized)
+	assert.Nil(t, progress)
+	mockABS.AssertNumberOfCalls(t, "GetUserProgress", 1)
+}
@@ -0,0 +1,100 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+)
+
+// errUnownedWantToRead is returned instead of a user book for an unowned book that would be added to
+// Want to Read while Sync.WantToReadOwnedOnly is enabled
+var errUnownedWantToRead = errors.New("book is not owned, not adding it to Want to Read")
+
+// findOrCreateUserBookIDForBook is findOrCreateUserBookID for the matched Hardcover book. It returns
+// errUnownedWantToRead rather than adding the book to Want to Read when skipUnownedWantToRead says so,
+// and errOwnershipOnly while Sync.OwnershipOnly is enabled. With the user's books prefetched, the
+// user book is looked up there instead of in Hardcover.
+func (s *Service) findOrCreateUserBookIDForBook(ctx context.Context, hcBook *models.HardcoverBook, editionID, status string) (int64, error) {
+	if s.config.Sync.OwnershipOnly {
+		return 0, errOwnershipOnly
+	}
+	if status == "WANT_TO_READ" && s.skipUnownedWantToRead(ctx, hcBook) {
+		return 0, errUnownedWantToRead
+	}
+	if s.userBooks == nil {
+		return s.findOrCreateUserBookID(ctx, editionID, status)
+	}
+
+	if userBookID := s.userBooks.lookup(hcBook.ID, editionID); userBookID > 0 {
+		return userBookID, nil
+	}
+	userBookID, err := s.createUserBookID(ctx, editionID, status)
+	if err == nil {
+		s.userBooks.add(hcBook.ID, editionID, userBookID)
+	}
+	return userBookID, err
+}
+
+// skipUnownedWantToRead reports whether an unstarted book should be kept off the Want to Read
+// shelf because Sync.WantToReadOwnedOnly is enabled and the book isn't owned in Hardcover. Books
+// whose ownership can't be checked are kept off as well. Ownership is checked once per book and run.
+func (s *Service) skipUnownedWantToRead(ctx context.Context, hcBook *models.HardcoverBook) bool {
+	// With sync_owned every matched book has already been marked as owned
+	if !s.config.Sync.WantToReadOwnedOnly || s.config.Sync.SyncOwned {
+		return false
+	}
+
+	owned, checked := s.ownedThisRun(hcBook.ID)
+	if checked {
+		return !owned
+	}
+
+	log := s.log.With(map[string]interface{}{
+		"book_id": hcBook.ID,
+		"title":   hcBook.Title,
+	})
+
+	bookID, err := strconv.Atoi(hcBook.ID)
+	if err != nil {
+		log.Warn("Invalid book ID format for ownership check, not adding to Want to Read", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return true
+	}
+
+	owned, err = s.hardcover.CheckBookOwnership(ctx, bookID)
+	if err != nil {
+		log.Warn("Failed to check book ownership, not adding to Want to Read", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return true
+	}
+
+	s.setOwnedThisRun(hcBook.ID, owned)
+
+	if !owned {
+		log.Info("Book is not owned in Hardcover, not adding to Want to Read", nil)
+	}
+	return !owned
+}
+
+// ownedThisRun returns whether the Hardcover book is owned, if its ownership was checked or set
+// earlier in the run
+func (s *Service) ownedThisRun(bookID string) (owned, checked bool) {
+	s.ownedBooksMutex.Lock()
+	defer s.ownedBooksMutex.Unlock()
+	owned, checked = s.ownedBooksThisRun[bookID]
+	return owned, checked
+}
+
+// setOwnedThisRun records the ownership of the Hardcover book for the rest of the run
+func (s *Service) setOwnedThisRun(bookID string, owned bool) {
+	s.ownedBooksMutex.Lock()
+	defer s.ownedBooksMutex.Unlock()
+	if s.ownedBooksThisRun == nil {
+		s.ownedBooksThisRun = make(map[string]bool)
+	}
+	s.ownedBooksThisRun[bookID] = owned
+}
@@ -0,0 +1,87 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/drallgood/audiobookshelf-hardcover-sync/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessBook_WantToReadOwnedOnly(t *testing.T) {
+	book := models.AudiobookshelfBook{ID: "unstarted-book", LibraryID: "lib1", MediaType: "book"}
+	book.Media.Metadata.Title = "Unstarted Audiobook"
+	book.Media.Metadata.ASIN = "B0UNSTART1"
+	book.Media.Duration = 3600
+
+	svc, mockClient := createTestService()
+	svc.summary = &SyncSummary{}
+	svc.config.Sync.SyncOwned = false
+	svc.config.Sync.SyncWantToRead = true
+	svc.config.Sync.ProcessUnreadBooks = true
+	svc.config.Sync.WantToReadOwnedOnly = true
+
+	mockClient.On("SearchBookByASIN", mock.Anything, "B0UNSTART1").Return(&models.HardcoverBook{ID: "123", EditionID: "456", Title: "Unstarted Audiobook"}, nil)
+	mockClient.On("CheckBookOwnership", mock.Anything, 123).Return(false, nil).Once()
+
+	require.NoError(t, svc.processBook(context.Background(), book, &models.AudiobookshelfUserProgress{}))
+
+	// The book isn't owned, so no user book is looked up or created
+	mockClient.AssertExpectations(t)
+	mockClient.AssertNotCalled(t, "GetUserBookID", mock.Anything, mock.Anything)
+	mockClient.AssertNotCalled(t, "CreateUserBook", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSkipUnownedWantToRead(t *testing.T) {
+	hcBook := &models.HardcoverBook{ID: "123", EditionID: "456"}
+
+	setup := func(t *testing.T) (*Service, *MockHardcoverClient) {
+		svc, mockClient := createTestService()
+		svc.config.Sync.SyncOwned = false
+		svc.config.Sync.WantToReadOwnedOnly = true
+		return svc, mockClient
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		svc, mockClient := setup(t)
+		svc.config.Sync.WantToReadOwnedOnly = false
+
+		assert.False(t, svc.skipUnownedWantToRead(context.Background(), hcBook))
+		mockClient.AssertNotCalled(t, "CheckBookOwnership", mock.Anything, mock.Anything)
+	})
+
+	t.Run("sync_owned marks every book as owned", func(t *testing.T) {
+		svc, mockClient := setup(t)
+		svc.config.Sync.SyncOwned = true
+
+		assert.False(t, svc.skipUnownedWantToRead(context.Background(), hcBook))
+		mockClient.AssertNotCalled(t, "CheckBookOwnership", mock.Anything, mock.Anything)
+	})
+
+	t.Run("owned", func(t *testing.T) {
+		svc, mockClient := setup(t)
+		mockClient.On("CheckBookOwnership", mock.Anything, 123).Return(true, nil).Once()
+
+		assert.False(t, svc.skipUnownedWantToRead(context.Background(), hcBook))
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("not owned", func(t *testing.T) {
+		svc, mockClient := setup(t)
+		mockClient.On("CheckBookOwnership", mock.Anything, 123).Return(false, nil).Once()
+
+		assert.True(t, svc.skipUnownedWantToRead(context.Background(), hcBook))
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("ownership check fails", func(t *testing.T) {
+		svc, mockClient := setup(t)
+		mockClient.On("CheckBookOwnership", mock.Anything, 123).Return(false, errors.New("connection refused")).Once()
+
+		assert.True(t, svc.skipUnownedWantToRead(context.Background(), hcBook))
+		mockClient.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,127 @@
+// Package tracing provides optional OpenTelemetry tracing of sync runs. Spans are started through
+// the global tracer provider, which is a no-op until Setup installs an exporting one, so tracing
+// costs next to nothing when it's disabled.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServiceName is the service.name resource attribute of exported traces
+const ServiceName = "audiobookshelf-hardcover-sync"
+
+// tracerName is the instrumentation scope of every span
+const tracerName = "github.com/drallgood/audiobookshelf-hardcover-sync"
+
+// Setup installs a tracer provider that exports spans over OTLP/HTTP to endpoint, a URL such as
+// "http://localhost:4318". An empty endpoint uses the standard OTEL_EXPORTER_OTLP_* environment
+// variables. The returned function flushes pending spans and must be called before exiting.
+func Setup(ctx context.Context, endpoint, version string) (func(context.Context) error, error) {
+	var opts []otlptracehttp.Option
+	if endpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpointURL(endpoint))
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		semconv.ServiceName(ServiceName),
+		semconv.ServiceVersion(version),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start starts a span as a child of the span in ctx, if any
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End ends the span, marking it failed when err is set. Errors for which expected returns true,
+// such as skipped books, are recorded as an attribute but don't fail the span.
+func End(span trace.Span, err error, expected ...error) {
+	if err != nil {
+		if isExpected(err, expected) {
+			span.SetAttributes(attribute.String("outcome", err.Error()))
+		} else {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}
+	span.End()
+}
+
+// isExpected reports whether err is one of the expected errors
+func isExpected(err error, expected []error) bool {
+	for _, target := range expected {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Transport returns an http.RoundTripper that wraps every request to a service such as
+// "audiobookshelf" in a client span named after the service and method. A nil rt uses
+// http.DefaultTransport.
+func Transport(service string, rt http.RoundTripper) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	return &transport{service: service, rt: rt}
+}
+
+// transport is the http.RoundTripper returned by Transport
+type transport struct {
+	service string
+	rt      http.RoundTripper
+}
+
+// RoundTrip implements the http.RoundTripper interface
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := otel.Tracer(tracerName).Start(req.Context(), t.service+" "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("peer.service", t.service),
+			semconv.HTTPRequestMethodKey.String(req.Method),
+			semconv.ServerAddress(req.URL.Hostname()),
+			semconv.URLPath(req.URL.Path),
+		),
+	)
+	defer span.End()
+
+	resp, err := t.rt.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	return resp, nil
+}
@@ -0,0 +1,89 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans installs a tracer provider that keeps finished spans in memory for the test
+func recordSpans(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return exporter
+}
+
+// spanAttr returns the value of a span attribute, or nil if it isn't set
+func spanAttr(span tracetest.SpanStub, key string) interface{} {
+	for _, attr := range span.Attributes {
+		if string(attr.Key) == key {
+			return attr.Value.AsInterface()
+		}
+	}
+	return nil
+}
+
+func TestTransport(t *testing.T) {
+	exporter := recordSpans(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: Transport("test", nil)}
+	ctx, parent := Start(context.Background(), "parent")
+	for _, path := range []string{"/ok", "/fail"} {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+path, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	parent.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 3)
+	ok, fail := spans[0], spans[1]
+
+	assert.Equal(t, "test GET", ok.Name)
+	assert.Equal(t, parent.SpanContext().SpanID(), ok.Parent.SpanID())
+	assert.Equal(t, "/ok", spanAttr(ok, "url.path"))
+	assert.Equal(t, int64(http.StatusOK), spanAttr(ok, "http.response.status_code"))
+	assert.Equal(t, codes.Unset, ok.Status.Code)
+
+	assert.Equal(t, int64(http.StatusInternalServerError), spanAttr(fail, "http.response.status_code"))
+	assert.Equal(t, codes.Error, fail.Status.Code)
+}
+
+func TestEnd(t *testing.T) {
+	exporter := recordSpans(t)
+	errExpected := errors.New("skipped")
+
+	_, span := Start(context.Background(), "expected")
+	End(span, errExpected, errExpected)
+	_, span = Start(context.Background(), "failed")
+	End(span, errors.New("boom"), errExpected)
+	_, span = Start(context.Background(), "succeeded")
+	End(span, nil)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 3)
+	assert.Equal(t, codes.Unset, spans[0].Status.Code)
+	assert.Equal(t, "skipped", spanAttr(spans[0], "outcome"))
+	assert.Equal(t, codes.Error, spans[1].Status.Code)
+	assert.Len(t, spans[1].Events, 1)
+	assert.Equal(t, codes.Unset, spans[2].Status.Code)
+}
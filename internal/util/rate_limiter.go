@@ -42,6 +42,13 @@ var (
 	DefaultJitterFactor = 0.5
 	// DefaultMaxConcurrent is the default maximum concurrent requests
 	DefaultMaxConcurrent = 3
+	// DefaultTooManyRequestsSlowdown is the factor the time between requests grows by for every
+	// HTTP 429 response
+	DefaultTooManyRequestsSlowdown = 1.5
+	// DefaultRateRecoveryInterval is how long requests go without an HTTP 429 response before the
+	// time between requests shrinks by DefaultTooManyRequestsSlowdown again, back to the
+	// configured rate
+	DefaultRateRecoveryInterval = time.Minute
 )
 
 // RateLimiter implements a token bucket rate limiter with dynamic rate adjustment
@@ -187,6 +194,7 @@ func (r *RateLimiter) Wait(ctx context.Context) error {
 	atomic.AddUint64(&r.metrics.Requests, 1)
 
 	now := time.Now()
+	r.recoverRate(now)
 
 	// Calculate time since last request
 	timeSinceLast := now.Sub(r.last)
@@ -270,6 +278,7 @@ func (r *RateLimiter) OnRateLimit(retryAfter time.Duration) time.Duration {
 
 	// Update rate to the new backoff value
 	r.rate = backoff
+	r.lastRateDrop = now
 
 	// Reset tokens to prevent burst after backoff
 	r.tokens = 1
@@ -289,6 +298,65 @@ func (r *RateLimiter) OnRateLimit(retryAfter time.Duration) time.Duration {
 	return backoff
 }
 
+// OnTooManyRequests records an HTTP 429 response and lowers the rate by
+// DefaultTooManyRequestsSlowdown, up to the maximum time between requests. Unlike OnRateLimit it
+// doesn't start a backoff period, as the caller waits before retrying itself. The rate recovers
+// once requests go without 429 responses, see recoverRate. It returns the new time between
+// requests.
+func (r *RateLimiter) OnTooManyRequests(retryAfter time.Duration) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.metrics.RateLimited++
+	if retryAfter > 0 {
+		r.metrics.RetryAfter++
+	}
+
+	rate := time.Duration(float64(r.rate) * DefaultTooManyRequestsSlowdown)
+	if rate > r.maxRate {
+		rate = r.maxRate
+	}
+	r.rate = rate
+	r.lastRateDrop = time.Now()
+	// Reset tokens to prevent a burst once requests resume
+	r.tokens = 1
+
+	r.logger.Warn("Lowered request rate after too many requests", map[string]interface{}{
+		"retryAfter":  retryAfter.String(),
+		"newRate":     rate.String(),
+		"rateLimited": r.metrics.RateLimited,
+	})
+	return rate
+}
+
+// recoverRate shrinks the time between requests by DefaultTooManyRequestsSlowdown for every
+// DefaultRateRecoveryInterval since the rate was last lowered, down to the configured rate, so a
+// limiter that lives across runs isn't throttled forever by a few rate limited requests.
+// Note: Caller must hold the write lock on r.mu
+func (r *RateLimiter) recoverRate(now time.Time) {
+	if r.rate <= r.minRate {
+		return
+	}
+	steps := int(now.Sub(r.lastRateDrop) / DefaultRateRecoveryInterval)
+	if steps <= 0 {
+		return
+	}
+
+	previous := r.rate
+	for i := 0; i < steps && r.rate > r.minRate; i++ {
+		r.rate = time.Duration(float64(r.rate) / DefaultTooManyRequestsSlowdown)
+	}
+	if r.rate < r.minRate {
+		r.rate = r.minRate
+	}
+	r.lastRateDrop = r.lastRateDrop.Add(time.Duration(steps) * DefaultRateRecoveryInterval)
+
+	r.logger.Debug("Raised request rate after requests without rate limiting", map[string]interface{}{
+		"previousRate": previous.String(),
+		"newRate":      r.rate.String(),
+	})
+}
+
 // ResetRate resets the rate limiter to its default rate and backoff factor
 func (r *RateLimiter) ResetRate() {
 	r.mu.Lock()
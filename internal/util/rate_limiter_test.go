@@ -645,3 +645,54 @@ func TestWithRateLimitHeaders(t *testing.T) {
 		})
 	}
 }
+
+func TestRateLimiter_OnTooManyRequests(t *testing.T) {
+	rl := NewRateLimiter(100*time.Millisecond, 1, 1, nil)
+
+	assert.Equal(t, 150*time.Millisecond, rl.OnTooManyRequests(0))
+	assert.Equal(t, 225*time.Millisecond, rl.OnTooManyRequests(5*time.Second))
+	assert.Equal(t, 225*time.Millisecond, rl.GetRate())
+
+	metrics := rl.GetMetrics()
+	assert.Equal(t, uint64(2), metrics.RateLimited)
+	assert.Equal(t, uint64(1), metrics.RetryAfter)
+
+	// No backoff period is started, the caller waits itself
+	start := time.Now()
+	require.NoError(t, rl.Wait(context.Background()))
+	assert.Less(t, time.Since(start), time.Second)
+
+	// The rate never drops below the maximum time between requests
+	for i := 0; i < 50; i++ {
+		rl.OnTooManyRequests(0)
+	}
+	assert.Equal(t, 10*time.Minute, rl.GetRate())
+}
+
+func TestRateLimiter_RecoversFromTooManyRequests(t *testing.T) {
+	rl := NewRateLimiter(100*time.Millisecond, 1, 1, nil)
+	for i := 0; i < 4; i++ {
+		rl.OnTooManyRequests(0)
+	}
+	slowed := rl.GetRate()
+	require.Greater(t, slowed, 500*time.Millisecond)
+
+	// Nothing recovers before the recovery interval passes
+	rl.mu.Lock()
+	rl.recoverRate(rl.lastRateDrop.Add(DefaultRateRecoveryInterval / 2))
+	rl.mu.Unlock()
+	assert.Equal(t, slowed, rl.GetRate())
+
+	// Every interval without a 429 undoes one slowdown
+	rl.mu.Lock()
+	rl.recoverRate(rl.lastRateDrop.Add(DefaultRateRecoveryInterval))
+	rl.mu.Unlock()
+	assert.Equal(t, time.Duration(float64(slowed)/DefaultTooManyRequestsSlowdown), rl.GetRate())
+
+	// A long quiet period, e.g. between runs, goes back to the configured rate, never below it
+	rl.mu.Lock()
+	rl.lastRateDrop = time.Now().Add(-24 * time.Hour)
+	rl.mu.Unlock()
+	require.NoError(t, rl.Wait(context.Background()))
+	assert.Equal(t, 100*time.Millisecond, rl.GetRate())
+}
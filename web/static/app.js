@@ -413,6 +413,7 @@ class SyncProfileApp {
                         <span class="status-badge ${statusClass}" title="${user.active ? 'Active' : 'Inactive'}">
                             ${statusIcon} ${user.active ? 'Active' : 'Inactive'}
                         </span>
+                        ${user.dry_run ? '<span class="status-badge inactive" title="Syncs run without making changes in Hardcover">Preview</span>' : ''}
                     </div>
                     
                     <div class="user-card-body">
@@ -1941,6 +1942,10 @@ class SyncProfileApp {
         if (includeEbooksEl) {
             includeEbooksEl.checked = this.toBool(config.include_ebooks, false);
         }
+        const dryRunEl = document.getElementById('edit-dry-run');
+        if (dryRunEl) {
+            dryRunEl.checked = this.toBool(user.profile.dry_run, false);
+        }
         
         // Library filters
         const libraries = config.libraries || {};
@@ -1964,9 +1969,10 @@ class SyncProfileApp {
         const formData = new FormData(event.target);
         const userId = formData.get('id');
         
-        // Update user name
+        // Update user name and read-only preview mode
         const userUpdateData = {
-            name: formData.get('name')
+            name: formData.get('name'),
+            dry_run: formData.get('dry_run') === 'on'
         };
 
         // Update user config with form data